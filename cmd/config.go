@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/config"
+)
+
+// loadConfig exits with the list of the invalid values when the config cannot be loaded
+func loadConfig(args []string) config.Config {
+	cfg, err := config.Load(args, os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "Usage: maple [flags]\n       maple config print [flags]\n\nFlags:\n%s", config.Usage())
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "  - %s\n", line)
+		}
+		os.Exit(2)
+	}
+
+	return cfg
+}
+
+// configCommand runs the config subcommands, print writes the effective config with the
+// secrets masked
+func configCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintf(os.Stderr, "Usage: maple config print [flags]\n")
+		return 2
+	}
+
+	cfg := loadConfig(args[1:])
+	if err := config.Print(os.Stdout, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "printing the config: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
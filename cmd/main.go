@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,94 +10,392 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ctfrancia/maple/internal/adapters/chatexport"
+	"github.com/ctfrancia/maple/internal/adapters/fide"
+	"github.com/ctfrancia/maple/internal/adapters/geocoding"
 	rest "github.com/ctfrancia/maple/internal/adapters/http"
+	"github.com/ctfrancia/maple/internal/adapters/http/live"
 	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/adapters/metrics"
+	"github.com/ctfrancia/maple/internal/adapters/notifier"
 	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	"github.com/ctfrancia/maple/internal/adapters/persistence/sqlstore"
+	"github.com/ctfrancia/maple/internal/adapters/security"
 	"github.com/ctfrancia/maple/internal/adapters/system"
+	"github.com/ctfrancia/maple/internal/adapters/tracing"
 	"github.com/ctfrancia/maple/internal/application/services"
+	"github.com/ctfrancia/maple/internal/core/domain"
 	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/ctfrancia/maple/internal/infrastructure"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 var (
-	env                  = os.Getenv("ENV")
-	listenAddress        = os.Getenv("LISTEN_ADDRESS")
-	workerCount          = os.Getenv("WORKER_COUNT")
-	sigServiceTimeout    = os.Getenv("SIG_SERVICE_TIMEOUT")
-	readTimeout          = os.Getenv("READ_TIMEOUT")
-	writeTimeout         = os.Getenv("WRITE_TIMEOUT")
-	idleTimeout          = os.Getenv("IDLE_TIMEOUT")
 	log                  ports.Logger
 	tournamentRepository ports.TournamentRepository
 	repoProvider         ports.TournamentRepositoryProvider
+	playerProvider       ports.PlayerRepositoryProvider
+	matchProvider        ports.MatchRepositoryProvider
+	ratingProvider       ports.RatingRepositoryProvider
+	fideProvider         ports.FideRepositoryProvider
+	outboxProvider       ports.OutboxRepositoryProvider
+	webhookProvider      ports.WebhookRepositoryProvider
+	notificationProvider ports.NotificationRepositoryProvider
+	relayProvider        ports.RelayRepositoryProvider
+	challengeProvider    ports.ChallengeRepositoryProvider
+	announcementProvider ports.AnnouncementRepositoryProvider
+	moderationProvider   ports.ModerationRepositoryProvider
+	locationProvider     ports.LocationRepositoryProvider
 )
 
 func main() {
-	var rt, wt, it time.Duration
-	if listenAddress == "" {
-		panic("LISTEN_ADDRESS is not set")
-	}
-	if workerCount == "" {
-		panic("WORKER_COUNT is not set")
-	}
-	if sigServiceTimeout == "" {
-		panic("SIG_SERVICE_TIMEOUT is not set")
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "config" {
+		os.Exit(configCommand(args[1:]))
 	}
 
-	// Parse timeout first
-	timeout, err := time.ParseDuration(sigServiceTimeout)
-	if err != nil {
-		panic(err)
+	// defaults < config file < environment variables < flags, every invalid value is reported at once
+	cfg := loadConfig(args)
+
+	poolConfig := services.WorkerPoolConfig{
+		Workers:       cfg.Workers.Count,
+		QueueSize:     cfg.Workers.QueueSize,
+		SubmitTimeout: cfg.Workers.SubmitTimeout,
+		TaskTimeout:   cfg.Workers.TaskTimeout,
 	}
 
 	// Create main application context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// environment specific setup
-	switch env {
+	// the metrics are scraped from /metrics
+	registry := metrics.NewRegistry()
+
+	// environment specific setup, the level of the logger can be changed at /v1/admin/log-level
+	zapLogger := logger.New(logger.Config{Env: cfg.Env, Level: cfg.Log.Level, SampleInitial: cfg.Log.SampleInitial, SampleThereafter: cfg.Log.SampleThereafter})
+	switch cfg.Env {
 	case "prod":
 		fmt.Println("using production environment")
-		log = logger.NewZapLogger(env)
 	case "dev", "test":
 		fmt.Println("using dev|test environment")
-		log = logger.NewZapLogger(env)
 		tournamentRepository = inmemory.NewInMemoryTournamentRepository()
-		repoProvider = inmemory.NewTournamentRepositoryProvider(tournamentRepository)
-		rt = 15 * time.Second
-		wt = 15 * time.Second
-		it = 60 * time.Second
-	default:
-		log = logger.NewZapLogger("dev")
-		fmt.Println("reached default using dev logger")
+		outboxProvider = inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+		repoProvider = inmemory.NewTournamentRepositoryProvider(tournamentRepository, outboxProvider)
+		playerProvider = inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+		matchProvider = inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), outboxProvider)
+		ratingProvider = inmemory.NewRatingRepositoryProvider(inmemory.NewInMemoryRatingRepository())
+		fideProvider = inmemory.NewFideRepositoryProvider(inmemory.NewInMemoryFideRepository())
+		webhookProvider = inmemory.NewWebhookRepositoryProvider(inmemory.NewInMemoryWebhookRepository())
+		notificationProvider = inmemory.NewNotificationRepositoryProvider(inmemory.NewInMemoryNotificationRepository())
+		relayProvider = inmemory.NewRelayRepositoryProvider(inmemory.NewInMemoryRelayRepository())
+		challengeProvider = inmemory.NewChallengeRepositoryProvider(inmemory.NewInMemoryChallengeRepository(), outboxProvider)
+		announcementProvider = inmemory.NewAnnouncementRepositoryProvider(inmemory.NewInMemoryAnnouncementRepository())
+		moderationProvider = inmemory.NewModerationRepositoryProvider(inmemory.NewInMemoryModerationRepository())
+		locationProvider = inmemory.NewLocationRepositoryProvider(inmemory.NewInMemoryLocationRepository())
+		// the lock times of the repositories are measured by wrapping their providers
+		repoProvider = inmemory.Instrument(registry, "tournaments", repoProvider)
+		playerProvider = inmemory.Instrument(registry, "players", playerProvider)
+		matchProvider = inmemory.Instrument(registry, "matches", matchProvider)
+		ratingProvider = inmemory.Instrument(registry, "ratings", ratingProvider)
+		fideProvider = inmemory.Instrument(registry, "fide", fideProvider)
+		outboxProvider = inmemory.Instrument(registry, "outbox", outboxProvider)
+		webhookProvider = inmemory.Instrument(registry, "webhooks", webhookProvider)
+		notificationProvider = inmemory.Instrument(registry, "notifications", notificationProvider)
+		relayProvider = inmemory.Instrument(registry, "relays", relayProvider)
+		challengeProvider = inmemory.Instrument(registry, "challenges", challengeProvider)
+		announcementProvider = inmemory.Instrument(registry, "announcements", announcementProvider)
+		moderationProvider = inmemory.Instrument(registry, "moderation", moderationProvider)
+		locationProvider = inmemory.Instrument(registry, "locations", locationProvider)
 	}
+	log = zapLogger
 
 	log.Info(context.Background(), "Starting server")
 
+	// requests are traced when spans have somewhere to go: an OTLP collector, or TRACES_FILE with - for stdout
+	var tracer *tracing.Tracer
+	tracingConfig := tracing.DefaultConfig()
+	tracingConfig.ServiceName = cfg.Tracing.ServiceName
+	tracingConfig.SampleRatio = cfg.Tracing.SampleRatio
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch {
+	case cfg.Tracing.OTLPEndpoint != "":
+		spanExporter, err = tracing.NewOTLPExporter(context.Background(), cfg.Tracing.OTLPEndpoint, cfg.Tracing.OTLPHeaders)
+	case cfg.Tracing.File == "-":
+		spanExporter, err = tracing.NewWriterExporter(os.Stdout)
+	case cfg.Tracing.File != "":
+		file, fileErr := os.OpenFile(cfg.Tracing.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if fileErr != nil {
+			log.Error(context.Background(), "Traces file creation failed", ports.Error("error", fileErr))
+			os.Exit(1)
+		}
+		defer file.Close()
+		spanExporter, err = tracing.NewWriterExporter(file)
+	}
+	if err != nil {
+		log.Error(context.Background(), "Spans exporter creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	if spanExporter != nil {
+		tracer = tracing.NewTracer(log, spanExporter, tracingConfig)
+	}
+	var routerTracer ports.Tracer
+	if tracer != nil {
+		// the background work started with the main context is traced too
+		ctx = ports.WithTracer(ctx, tracer)
+		routerTracer = tracer
+		defer func() {
+			flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer flushCancel()
+			if err := tracer.Shutdown(flushCtx); err != nil {
+				log.Error(context.Background(), "Spans export on shutdown failed", ports.Error("error", err))
+			}
+		}()
+	}
+
 	// Adapters
 	sa := system.NewSystemAdapter()
 
+	// the probes of /livez and /readyz, the adapters register theirs as they are created
+	health := services.NewHealthRegistry(sa)
+	if repoProvider != nil {
+		for _, check := range []ports.HealthCheckConfig{
+			{Name: "repository.tournaments", Probe: inmemory.Probe(repoProvider)},
+			{Name: "repository.players", Probe: inmemory.Probe(playerProvider)},
+			{Name: "repository.matches", Probe: inmemory.Probe(matchProvider)},
+			{Name: "repository.ratings", Probe: inmemory.Probe(ratingProvider)},
+			{Name: "repository.fide", Probe: inmemory.Probe(fideProvider)},
+			{Name: "repository.outbox", Probe: inmemory.Probe(outboxProvider)},
+			{Name: "repository.webhooks", Probe: inmemory.Probe(webhookProvider)},
+			{Name: "repository.notifications", Probe: inmemory.Probe(notificationProvider)},
+			{Name: "repository.relays", Probe: inmemory.Probe(relayProvider)},
+			{Name: "repository.challenges", Probe: inmemory.Probe(challengeProvider)},
+			{Name: "repository.announcements", Probe: inmemory.Probe(announcementProvider)},
+			{Name: "repository.moderation", Probe: inmemory.Probe(moderationProvider)},
+			{Name: "repository.locations", Probe: inmemory.Probe(locationProvider)},
+		} {
+			check.Timeout = time.Second
+			health.Register(check)
+		}
+	}
+
 	// Services - use the main context
-	wp := services.NewTournamentWorkerPool(ctx, cancel)
+	wp := services.NewWorkerPool(ctx, log, registry, poolConfig)
 	wp.Start()
 	defer wp.Stop()
-	shs := services.NewSystemHealthServicer(sa, nil, nil)
+	health.Register(ports.HealthCheckConfig{Name: "worker_pool", Probe: wp.Ping, Liveness: true})
+	shs := services.NewSystemHealthServicer(sa, nil, security.NewSecurityAdapter(cfg.Security.Argon2.Params()), health)
+
+	// reports and the moderators' queue, MODERATION_APPROVAL=new|all holds the listings of consumers for approval
+	moderationConfig := services.DefaultModerationConfig()
+	moderationConfig.Approval = cfg.Moderation.Approval
+	mods := services.NewModerationServicer(log, moderationProvider, repoProvider, matchProvider, playerProvider, challengeProvider, moderationConfig)
 
-	ts, err := services.NewTournamentServicer(log, repoProvider, wp)
+	ts, err := services.NewTournamentServicer(log, repoProvider, matchProvider, playerProvider, locationProvider, wp, mods)
 	if err != nil {
 		log.Error(context.Background(), "Tournament service creation failed", ports.Error("error", err))
 		os.Exit(1)
 	}
 
+	ps, err := services.NewPlayerServicer(log, playerProvider, matchProvider)
+	if err != nil {
+		log.Error(context.Background(), "Player service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	// maple rating engine, elo unless glicko-2 is asked for
+	elo, g2 := cfg.Rating.Elo, cfg.Rating.Glicko2
+	calculator := services.NewEloCalculator(services.EloConfig{
+		InitialRating:    elo.InitialRating,
+		ProvisionalGames: elo.ProvisionalGames,
+		ProvisionalK:     elo.ProvisionalK,
+		K:                elo.K,
+		MasterK:          elo.MasterK,
+		MasterThreshold:  elo.MasterThreshold,
+		Floor:            elo.Floor,
+	})
+	if cfg.Rating.Algorithm == domain.RatingAlgorithmGlicko2 {
+		calculator = services.NewGlicko2Calculator(services.Glicko2Config{
+			InitialRating:        g2.InitialRating,
+			InitialDeviation:     g2.InitialDeviation,
+			InitialVolatility:    g2.InitialVolatility,
+			Tau:                  g2.Tau,
+			ProvisionalDeviation: g2.ProvisionalDeviation,
+		})
+	}
+	rs, err := services.NewRatingServicer(log, calculator, ratingProvider, playerProvider, matchProvider)
+	if err != nil {
+		log.Error(context.Background(), "Rating service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	ms, err := services.NewMatchServicer(log, matchProvider, playerProvider, repoProvider, locationProvider)
+	if err != nil {
+		log.Error(context.Background(), "Match service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	fs, err := services.NewFideServicer(log, fide.NewListReader(cfg.Imports.Dir), fideProvider, playerProvider, repoProvider, wp)
+	if err != nil {
+		log.Error(context.Background(), "Fide service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	// webhook urls are absolute so the client has no base url
+	ws := services.NewWebhookServicer(log, webhookProvider, infrastructure.NewPublicHTTPClientAdapter(15*time.Second), services.DefaultWebhookConfig())
+	ws.Start(ctx)
+	defer ws.Stop()
+
+	// emails go through smtp when it is configured, to MAIL_DIR or the console otherwise
+	var mailer ports.Notifier
+	switch smtp := cfg.Mail.SMTP; {
+	case smtp.Host != "":
+		mailer = notifier.NewSMTPNotifier(notifier.SMTPConfig{Host: smtp.Host, Port: smtp.Port, Username: smtp.Username, Password: smtp.Password, From: cfg.Mail.From, Timeout: smtp.Timeout})
+	case cfg.Mail.Dir != "":
+		mailer, err = notifier.NewFileNotifier(cfg.Mail.Dir, cfg.Mail.From)
+		if err != nil {
+			log.Error(context.Background(), "Mail directory creation failed", ports.Error("error", err))
+			os.Exit(1)
+		}
+	default:
+		mailer = notifier.NewConsoleNotifier(os.Stdout, cfg.Mail.From)
+	}
+	if pinger, ok := mailer.(ports.Pinger); ok {
+		health.Register(ports.HealthCheckConfig{Name: "smtp", Probe: pinger.Ping, Timeout: 5 * time.Second})
+	}
+	notificationConfig := services.DefaultNotificationConfig()
+	if cfg.Server.PublicURL != "" {
+		notificationConfig.BaseURL = cfg.Server.PublicURL
+	}
+	ns := services.NewNotificationServicer(log, notificationProvider, playerProvider, repoProvider, challengeProvider, mailer, notificationConfig)
+	ns.Start(ctx)
+	defer ns.Stop()
+
+	// domain events recorded in the outbox are delivered to the subscribers in the background
+	dispatcher := services.NewEventDispatcher(log, outboxProvider, services.DefaultEventDispatcherConfig())
+	if err := dispatcher.Subscribe("event_log", func(ctx context.Context, event domain.Event) error {
+		log.Debug(ctx, "domain event", ports.String("event", string(event.EventType())), ports.String("aggregate_id", event.AggregateID().String()))
+		return nil
+	}); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	if err := dispatcher.Subscribe("metrics", services.NewEventMetrics(registry).HandleEvent); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	if err := dispatcher.Subscribe("webhooks", ws.HandleEvent); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	if err := dispatcher.Subscribe("notifications", ns.HandleEvent, domain.EventPlayerRegistered, domain.EventRoundPaired, domain.EventChallengeAccepted); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	// the ratings follow the results, an update that fails is retried
+	if err := dispatcher.Subscribe("ratings", rs.HandleEvent, domain.EventResultRecorded); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	// spectators of the tournaments open to them follow the events live
+	hub := live.NewHub(live.DefaultHubConfig())
+	liveFeed := services.NewLiveFeed(log, repoProvider, matchProvider, hub)
+	if err := dispatcher.Subscribe("live", liveFeed.HandleEvent, domain.EventPlayerRegistered, domain.EventRoundPaired, domain.EventResultRecorded, domain.EventTournamentCompleted); err != nil {
+		log.Error(context.Background(), "Event subscription failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+	dispatcher.Start(ctx)
+	defer dispatcher.Stop()
+
+	// the broadcast boards relay their games move by move to the same live feed
+	rls, err := services.NewRelayServicer(log, relayProvider, matchProvider, playerProvider, repoProvider, ms, hub)
+	if err != nil {
+		log.Error(context.Background(), "Relay service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	// players post open challenges for casual games, the ones nobody accepts expire
+	cs := services.NewChallengeServicer(log, challengeProvider, playerProvider, matchProvider, rs, mods, services.DefaultChallengeConfig())
+	cs.Start(ctx)
+	defer cs.Stop()
+
+	// announcements of events read from exported whatsapp and telegram chats, reviewed before becoming drafts
+	as, err := services.NewAnnouncementServicer(log, chatexport.NewExportReader(cfg.Imports.Dir), announcementProvider, repoProvider, wp)
+	if err != nil {
+		log.Error(context.Background(), "Announcement service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	// venues are geocoded offline with the geonames dumps of GEONAMES_DIR, or with nominatim
+	var geocoder ports.Geocoder
+	switch {
+	case cfg.Geocoding.GeonamesDir != "":
+		gazetteer, err := geocoding.LoadGazetteer(ctx, geocoding.GazetteerDir(cfg.Geocoding.GeonamesDir, cfg.Geocoding.GeonamesCountry))
+		if err != nil {
+			log.Error(context.Background(), "Gazetteer loading failed", ports.Error("error", err))
+			os.Exit(1)
+		}
+		geocoder = gazetteer
+	case cfg.Geocoding.NominatimURL != "":
+		// the public instance asks for a user agent it can reach the operator with
+		userAgent := "maple"
+		if cfg.Server.PublicURL != "" {
+			userAgent += " (" + cfg.Server.PublicURL + ")"
+		}
+		geocoder = geocoding.NewNominatim(infrastructure.NewHTTPClientAdapter(cfg.Geocoding.NominatimURL, 10*time.Second), userAgent)
+	}
+	if pinger, ok := geocoder.(ports.Pinger); ok {
+		health.Register(ports.HealthCheckConfig{Name: "geocoder", Probe: pinger.Ping, Timeout: 5 * time.Second})
+	}
+
+	// the registry of venues the tournaments and matches are linked to
+	ls, err := services.NewLocationServicer(log, locationProvider, repoProvider, matchProvider, geocoder)
+	if err != nil {
+		log.Error(context.Background(), "Location service creation failed", ports.Error("error", err))
+		os.Exit(1)
+	}
+
+	// the retries of a POST with an Idempotency-Key are replayed, the keys are shared by the
+	// instances when they are kept in IDEMPOTENCY_SQL_DSN
+	idempotency := inmemory.NewInMemoryIdempotencyStore()
+	if cfg.Idempotency.SQLDriver != "" {
+		db, err := sql.Open(cfg.Idempotency.SQLDriver, cfg.Idempotency.SQLDSN)
+		if err != nil {
+			log.Error(context.Background(), "Idempotency database opening failed", ports.Error("error", err))
+			os.Exit(1)
+		}
+		defer db.Close()
+		idempotency, err = sqlstore.NewSQLIdempotencyStore(ctx, db)
+		if err != nil {
+			log.Error(context.Background(), "Idempotency store creation failed", ports.Error("error", err))
+			os.Exit(1)
+		}
+	}
+	if pinger, ok := idempotency.(ports.Pinger); ok {
+		health.Register(ports.HealthCheckConfig{Name: "idempotency", Probe: pinger.Ping, Timeout: 5 * time.Second})
+	}
+
+	// the consumers are who their bearer token says, never what the request claims
+	auth := security.NewTokenAuthenticator(cfg.Admin.Token, map[domain.Role]map[string]string{
+		domain.RoleConsumer:  cfg.Auth.ConsumerTokens,
+		domain.RoleModerator: cfg.Auth.ModeratorTokens,
+		domain.RolePlayer:    cfg.Auth.PlayerTokens,
+		domain.RoleArbiter:   cfg.Auth.ArbiterTokens,
+	})
+
 	// Create a new router
 	// TODO: this will be moved to server.go file
-	router := rest.NewRouter(log, shs, ts)
+	router := rest.NewRouter(log, shs, ts, ps, ms, rs, fs, ws, ns, rls, cs, as, mods, ls, hub, registry, routerTracer, zapLogger, idempotency, cfg.Idempotency.TTL, cfg.Admin.Token, auth)
 	srv := &http.Server{
-		Addr:         listenAddress,
+		Addr:         cfg.Server.ListenAddress,
 		Handler:      router,
-		ReadTimeout:  rt,
-		WriteTimeout: wt,
-		IdleTimeout:  it,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	// Shutdown does not wait for the live streams, closing them lets their handlers return
+	srv.RegisterOnShutdown(hub.Shutdown)
 
 	// Channel to listen for interrupt signal to trigger shutdown
 	quit := make(chan os.Signal, 1)
@@ -104,7 +403,7 @@ func main() {
 
 	// Start server in a goroutine so it doesn't block
 	go func() {
-		log.Info(context.Background(), fmt.Sprintf("Server starting on %s", listenAddress))
+		log.Info(context.Background(), fmt.Sprintf("Server starting on %s", cfg.Server.ListenAddress))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error(context.Background(), "Failed to start server", ports.Error("error", err))
 			cancel()
@@ -124,8 +423,8 @@ func main() {
 	// Cancel the main context to signal all services to stop
 	cancel()
 
-	// Creates a deadline for shutdown (use the parsed timeout)
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
+	// Creates a deadline for shutdown
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
 	// Attempt graceful shutdown
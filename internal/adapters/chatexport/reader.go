@@ -0,0 +1,86 @@
+// Package chatexport provides readers for the files whatsapp and telegram write when
+// a chat is exported, the messages are read as they are and left for the caller to interpret
+package chatexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/importdir"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// ExportReader reads chat export files from the import directory, the paths are relative to it
+type ExportReader struct {
+	dir string
+}
+
+func NewExportReader(dir string) ports.ChatExportReader {
+	return &ExportReader{dir: dir}
+}
+
+// Read parses the whole file, exports are small enough to be held in memory
+func (er *ExportReader) Read(ctx context.Context, file domain.ChatExportFile) (domain.ChatExport, error) {
+	format := file.Format
+	if format == "" {
+		format = formatFromPath(file.Path)
+	}
+	loc := file.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	f, err := importdir.Open(er.dir, file.Path)
+	if err != nil {
+		return domain.ChatExport{}, fmt.Errorf("error opening chat export: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return domain.ChatExport{}, fmt.Errorf("error reading chat export: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return domain.ChatExport{}, err
+	}
+
+	var export domain.ChatExport
+	switch format {
+	case domain.ChatExportWhatsApp:
+		export, err = readWhatsApp(string(data), loc)
+		export.Chat = whatsappChatName(file.Path)
+	case domain.ChatExportTelegram:
+		export, err = readTelegram(data, loc)
+	default:
+		return domain.ChatExport{}, domain.ErrChatExportFormat
+	}
+	if err != nil {
+		return domain.ChatExport{}, err
+	}
+
+	export.Format = format
+	return export, nil
+}
+
+// formatFromPath tells the format by the extension whatsapp and telegram give the files
+func formatFromPath(path string) domain.ChatExportFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		return domain.ChatExportWhatsApp
+	case ".json":
+		return domain.ChatExportTelegram
+	}
+	return ""
+}
+
+// invisibles are the marks whatsapp sprinkles over the export and the spaces it uses
+// before am and pm
+var invisibles = strings.NewReplacer("\u200e", "", "\u200f", "", "\ufeff", "", "\u202f", " ", "\u00a0", " ", "\r", "")
+
+func clean(s string) string {
+	return invisibles.Replace(s)
+}
@@ -0,0 +1,111 @@
+package chatexport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const androidTXT = "12/09/26, 19:02 - Els missatges i les trucades estan xifrats d'extrem a extrem.\n" +
+	"12/09/26, 19:05 - Núria: Open d'escacs el 12 d'octubre!\n" +
+	"Lloc: Ateneu Gracienc\n" +
+	"\n" +
+	"Inscripció 12€\n" +
+	"13/09/26, 9:30 - Pau: <Multimèdia omès>\n" +
+	"13/09/26, 21:15 - Pau: Hi serem\n"
+
+const iosTXT = "‎[9/13/26, 9:30:12 PM] Marta: Torneo blitz el sábado\n" +
+	"[9/13/26, 12:01:00 AM] Marta: ‎image omitted\n" +
+	"[9/14/26, 8:00:00 AM] Jordi: Apuntat\n"
+
+const telegramJSON = `{
+ "name": "Escacs BCN",
+ "type": "public_supergroup",
+ "messages": [
+  {"id": 1, "type": "service", "date": "2026-09-12T19:00:00", "actor": "Núria", "action": "create_group", "text": ""},
+  {"id": 2, "type": "message", "date": "2026-09-12T19:05:00", "date_unixtime": "1789232700", "from": "Núria",
+   "text": [{"type": "bold", "text": "Open d'escacs"}, " el 12 d'octubre\nLloc: ", {"type": "link", "text": "Ateneu Gracienc"}]},
+  {"id": 3, "type": "message", "date": "2026-09-12T21:15:00", "from": "Pau", "text": "Hi serem"},
+  {"id": 4, "type": "message", "date": "2026-09-12T21:16:00", "from": "Pau", "photo": "photos/1.jpg", "text": ""}
+ ]
+}`
+
+func writeExport(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRead_WhatsAppAndroid(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	path := writeExport(t, "Xat de WhatsApp amb Escacs BCN.txt", androidTXT)
+
+	export, err := NewExportReader(filepath.Dir(path)).Read(context.Background(), domain.ChatExportFile{Path: filepath.Base(path), Location: madrid})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.ChatExportWhatsApp, export.Format)
+	assert.Equal(t, "Escacs BCN", export.Chat)
+	require.Len(t, export.Messages, 2, "the system and media messages are skipped")
+
+	assert.Equal(t, "Núria", export.Messages[0].Author)
+	assert.Equal(t, "Open d'escacs el 12 d'octubre!\nLloc: Ateneu Gracienc\n\nInscripció 12€", export.Messages[0].Text)
+	assert.True(t, export.Messages[0].SentAt.Equal(time.Date(2026, 9, 12, 19, 5, 0, 0, madrid)))
+	assert.Equal(t, "Hi serem", export.Messages[1].Text)
+}
+
+func TestRead_WhatsAppIOS(t *testing.T) {
+	path := writeExport(t, "_chat.txt", iosTXT)
+
+	export, err := NewExportReader(filepath.Dir(path)).Read(context.Background(), domain.ChatExportFile{Path: filepath.Base(path)})
+	require.NoError(t, err)
+
+	assert.Empty(t, export.Chat)
+	require.Len(t, export.Messages, 2)
+	// the month comes first as 13 cannot be one
+	assert.Equal(t, time.Date(2026, 9, 13, 21, 30, 12, 0, time.UTC), export.Messages[0].SentAt)
+	assert.Equal(t, "Torneo blitz el sábado", export.Messages[0].Text)
+	assert.Equal(t, time.Date(2026, 9, 14, 8, 0, 0, 0, time.UTC), export.Messages[1].SentAt)
+}
+
+func TestRead_Telegram(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+	path := writeExport(t, "result.json", telegramJSON)
+
+	export, err := NewExportReader(filepath.Dir(path)).Read(context.Background(), domain.ChatExportFile{Path: filepath.Base(path), Location: madrid})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.ChatExportTelegram, export.Format)
+	assert.Equal(t, "Escacs BCN", export.Chat)
+	require.Len(t, export.Messages, 2, "the service message and the photo without caption are skipped")
+
+	assert.Equal(t, "Open d'escacs el 12 d'octubre\nLloc: Ateneu Gracienc", export.Messages[0].Text)
+	assert.Equal(t, int64(1789232700), export.Messages[0].SentAt.Unix())
+	assert.True(t, export.Messages[1].SentAt.Equal(time.Date(2026, 9, 12, 21, 15, 0, 0, madrid)), "older exports only have the local date")
+}
+
+func TestRead_UnknownFormat(t *testing.T) {
+	path := writeExport(t, "chat.csv", "date,author,text\n")
+
+	_, err := NewExportReader(filepath.Dir(path)).Read(context.Background(), domain.ChatExportFile{Path: filepath.Base(path)})
+	assert.ErrorIs(t, err, domain.ErrChatExportFormat)
+
+	path = writeExport(t, "notes.txt", "just some notes\n")
+	_, err = NewExportReader(filepath.Dir(path)).Read(context.Background(), domain.ChatExportFile{Path: filepath.Base(path)})
+	assert.ErrorIs(t, err, domain.ErrChatExportFormat, "a text file without messages is not a whatsapp export")
+}
+
+func TestRead_OutsideTheImportDirectory(t *testing.T) {
+	path := writeExport(t, "chat.txt", androidTXT)
+
+	_, err := NewExportReader(t.TempDir()).Read(context.Background(), domain.ChatExportFile{Path: path})
+	assert.ErrorIs(t, err, domain.ErrImportFileNotFound)
+}
@@ -0,0 +1,116 @@
+package chatexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// telegramChat is a chat of the result.json telegram desktop writes, either the whole
+// file when a single chat is exported or one of chats.list when all of them are
+type telegramChat struct {
+	Name     string            `json:"name"`
+	Messages []telegramMessage `json:"messages"`
+	Chats    *struct {
+		List []telegramChat `json:"list"`
+	} `json:"chats"`
+}
+
+type telegramMessage struct {
+	Type         string          `json:"type"` // message or service
+	Date         string          `json:"date"` // local time of the exporting computer
+	DateUnixtime string          `json:"date_unixtime"`
+	From         string          `json:"from"`
+	Text         json.RawMessage `json:"text"`
+}
+
+func readTelegram(data []byte, loc *time.Location) (domain.ChatExport, error) {
+	var chat telegramChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return domain.ChatExport{}, fmt.Errorf("%w: %v", domain.ErrChatExportFormat, err)
+	}
+
+	chats := []telegramChat{chat}
+	if chat.Chats != nil {
+		chats = chat.Chats.List
+	}
+
+	export := domain.ChatExport{Messages: make([]domain.ChatMessage, 0)}
+	for _, c := range chats {
+		if export.Chat == "" {
+			export.Chat = c.Name
+		}
+		for _, m := range c.Messages {
+			if m.Type != "message" {
+				continue
+			}
+			text := strings.TrimSpace(clean(telegramText(m.Text)))
+			if text == "" {
+				// photos and files without a caption
+				continue
+			}
+			author := m.From
+			if author == "" {
+				// posts of a channel
+				author = c.Name
+			}
+
+			export.Messages = append(export.Messages, domain.ChatMessage{
+				Author: author,
+				SentAt: m.time(loc),
+				Text:   text,
+			})
+		}
+	}
+	if len(chats) > 1 {
+		export.Chat = ""
+	}
+
+	return export, nil
+}
+
+// telegramText flattens the text of a message, a plain string or a list of strings
+// and entities such as {"type": "bold", "text": "Open"}
+func telegramText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, part := range parts {
+		var s string
+		if err := json.Unmarshal(part, &s); err == nil {
+			b.WriteString(s)
+			continue
+		}
+		var entity struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(part, &entity); err == nil {
+			b.WriteString(entity.Text)
+		}
+	}
+	return b.String()
+}
+
+// time prefers the unix time of newer exports, older ones only have the local date
+func (m telegramMessage) time(loc *time.Location) time.Time {
+	if unix, err := strconv.ParseInt(m.DateUnixtime, 10, 64); err == nil {
+		return time.Unix(unix, 0).In(loc)
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", m.Date, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,164 @@
+package chatexport
+
+import (
+	"bufio"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// whatsappLineRX matches the line a message starts on, android writes
+// "12/10/26, 19:02 - Author: text" and ios "[12/10/26, 19:02:11] Author: text". The
+// order of the day and month and the 12 hour clock follow the phone's settings
+var whatsappLineRX = regexp.MustCompile(`^\[?(\d{1,2})[/.](\d{1,2})[/.](\d{2,4}),? (\d{1,2}):(\d{2})(?::(\d{2}))?(?: ?([aApP])\.? ?[mM]\.?)?(?:\] | - )(.*)$`)
+
+// whatsappFilePrefixes are how the file of an exported chat is named before the name of the chat
+var whatsappFilePrefixes = []string{"WhatsApp Chat with ", "Chat de WhatsApp con ", "Xat de WhatsApp amb ", "WhatsApp Chat - "}
+
+// whatsappSkipped are the texts whatsapp writes in place of what was not exported
+var whatsappSkipped = []string{
+	"this message was deleted", "you deleted this message",
+	"se eliminó este mensaje", "eliminaste este mensaje",
+	"aquest missatge s'ha suprimit", "has suprimit aquest missatge",
+	"image omitted", "video omitted", "audio omitted", "sticker omitted", "document omitted",
+	"imagen omitida", "video omitido", "audio omitido", "sticker omitido", "documento omitido",
+	"imatge omesa", "vídeo omès", "àudio omès", "adhesiu omès", "document omès",
+}
+
+// whatsappHeader is the start of a message before its date is known to be day or month first
+type whatsappHeader struct {
+	first, second, year int
+	hour, minute, sec   int
+	pm, am              bool
+	body                string
+}
+
+func readWhatsApp(data string, loc *time.Location) (domain.ChatExport, error) {
+	type entry struct {
+		header *whatsappHeader
+		lines  []string
+	}
+
+	var entries []entry
+	dayFirst, monthFirst := false, false
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := clean(scanner.Text())
+		m := whatsappLineRX.FindStringSubmatch(line)
+		if m == nil {
+			// the continuation of a multiline message, lines before the first message are dropped
+			if len(entries) > 0 {
+				last := &entries[len(entries)-1]
+				last.lines = append(last.lines, line)
+			}
+			continue
+		}
+
+		h := &whatsappHeader{
+			first:  atoi(m[1]),
+			second: atoi(m[2]),
+			year:   atoi(m[3]),
+			hour:   atoi(m[4]),
+			minute: atoi(m[5]),
+			sec:    atoi(m[6]),
+			pm:     strings.EqualFold(m[7], "p"),
+			am:     strings.EqualFold(m[7], "a"),
+			body:   m[8],
+		}
+		if h.first > 12 {
+			dayFirst = true
+		}
+		if h.second > 12 {
+			monthFirst = true
+		}
+		entries = append(entries, entry{header: h})
+	}
+	if err := scanner.Err(); err != nil {
+		return domain.ChatExport{}, err
+	}
+	if len(entries) == 0 {
+		return domain.ChatExport{}, domain.ErrChatExportFormat
+	}
+
+	// spanish phones write the day first, only a month over 12 says otherwise
+	swap := monthFirst && !dayFirst
+
+	export := domain.ChatExport{Messages: make([]domain.ChatMessage, 0, len(entries))}
+	for _, e := range entries {
+		author, text, ok := strings.Cut(e.header.body, ": ")
+		if !ok {
+			// system messages, someone joined or changed the subject
+			continue
+		}
+		if len(e.lines) > 0 {
+			text += "\n" + strings.Join(e.lines, "\n")
+		}
+		text = strings.TrimSpace(text)
+		if skipWhatsApp(text) {
+			continue
+		}
+
+		export.Messages = append(export.Messages, domain.ChatMessage{
+			Author: strings.TrimSpace(author),
+			SentAt: e.header.time(swap, loc),
+			Text:   text,
+		})
+	}
+
+	return export, nil
+}
+
+func (h *whatsappHeader) time(swap bool, loc *time.Location) time.Time {
+	day, month := h.first, h.second
+	if swap {
+		day, month = month, day
+	}
+	year := h.year
+	if year < 100 {
+		year += 2000
+	}
+	hour := h.hour
+	if h.pm && hour < 12 {
+		hour += 12
+	}
+	if h.am && hour == 12 {
+		hour = 0
+	}
+
+	return time.Date(year, time.Month(month), day, hour, h.minute, h.sec, 0, loc)
+}
+
+func skipWhatsApp(text string) bool {
+	if text == "" || (strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">")) {
+		return true
+	}
+	lower := strings.ToLower(text)
+	for _, skipped := range whatsappSkipped {
+		if lower == skipped {
+			return true
+		}
+	}
+	return false
+}
+
+// whatsappChatName reads the name of the chat from the name whatsapp gives the file
+func whatsappChatName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for _, prefix := range whatsappFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return ""
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
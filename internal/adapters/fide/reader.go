@@ -0,0 +1,133 @@
+// Package fide provides readers for the official fide rating list files
+// published at https://ratings.fide.com/download_lists.phtml
+package fide
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ctfrancia/maple/internal/adapters/importdir"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// cancelCheckEvery is how many records are read between context checks,
+// the full lists have around a million players
+const cancelCheckEvery = 1000
+
+// ListReader reads rating list files from the import directory, the paths are relative to it
+type ListReader struct {
+	dir string
+}
+
+func NewListReader(dir string) ports.FideListReader {
+	return &ListReader{dir: dir}
+}
+
+// Read opens the file and streams its records to fn, the period of the records is left for the caller to set
+func (lr *ListReader) Read(ctx context.Context, file domain.FideListFile, fn func(domain.FideRecord) error) error {
+	f, err := importdir.Open(lr.dir, file.Path)
+	if err != nil {
+		return fmt.Errorf("error opening rating list: %w", err)
+	}
+	defer f.Close()
+
+	single := file.SingleRatingType
+	if single == "" {
+		single = domain.RatingTypeStandard
+	}
+
+	r := bufio.NewReader(f)
+	switch file.Format {
+	case domain.FideListFormatXML:
+		return readXML(ctx, r, single, fn)
+	case domain.FideListFormatTXT:
+		return readTXT(ctx, r, single, fn)
+	}
+
+	return domain.ErrFideListFormat
+}
+
+// checkContext is called for every record and only looks at the context every cancelCheckEvery records
+func checkContext(ctx context.Context, n int) error {
+	if n%cancelCheckEvery != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// atoi parses a number from the list, blanks and garbage are 0 as fide leaves
+// the columns of unrated players empty
+func atoi(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// latin1ToUTF8 decodes an ISO-8859-1 string, the encoding fide publishes the lists in
+func latin1ToUTF8(s string) string {
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+// latin1Reader is the xml.Decoder CharsetReader for the ISO-8859-1 xml lists
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		return &latin1Decoder{r: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("unsupported rating list charset %q", charset)
+}
+
+// latin1Decoder streams ISO-8859-1 as UTF-8 so the full lists are never held in memory
+type latin1Decoder struct {
+	r       io.ByteReader
+	pending []byte
+}
+
+func (d *latin1Decoder) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(d.pending) > 0 {
+			p[n] = d.pending[0]
+			d.pending = d.pending[1:]
+			n++
+			continue
+		}
+
+		b, err := d.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		d.pending = utf8.AppendRune(d.pending[:0], rune(b))
+	}
+
+	return n, nil
+}
+
+// title returns the open title when there is one and the women's title otherwise
+func title(open, women string) string {
+	if t := strings.TrimSpace(open); t != "" {
+		return t
+	}
+	return strings.TrimSpace(women)
+}
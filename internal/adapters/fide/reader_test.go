@@ -0,0 +1,185 @@
+package fide
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const combinedXML = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<playerslist>
+<player>
+<fideid>1503014</fideid>
+<name>Carlsen, Magnus</name>
+<country>NOR</country>
+<sex>M</sex>
+<title>GM</title>
+<w_title></w_title>
+<o_title></o_title>
+<foa_title></foa_title>
+<rating>2830</rating>
+<games>0</games>
+<k>10</k>
+<rapid_rating>2823</rapid_rating>
+<rapid_games>0</rapid_games>
+<rapid_k>20</rapid_k>
+<blitz_rating>2886</blitz_rating>
+<blitz_games>0</blitz_games>
+<blitz_k>20</blitz_k>
+<birthday>1990</birthday>
+<flag></flag>
+</player>
+<player>
+<fideid>2293080</fideid>
+<name>Garcia Lopez, Maria</name>
+<country>ESP</country>
+<sex>F</sex>
+<title></title>
+<w_title>WFM</w_title>
+<rating>2010</rating>
+<rapid_rating></rapid_rating>
+<blitz_rating></blitz_rating>
+<birthday>0000</birthday>
+</player>
+</playerslist>
+`
+
+const combinedTXT = `ID Number      Name                                                         Fed Sex Tit  WTit OTit           FOA SRtng SGm SK RRtng RGm Rk BRtng BGm BK B-day Flag 
+1503014        Carlsen, Magnus                                              NOR M   GM                           2830   0 10  2823   0 20  2886   0 20  1990      
+2293080        Garcia Lopez, Maria                                          ESP F        WFM                     2010   4 20                           0000 w    
+`
+
+const singleTXT = `ID Number      Name                                                         Fed Sex Tit  WTit OTit           FOA OCT26 Gms  K B-day Flag 
+1503014        Carlsen, Magnus                                              NOR M   GM                           2823   0 20 1990      
+`
+
+func writeList(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func readAll(t *testing.T, file domain.FideListFile) []domain.FideRecord {
+	t.Helper()
+
+	var records []domain.FideRecord
+	dir, name := filepath.Split(file.Path)
+	file.Path = name
+	err := NewListReader(dir).Read(context.Background(), file, func(r domain.FideRecord) error {
+		records = append(records, r)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return records
+}
+
+func TestListReader_Read(t *testing.T) {
+	carlsen := domain.FideRecord{
+		FideID:     1503014,
+		Name:       "Carlsen, Magnus",
+		Federation: "NOR",
+		Sex:        "M",
+		Title:      "GM",
+		BirthYear:  1990,
+		Ratings:    domain.Ratings{Standard: 2830, Rapid: 2823, Blitz: 2886},
+	}
+	garcia := domain.FideRecord{
+		FideID:     2293080,
+		Name:       "Garcia Lopez, Maria",
+		Federation: "ESP",
+		Sex:        "F",
+		Title:      "WFM",
+		Ratings:    domain.Ratings{Standard: 2010},
+	}
+
+	tests := []struct {
+		name     string
+		file     domain.FideListFile
+		content  string
+		expected []domain.FideRecord
+	}{
+		{
+			name:     "combined xml list",
+			file:     domain.FideListFile{Format: domain.FideListFormatXML},
+			content:  combinedXML,
+			expected: []domain.FideRecord{carlsen, garcia},
+		},
+		{
+			name:     "combined txt list",
+			file:     domain.FideListFile{Format: domain.FideListFormatTXT},
+			content:  combinedTXT,
+			expected: []domain.FideRecord{carlsen, garcia},
+		},
+		{
+			name:    "single rapid txt list",
+			file:    domain.FideListFile{Format: domain.FideListFormatTXT, SingleRatingType: domain.RatingTypeRapid},
+			content: singleTXT,
+			expected: []domain.FideRecord{{
+				FideID:     1503014,
+				Name:       "Carlsen, Magnus",
+				Federation: "NOR",
+				Sex:        "M",
+				Title:      "GM",
+				BirthYear:  1990,
+				Ratings:    domain.Ratings{Rapid: 2823},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.file.Path = writeList(t, "list."+string(tt.file.Format), tt.content)
+
+			assert.Equal(t, tt.expected, readAll(t, tt.file))
+		})
+	}
+}
+
+func TestListReader_Errors(t *testing.T) {
+	t.Run("should fail on missing file", func(t *testing.T) {
+		err := NewListReader(t.TempDir()).Read(context.Background(), domain.FideListFile{
+			Path:   "missing.txt",
+			Format: domain.FideListFormatTXT,
+		}, func(domain.FideRecord) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrImportFileNotFound)
+	})
+
+	t.Run("should fail on a file outside the import directory", func(t *testing.T) {
+		err := NewListReader(t.TempDir()).Read(context.Background(), domain.FideListFile{
+			Path:   writeList(t, "list.txt", "ID Number Name\n"),
+			Format: domain.FideListFormatTXT,
+		}, func(domain.FideRecord) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrImportFileNotFound)
+	})
+
+	t.Run("should fail on unknown format", func(t *testing.T) {
+		dir := filepath.Dir(writeList(t, "list.csv", "a,b,c"))
+		err := NewListReader(dir).Read(context.Background(), domain.FideListFile{
+			Path:   "list.csv",
+			Format: "csv",
+		}, func(domain.FideRecord) error { return nil })
+
+		assert.ErrorIs(t, err, domain.ErrFideListFormat)
+	})
+
+	t.Run("should fail on a header without ids", func(t *testing.T) {
+		dir := filepath.Dir(writeList(t, "list.txt", "Name Fed\nCarlsen NOR\n"))
+		err := NewListReader(dir).Read(context.Background(), domain.FideListFile{
+			Path:   "list.txt",
+			Format: domain.FideListFormatTXT,
+		}, func(domain.FideRecord) error { return nil })
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package fide
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// monthColumnRX matches the rating column of the single lists, which is named after the period, e.g. OCT26
+var monthColumnRX = regexp.MustCompile(`^[A-Z]{3}\d{2}$`)
+
+var labelRX = regexp.MustCompile(`\S+`)
+
+// numericColumns are the columns whose values may be right aligned to their label
+var numericColumns = map[string]bool{
+	"SRtng": true, "SGm": true, "SK": true,
+	"RRtng": true, "RGm": true, "Rk": true,
+	"BRtng": true, "BGm": true, "BK": true,
+	"Rating": true, "Gms": true, "K": true,
+	"B-day": true,
+}
+
+// txtColumns is where every column starts and ends, taken from the header line
+type txtColumns map[string][2]int
+
+// newTXTColumns reads the column positions from the header. Text values are left aligned
+// to their label and run until the next label starts, numbers may be right aligned so they
+// run from the end of the previous label until the end of their own
+func newTXTColumns(header string) (txtColumns, error) {
+	// "ID Number" is the only label with a space in it
+	header = strings.Replace(header, "ID Number", "ID_Number", 1)
+
+	labels := labelRX.FindAllStringIndex(header, -1)
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("rating list header is empty")
+	}
+
+	cols := make(txtColumns, len(labels))
+	for i, l := range labels {
+		name := header[l[0]:l[1]]
+		if monthColumnRX.MatchString(name) || name == "Rtng" {
+			name = "Rating"
+		}
+
+		if numericColumns[name] && i > 0 {
+			cols[name] = [2]int{labels[i-1][1], l[1]}
+			continue
+		}
+
+		end := -1 // until the end of the line
+		if i+1 < len(labels) {
+			end = labels[i+1][0]
+		}
+		cols[name] = [2]int{l[0], end}
+	}
+
+	if _, ok := cols["ID_Number"]; !ok {
+		return nil, fmt.Errorf("rating list header has no ID Number column")
+	}
+	if _, ok := cols["Name"]; !ok {
+		return nil, fmt.Errorf("rating list header has no Name column")
+	}
+
+	return cols, nil
+}
+
+// value returns the trimmed value of the column, empty if the list does not have it
+func (c txtColumns) value(line, name string) string {
+	pos, ok := c[name]
+	if !ok || pos[0] >= len(line) {
+		return ""
+	}
+
+	end := pos[1]
+	if end < 0 || end > len(line) {
+		end = len(line)
+	}
+
+	value := line[pos[0]:end]
+	if !utf8.ValidString(value) {
+		value = latin1ToUTF8(value)
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func readTXT(ctx context.Context, r io.Reader, single domain.RatingType, fn func(domain.FideRecord) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("error reading txt rating list: %w", err)
+		}
+		return fmt.Errorf("rating list is empty")
+	}
+
+	cols, err := newTXTColumns(scanner.Text())
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n++
+		if err := checkContext(ctx, n); err != nil {
+			return err
+		}
+
+		record := domain.FideRecord{
+			FideID:     atoi(cols.value(line, "ID_Number")),
+			Name:       cols.value(line, "Name"),
+			Federation: cols.value(line, "Fed"),
+			Sex:        cols.value(line, "Sex"),
+			Title:      title(cols.value(line, "Tit"), cols.value(line, "WTit")),
+			BirthYear:  atoi(cols.value(line, "B-day")),
+			Ratings: domain.Ratings{
+				Standard: atoi(cols.value(line, "SRtng")),
+				Rapid:    atoi(cols.value(line, "RRtng")),
+				Blitz:    atoi(cols.value(line, "BRtng")),
+			},
+		}
+		if rating := atoi(cols.value(line, "Rating")); rating > 0 {
+			record.Ratings.Set(single, rating)
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading txt rating list: %w", err)
+	}
+
+	return nil
+}
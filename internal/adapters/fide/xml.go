@@ -0,0 +1,78 @@
+package fide
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// xmlPlayer is a <player> of the xml lists, the combined list carries the three
+// ratings while the single lists only have <rating>
+type xmlPlayer struct {
+	FideID      string `xml:"fideid"`
+	Name        string `xml:"name"`
+	Country     string `xml:"country"`
+	Sex         string `xml:"sex"`
+	Title       string `xml:"title"`
+	WomenTitle  string `xml:"w_title"`
+	Rating      string `xml:"rating"`
+	RapidRating string `xml:"rapid_rating"`
+	BlitzRating string `xml:"blitz_rating"`
+	Birthday    string `xml:"birthday"`
+}
+
+func readXML(ctx context.Context, r io.Reader, single domain.RatingType, fn func(domain.FideRecord) error) error {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = latin1Reader
+
+	n := 0
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading xml rating list: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "player" {
+			continue
+		}
+
+		var p xmlPlayer
+		if err := decoder.DecodeElement(&p, &start); err != nil {
+			return fmt.Errorf("error reading xml rating list: %w", err)
+		}
+
+		n++
+		if err := checkContext(ctx, n); err != nil {
+			return err
+		}
+
+		record := domain.FideRecord{
+			FideID:     atoi(p.FideID),
+			Name:       strings.TrimSpace(p.Name),
+			Federation: strings.TrimSpace(p.Country),
+			Sex:        strings.TrimSpace(p.Sex),
+			Title:      title(p.Title, p.WomenTitle),
+			BirthYear:  atoi(p.Birthday),
+		}
+		record.Ratings.Set(single, atoi(p.Rating))
+		if rapid := atoi(p.RapidRating); rapid > 0 {
+			record.Ratings.Rapid = rapid
+		}
+		if blitz := atoi(p.BlitzRating); blitz > 0 {
+			record.Ratings.Blitz = blitz
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package geocoding provides the geocoders of the locations: an offline gazetteer loaded
+// from the geonames dumps and a client of the nominatim api
+package geocoding
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+const (
+	// reverseMaxKm is how far the closest populated place can be from the coordinates
+	reverseMaxKm = 25.0
+	// reversePostalMaxKm is how far the closest postal code can be, they are points in the
+	// middle of their area
+	reversePostalMaxKm = 5.0
+	// kmPerDegree is the length of a degree of latitude
+	kmPerDegree = 111.0
+)
+
+// postalCodePattern finds the postal code of an address, five digits as in spain
+var postalCodePattern = regexp.MustCompile(`\b\d{5}\b`)
+
+// Gazetteer geocodes offline with the places of the geonames dumps. It resolves postal codes
+// and the names of the populated places, streets are out of its reach so the address only
+// counts for its postal code and its city
+type Gazetteer struct {
+	places           []place
+	byName           map[string][]int  // normalized names, alternate ones included
+	byMunicipality   map[string]int    // country.admin3
+	populated        []int             // the populated places by latitude
+	states           map[string]string // country.admin1
+	provinces        map[string]string // country.admin1.admin2
+	counties         map[string]string // country.admin3
+	postal           []postalCode
+	postalCodes      map[string][]int
+	postalByLatitude []int
+}
+
+var _ ports.Geocoder = (*Gazetteer)(nil)
+
+// place is a populated place or a municipality of the places dump
+type place struct {
+	name                   string
+	latitude, longitude    float64
+	municipality           bool // an ADM3 area rather than a populated place
+	country                string
+	admin1, admin2, admin3 string
+	population             int
+	timezone               domain.Timezone
+}
+
+type postalCode struct {
+	country             string
+	code                string
+	place               string
+	state               string
+	province            string
+	municipality        string
+	admin3              string
+	latitude, longitude float64
+}
+
+// Geocode resolves the postal code first, it is the most precise the gazetteer gets, then
+// the city and last the parts of the address from its end as it usually finishes with the city
+func (g *Gazetteer) Geocode(ctx context.Context, query domain.GeocodeQuery) (domain.Place, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Place{}, err
+	}
+	if query.Empty() {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+
+	code := query.PostalCode
+	var cities []string
+	if query.City != "" {
+		cities = append(cities, query.City)
+	}
+	if query.Address != "" {
+		if code == "" {
+			code = postalCodePattern.FindString(query.Address)
+		}
+		parts := strings.Split(query.Address, ",")
+		for i := len(parts) - 1; i >= 0; i-- {
+			if part := strings.TrimSpace(postalCodePattern.ReplaceAllString(parts[i], "")); part != "" {
+				cities = append(cities, part)
+			}
+		}
+	}
+
+	if code != "" {
+		if result, ok := g.findPostalCode(code, query.Country, cities); ok {
+			return result, nil
+		}
+	}
+	for _, city := range cities {
+		if result, ok := g.findCity(city, query.Province, query.Country); ok {
+			return result, nil
+		}
+	}
+
+	return domain.Place{}, domain.ErrGeocodeNotFound
+}
+
+// Reverse returns the populated place closest to the coordinates with the closest postal code
+func (g *Gazetteer) Reverse(ctx context.Context, latitude, longitude float64) (domain.Place, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Place{}, err
+	}
+
+	closest := nearest(g.populated, latitude, longitude, reverseMaxKm, func(i int) (float64, float64) {
+		return g.places[i].latitude, g.places[i].longitude
+	})
+	if closest < 0 {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+	result := g.placeOf(g.places[closest])
+
+	code := nearest(g.postalByLatitude, latitude, longitude, reversePostalMaxKm, func(i int) (float64, float64) {
+		return g.postal[i].latitude, g.postal[i].longitude
+	})
+	if code >= 0 {
+		result.PostalCode = g.postal[code].code
+	}
+
+	return result, nil
+}
+
+// findPostalCode picks the place of the postal code that is in one of the cities, or the
+// first one when none is. A code can span several villages
+func (g *Gazetteer) findPostalCode(code, country string, cities []string) (domain.Place, bool) {
+	var candidates []postalCode
+	for _, i := range g.postalCodes[normalizePostalCode(code)] {
+		if country == "" || strings.EqualFold(g.postal[i].country, country) {
+			candidates = append(candidates, g.postal[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return domain.Place{}, false
+	}
+
+	found := candidates[0]
+cities:
+	for _, city := range cities {
+		name := domain.NormalizeName(city)
+		for _, candidate := range candidates {
+			if domain.NormalizeName(candidate.place) == name || domain.NormalizeName(candidate.municipality) == name {
+				found = candidate
+				break cities
+			}
+		}
+	}
+
+	result := domain.Place{
+		Name:     found.place,
+		City:     found.place,
+		State:    found.state,
+		Province: found.province,
+		Country:  found.country,
+	}
+	if found.municipality != "" {
+		result.City = found.municipality
+	}
+	// the municipality of the places dump has the canonical names of the regions
+	if i, ok := g.byMunicipality[found.country+"."+found.admin3]; ok {
+		result = g.placeOf(g.places[i])
+		result.Name = found.place
+	}
+	result.County = g.counties[found.country+"."+found.admin3]
+	result.PostalCode = found.code
+	result.Latitude, result.Longitude = found.latitude, found.longitude
+	result.Precision = domain.GeocodePrecisionPostalCode
+
+	return result, true
+}
+
+// findCity picks among the places called name the ones whose own name it is before the
+// ones it is an alternate name of, then the most populated
+func (g *Gazetteer) findCity(name, province, country string) (domain.Place, bool) {
+	key := domain.NormalizeName(name)
+	var candidates []place
+	for _, i := range g.byName[key] {
+		p := g.places[i]
+		if country != "" && !strings.EqualFold(p.country, country) {
+			continue
+		}
+		if province != "" {
+			if known, ok := g.provinces[p.country+"."+p.admin1+"."+p.admin2]; ok && domain.NormalizeName(known) != domain.NormalizeName(province) {
+				continue
+			}
+		}
+		candidates = append(candidates, p)
+	}
+	// the populated place is the centre of the town, the municipality of the same name the
+	// centre of its area
+	towns := make(map[string]bool)
+	for _, p := range candidates {
+		if !p.municipality {
+			towns[p.country+"."+p.admin3] = true
+		}
+	}
+	candidates = slices.DeleteFunc(candidates, func(p place) bool {
+		return p.municipality && towns[p.country+"."+p.admin3]
+	})
+	if len(candidates) == 0 {
+		return domain.Place{}, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		ownA, ownB := domain.NormalizeName(a.name) == key, domain.NormalizeName(b.name) == key
+		if ownA != ownB {
+			return ownA
+		}
+		return a.population > b.population
+	})
+
+	return g.placeOf(candidates[0]), true
+}
+
+// placeOf names the regions of the place, the city is the municipality it belongs to
+func (g *Gazetteer) placeOf(p place) domain.Place {
+	result := domain.Place{
+		Name:      p.name,
+		City:      p.name,
+		County:    g.counties[p.country+"."+p.admin3],
+		Province:  g.provinces[p.country+"."+p.admin1+"."+p.admin2],
+		State:     g.states[p.country+"."+p.admin1],
+		Country:   p.country,
+		Latitude:  p.latitude,
+		Longitude: p.longitude,
+		Timezone:  p.timezone,
+		Precision: domain.GeocodePrecisionCity,
+	}
+	if i, ok := g.byMunicipality[p.country+"."+p.admin3]; ok && p.admin3 != "" {
+		result.City = g.places[i].name
+	}
+	return result
+}
+
+// nearest returns the index of byLatitude closest to the coordinates within maxKm or -1, only
+// the band of latitudes maxKm around them is scanned
+func nearest(byLatitude []int, latitude, longitude, maxKm float64, coordinates func(int) (float64, float64)) int {
+	band := maxKm / kmPerDegree
+	start := sort.Search(len(byLatitude), func(i int) bool {
+		lat, _ := coordinates(byLatitude[i])
+		return lat >= latitude-band
+	})
+
+	closest, best := -1, maxKm
+	for _, i := range byLatitude[start:] {
+		lat, lng := coordinates(i)
+		if lat > latitude+band {
+			break
+		}
+		if distance := domain.DistanceKm(latitude, longitude, lat, lng); distance <= best {
+			closest, best = i, distance
+		}
+	}
+	return closest
+}
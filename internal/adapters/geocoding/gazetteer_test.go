@@ -0,0 +1,172 @@
+package geocoding
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the columns of the places dump: id, name, ascii name, alternate names, latitude, longitude,
+// feature class and code, country, cc2, admin1 to admin4, population, elevation, dem,
+// timezone and modification date
+var places = [][]string{
+	{"3128760", "Barcelona", "Barcelona", "BCN,Barcelone,Barcelona", "41.38879", "2.15899", "P", "PPLA2", "ES", "", "56", "B", "08019", "", "1620343", "", "47", "Europe/Madrid", "2024-01-01"},
+	{"6356055", "Barcelona", "Barcelona", "", "41.40000", "2.16667", "A", "ADM3", "ES", "", "56", "B", "08019", "", "1621537", "", "40", "Europe/Madrid", "2024-01-01"},
+	{"3106560", "Vallcarca", "Vallcarca", "", "41.41060", "2.14250", "P", "PPLX", "ES", "", "56", "B", "08019", "", "0", "", "120", "Europe/Madrid", "2024-01-01"},
+	{"3121456", "Girona", "Girona", "Gerona,Gérone", "41.98311", "2.82493", "P", "PPLA2", "ES", "", "56", "GI", "17079", "", "103369", "", "75", "Europe/Madrid", "2024-01-01"},
+	{"6534166", "Girona", "Girona", "", "41.97000", "2.81000", "A", "ADM3", "ES", "", "56", "GI", "17079", "", "0", "", "70", "Europe/Madrid", "2024-01-01"},
+	{"3124932", "Hotel Colón", "Hotel Colon", "", "41.38540", "2.17580", "S", "HTL", "ES", "", "56", "B", "08019", "", "0", "", "12", "Europe/Madrid", "2024-01-01"},
+	{"3648559", "Barcelona", "Barcelona", "", "10.13625", "-64.68618", "P", "PPLA", "VE", "", "02", "", "", "", "424795", "", "15", "America/Caracas", "2024-01-01"},
+}
+
+// the columns of the postal codes dump: country, code, place, admin1 name and code, admin2
+// name and code, admin3 name and code, latitude, longitude and accuracy
+var postalCodes = [][]string{
+	{"ES", "08019", "Barcelona", "Cataluna", "CT", "Barcelona", "B", "Barcelona", "08019", "41.4036", "2.1936", "4"},
+	{"ES", "17001", "Girona", "Cataluna", "CT", "Girona", "GI", "Girona", "17079", "41.9831", "2.8249", "4"},
+	{"ES", "17150", "Sant Gregori", "Cataluna", "CT", "Girona", "GI", "Sant Gregori", "17163", "41.9882", "2.7572", "4"},
+	{"ES", "17150", "Canet d'Adri", "Cataluna", "CT", "Girona", "GI", "Canet d'Adri", "17036", "42.0299", "2.7424", "4"},
+}
+
+func writeTSV(t *testing.T, dir, name string, rows [][]string) string {
+	t.Helper()
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, "\t")
+	}
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+
+	return path
+}
+
+func loadGazetteer(t *testing.T) *Gazetteer {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeTSV(t, dir, "ES.txt", places)
+	writeTSV(t, dir, "admin1CodesASCII.txt", [][]string{{"ES.56", "Catalonia", "Catalonia", "3336901"}, {"VE.02", "Anzoátegui", "Anzoategui", "3648544"}})
+	writeTSV(t, dir, "admin2Codes.txt", [][]string{{"ES.56.B", "Barcelona", "Barcelona", "6355233"}, {"ES.56.GI", "Girona", "Girona", "6355230"}})
+	writeTSV(t, dir, "counties.txt", [][]string{{"ES.08019", "Barcelonès"}, {"ES.17079", "Gironès"}, {"ES.17163", "Gironès"}, {"ES.17036", "Gironès"}})
+	writeTSV(t, dir, filepath.Join("zip", "ES.txt"), postalCodes)
+
+	g, err := LoadGazetteer(context.Background(), GazetteerDir(dir, "es"))
+	require.NoError(t, err)
+
+	return g
+}
+
+func TestGazetteer_Geocode(t *testing.T) {
+	g := loadGazetteer(t)
+
+	tests := []struct {
+		name     string
+		query    domain.GeocodeQuery
+		expected domain.Place
+	}{
+		{
+			name:  "postal code",
+			query: domain.GeocodeQuery{PostalCode: "08019"},
+			expected: domain.Place{
+				Name: "Barcelona", PostalCode: "08019", City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalonia", Country: "ES",
+				Latitude: 41.4036, Longitude: 2.1936, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionPostalCode,
+			},
+		},
+		{
+			name:  "postal code of the address",
+			query: domain.GeocodeQuery{Address: "Carrer de Provençals 40, 08019 Barcelona"},
+			expected: domain.Place{
+				Name: "Barcelona", PostalCode: "08019", City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalonia", Country: "ES",
+				Latitude: 41.4036, Longitude: 2.1936, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionPostalCode,
+			},
+		},
+		{
+			name:  "postal code shared by villages",
+			query: domain.GeocodeQuery{PostalCode: "17150", City: "Canet d'Adri"},
+			expected: domain.Place{
+				Name: "Canet d'Adri", PostalCode: "17150", City: "Canet d'Adri", County: "Gironès", Province: "Girona", State: "Cataluna", Country: "ES",
+				Latitude: 42.0299, Longitude: 2.7424, Precision: domain.GeocodePrecisionPostalCode,
+			},
+		},
+		{
+			name:  "alternate name of the city",
+			query: domain.GeocodeQuery{City: "Gerona"},
+			expected: domain.Place{
+				Name: "Girona", City: "Girona", County: "Gironès", Province: "Girona", State: "Catalonia", Country: "ES",
+				Latitude: 41.98311, Longitude: 2.82493, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionCity,
+			},
+		},
+		{
+			name:  "city of the address",
+			query: domain.GeocodeQuery{Address: "Plaça del Vi 1, girona"},
+			expected: domain.Place{
+				Name: "Girona", City: "Girona", County: "Gironès", Province: "Girona", State: "Catalonia", Country: "ES",
+				Latitude: 41.98311, Longitude: 2.82493, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionCity,
+			},
+		},
+		{
+			name:  "the most populated city of the name",
+			query: domain.GeocodeQuery{City: "Barcelona"},
+			expected: domain.Place{
+				Name: "Barcelona", City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalonia", Country: "ES",
+				Latitude: 41.38879, Longitude: 2.15899, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionCity,
+			},
+		},
+		{
+			name:  "city of another country",
+			query: domain.GeocodeQuery{City: "Barcelona", Country: "VE"},
+			expected: domain.Place{
+				Name: "Barcelona", City: "Barcelona", State: "Anzoátegui", Country: "VE",
+				Latitude: 10.13625, Longitude: -64.68618, Timezone: "America/Caracas", Precision: domain.GeocodePrecisionCity,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			place, err := g.Geocode(context.Background(), tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, place)
+		})
+	}
+
+	for _, query := range []domain.GeocodeQuery{{}, {City: "Hotel Colón"}, {PostalCode: "99999"}, {City: "Girona", Province: "Barcelona"}} {
+		_, err := g.Geocode(context.Background(), query)
+		assert.ErrorIs(t, err, domain.ErrGeocodeNotFound, "%+v", query)
+	}
+}
+
+func TestGazetteer_Reverse(t *testing.T) {
+	g := loadGazetteer(t)
+
+	// a neighbourhood is named after the municipality it belongs to
+	place, err := g.Reverse(context.Background(), 41.4110, 2.1430)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Place{
+		Name: "Vallcarca", PostalCode: "08019", City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalonia", Country: "ES",
+		Latitude: 41.4106, Longitude: 2.1425, Timezone: "Europe/Madrid", Precision: domain.GeocodePrecisionCity,
+	}, place)
+
+	// out in the mediterranean
+	_, err = g.Reverse(context.Background(), 40.5, 4.5)
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotFound)
+}
+
+func TestLoadGazetteer(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTSV(t, dir, "ES.txt", [][]string{{"3128760", "Barcelona", "41.38879"}})
+
+	_, err := LoadGazetteer(context.Background(), GazetteerFiles{Places: path})
+	assert.ErrorIs(t, err, domain.ErrGazetteerFormat)
+
+	_, err = LoadGazetteer(context.Background(), GazetteerFiles{Places: filepath.Join(dir, "missing.txt")})
+	assert.Error(t, err)
+}
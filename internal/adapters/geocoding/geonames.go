@@ -0,0 +1,254 @@
+package geocoding
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// cancelCheckEvery is how many lines are read between context checks, the dump of spain
+// has around 70k places
+const cancelCheckEvery = 1000
+
+// GazetteerFiles are the geonames dumps the gazetteer is loaded from, only Places is required.
+// They are published at https://download.geonames.org/export/dump/ and the postal codes at
+// https://download.geonames.org/export/zip/
+type GazetteerFiles struct {
+	Places      string // e.g. ES.txt, the places of a country
+	Admin1      string // admin1CodesASCII.txt, the names of the states
+	Admin2      string // admin2Codes.txt, the names of the provinces
+	PostalCodes string // zip/ES.txt, the postal codes of the country
+	// Counties is not a geonames file, it maps municipalities to the county they belong to
+	// with lines such as "ES.08019<TAB>Barcelonès", the country and the admin3 code of the
+	// municipality. In catalonia the comarques are built from the municipality list of idescat
+	Counties string
+}
+
+// GazetteerDir is the files of the country found in dir with the names geonames gives them,
+// the postal codes are looked for in dir/zip as both dumps are called after the country
+func GazetteerDir(dir, country string) GazetteerFiles {
+	optional := func(path string) string {
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+		return path
+	}
+
+	country = strings.ToUpper(country)
+	return GazetteerFiles{
+		Places:      filepath.Join(dir, country+".txt"),
+		Admin1:      optional(filepath.Join(dir, "admin1CodesASCII.txt")),
+		Admin2:      optional(filepath.Join(dir, "admin2Codes.txt")),
+		PostalCodes: optional(filepath.Join(dir, "zip", country+".txt")),
+		Counties:    optional(filepath.Join(dir, "counties.txt")),
+	}
+}
+
+// LoadGazetteer reads the files into memory and indexes them
+func LoadGazetteer(ctx context.Context, files GazetteerFiles) (*Gazetteer, error) {
+	g := &Gazetteer{
+		byName:         make(map[string][]int),
+		states:         make(map[string]string),
+		provinces:      make(map[string]string),
+		byMunicipality: make(map[string]int),
+		counties:       make(map[string]string),
+		postalCodes:    make(map[string][]int),
+	}
+
+	if files.Places == "" {
+		return nil, errors.New("the places file of the gazetteer is required")
+	}
+	if err := readTSV(ctx, files.Places, g.addPlace); err != nil {
+		return nil, err
+	}
+	if files.Admin1 != "" {
+		if err := readTSV(ctx, files.Admin1, codeNames(g.states)); err != nil {
+			return nil, err
+		}
+	}
+	if files.Admin2 != "" {
+		if err := readTSV(ctx, files.Admin2, codeNames(g.provinces)); err != nil {
+			return nil, err
+		}
+	}
+	if files.Counties != "" {
+		if err := readTSV(ctx, files.Counties, codeNames(g.counties)); err != nil {
+			return nil, err
+		}
+	}
+	if files.PostalCodes != "" {
+		if err := readTSV(ctx, files.PostalCodes, g.addPostalCode); err != nil {
+			return nil, err
+		}
+	}
+
+	g.index()
+
+	return g, nil
+}
+
+// readTSV calls fn with the columns of every line of the file, blank lines and comments are skipped
+func readTSV(ctx context.Context, path string, fn func(columns []string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening gazetteer file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// the alternate names of the big cities make for long lines
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if n%cancelCheckEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(strings.Split(line, "\t")); err != nil {
+			return fmt.Errorf("%s line %d: %w", filepath.Base(path), n, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// codeNames reads the admin codes files, "ES.56<TAB>Catalonia<TAB>Catalonia<TAB>3336901",
+// the second column is the name in its language and the third in ascii
+func codeNames(names map[string]string) func(columns []string) error {
+	return func(columns []string) error {
+		if len(columns) < 2 {
+			return domain.ErrGazetteerFormat
+		}
+		names[columns[0]] = columns[1]
+		return nil
+	}
+}
+
+// addPlace reads a line of a places dump, only the populated places and the municipalities are kept
+func (g *Gazetteer) addPlace(columns []string) error {
+	if len(columns) < 19 {
+		return domain.ErrGazetteerFormat
+	}
+
+	featureClass, featureCode := columns[6], columns[7]
+	municipality := featureClass == "A" && featureCode == "ADM3"
+	if featureClass != "P" && !municipality {
+		return nil
+	}
+
+	lat, err := strconv.ParseFloat(columns[4], 64)
+	if err != nil {
+		return domain.ErrGazetteerFormat
+	}
+	lng, err := strconv.ParseFloat(columns[5], 64)
+	if err != nil {
+		return domain.ErrGazetteerFormat
+	}
+	population, _ := strconv.Atoi(columns[14])
+
+	p := place{
+		name:         columns[1],
+		latitude:     lat,
+		longitude:    lng,
+		municipality: municipality,
+		country:      columns[8],
+		admin1:       columns[10],
+		admin2:       columns[11],
+		admin3:       columns[12],
+		population:   population,
+		timezone:     domain.Timezone(columns[17]),
+	}
+	i := len(g.places)
+	g.places = append(g.places, p)
+
+	if municipality {
+		g.byMunicipality[p.country+"."+p.admin3] = i
+	}
+
+	// the alternate names have the names in other languages, Gerona is Girona
+	names := append([]string{columns[1], columns[2]}, strings.Split(columns[3], ",")...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := domain.NormalizeName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		g.byName[key] = append(g.byName[key], i)
+	}
+
+	return nil
+}
+
+// addPostalCode reads a line of a postal codes dump: country, postal code, place name, then
+// the name and code of admin1, admin2 and admin3, the coordinates and their accuracy
+func (g *Gazetteer) addPostalCode(columns []string) error {
+	if len(columns) < 11 {
+		return domain.ErrGazetteerFormat
+	}
+
+	lat, err := strconv.ParseFloat(columns[9], 64)
+	if err != nil {
+		return domain.ErrGazetteerFormat
+	}
+	lng, err := strconv.ParseFloat(columns[10], 64)
+	if err != nil {
+		return domain.ErrGazetteerFormat
+	}
+
+	code := postalCode{
+		country:      columns[0],
+		code:         columns[1],
+		place:        columns[2],
+		state:        columns[3],
+		province:     columns[5],
+		municipality: columns[7],
+		admin3:       columns[8],
+		latitude:     lat,
+		longitude:    lng,
+	}
+
+	i := len(g.postal)
+	g.postal = append(g.postal, code)
+	key := normalizePostalCode(code.code)
+	g.postalCodes[key] = append(g.postalCodes[key], i)
+
+	return nil
+}
+
+// index sorts what reverse geocoding scans by latitude
+func (g *Gazetteer) index() {
+	for i, p := range g.places {
+		if !p.municipality {
+			g.populated = append(g.populated, i)
+		}
+	}
+	sort.Slice(g.populated, func(a, b int) bool {
+		return g.places[g.populated[a]].latitude < g.places[g.populated[b]].latitude
+	})
+
+	g.postalByLatitude = make([]int, len(g.postal))
+	for i := range g.postal {
+		g.postalByLatitude[i] = i
+	}
+	sort.Slice(g.postalByLatitude, func(a, b int) bool {
+		return g.postal[g.postalByLatitude[a]].latitude < g.postal[g.postalByLatitude[b]].latitude
+	})
+}
+
+func normalizePostalCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
@@ -0,0 +1,188 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// Nominatim geocodes with the api of openstreetmap, https://nominatim.org/release-docs/latest/api/Overview/.
+// The client carries the base url, the public instance asks for a user agent naming the
+// application and no more than a request per second
+type Nominatim struct {
+	client    ports.HTTPClient
+	userAgent string
+}
+
+var _ ports.Geocoder = (*Nominatim)(nil)
+
+func NewNominatim(client ports.HTTPClient, userAgent string) *Nominatim {
+	return &Nominatim{
+		client:    client,
+		userAgent: userAgent,
+	}
+}
+
+// nominatimPlace is a result of /search and /reverse with addressdetails=1
+type nominatimPlace struct {
+	Latitude  string           `json:"lat"`
+	Longitude string           `json:"lon"`
+	Name      string           `json:"name"`
+	Rank      int              `json:"place_rank"` // 26 and above are streets and buildings
+	Address   nominatimAddress `json:"address"`
+	Error     string           `json:"error"` // set by /reverse when nothing is there
+}
+
+type nominatimAddress struct {
+	Road         string `json:"road"`
+	HouseNumber  string `json:"house_number"`
+	Postcode     string `json:"postcode"`
+	City         string `json:"city"`
+	Town         string `json:"town"`
+	Village      string `json:"village"`
+	Municipality string `json:"municipality"`
+	County       string `json:"county"` // the comarca in catalonia
+	Province     string `json:"province"`
+	State        string `json:"state"`
+	CountryCode  string `json:"country_code"`
+}
+
+// Geocode uses the structured search when the address is split in fields and the free form
+// one when only the address is known
+func (n *Nominatim) Geocode(ctx context.Context, query domain.GeocodeQuery) (domain.Place, error) {
+	if query.Empty() {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+
+	params := url.Values{
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"limit":          {"1"},
+	}
+	if query.PostalCode == "" && query.City == "" {
+		params.Set("q", query.Address)
+	} else {
+		for key, value := range map[string]string{"street": query.Address, "postalcode": query.PostalCode, "city": query.City, "county": query.Province} {
+			if value != "" {
+				params.Set(key, value)
+			}
+		}
+	}
+	if query.Country != "" {
+		params.Set("countrycodes", strings.ToLower(query.Country))
+	}
+
+	var results []nominatimPlace
+	if err := n.get(ctx, "/search?"+params.Encode(), &results); err != nil {
+		return domain.Place{}, err
+	}
+	if len(results) == 0 {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+
+	return results[0].toPlace()
+}
+
+func (n *Nominatim) Reverse(ctx context.Context, latitude, longitude float64) (domain.Place, error) {
+	params := url.Values{
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"lat":            {strconv.FormatFloat(latitude, 'f', -1, 64)},
+		"lon":            {strconv.FormatFloat(longitude, 'f', -1, 64)},
+	}
+
+	var result nominatimPlace
+	if err := n.get(ctx, "/reverse?"+params.Encode(), &result); err != nil {
+		return domain.Place{}, err
+	}
+	if result.Error != "" {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+
+	return result.toPlace()
+}
+
+// Ping asks the instance for its status, https://nominatim.org/release-docs/latest/api/Status/
+func (n *Nominatim) Ping(ctx context.Context) error {
+	var status struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := n.get(ctx, "/status?format=json", &status); err != nil {
+		return err
+	}
+	if status.Status != 0 {
+		return fmt.Errorf("nominatim is not ready: %s", status.Message)
+	}
+
+	return nil
+}
+
+func (n *Nominatim) get(ctx context.Context, path string, v any) error {
+	headers := map[string]string{
+		"Accept": "application/json",
+	}
+	if n.userAgent != "" {
+		headers["User-Agent"] = n.userAgent
+	}
+
+	resp, err := n.client.Get(ctx, path, headers)
+	if err != nil {
+		return fmt.Errorf("error calling nominatim: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from nominatim", resp.StatusCode)
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("error decoding nominatim response: %w", err)
+	}
+
+	return nil
+}
+
+func (p nominatimPlace) toPlace() (domain.Place, error) {
+	lat, err := strconv.ParseFloat(p.Latitude, 64)
+	if err != nil {
+		return domain.Place{}, fmt.Errorf("error decoding nominatim latitude: %w", err)
+	}
+	lng, err := strconv.ParseFloat(p.Longitude, 64)
+	if err != nil {
+		return domain.Place{}, fmt.Errorf("error decoding nominatim longitude: %w", err)
+	}
+
+	a := p.Address
+	result := domain.Place{
+		Name:       p.Name,
+		PostalCode: a.Postcode,
+		City:       firstOf(a.City, a.Town, a.Village, a.Municipality),
+		County:     a.County,
+		Province:   a.Province,
+		State:      a.State,
+		Country:    strings.ToUpper(a.CountryCode),
+		Latitude:   lat,
+		Longitude:  lng,
+		Precision:  domain.GeocodePrecisionCity,
+	}
+	if p.Rank >= 26 {
+		result.Precision = domain.GeocodePrecisionStreet
+		result.Name = strings.TrimSpace(a.Road + " " + a.HouseNumber)
+	}
+
+	return result, nil
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package geocoding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nominatimSearch = `[{
+	"lat": "41.4097193",
+	"lon": "2.2001287",
+	"name": "",
+	"place_rank": 30,
+	"address": {
+		"house_number": "40",
+		"road": "Carrer de Provençals",
+		"suburb": "Sant Martí",
+		"city": "Barcelona",
+		"county": "Barcelonès",
+		"province": "Barcelona",
+		"state": "Catalunya",
+		"postcode": "08019",
+		"country_code": "es"
+	}
+}]`
+
+const nominatimReverse = `{
+	"lat": "41.98311",
+	"lon": "2.82493",
+	"name": "Girona",
+	"place_rank": 16,
+	"address": {
+		"city": "Girona",
+		"county": "Gironès",
+		"province": "Girona",
+		"state": "Catalunya",
+		"postcode": "17001",
+		"country_code": "es"
+	}
+}`
+
+func newNominatimServer(t *testing.T) *Nominatim {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "maple-test", r.Header.Get("User-Agent"))
+		query := r.URL.Query()
+		assert.Equal(t, "jsonv2", query.Get("format"))
+
+		switch r.URL.Path {
+		case "/search":
+			switch {
+			case query.Get("q") == "Carrer de Provençals 40, Barcelona":
+				w.Write([]byte(nominatimSearch))
+			case query.Get("street") == "Carrer de Provençals 40" && query.Get("postalcode") == "08019" && query.Get("countrycodes") == "es":
+				w.Write([]byte(nominatimSearch))
+			case query.Get("q") == "down":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				w.Write([]byte(`[]`))
+			}
+		case "/reverse":
+			if query.Get("lat") == "41.98" && query.Get("lon") == "2.82" {
+				w.Write([]byte(nominatimReverse))
+				return
+			}
+			w.Write([]byte(`{"error": "Unable to geocode"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewNominatim(infrastructure.NewHTTPClientAdapter(server.URL, 5*time.Second), "maple-test")
+}
+
+func TestNominatim_Geocode(t *testing.T) {
+	n := newNominatimServer(t)
+	ctx := context.Background()
+
+	expected := domain.Place{
+		Name: "Carrer de Provençals 40", PostalCode: "08019", City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalunya", Country: "ES",
+		Latitude: 41.4097193, Longitude: 2.2001287, Precision: domain.GeocodePrecisionStreet,
+	}
+
+	place, err := n.Geocode(ctx, domain.GeocodeQuery{Address: "Carrer de Provençals 40, Barcelona"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, place)
+
+	place, err = n.Geocode(ctx, domain.GeocodeQuery{Address: "Carrer de Provençals 40", PostalCode: "08019", Country: "ES"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, place)
+
+	_, err = n.Geocode(ctx, domain.GeocodeQuery{City: "Atlantis"})
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotFound)
+
+	_, err = n.Geocode(ctx, domain.GeocodeQuery{Address: "down"})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrGeocodeNotFound)
+}
+
+func TestNominatim_Reverse(t *testing.T) {
+	n := newNominatimServer(t)
+	ctx := context.Background()
+
+	place, err := n.Reverse(ctx, 41.98, 2.82)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Place{
+		Name: "Girona", PostalCode: "17001", City: "Girona", County: "Gironès", Province: "Girona", State: "Catalunya", Country: "ES",
+		Latitude: 41.98311, Longitude: 2.82493, Precision: domain.GeocodePrecisionCity,
+	}, place)
+
+	_, err = n.Reverse(ctx, 40.5, 4.5)
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotFound)
+}
+
+func TestNominatim_Ping(t *testing.T) {
+	status := `{"status": 0, "message": "OK"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		w.Write([]byte(status))
+	}))
+	defer server.Close()
+
+	nominatim := NewNominatim(infrastructure.NewHTTPClientAdapter(server.URL, 5*time.Second), "maple-test")
+
+	require.NoError(t, nominatim.Ping(context.Background()))
+
+	status = `{"status": 700, "message": "Database connection failed"}`
+	err := nominatim.Ping(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Database connection failed")
+}
@@ -5,23 +5,83 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/admin"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/announcement"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/challenge"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/fide"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/live"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/location"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/match"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/moderation"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/notification"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/player"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/rating"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/relay"
 	"github.com/ctfrancia/maple/internal/adapters/http/handlers/system"
 	"github.com/ctfrancia/maple/internal/adapters/http/handlers/tournament"
+	"github.com/ctfrancia/maple/internal/adapters/http/handlers/webhook"
+	"github.com/ctfrancia/maple/internal/adapters/http/live"
+	mw "github.com/ctfrancia/maple/internal/adapters/http/middleware"
+	"github.com/ctfrancia/maple/internal/adapters/http/openapi"
+	"github.com/ctfrancia/maple/internal/adapters/metrics"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
 	"github.com/ctfrancia/maple/internal/core/ports"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
 type Router struct {
-	sysHandler        ports.SystemHandler
-	tournamentHandler ports.TournamentHandler
+	logger              ports.Logger
+	sysHandler          ports.SystemHandler
+	tournamentHandler   ports.TournamentHandler
+	playerHandler       ports.PlayerHandler
+	matchHandler        ports.MatchHandler
+	ratingHandler       ports.RatingHandler
+	fideHandler         ports.FideHandler
+	webhookHandler      ports.WebhookHandler
+	notificationHandler ports.NotificationHandler
+	liveHandler         ports.LiveHandler
+	relayHandler        ports.RelayHandler
+	challengeHandler    ports.ChallengeHandler
+	announcementHandler ports.AnnouncementHandler
+	moderationHandler   ports.ModerationHandler
+	locationHandler     ports.LocationHandler
+	metrics             *metrics.Registry // nil when the api is not measured
+	tracer              ports.Tracer      // nil when the requests are not traced
+	adminHandler        ports.AdminHandler
+	idempotency         ports.IdempotencyStore // nil when the retries are not deduplicated
+	idempotencyTTL      time.Duration
+	adminToken          string // the admin routes are not mounted without one
+	auth                ports.Authenticator
 }
 
-func NewRouter(log ports.Logger, ss ports.SystemServicer, ts ports.TournamentServicer) *chi.Mux {
+func NewRouter(log ports.Logger, ss ports.SystemServicer, ts ports.TournamentServicer, ps ports.PlayerServicer, ms ports.MatchServicer, rs ports.RatingServicer, fs ports.FideServicer, ws ports.WebhookServicer, ns ports.NotificationServicer, rls ports.RelayServicer, cs ports.ChallengeServicer, as ports.AnnouncementServicer, mods ports.ModerationServicer, ls ports.LocationServicer, hub *live.Hub, registry *metrics.Registry, tracer ports.Tracer, levels ports.LogLeveler, idempotency ports.IdempotencyStore, idempotencyTTL time.Duration, adminToken string, auth ports.Authenticator) *chi.Mux {
 	routes := &Router{
-		sysHandler:        systemhandlers.NewSystemHandler(ss, log),
-		tournamentHandler: tournamenthandlers.NewTournamentHandler(log, ts),
+		logger:              log,
+		sysHandler:          systemhandlers.NewSystemHandler(ss, log),
+		tournamentHandler:   tournamenthandlers.NewTournamentHandler(log, ts),
+		playerHandler:       playerhandlers.NewPlayerHandler(log, ps),
+		matchHandler:        matchhandlers.NewMatchHandler(log, ms),
+		ratingHandler:       ratinghandlers.NewRatingHandler(log, rs),
+		fideHandler:         fidehandlers.NewFideHandler(log, fs),
+		webhookHandler:      webhookhandlers.NewWebhookHandler(log, ws),
+		notificationHandler: notificationhandlers.NewNotificationHandler(log, ns),
+		liveHandler:         livehandlers.NewLiveHandler(log, ts, hub),
+		relayHandler:        relayhandlers.NewRelayHandler(log, rls),
+		challengeHandler:    challengehandlers.NewChallengeHandler(log, cs),
+		announcementHandler: announcementhandlers.NewAnnouncementHandler(log, as),
+		moderationHandler:   moderationhandlers.NewModerationHandler(log, mods),
+		locationHandler:     locationhandlers.NewLocationHandler(log, ls),
+		metrics:             registry,
+		tracer:              tracer,
+		adminHandler:        adminhandlers.NewAdminHandler(log, levels),
+		idempotency:         idempotency,
+		idempotencyTTL:      idempotencyTTL,
+		adminToken:          adminToken,
+		auth:                auth,
 	}
 
 	return routes.Routes()
@@ -32,28 +92,159 @@ func (r *Router) Routes() *chi.Mux {
 
 	mux.Use(middleware.RequestID)
 	mux.Use(middleware.RealIP)
-	mux.Use(middleware.Logger)
+	if r.tracer != nil {
+		mux.Use(mw.Tracing(r.tracer))
+	}
+	mux.Use(mw.AccessLog(r.logger))
+	if r.metrics != nil {
+		mux.Use(mw.Metrics(r.metrics))
+	}
 	mux.Use(middleware.Recoverer)
+	mux.Use(mw.Locale(i18n.Default()))
+
+	// the retries of the routes that create a resource are deduplicated, the ones of the system
+	// routes are not as their responses carry credentials
+	idempotent := func(next http.Handler) http.Handler { return next }
+	if r.idempotency != nil {
+		idempotent = mw.Idempotency(r.logger, r.idempotency, r.idempotencyTTL)
+	}
+
+	// the listings are posted as the api consumer of the bearer token, never one the request names
+	consumers := mw.Authenticate(r.logger, r.auth, domain.RoleConsumer)
+	// the moderators look after what the others post, the admin moderates too
+	moderating := mw.Authenticate(r.logger, r.auth, domain.RoleModerator, domain.RoleAdmin)
+	// the games are relayed by the arbiters and their boards
+	arbiters := mw.Authenticate(r.logger, r.auth, domain.RoleArbiter, domain.RoleAdmin)
+	// the challenges are accepted and cancelled by the player of the bearer token
+	players := mw.Authenticate(r.logger, r.auth, domain.RolePlayer)
+	// what belongs to a player is changed with their token, the handler checks it is the one of the path
+	playerOrAdmin := mw.Authenticate(r.logger, r.auth, domain.RolePlayer, domain.RoleAdmin)
+
+	if r.metrics != nil {
+		mux.Method(http.MethodGet, "/metrics", r.metrics.Handler())
+	}
+	mux.Get("/livez", r.sysHandler.LivezHandler)
+	mux.Get("/readyz", r.sysHandler.ReadyzHandler)
 
 	mux.Route("/v1", func(v1 chi.Router) {
+		v1.Get("/openapi.json", openapi.SpecHandler)
+		v1.Get("/docs", openapi.DocsHandler)
 		v1.Route("/system", func(v1s chi.Router) {
 			v1s.Get("/health", r.sysHandler.HealthHandler)
 			v1s.Post("/login", r.sysHandler.LoginHandler)
 			v1s.Post("/new-consumer", r.sysHandler.NewConsumerHandler)
 		})
 		v1.Route("/tournament", func(v1t chi.Router) {
+			v1t.Get("/", r.tournamentHandler.ListTournamentsHandler)
 			v1t.Get("/find/{id}", r.tournamentHandler.FindTournamentHandler)
-			v1t.Post("/new", r.tournamentHandler.CreateTournamentHandler)
+			v1t.With(consumers, idempotent).Post("/new", r.tournamentHandler.CreateTournamentHandler)
+			v1t.Post("/{id}/players", r.tournamentHandler.RegisterPlayerHandler)
+			v1t.Post("/{id}/rounds", r.tournamentHandler.PairRoundHandler)
+			v1t.With(mw.Authenticate(r.logger, r.auth, domain.RoleConsumer, domain.RoleAdmin)).Post("/{id}/{action}", r.tournamentHandler.TransitionTournamentHandler)
+			v1t.Get("/{id}/live", r.liveHandler.StreamHandler)
+			v1t.Get("/{id}/live/ws", r.liveHandler.WebSocketHandler)
 			// v1t.Post("/tournaments", r.tournamentHandler.CreateTournamentHandler)
 			// v1t.Put("/tournaments/{id}", r.tournamentHandler.UpdateTournamentHandler)
 			// v1t.Delete("/tournaments/{id}", r.tournamentHandler.DeleteTournamentHandler)
 		})
+		v1.Route("/player", func(v1p chi.Router) {
+			v1p.With(idempotent).Post("/new", r.playerHandler.CreatePlayerHandler)
+			v1p.Get("/find/{id}", r.playerHandler.FindPlayerHandler)
+			v1p.With(playerOrAdmin).Put("/{id}", r.playerHandler.UpdatePlayerHandler)
+			v1p.Get("/{id}/ratings", r.playerHandler.RatingHistoryHandler)
+			v1p.With(moderating, idempotent).Post("/{id}/ratings", r.playerHandler.RecordRatingChangeHandler)
+			v1p.Get("/{id}/head-to-head/{opponentID}", r.playerHandler.HeadToHeadHandler)
+		})
 		v1.Route("/match", func(v1m chi.Router) {
+			v1m.Get("/", r.matchHandler.ListMatchesHandler)
+			v1m.With(idempotent).Post("/new", r.matchHandler.CreateMatchHandler)
+			v1m.Get("/find/{id}", r.matchHandler.FindMatchHandler)
+			v1m.Post("/{id}/result", r.matchHandler.RecordResultHandler)
+			v1m.With(arbiters, idempotent).Post("/{id}/relay", r.relayHandler.StartRelayHandler)
+			v1m.Get("/{id}/relay", r.relayHandler.FindRelayHandler)
+			v1m.With(arbiters).Post("/{id}/relay/moves", r.relayHandler.RecordMoveHandler)
+			v1m.With(arbiters).Post("/{id}/relay/finish", r.relayHandler.FinishRelayHandler)
 			// v1m.Get("/matches", r.matchHandler.GetMatchesHandler)
 			// v1m.Post("/matches", r.tournamentHandler.CreateMatchHandler)
 			// v1m.Put("/matches/{id}", r.matchHandler.UpdateMatchHandler)
 			// v1m.Delete("/matches/{id}", r.matchHandler.DeleteMatchHandler)
 		})
+		v1.Route("/challenge", func(v1c chi.Router) {
+			v1c.Get("/", r.challengeHandler.ListChallengesHandler)
+			v1c.With(consumers, idempotent).Post("/new", r.challengeHandler.CreateChallengeHandler)
+			v1c.Get("/find/{id}", r.challengeHandler.FindChallengeHandler)
+			v1c.With(players).Post("/{id}/accept", r.challengeHandler.AcceptChallengeHandler)
+			v1c.With(players).Post("/{id}/cancel", r.challengeHandler.CancelChallengeHandler)
+		})
+		v1.Route("/rating", func(v1r chi.Router) {
+			v1r.Get("/{pool}", r.ratingHandler.ListRatingsHandler)
+			v1r.Get("/{pool}/{playerID}", r.ratingHandler.FindRatingHandler)
+			v1r.With(mw.AdminToken(r.logger, r.adminToken)).Post("/recompute", r.ratingHandler.RecomputeRatingsHandler)
+		})
+		v1.Route("/fide", func(v1f chi.Router) {
+			v1f.With(mw.AdminToken(r.logger, r.adminToken)).Post("/import", r.fideHandler.ImportRatingListHandler)
+			v1f.With(mw.AdminToken(r.logger, r.adminToken)).Get("/import/{id}", r.fideHandler.FindImportHandler)
+			v1f.Get("/player/{fideID}", r.fideHandler.FindRecordHandler)
+			v1f.Get("/candidates/{playerID}", r.fideHandler.MatchCandidatesHandler)
+			v1f.With(mw.AdminToken(r.logger, r.adminToken)).Post("/confirm", r.fideHandler.ConfirmMatchHandler)
+			v1f.With(mw.AdminToken(r.logger, r.adminToken)).Post("/refresh/{tournamentID}", r.fideHandler.RefreshTournamentRatingsHandler)
+		})
+		v1.Route("/announcement", func(v1a chi.Router) {
+			// the announcements are the text of private chats, only the admin reads them
+			v1a.Use(mw.AdminToken(r.logger, r.adminToken))
+			v1a.Post("/import", r.announcementHandler.ImportChatHandler)
+			v1a.Get("/import/{id}", r.announcementHandler.FindImportHandler)
+			v1a.Get("/", r.announcementHandler.ListAnnouncementsHandler)
+			v1a.Get("/find/{id}", r.announcementHandler.FindAnnouncementHandler)
+			v1a.Post("/{id}/confirm", r.announcementHandler.ConfirmAnnouncementHandler)
+			v1a.Post("/{id}/reject", r.announcementHandler.RejectAnnouncementHandler)
+		})
+		v1.Route("/moderation", func(v1m chi.Router) {
+			v1m.With(idempotent).Post("/report", r.moderationHandler.ReportHandler)
+			v1m.Group(func(moderators chi.Router) {
+				moderators.Use(moderating)
+				moderators.Get("/queue", r.moderationHandler.ModerationQueueHandler)
+				moderators.Get("/consumer/{consumerID}", r.moderationHandler.FindStandingHandler)
+				moderators.Post("/consumer/{consumerID}/reinstate", r.moderationHandler.ReinstateConsumerHandler)
+				moderators.Get("/{type}/{id}/history", r.moderationHandler.ModerationHistoryHandler)
+				moderators.Post("/{type}/{id}/{action}", r.moderationHandler.ModerateHandler)
+			})
+		})
+		v1.Route("/location", func(v1l chi.Router) {
+			v1l.Get("/", r.locationHandler.ListLocationsHandler)
+			v1l.With(idempotent).Post("/new", r.locationHandler.CreateLocationHandler)
+			v1l.Post("/duplicates", r.locationHandler.CheckLocationHandler)
+			v1l.Get("/geocode", r.locationHandler.GeocodeHandler)
+			v1l.Get("/reverse", r.locationHandler.ReverseGeocodeHandler)
+			v1l.Get("/find/{id}", r.locationHandler.FindLocationHandler)
+			v1l.With(moderating).Put("/{id}", r.locationHandler.UpdateLocationHandler)
+			v1l.With(moderating).Delete("/{id}", r.locationHandler.DeleteLocationHandler)
+			v1l.Get("/{id}/duplicates", r.locationHandler.FindDuplicatesHandler)
+			v1l.With(moderating).Post("/{id}/merge", r.locationHandler.MergeLocationsHandler)
+		})
+		if r.adminToken != "" {
+			v1.Route("/admin", func(v1ad chi.Router) {
+				v1ad.Use(mw.AdminToken(r.logger, r.adminToken))
+				v1ad.Get("/log-level", r.adminHandler.LogLevelHandler)
+				v1ad.Put("/log-level", r.adminHandler.SetLogLevelHandler)
+			})
+		}
+		v1.Route("/webhook", func(v1w chi.Router) {
+			v1w.Use(consumers)
+			v1w.Get("/", r.webhookHandler.ListWebhooksHandler)
+			v1w.With(idempotent).Post("/new", r.webhookHandler.CreateWebhookHandler)
+			v1w.Get("/find/{id}", r.webhookHandler.FindWebhookHandler)
+			v1w.Delete("/{id}", r.webhookHandler.DeleteWebhookHandler)
+			v1w.Post("/{id}/enable", r.webhookHandler.EnableWebhookHandler)
+			v1w.Get("/{id}/deliveries", r.webhookHandler.ListDeliveriesHandler)
+			v1w.Post("/delivery/{deliveryID}/redeliver", r.webhookHandler.RedeliverHandler)
+		})
+		v1.Route("/notification", func(v1n chi.Router) {
+			v1n.With(playerOrAdmin).Get("/preferences/{playerID}", r.notificationHandler.FindPreferencesHandler)
+			v1n.With(playerOrAdmin).Put("/preferences/{playerID}", r.notificationHandler.UpdatePreferencesHandler)
+			v1n.Get("/unsubscribe/{token}", r.notificationHandler.UnsubscribeHandler)
+			v1n.Post("/unsubscribe/{token}", r.notificationHandler.UnsubscribeHandler)
+		})
 	})
 
 	// TODO: should only print if not in production
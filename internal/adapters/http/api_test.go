@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/openapi"
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/adapters/metrics"
+	"github.com/ctfrancia/maple/internal/adapters/security"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutesAreDocumented fails when a route is mounted without being in the openapi document
+// or the document has a route that is not mounted. The optional routes are all mounted here
+func TestRoutesAreDocumented(t *testing.T) {
+	mux := NewRouter(logger.NewZapLogger("test"), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, metrics.NewRegistry(), nil, nil, nil, 0, "token", security.NewTokenAuthenticator("token", nil))
+
+	var mounted []string
+	err := chi.Walk(mux, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		mounted = append(mounted, method+" "+strings.ReplaceAll(route, "/*/", "/"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for _, route := range openapi.Routes() {
+		documented = append(documented, route.Method+" "+route.Path)
+	}
+
+	sort.Strings(mounted)
+	sort.Strings(documented)
+	assert.Equal(t, mounted, documented, "update openapi.Routes and run go generate ./internal/adapters/http/openapi")
+}
@@ -0,0 +1,84 @@
+// Package adminhandlers are the handlers of the operators of the api
+package adminhandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/admin"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// logLevels are the levels the logger can be set to
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+type AdminHandler struct {
+	levels   ports.LogLeveler
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewAdminHandler(log ports.Logger, levels ports.LogLeveler) ports.AdminHandler {
+	handler := &AdminHandler{
+		levels:   levels,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+func (h *AdminHandler) LogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	env := map[string]dto.LogLevelResponse{
+		"log_level": {Level: h.levels.Level()},
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// SetLogLevelHandler changes the level of the logger until the next restart
+func (h *AdminHandler) SetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	level := strings.ToLower(strings.TrimSpace(req.Level))
+	errors := make(validation.Errors)
+	switch {
+	case level == "":
+		errors["level"] = validation.Required()
+	case !validLevel(level):
+		errors["level"] = validation.OneOf(logLevels...)
+	}
+	if len(errors) > 0 {
+		h.response.FailedValidationResponse(w, r, errors)
+		return
+	}
+
+	previous := h.levels.Level()
+	if err := h.levels.SetLevel(level); err != nil {
+		h.response.ServerErrorResponse(w, r, err)
+		return
+	}
+	h.logger.Warn(r.Context(), "log level changed", ports.String("from", previous), ports.String("to", level))
+
+	env := map[string]dto.LogLevelResponse{
+		"log_level": {Level: h.levels.Level()},
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func validLevel(level string) bool {
+	for _, l := range logLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
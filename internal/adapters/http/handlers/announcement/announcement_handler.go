@@ -0,0 +1,233 @@
+// Package announcementhandlers are the handlers of the chat import and the review of the announcements it finds
+package announcementhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/announcement"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/announcement"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type AnnouncementHandler struct {
+	service  ports.AnnouncementServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewAnnouncementHandler(log ports.Logger, as ports.AnnouncementServicer) ports.AnnouncementHandler {
+	handler := &AnnouncementHandler{
+		service:  as,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// ImportChatHandler queues the import of a chat export file the admin put in the import
+// directory, FindImportHandler tells how it goes
+func (h *AnnouncementHandler) ImportChatHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToImportCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ImportChat(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ImportJobResponse{
+		"import": mapImportJobToDto(result),
+	}
+	headers := http.Header{"Location": []string{"/v1/announcement/import/" + result.ID.String()}}
+
+	h.response.WriteJSON(w, http.StatusAccepted, env, headers)
+}
+
+// FindImportHandler is the entrypoint for how an import of a chat export is going
+func (h *AnnouncementHandler) FindImportHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindImportCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindImport(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ImportJobResponse{
+		"import": mapImportJobToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ListAnnouncementsHandler is the review queue, ?status= lists the reviewed ones
+func (h *AnnouncementHandler) ListAnnouncementsHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.ListAnnouncementsCommand{
+		Status: domain.AnnouncementStatus(r.URL.Query().Get("status")),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListAnnouncements(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.AnnouncementResponse{
+		"announcements": mapAnnouncementsToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *AnnouncementHandler) FindAnnouncementHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.FindAnnouncement(r.Context(), commands.FindAnnouncementCommand{ID: ID})
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.AnnouncementResponse{
+		"announcement": mapAnnouncementToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ConfirmAnnouncementHandler creates the draft tournament, the body is optional and corrects
+// what was read from the message
+func (h *AnnouncementHandler) ConfirmAnnouncementHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ConfirmAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToConfirmCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ConfirmAnnouncement(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.AnnouncementResponse{
+		"announcement": mapAnnouncementToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+func (h *AnnouncementHandler) RejectAnnouncementHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RejectAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.RejectAnnouncementCommand{ID: ID, Note: req.Note}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.RejectAnnouncement(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.AnnouncementResponse{
+		"announcement": mapAnnouncementToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *AnnouncementHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *AnnouncementHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrAnnouncementNotFound),
+		errors.Is(err, domain.ErrImportJobNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrAnnouncementNotPending):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "announcement_not_pending")
+	case errors.Is(err, domain.ErrAnnouncementIncomplete):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "announcement_incomplete")
+	case errors.Is(err, domain.ErrChatExportFormat):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "chat_export_format")
+	case errors.Is(err, domain.ErrImportFileNotFound):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "import_file_not_found")
+	case errors.Is(err, domain.ErrWorkerPoolFull),
+		errors.Is(err, domain.ErrWorkerPoolStopped):
+		w.Header().Set("Retry-After", "1")
+		h.response.ErrorCodeResponse(w, r, http.StatusServiceUnavailable, "service_busy")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,118 @@
+package announcementhandlers
+
+import (
+	"strings"
+	"time"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/announcement"
+	commands "github.com/ctfrancia/maple/internal/application/commands/announcement"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func mapToImportCommand(req dto.ImportChatRequest) commands.ImportChatCommand {
+	return commands.ImportChatCommand{
+		Path:     req.Path,
+		Format:   domain.ChatExportFormat(strings.TrimSpace(req.Format)),
+		Timezone: domain.Timezone(strings.TrimSpace(req.Timezone)),
+	}
+}
+
+func mapToConfirmCommand(ID uuid.UUID, req dto.ConfirmAnnouncementRequest) commands.ConfirmAnnouncementCommand {
+	return commands.ConfirmAnnouncementCommand{
+		ID:          ID,
+		Name:        strings.TrimSpace(req.Name),
+		StartsAt:    req.StartsAt,
+		Venue:       strings.TrimSpace(req.Venue),
+		Address:     strings.TrimSpace(req.Address),
+		City:        strings.TrimSpace(req.City),
+		Fee:         req.Fee,
+		TimeControl: strings.TrimSpace(req.TimeControl),
+		Phone:       strings.TrimSpace(req.Phone),
+		Note:        req.Note,
+	}
+}
+
+func mapSummaryToDto(s domain.ChatImportSummary) dto.ImportSummaryResponse {
+	return dto.ImportSummaryResponse{
+		Format:        string(s.Format),
+		Chat:          s.Chat,
+		Messages:      s.Messages,
+		Announcements: s.Announcements,
+		Duplicates:    s.Duplicates,
+	}
+}
+
+func mapAnnouncementToDto(a domain.Announcement) dto.AnnouncementResponse {
+	xAnnouncement := dto.AnnouncementResponse{
+		ID:       a.PublicID.String(),
+		Source:   string(a.Source),
+		Chat:     a.Chat,
+		Author:   a.Author,
+		PostedAt: a.PostedAt,
+		Text:     a.Text,
+		Language: a.Language,
+		Title:    a.Title,
+		Dates:    make([]string, len(a.Dates)),
+		StartsAt: a.StartsAt,
+		HasTime:  a.HasTime,
+		Place: dto.PlaceResponse{
+			Venue:    a.Venue,
+			Address:  a.Address,
+			City:     a.City,
+			Timezone: string(a.Timezone),
+		},
+		Fee:         a.FeeCents,
+		Free:        a.Free,
+		Phones:      a.Phones,
+		TimeControl: a.TimeControl,
+		Status:      string(a.Status),
+		ReviewNote:  a.ReviewNote,
+	}
+	for i, date := range a.Dates {
+		xAnnouncement.Dates[i] = date.Format("2006-01-02")
+	}
+	if xAnnouncement.Phones == nil {
+		xAnnouncement.Phones = []string{}
+	}
+	if a.TournamentID != uuid.Nil {
+		xAnnouncement.TournamentID = a.TournamentID.String()
+	}
+	if !a.ReviewedAt.IsZero() {
+		xAnnouncement.ReviewedAt = &a.ReviewedAt
+	}
+
+	return xAnnouncement
+}
+
+func mapAnnouncementsToDto(announcements []domain.Announcement) []dto.AnnouncementResponse {
+	result := make([]dto.AnnouncementResponse, len(announcements))
+	for i, a := range announcements {
+		result[i] = mapAnnouncementToDto(a)
+	}
+	return result
+}
+
+func mapImportJobToDto(j domain.ImportJob[domain.ChatImportSummary]) dto.ImportJobResponse {
+	job := dto.ImportJobResponse{
+		ID:         j.ID.String(),
+		Status:     string(j.Status),
+		Error:      j.Error,
+		QueuedAt:   j.QueuedAt,
+		StartedAt:  optionalTime(j.StartedAt),
+		FinishedAt: optionalTime(j.FinishedAt),
+	}
+	if j.Status == domain.ImportJobDone {
+		summary := mapSummaryToDto(j.Summary)
+		job.Summary = &summary
+	}
+
+	return job
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
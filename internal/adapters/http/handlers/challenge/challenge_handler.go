@@ -0,0 +1,282 @@
+// Package challengehandlers are the handlers of the open challenges for casual games
+package challengehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/challenge"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ChallengeHandler struct {
+	service  ports.ChallengeServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewChallengeHandler(log ports.Logger, cs ports.ChallengeServicer) ports.ChallengeHandler {
+	handler := &ChallengeHandler{
+		service:  cs,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+func (h *ChallengeHandler) CreateChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := mapToCreateCommand(req, consumerID)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CreateChallenge(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ChallengeResponse{
+		"challenge": mapChallengeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+func (h *ChallengeHandler) FindChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.FindChallenge(r.Context(), commands.FindChallengeCommand{ID: ID})
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ChallengeResponse{
+		"challenge": mapChallengeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ListChallengesHandler lists the open challenges, e.g. ?lat=41.38&lng=2.17&radius_km=10
+// for the ones near the player or ?player=<id> for the ones the player can accept
+func (h *ChallengeHandler) ListChallengesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cmd := commands.ListChallengesCommand{
+		Category: domain.TimeControlCategory(query.Get("category")),
+	}
+
+	for param, dst := range map[string]**float64{"lat": &cmd.Latitude, "lng": &cmd.Longitude} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{param: validation.InvalidFormat("number")})
+			return
+		}
+		*dst = &parsed
+	}
+	if value := query.Get("radius_km"); value != "" {
+		radius, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{"radius_km": validation.InvalidFormat("number")})
+			return
+		}
+		cmd.RadiusKm = radius
+	}
+	for param, dst := range map[string]*int{"min_rating": &cmd.MinRating, "max_rating": &cmd.MaxRating} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{param: validation.InvalidFormat("integer")})
+			return
+		}
+		*dst = parsed
+	}
+	if player := query.Get("player"); player != "" {
+		playerID, err := uuid.Parse(player)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{"player": validation.InvalidFormat("uuid")})
+			return
+		}
+		cmd.PlayerID = playerID
+	}
+
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListChallenges(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.ChallengeResponse{
+		"challenges": mapChallengesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// AcceptChallengeHandler creates the match of the challenge, a challenge someone else
+// accepted first is a conflict
+func (h *ChallengeHandler) AcceptChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	playerID, ok := h.player(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.AcceptChallengeCommand{ID: ID, PlayerID: playerID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.AcceptChallenge(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ChallengeResponse{
+		"challenge": mapChallengeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *ChallengeHandler) CancelChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	playerID, ok := h.player(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.CancelChallengeCommand{ID: ID, PlayerID: playerID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CancelChallenge(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ChallengeResponse{
+		"challenge": mapChallengeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// consumer is the api consumer the request is authenticated as, the challenges are posted as its own
+func (h *ChallengeHandler) consumer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok || principal.Role != domain.RoleConsumer {
+		h.response.InvalidCredentialsResponse(w, r)
+		return "", false
+	}
+
+	return principal.ID, true
+}
+
+// player is the player the request is authenticated as, the challenges are accepted and
+// cancelled in their name
+func (h *ChallengeHandler) player(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok || principal.Role != domain.RolePlayer {
+		h.response.InvalidCredentialsResponse(w, r)
+		return uuid.Nil, false
+	}
+	playerID, err := uuid.Parse(principal.ID)
+	if err != nil {
+		h.response.InvalidCredentialsResponse(w, r)
+		return uuid.Nil, false
+	}
+
+	return playerID, true
+}
+
+func (h *ChallengeHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *ChallengeHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrChallengeNotFound),
+		errors.Is(err, domain.ErrPlayerNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrChallengeNotOpen):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "challenge_not_open")
+	case errors.Is(err, domain.ErrChallengeOwn):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "challenge_own")
+	case errors.Is(err, domain.ErrChallengeRatingOutOfRange):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "challenge_rating_out_of_range")
+	case errors.Is(err, domain.ErrChallengeWindowPassed):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "challenge_window_passed")
+	case errors.Is(err, domain.ErrChallengeNotOwner):
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "challenge_not_owner")
+	case errors.Is(err, domain.ErrConsumerSuspended):
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "consumer_suspended")
+	case errors.Is(err, domain.ErrConsumerRequired):
+		h.response.InvalidCredentialsResponse(w, r)
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
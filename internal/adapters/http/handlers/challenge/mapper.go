@@ -0,0 +1,84 @@
+package challengehandlers
+
+import (
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/challenge"
+	commands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// mapToCreateCommand maps the request of the consumer, ids that cannot be parsed are left as
+// uuid.Nil so they are reported by the command validation
+func mapToCreateCommand(req dto.CreateChallengeRequest, consumerID string) commands.CreateChallengeCommand {
+	challengerID, _ := uuid.Parse(req.ChallengerID)
+
+	return commands.CreateChallengeCommand{
+		ChallengerID: challengerID,
+		LocationName: req.LocationName,
+		Address:      req.Address,
+		City:         req.City,
+		Country:      req.Country,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Timezone:     domain.Timezone(strings.TrimSpace(req.Timezone)),
+		RadiusKm:     req.RadiusKm,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		TimeControl:  strings.TrimSpace(req.TimeControl),
+		Rated:        req.Rated,
+		MinRating:    req.MinRating,
+		MaxRating:    req.MaxRating,
+		Note:         req.Note,
+		ConsumerID:   consumerID,
+	}
+}
+
+func mapChallengeToDto(c domain.Challenge) dto.ChallengeResponse {
+	xChallenge := dto.ChallengeResponse{
+		ID:           c.PublicID.String(),
+		ChallengerID: c.ChallengerID.String(),
+		Location: dto.ChallengeLocationResponse{
+			Name:      c.Location.Name,
+			Address:   c.Location.Address,
+			City:      c.Location.City,
+			Country:   c.Location.Country,
+			Latitude:  c.Location.Latitude,
+			Longitude: c.Location.Longitude,
+			Timezone:  string(c.Location.Timezone),
+		},
+		RadiusKm:    c.RadiusKm,
+		StartsAt:    c.StartsAt,
+		EndsAt:      c.EndsAt,
+		TimeControl: c.TimeControl.String(),
+		Rated:       c.Rated,
+		MinRating:   c.MinRating,
+		MaxRating:   c.MaxRating,
+		Note:        c.Note,
+		Status:      string(c.Status),
+		ConsumerID:  c.ConsumerID,
+		Moderation:  string(c.Moderation),
+		CreatedAt:   c.CreatedAt,
+	}
+	if c.AcceptedBy != uuid.Nil {
+		xChallenge.AcceptedBy = c.AcceptedBy.String()
+		xChallenge.MatchID = c.MatchID.String()
+	}
+	if !c.AcceptedAt.IsZero() {
+		xChallenge.AcceptedAt = &c.AcceptedAt
+	}
+	if !c.CancelledAt.IsZero() {
+		xChallenge.CancelledAt = &c.CancelledAt
+	}
+
+	return xChallenge
+}
+
+func mapChallengesToDto(challenges []domain.Challenge) []dto.ChallengeResponse {
+	result := make([]dto.ChallengeResponse, len(challenges))
+	for i, c := range challenges {
+		result[i] = mapChallengeToDto(c)
+	}
+	return result
+}
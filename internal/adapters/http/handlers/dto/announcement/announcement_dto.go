@@ -0,0 +1,76 @@
+// Package dto is the data transfer object for the chat import and announcement review REST API
+package dto
+
+import "time"
+
+type ImportChatRequest struct {
+	Path     string `json:"path"`             // relative to the import directory of the server
+	Format   string `json:"format,omitempty"` // whatsapp or telegram, told by the extension when empty
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type ImportSummaryResponse struct {
+	Format        string `json:"format"`
+	Chat          string `json:"chat,omitempty"`
+	Messages      int    `json:"messages"`
+	Announcements int    `json:"announcements"`
+	Duplicates    int    `json:"duplicates"`
+}
+
+// ImportJobResponse is an import running in the background, its summary is set once it is done
+type ImportJobResponse struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"` // queued, running, done or failed
+	Summary    *ImportSummaryResponse `json:"summary,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	QueuedAt   time.Time              `json:"queued_at"`
+	StartedAt  *time.Time             `json:"started_at,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
+
+// ConfirmAnnouncementRequest - every field is optional and corrects what was read from the message
+type ConfirmAnnouncementRequest struct {
+	Name        string     `json:"name,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	Venue       string     `json:"venue,omitempty"`
+	Address     string     `json:"address,omitempty"`
+	City        string     `json:"city,omitempty"`
+	Fee         *int64     `json:"fee,omitempty"` // in cents
+	TimeControl string     `json:"time_control,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	Note        string     `json:"note,omitempty"`
+}
+
+type RejectAnnouncementRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type AnnouncementResponse struct {
+	ID           string        `json:"id"`
+	Source       string        `json:"source"`
+	Chat         string        `json:"chat,omitempty"`
+	Author       string        `json:"author"`
+	PostedAt     time.Time     `json:"posted_at"`
+	Text         string        `json:"text"`
+	Language     string        `json:"language,omitempty"`
+	Title        string        `json:"title"`
+	Dates        []string      `json:"dates"` // 2006-01-02
+	StartsAt     time.Time     `json:"starts_at"`
+	HasTime      bool          `json:"has_time"`
+	Place        PlaceResponse `json:"place"`
+	Fee          int64         `json:"fee"` // in cents
+	Free         bool          `json:"free"`
+	Phones       []string      `json:"phones"`
+	TimeControl  string        `json:"time_control,omitempty"`
+	Status       string        `json:"status"`
+	TournamentID string        `json:"tournament_id,omitempty"`
+	ReviewNote   string        `json:"review_note,omitempty"`
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+}
+
+type PlaceResponse struct {
+	Venue    string `json:"venue,omitempty"`
+	Address  string `json:"address,omitempty"`
+	City     string `json:"city,omitempty"`
+	Timezone string `json:"timezone"`
+}
@@ -0,0 +1,55 @@
+// Package dto is the data transfer object for the open challenges REST API
+package dto
+
+import "time"
+
+type CreateChallengeRequest struct {
+	ChallengerID string    `json:"challenger_id"`
+	LocationName string    `json:"location_name,omitempty"`
+	Address      string    `json:"address,omitempty"`
+	City         string    `json:"city,omitempty"`
+	Country      string    `json:"country,omitempty"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	Timezone     string    `json:"timezone,omitempty"`
+	RadiusKm     float64   `json:"radius_km,omitempty"` // how far from the location the challenger is willing to play
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	TimeControl  string    `json:"time_control"`
+	Rated        bool      `json:"rated"`
+	MinRating    int       `json:"min_rating,omitempty"`
+	MaxRating    int       `json:"max_rating,omitempty"`
+	Note         string    `json:"note,omitempty"`
+}
+
+type ChallengeLocationResponse struct {
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone,omitempty"`
+}
+
+type ChallengeResponse struct {
+	ID           string                    `json:"id"`
+	ChallengerID string                    `json:"challenger_id"`
+	Location     ChallengeLocationResponse `json:"location"`
+	RadiusKm     float64                   `json:"radius_km"`
+	StartsAt     time.Time                 `json:"starts_at"`
+	EndsAt       time.Time                 `json:"ends_at"`
+	TimeControl  string                    `json:"time_control"`
+	Rated        bool                      `json:"rated"`
+	MinRating    int                       `json:"min_rating,omitempty"`
+	MaxRating    int                       `json:"max_rating,omitempty"`
+	Note         string                    `json:"note,omitempty"`
+	Status       string                    `json:"status"`
+	ConsumerID   string                    `json:"consumer_id,omitempty"`
+	Moderation   string                    `json:"moderation"`
+	AcceptedBy   string                    `json:"accepted_by,omitempty"`
+	MatchID      string                    `json:"match_id,omitempty"`
+	AcceptedAt   *time.Time                `json:"accepted_at,omitempty"`
+	CancelledAt  *time.Time                `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+// Package dto is the data transfer object for the fide rating list REST API
+package dto
+
+import (
+	"time"
+
+	playerdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+)
+
+type ImportRatingListRequest struct {
+	Path       string `json:"path"`   // relative to the import directory of the server
+	Format     string `json:"format"` // xml or txt
+	Period     string `json:"period"` // e.g. 2026-10
+	RatingType string `json:"rating_type,omitempty"`
+}
+
+type ImportSummaryResponse struct {
+	Period   string `json:"period"`
+	Read     int    `json:"read"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	Skipped  int    `json:"skipped"`
+}
+
+// ImportJobResponse is an import running in the background, its summary is set once it is done
+type ImportJobResponse struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"` // queued, running, done or failed
+	Summary    *ImportSummaryResponse `json:"summary,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	QueuedAt   time.Time              `json:"queued_at"`
+	StartedAt  *time.Time             `json:"started_at,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
+
+type RecordResponse struct {
+	FideID     int               `json:"fide_id"`
+	Name       string            `json:"name"`
+	Federation string            `json:"federation"`
+	Sex        string            `json:"sex,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	BirthYear  int               `json:"birth_year,omitempty"`
+	Ratings    playerdto.Ratings `json:"ratings"`
+	Period     string            `json:"period"`
+	URL        string            `json:"url"`
+}
+
+type CandidateResponse struct {
+	Record RecordResponse `json:"record"`
+	Score  float64        `json:"score"`
+}
+
+type ConfirmMatchRequest struct {
+	PlayerID string `json:"player_id"`
+	FideID   int    `json:"fide_id"`
+}
+
+// LinkedPlayerResponse is the player's fide profile once linked, see the player api for the full profile
+type LinkedPlayerResponse struct {
+	PlayerID string         `json:"player_id"`
+	FIDE     playerdto.Fide `json:"fide"`
+}
+
+type RefreshRequest struct {
+	Period string `json:"period,omitempty"` // defaults to the latest imported
+}
+
+type RefreshResponse struct {
+	PlayersRefreshed int `json:"players_refreshed"`
+}
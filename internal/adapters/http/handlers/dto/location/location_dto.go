@@ -0,0 +1,69 @@
+// Package dto is the data transfer object for the venue registry REST API
+package dto
+
+import "time"
+
+// LocationRequest is the body of the creation, the update and the duplicates check of a venue
+type LocationRequest struct {
+	Name       string  `json:"name"`
+	Address    string  `json:"address,omitempty"`
+	PostalCode string  `json:"postal_code,omitempty"`
+	City       string  `json:"city"`
+	State      string  `json:"state,omitempty"`
+	County     string  `json:"county,omitempty"`
+	Province   string  `json:"province,omitempty"`
+	Country    string  `json:"country,omitempty"`
+	Latitude   float64 `json:"latitude,omitempty"`
+	Longitude  float64 `json:"longitude,omitempty"`
+	Timezone   string  `json:"timezone,omitempty"` // IANA zone, e.g. Europe/Madrid
+	Force      bool    `json:"force,omitempty"`    // register it even when a similar venue exists
+}
+
+type MergeLocationsRequest struct {
+	Into string `json:"into"` // public uuid of the location that is kept
+}
+
+type LocationResponse struct {
+	ID         string    `json:"id"` // public uuid
+	Name       string    `json:"name"`
+	Address    string    `json:"address,omitempty"`
+	PostalCode string    `json:"postal_code,omitempty"`
+	City       string    `json:"city"`
+	State      string    `json:"state,omitempty"`
+	County     string    `json:"county,omitempty"`
+	Province   string    `json:"province,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	Latitude   float64   `json:"latitude,omitempty"`
+	Longitude  float64   `json:"longitude,omitempty"`
+	Timezone   string    `json:"timezone,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type DuplicateResponse struct {
+	Location   LocationResponse `json:"location"`
+	Score      float64          `json:"score"`                 // from 0 to 1
+	DistanceKm *float64         `json:"distance_km,omitempty"` // omitted when either has no coordinates
+}
+
+type MergeResponse struct {
+	Location    LocationResponse `json:"location"`
+	Merged      string           `json:"merged"` // public uuid of the removed duplicate
+	Tournaments int              `json:"tournaments"`
+	Matches     int              `json:"matches"`
+}
+
+// PlaceResponse is an address resolved by the geocoder
+type PlaceResponse struct {
+	Name       string  `json:"name,omitempty"`
+	PostalCode string  `json:"postal_code,omitempty"`
+	City       string  `json:"city,omitempty"`
+	County     string  `json:"county,omitempty"`
+	Province   string  `json:"province,omitempty"`
+	State      string  `json:"state,omitempty"`
+	Country    string  `json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Timezone   string  `json:"timezone,omitempty"`
+	Precision  string  `json:"precision"` // street, postal_code or city
+}
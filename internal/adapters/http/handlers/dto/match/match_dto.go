@@ -0,0 +1,52 @@
+// Package dto is the data transfer object for the match REST API
+package dto
+
+import (
+	"time"
+)
+
+type CreateMatchRequest struct {
+	TournamentID string `json:"tournament_id,omitempty"` // public uuid
+	WhitePlayer  string `json:"white_player"`            // public uuid
+	BlackPlayer  string `json:"black_player"`            // public uuid
+	Rated        bool   `json:"rated"`
+	RatingType   string `json:"rating_type,omitempty"` // standard, rapid or blitz
+	TimeControl  string `json:"time_control"`          // e.g. 90+30, optional for tournament games
+	LocationID   string `json:"location_id,omitempty"` // public uuid of a registered venue
+}
+
+type RecordResultRequest struct {
+	Result string `json:"result"` // 1-0, 0-1 or 1/2-1/2
+}
+
+type MatchResponse struct {
+	ID           string       `json:"id"`                      // public uuid
+	TournamentID string       `json:"tournament_id,omitempty"` // public uuid
+	WhitePlayer  string       `json:"white_player"`            // public uuid
+	BlackPlayer  string       `json:"black_player"`            // public uuid
+	Rated        bool         `json:"rated"`
+	RatingType   string       `json:"rating_type"`
+	TimeControl  *TimeControl `json:"time_control,omitempty"`
+	LocationID   string       `json:"location_id,omitempty"` // public uuid of the venue
+	Location     string       `json:"location,omitempty"`    // name of the venue
+	Result       string       `json:"result"`
+	PGN          string       `json:"pgn,omitempty"`
+	CompletedAt  *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// TimeControl is the time each player gets, Notation is the format accepted by the api
+type TimeControl struct {
+	Notation string              `json:"notation"` // e.g. 90/40+30, 30+30
+	PGN      string              `json:"pgn"`      // value of the PGN TimeControl tag
+	Category string              `json:"category"` // bullet, blitz, rapid or classical
+	Periods  []TimeControlPeriod `json:"periods"`
+}
+
+type TimeControlPeriod struct {
+	Moves            int `json:"moves,omitempty"` // omitted for the rest of the game
+	BaseSeconds      int `json:"base_seconds"`
+	IncrementSeconds int `json:"increment_seconds,omitempty"`
+	DelaySeconds     int `json:"delay_seconds,omitempty"`
+}
@@ -0,0 +1,71 @@
+// Package dto is the data transfer object for the moderation REST API
+package dto
+
+import "time"
+
+type ReportRequest struct {
+	Type       string `json:"type"` // tournament, match, player or challenge
+	ID         string `json:"id"`
+	ReporterID string `json:"reporter_id,omitempty"`
+	Reason     string `json:"reason"` // spam, offensive, incorrect, duplicate or other
+	Details    string `json:"details,omitempty"`
+}
+
+// ModeratorRequest is the body of the actions of a moderator, who they are is their token
+type ModeratorRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type SubjectResponse struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type ReportResponse struct {
+	ID         string          `json:"id"`
+	Subject    SubjectResponse `json:"subject"`
+	ConsumerID string          `json:"consumer_id,omitempty"`
+	ReporterID string          `json:"reporter_id,omitempty"`
+	Reason     string          `json:"reason"`
+	Details    string          `json:"details,omitempty"`
+	Status     string          `json:"status"`
+	ResolvedAt *time.Time      `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type QueueItemResponse struct {
+	Subject    SubjectResponse  `json:"subject"`
+	Title      string           `json:"title"`
+	ConsumerID string           `json:"consumer_id,omitempty"`
+	Moderation string           `json:"moderation"`
+	Reports    []ReportResponse `json:"reports"`
+	Since      time.Time        `json:"since"`
+}
+
+type DecisionResponse struct {
+	ID        string          `json:"id"`
+	Subject   SubjectResponse `json:"subject"`
+	Action    string          `json:"action"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Moderator string          `json:"moderator"`
+	Note      string          `json:"note,omitempty"`
+	Reports   int             `json:"reports"` // the open reports it resolved
+	At        time.Time       `json:"at"`
+}
+
+type HistoryResponse struct {
+	Subject    SubjectResponse    `json:"subject"`
+	Moderation string             `json:"moderation"`
+	Reports    []ReportResponse   `json:"reports"`
+	Decisions  []DecisionResponse `json:"decisions"`
+}
+
+type StandingResponse struct {
+	ConsumerID       string     `json:"consumer_id"`
+	Status           string     `json:"status"`
+	ApprovedListings int        `json:"approved_listings"`
+	UpheldReports    int        `json:"upheld_reports"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedReason  string     `json:"suspended_reason,omitempty"`
+}
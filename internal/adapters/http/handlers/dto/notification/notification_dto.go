@@ -0,0 +1,18 @@
+// Package dto is the data transfer object for the notification REST API
+package dto
+
+import "time"
+
+type UpdatePreferencesRequest struct {
+	Locale       string   `json:"locale,omitempty"` // the default locale when empty
+	Muted        []string `json:"muted"`            // notification kinds the player does not want
+	Unsubscribed bool     `json:"unsubscribed"`     // no emails at all
+}
+
+type PreferencesResponse struct {
+	PlayerID     string     `json:"player_id"`
+	Locale       string     `json:"locale"`
+	Muted        []string   `json:"muted"`
+	Unsubscribed bool       `json:"unsubscribed"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"` // omit if never chosen
+}
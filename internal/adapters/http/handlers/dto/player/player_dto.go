@@ -0,0 +1,97 @@
+// Package dto is the data transfer object for the player REST API
+package dto
+
+import (
+	"time"
+)
+
+type Ratings struct {
+	Standard int `json:"standard"`
+	Rapid    int `json:"rapid"`
+	Blitz    int `json:"blitz"`
+}
+
+type CreatePlayerRequest struct {
+	Username      string  `json:"username"`
+	Email         string  `json:"email"`
+	FirstName     string  `json:"first_name"`
+	LastName      string  `json:"last_name"`
+	Website       string  `json:"website,omitempty"`
+	FideTitle     string  `json:"fide_title,omitempty"`
+	FideRatings   Ratings `json:"fide_ratings"`
+	RegionalTitle string  `json:"regional_title,omitempty"`
+	Country       string  `json:"country,omitempty"`
+	City          string  `json:"city,omitempty"`
+	Ratings       Ratings `json:"ratings"` // regional ratings
+}
+
+// UpdatePlayerRequest only updates the fields that are present in the request
+type UpdatePlayerRequest struct {
+	Username      *string `json:"username,omitempty"`
+	Email         *string `json:"email,omitempty"`
+	FirstName     *string `json:"first_name,omitempty"`
+	LastName      *string `json:"last_name,omitempty"`
+	Website       *string `json:"website,omitempty"`
+	FideTitle     *string `json:"fide_title,omitempty"`
+	RegionalTitle *string `json:"regional_title,omitempty"`
+	Country       *string `json:"country,omitempty"`
+	City          *string `json:"city,omitempty"`
+}
+
+type RecordRatingChangeRequest struct {
+	Source     string    `json:"source"` // fide or regional, the maple ratings follow the results
+	Type       string    `json:"type"`   // standard, rapid or blitz
+	EventID    string    `json:"event_id,omitempty"`
+	EventName  string    `json:"event_name,omitempty"`
+	Rating     int       `json:"rating"`
+	RecordedAt time.Time `json:"recorded_at,omitempty"`
+}
+
+// PlayerProfileResponse is the public profile of a player, private data such as the email is never exposed
+type PlayerProfileResponse struct {
+	ID        string    `json:"id"` // public uuid
+	Username  string    `json:"username"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Website   string    `json:"website,omitempty"`
+	Club      string    `json:"club,omitempty"`
+	FIDE      Fide      `json:"fide"`
+	Regional  Regional  `json:"regional"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Fide struct {
+	ID         int     `json:"id,omitempty"` // 0 until the player is linked to a fide id
+	Federation string  `json:"federation,omitempty"`
+	Title      string  `json:"title,omitempty"`
+	URL        string  `json:"url,omitempty"`
+	Ratings    Ratings `json:"ratings"`
+}
+
+type Regional struct {
+	Title   string  `json:"title,omitempty"`
+	Country string  `json:"country,omitempty"`
+	City    string  `json:"city,omitempty"`
+	Ratings Ratings `json:"ratings"`
+}
+
+type RatingChangeResponse struct {
+	Source     string    `json:"source"`
+	Type       string    `json:"type"`
+	EventID    string    `json:"event_id,omitempty"`
+	EventName  string    `json:"event_name,omitempty"`
+	Previous   int       `json:"previous"`
+	Rating     int       `json:"rating"`
+	Delta      int       `json:"delta"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+type HeadToHeadResponse struct {
+	PlayerID   string   `json:"player_id"`
+	OpponentID string   `json:"opponent_id"`
+	Wins       int      `json:"wins"`
+	Losses     int      `json:"losses"`
+	Draws      int      `json:"draws"`
+	Matches    []string `json:"matches"` // public uuids
+}
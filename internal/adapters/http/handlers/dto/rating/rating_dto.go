@@ -0,0 +1,20 @@
+// Package dto is the data transfer object for the maple rating REST API
+package dto
+
+import (
+	"time"
+)
+
+type RatingResponse struct {
+	PlayerID    string    `json:"player_id"` // public uuid
+	Pool        string    `json:"pool"`
+	Rating      int       `json:"rating"`
+	Deviation   float64   `json:"deviation,omitempty"`
+	Games       int       `json:"games"`
+	Provisional bool      `json:"provisional"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type RecomputeResponse struct {
+	MatchesReplayed int `json:"matches_replayed"`
+}
@@ -0,0 +1,44 @@
+// Package dto is the data transfer object for the live relay REST API
+package dto
+
+import "time"
+
+type StartRelayRequest struct {
+	FEN string `json:"fen,omitempty"` // the standard initial position when empty
+}
+
+type RecordMoveRequest struct {
+	Move    string `json:"move"`               // e.g. "Nf3" or "g1f3"
+	Ply     int    `json:"ply,omitempty"`      // number of the move in the game, to detect repeats and gaps
+	ClockMs *int64 `json:"clock_ms,omitempty"` // what the clock shows for the player after the move
+}
+
+type FinishRelayRequest struct {
+	Result      string `json:"result"`
+	Termination string `json:"termination"`
+}
+
+type RelayMoveResponse struct {
+	Ply          int       `json:"ply"`
+	SAN          string    `json:"san"`
+	UCI          string    `json:"uci"`
+	FEN          string    `json:"fen"`
+	WhiteClockMs int64     `json:"white_clock_ms"`
+	BlackClockMs int64     `json:"black_clock_ms"`
+	At           time.Time `json:"at"`
+}
+
+type RelayResponse struct {
+	MatchID      string              `json:"match_id"`
+	TournamentID string              `json:"tournament_id,omitempty"`
+	Status       string              `json:"status"`
+	InitialFEN   string              `json:"initial_fen"`
+	FEN          string              `json:"fen"`
+	Moves        []RelayMoveResponse `json:"moves"`
+	WhiteClockMs int64               `json:"white_clock_ms"`
+	BlackClockMs int64               `json:"black_clock_ms"`
+	Result       string              `json:"result"`
+	Termination  string              `json:"termination,omitempty"`
+	StartedAt    time.Time           `json:"started_at"`
+	FinishedAt   *time.Time          `json:"finished_at,omitempty"`
+}
@@ -6,8 +6,39 @@ import (
 )
 
 type CreateTournamentRequest struct {
-	Name     string     `json:"name"`
-	Schedule []Schedule `json:"schedule,omitempty"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description,omitempty"`
+	Descriptions map[string]string `json:"descriptions,omitempty"` // translations of the description by locale, e.g. "ca"
+	Schedule     []Schedule        `json:"schedule,omitempty"`
+	TimeControl  string            `json:"time_control"`          // e.g. 90+30 or 90/40+30, 30+30
+	LocationID   string            `json:"location_id,omitempty"` // public uuid of a registered venue
+}
+
+// TransitionTournamentRequest is the body of the action endpoints such as POST /{id}/start, the
+// actor is who the request is authenticated as
+type TransitionTournamentRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// RegisterPlayerRequest is the body of POST /{id}/players
+type RegisterPlayerRequest struct {
+	PlayerID string `json:"player_id"` // public uuid
+}
+
+// PairRoundRequest is the body of POST /{id}/rounds, the pairings of the next round
+type PairRoundRequest struct {
+	Pairings []Pairing `json:"pairings"`
+}
+
+type Pairing struct {
+	White string `json:"white"` // public uuid
+	Black string `json:"black"` // public uuid
+}
+
+// RoundResponse is a paired round and its matches
+type RoundResponse struct {
+	Round   int     `json:"round"`
+	Matches []Match `json:"matches"`
 }
 
 type TournamentStatus string
@@ -22,28 +53,37 @@ const (
 )
 
 type TournamentResponse struct {
-	ID                 string           `json:"id"` // public uuid
-	Name               string           `json:"name"`
-	Location           Location         `json:"location"`
-	Description        string           `json:"description"`
-	OpenToPublic       bool             `json:"open_to_public"`
-	OpenToSpectators   bool             `json:"open_to_spectators"`
-	OpenToRegistration bool             `json:"open_to_registration"`
-	Registration       Registration     `json:"registration"`
-	Arbitrator         string           `json:"arbitrator"` // name of the person
-	PairingMethod      string           `json:"pairing_method"`
-	Matches            []Match          `json:"matches,omitempty"`
-	Players            []string         `json:"players,omitempty"` // this will be there public IDS
-	NumberOfPlayers    int              `json:"number_of_players"` // how many are participating
-	Schedule           []Schedule       `json:"schedule,omitempty"`
-	Results            []Result         `json:"results"`
-	Status             TournamentStatus `json:"status"`
-	CreatedAt          time.Time        `json:"created_at"`
-	UpdatedAt          time.Time        `json:"updated_at"`
-	SoftDeletedAt      *time.Time       `json:"soft_deleted_at,omitempty"` // omit if not soft deleted
+	ID                 string            `json:"id"` // public uuid
+	Name               string            `json:"name"`
+	Location           Location          `json:"location"`
+	Description        string            `json:"description"` // in the locale of the request when translated
+	Descriptions       map[string]string `json:"descriptions,omitempty"`
+	OpenToPublic       bool              `json:"open_to_public"`
+	OpenToSpectators   bool              `json:"open_to_spectators"`
+	OpenToRegistration bool              `json:"open_to_registration"`
+	Registration       Registration      `json:"registration"`
+	Arbitrator         string            `json:"arbitrator"` // name of the person
+	PairingMethod      string            `json:"pairing_method"`
+	TimeControl        *TimeControl      `json:"time_control,omitempty"`
+	Matches            []Match           `json:"matches,omitempty"`
+	Players            []string          `json:"players,omitempty"` // this will be there public IDS
+	NumberOfPlayers    int               `json:"number_of_players"` // how many are participating
+	Rounds             int               `json:"rounds"`            // how many have been paired
+	Schedule           []Schedule        `json:"schedule,omitempty"`
+	Results            []Result          `json:"results"`
+	Status             TournamentStatus  `json:"status"`
+	AvailableActions   []string          `json:"available_actions"`
+	Transitions        []Transition      `json:"transitions,omitempty"`
+	ConsumerID         string            `json:"consumer_id,omitempty"`
+	Moderation         string            `json:"moderation"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	SoftDeletedAt      *time.Time        `json:"soft_deleted_at,omitempty"` // omit if not soft deleted
 }
 
 type Location struct {
+	ID         string `json:"id,omitempty"` // public uuid, empty when the venue is not registered
+	Name       string `json:"name,omitempty"`
 	Address    string `json:"address"`
 	City       string `json:"city"`
 	State      string `json:"state"`
@@ -94,3 +134,28 @@ type Payment struct {
 	Amount int64  `json:"amount"`
 	Other  string `json:"other"` // maybe they get a book or a subscription
 }
+
+// TimeControl is the time each player gets, Notation is the format accepted by the api
+type TimeControl struct {
+	Notation string              `json:"notation"` // e.g. 90/40+30, 30+30
+	PGN      string              `json:"pgn"`      // value of the PGN TimeControl tag
+	Category string              `json:"category"` // bullet, blitz, rapid or classical
+	Periods  []TimeControlPeriod `json:"periods"`
+}
+
+type TimeControlPeriod struct {
+	Moves            int `json:"moves,omitempty"` // omitted for the rest of the game
+	BaseSeconds      int `json:"base_seconds"`
+	IncrementSeconds int `json:"increment_seconds,omitempty"`
+	DelaySeconds     int `json:"delay_seconds,omitempty"`
+}
+
+// Transition is a change of status of the tournament
+type Transition struct {
+	Action string    `json:"action"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Actor  string    `json:"actor"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,45 @@
+// Package dto is the data transfer object for the webhook REST API
+package dto
+
+import "time"
+
+type CreateWebhookRequest struct {
+	URL           string   `json:"url"`
+	EventTypes    []string `json:"event_types,omitempty"`    // every type when empty
+	TournamentIDs []string `json:"tournament_ids,omitempty"` // every tournament when empty
+}
+
+type WebhookResponse struct {
+	ID                  string     `json:"id"`
+	ConsumerID          string     `json:"consumer_id"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"secret,omitempty"` // only when the webhook is created
+	EventTypes          []string   `json:"event_types"`
+	TournamentIDs       []string   `json:"tournament_ids"`
+	Status              string     `json:"status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type DeliveryResponse struct {
+	ID            string            `json:"id"`
+	WebhookID     string            `json:"webhook_id"`
+	EventID       string            `json:"event_id"`
+	EventType     string            `json:"event_type"`
+	Status        string            `json:"status"`
+	Attempts      []AttemptResponse `json:"attempts"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"` // only while pending
+	RedeliveryOf  string            `json:"redelivery_of,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type AttemptResponse struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
@@ -0,0 +1,258 @@
+// Package fidehandlers are the handlers for the fide rating list api
+package fidehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/fide"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/fide"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type FideHandler struct {
+	service  ports.FideServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewFideHandler(log ports.Logger, fs ports.FideServicer) ports.FideHandler {
+	handler := &FideHandler{
+		service:  fs,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// ImportRatingListHandler queues the import of a rating list file the admin put in the import
+// directory, FindImportHandler tells how it goes
+func (h *FideHandler) ImportRatingListHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportRatingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToImportCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ImportRatingList(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ImportJobResponse{
+		"import": mapImportJobToDto(result),
+	}
+	headers := http.Header{"Location": []string{"/v1/fide/import/" + result.ID.String()}}
+
+	h.response.WriteJSON(w, http.StatusAccepted, env, headers)
+}
+
+// FindImportHandler is the entrypoint for how an import of a rating list is going
+func (h *FideHandler) FindImportHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindImportCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindImport(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ImportJobResponse{
+		"import": mapImportJobToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// FindRecordHandler is the entrypoint for a fide record, ?period= selects the rating list
+func (h *FideHandler) FindRecordHandler(w http.ResponseWriter, r *http.Request) {
+	fideID, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "fideID")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return
+	}
+
+	cmd := commands.FindRecordCommand{
+		FideID: fideID,
+		Period: r.URL.Query().Get("period"),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindRecord(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RecordResponse{
+		"record": mapRecordToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// MatchCandidatesHandler lists the fide records that could be the player, ?limit= caps the list
+func (h *FideHandler) MatchCandidatesHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, ok := h.parseID(w, r, "playerID")
+	if !ok {
+		return
+	}
+
+	cmd := commands.MatchCandidatesCommand{PlayerID: playerID}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{"limit": validation.InvalidFormat("integer")})
+			return
+		}
+		cmd.Limit = n
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.MatchCandidates(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.CandidateResponse{
+		"candidates": mapCandidatesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ConfirmMatchHandler links a player to a fide id
+func (h *FideHandler) ConfirmMatchHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ConfirmMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToConfirmCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ConfirmMatch(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.LinkedPlayerResponse{
+		"player": mapLinkedPlayerToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RefreshTournamentRatingsHandler sets the entrants' fide ratings to a rating period, the body is optional
+func (h *FideHandler) RefreshTournamentRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := h.parseID(w, r, "tournamentID")
+	if !ok {
+		return
+	}
+
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.RefreshTournamentRatingsCommand{
+		TournamentID: tournamentID,
+		Period:       req.Period,
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	refreshed, err := h.service.RefreshTournamentRatings(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RefreshResponse{
+		"refresh": {PlayersRefreshed: refreshed},
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// parseID reads a uuid from the url, writing the error response if it is not valid
+func (h *FideHandler) parseID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, param)))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *FideHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrFideRecordNotFound),
+		errors.Is(err, domain.ErrImportJobNotFound),
+		errors.Is(err, domain.ErrPlayerNotFound),
+		errors.Is(err, domain.ErrTournamentNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrFideAlreadyLinked):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "fide_already_linked")
+	case errors.Is(err, domain.ErrFidePeriodNotImported):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "fide_period_not_imported")
+	case errors.Is(err, domain.ErrImportFileNotFound):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "import_file_not_found")
+	case errors.Is(err, domain.ErrFideListFormat):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "fide_list_format")
+	case errors.Is(err, domain.ErrWorkerPoolFull),
+		errors.Is(err, domain.ErrWorkerPoolStopped):
+		w.Header().Set("Retry-After", "1")
+		h.response.ErrorCodeResponse(w, r, http.StatusServiceUnavailable, "service_busy")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,101 @@
+package fidehandlers
+
+import (
+	"time"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/fide"
+	playerdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+	commands "github.com/ctfrancia/maple/internal/application/commands/fide"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func mapToImportCommand(req dto.ImportRatingListRequest) commands.ImportRatingListCommand {
+	return commands.ImportRatingListCommand{
+		Path:       req.Path,
+		Format:     domain.FideListFormat(req.Format),
+		Period:     req.Period,
+		RatingType: domain.RatingType(req.RatingType),
+	}
+}
+
+// mapToConfirmCommand maps the request, an invalid player id is left nil for the command to reject
+func mapToConfirmCommand(req dto.ConfirmMatchRequest) commands.ConfirmMatchCommand {
+	playerID, _ := uuid.Parse(req.PlayerID)
+	return commands.ConfirmMatchCommand{
+		PlayerID: playerID,
+		FideID:   req.FideID,
+	}
+}
+
+func mapSummaryToDto(s domain.FideImportSummary) dto.ImportSummaryResponse {
+	return dto.ImportSummaryResponse{
+		Period:   s.Period,
+		Read:     s.Read,
+		Inserted: s.Inserted,
+		Updated:  s.Updated,
+		Skipped:  s.Skipped,
+	}
+}
+
+func mapImportJobToDto(j domain.ImportJob[domain.FideImportSummary]) dto.ImportJobResponse {
+	job := dto.ImportJobResponse{
+		ID:         j.ID.String(),
+		Status:     string(j.Status),
+		Error:      j.Error,
+		QueuedAt:   j.QueuedAt,
+		StartedAt:  optionalTime(j.StartedAt),
+		FinishedAt: optionalTime(j.FinishedAt),
+	}
+	if j.Status == domain.ImportJobDone {
+		summary := mapSummaryToDto(j.Summary)
+		job.Summary = &summary
+	}
+
+	return job
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func mapRecordToDto(r domain.FideRecord) dto.RecordResponse {
+	return dto.RecordResponse{
+		FideID:     r.FideID,
+		Name:       r.Name,
+		Federation: r.Federation,
+		Sex:        r.Sex,
+		Title:      r.Title,
+		BirthYear:  r.BirthYear,
+		Ratings:    playerdto.Ratings(r.Ratings),
+		Period:     r.Period,
+		URL:        r.ProfileURL(),
+	}
+}
+
+func mapCandidatesToDto(candidates []domain.FideMatchCandidate) []dto.CandidateResponse {
+	xCandidates := make([]dto.CandidateResponse, len(candidates))
+	for i, c := range candidates {
+		xCandidates[i] = dto.CandidateResponse{
+			Record: mapRecordToDto(c.Record),
+			Score:  c.Score,
+		}
+	}
+	return xCandidates
+}
+
+func mapLinkedPlayerToDto(p domain.Player) dto.LinkedPlayerResponse {
+	return dto.LinkedPlayerResponse{
+		PlayerID: p.PublicID.String(),
+		FIDE: playerdto.Fide{
+			ID:         p.FIDE.ID,
+			Federation: p.FIDE.Federation,
+			Title:      p.FIDE.Title,
+			URL:        p.FIDE.URL,
+			Ratings:    playerdto.Ratings(p.FIDE.Ratings),
+		},
+	}
+}
@@ -0,0 +1,264 @@
+// Package livehandlers are the handlers streaming a tournament live to its spectators
+package livehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/live"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// retryAfter is how long an EventSource waits before it reconnects
+	retryAfter = 3 * time.Second
+	// resetType tells the client it missed updates that are no longer in the replay buffer
+	// and has to reload the tournament
+	resetType = "reset"
+	writeWait = 10 * time.Second
+)
+
+type LiveHandler struct {
+	tournaments ports.TournamentServicer
+	hub         *live.Hub
+	upgrader    websocket.Upgrader
+	response    ports.SystemResponder
+	logger      ports.Logger
+}
+
+func NewLiveHandler(log ports.Logger, ts ports.TournamentServicer, hub *live.Hub) ports.LiveHandler {
+	handler := &LiveHandler{
+		tournaments: ts,
+		hub:         hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// the feed is public and read only, any site can embed it
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// StreamHandler streams the updates of the tournament as server sent events. A client that
+// reconnects with the Last-Event-ID header gets the updates it missed first
+func (h *LiveHandler) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	sub, missed, resumed, ok := h.subscribe(w, r, r.Header.Get("Last-Event-ID"))
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	// the stream outlives the write timeout of the server
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Error(r.Context(), "error clearing the write deadline", ports.Error("error", err))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryAfter.Milliseconds())
+	if !resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", resetType)
+	}
+	for _, msg := range missed {
+		writeEvent(w, msg)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.hub.Config().HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, open := <-sub.Messages():
+			// dropped for being too slow or shutting down, the client reconnects and resumes
+			if !open {
+				return
+			}
+			writeEvent(w, msg)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, msg live.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, msg.Data)
+}
+
+// clientMessage is what a websocket client can send, {"type":"ping"} is answered with a pong
+type clientMessage struct {
+	Type string `json:"type"`
+}
+
+// WebSocketHandler streams the updates of the tournament over a websocket, the client resumes
+// with ?last_event_id=
+func (h *LiveHandler) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	sub, missed, resumed, ok := h.subscribe(w, r, r.URL.Query().Get("last_event_id"))
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered the request
+		return
+	}
+	defer conn.Close()
+
+	heartbeatInterval := h.hub.Config().HeartbeatInterval
+	pongWait := 2 * heartbeatInterval
+
+	// the reader only answers the client, every write happens on this goroutine
+	pongs := make(chan struct{}, 1)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			var msg clientMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				var syntaxErr *json.SyntaxError
+				if errors.As(err, &syntaxErr) {
+					continue
+				}
+				return
+			}
+			if msg.Type == "ping" {
+				select {
+				case pongs <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	write := func(v any) error {
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(v)
+	}
+
+	if !resumed {
+		if err := write(clientMessage{Type: resetType}); err != nil {
+			return
+		}
+	}
+	for _, msg := range missed {
+		if err := write(msg); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case msg, open := <-sub.Messages():
+			if !open {
+				reason := "reconnect to resume"
+				if errors.Is(sub.Err(), live.ErrHubClosed) {
+					reason = "server shutting down"
+				}
+				deadline := time.Now().Add(writeWait)
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, reason), deadline)
+				return
+			}
+			if err := write(msg); err != nil {
+				return
+			}
+		case <-pongs:
+			if err := write(clientMessage{Type: "pong"}); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe answers the request itself when the tournament cannot be followed
+func (h *LiveHandler) subscribe(w http.ResponseWriter, r *http.Request, lastEventID string) (*live.Subscription, []live.Message, bool, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return nil, nil, false, false
+	}
+
+	cmd := commands.FindTournamentCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return nil, nil, false, false
+	}
+
+	tournament, err := h.tournaments.FindTournament(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return nil, nil, false, false
+	}
+	if !tournament.OpenToSpectators {
+		h.handleError(w, r, domain.ErrTournamentNotOpenToSpectators)
+		return nil, nil, false, false
+	}
+
+	sub, missed, resumed, err := h.hub.Subscribe(tournament.PublicID, strings.TrimSpace(lastEventID))
+	if err != nil {
+		h.handleError(w, r, err)
+		return nil, nil, false, false
+	}
+
+	return sub, missed, resumed, true
+}
+
+func (h *LiveHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrTournamentNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrTournamentNotOpenToSpectators):
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "tournament_not_open_to_spectators")
+	case errors.Is(err, live.ErrHubClosed):
+		h.response.ErrorCodeResponse(w, r, http.StatusServiceUnavailable, "server_shutting_down")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,211 @@
+package livehandlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/live"
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubTournaments struct {
+	ports.TournamentServicer
+	tournaments map[uuid.UUID]domain.Tournament
+}
+
+func (s stubTournaments) FindTournament(ctx context.Context, cmd commands.FindTournamentCommand) (domain.Tournament, error) {
+	t, ok := s.tournaments[cmd.ID]
+	if !ok {
+		return domain.Tournament{}, domain.ErrTournamentNotFound
+	}
+	return t, nil
+}
+
+type fixture struct {
+	srv     *httptest.Server
+	hub     *live.Hub
+	open    uuid.UUID
+	private uuid.UUID
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	f := &fixture{
+		hub:     live.NewHub(live.HubConfig{ReplaySize: 16, SubscriberBuffer: 16, HeartbeatInterval: 50 * time.Millisecond}),
+		open:    uuid.New(),
+		private: uuid.New(),
+	}
+	ts := stubTournaments{tournaments: map[uuid.UUID]domain.Tournament{
+		f.open:    {PublicID: f.open, OpenToSpectators: true},
+		f.private: {PublicID: f.private},
+	}}
+
+	h := NewLiveHandler(logger.NewZapLogger("test"), ts, f.hub)
+	mux := chi.NewMux()
+	mux.Get("/v1/tournament/{id}/live", h.StreamHandler)
+	mux.Get("/v1/tournament/{id}/live/ws", h.WebSocketHandler)
+
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	t.Cleanup(f.hub.Shutdown)
+
+	return f
+}
+
+func (f *fixture) publish(t *testing.T, n int) {
+	t.Helper()
+	require.NoError(t, f.hub.Publish(domain.LiveUpdate{TournamentID: f.open, Type: domain.LiveStandings, Data: map[string]int{"n": n}}))
+}
+
+// waitSubscribed waits for the handler to subscribe before publishing
+func (f *fixture) waitSubscribed(t *testing.T, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return f.hub.Subscribers(f.open) == n }, time.Second, 5*time.Millisecond)
+}
+
+type sseEvent struct {
+	id, event, data string
+	comment         bool
+}
+
+// readEvent reads the next event of the stream, the retry field is skipped
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if ev != (sseEvent{}) {
+				return ev
+			}
+		case strings.HasPrefix(line, ":"):
+			ev.comment = true
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	f := newFixture(t)
+
+	for name, tt := range map[string]struct {
+		path   string
+		status int
+	}{
+		"unknown tournament":    {path: "/v1/tournament/" + uuid.NewString() + "/live", status: http.StatusNotFound},
+		"closed to spectators":  {path: "/v1/tournament/" + f.private.String() + "/live", status: http.StatusForbidden},
+		"invalid tournament id": {path: "/v1/tournament/nope/live", status: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := http.Get(f.srv.URL + tt.path)
+			require.NoError(t, err)
+			res.Body.Close()
+			assert.Equal(t, tt.status, res.StatusCode)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.srv.URL+"/v1/tournament/"+f.open.String()+"/live", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	body := bufio.NewReader(res.Body)
+	f.waitSubscribed(t, 1)
+	f.publish(t, 1)
+	f.publish(t, 2)
+
+	first := readEvent(t, body)
+	assert.Equal(t, domain.LiveStandings, first.event)
+	assert.JSONEq(t, `{"n":1}`, first.data)
+	readEvent(t, body)
+
+	// nothing published, the connection is kept alive
+	assert.True(t, readEvent(t, body).comment)
+
+	// the client goes away and comes back with the last id it received
+	cancel()
+	f.waitSubscribed(t, 0)
+	f.publish(t, 3)
+
+	req, err = http.NewRequest(http.MethodGet, f.srv.URL+"/v1/tournament/"+f.open.String()+"/live", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", first.id)
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body = bufio.NewReader(res.Body)
+
+	assert.JSONEq(t, `{"n":2}`, readEvent(t, body).data)
+	assert.JSONEq(t, `{"n":3}`, readEvent(t, body).data)
+
+	// shutting down ends the stream
+	f.hub.Shutdown()
+	_, err = body.ReadString('\n')
+	for err == nil {
+		_, err = body.ReadString('\n')
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	f := newFixture(t)
+	url := "ws" + strings.TrimPrefix(f.srv.URL, "http") + "/v1/tournament/" + f.open.String() + "/live/ws"
+
+	_, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(f.srv.URL, "http")+"/v1/tournament/"+f.private.String()+"/live/ws", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	f.waitSubscribed(t, 1)
+
+	f.publish(t, 1)
+	var msg live.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, domain.LiveStandings, msg.Type)
+	assert.JSONEq(t, `{"n":1}`, string(msg.Data))
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"type": "ping"}))
+	var pong clientMessage
+	require.NoError(t, conn.ReadJSON(&pong))
+	assert.Equal(t, "pong", pong.Type)
+
+	// resuming from an id the server no longer has asks the client to reload
+	stale, _, err := websocket.DefaultDialer.Dial(url+"?last_event_id=stale-1", nil)
+	require.NoError(t, err)
+	defer stale.Close()
+	var reset clientMessage
+	require.NoError(t, stale.ReadJSON(&reset))
+	assert.Equal(t, "reset", reset.Type)
+
+	f.hub.Shutdown()
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "expected a going away close, got %v", err)
+}
@@ -0,0 +1,343 @@
+// Package locationhandlers are the handlers of the venue registry
+package locationhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/location"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/location"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type LocationHandler struct {
+	service  ports.LocationServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewLocationHandler(log ports.Logger, ls ports.LocationServicer) ports.LocationHandler {
+	handler := &LocationHandler{
+		service:  ls,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// CreateLocationHandler registers a venue, a similar venue already registered is a conflict
+// unless the request forces it
+func (h *LocationHandler) CreateLocationHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.LocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.CreateLocationCommand{LocationDetails: mapToLocationDetails(req), Force: req.Force}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CreateLocation(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.LocationResponse{
+		"location": mapLocationToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+func (h *LocationHandler) UpdateLocationHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.LocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.UpdateLocationCommand{ID: ID, LocationDetails: mapToLocationDetails(req)}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.UpdateLocation(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.LocationResponse{
+		"location": mapLocationToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *LocationHandler) FindLocationHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindLocationCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindLocation(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.LocationResponse{
+		"location": mapLocationToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ListLocationsHandler searches the registry by ?city=, ?country= and ?q=, part of the name or address
+func (h *LocationHandler) ListLocationsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cmd := commands.ListLocationsCommand{
+		City:    strings.TrimSpace(query.Get("city")),
+		Country: strings.TrimSpace(query.Get("country")),
+		Query:   strings.TrimSpace(query.Get("q")),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListLocations(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.LocationResponse{
+		"locations": mapLocationsToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *LocationHandler) DeleteLocationHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.DeleteLocationCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteLocation(r.Context(), cmd); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckLocationHandler returns the registered venues that look like the one of the body,
+// clients call it before creating one
+func (h *LocationHandler) CheckLocationHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.LocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.CreateLocationCommand{LocationDetails: mapToLocationDetails(req)}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CheckLocation(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.DuplicateResponse{
+		"duplicates": mapDuplicatesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *LocationHandler) FindDuplicatesHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindLocationCommand{ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindDuplicates(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.DuplicateResponse{
+		"duplicates": mapDuplicatesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// MergeLocationsHandler folds the location of the path into the one of the body
+func (h *LocationHandler) MergeLocationsHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.MergeLocationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+	intoID, _ := uuid.Parse(strings.TrimSpace(req.Into))
+
+	cmd := commands.MergeLocationsCommand{ID: ID, IntoID: intoID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.MergeLocations(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.MergeResponse{
+		"merge": mapMergeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// GeocodeHandler resolves ?address=, ?postal_code=, ?city=, ?province= and ?country=
+func (h *LocationHandler) GeocodeHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cmd := commands.GeocodeCommand{
+		Address:    query.Get("address"),
+		PostalCode: query.Get("postal_code"),
+		City:       query.Get("city"),
+		Province:   query.Get("province"),
+		Country:    query.Get("country"),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Geocode(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PlaceResponse{
+		"place": mapPlaceToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ReverseGeocodeHandler names the place at ?lat= and ?lng=
+func (h *LocationHandler) ReverseGeocodeHandler(w http.ResponseWriter, r *http.Request) {
+	var cmd commands.ReverseGeocodeCommand
+	for param, dst := range map[string]*float64{"lat": &cmd.Latitude, "lng": &cmd.Longitude} {
+		value, err := strconv.ParseFloat(r.URL.Query().Get(param), 64)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{param: validation.InvalidFormat("number")})
+			return
+		}
+		*dst = value
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ReverseGeocode(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PlaceResponse{
+		"place": mapPlaceToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *LocationHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *LocationHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrLocationNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrLocationDuplicate):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "location_duplicate")
+	case errors.Is(err, domain.ErrLocationInUse):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "location_in_use")
+	case errors.Is(err, domain.ErrLocationMergeSelf):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "location_merge_self")
+	case errors.Is(err, domain.ErrGeocodeNotFound):
+		h.response.ErrorCodeResponse(w, r, http.StatusNotFound, "geocode_not_found")
+	case errors.Is(err, domain.ErrGeocoderUnavailable):
+		h.response.ErrorCodeResponse(w, r, http.StatusServiceUnavailable, "geocoder_unavailable")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
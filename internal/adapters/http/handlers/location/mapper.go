@@ -0,0 +1,92 @@
+package locationhandlers
+
+import (
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/location"
+	commands "github.com/ctfrancia/maple/internal/application/commands/location"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+func mapToLocationDetails(req dto.LocationRequest) commands.LocationDetails {
+	return commands.LocationDetails{
+		Name:       req.Name,
+		Address:    req.Address,
+		PostalCode: req.PostalCode,
+		City:       req.City,
+		State:      req.State,
+		County:     req.County,
+		Province:   req.Province,
+		Country:    req.Country,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+		Timezone:   domain.Timezone(strings.TrimSpace(req.Timezone)),
+	}
+}
+
+func mapLocationToDto(l domain.Location) dto.LocationResponse {
+	return dto.LocationResponse{
+		ID:         l.PublicID.String(),
+		Name:       l.Name,
+		Address:    l.Address,
+		PostalCode: l.PostalCode,
+		City:       l.City,
+		State:      l.State,
+		County:     l.County,
+		Province:   l.Province,
+		Country:    l.Country,
+		Latitude:   l.Latitude,
+		Longitude:  l.Longitude,
+		Timezone:   string(l.Timezone),
+		CreatedAt:  l.CreatedAt,
+		UpdatedAt:  l.UpdatedAt,
+	}
+}
+
+func mapLocationsToDto(locations []domain.Location) []dto.LocationResponse {
+	xLocations := make([]dto.LocationResponse, len(locations))
+	for i, l := range locations {
+		xLocations[i] = mapLocationToDto(l)
+	}
+	return xLocations
+}
+
+func mapDuplicatesToDto(duplicates []domain.LocationDuplicate) []dto.DuplicateResponse {
+	xDuplicates := make([]dto.DuplicateResponse, len(duplicates))
+	for i, d := range duplicates {
+		xDuplicates[i] = dto.DuplicateResponse{
+			Location: mapLocationToDto(d.Location),
+			Score:    d.Score,
+		}
+		if d.DistanceKm >= 0 {
+			distance := d.DistanceKm
+			xDuplicates[i].DistanceKm = &distance
+		}
+	}
+	return xDuplicates
+}
+
+func mapMergeToDto(m domain.LocationMerge) dto.MergeResponse {
+	return dto.MergeResponse{
+		Location:    mapLocationToDto(m.Location),
+		Merged:      m.Merged.String(),
+		Tournaments: m.Tournaments,
+		Matches:     m.Matches,
+	}
+}
+
+func mapPlaceToDto(p domain.Place) dto.PlaceResponse {
+	return dto.PlaceResponse{
+		Name:       p.Name,
+		PostalCode: p.PostalCode,
+		City:       p.City,
+		County:     p.County,
+		Province:   p.Province,
+		State:      p.State,
+		Country:    p.Country,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+		Timezone:   string(p.Timezone),
+		Precision:  string(p.Precision),
+	}
+}
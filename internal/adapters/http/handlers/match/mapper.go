@@ -0,0 +1,104 @@
+package matchhandlers
+
+import (
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/match"
+	commands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type MatchMapper struct{}
+
+func NewMatchMapper() MatchMapper {
+	return MatchMapper{}
+}
+
+// MapToCreateCommand maps the request, ids that cannot be parsed are left as uuid.Nil
+// so they are reported by the command validation
+func (m MatchMapper) MapToCreateCommand(dto dto.CreateMatchRequest) commands.CreateMatchCommand {
+	tournamentID, _ := uuid.Parse(dto.TournamentID)
+	white, _ := uuid.Parse(dto.WhitePlayer)
+	black, _ := uuid.Parse(dto.BlackPlayer)
+	locationID, _ := uuid.Parse(dto.LocationID)
+
+	return commands.CreateMatchCommand{
+		TournamentID: tournamentID,
+		WhitePlayer:  white,
+		BlackPlayer:  black,
+		Rated:        dto.Rated,
+		RatingType:   domain.RatingType(dto.RatingType),
+		TimeControl:  dto.TimeControl,
+		LocationID:   locationID,
+	}
+}
+
+func (m MatchMapper) MapToFindCommand(ID uuid.UUID) commands.FindMatchCommand {
+	return commands.FindMatchCommand{
+		ID: ID,
+	}
+}
+
+func (m MatchMapper) MapToRecordResultCommand(ID uuid.UUID, dto dto.RecordResultRequest) commands.RecordResultCommand {
+	return commands.RecordResultCommand{
+		ID:     ID,
+		Result: domain.MatchResult(dto.Result),
+	}
+}
+
+func mapMatchToDto(m domain.Match) dto.MatchResponse {
+	resp := dto.MatchResponse{
+		ID:          m.UUID.String(),
+		WhitePlayer: m.WhitePlayer.String(),
+		BlackPlayer: m.BlackPlayer.String(),
+		Rated:       m.Rated,
+		RatingType:  string(m.RatingType),
+		TimeControl: mapTimeControlToDto(m.TimeControl),
+		Result:      string(m.Result),
+		PGN:         m.PGN,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+	if m.TournamentID != uuid.Nil {
+		resp.TournamentID = m.TournamentID.String()
+	}
+	if m.Location.Registered() {
+		resp.LocationID = m.Location.PublicID.String()
+		resp.Location = m.Location.Name
+	}
+	if !m.CompletedAt.IsZero() {
+		completedAt := m.CompletedAt
+		resp.CompletedAt = &completedAt
+	}
+	return resp
+}
+
+func mapMatchesToDto(matches []domain.Match) []dto.MatchResponse {
+	xMatches := make([]dto.MatchResponse, len(matches))
+	for i, m := range matches {
+		xMatches[i] = mapMatchToDto(m)
+	}
+	return xMatches
+}
+
+func mapTimeControlToDto(tc domain.TimeControl) *dto.TimeControl {
+	if tc.IsZero() {
+		return nil
+	}
+
+	periods := make([]dto.TimeControlPeriod, len(tc.Periods))
+	for i, p := range tc.Periods {
+		periods[i] = dto.TimeControlPeriod{
+			Moves:            p.Moves,
+			BaseSeconds:      int(p.Base.Seconds()),
+			IncrementSeconds: int(p.Increment.Seconds()),
+			DelaySeconds:     int(p.Delay.Seconds()),
+		}
+	}
+
+	return &dto.TimeControl{
+		Notation: tc.String(),
+		PGN:      tc.PGN(),
+		Category: string(tc.Category()),
+		Periods:  periods,
+	}
+}
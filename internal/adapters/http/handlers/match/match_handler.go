@@ -0,0 +1,177 @@
+// Package matchhandlers are the handlers for the match api
+package matchhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/match"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type MatchHandler struct {
+	service  ports.MatchServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+	mapper   ports.MatchMapper
+}
+
+func NewMatchHandler(log ports.Logger, ms ports.MatchServicer) ports.MatchHandler {
+	handler := &MatchHandler{
+		service:  ms,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+		mapper:   NewMatchMapper(),
+	}
+
+	return handler
+}
+
+// CreateMatchHandler is the entrypoint for creating a match
+func (h *MatchHandler) CreateMatchHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToCreateCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CreateMatch(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.MatchResponse{
+		"match": mapMatchToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// FindMatchHandler is the entrypoint for finding a match
+func (h *MatchHandler) FindMatchHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.FindMatch(r.Context(), h.mapper.MapToFindCommand(ID))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.MatchResponse{
+		"match": mapMatchToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ListMatchesHandler is the entrypoint for searching matches, filtered by
+// ?player=, ?category= and ?time_control=
+func (h *MatchHandler) ListMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cmd := commands.ListMatchesCommand{
+		Category:    domain.TimeControlCategory(query.Get("category")),
+		TimeControl: query.Get("time_control"),
+	}
+	if player := query.Get("player"); player != "" {
+		playerID, err := uuid.Parse(player)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{"player": validation.InvalidFormat("uuid")})
+			return
+		}
+		cmd.PlayerID = playerID
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListMatches(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.MatchResponse{
+		"matches": mapMatchesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RecordResultHandler is the entrypoint for recording the final result of a match
+func (h *MatchHandler) RecordResultHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RecordResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToRecordResultCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.RecordResult(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.MatchResponse{
+		"match": mapMatchToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *MatchHandler) parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *MatchHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrMatchNotFound),
+		errors.Is(err, domain.ErrPlayerNotFound),
+		errors.Is(err, domain.ErrTournamentNotFound),
+		errors.Is(err, domain.ErrLocationNotFound):
+		h.response.NotFoundResponse(w, r)
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
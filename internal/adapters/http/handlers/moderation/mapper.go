@@ -0,0 +1,114 @@
+package moderationhandlers
+
+import (
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/moderation"
+	commands "github.com/ctfrancia/maple/internal/application/commands/moderation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func mapToReportCommand(req dto.ReportRequest) commands.ReportCommand {
+	ID, _ := uuid.Parse(strings.TrimSpace(req.ID))
+
+	return commands.ReportCommand{
+		Subject:    domain.ModerationSubject{Type: domain.SubjectType(strings.TrimSpace(req.Type)), ID: ID},
+		ReporterID: strings.TrimSpace(req.ReporterID),
+		Reason:     domain.ReportReason(strings.TrimSpace(req.Reason)),
+		Details:    strings.TrimSpace(req.Details),
+	}
+}
+
+func mapSubjectToDto(s domain.ModerationSubject) dto.SubjectResponse {
+	return dto.SubjectResponse{
+		Type: string(s.Type),
+		ID:   s.ID.String(),
+	}
+}
+
+func mapReportToDto(r domain.Report) dto.ReportResponse {
+	xReport := dto.ReportResponse{
+		ID:         r.PublicID.String(),
+		Subject:    mapSubjectToDto(r.Subject),
+		ConsumerID: r.ConsumerID,
+		ReporterID: r.ReporterID,
+		Reason:     string(r.Reason),
+		Details:    r.Details,
+		Status:     string(r.Status),
+		CreatedAt:  r.CreatedAt,
+	}
+	if !r.ResolvedAt.IsZero() {
+		xReport.ResolvedAt = &r.ResolvedAt
+	}
+
+	return xReport
+}
+
+func mapReportsToDto(reports []domain.Report) []dto.ReportResponse {
+	xReports := make([]dto.ReportResponse, len(reports))
+	for i, r := range reports {
+		xReports[i] = mapReportToDto(r)
+	}
+
+	return xReports
+}
+
+func mapQueueToDto(items []domain.ModerationItem) []dto.QueueItemResponse {
+	xItems := make([]dto.QueueItemResponse, len(items))
+	for i, item := range items {
+		xItems[i] = dto.QueueItemResponse{
+			Subject:    mapSubjectToDto(item.Subject),
+			Title:      item.Title,
+			ConsumerID: item.ConsumerID,
+			Moderation: string(item.Status),
+			Reports:    mapReportsToDto(item.Reports),
+			Since:      item.Since,
+		}
+	}
+
+	return xItems
+}
+
+func mapDecisionToDto(d domain.ModerationDecision) dto.DecisionResponse {
+	return dto.DecisionResponse{
+		ID:        d.PublicID.String(),
+		Subject:   mapSubjectToDto(d.Subject),
+		Action:    string(d.Action),
+		From:      string(d.From),
+		To:        string(d.To),
+		Moderator: d.Moderator,
+		Note:      d.Note,
+		Reports:   d.Reports,
+		At:        d.At,
+	}
+}
+
+func mapHistoryToDto(h domain.ModerationHistory) dto.HistoryResponse {
+	xHistory := dto.HistoryResponse{
+		Subject:    mapSubjectToDto(h.Subject),
+		Moderation: string(h.Status),
+		Reports:    mapReportsToDto(h.Reports),
+		Decisions:  make([]dto.DecisionResponse, len(h.Decisions)),
+	}
+	for i, d := range h.Decisions {
+		xHistory.Decisions[i] = mapDecisionToDto(d)
+	}
+
+	return xHistory
+}
+
+func mapStandingToDto(s domain.ConsumerStanding) dto.StandingResponse {
+	xStanding := dto.StandingResponse{
+		ConsumerID:       s.ConsumerID,
+		Status:           string(s.Status),
+		ApprovedListings: s.ApprovedListings,
+		UpheldReports:    s.UpheldReports,
+		SuspendedReason:  s.SuspendedReason,
+	}
+	if !s.SuspendedAt.IsZero() {
+		xStanding.SuspendedAt = &s.SuspendedAt
+	}
+
+	return xStanding
+}
@@ -0,0 +1,251 @@
+// Package moderationhandlers are the handlers of the reports and the moderators' queue
+package moderationhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/moderation"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/moderation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ModerationHandler struct {
+	service  ports.ModerationServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewModerationHandler(log ports.Logger, ms ports.ModerationServicer) ports.ModerationHandler {
+	handler := &ModerationHandler{
+		service:  ms,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// ReportHandler lets anyone flag a tournament, match, player or challenge
+func (h *ModerationHandler) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToReportCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Report(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.ReportResponse{
+		"report": mapReportToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// ModerationQueueHandler is the moderators' queue, ?type= narrows it to one kind of subject
+func (h *ModerationHandler) ModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.ModerationQueueCommand{
+		Type: domain.SubjectType(r.URL.Query().Get("type")),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Queue(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.QueueItemResponse{
+		"queue": mapQueueToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ModerateHandler approves, rejects or hides the subject of the path
+func (h *ModerationHandler) ModerateHandler(w http.ResponseWriter, r *http.Request) {
+	subject, ok := h.parseSubject(w, r)
+	if !ok {
+		return
+	}
+
+	moderator, ok := h.moderator(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ModeratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.ModerateCommand{
+		Subject:   subject,
+		Action:    domain.ModerationAction(chi.URLParam(r, "action")),
+		Moderator: moderator,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Moderate(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.DecisionResponse{
+		"decision": mapDecisionToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *ModerationHandler) ModerationHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	subject, ok := h.parseSubject(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.ModerationHistoryCommand{Subject: subject}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.History(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.HistoryResponse{
+		"history": mapHistoryToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *ModerationHandler) FindStandingHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.FindStandingCommand{ConsumerID: strings.TrimSpace(chi.URLParam(r, "consumerID"))}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindStanding(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.StandingResponse{
+		"standing": mapStandingToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *ModerationHandler) ReinstateConsumerHandler(w http.ResponseWriter, r *http.Request) {
+	moderator, ok := h.moderator(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ModeratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.ReinstateConsumerCommand{
+		ConsumerID: strings.TrimSpace(chi.URLParam(r, "consumerID")),
+		Moderator:  moderator,
+		Note:       strings.TrimSpace(req.Note),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ReinstateConsumer(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.StandingResponse{
+		"standing": mapStandingToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// moderator is who the request is authenticated as, the decisions are recorded as theirs
+func (h *ModerationHandler) moderator(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok {
+		h.response.InvalidCredentialsResponse(w, r)
+		return "", false
+	}
+
+	return principal.ID, true
+}
+
+// parseSubject reads the {type}/{id} of the path, the type is validated with the command
+func (h *ModerationHandler) parseSubject(w http.ResponseWriter, r *http.Request) (domain.ModerationSubject, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return domain.ModerationSubject{}, false
+	}
+
+	return domain.ModerationSubject{Type: domain.SubjectType(chi.URLParam(r, "type")), ID: ID}, true
+}
+
+func (h *ModerationHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrTournamentNotFound),
+		errors.Is(err, domain.ErrMatchNotFound),
+		errors.Is(err, domain.ErrPlayerNotFound),
+		errors.Is(err, domain.ErrChallengeNotFound),
+		errors.Is(err, domain.ErrModerationSubject):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrReportDuplicate):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "report_duplicate")
+	case errors.Is(err, domain.ErrConsumerNotSuspended):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "consumer_not_suspended")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,43 @@
+package notificationhandlers
+
+import (
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/notification"
+	commands "github.com/ctfrancia/maple/internal/application/commands/notification"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/google/uuid"
+)
+
+func mapToUpdateCommand(playerID uuid.UUID, req dto.UpdatePreferencesRequest) commands.UpdatePreferencesCommand {
+	muted := make([]domain.NotificationKind, len(req.Muted))
+	for i, kind := range req.Muted {
+		muted[i] = domain.NotificationKind(kind)
+	}
+
+	return commands.UpdatePreferencesCommand{
+		PlayerID:     playerID,
+		Locale:       i18n.Locale(req.Locale),
+		Muted:        muted,
+		Unsubscribed: req.Unsubscribed,
+	}
+}
+
+// mapPreferencesToDto maps the preferences, the unsubscribe token is only ever sent by email
+func mapPreferencesToDto(p domain.NotificationPreferences) dto.PreferencesResponse {
+	muted := make([]string, len(p.Muted))
+	for i, kind := range p.Muted {
+		muted[i] = string(kind)
+	}
+
+	xPrefs := dto.PreferencesResponse{
+		PlayerID:     p.PlayerID.String(),
+		Locale:       string(p.EmailLocale()),
+		Muted:        muted,
+		Unsubscribed: p.Unsubscribed,
+	}
+	if !p.UpdatedAt.IsZero() {
+		xPrefs.UpdatedAt = &p.UpdatedAt
+	}
+
+	return xPrefs
+}
@@ -0,0 +1,159 @@
+// Package notificationhandlers are the handlers for the email preferences of the players
+package notificationhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/notification"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/notification"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	service  ports.NotificationServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewNotificationHandler(log ports.Logger, ns ports.NotificationServicer) ports.NotificationHandler {
+	handler := &NotificationHandler{
+		service:  ns,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+func (h *NotificationHandler) FindPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, ok := h.playerID(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindPreferencesCommand{PlayerID: playerID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindPreferences(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PreferencesResponse{
+		"preferences": mapPreferencesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *NotificationHandler) UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, ok := h.playerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToUpdateCommand(playerID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.UpdatePreferences(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PreferencesResponse{
+		"preferences": mapPreferencesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// UnsubscribeHandler is the link at the bottom of every email, it answers GET for the players
+// following it and POST for the one click unsubscribe of the mail clients
+func (h *NotificationHandler) UnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.UnsubscribeCommand{Token: strings.TrimSpace(chi.URLParam(r, "token"))}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Unsubscribe(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PreferencesResponse{
+		"preferences": mapPreferencesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// playerID is the player of the url, the preferences are only read and changed with the token of
+// that player or the admin one
+func (h *NotificationHandler) playerID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	playerID, ok := h.parseID(w, r, "playerID")
+	if !ok {
+		return uuid.Nil, false
+	}
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok {
+		h.response.InvalidCredentialsResponse(w, r)
+		return uuid.Nil, false
+	}
+	if !principal.ActsFor(playerID) {
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "forbidden")
+		return uuid.Nil, false
+	}
+
+	return playerID, true
+}
+
+// parseID reads a uuid from the url, writing the error response if it is not valid
+func (h *NotificationHandler) parseID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, param)))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *NotificationHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrPlayerNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrUnsubscribeTokenInvalid):
+		h.response.ErrorCodeResponse(w, r, http.StatusNotFound, "unsubscribe_token_invalid")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,132 @@
+package playerhandlers
+
+import (
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+	commands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type PlayerMapper struct{}
+
+func NewPlayerMapper() PlayerMapper {
+	return PlayerMapper{}
+}
+
+func (m PlayerMapper) MapToCreateCommand(dto dto.CreatePlayerRequest) commands.CreatePlayerCommand {
+	return commands.CreatePlayerCommand{
+		Username:      dto.Username,
+		Email:         dto.Email,
+		FirstName:     dto.FirstName,
+		LastName:      dto.LastName,
+		Website:       dto.Website,
+		FideTitle:     dto.FideTitle,
+		FideRatings:   commands.Ratings(dto.FideRatings),
+		RegionalTitle: dto.RegionalTitle,
+		Country:       dto.Country,
+		City:          dto.City,
+		Ratings:       commands.Ratings(dto.Ratings),
+	}
+}
+
+func (m PlayerMapper) MapToUpdateCommand(ID uuid.UUID, dto dto.UpdatePlayerRequest) commands.UpdatePlayerCommand {
+	return commands.UpdatePlayerCommand{
+		ID:            ID,
+		Username:      dto.Username,
+		Email:         dto.Email,
+		FirstName:     dto.FirstName,
+		LastName:      dto.LastName,
+		Website:       dto.Website,
+		FideTitle:     dto.FideTitle,
+		RegionalTitle: dto.RegionalTitle,
+		Country:       dto.Country,
+		City:          dto.City,
+	}
+}
+
+func (m PlayerMapper) MapToFindCommand(ID uuid.UUID) commands.FindPlayerCommand {
+	return commands.FindPlayerCommand{
+		ID: ID,
+	}
+}
+
+func (m PlayerMapper) MapToRecordRatingChangeCommand(ID uuid.UUID, dto dto.RecordRatingChangeRequest) commands.RecordRatingChangeCommand {
+	// an invalid event id is treated as no event, it is optional
+	eventID, _ := uuid.Parse(dto.EventID)
+
+	return commands.RecordRatingChangeCommand{
+		PlayerID:   ID,
+		Source:     domain.RatingSource(dto.Source),
+		Type:       domain.RatingType(dto.Type),
+		EventID:    eventID,
+		EventName:  dto.EventName,
+		Rating:     dto.Rating,
+		RecordedAt: dto.RecordedAt,
+	}
+}
+
+func mapPlayerToProfileDto(p domain.Player) dto.PlayerProfileResponse {
+	return dto.PlayerProfileResponse{
+		ID:        p.PublicID.String(),
+		Username:  p.Username,
+		FirstName: p.FirstName,
+		LastName:  p.LastName,
+		Website:   p.Website,
+		Club:      p.ClubAffiliation.Name,
+		FIDE: dto.Fide{
+			ID:         p.FIDE.ID,
+			Federation: p.FIDE.Federation,
+			Title:      p.FIDE.Title,
+			URL:        p.FIDE.URL,
+			Ratings:    dto.Ratings(p.FIDE.Ratings),
+		},
+		Regional: dto.Regional{
+			Title:   p.Regional.Title,
+			Country: p.Regional.Country,
+			City:    p.Regional.City,
+			Ratings: dto.Ratings(p.Regional.Ratings),
+		},
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func mapRatingChangeToDto(rc domain.RatingChange) dto.RatingChangeResponse {
+	resp := dto.RatingChangeResponse{
+		Source:     string(rc.Source),
+		Type:       string(rc.Type),
+		EventName:  rc.EventName,
+		Previous:   rc.Previous,
+		Rating:     rc.Rating,
+		Delta:      rc.Delta(),
+		RecordedAt: rc.RecordedAt,
+	}
+	if rc.EventID != uuid.Nil {
+		resp.EventID = rc.EventID.String()
+	}
+	return resp
+}
+
+func mapRatingHistoryToDto(history []domain.RatingChange) []dto.RatingChangeResponse {
+	xHistory := make([]dto.RatingChangeResponse, len(history))
+	for i, rc := range history {
+		xHistory[i] = mapRatingChangeToDto(rc)
+	}
+	return xHistory
+}
+
+func mapHeadToHeadToDto(h domain.HeadToHead) dto.HeadToHeadResponse {
+	matches := make([]string, len(h.Matches))
+	for i, m := range h.Matches {
+		matches[i] = m.UUID.String()
+	}
+
+	return dto.HeadToHeadResponse{
+		PlayerID:   h.PlayerID.String(),
+		OpponentID: h.OpponentID.String(),
+		Wins:       h.Wins,
+		Losses:     h.Losses,
+		Draws:      h.Draws,
+		Matches:    matches,
+	}
+}
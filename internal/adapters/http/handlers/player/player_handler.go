@@ -0,0 +1,245 @@
+// Package playerhandlers are the handlers for the player api
+package playerhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type PlayerHandler struct {
+	service  ports.PlayerServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+	mapper   ports.PlayerMapper
+}
+
+func NewPlayerHandler(log ports.Logger, ps ports.PlayerServicer) ports.PlayerHandler {
+	handler := &PlayerHandler{
+		service:  ps,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+		mapper:   NewPlayerMapper(),
+	}
+
+	return handler
+}
+
+// CreatePlayerHandler is the entrypoint for creating a player profile
+func (h *PlayerHandler) CreatePlayerHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreatePlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToCreateCommand(req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CreatePlayer(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PlayerProfileResponse{
+		"player": mapPlayerToProfileDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// FindPlayerHandler is the entrypoint for the public profile of a player
+func (h *PlayerHandler) FindPlayerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.FindPlayer(r.Context(), h.mapper.MapToFindCommand(ID))
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PlayerProfileResponse{
+		"player": mapPlayerToProfileDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// UpdatePlayerHandler is the entrypoint for updating a player profile, by the player or the admin
+func (h *PlayerHandler) UpdatePlayerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok {
+		h.response.InvalidCredentialsResponse(w, r)
+		return
+	}
+	if !principal.ActsFor(ID) {
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	var req dto.UpdatePlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToUpdateCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.UpdatePlayer(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.PlayerProfileResponse{
+		"player": mapPlayerToProfileDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RecordRatingChangeHandler is the entrypoint for adding a point to a player's rating history
+func (h *PlayerHandler) RecordRatingChangeHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.RecordRatingChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToRecordRatingChangeCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.RecordRatingChange(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RatingChangeResponse{
+		"rating_change": mapRatingChangeToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// RatingHistoryHandler is the entrypoint for a player's rating time series,
+// it can be narrowed down with the ?source= and ?type= query parameters
+func (h *PlayerHandler) RatingHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.RatingHistoryCommand{
+		PlayerID: ID,
+		Source:   domain.RatingSource(r.URL.Query().Get("source")),
+		Type:     domain.RatingType(r.URL.Query().Get("type")),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.RatingHistory(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.RatingChangeResponse{
+		"rating_history": mapRatingHistoryToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// HeadToHeadHandler is the entrypoint for the record between two players
+func (h *PlayerHandler) HeadToHeadHandler(w http.ResponseWriter, r *http.Request) {
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+	opponentID, ok := h.parseID(w, r, "opponentID")
+	if !ok {
+		return
+	}
+
+	cmd := commands.HeadToHeadCommand{
+		PlayerID:   ID,
+		OpponentID: opponentID,
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.HeadToHead(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.HeadToHeadResponse{
+		"head_to_head": mapHeadToHeadToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// parseID reads a uuid from the url, writing the error response if it is not valid
+func (h *PlayerHandler) parseID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, param)))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *PlayerHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrPlayerNotFound):
+		h.response.NotFoundResponse(w, r)
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
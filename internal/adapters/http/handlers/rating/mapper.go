@@ -0,0 +1,26 @@
+package ratinghandlers
+
+import (
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+func mapRatingToDto(r domain.MapleRating) dto.RatingResponse {
+	return dto.RatingResponse{
+		PlayerID:    r.PlayerID.String(),
+		Pool:        string(r.Pool),
+		Rating:      r.Rounded(),
+		Deviation:   r.Deviation,
+		Games:       r.Games,
+		Provisional: r.Provisional,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func mapRatingsToDto(ratings []domain.MapleRating) []dto.RatingResponse {
+	xRatings := make([]dto.RatingResponse, len(ratings))
+	for i, r := range ratings {
+		xRatings[i] = mapRatingToDto(r)
+	}
+	return xRatings
+}
@@ -0,0 +1,111 @@
+// Package ratinghandlers are the handlers for the maple rating api
+package ratinghandlers
+
+import (
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/rating"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type RatingHandler struct {
+	service  ports.RatingServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewRatingHandler(log ports.Logger, rs ports.RatingServicer) ports.RatingHandler {
+	handler := &RatingHandler{
+		service:  rs,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// ListRatingsHandler is the entrypoint for the leaderboard of a pool,
+// provisional ratings are only listed with ?provisional=true
+func (h *RatingHandler) ListRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.ListRatingsCommand{
+		Pool:               domain.RatingType(chi.URLParam(r, "pool")),
+		IncludeProvisional: r.URL.Query().Get("provisional") == "true",
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListRatings(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.RatingResponse{
+		"ratings": mapRatingsToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// FindRatingHandler is the entrypoint for a player's maple rating in a pool
+func (h *RatingHandler) FindRatingHandler(w http.ResponseWriter, r *http.Request) {
+	playerID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "playerID")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return
+	}
+
+	cmd := commands.FindRatingCommand{
+		PlayerID: playerID,
+		Pool:     domain.RatingType(chi.URLParam(r, "pool")),
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindRating(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RatingResponse{
+		"rating": mapRatingToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RecomputeRatingsHandler rebuilds every maple rating from the match history
+func (h *RatingHandler) RecomputeRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	replayed, err := h.service.Recompute(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RecomputeResponse{
+		"recompute": {MatchesReplayed: replayed},
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *RatingHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	h.response.ServerErrorResponse(w, r, err)
+}
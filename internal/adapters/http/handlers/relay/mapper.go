@@ -0,0 +1,68 @@
+package relayhandlers
+
+import (
+	"time"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/relay"
+	commands "github.com/ctfrancia/maple/internal/application/commands/relay"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func mapToRecordMoveCommand(matchID uuid.UUID, req dto.RecordMoveRequest) commands.RecordMoveCommand {
+	cmd := commands.RecordMoveCommand{
+		MatchID: matchID,
+		Move:    req.Move,
+		Ply:     req.Ply,
+	}
+	if req.ClockMs != nil {
+		clock := time.Duration(*req.ClockMs) * time.Millisecond
+		cmd.Clock = &clock
+	}
+
+	return cmd
+}
+
+func mapToFinishCommand(matchID uuid.UUID, req dto.FinishRelayRequest) commands.FinishRelayCommand {
+	return commands.FinishRelayCommand{
+		MatchID:     matchID,
+		Result:      domain.MatchResult(req.Result),
+		Termination: domain.RelayTermination(req.Termination),
+	}
+}
+
+func mapRelayToDto(r domain.Relay) dto.RelayResponse {
+	moves := make([]dto.RelayMoveResponse, len(r.Moves))
+	for i, m := range r.Moves {
+		moves[i] = dto.RelayMoveResponse{
+			Ply:          m.Ply,
+			SAN:          m.SAN,
+			UCI:          m.UCI,
+			FEN:          m.FEN,
+			WhiteClockMs: m.WhiteClock.Milliseconds(),
+			BlackClockMs: m.BlackClock.Milliseconds(),
+			At:           m.At,
+		}
+	}
+
+	xRelay := dto.RelayResponse{
+		MatchID:      r.MatchID.String(),
+		Status:       string(r.Status),
+		InitialFEN:   r.InitialFEN,
+		FEN:          r.FEN,
+		Moves:        moves,
+		WhiteClockMs: r.WhiteClock.Milliseconds(),
+		BlackClockMs: r.BlackClock.Milliseconds(),
+		Result:       string(r.Result),
+		Termination:  string(r.Termination),
+		StartedAt:    r.StartedAt,
+	}
+	if r.TournamentID != uuid.Nil {
+		xRelay.TournamentID = r.TournamentID.String()
+	}
+	if !r.FinishedAt.IsZero() {
+		xRelay.FinishedAt = &r.FinishedAt
+	}
+
+	return xRelay
+}
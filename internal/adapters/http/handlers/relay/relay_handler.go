@@ -0,0 +1,190 @@
+// Package relayhandlers are the handlers for relaying the games of the broadcast boards
+package relayhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/relay"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/relay"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type RelayHandler struct {
+	service  ports.RelayServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewRelayHandler(log ports.Logger, rs ports.RelayServicer) ports.RelayHandler {
+	handler := &RelayHandler{
+		service:  rs,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// StartRelayHandler starts relaying the match, the body is optional
+func (h *RelayHandler) StartRelayHandler(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.StartRelayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := commands.StartRelayCommand{MatchID: matchID, FEN: strings.TrimSpace(req.FEN)}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.StartRelay(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RelayResponse{
+		"relay": mapRelayToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+func (h *RelayHandler) FindRelayHandler(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindRelayCommand{MatchID: matchID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindRelay(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RelayResponse{
+		"relay": mapRelayToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *RelayHandler) RecordMoveHandler(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.RecordMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToRecordMoveCommand(matchID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.RecordMove(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RelayResponse{
+		"relay": mapRelayToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// FinishRelayHandler ends a game that did not end on the board, e.g. a resignation
+func (h *RelayHandler) FinishRelayHandler(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.FinishRelayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := mapToFinishCommand(matchID, req)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FinishRelay(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RelayResponse{
+		"relay": mapRelayToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *RelayHandler) parseID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, param)))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *RelayHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrRelayNotFound),
+		errors.Is(err, domain.ErrMatchNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrIllegalMove):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "illegal_move")
+	case errors.Is(err, domain.ErrRelayAlreadyStarted):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "relay_already_started")
+	case errors.Is(err, domain.ErrRelayFinished):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "relay_finished")
+	case errors.Is(err, domain.ErrRelayOutOfSync):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "relay_out_of_sync")
+	case errors.Is(err, domain.ErrMatchFinished):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "match_finished")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
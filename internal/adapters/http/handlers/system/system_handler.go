@@ -8,6 +8,8 @@ import (
 	"github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/system"
 	"github.com/ctfrancia/maple/internal/adapters/http/handlers/validator"
 	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
@@ -31,18 +33,36 @@ func NewSystemHandler(ss ports.SystemServicer, log ports.Logger) ports.SystemHan
 }
 
 func (h *SystemHealthHandler) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	// start of the business logic
-	sysInfo := h.system.ProcessSystemHealthRequest()
+	env := map[string]dto.SystemResponse{
+		"system": mapSystemToDto(h.system.ProcessSystemHealthRequest()),
+	}
 
-	// end of the business logic
-	res, err := json.Marshal(sysInfo)
-	if err != nil {
-		h.response.ServerErrorResponse(w, r, err)
-		return
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// LivezHandler answers 503 when the process is stuck and has to be restarted
+func (h *SystemHealthHandler) LivezHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeHealth(w, h.system.Liveness(r.Context()))
+}
+
+// ReadyzHandler answers 503 while a dependency is down, the instance should not be sent
+// traffic until it is back
+func (h *SystemHealthHandler) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeHealth(w, h.system.Readiness(r.Context()))
+}
+
+func (h *SystemHealthHandler) writeHealth(w http.ResponseWriter, report domain.HealthReport) {
+	status := http.StatusOK
+	if report.Status != domain.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	env := map[string]dto.HealthResponse{
+		"health": mapHealthToDto(report),
 	}
+	headers := http.Header{"Cache-Control": []string{"no-store"}}
 
-	// write the response
-	h.response.WriteJSON(w, http.StatusOK, res, nil)
+	h.response.WriteJSON(w, status, env, headers)
 }
 
 // LoginHandler handles the login request for logging into the system this LoginHandler is for
@@ -84,18 +104,20 @@ func (h *SystemHealthHandler) NewConsumerHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	h.validator.Check(requestBody.Email != "", "email", "must be provided")
-	h.validator.Check(requestBody.FirstName != "", "first_name", "must be provided")
-	h.validator.Check(requestBody.LastName != "", "last_name", "must be provided")
-	h.validator.Check(requestBody.Website != "", "website", "must be provided")
-	h.validator.Check(validator.Matches(requestBody.Email, validator.EmailRX), "email", "must be a valid email address")
+	// a validator per request, the errors of one request must not leak into the next
+	v := validator.NewValidator()
+	v.Check(requestBody.Email != "", "email", validation.Required())
+	v.Check(requestBody.FirstName != "", "first_name", validation.Required())
+	v.Check(requestBody.LastName != "", "last_name", validation.Required())
+	v.Check(requestBody.Website != "", "website", validation.Required())
+	v.Check(validator.Matches(requestBody.Email, validator.EmailRX), "email", validation.InvalidEmail())
 
 	if requestBody.ClubAffiliation != "" {
-		msg := "not a valid club affiliation" // TODO: create a better message and figure out how to solve
-		h.validator.AddError("club_affiliation", msg)
+		// TODO: figure out how to validate club affiliations
+		v.AddError("club_affiliation", validation.InvalidClubAffiliation())
 	}
-	if !h.validator.Valid() {
-		h.response.FailedValidationResponse(w, r, h.validator.ReturnErrors())
+	if !v.Valid() {
+		h.response.FailedValidationResponse(w, r, v.ReturnErrors())
 		return
 	}
 
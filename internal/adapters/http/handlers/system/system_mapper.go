@@ -16,3 +16,30 @@ func transformNewAPIConsumerRequestToDomainModel(requestBody dto.NewAPIConsumerR
 		ClubAffiliation: requestBody.ClubAffiliation,
 	}
 }
+
+func mapSystemToDto(system domain.System) dto.SystemResponse {
+	return dto.SystemResponse{
+		Version:   system.Version,
+		Commit:    system.Commit,
+		BuildTime: system.BuildTime,
+		GoVersion: system.GoVersion,
+	}
+}
+
+func mapHealthToDto(report domain.HealthReport) dto.HealthResponse {
+	checks := make([]dto.HealthCheckResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, dto.HealthCheckResponse{
+			Name:      check.Name,
+			Status:    string(check.Status),
+			LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+			Error:     check.Error,
+		})
+	}
+
+	return dto.HealthResponse{
+		Status: string(report.Status),
+		Checks: checks,
+		System: mapSystemToDto(report.System),
+	}
+}
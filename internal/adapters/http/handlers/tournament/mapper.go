@@ -1,9 +1,12 @@
 package tournamenthandlers
 
 import (
+	"strings"
+
 	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/tournament"
 	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
 	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
 	"github.com/google/uuid"
 )
 
@@ -13,10 +16,48 @@ func NewTournamentMapper() TournamentMapper {
 	return TournamentMapper{}
 }
 
-func (m TournamentMapper) MapToCommand(dto dto.CreateTournamentRequest) commands.CreateTournamentCommand {
+// MapToCommand - consumerID is the authenticated api consumer posting the tournament
+func (m TournamentMapper) MapToCommand(consumerID string, dto dto.CreateTournamentRequest) commands.CreateTournamentCommand {
 	return commands.CreateTournamentCommand{
-		Name:     dto.Name,
-		Schedule: mapScheduleToCommand(dto.Schedule),
+		Name:         dto.Name,
+		Description:  dto.Description,
+		Descriptions: dto.Descriptions,
+		Schedule:     mapScheduleToCommand(dto.Schedule),
+		TimeControl:  dto.TimeControl,
+		LocationID:   strings.TrimSpace(dto.LocationID),
+		ConsumerID:   consumerID,
+	}
+}
+
+// MapToTransitionCommand - actor is who the request is authenticated as
+func (m TournamentMapper) MapToTransitionCommand(ID uuid.UUID, action domain.TournamentAction, actor domain.Principal, req dto.TransitionTournamentRequest) commands.TransitionTournamentCommand {
+	return commands.TransitionTournamentCommand{
+		ID:     ID,
+		Action: action,
+		Actor:  actor,
+		Reason: req.Reason,
+	}
+}
+
+func (m TournamentMapper) MapToRegisterPlayerCommand(ID uuid.UUID, req dto.RegisterPlayerRequest) commands.RegisterPlayerCommand {
+	playerID, _ := uuid.Parse(strings.TrimSpace(req.PlayerID))
+
+	return commands.RegisterPlayerCommand{
+		TournamentID: ID,
+		PlayerID:     playerID,
+	}
+}
+
+func (m TournamentMapper) MapToPairRoundCommand(ID uuid.UUID, req dto.PairRoundRequest) commands.PairRoundCommand {
+	pairings := make([]commands.Pairing, len(req.Pairings))
+	for i, p := range req.Pairings {
+		pairings[i].White, _ = uuid.Parse(strings.TrimSpace(p.White))
+		pairings[i].Black, _ = uuid.Parse(strings.TrimSpace(p.Black))
+	}
+
+	return commands.PairRoundCommand{
+		TournamentID: ID,
+		Pairings:     pairings,
 	}
 }
 
@@ -44,47 +85,88 @@ func mapTournamentToDomain(t dto.CreateTournamentRequest) domain.Tournament {
 	}
 }
 
-func mapTournamentToDto(t domain.Tournament) dto.TournamentResponse {
+// mapTournamentToDto converts the tournament, the description is given in the locale when translated
+func mapTournamentToDto(t domain.Tournament, locale i18n.Locale) dto.TournamentResponse {
 	return dto.TournamentResponse{
 		ID:                 t.PublicID.String(),
 		Name:               t.Name,
-		Description:        t.Description,
+		Description:        t.Descriptions.In(locale, t.Description),
+		Descriptions:       mapDescriptionsToDto(t.Descriptions),
 		Location:           mapLocationToDto(t.Location),
 		OpenToPublic:       t.OpenToPublic,
 		OpenToSpectators:   t.OpenToSpectators,
 		OpenToRegistration: t.OpenToRegistration,
 		Registration:       mapRegistrationToDto(t.Registration),
 		Arbitrator:         t.Arbitrator,
+		TimeControl:        mapTimeControlToDto(t.TimeControl),
 		Matches:            nil,
-		Players:            nil,
+		Players:            mapPlayersToDto(t.Players),
 		NumberOfPlayers:    t.NumberOfPlayers,
+		Rounds:             t.Rounds,
 		Schedule:           mapScheduleToDto(t.Schedule),
 		Results:            nil,
-		Status:             dto.TournamentStatus(t.Status),
+		Status:             dto.TournamentStatus(t.CurrentStatus()),
+		AvailableActions:   mapActionsToDto(t.AvailableActions()),
+		Transitions:        mapTransitionsToDto(t.Transitions),
+		ConsumerID:         t.ConsumerID,
+		Moderation:         string(t.Moderation),
+	}
+}
+
+func mapRoundToDto(r domain.PairedRound) dto.RoundResponse {
+	matches := make([]dto.Match, len(r.Matches))
+	for i, m := range r.Matches {
+		matches[i] = dto.Match{
+			UUID:         m.UUID.String(),
+			TournamentID: m.TournamentID.String(),
+			Location:     m.Location.Name,
+			City:         m.City,
+			State:        m.State,
+			Country:      m.Country,
+			Rated:        m.Rated,
+			WhitePlayer:  m.WhitePlayer.String(),
+			BlackPlayer:  m.BlackPlayer.String(),
+			PGN:          m.PGN,
+			CreatedAt:    m.CreatedAt,
+			UpdatedAt:    m.UpdatedAt,
+		}
 	}
+	return dto.RoundResponse{Round: r.Round, Matches: matches}
 }
 
 func mapLocationToDto(l domain.Location) dto.Location {
-	return dto.Location{
+	location := dto.Location{
+		Name:       l.Name,
 		Address:    l.Address,
 		PostalCode: l.PostalCode,
 		City:       l.City,
 		State:      l.State,
 		Country:    l.Country,
-		// Name:       l.Name,
 		// County:     l.County,
 		// Province:   l.Province,
 		// Latitude:   l.Latitude,
 		// Longitude:  l.Longitude,
 		// Timezone: domain.TimezoneUTC,
 	}
+	if l.Registered() {
+		location.ID = l.PublicID.String()
+	}
+	return location
+}
+
+func mapPlayersToDto(players []uuid.UUID) []string {
+	xPlayers := make([]string, len(players))
+	for i, p := range players {
+		xPlayers[i] = p.String()
+	}
+	return xPlayers
 }
 
 func mapResultsToDto(r []domain.Result) []dto.Result {
 	xResults := make([]dto.Result, len(r))
 	for i, s := range r {
 		xResults[i] = dto.Result{
-			Player: s.Player.PublicID.String(),
+			Player: s.Player.String(),
 			Prize:  s.Prize,
 		}
 	}
@@ -124,3 +206,69 @@ func mapRegistrationPayoutToDto(p []domain.Payment) []dto.Payment {
 	}
 	return xPayout
 }
+
+func mapTournamentsToDto(tournaments []domain.Tournament, locale i18n.Locale) []dto.TournamentResponse {
+	xTournaments := make([]dto.TournamentResponse, len(tournaments))
+	for i, t := range tournaments {
+		xTournaments[i] = mapTournamentToDto(t, locale)
+	}
+	return xTournaments
+}
+
+func mapDescriptionsToDto(lt domain.LocalizedText) map[string]string {
+	if len(lt) == 0 {
+		return nil
+	}
+
+	descriptions := make(map[string]string, len(lt))
+	for locale, text := range lt {
+		descriptions[string(locale)] = text
+	}
+	return descriptions
+}
+
+func mapTimeControlToDto(tc domain.TimeControl) *dto.TimeControl {
+	if tc.IsZero() {
+		return nil
+	}
+
+	periods := make([]dto.TimeControlPeriod, len(tc.Periods))
+	for i, p := range tc.Periods {
+		periods[i] = dto.TimeControlPeriod{
+			Moves:            p.Moves,
+			BaseSeconds:      int(p.Base.Seconds()),
+			IncrementSeconds: int(p.Increment.Seconds()),
+			DelaySeconds:     int(p.Delay.Seconds()),
+		}
+	}
+
+	return &dto.TimeControl{
+		Notation: tc.String(),
+		PGN:      tc.PGN(),
+		Category: string(tc.Category()),
+		Periods:  periods,
+	}
+}
+
+func mapActionsToDto(actions []domain.TournamentAction) []string {
+	xActions := make([]string, len(actions))
+	for i, a := range actions {
+		xActions[i] = string(a)
+	}
+	return xActions
+}
+
+func mapTransitionsToDto(transitions []domain.TournamentTransition) []dto.Transition {
+	xTransitions := make([]dto.Transition, len(transitions))
+	for i, t := range transitions {
+		xTransitions[i] = dto.Transition{
+			Action: string(t.Action),
+			From:   string(t.From),
+			To:     string(t.To),
+			Actor:  t.Actor,
+			Reason: t.Reason,
+			At:     t.At,
+		}
+	}
+	return xTransitions
+}
@@ -3,12 +3,17 @@ package tournamenthandlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
 	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/tournament"
 	"github.com/ctfrancia/maple/internal/adapters/http/handlers/validator"
 	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
 	"github.com/ctfrancia/maple/internal/core/ports"
 
 	"github.com/go-chi/chi/v5"
@@ -40,27 +45,36 @@ func (h *TournamentHandler) CreateTournamentHandler(w http.ResponseWriter, r *ht
 	// 1. Receive DTO from JSON
 	var ctr dto.CreateTournamentRequest
 	if err := json.NewDecoder(r.Body).Decode(&ctr); err != nil {
-		h.response.ErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
 		return
 	}
 
 	// 2. Map DTO to Command
-	cmd := h.mapper.MapToCommand(ctr)
+	cmd := h.mapper.MapToCommand(consumerID, ctr)
 
 	// 3. Validate command
 	if err := cmd.Validate(); err != nil {
-		h.response.ErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		if ve, ok := commands.IsValidationError(err); ok {
+			h.response.FailedValidationResponse(w, r, ve.Errors)
+			return
+		}
+		h.response.BadRequestResponse(w, r, err)
 		return
 	}
 
 	// 4. Execute command via service
 	result, err := h.service.CreateTournament(r.Context(), cmd)
 	if err != nil {
-		h.response.ServerErrorResponse(w, r, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	resp := mapTournamentToDto(result)
+	resp := mapTournamentToDto(result, i18n.FromContext(r.Context()))
 
 	env := map[string]dto.TournamentResponse{
 		"tournament": resp,
@@ -72,16 +86,10 @@ func (h *TournamentHandler) CreateTournamentHandler(w http.ResponseWriter, r *ht
 
 // FindTournamentHandler is the entrypoint for finding a tournament
 func (h *TournamentHandler) FindTournamentHandler(w http.ResponseWriter, r *http.Request) {
-	tournamentIDStr := strings.TrimSpace(chi.URLParam(r, "id"))
-	if tournamentIDStr == "" {
-		h.response.ErrorResponse(w, r, http.StatusBadRequest, "id is required")
-		return
-	}
-
 	// Parse and validate the UUID here - fail fast
-	ID, err := uuid.Parse(tournamentIDStr)
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
 	if err != nil {
-		h.response.ErrorResponse(w, r, http.StatusBadRequest, "invalid tournament ID format")
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
 		return
 	}
 
@@ -93,7 +101,7 @@ func (h *TournamentHandler) FindTournamentHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	tournament := mapTournamentToDto(result)
+	tournament := mapTournamentToDto(result, i18n.FromContext(r.Context()))
 
 	env := map[string]dto.TournamentResponse{
 		"tournament": tournament,
@@ -103,8 +111,203 @@ func (h *TournamentHandler) FindTournamentHandler(w http.ResponseWriter, r *http
 	h.response.WriteJSON(w, http.StatusOK, env, nil)
 }
 
-// ListTournamentsHandler is the entrypoint for listing tournaments
+// ListTournamentsHandler is the entrypoint for listing tournaments, filtered by ?category= and ?time_control=
 func (h *TournamentHandler) ListTournamentsHandler(w http.ResponseWriter, r *http.Request) {
+	cmd := commands.ListTournamentsCommand{
+		Category:    domain.TimeControlCategory(r.URL.Query().Get("category")),
+		TimeControl: r.URL.Query().Get("time_control"),
+	}
+	if err := cmd.Validate(); err != nil {
+		if ve, ok := commands.IsValidationError(err); ok {
+			h.response.FailedValidationResponse(w, r, ve.Errors)
+			return
+		}
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListTournaments(r.Context(), cmd)
+	if err != nil {
+		h.response.ServerErrorResponse(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.TournamentResponse{
+		"tournaments": mapTournamentsToDto(result, i18n.FromContext(r.Context())),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// TransitionTournamentHandler is the entrypoint for the lifecycle actions of a tournament,
+// the action is the last segment of the route e.g. POST /v1/tournament/{id}/start
+func (h *TournamentHandler) TransitionTournamentHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return
+	}
+
+	action := domain.TournamentAction(chi.URLParam(r, "action"))
+	if !action.Valid() {
+		h.response.NotFoundResponse(w, r)
+		return
+	}
+
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok {
+		h.response.InvalidCredentialsResponse(w, r)
+		return
+	}
+
+	var req dto.TransitionTournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToTransitionCommand(ID, action, principal, req)
+	if err := cmd.Validate(); err != nil {
+		if ve, ok := commands.IsValidationError(err); ok {
+			h.response.FailedValidationResponse(w, r, ve.Errors)
+			return
+		}
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	result, err := h.service.TransitionTournament(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.TournamentResponse{
+		"tournament": mapTournamentToDto(result, i18n.FromContext(r.Context())),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RegisterPlayerHandler is the entrypoint for registering a player for a tournament
+func (h *TournamentHandler) RegisterPlayerHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return
+	}
+
+	var req dto.RegisterPlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToRegisterPlayerCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		if ve, ok := commands.IsValidationError(err); ok {
+			h.response.FailedValidationResponse(w, r, ve.Errors)
+			return
+		}
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	result, err := h.service.RegisterPlayer(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.TournamentResponse{
+		"tournament": mapTournamentToDto(result, i18n.FromContext(r.Context())),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// PairRoundHandler is the entrypoint for publishing the pairings of the next round of a tournament
+func (h *TournamentHandler) PairRoundHandler(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, "id")))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return
+	}
+
+	var req dto.PairRoundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	cmd := h.mapper.MapToPairRoundCommand(ID, req)
+	if err := cmd.Validate(); err != nil {
+		if ve, ok := commands.IsValidationError(err); ok {
+			h.response.FailedValidationResponse(w, r, ve.Errors)
+			return
+		}
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	result, err := h.service.PairRound(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.RoundResponse{
+		"round": mapRoundToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// consumer is the api consumer the request is authenticated as, the tournaments are posted as its own
+func (h *TournamentHandler) consumer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok || principal.Role != domain.RoleConsumer {
+		h.response.InvalidCredentialsResponse(w, r)
+		return "", false
+	}
+
+	return principal.ID, true
+}
+
+func (h *TournamentHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTournamentNotFound),
+		errors.Is(err, domain.ErrLocationNotFound),
+		errors.Is(err, domain.ErrPlayerNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrTournamentRegistrationClosed):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "tournament_registration_closed")
+	case errors.Is(err, domain.ErrPlayerAlreadyRegistered):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "player_already_registered")
+	case errors.Is(err, domain.ErrTournamentNotInPlay):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "tournament_not_in_play")
+	case errors.Is(err, domain.ErrInvalidPairings):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "invalid_pairings")
+	case errors.Is(err, domain.ErrTournamentTransitionNotAllowed):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "tournament_transition_not_allowed")
+	case errors.Is(err, domain.ErrTournamentNotEnoughPlayers):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "tournament_not_enough_players")
+	case errors.Is(err, domain.ErrTournamentUnreportedResults):
+		h.response.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "tournament_unreported_results")
+	case errors.Is(err, domain.ErrConsumerSuspended):
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "consumer_suspended")
+	case errors.Is(err, domain.ErrTournamentNotOwner):
+		h.response.ErrorCodeResponse(w, r, http.StatusForbidden, "tournament_not_owner")
+	case errors.Is(err, domain.ErrConsumerRequired):
+		h.response.InvalidCredentialsResponse(w, r)
+	case errors.Is(err, domain.ErrWorkerPoolFull),
+		errors.Is(err, domain.ErrWorkerPoolStopped),
+		errors.Is(err, domain.ErrTaskTimeout):
+		w.Header().Set("Retry-After", "1")
+		h.response.ErrorCodeResponse(w, r, http.StatusServiceUnavailable, "service_busy")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
 }
 
 // UpdateTournamentHandler is the entrypoint for updating a tournament
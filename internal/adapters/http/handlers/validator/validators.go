@@ -4,17 +4,19 @@ package validator
 import (
 	"regexp"
 	"slices"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
 )
 
 var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
 type Validator struct {
-	Errors map[string]string
+	Errors validation.Errors
 }
 
 // New returns a new pointer to the handler of Validator
 func NewValidator() *Validator {
-	return &Validator{Errors: make(map[string]string)}
+	return &Validator{Errors: make(validation.Errors)}
 }
 
 // Valid should be called after all validations are performed to check if there are indeed valid
@@ -23,20 +25,20 @@ func (v *Validator) Valid() bool {
 }
 
 // ReturnErrors returns the errors map mainly for returning to the client
-func (v *Validator) ReturnErrors() map[string]string {
+func (v *Validator) ReturnErrors() validation.Errors {
 	return v.Errors
 }
 
-func (v *Validator) AddError(key, message string) {
+func (v *Validator) AddError(key string, fe validation.FieldError) {
 	if _, exists := v.Errors[key]; !exists {
-		v.Errors[key] = message
+		v.Errors[key] = fe
 	}
 }
 
-// Check adds an error message to the map only if a validation check is not 'ok'.
-func (v *Validator) Check(ok bool, key, message string) {
+// Check adds an error to the map only if a validation check is not 'ok'.
+func (v *Validator) Check(ok bool, key string, fe validation.FieldError) {
 	if !ok {
-		v.AddError(key, message)
+		v.AddError(key, fe)
 	}
 }
 
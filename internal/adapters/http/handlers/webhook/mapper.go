@@ -0,0 +1,111 @@
+package webhookhandlers
+
+import (
+	"time"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/webhook"
+	commands "github.com/ctfrancia/maple/internal/application/commands/webhook"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// mapToCreateCommand maps the request of the consumer, invalid tournament ids are left nil for
+// the command to reject
+func mapToCreateCommand(req dto.CreateWebhookRequest, consumerID string) commands.CreateWebhookCommand {
+	cmd := commands.CreateWebhookCommand{
+		ConsumerID: consumerID,
+		URL:        req.URL,
+	}
+
+	for _, t := range req.EventTypes {
+		cmd.EventTypes = append(cmd.EventTypes, domain.EventType(t))
+	}
+	for _, id := range req.TournamentIDs {
+		tournamentID, _ := uuid.Parse(id)
+		cmd.TournamentIDs = append(cmd.TournamentIDs, tournamentID)
+	}
+
+	return cmd
+}
+
+// mapWebhookToDto maps the webhook without its secret, it is only shown once when created
+func mapWebhookToDto(w domain.Webhook) dto.WebhookResponse {
+	eventTypes := make([]string, len(w.EventTypes))
+	for i, t := range w.EventTypes {
+		eventTypes[i] = string(t)
+	}
+	tournamentIDs := make([]string, len(w.TournamentIDs))
+	for i, id := range w.TournamentIDs {
+		tournamentIDs[i] = id.String()
+	}
+
+	return dto.WebhookResponse{
+		ID:                  w.PublicID.String(),
+		ConsumerID:          w.ConsumerID,
+		URL:                 w.URL,
+		EventTypes:          eventTypes,
+		TournamentIDs:       tournamentIDs,
+		Status:              string(w.Status),
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		DisabledAt:          optionalTime(w.DisabledAt),
+		DisabledReason:      w.DisabledReason,
+		LastDeliveryAt:      optionalTime(w.LastDeliveryAt),
+		CreatedAt:           w.CreatedAt,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+func mapWebhooksToDto(webhooks []domain.Webhook) []dto.WebhookResponse {
+	xWebhooks := make([]dto.WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		xWebhooks[i] = mapWebhookToDto(w)
+	}
+
+	return xWebhooks
+}
+
+func mapDeliveryToDto(d domain.WebhookDelivery) dto.DeliveryResponse {
+	attempts := make([]dto.AttemptResponse, len(d.Attempts))
+	for i, a := range d.Attempts {
+		attempts[i] = dto.AttemptResponse{
+			At:         a.At,
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMs: a.Duration.Milliseconds(),
+		}
+	}
+
+	xDelivery := dto.DeliveryResponse{
+		ID:        d.PublicID.String(),
+		WebhookID: d.WebhookID.String(),
+		EventID:   d.EventID.String(),
+		EventType: string(d.EventType),
+		Status:    string(d.Status),
+		Attempts:  attempts,
+		CreatedAt: d.CreatedAt,
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		xDelivery.NextAttemptAt = optionalTime(d.NextAttemptAt)
+	}
+	if d.RedeliveryOf != uuid.Nil {
+		xDelivery.RedeliveryOf = d.RedeliveryOf.String()
+	}
+
+	return xDelivery
+}
+
+func mapDeliveriesToDto(deliveries []domain.WebhookDelivery) []dto.DeliveryResponse {
+	xDeliveries := make([]dto.DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		xDeliveries[i] = mapDeliveryToDto(d)
+	}
+
+	return xDeliveries
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,286 @@
+// Package webhookhandlers are the handlers for the webhooks of the api consumers
+package webhookhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/webhook"
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	commands "github.com/ctfrancia/maple/internal/application/commands/webhook"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	service  ports.WebhookServicer
+	response ports.SystemResponder
+	logger   ports.Logger
+}
+
+func NewWebhookHandler(log ports.Logger, ws ports.WebhookServicer) ports.WebhookHandler {
+	handler := &WebhookHandler{
+		service:  ws,
+		response: response.NewResponseWriter(log),
+		logger:   log,
+	}
+
+	return handler
+}
+
+// CreateWebhookHandler registers an endpoint, the response is the only time the secret is shown
+func (h *WebhookHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.response.BadRequestResponse(w, r, err)
+		return
+	}
+
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := mapToCreateCommand(req, consumerID)
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.CreateWebhook(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	webhook := mapWebhookToDto(result)
+	webhook.Secret = result.Secret
+	env := map[string]dto.WebhookResponse{
+		"webhook": webhook,
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// ListWebhooksHandler lists the webhooks of the authenticated api consumer
+func (h *WebhookHandler) ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+
+	cmd := commands.ListWebhooksCommand{ConsumerID: consumerID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListWebhooks(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.WebhookResponse{
+		"webhooks": mapWebhooksToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *WebhookHandler) FindWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindWebhookCommand{ConsumerID: consumerID, ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.FindWebhook(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.WebhookResponse{
+		"webhook": mapWebhookToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+func (h *WebhookHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindWebhookCommand{ConsumerID: consumerID, ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), cmd); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableWebhookHandler enables a webhook that was disabled after failing too many times
+func (h *WebhookHandler) EnableWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.FindWebhookCommand{ConsumerID: consumerID, ID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.EnableWebhook(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.WebhookResponse{
+		"webhook": mapWebhookToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// ListDeliveriesHandler is the delivery log of a webhook, ?limit= caps the list
+func (h *WebhookHandler) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	ID, ok := h.parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cmd := commands.ListDeliveriesCommand{ConsumerID: consumerID, WebhookID: ID}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			h.response.FailedValidationResponse(w, r, validation.Errors{"limit": validation.InvalidFormat("integer")})
+			return
+		}
+		cmd.Limit = n
+	}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.ListDeliveries(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string][]dto.DeliveryResponse{
+		"deliveries": mapDeliveriesToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusOK, env, nil)
+}
+
+// RedeliverHandler sends a delivery again and returns the new delivery with its first attempt
+func (h *WebhookHandler) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	consumerID, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	ID, ok := h.parseID(w, r, "deliveryID")
+	if !ok {
+		return
+	}
+
+	cmd := commands.RedeliverCommand{ConsumerID: consumerID, DeliveryID: ID}
+	if err := cmd.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Redeliver(r.Context(), cmd)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	env := map[string]dto.DeliveryResponse{
+		"delivery": mapDeliveryToDto(result),
+	}
+
+	h.response.WriteJSON(w, http.StatusCreated, env, nil)
+}
+
+// consumer is the api consumer the request is authenticated as, the webhooks are always its own
+func (h *WebhookHandler) consumer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal, ok := ports.PrincipalFromContext(r.Context())
+	if !ok || principal.Role != domain.RoleConsumer {
+		h.response.InvalidCredentialsResponse(w, r)
+		return "", false
+	}
+
+	return principal.ID, true
+}
+
+// parseID reads a uuid from the url, writing the error response if it is not valid
+func (h *WebhookHandler) parseID(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, bool) {
+	ID, err := uuid.Parse(strings.TrimSpace(chi.URLParam(r, param)))
+	if err != nil {
+		h.response.ErrorCodeResponse(w, r, http.StatusBadRequest, response.CodeInvalidID)
+		return uuid.Nil, false
+	}
+
+	return ID, true
+}
+
+func (h *WebhookHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if ve, ok := commands.IsValidationError(err); ok {
+		h.response.FailedValidationResponse(w, r, ve.Errors)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound),
+		errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		h.response.NotFoundResponse(w, r)
+	case errors.Is(err, domain.ErrWebhookDisabled):
+		h.response.ErrorCodeResponse(w, r, http.StatusConflict, "webhook_disabled")
+	default:
+		h.response.ServerErrorResponse(w, r, err)
+	}
+}
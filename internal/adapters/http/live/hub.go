@@ -0,0 +1,302 @@
+// Package live is the pub/sub hub behind the live tournament feeds. Every tournament is a
+// topic with a bounded replay buffer so a spectator that reconnects resumes where it left off
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSlowSubscriber closes a subscription that did not keep up, the client reconnects
+	// and resumes from the replay buffer
+	ErrSlowSubscriber = errors.New("the subscriber is too slow")
+	ErrHubClosed      = errors.New("the live hub is shut down")
+)
+
+// HubConfig - how much is kept for the spectators that reconnect, how far behind one can fall
+// and how long a tournament nobody follows is kept
+type HubConfig struct {
+	ReplaySize        int // messages kept per tournament
+	SubscriberBuffer  int // messages queued per connection before it is dropped
+	HeartbeatInterval time.Duration
+	TopicTTL          time.Duration // idle time after which a topic without subscribers is dropped, zero keeps them
+}
+
+func DefaultHubConfig() HubConfig {
+	return HubConfig{
+		ReplaySize:        256,
+		SubscriberBuffer:  64,
+		HeartbeatInterval: 15 * time.Second,
+		TopicTTL:          time.Hour,
+	}
+}
+
+// Message is a live update as sent to the spectators
+type Message struct {
+	ID   string          `json:"id"` // <epoch>-<sequence>, the Last-Event-ID to resume from
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	At   time.Time       `json:"at"`
+
+	seq uint64
+}
+
+// Hub keeps the subscriptions of every tournament. The topic of a completed tournament is
+// dropped with its last subscriber, the others once nobody followed them for TopicTTL
+type Hub struct {
+	config HubConfig
+	epoch  string // tells the ids of this process apart from the ones of a previous run
+	now    func() time.Time
+
+	mu     sync.Mutex
+	topics map[uuid.UUID]*topic
+	topicN uint64    // topics created, a dropped topic comes back with ids of its own
+	swept  time.Time // last time the idle topics were dropped
+	closed bool
+	done   chan struct{}
+}
+
+type topic struct {
+	epoch       string // of the hub and the topic, the ids of a dropped one do not resume this one
+	seq         uint64
+	replay      []Message // oldest first, at most ReplaySize
+	subscribers map[*Subscription]struct{}
+	completed   bool
+	active      time.Time // last message, subscription or unsubscription
+}
+
+func NewHub(config HubConfig) *Hub {
+	return &Hub{
+		config: config,
+		epoch:  strconv.FormatInt(time.Now().UnixNano(), 36),
+		now:    time.Now,
+		topics: make(map[uuid.UUID]*topic),
+		swept:  time.Now(),
+		done:   make(chan struct{}),
+	}
+}
+
+var _ ports.LiveBroadcaster = (*Hub)(nil)
+
+// Config returns the configuration of the hub
+func (h *Hub) Config() HubConfig {
+	return h.config
+}
+
+// Done is closed when the hub shuts down
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Publish sends the update to the subscribers of its tournament. A subscriber whose buffer
+// is full is dropped instead of holding back the others
+func (h *Hub) Publish(update domain.LiveUpdate) error {
+	data, err := json.Marshal(update.Data)
+	if err != nil {
+		return fmt.Errorf("encoding live update: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrHubClosed
+	}
+
+	t := h.topic(update.TournamentID)
+	t.seq++
+	msg := Message{
+		ID:   t.epoch + "-" + strconv.FormatUint(t.seq, 10),
+		Type: update.Type,
+		Data: data,
+		At:   update.At,
+		seq:  t.seq,
+	}
+
+	t.replay = append(t.replay, msg)
+	if over := len(t.replay) - h.config.ReplaySize; over > 0 {
+		t.replay = append([]Message(nil), t.replay[over:]...)
+	}
+
+	t.active = h.now()
+	t.completed = t.completed || update.Type == string(domain.EventTournamentCompleted)
+	for sub := range t.subscribers {
+		select {
+		case sub.ch <- msg:
+		default:
+			h.drop(t, sub, ErrSlowSubscriber)
+		}
+	}
+	h.release(update.TournamentID, t)
+
+	return nil
+}
+
+// Subscribe follows the tournament. With the id of the last message the client received the
+// messages it missed are returned to be sent first; resumed is false when they are no longer
+// in the replay buffer, the client has to reload the state of the tournament
+func (h *Hub) Subscribe(tournamentID uuid.UUID, lastEventID string) (sub *Subscription, missed []Message, resumed bool, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, false, ErrHubClosed
+	}
+
+	t := h.topic(tournamentID)
+	resumed = true
+	if lastEventID != "" {
+		missed, resumed = h.since(t, lastEventID)
+	}
+
+	sub = &Subscription{
+		hub:          h,
+		tournamentID: tournamentID,
+		ch:           make(chan Message, h.config.SubscriberBuffer),
+	}
+	t.subscribers[sub] = struct{}{}
+	t.active = h.now()
+
+	return sub, missed, resumed, nil
+}
+
+// since returns the messages after the id, false when some of them were already discarded
+func (h *Hub) since(t *topic, lastEventID string) ([]Message, bool) {
+	epoch, seqText, ok := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if !ok || err != nil || epoch != t.epoch || seq > t.seq {
+		return nil, false
+	}
+
+	var missed []Message
+	for _, msg := range t.replay {
+		if msg.seq > seq {
+			missed = append(missed, msg)
+		}
+	}
+
+	// the message right after the last one received must still be in the buffer
+	if seq < t.seq && (len(missed) == 0 || missed[0].seq != seq+1) {
+		return nil, false
+	}
+	return missed, true
+}
+
+// Shutdown closes every subscription so the connections end, it is safe to call twice
+func (h *Hub) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+
+	for _, t := range h.topics {
+		for sub := range t.subscribers {
+			h.drop(t, sub, ErrHubClosed)
+		}
+	}
+}
+
+// Subscribers returns how many connections follow the tournament
+func (h *Hub) Subscribers(tournamentID uuid.UUID) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if t, ok := h.topics[tournamentID]; ok {
+		return len(t.subscribers)
+	}
+	return 0
+}
+
+func (h *Hub) topic(tournamentID uuid.UUID) *topic {
+	t, ok := h.topics[tournamentID]
+	if !ok {
+		h.sweep()
+		h.topicN++
+		t = &topic{
+			epoch:       h.epoch + "." + strconv.FormatUint(h.topicN, 36),
+			subscribers: make(map[*Subscription]struct{}),
+			active:      h.now(),
+		}
+		h.topics[tournamentID] = t
+	}
+	return t
+}
+
+// sweep drops the topics nobody followed for TopicTTL, at most once per TopicTTL as it goes
+// through all of them. h.mu must be held
+func (h *Hub) sweep() {
+	now := h.now()
+	if h.config.TopicTTL <= 0 || now.Sub(h.swept) < h.config.TopicTTL {
+		return
+	}
+	h.swept = now
+
+	for id, t := range h.topics {
+		if len(t.subscribers) == 0 && now.Sub(t.active) >= h.config.TopicTTL {
+			delete(h.topics, id)
+		}
+	}
+}
+
+// release drops the topic of a completed tournament once nobody follows it, h.mu must be held
+func (h *Hub) release(tournamentID uuid.UUID, t *topic) {
+	if t.completed && len(t.subscribers) == 0 && h.topics[tournamentID] == t {
+		delete(h.topics, tournamentID)
+	}
+}
+
+// drop removes the subscription and closes its channel, h.mu must be held
+func (h *Hub) drop(t *topic, sub *Subscription, reason error) {
+	if _, ok := t.subscribers[sub]; !ok {
+		return
+	}
+	delete(t.subscribers, sub)
+	t.active = h.now()
+	sub.err = reason
+	close(sub.ch)
+	h.release(sub.tournamentID, t)
+}
+
+// Subscription is one connection following a tournament
+type Subscription struct {
+	hub          *Hub
+	tournamentID uuid.UUID
+	ch           chan Message
+	err          error // why the hub closed the channel
+}
+
+// Messages is closed when the hub drops the subscription, Err tells why
+func (s *Subscription) Messages() <-chan Message {
+	return s.ch
+}
+
+// Err returns why the hub dropped the subscription, nil while it is open
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription, it is safe to call after the hub dropped it
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if t, ok := s.hub.topics[s.tournamentID]; ok {
+		s.hub.drop(t, s, nil)
+	}
+}
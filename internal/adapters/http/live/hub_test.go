@@ -0,0 +1,178 @@
+package live
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publish(t *testing.T, h *Hub, tournamentID uuid.UUID, n int) {
+	t.Helper()
+	for i := range n {
+		err := h.Publish(domain.LiveUpdate{TournamentID: tournamentID, Type: "test", Data: map[string]int{"n": i}, At: time.Now()})
+		require.NoError(t, err)
+	}
+}
+
+func receive(t *testing.T, sub *Subscription) Message {
+	t.Helper()
+	select {
+	case msg, ok := <-sub.Messages():
+		require.True(t, ok, "the subscription was closed")
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return Message{}
+	}
+}
+
+func TestHub_Publish(t *testing.T) {
+	h := NewHub(DefaultHubConfig())
+	tournamentID, other := uuid.New(), uuid.New()
+
+	sub, missed, resumed, err := h.Subscribe(tournamentID, "")
+	require.NoError(t, err)
+	defer sub.Close()
+	assert.Empty(t, missed)
+	assert.True(t, resumed)
+
+	publish(t, h, other, 1)
+	publish(t, h, tournamentID, 2)
+
+	first, second := receive(t, sub), receive(t, sub)
+	assert.JSONEq(t, `{"n":0}`, string(first.Data))
+	assert.JSONEq(t, `{"n":1}`, string(second.Data))
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Empty(t, sub.Messages(), "the updates of another tournament are not received")
+}
+
+func TestHub_Resume(t *testing.T) {
+	h := NewHub(HubConfig{ReplaySize: 3, SubscriberBuffer: 8, HeartbeatInterval: time.Second})
+	tournamentID := uuid.New()
+
+	sub, _, _, err := h.Subscribe(tournamentID, "")
+	require.NoError(t, err)
+	publish(t, h, tournamentID, 2)
+	receive(t, sub)
+	last := receive(t, sub)
+	sub.Close()
+
+	publish(t, h, tournamentID, 2)
+
+	sub, missed, resumed, err := h.Subscribe(tournamentID, last.ID)
+	require.NoError(t, err)
+	sub.Close()
+	assert.True(t, resumed)
+	require.Len(t, missed, 2)
+	assert.JSONEq(t, `{"n":0}`, string(missed[0].Data))
+
+	// up to date, nothing to replay
+	sub, missed, resumed, err = h.Subscribe(tournamentID, missed[1].ID)
+	require.NoError(t, err)
+	sub.Close()
+	assert.True(t, resumed)
+	assert.Empty(t, missed)
+
+	// the update after the last one received fell out of the replay buffer
+	publish(t, h, tournamentID, 3)
+	_, missed, resumed, err = h.Subscribe(tournamentID, last.ID)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	assert.Empty(t, missed)
+
+	// ids of another process, or nonsense, cannot be resumed either
+	for _, id := range []string{"abc-1", "nonsense", h.epoch + "-99"} {
+		_, _, resumed, err = h.Subscribe(tournamentID, id)
+		require.NoError(t, err)
+		assert.False(t, resumed, id)
+	}
+}
+
+func TestHub_SlowSubscriberIsDropped(t *testing.T) {
+	h := NewHub(HubConfig{ReplaySize: 16, SubscriberBuffer: 2, HeartbeatInterval: time.Second})
+	tournamentID := uuid.New()
+
+	slow, _, _, err := h.Subscribe(tournamentID, "")
+	require.NoError(t, err)
+	fast, _, _, err := h.Subscribe(tournamentID, "")
+	require.NoError(t, err)
+	defer fast.Close()
+
+	publish(t, h, tournamentID, 2)
+	receive(t, fast)
+	receive(t, fast)
+	publish(t, h, tournamentID, 1)
+
+	assert.Equal(t, 1, h.Subscribers(tournamentID))
+	assert.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+	receive(t, slow)
+	receive(t, slow)
+	_, open := <-slow.Messages()
+	assert.False(t, open)
+	assert.NotPanics(t, slow.Close)
+
+	receive(t, fast)
+}
+
+func TestHub_TopicsAreDropped(t *testing.T) {
+	now := time.Now()
+	h := NewHub(HubConfig{ReplaySize: 16, SubscriberBuffer: 8, HeartbeatInterval: time.Second, TopicTTL: time.Hour})
+	h.now = func() time.Time { return now }
+	completed, idle, followed := uuid.New(), uuid.New(), uuid.New()
+
+	// the topic of a completed tournament goes with its last subscriber
+	sub, _, _, err := h.Subscribe(completed, "")
+	require.NoError(t, err)
+	require.NoError(t, h.Publish(domain.LiveUpdate{TournamentID: completed, Type: string(domain.EventTournamentCompleted), At: now}))
+	last := receive(t, sub)
+	assert.Contains(t, h.topics, completed)
+	sub.Close()
+	assert.NotContains(t, h.topics, completed)
+
+	// a topic created again does not resume from the ids of the dropped one
+	publish(t, h, completed, 2)
+	again, _, resumed, err := h.Subscribe(completed, last.ID)
+	require.NoError(t, err)
+	assert.False(t, resumed)
+	again.Close()
+
+	publish(t, h, idle, 1)
+	kept, _, _, err := h.Subscribe(followed, "")
+	require.NoError(t, err)
+	defer kept.Close()
+
+	// the idle ones are swept when another topic is created once TopicTTL went by
+	now = now.Add(2 * time.Hour)
+	publish(t, h, uuid.New(), 1)
+	assert.NotContains(t, h.topics, idle)
+	assert.Contains(t, h.topics, followed)
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	h := NewHub(DefaultHubConfig())
+	tournamentID := uuid.New()
+
+	sub, _, _, err := h.Subscribe(tournamentID, "")
+	require.NoError(t, err)
+
+	h.Shutdown()
+	h.Shutdown()
+
+	_, open := <-sub.Messages()
+	assert.False(t, open)
+	assert.ErrorIs(t, sub.Err(), ErrHubClosed)
+
+	_, _, _, err = h.Subscribe(tournamentID, "")
+	assert.ErrorIs(t, err, ErrHubClosed)
+	assert.ErrorIs(t, h.Publish(domain.LiveUpdate{TournamentID: tournamentID}), ErrHubClosed)
+
+	select {
+	case <-h.Done():
+	default:
+		t.Error("expected Done to be closed")
+	}
+}
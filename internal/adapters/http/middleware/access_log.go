@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessLog logs a structured line per request once it is answered, at warn for the client
+// errors and error for the server ones. The request id of chi's RequestID is added to the
+// request context so every line logged while serving the request carries it, it goes after
+// RequestID and Tracing for the line to have the ids of both
+func AccessLog(log ports.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if id := middleware.GetReqID(ctx); id != "" {
+				ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyRequestID, id))
+			}
+
+			start := time.Now()
+			// keeps the flusher and the hijacker of the live streams
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			fields := []ports.LogField{
+				ports.String("method", r.Method),
+				ports.String("path", r.URL.Path),
+				ports.Int("status", status),
+				ports.Int("bytes", ww.BytesWritten()),
+				ports.Any("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				ports.String("remote_addr", r.RemoteAddr),
+				ports.String("user_agent", r.UserAgent()),
+			}
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				fields = append(fields, ports.String("route", rctx.RoutePattern()))
+			}
+
+			logAt(log, status)(ctx, "http request", fields...)
+		})
+	}
+}
+
+func logAt(log ports.Logger, status int) func(ctx context.Context, msg string, fields ...ports.LogField) {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return log.Error
+	case status >= http.StatusBadRequest:
+		return log.Warn
+	default:
+		return log.Info
+	}
+}
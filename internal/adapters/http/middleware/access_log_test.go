@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logLine struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// recordingLogger keeps the lines with the fields of their context
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []logLine
+}
+
+func (l *recordingLogger) record(level string, ctx context.Context, msg string, fields []ports.LogField) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := logLine{level: level, msg: msg, fields: map[string]any{}}
+	for _, field := range append(ports.LogFieldsFromContext(ctx), fields...) {
+		line.fields[field.Key] = field.Value
+	}
+	l.lines = append(l.lines, line)
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, fields ...ports.LogField) {
+	l.record("debug", ctx, msg, fields)
+}
+func (l *recordingLogger) Info(ctx context.Context, msg string, fields ...ports.LogField) {
+	l.record("info", ctx, msg, fields)
+}
+func (l *recordingLogger) Warn(ctx context.Context, msg string, fields ...ports.LogField) {
+	l.record("warn", ctx, msg, fields)
+}
+func (l *recordingLogger) Error(ctx context.Context, msg string, fields ...ports.LogField) {
+	l.record("error", ctx, msg, fields)
+}
+func (l *recordingLogger) Fatal(ctx context.Context, msg string, fields ...ports.LogField) {
+	l.record("fatal", ctx, msg, fields)
+}
+
+func TestAccessLog(t *testing.T) {
+	log := &recordingLogger{}
+
+	mux := chi.NewMux()
+	mux.Use(middleware.RequestID, AccessLog(log))
+	mux.Get("/v1/tournament/find/{id}", func(w http.ResponseWriter, r *http.Request) {
+		log.Info(r.Context(), "handling")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("missing"))
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/tournament/find/42", nil))
+
+	require.Len(t, log.lines, 2)
+	handling, access := log.lines[0], log.lines[1]
+
+	assert.NotEmpty(t, handling.fields[ports.LogKeyRequestID])
+	assert.Equal(t, handling.fields[ports.LogKeyRequestID], access.fields[ports.LogKeyRequestID])
+
+	assert.Equal(t, "warn", access.level)
+	assert.Equal(t, "http request", access.msg)
+	assert.Equal(t, http.MethodGet, access.fields["method"])
+	assert.Equal(t, "/v1/tournament/find/42", access.fields["path"])
+	assert.Equal(t, "/v1/tournament/find/{id}", access.fields["route"])
+	assert.Equal(t, http.StatusNotFound, access.fields["status"])
+	assert.Equal(t, len("missing"), access.fields["bytes"])
+}
+
+func TestAdminToken(t *testing.T) {
+	log := &recordingLogger{}
+	handler := AdminToken(log, "s3cret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for header, status := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusNoContent,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/log-level", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, status, rec.Code, header)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// AdminToken lets through the requests with the token as their bearer, the others are
+// answered with invalid credentials
+func AdminToken(log ports.Logger, token string) func(http.Handler) http.Handler {
+	helper := response.NewResponseWriter(log)
+	expected := []byte("Bearer " + token)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given := []byte(strings.TrimSpace(r.Header.Get("Authorization")))
+			if token == "" || subtle.ConstantTimeCompare(given, expected) != 1 {
+				helper.InvalidCredentialsResponse(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
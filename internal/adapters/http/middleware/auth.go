@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// Authenticate lets through the requests whose bearer token belongs to one of the roles, who
+// they are authenticated as is put in their context, see ports.PrincipalFromContext. The others
+// are answered with invalid credentials, or forbidden when the token is one of another role
+func Authenticate(log ports.Logger, auth ports.Authenticator, roles ...domain.Role) func(http.Handler) http.Handler {
+	helper := response.NewResponseWriter(log)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(strings.TrimSpace(r.Header.Get("Authorization")), "Bearer ")
+			if !ok {
+				helper.InvalidCredentialsResponse(w, r)
+				return
+			}
+
+			principal, err := auth.Authenticate(r.Context(), strings.TrimSpace(token))
+			switch {
+			case errors.Is(err, domain.ErrInvalidCredentials):
+				helper.InvalidCredentialsResponse(w, r)
+				return
+			case err != nil:
+				helper.ServerErrorResponse(w, r, err)
+				return
+			case !slices.Contains(roles, principal.Role):
+				helper.ErrorCodeResponse(w, r, http.StatusForbidden, "forbidden")
+				return
+			}
+
+			ctx := ports.WithPrincipal(r.Context(), principal)
+			ctx = ports.WithLogFields(ctx, ports.String("principal", string(principal.Role)+":"+principal.ID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/adapters/security"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	auth := security.NewTokenAuthenticator("admin-token", map[domain.Role]map[string]string{
+		domain.RoleConsumer:  {"club": "club-token"},
+		domain.RoleModerator: {"ana": "ana-token"},
+		domain.RolePlayer:    {"5f0c6b8e-3b8a-4c1e-9d7a-2f4e6a8b1c3d": "magnus-token"},
+	})
+
+	var got domain.Principal
+	principal := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ports.PrincipalFromContext(r.Context())
+	})
+	consumers := Authenticate(logger.NewZapLogger("test"), auth, domain.RoleConsumer)(principal)
+	moderators := Authenticate(logger.NewZapLogger("test"), auth, domain.RoleModerator, domain.RoleAdmin)(principal)
+
+	tests := []struct {
+		name          string
+		handler       http.Handler
+		authorization string
+		wantStatus    int
+		wantCode      string
+		wantPrincipal domain.Principal
+	}{
+		{name: "consumer", handler: consumers, authorization: "Bearer club-token", wantStatus: http.StatusOK, wantPrincipal: domain.Principal{Role: domain.RoleConsumer, ID: "club"}},
+		{name: "no token", handler: consumers, wantStatus: http.StatusUnauthorized, wantCode: "invalid_credentials"},
+		{name: "not a bearer", handler: consumers, authorization: "Basic club-token", wantStatus: http.StatusUnauthorized, wantCode: "invalid_credentials"},
+		{name: "unknown token", handler: consumers, authorization: "Bearer nobody", wantStatus: http.StatusUnauthorized, wantCode: "invalid_credentials"},
+		{name: "another role", handler: consumers, authorization: "Bearer admin-token", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "moderator", handler: moderators, authorization: "Bearer ana-token", wantStatus: http.StatusOK, wantPrincipal: domain.Principal{Role: domain.RoleModerator, ID: "ana"}},
+		{name: "admin moderating", handler: moderators, authorization: "Bearer admin-token", wantStatus: http.StatusOK, wantPrincipal: domain.Principal{Role: domain.RoleAdmin, ID: "admin"}},
+		{name: "consumer moderating", handler: moderators, authorization: "Bearer club-token", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "player moderating", handler: moderators, authorization: "Bearer magnus-token", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.Principal{}
+			req := httptest.NewRequest(http.MethodGet, "/v1/webhook/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, errorCode(t, rec))
+			}
+			assert.Equal(t, tt.wantPrincipal, got)
+		})
+	}
+}
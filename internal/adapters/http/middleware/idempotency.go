@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// IdempotencyKeyHeader is sent by the clients that retry a POST, e.g. with a uuid per request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on the responses replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes caps the body read to fingerprint it, the json of the api is far smaller
+	maxIdempotentBodyBytes = 1 << 20
+)
+
+// Idempotency replays the response of the first POST of an Idempotency-Key to its retries. A
+// key belongs to the consumer, the credentials of the Authorization header, and to the method and
+// path of the request. A retry with another body is answered 422 and one sent while the first is
+// in flight 409. The server errors are not stored, the request can be retried. It is mounted on
+// the routes that create a resource, never on the ones answering credentials such as the login
+func Idempotency(log ports.Logger, store ports.IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	helper := response.NewResponseWriter(log)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !validIdempotencyKey(idempotencyKey) {
+				helper.ErrorCodeResponse(w, r, http.StatusBadRequest, "idempotency_key_invalid")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					helper.ErrorCodeResponse(w, r, http.StatusRequestEntityTooLarge, "request_body_too_large")
+					return
+				}
+				helper.BadRequestResponse(w, r, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key := scopedIdempotencyKey(r, idempotencyKey)
+			fingerprint := sha256.Sum256(body)
+			record, err := store.Begin(r.Context(), key, hex.EncodeToString(fingerprint[:]), ttl)
+			switch {
+			case errors.Is(err, domain.ErrIdempotencyKeyInFlight):
+				helper.ErrorCodeResponse(w, r, http.StatusConflict, "idempotency_key_in_flight")
+				return
+			case errors.Is(err, domain.ErrIdempotencyKeyReused):
+				helper.ErrorCodeResponse(w, r, http.StatusUnprocessableEntity, "idempotency_key_reused")
+				return
+			case err != nil:
+				helper.ServerErrorResponse(w, r, err)
+				return
+			case record != nil:
+				log.Debug(r.Context(), "idempotent response replayed", ports.String("idempotency_key", idempotencyKey))
+				replay(w, *record.Response)
+				return
+			}
+
+			// the key is settled even when the client is gone, its retry must find it
+			ctx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := store.Release(ctx, key); err != nil {
+					log.Error(ctx, "releasing the idempotency key", ports.Error("error", err))
+				}
+			}()
+
+			// the headers of the middlewares before this one are the ones of this request, such as
+			// its Content-Language, they are not replayed
+			before := w.Header().Clone()
+			var captured bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&captured)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			stored := domain.IdempotentResponse{
+				Status: status,
+				Header: handlerHeader(before, ww.Header()),
+				Body:   captured.Bytes(),
+			}
+			if err := store.Complete(ctx, key, stored); err != nil {
+				log.Error(ctx, "storing the idempotent response", ports.Error("error", err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// scopedIdempotencyKey keeps the keys of a consumer and a route apart, the credentials are hashed
+// so they are not stored
+func scopedIdempotencyKey(r *http.Request, idempotencyKey string) string {
+	h := sha256.New()
+	for _, part := range []string{r.Header.Get("Authorization"), r.Method, r.URL.Path, idempotencyKey} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := range len(key) {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// handlerHeader is the header the handler set, the values of before it changed are its own too
+func handlerHeader(before, after http.Header) http.Header {
+	header := make(http.Header)
+	for name, values := range after {
+		if !slices.Equal(before[name], values) {
+			header[name] = slices.Clone(values)
+		}
+	}
+	return header
+}
+
+func replay(w http.ResponseWriter, stored domain.IdempotentResponse) {
+	for name, values := range stored.Header {
+		w.Header()[name] = slices.Clone(values)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTournaments answers every request with a new tournament, like the handler of /v1/tournament/new
+func newTournaments(created *atomic.Int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := created.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/v1/tournament/find/%d", id))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"tournament":{"id":"%d"}}`, id)
+	})
+}
+
+func idempotentRequest(method, path, key, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Code
+}
+
+func TestIdempotency(t *testing.T) {
+	log := logger.NewZapLogger("test")
+
+	t.Run("a retry is replayed", func(t *testing.T) {
+		var created atomic.Int64
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+		first := httptest.NewRecorder()
+		handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+		retry := httptest.NewRecorder()
+		handler.ServeHTTP(retry, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+
+		assert.Equal(t, int64(1), created.Load())
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "/v1/tournament/find/1", retry.Header().Get("Location"))
+		assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("a key reused with another body", func(t *testing.T) {
+		var created atomic.Int64
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"blitz"}`))
+
+		assert.Equal(t, int64(1), created.Load())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "idempotency_key_reused", errorCode(t, rec))
+	})
+
+	t.Run("a retry while the first is in flight", func(t *testing.T) {
+		var created atomic.Int64
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			newTournaments(&created).ServeHTTP(w, r)
+		}))
+
+		first := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+		}()
+		<-started
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+		close(release)
+		<-done
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "idempotency_key_in_flight", errorCode(t, rec))
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, int64(1), created.Load())
+	})
+
+	t.Run("a server error is not stored", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		first := httptest.NewRecorder()
+		handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{}`))
+		retry := httptest.NewRecorder()
+		handler.ServeHTTP(retry, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{}`))
+
+		assert.Equal(t, http.StatusServiceUnavailable, first.Code)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("the body reaches the handler", func(t *testing.T) {
+		var got string
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			got = string(body)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`))
+
+		assert.Equal(t, `{"name":"open"}`, got)
+	})
+
+	t.Run("a body too large", func(t *testing.T) {
+		var created atomic.Int64
+		handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+		rec := httptest.NewRecorder()
+		body := `{"name":"` + strings.Repeat("x", maxIdempotentBodyBytes) + `"}`
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", body))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "request_body_too_large", errorCode(t, rec))
+		assert.Zero(t, created.Load())
+	})
+
+	t.Run("the keys are scoped", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			retry func(*http.Request)
+		}{
+			{name: "by path", retry: func(r *http.Request) { r.URL.Path = "/v1/player/new" }},
+			{name: "by consumer", retry: func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var created atomic.Int64
+				handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+				first := idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`)
+				first.Header.Set("Authorization", "Bearer token")
+				handler.ServeHTTP(httptest.NewRecorder(), first)
+
+				retry := idempotentRequest(http.MethodPost, "/v1/tournament/new", "key", `{"name":"open"}`)
+				retry.Header.Set("Authorization", "Bearer token")
+				tt.retry(retry)
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, retry)
+
+				assert.Equal(t, int64(2), created.Load())
+				assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+			})
+		}
+	})
+
+	t.Run("requests that are not deduplicated", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			key    string
+		}{
+			{name: "no key", method: http.MethodPost},
+			{name: "not a post", method: http.MethodPut, key: "key"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var created atomic.Int64
+				handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+				for range 2 {
+					rec := httptest.NewRecorder()
+					handler.ServeHTTP(rec, idempotentRequest(tt.method, "/v1/tournament/new", tt.key, `{}`))
+					assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+				}
+				assert.Equal(t, int64(2), created.Load())
+			})
+		}
+	})
+
+	t.Run("an invalid key", func(t *testing.T) {
+		tests := []struct {
+			name string
+			key  string
+		}{
+			{name: "too long", key: strings.Repeat("k", 256)},
+			{name: "with a space", key: "my key"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var created atomic.Int64
+				handler := Idempotency(log, inmemory.NewInMemoryIdempotencyStore(), time.Hour)(newTournaments(&created))
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "/v1/tournament/new", tt.key, `{}`))
+
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Equal(t, "idempotency_key_invalid", errorCode(t, rec))
+				assert.Zero(t, created.Load())
+			})
+		}
+	})
+}
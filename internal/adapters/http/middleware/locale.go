@@ -0,0 +1,23 @@
+// Package middleware holds the http middlewares of the maple api
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ctfrancia/maple/internal/core/i18n"
+)
+
+// Locale negotiates the locale of the request from the Accept-Language header and stores it
+// in the request context, see i18n.FromContext. The response says which one was picked
+func Locale(bundle *i18n.Bundle) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := bundle.Negotiate(r.Header.Get("Accept-Language"))
+
+			w.Header().Set("Content-Language", string(locale))
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale(t *testing.T) {
+	helper := response.NewResponseWriter(logger.NewZapLogger("test"))
+	handler := Locale(i18n.Default())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		helper.FailedValidationResponse(w, r, validation.Errors{"name": validation.TooShort(3)})
+	}))
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLocale     string
+		wantMessage    string
+	}{
+		{name: "no header", acceptLanguage: "", wantLocale: "en", wantMessage: "must be at least 3 characters"},
+		{name: "catalan", acceptLanguage: "ca-ES,ca;q=0.9,en;q=0.5", wantLocale: "ca", wantMessage: "ha de tenir com a mínim 3 caràcters"},
+		{name: "spanish", acceptLanguage: "fr, es;q=0.8", wantLocale: "es", wantMessage: "debe tener al menos 3 caracteres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Equal(t, tt.wantLocale, rec.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+
+			var body struct {
+				Code   string `json:"code"`
+				Errors map[string]struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, response.CodeValidationFailed, body.Code)
+			assert.Equal(t, validation.CodeTooShort, body.Errors["name"].Code)
+			assert.Equal(t, tt.wantMessage, body.Errors["name"].Message)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Metrics counts the requests, their latency and the ones in flight per chi route pattern, so
+// /v1/tournament/find/{id} is one series whatever the id. The requests no route matched are
+// counted under "unmatched"
+func Metrics(m ports.Metrics) func(http.Handler) http.Handler {
+	inFlight := m.Gauge("maple_http_requests_in_flight", "HTTP requests being served.")
+	requests := m.Counter("maple_http_requests_total", "HTTP requests served.", "method", "route", "status")
+	latency := m.Histogram("maple_http_request_duration_seconds", "Latency of the HTTP requests.", nil, "method", "route")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			start := time.Now()
+			// keeps the flusher and the hijacker of the live streams
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			requests.Inc(r.Method, route, strconv.Itoa(status))
+			latency.Observe(time.Since(start).Seconds(), r.Method, route)
+		})
+	}
+}
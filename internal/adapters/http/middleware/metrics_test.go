@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	mux := chi.NewMux()
+	mux.Use(Metrics(registry))
+	mux.Get("/v1/tournament/find/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.Get("/v1/tournament", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	})
+
+	for _, path := range []string{"/v1/tournament/find/1", "/v1/tournament/find/2", "/v1/tournament", "/nowhere"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var out strings.Builder
+	require.NoError(t, registry.Write(&out))
+	text := out.String()
+
+	assert.Contains(t, text, `maple_http_requests_total{method="GET",route="/v1/tournament/find/{id}",status="404"} 2`+"\n")
+	assert.Contains(t, text, `maple_http_requests_total{method="GET",route="/v1/tournament",status="200"} 1`+"\n")
+	assert.Contains(t, text, `maple_http_requests_total{method="GET",route="unmatched",status="404"} 1`+"\n")
+	assert.Contains(t, text, `maple_http_request_duration_seconds_count{method="GET",route="/v1/tournament/find/{id}"} 2`+"\n")
+	assert.Contains(t, text, "maple_http_requests_in_flight 0\n")
+}
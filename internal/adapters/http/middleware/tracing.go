@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Tracing starts the server span of the request, continuing the trace of the caller when its
+// traceparent header has one. The tracer is stored in the request context so the services
+// and the clients add their spans to the trace. The span is named after the chi route
+// pattern once the route is matched
+func Tracing(tracer ports.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			carrier := map[string]string{
+				"traceparent": r.Header.Get("traceparent"),
+				"tracestate":  r.Header.Get("tracestate"),
+			}
+			ctx := ports.WithTracer(tracer.Extract(r.Context(), carrier), tracer)
+			ctx, span := tracer.Start(ctx, r.Method, ports.SpanKindServer,
+				ports.String("http.request.method", r.Method),
+				ports.String("url.path", r.URL.Path),
+			)
+			defer span.End()
+
+			// keeps the flusher and the hijacker of the live streams
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(ports.String("http.route", rctx.RoutePattern()))
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(ports.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.RecordError(fmt.Errorf("%d %s", status, http.StatusText(status)))
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/adapters/tracing"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// writtenSpan is the part of the lines of the writer exporter the test reads
+type writtenSpan struct {
+	Name        string
+	SpanKind    int
+	SpanContext struct{ TraceID, SpanID string }
+	Parent      struct{ SpanID string }
+	Status      struct{ Code, Description string }
+	Attributes  []struct {
+		Key   string
+		Value struct{ Value any }
+	}
+}
+
+func (s writtenSpan) attribute(key string) any {
+	for _, attribute := range s.Attributes {
+		if attribute.Key == key {
+			return attribute.Value.Value
+		}
+	}
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := tracing.NewWriterExporter(&out)
+	require.NoError(t, err)
+	tracer := tracing.NewTracer(logger.NewZapLogger("test"), exporter, tracing.DefaultConfig())
+
+	mux := chi.NewMux()
+	mux.Use(Tracing(tracer))
+	mux.Get("/v1/tournament/find/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// the services add their spans with the tracer of the context
+		_, span := ports.StartSpan(r.Context(), "TournamentServicer.FindTournament", ports.SpanKindInternal)
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/tournament/find/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var service, server writtenSpan
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &service))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &server))
+
+	assert.Equal(t, "GET /v1/tournament/find/{id}", server.Name)
+	assert.Equal(t, int(trace.SpanKindServer), server.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID)
+	assert.Equal(t, "Error", server.Status.Code)
+	assert.Equal(t, "500 Internal Server Error", server.Status.Description)
+	assert.Equal(t, "/v1/tournament/find/{id}", server.attribute("http.route"))
+
+	assert.Equal(t, server.SpanContext.TraceID, service.SpanContext.TraceID)
+	assert.Equal(t, server.SpanContext.SpanID, service.Parent.SpanID)
+}
@@ -0,0 +1,477 @@
+package openapi
+
+import (
+	"net/http"
+
+	admindto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/admin"
+	announcementdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/announcement"
+	challengedto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/challenge"
+	fidedto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/fide"
+	locationdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/location"
+	matchdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/match"
+	moderationdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/moderation"
+	notificationdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/notification"
+	playerdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+	ratingdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/rating"
+	relaydto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/relay"
+	systemdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/system"
+	tournamentdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/tournament"
+	webhookdto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/webhook"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// Route is an operation of the api as the router mounts it. The errors every route may answer
+// are added from its parameters: 400 and 422 for a body or a query, 404 for a path parameter and
+// 500 for all of them, Errors are the others
+type Route struct {
+	Method   string
+	Path     string // the pattern of the router, e.g. /v1/tournament/find/{id}
+	ID       string // operationId
+	Tag      string
+	Summary  string
+	Params   []Param // query and header parameters, the path ones are only listed to describe them
+	Request  any     // zero value of the body, nil when there is none
+	Optional bool    // the body may be left out
+	Status   int
+	Also     []int  // other statuses answered with the response, e.g. the 503 of the health checks
+	Key      string // key of the envelope of the response, empty when it is not wrapped
+	Response any    // zero value of the response, nil when there is no body
+	Content  string // media type of the response when it is not json
+	Errors   []int
+	// Admin and Consumer routes take the admin token or the token of an api consumer as bearer,
+	// either of them when both are set, 403 with another one
+	Admin    bool
+	Consumer bool
+	// Moderator routes take the token of a moderator or the admin token as bearer, 403 with another one
+	Moderator bool
+	// Player routes take the token of a player as bearer, the one of the player of the path when it has
+	// one, and the admin token too when Admin is set, 403 with another one
+	Player bool
+	// Arbiter routes take the token of an arbiter or the admin token as bearer, 403 with another one
+	Arbiter bool
+	// Idempotent routes take an Idempotency-Key, they may answer 409, 413 and 422 for it
+	Idempotent bool
+}
+
+// Param is a parameter of a route, Type is the one of its json schema
+type Param struct {
+	Name        string
+	In          string // path, query or header
+	Type        string
+	Description string
+	Enum        []string
+}
+
+func query(name, kind, description string, enum ...string) Param {
+	return Param{Name: name, In: "query", Type: kind, Description: description, Enum: enum}
+}
+
+func path(name, description string, enum ...string) Param {
+	return Param{Name: name, In: "path", Type: "string", Description: description, Enum: enum}
+}
+
+func values[T ~string](vs ...T) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = string(v)
+	}
+	return out
+}
+
+var (
+	categories = values(domain.TimeControlCategoryBullet, domain.TimeControlCategoryBlitz,
+		domain.TimeControlCategoryRapid, domain.TimeControlCategoryClassical)
+	ratingTypes = values(domain.RatingTypeStandard, domain.RatingTypeRapid, domain.RatingTypeBlitz)
+	subjects    = values(domain.SubjectTournament, domain.SubjectMatch, domain.SubjectPlayer, domain.SubjectChallenge)
+)
+
+// Routes are the operations of the router, a route mounted without being listed here or listed
+// without being mounted fails the tests
+func Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Path: "/livez", ID: "livez", Tag: "system",
+			Summary: "Whether the process is alive, 503 when it has to be restarted",
+			Status:  http.StatusOK, Also: []int{http.StatusServiceUnavailable},
+			Key: "health", Response: systemdto.HealthResponse{}},
+		{Method: http.MethodGet, Path: "/readyz", ID: "readyz", Tag: "system",
+			Summary: "Whether the dependencies are up, 503 while the instance should not get traffic",
+			Status:  http.StatusOK, Also: []int{http.StatusServiceUnavailable},
+			Key: "health", Response: systemdto.HealthResponse{}},
+		{Method: http.MethodGet, Path: "/metrics", ID: "metrics", Tag: "system",
+			Summary: "Prometheus metrics, only mounted when the metrics are enabled",
+			Status:  http.StatusOK, Content: "text/plain"},
+		{Method: http.MethodGet, Path: "/v1/openapi.json", ID: "openapi", Tag: "system",
+			Summary: "This document",
+			Status:  http.StatusOK, Response: map[string]any{}},
+		{Method: http.MethodGet, Path: "/v1/docs", ID: "docs", Tag: "system",
+			Summary: "Interactive documentation of the api",
+			Status:  http.StatusOK, Content: "text/html"},
+
+		// system
+		{Method: http.MethodGet, Path: "/v1/system/health", ID: "health", Tag: "system",
+			Summary: "Version and build of the running api",
+			Status:  http.StatusOK, Key: "system", Response: systemdto.SystemResponse{}},
+		{Method: http.MethodPost, Path: "/v1/system/login", ID: "login", Tag: "system",
+			Summary: "Log in as an api consumer",
+			Request: systemdto.SystemLoginRequest{},
+			Status:  http.StatusOK, Response: map[string]any{}},
+		{Method: http.MethodPost, Path: "/v1/system/new-consumer", ID: "newConsumer", Tag: "system",
+			Summary: "Register an api consumer",
+			Request: systemdto.NewAPIConsumerRequest{},
+			Status:  http.StatusCreated, Response: domain.NewAPIConsumer{}},
+
+		// tournament
+		{Method: http.MethodGet, Path: "/v1/tournament/", ID: "listTournaments", Tag: "tournament",
+			Summary: "Search the tournaments",
+			Params: []Param{
+				query("category", "string", "", categories...),
+				query("time_control", "string", "notation of the time control, e.g. 90+30"),
+			},
+			Status: http.StatusOK, Key: "tournaments", Response: []tournamentdto.TournamentResponse{}},
+		{Method: http.MethodGet, Path: "/v1/tournament/find/{id}", ID: "findTournament", Tag: "tournament",
+			Summary: "Find a tournament",
+			Status:  http.StatusOK, Key: "tournament", Response: tournamentdto.TournamentResponse{}},
+		{Method: http.MethodPost, Path: "/v1/tournament/new", ID: "createTournament", Tag: "tournament", Consumer: true, Idempotent: true,
+			Summary: "Create a tournament, it is a draft until it is submitted",
+			Request: tournamentdto.CreateTournamentRequest{},
+			Status:  http.StatusCreated, Key: "tournament", Response: tournamentdto.TournamentResponse{},
+			Errors: []int{http.StatusForbidden, http.StatusNotFound, http.StatusServiceUnavailable}},
+		{Method: http.MethodPost, Path: "/v1/tournament/{id}/{action}", ID: "transitionTournament", Tag: "tournament", Consumer: true, Admin: true,
+			Summary: "Change the status of a tournament, the consumer that posted it or the admin",
+			Params: []Param{path("action", "", values(domain.TournamentActionSubmit, domain.TournamentActionStart,
+				domain.TournamentActionSuspend, domain.TournamentActionResume, domain.TournamentActionComplete,
+				domain.TournamentActionDeactivate, domain.TournamentActionReopen)...)},
+			Request: tournamentdto.TransitionTournamentRequest{}, Optional: true,
+			Status: http.StatusOK, Key: "tournament", Response: tournamentdto.TournamentResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/v1/tournament/{id}/players", ID: "registerPlayer", Tag: "tournament",
+			Summary: "Register a player for a tournament that has not started",
+			Request: tournamentdto.RegisterPlayerRequest{},
+			Status:  http.StatusOK, Key: "tournament", Response: tournamentdto.TournamentResponse{},
+			Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusServiceUnavailable}},
+		{Method: http.MethodPost, Path: "/v1/tournament/{id}/rounds", ID: "pairRound", Tag: "tournament",
+			Summary: "Publish the pairings of the next round of an active tournament",
+			Request: tournamentdto.PairRoundRequest{},
+			Status:  http.StatusCreated, Key: "round", Response: tournamentdto.RoundResponse{},
+			Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/tournament/{id}/live", ID: "streamTournament", Tag: "tournament",
+			Summary: "Server-sent events of the tournament, resumed from the Last-Event-ID header",
+			Params: []Param{{Name: "Last-Event-ID", In: "header", Type: "string",
+				Description: "id of the last event received before the connection dropped"}},
+			Status: http.StatusOK, Content: "text/event-stream",
+			Errors: []int{http.StatusForbidden, http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/tournament/{id}/live/ws", ID: "tournamentWebSocket", Tag: "tournament",
+			Summary: "Events of the tournament over a websocket",
+			Params: []Param{query("last_event_id", "string",
+				"id of the last event received before the connection dropped")},
+			Status: http.StatusSwitchingProtocols,
+			Errors: []int{http.StatusForbidden, http.StatusServiceUnavailable}},
+
+		// player
+		{Method: http.MethodPost, Path: "/v1/player/new", ID: "createPlayer", Tag: "player", Idempotent: true,
+			Summary: "Create a player",
+			Request: playerdto.CreatePlayerRequest{},
+			Status:  http.StatusCreated, Key: "player", Response: playerdto.PlayerProfileResponse{}},
+		{Method: http.MethodGet, Path: "/v1/player/find/{id}", ID: "findPlayer", Tag: "player",
+			Summary: "Find a player",
+			Status:  http.StatusOK, Key: "player", Response: playerdto.PlayerProfileResponse{}},
+		{Method: http.MethodPut, Path: "/v1/player/{id}", ID: "updatePlayer", Tag: "player", Admin: true, Player: true,
+			Summary: "Update a player",
+			Request: playerdto.UpdatePlayerRequest{},
+			Status:  http.StatusOK, Key: "player", Response: playerdto.PlayerProfileResponse{}},
+		{Method: http.MethodGet, Path: "/v1/player/{id}/ratings", ID: "ratingHistory", Tag: "player",
+			Summary: "Rating history of a player",
+			Params: []Param{
+				query("source", "string", "", values(domain.RatingSourceFIDE, domain.RatingSourceRegional, domain.RatingSourceMaple)...),
+				query("type", "string", "", ratingTypes...),
+			},
+			Status: http.StatusOK, Key: "rating_history", Response: []playerdto.RatingChangeResponse{}},
+		{Method: http.MethodPost, Path: "/v1/player/{id}/ratings", ID: "recordRatingChange", Tag: "player", Moderator: true, Idempotent: true,
+			Summary: "Record a change of rating of a player",
+			Request: playerdto.RecordRatingChangeRequest{},
+			Status:  http.StatusCreated, Key: "rating_change", Response: playerdto.RatingChangeResponse{}},
+		{Method: http.MethodGet, Path: "/v1/player/{id}/head-to-head/{opponentID}", ID: "headToHead", Tag: "player",
+			Summary: "Results of a player against another",
+			Status:  http.StatusOK, Key: "head_to_head", Response: playerdto.HeadToHeadResponse{}},
+
+		// match
+		{Method: http.MethodGet, Path: "/v1/match/", ID: "listMatches", Tag: "match",
+			Summary: "Search the matches",
+			Params: []Param{
+				query("player", "string", "public id of a player of the match"),
+				query("category", "string", "", categories...),
+				query("time_control", "string", "notation of the time control, e.g. 90+30"),
+			},
+			Status: http.StatusOK, Key: "matches", Response: []matchdto.MatchResponse{}},
+		{Method: http.MethodPost, Path: "/v1/match/new", ID: "createMatch", Tag: "match", Idempotent: true,
+			Summary: "Create a match",
+			Request: matchdto.CreateMatchRequest{},
+			Status:  http.StatusCreated, Key: "match", Response: matchdto.MatchResponse{}},
+		{Method: http.MethodGet, Path: "/v1/match/find/{id}", ID: "findMatch", Tag: "match",
+			Summary: "Find a match",
+			Status:  http.StatusOK, Key: "match", Response: matchdto.MatchResponse{}},
+		{Method: http.MethodPost, Path: "/v1/match/{id}/result", ID: "recordResult", Tag: "match",
+			Summary: "Record the result of a match",
+			Request: matchdto.RecordResultRequest{},
+			Status:  http.StatusOK, Key: "match", Response: matchdto.MatchResponse{}},
+		{Method: http.MethodPost, Path: "/v1/match/{id}/relay", ID: "startRelay", Tag: "relay", Arbiter: true, Idempotent: true,
+			Summary: "Start relaying the moves of a match",
+			Request: relaydto.StartRelayRequest{}, Optional: true,
+			Status: http.StatusCreated, Key: "relay", Response: relaydto.RelayResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodGet, Path: "/v1/match/{id}/relay", ID: "findRelay", Tag: "relay",
+			Summary: "Moves relayed of a match",
+			Status:  http.StatusOK, Key: "relay", Response: relaydto.RelayResponse{}},
+		{Method: http.MethodPost, Path: "/v1/match/{id}/relay/moves", ID: "recordMove", Tag: "relay", Arbiter: true,
+			Summary: "Relay a move",
+			Request: relaydto.RecordMoveRequest{},
+			Status:  http.StatusOK, Key: "relay", Response: relaydto.RelayResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/v1/match/{id}/relay/finish", ID: "finishRelay", Tag: "relay", Arbiter: true,
+			Summary: "Finish the relay with the result of the match",
+			Request: relaydto.FinishRelayRequest{},
+			Status:  http.StatusOK, Key: "relay", Response: relaydto.RelayResponse{},
+			Errors: []int{http.StatusConflict}},
+
+		// challenge
+		{Method: http.MethodGet, Path: "/v1/challenge/", ID: "listChallenges", Tag: "challenge",
+			Summary: "Open challenges, near a point or the ones a player can accept",
+			Params: []Param{
+				query("category", "string", "", categories...),
+				query("lat", "number", "latitude of the player"),
+				query("lng", "number", "longitude of the player"),
+				query("radius_km", "number", "distance from lat and lng"),
+				query("min_rating", "integer", ""),
+				query("max_rating", "integer", ""),
+				query("player", "string", "public id of the player, only the challenges they can accept"),
+			},
+			Status: http.StatusOK, Key: "challenges", Response: []challengedto.ChallengeResponse{}},
+		{Method: http.MethodPost, Path: "/v1/challenge/new", ID: "createChallenge", Tag: "challenge", Consumer: true, Idempotent: true,
+			Summary: "Post a challenge",
+			Request: challengedto.CreateChallengeRequest{},
+			Status:  http.StatusCreated, Key: "challenge", Response: challengedto.ChallengeResponse{},
+			Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: http.MethodGet, Path: "/v1/challenge/find/{id}", ID: "findChallenge", Tag: "challenge",
+			Summary: "Find a challenge",
+			Status:  http.StatusOK, Key: "challenge", Response: challengedto.ChallengeResponse{}},
+		{Method: http.MethodPost, Path: "/v1/challenge/{id}/accept", ID: "acceptChallenge", Tag: "challenge", Player: true,
+			Summary: "Accept a challenge as the player of the token, the match is created",
+			Status:  http.StatusOK, Key: "challenge", Response: challengedto.ChallengeResponse{},
+			Errors: []int{http.StatusForbidden, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/v1/challenge/{id}/cancel", ID: "cancelChallenge", Tag: "challenge", Player: true,
+			Summary: "Cancel a challenge of the player of the token",
+			Status:  http.StatusOK, Key: "challenge", Response: challengedto.ChallengeResponse{},
+			Errors: []int{http.StatusForbidden, http.StatusConflict}},
+
+		// rating
+		{Method: http.MethodGet, Path: "/v1/rating/{pool}", ID: "listRatings", Tag: "rating",
+			Summary: "Leaderboard of a pool",
+			Params: []Param{
+				path("pool", "", ratingTypes...),
+				query("provisional", "boolean", "include the provisional ratings"),
+			},
+			Status: http.StatusOK, Key: "ratings", Response: []ratingdto.RatingResponse{}},
+		{Method: http.MethodGet, Path: "/v1/rating/{pool}/{playerID}", ID: "findRating", Tag: "rating",
+			Summary: "Rating of a player in a pool",
+			Params:  []Param{path("pool", "", ratingTypes...)},
+			Status:  http.StatusOK, Key: "rating", Response: ratingdto.RatingResponse{}},
+		{Method: http.MethodPost, Path: "/v1/rating/recompute", ID: "recomputeRatings", Tag: "rating", Admin: true,
+			Summary: "Recompute the ratings from the results",
+			Status:  http.StatusOK, Key: "recompute", Response: ratingdto.RecomputeResponse{}},
+
+		// fide
+		{Method: http.MethodPost, Path: "/v1/fide/import", ID: "importRatingList", Tag: "fide", Admin: true,
+			Summary: "Queue the import of a FIDE rating list of the import directory",
+			Request: fidedto.ImportRatingListRequest{},
+			Status:  http.StatusAccepted, Key: "import", Response: fidedto.ImportJobResponse{},
+			Errors: []int{http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/fide/import/{id}", ID: "findFideImport", Tag: "fide", Admin: true,
+			Summary: "How an import of a FIDE rating list is going",
+			Status:  http.StatusOK, Key: "import", Response: fidedto.ImportJobResponse{}},
+		{Method: http.MethodGet, Path: "/v1/fide/player/{fideID}", ID: "findFideRecord", Tag: "fide",
+			Summary: "Record of a FIDE id",
+			Params:  []Param{query("period", "string", "period of the rating list, the latest by default")},
+			Status:  http.StatusOK, Key: "record", Response: fidedto.RecordResponse{}},
+		{Method: http.MethodGet, Path: "/v1/fide/candidates/{playerID}", ID: "fideCandidates", Tag: "fide",
+			Summary: "FIDE records that may be the player",
+			Params:  []Param{query("limit", "integer", "")},
+			Status:  http.StatusOK, Key: "candidates", Response: []fidedto.CandidateResponse{}},
+		{Method: http.MethodPost, Path: "/v1/fide/confirm", ID: "confirmFideMatch", Tag: "fide", Admin: true,
+			Summary: "Link a player to their FIDE id",
+			Request: fidedto.ConfirmMatchRequest{},
+			Status:  http.StatusOK, Key: "player", Response: fidedto.LinkedPlayerResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/v1/fide/refresh/{tournamentID}", ID: "refreshTournamentRatings", Tag: "fide", Admin: true,
+			Summary: "Refresh the FIDE ratings of the players of a tournament",
+			Request: fidedto.RefreshRequest{}, Optional: true,
+			Status: http.StatusOK, Key: "refresh", Response: fidedto.RefreshResponse{}},
+
+		// announcement
+		{Method: http.MethodPost, Path: "/v1/announcement/import", ID: "importChat", Tag: "announcement", Admin: true,
+			Summary: "Queue the import of the announcements of a chat export of the import directory",
+			Request: announcementdto.ImportChatRequest{},
+			Status:  http.StatusAccepted, Key: "import", Response: announcementdto.ImportJobResponse{},
+			Errors: []int{http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/announcement/import/{id}", ID: "findChatImport", Tag: "announcement", Admin: true,
+			Summary: "How an import of a chat export is going",
+			Status:  http.StatusOK, Key: "import", Response: announcementdto.ImportJobResponse{}},
+		{Method: http.MethodGet, Path: "/v1/announcement/", ID: "listAnnouncements", Tag: "announcement", Admin: true,
+			Summary: "Announcements imported",
+			Params: []Param{query("status", "string", "", values(domain.AnnouncementPending,
+				domain.AnnouncementConfirmed, domain.AnnouncementRejected)...)},
+			Status: http.StatusOK, Key: "announcements", Response: []announcementdto.AnnouncementResponse{}},
+		{Method: http.MethodGet, Path: "/v1/announcement/find/{id}", ID: "findAnnouncement", Tag: "announcement", Admin: true,
+			Summary: "Find an announcement",
+			Status:  http.StatusOK, Key: "announcement", Response: announcementdto.AnnouncementResponse{}},
+		{Method: http.MethodPost, Path: "/v1/announcement/{id}/confirm", ID: "confirmAnnouncement", Tag: "announcement", Admin: true,
+			Summary: "Create a draft tournament from an announcement",
+			Request: announcementdto.ConfirmAnnouncementRequest{}, Optional: true,
+			Status: http.StatusCreated, Key: "announcement", Response: announcementdto.AnnouncementResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/v1/announcement/{id}/reject", ID: "rejectAnnouncement", Tag: "announcement", Admin: true,
+			Summary: "Reject an announcement",
+			Request: announcementdto.RejectAnnouncementRequest{}, Optional: true,
+			Status: http.StatusOK, Key: "announcement", Response: announcementdto.AnnouncementResponse{},
+			Errors: []int{http.StatusConflict}},
+
+		// moderation
+		{Method: http.MethodPost, Path: "/v1/moderation/report", ID: "report", Tag: "moderation", Idempotent: true,
+			Summary: "Report a listing",
+			Request: moderationdto.ReportRequest{},
+			Status:  http.StatusCreated, Key: "report", Response: moderationdto.ReportResponse{},
+			Errors: []int{http.StatusNotFound, http.StatusConflict}},
+		{Method: http.MethodGet, Path: "/v1/moderation/queue", ID: "moderationQueue", Tag: "moderation", Moderator: true,
+			Summary: "Listings waiting for a moderator",
+			Params:  []Param{query("type", "string", "", subjects...)},
+			Status:  http.StatusOK, Key: "queue", Response: []moderationdto.QueueItemResponse{}},
+		{Method: http.MethodGet, Path: "/v1/moderation/consumer/{consumerID}", ID: "findStanding", Tag: "moderation", Moderator: true,
+			Summary: "Standing of an api consumer",
+			Status:  http.StatusOK, Key: "standing", Response: moderationdto.StandingResponse{}},
+		{Method: http.MethodPost, Path: "/v1/moderation/consumer/{consumerID}/reinstate", ID: "reinstateConsumer", Tag: "moderation", Moderator: true,
+			Summary: "Lift the suspension of an api consumer",
+			Request: moderationdto.ModeratorRequest{}, Optional: true,
+			Status: http.StatusOK, Key: "standing", Response: moderationdto.StandingResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodGet, Path: "/v1/moderation/{type}/{id}/history", ID: "moderationHistory", Tag: "moderation", Moderator: true,
+			Summary: "Reports and decisions of a listing",
+			Params:  []Param{path("type", "", subjects...)},
+			Status:  http.StatusOK, Key: "history", Response: moderationdto.HistoryResponse{}},
+		{Method: http.MethodPost, Path: "/v1/moderation/{type}/{id}/{action}", ID: "moderate", Tag: "moderation", Moderator: true,
+			Summary: "Decide on a listing",
+			Params: []Param{
+				path("type", "", subjects...),
+				path("action", "", values(domain.ModerationApprove, domain.ModerationReject, domain.ModerationHide)...),
+			},
+			Request: moderationdto.ModeratorRequest{}, Optional: true,
+			Status: http.StatusOK, Key: "decision", Response: moderationdto.DecisionResponse{}},
+
+		// location
+		{Method: http.MethodGet, Path: "/v1/location/", ID: "listLocations", Tag: "location",
+			Summary: "Search the venues",
+			Params: []Param{
+				query("city", "string", ""),
+				query("country", "string", ""),
+				query("q", "string", "part of the name or the address"),
+			},
+			Status: http.StatusOK, Key: "locations", Response: []locationdto.LocationResponse{}},
+		{Method: http.MethodPost, Path: "/v1/location/new", ID: "createLocation", Tag: "location", Idempotent: true,
+			Summary: "Register a venue",
+			Request: locationdto.LocationRequest{},
+			Status:  http.StatusCreated, Key: "location", Response: locationdto.LocationResponse{},
+			Errors: []int{http.StatusConflict, http.StatusServiceUnavailable}},
+		{Method: http.MethodPost, Path: "/v1/location/duplicates", ID: "checkLocation", Tag: "location",
+			Summary: "Venues registered that may be the same as the one given",
+			Request: locationdto.LocationRequest{},
+			Status:  http.StatusOK, Key: "duplicates", Response: []locationdto.DuplicateResponse{}},
+		{Method: http.MethodGet, Path: "/v1/location/geocode", ID: "geocode", Tag: "location",
+			Summary: "Coordinates of an address",
+			Params: []Param{
+				query("address", "string", ""),
+				query("postal_code", "string", ""),
+				query("city", "string", ""),
+				query("province", "string", ""),
+				query("country", "string", ""),
+			},
+			Status: http.StatusOK, Key: "place", Response: locationdto.PlaceResponse{},
+			Errors: []int{http.StatusNotFound, http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/location/reverse", ID: "reverseGeocode", Tag: "location",
+			Summary: "Address of coordinates",
+			Params: []Param{
+				query("lat", "number", ""),
+				query("lng", "number", ""),
+			},
+			Status: http.StatusOK, Key: "place", Response: locationdto.PlaceResponse{},
+			Errors: []int{http.StatusNotFound, http.StatusServiceUnavailable}},
+		{Method: http.MethodGet, Path: "/v1/location/find/{id}", ID: "findLocation", Tag: "location",
+			Summary: "Find a venue",
+			Status:  http.StatusOK, Key: "location", Response: locationdto.LocationResponse{}},
+		{Method: http.MethodPut, Path: "/v1/location/{id}", ID: "updateLocation", Tag: "location", Moderator: true,
+			Summary: "Update a venue",
+			Request: locationdto.LocationRequest{},
+			Status:  http.StatusOK, Key: "location", Response: locationdto.LocationResponse{},
+			Errors: []int{http.StatusConflict, http.StatusServiceUnavailable}},
+		{Method: http.MethodDelete, Path: "/v1/location/{id}", ID: "deleteLocation", Tag: "location", Moderator: true,
+			Summary: "Delete a venue",
+			Status:  http.StatusNoContent,
+			Errors:  []int{http.StatusConflict}},
+		{Method: http.MethodGet, Path: "/v1/location/{id}/duplicates", ID: "findDuplicates", Tag: "location",
+			Summary: "Venues registered that may be the same as this one",
+			Status:  http.StatusOK, Key: "duplicates", Response: []locationdto.DuplicateResponse{}},
+		{Method: http.MethodPost, Path: "/v1/location/{id}/merge", ID: "mergeLocations", Tag: "location", Moderator: true,
+			Summary: "Merge duplicates into a venue",
+			Request: locationdto.MergeLocationsRequest{},
+			Status:  http.StatusOK, Key: "merge", Response: locationdto.MergeResponse{}},
+
+		// admin
+		{Method: http.MethodGet, Path: "/v1/admin/log-level", ID: "logLevel", Tag: "admin",
+			Summary: "Level of the logs, only mounted when an admin token is set",
+			Status:  http.StatusOK, Key: "log_level", Response: admindto.LogLevelResponse{},
+			Admin: true},
+		{Method: http.MethodPut, Path: "/v1/admin/log-level", ID: "setLogLevel", Tag: "admin",
+			Summary: "Change the level of the logs at runtime",
+			Request: admindto.LogLevelRequest{},
+			Status:  http.StatusOK, Key: "log_level", Response: admindto.LogLevelResponse{},
+			Admin: true},
+
+		// webhook
+		{Method: http.MethodGet, Path: "/v1/webhook/", ID: "listWebhooks", Tag: "webhook", Consumer: true,
+			Summary: "Webhooks of the consumer",
+			Status:  http.StatusOK, Key: "webhooks", Response: []webhookdto.WebhookResponse{}},
+		{Method: http.MethodPost, Path: "/v1/webhook/new", ID: "createWebhook", Tag: "webhook", Consumer: true, Idempotent: true,
+			Summary: "Subscribe to events, the secret signs the deliveries",
+			Request: webhookdto.CreateWebhookRequest{},
+			Status:  http.StatusCreated, Key: "webhook", Response: webhookdto.WebhookResponse{}},
+		{Method: http.MethodGet, Path: "/v1/webhook/find/{id}", ID: "findWebhook", Tag: "webhook", Consumer: true,
+			Summary: "Find a webhook",
+			Status:  http.StatusOK, Key: "webhook", Response: webhookdto.WebhookResponse{}},
+		{Method: http.MethodDelete, Path: "/v1/webhook/{id}", ID: "deleteWebhook", Tag: "webhook", Consumer: true,
+			Summary: "Delete a webhook",
+			Status:  http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/v1/webhook/{id}/enable", ID: "enableWebhook", Tag: "webhook", Consumer: true,
+			Summary: "Enable a webhook disabled after its failures",
+			Status:  http.StatusOK, Key: "webhook", Response: webhookdto.WebhookResponse{}},
+		{Method: http.MethodGet, Path: "/v1/webhook/{id}/deliveries", ID: "listDeliveries", Tag: "webhook", Consumer: true,
+			Summary: "Latest deliveries of a webhook",
+			Params:  []Param{query("limit", "integer", "")},
+			Status:  http.StatusOK, Key: "deliveries", Response: []webhookdto.DeliveryResponse{}},
+		{Method: http.MethodPost, Path: "/v1/webhook/delivery/{deliveryID}/redeliver", ID: "redeliver", Tag: "webhook", Consumer: true,
+			Summary: "Send a delivery again",
+			Status:  http.StatusCreated, Key: "delivery", Response: webhookdto.DeliveryResponse{},
+			Errors: []int{http.StatusConflict}},
+
+		// notification
+		{Method: http.MethodGet, Path: "/v1/notification/preferences/{playerID}", ID: "findPreferences", Tag: "notification", Admin: true, Player: true,
+			Summary: "Notification preferences of a player",
+			Status:  http.StatusOK, Key: "preferences", Response: notificationdto.PreferencesResponse{}},
+		{Method: http.MethodPut, Path: "/v1/notification/preferences/{playerID}", ID: "updatePreferences", Tag: "notification", Admin: true, Player: true,
+			Summary: "Update the notification preferences of a player",
+			Request: notificationdto.UpdatePreferencesRequest{},
+			Status:  http.StatusOK, Key: "preferences", Response: notificationdto.PreferencesResponse{}},
+		{Method: http.MethodGet, Path: "/v1/notification/unsubscribe/{token}", ID: "unsubscribeLink", Tag: "notification",
+			Summary: "Unsubscribe with the link of an email",
+			Status:  http.StatusOK, Key: "preferences", Response: notificationdto.PreferencesResponse{}},
+		{Method: http.MethodPost, Path: "/v1/notification/unsubscribe/{token}", ID: "unsubscribe", Tag: "notification",
+			Summary: "Unsubscribe with the token of an email, the one click unsubscribe of the mail clients",
+			Status:  http.StatusOK, Key: "preferences", Response: notificationdto.PreferencesResponse{}},
+	}
+}
@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// schemas turns the go types into json schemas, the named structs are components referenced by
+// their name. Names found in several packages are prefixed with the package, e.g. the
+// ImportSummaryResponse of fide and the one of announcement
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas(roots []reflect.Type) *schemas {
+	found := make(map[reflect.Type]bool)
+	for _, t := range roots {
+		collectStructs(t, found)
+	}
+
+	count := make(map[string]int)
+	for t := range found {
+		count[t.Name()]++
+	}
+	names := make(map[reflect.Type]string, len(found))
+	for t := range found {
+		name := t.Name()
+		if count[name] > 1 {
+			name = packagePrefix(t) + name
+		}
+		names[t] = name
+	}
+
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      names,
+	}
+}
+
+// collectStructs finds the named structs reachable from t
+func collectStructs(t reflect.Type, found map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		collectStructs(t.Elem(), found)
+	case reflect.Struct:
+		if t == timeType || found[t] {
+			return
+		}
+		if t.Name() != "" {
+			found[t] = true
+		}
+		for i := range t.NumField() {
+			collectStructs(t.Field(i).Type, found)
+		}
+	}
+}
+
+// packagePrefix is the last element of the package path in upper camel case, the dtos are in a
+// directory per area so it is the name of the area
+func packagePrefix(t reflect.Type) string {
+	path := t.PkgPath()
+	last := path[strings.LastIndex(path, "/")+1:]
+
+	var b strings.Builder
+	upper := true
+	for _, r := range last {
+		if r == '-' || r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// of is the schema of t, the named structs are added to the components on their first use
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return nullable(s.of(t.Elem()))
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		name, ok := s.names[t]
+		if !ok {
+			return s.object(t)
+		}
+		if _, ok := s.components[name]; !ok {
+			// reserved before the fields are walked, a struct may refer to itself
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.object(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + name}
+	default:
+		// an interface, the value may be anything
+		return &Schema{}
+	}
+}
+
+// object is the schema of the fields of a struct as encoding/json writes them, the fields without
+// omitempty are always there so they are required
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.fields(t, schema)
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			s.fields(sf.Type, schema)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		omitempty := strings.Contains(opts, "omitempty")
+		var field *Schema
+		if omitempty && sf.Type.Kind() == reflect.Pointer {
+			// a nil pointer is left out rather than written as null
+			field = s.of(sf.Type.Elem())
+		} else {
+			field = s.of(sf.Type)
+		}
+		schema.Properties[name] = field
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// nullable lets the value of the schema be null as well
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+	}
+	if kind, ok := schema.Type.(string); ok {
+		schema.Type = []string{kind, "null"}
+	}
+	return schema
+}
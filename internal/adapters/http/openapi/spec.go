@@ -0,0 +1,319 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/adapters/http/response"
+)
+
+//go:generate go test . -run TestSpecIsUpToDate -update
+
+// AdminScheme is the security scheme of the admin routes
+const AdminScheme = "adminToken"
+
+// ConsumerScheme is the security scheme of the routes of the api consumers
+const ConsumerScheme = "consumerToken"
+
+// ModeratorScheme is the security scheme of the routes of the moderators
+const ModeratorScheme = "moderatorToken"
+
+// PlayerScheme is the security scheme of the routes of the players
+const PlayerScheme = "playerToken"
+
+// ArbiterScheme is the security scheme of the routes of the arbiters
+const ArbiterScheme = "arbiterToken"
+
+var (
+	//go:embed openapi.json
+	spec []byte
+
+	//go:embed docs.html
+	docs []byte
+)
+
+// SpecHandler serves the committed document, Build is what it should be
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+// DocsHandler serves the docs of the api, the page reads the document of SpecHandler
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docs)
+}
+
+// errorResponses are the components of the error statuses, all of them are a response.ErrorBody
+var errorResponses = map[int]string{
+	http.StatusBadRequest:            "BadRequest",
+	http.StatusUnauthorized:          "Unauthorized",
+	http.StatusForbidden:             "Forbidden",
+	http.StatusNotFound:              "NotFound",
+	http.StatusConflict:              "Conflict",
+	http.StatusRequestEntityTooLarge: "PayloadTooLarge",
+	http.StatusUnprocessableEntity:   "ValidationFailed",
+	http.StatusInternalServerError:   "ServerError",
+	http.StatusServiceUnavailable:    "ServiceUnavailable",
+}
+
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:            "The request is malformed, e.g. the body is not json or an id is not a uuid",
+	http.StatusUnauthorized:          "The credentials are missing or wrong",
+	http.StatusForbidden:             "The consumer or the player is not allowed to do it",
+	http.StatusNotFound:              "The resource does not exist",
+	http.StatusConflict:              "The resource is not in a state that allows it, or the first request of the Idempotency-Key is in flight",
+	http.StatusRequestEntityTooLarge: "The body of the request is too large",
+	http.StatusUnprocessableEntity:   "The request is invalid, errors has the code and the message of every invalid field, or the Idempotency-Key was sent with another body",
+	http.StatusInternalServerError:   "Something went wrong on the server",
+	http.StatusServiceUnavailable:    "A dependency is down or the server is busy, the request can be retried",
+}
+
+// idempotencyKey is a parameter of the Idempotent routes, the first response of a key is replayed to its retries
+var idempotencyKey = Param{
+	Name: "Idempotency-Key",
+	In:   "header",
+	Type: "string",
+	Description: "Up to 255 printable characters, e.g. a uuid per request. The retries with the same body " +
+		"get the first response with Idempotent-Replayed: true",
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Build generates the document of the routes
+func Build() *Document {
+	routes := Routes()
+
+	roots := []reflect.Type{reflect.TypeFor[response.ErrorBody]()}
+	for _, route := range routes {
+		for _, v := range []any{route.Request, route.Response} {
+			if v != nil {
+				roots = append(roots, reflect.TypeOf(v))
+			}
+		}
+	}
+	s := newSchemas(roots)
+
+	errorSchema := s.of(reflect.TypeFor[response.ErrorBody]())
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title: "Maple API",
+			Description: "API of Maple, to find over the board chess tournaments, matches and challenges. " +
+				"The errors have a code that does not change with the Accept-Language of the request " +
+				"and a message translated to it.",
+			Version: "v1",
+		},
+		Servers: []Server{{URL: "/"}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas:   s.components,
+			Responses: make(map[string]*Response, len(errorResponses)),
+			SecuritySchemes: map[string]*SecurityScheme{
+				AdminScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The admin token of the configuration, the admin routes are not mounted without one",
+				},
+				ConsumerScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The token of an api consumer in the configuration, what it manages is its own",
+				},
+				ModeratorScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The token of a moderator in the configuration, the decisions are recorded as theirs",
+				},
+				PlayerScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The token of a player in the configuration, a player only acts on their own behalf",
+				},
+				ArbiterScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The token of an arbiter or a board in the configuration, they relay the games",
+				},
+			},
+		},
+	}
+	for status, name := range errorResponses {
+		doc.Components.Responses[name] = &Response{
+			Description: errorDescriptions[status],
+			Content:     map[string]*MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+
+	seenTags := make(map[string]bool)
+	for _, route := range routes {
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[route.Path] = item
+		}
+		op := operation(s, route)
+		switch route.Method {
+		case http.MethodGet:
+			item.Get = op
+		case http.MethodPut:
+			item.Put = op
+		case http.MethodPost:
+			item.Post = op
+		case http.MethodDelete:
+			item.Delete = op
+		}
+	}
+
+	return doc
+}
+
+// JSON is the document as it is committed
+func (d *Document) JSON() ([]byte, error) {
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func operation(s *schemas, route Route) *Operation {
+	op := &Operation{
+		OperationID: route.ID,
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		Responses:   make(map[string]*Response),
+	}
+
+	described := make(map[string]Param)
+	for _, p := range route.Params {
+		if p.In == "path" {
+			described[p.Name] = p
+		}
+	}
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		p, ok := described[match[1]]
+		if !ok {
+			p = path(match[1], "")
+		}
+		op.Parameters = append(op.Parameters, parameter(p))
+	}
+	for _, p := range route.Params {
+		if p.In != "path" {
+			op.Parameters = append(op.Parameters, parameter(p))
+		}
+	}
+	if route.Idempotent {
+		op.Parameters = append(op.Parameters, parameter(idempotencyKey))
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: !route.Optional,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: s.of(reflect.TypeOf(route.Request))},
+			},
+		}
+	}
+
+	success := &Response{Description: http.StatusText(route.Status)}
+	switch {
+	case route.Content != "":
+		success.Content = map[string]*MediaType{route.Content: {Schema: &Schema{Type: "string"}}}
+	case route.Response != nil:
+		schema := s.of(reflect.TypeOf(route.Response))
+		if route.Key != "" {
+			schema = &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{route.Key: schema},
+				Required:   []string{route.Key},
+			}
+		}
+		success.Content = map[string]*MediaType{"application/json": {Schema: schema}}
+	}
+	op.Responses[strconv.Itoa(route.Status)] = success
+	for _, status := range route.Also {
+		op.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     success.Content,
+		}
+	}
+
+	for _, status := range errorStatuses(route) {
+		op.Responses[strconv.Itoa(status)] = &Response{Ref: "#/components/responses/" + errorResponses[status]}
+	}
+	// any of the schemes of the route authenticates it
+	if route.Consumer {
+		op.Security = append(op.Security, map[string][]string{ConsumerScheme: {}})
+	}
+	if route.Moderator {
+		op.Security = append(op.Security, map[string][]string{ModeratorScheme: {}})
+	}
+	if route.Player {
+		op.Security = append(op.Security, map[string][]string{PlayerScheme: {}})
+	}
+	if route.Arbiter {
+		op.Security = append(op.Security, map[string][]string{ArbiterScheme: {}})
+	}
+	if route.Admin || route.Moderator || route.Arbiter {
+		op.Security = append(op.Security, map[string][]string{AdminScheme: {}})
+	}
+
+	return op
+}
+
+// errorStatuses are the ones of Errors along with the ones every route with its parameters
+// may answer, an Idempotent route those of its Idempotency-Key too. Any of them may answer 500 when a handler
+// panics
+func errorStatuses(route Route) []int {
+	hasQuery := false
+	for _, p := range route.Params {
+		hasQuery = hasQuery || p.In == "query"
+	}
+	hasPath := strings.Contains(route.Path, "{")
+
+	statuses := append([]int{}, route.Errors...)
+	if route.Idempotent {
+		if !slices.Contains(statuses, http.StatusConflict) {
+			statuses = append(statuses, http.StatusConflict)
+		}
+		statuses = append(statuses, http.StatusRequestEntityTooLarge)
+	}
+	if route.Request != nil || hasQuery || hasPath || route.Idempotent {
+		statuses = append(statuses, http.StatusBadRequest)
+	}
+	if route.Request != nil || hasQuery || route.Idempotent {
+		statuses = append(statuses, http.StatusUnprocessableEntity)
+	}
+	if hasPath {
+		statuses = append(statuses, http.StatusNotFound)
+	}
+	if route.Admin {
+		statuses = append(statuses, http.StatusUnauthorized)
+	}
+	if route.Consumer || route.Moderator || route.Player || route.Arbiter {
+		statuses = append(statuses, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	return append(statuses, http.StatusInternalServerError)
+}
+
+func parameter(p Param) Parameter {
+	return Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.In == "path",
+		Schema:      &Schema{Type: p.Type, Enum: p.Enum},
+	}
+}
@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "write the generated document to openapi.json")
+
+// TestSpecIsUpToDate fails when a route or a dto changed without the committed document being
+// regenerated, run go generate ./internal/adapters/http/openapi to update it
+func TestSpecIsUpToDate(t *testing.T) {
+	generated, err := Build().JSON()
+	require.NoError(t, err)
+
+	if *update {
+		require.NoError(t, os.WriteFile("openapi.json", generated, 0o644))
+		return
+	}
+
+	if !bytes.Equal(generated, spec) {
+		t.Fatal("openapi.json is out of date with the routes and the dtos, run go generate ./internal/adapters/http/openapi")
+	}
+}
+
+func TestSpecIsValid(t *testing.T) {
+	doc := Build()
+	raw, err := doc.JSON()
+	require.NoError(t, err)
+
+	var tree map[string]any
+	require.NoError(t, json.Unmarshal(raw, &tree))
+
+	// every reference points to a component
+	var refs []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				if ref, ok := value.(string); ok && key == "$ref" {
+					refs = append(refs, ref)
+				}
+				walk(value)
+			}
+		case []any:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+	walk(tree)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		var node any = tree
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			m, ok := node.(map[string]any)
+			require.True(t, ok, "%s does not resolve", ref)
+			node, ok = m[key]
+			require.True(t, ok, "%s does not resolve", ref)
+		}
+	}
+
+	ids := make(map[string]string)
+	for _, route := range Routes() {
+		key := route.Method + " " + route.Path
+		if other, ok := ids[route.ID]; ok {
+			t.Errorf("operationId %s of %s is also the one of %s", route.ID, key, other)
+		}
+		ids[route.ID] = key
+	}
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Contains(t, doc.Components.Schemas, "ErrorBody")
+	// the names found in several dto packages are prefixed with theirs
+	assert.Contains(t, doc.Components.Schemas, "FideImportSummaryResponse")
+	assert.Contains(t, doc.Components.Schemas, "AnnouncementImportSummaryResponse")
+	assert.NotContains(t, doc.Components.Schemas, "ImportSummaryResponse")
+}
+
+func TestSchemaOf(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type Inner inner
+	type outer struct {
+		ID       string            `json:"id"`
+		Note     string            `json:"note,omitempty"`
+		Count    int64             `json:"count"`
+		Inner    Inner             `json:"inner"`
+		Maybe    *Inner            `json:"maybe"`
+		Optional *string           `json:"optional,omitempty"`
+		Tags     []string          `json:"tags"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Skipped  string            `json:"-"`
+		hidden   string
+	}
+
+	s := newSchemas(nil)
+	s.names[reflect.TypeFor[Inner]()] = "Inner"
+	schema := s.object(reflect.TypeFor[outer]())
+
+	assert.Equal(t, []string{"id", "count", "inner", "maybe", "tags"}, schema.Required)
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, schema.Properties["count"])
+	assert.Equal(t, &Schema{Ref: schemaRefPrefix + "Inner"}, schema.Properties["inner"])
+	assert.Equal(t, &Schema{AnyOf: []*Schema{{Ref: schemaRefPrefix + "Inner"}, {Type: "null"}}}, schema.Properties["maybe"])
+	assert.Equal(t, &Schema{Type: "string"}, schema.Properties["optional"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, schema.Properties["tags"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, schema.Properties["labels"])
+	assert.NotContains(t, schema.Properties, "Skipped")
+	assert.NotContains(t, schema.Properties, "hidden")
+	assert.Contains(t, s.components, "Inner")
+}
+
+func TestHandlers(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SpecHandler(rec, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+
+	rec = httptest.NewRecorder()
+	DocsHandler(rec, httptest.NewRequest(http.MethodGet, "/v1/docs", nil))
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `fetch("openapi.json")`)
+}
@@ -6,19 +6,48 @@ import (
 	"maps"
 	"net/http"
 
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/i18n"
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
+// Codes of the error responses, they are also the keys of the i18n catalogs
+const (
+	CodeValidationFailed   = "validation_failed"
+	CodeBadRequest         = "bad_request"
+	CodeServerError        = "server_error"
+	CodeNotFound           = "not_found"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeConflict           = "conflict"
+	CodeInvalidID          = "invalid_id"
+)
+
 type Helper struct {
 	logger ports.Logger
+	bundle *i18n.Bundle
 }
 
 func NewResponseWriter(logger ports.Logger) *Helper {
 	return &Helper{
 		logger: logger,
+		bundle: i18n.Default(),
 	}
 }
 
+// ErrorBody is the body of the error responses. Code is missing from the ones of ErrorResponse
+// and Errors is only set when the validation failed
+type ErrorBody struct {
+	Code   string                `json:"code,omitempty"`
+	Error  string                `json:"error"`
+	Errors map[string]FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a validation error as the client sees it
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type envelope map[string]any
 
 func (h *Helper) WriteJSON(w http.ResponseWriter, status int, data any, headers http.Header) error {
@@ -45,29 +74,66 @@ func (h *Helper) ErrorResponse(w http.ResponseWriter, r *http.Request, status in
 	}
 }
 
-func (h *Helper) FailedValidationResponse(w http.ResponseWriter, r *http.Request, errs map[string]string) {
-	env := envelope{"errors": errs}
-	h.ErrorResponse(w, r, http.StatusUnprocessableEntity, env)
+// ErrorCodeResponse writes the message of the code in the locale of the request along with the code,
+// clients should rely on the code as the message changes with the language
+func (h *Helper) ErrorCodeResponse(w http.ResponseWriter, r *http.Request, status int, code string) {
+	env := ErrorBody{
+		Code:  code,
+		Error: h.bundle.Translate(i18n.FromContext(r.Context()), code, nil),
+	}
+	if err := h.WriteJSON(w, status, env, nil); err != nil {
+		h.logError(r, err)
+		w.WriteHeader(500)
+	}
+}
+
+// FailedValidationResponse writes the code and the localized message of every invalid field
+func (h *Helper) FailedValidationResponse(w http.ResponseWriter, r *http.Request, errs validation.Errors) {
+	locale := i18n.FromContext(r.Context())
+
+	fields := make(map[string]FieldError, len(errs))
+	for field, fe := range errs {
+		fields[field] = FieldError{
+			Code:    fe.Code,
+			Message: h.bundle.Translate(locale, fe.Code, fe.Params),
+		}
+	}
+
+	env := ErrorBody{
+		Code:   CodeValidationFailed,
+		Error:  h.bundle.Translate(locale, CodeValidationFailed, nil),
+		Errors: fields,
+	}
+	if err := h.WriteJSON(w, http.StatusUnprocessableEntity, env, nil); err != nil {
+		h.logError(r, err)
+		w.WriteHeader(500)
+	}
 }
 
+// BadRequestResponse writes the error as is, it is usually a json decoding error
 func (h *Helper) BadRequestResponse(w http.ResponseWriter, r *http.Request, err error) {
-	h.ErrorResponse(w, r, http.StatusBadRequest, err.Error())
+	env := ErrorBody{Code: CodeBadRequest, Error: err.Error()}
+	if err := h.WriteJSON(w, http.StatusBadRequest, env, nil); err != nil {
+		h.logError(r, err)
+		w.WriteHeader(500)
+	}
 }
 
 func (h *Helper) ServerErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	h.logError(r, err)
-	message := "the server encountered a problem and could not process your request"
-	h.ErrorResponse(w, r, http.StatusInternalServerError, message)
+	h.ErrorCodeResponse(w, r, http.StatusInternalServerError, CodeServerError)
+}
+
+func (h *Helper) NotFoundResponse(w http.ResponseWriter, r *http.Request) {
+	h.ErrorCodeResponse(w, r, http.StatusNotFound, CodeNotFound)
 }
 
 func (h *Helper) InvalidCredentialsResponse(w http.ResponseWriter, r *http.Request) {
-	message := "invalid credentials"
-	h.ErrorResponse(w, r, http.StatusUnauthorized, message)
+	h.ErrorCodeResponse(w, r, http.StatusUnauthorized, CodeInvalidCredentials)
 }
 
 func (h *Helper) ConflictResponse(w http.ResponseWriter, r *http.Request) {
-	message := "a record already exists with this email address"
-	h.ErrorResponse(w, r, http.StatusConflict, message)
+	h.ErrorCodeResponse(w, r, http.StatusConflict, CodeConflict)
 }
 
 func (h *Helper) logError(r *http.Request, err error) {
@@ -0,0 +1,66 @@
+// Package importdir opens the files of the imports. They are confined to the import directory
+// of the configuration, a path given to the api cannot read anything else of the server
+package importdir
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// Open opens the regular file name of dir. name is relative to dir and must still be in it once
+// its symlinks are followed, otherwise, and when there is no dir, it is domain.ErrImportFileNotFound
+func Open(dir, name string) (*os.File, error) {
+	path, err := resolve(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("%w: %s", domain.ErrImportFileNotFound, name)
+	}
+
+	return f, nil
+}
+
+// resolve follows the symlinks of name and checks where they lead, the import directory is
+// written by the operator so it is not expected to change between resolving and opening
+func resolve(dir, name string) (string, error) {
+	notFound := fmt.Errorf("%w: %s", domain.ErrImportFileNotFound, name)
+	if dir == "" || !filepath.IsLocal(name) {
+		return "", notFound
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving the import directory: %w", err)
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", name, err)
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", notFound
+	}
+
+	return path, nil
+}
@@ -0,0 +1,60 @@
+package importdir
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lists"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lists", "players.txt"), []byte("list"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "lists", "players.txt"), filepath.Join(dir, "latest.txt")))
+	require.NoError(t, os.Symlink(secret, filepath.Join(dir, "escape.txt")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "out")))
+
+	t.Run("a file of the directory", func(t *testing.T) {
+		for _, name := range []string{"lists/players.txt", "latest.txt", "lists/../latest.txt"} {
+			f, err := Open(dir, name)
+			require.NoError(t, err, name)
+			content, err := io.ReadAll(f)
+			f.Close()
+			require.NoError(t, err)
+			assert.Equal(t, "list", string(content), name)
+		}
+	})
+
+	t.Run("anything else", func(t *testing.T) {
+		tests := []struct {
+			name string
+			dir  string
+			path string
+		}{
+			{name: "absolute", dir: dir, path: secret},
+			{name: "up", dir: dir, path: "../" + filepath.Base(outside) + "/secret.txt"},
+			{name: "symlink out", dir: dir, path: "escape.txt"},
+			{name: "through a symlinked directory", dir: dir, path: "out/secret.txt"},
+			{name: "missing", dir: dir, path: "missing.txt"},
+			{name: "a directory", dir: dir, path: "lists"},
+			{name: "empty", dir: dir, path: ""},
+			{name: "no import directory", dir: "", path: "lists/players.txt"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := Open(tt.dir, tt.path)
+				assert.ErrorIs(t, err, domain.ErrImportFileNotFound)
+			})
+		}
+	})
+}
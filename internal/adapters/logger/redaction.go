@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+const redacted = "[REDACTED]"
+
+// Redaction hides the values of the fields whose key contains one of its words, case
+// insensitive. The hidden ones are replaced whole, the masked ones keep enough to tell two
+// apart in the logs: the first letter and the domain of an email, the last digits of a phone
+type Redaction struct {
+	Hidden []string
+	Masked []string
+}
+
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Hidden: []string{"password", "secret", "token", "authorization", "api_key", "apikey", "cookie", "signature"},
+		Masked: []string{"email", "phone", "username"},
+	}
+}
+
+// Apply returns the field with its value redacted when its key is sensitive
+func (rd Redaction) Apply(field ports.LogField) ports.LogField {
+	key := strings.ToLower(field.Key)
+	for _, word := range rd.Hidden {
+		if strings.Contains(key, word) {
+			return ports.String(field.Key, redacted)
+		}
+	}
+	for _, word := range rd.Masked {
+		if strings.Contains(key, word) {
+			value, ok := field.Value.(string)
+			if !ok {
+				return ports.String(field.Key, redacted)
+			}
+			return ports.String(field.Key, mask(word, value))
+		}
+	}
+	return field
+}
+
+// mask keeps a little of the value
+func mask(word, value string) string {
+	switch {
+	case value == "":
+		return ""
+	case word == "phone":
+		digits := make([]rune, 0, len(value))
+		for _, r := range value {
+			if r >= '0' && r <= '9' {
+				digits = append(digits, r)
+			}
+		}
+		if len(digits) <= 2 {
+			return "***"
+		}
+		return "***" + string(digits[len(digits)-2:])
+	case strings.Contains(value, "@"):
+		local, domain, _ := strings.Cut(value, "@")
+		return firstRune(local) + "***@" + domain
+	default:
+		return firstRune(value) + "***"
+	}
+}
+
+func firstRune(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package logger
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -10,21 +11,66 @@ import (
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
-// ZapLogger is a logger that uses the Zap library
+// Config of the logger
+type Config struct {
+	// Env is prod for json lines, anything else logs to the console in colour
+	Env string
+	// Level is debug, info, warn or error. Empty is info in prod and debug otherwise
+	Level string
+	// SampleInitial and SampleThereafter sample the lines of the same level and message: every
+	// second the first SampleInitial are logged and then one of every SampleThereafter. Zero
+	// is 100 and 100 in prod, negative logs every line
+	SampleInitial    int
+	SampleThereafter int
+	// Redaction hides the values of the sensitive keys, nil is DefaultRedaction
+	Redaction *Redaction
+}
+
+// ZapLogger is a logger that uses the Zap library. The ids of the request stored in the
+// context, see ports.WithLogFields, and its trace are added to every line
 type ZapLogger struct {
-	logger *zap.Logger
+	logger    *zap.Logger
+	level     zap.AtomicLevel
+	redaction Redaction
 }
 
 // NewZapLogger creates a new ZapLogger
 func NewZapLogger(env string) ports.Logger {
+	return New(Config{Env: env})
+}
+
+// New creates a ZapLogger from the config, it panics when the level is not one it knows
+func New(c Config) *ZapLogger {
 	var config zap.Config
 
-	if env == "prod" {
+	if c.Env == "prod" {
 		config = zap.NewProductionConfig()
 		// Add log rotation and other production configs here
+		initial, thereafter := c.SampleInitial, c.SampleThereafter
+		if initial == 0 {
+			initial = 100
+		}
+		if thereafter == 0 {
+			thereafter = 100
+		}
+		config.Sampling = nil
+		if initial > 0 && thereafter > 0 {
+			config.Sampling = &zap.SamplingConfig{Initial: initial, Thereafter: thereafter}
+		}
 	} else {
 		config = zap.NewDevelopmentConfig()
 		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		if c.SampleInitial > 0 && c.SampleThereafter > 0 {
+			config.Sampling = &zap.SamplingConfig{Initial: c.SampleInitial, Thereafter: c.SampleThereafter}
+		}
+	}
+
+	if c.Level != "" {
+		level, err := zapcore.ParseLevel(c.Level)
+		if err != nil {
+			panic("Failed to initialize logger: " + err.Error())
+		}
+		config.Level.SetLevel(level)
 	}
 
 	logger, err := config.Build()
@@ -32,15 +78,72 @@ func NewZapLogger(env string) ports.Logger {
 		panic("Failed to initialize logger: " + err.Error())
 	}
 
+	redaction := DefaultRedaction()
+	if c.Redaction != nil {
+		redaction = *c.Redaction
+	}
+
 	return &ZapLogger{
-		logger: logger,
+		logger:    logger,
+		level:     config.Level,
+		redaction: redaction,
+	}
+}
+
+// Level is the lowest level logged
+func (z *ZapLogger) Level() string {
+	return z.level.Level().String()
+}
+
+// SetLevel changes the lowest level logged, for every copy of the logger
+func (z *ZapLogger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	if parsed < zapcore.DebugLevel || parsed > zapcore.ErrorLevel {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	z.level.SetLevel(parsed)
+	return nil
+}
+
+// fields are the fields of the context followed by the ones of the call, which take over
+// the ones of the context with the same key
+func (z *ZapLogger) fields(ctx context.Context, fields []ports.LogField) []zap.Field {
+	var contextFields []ports.LogField
+	if ctx != nil {
+		contextFields = ports.LogFieldsFromContext(ctx)
+		if span := ports.SpanFromContext(ctx); span.TraceID() != "" {
+			contextFields = append(contextFields, ports.String(ports.LogKeyTraceID, span.TraceID()), ports.String(ports.LogKeySpanID, span.SpanID()))
+		}
+	}
+
+	all := make([]ports.LogField, 0, len(contextFields)+len(fields))
+	for _, field := range contextFields {
+		if !hasKey(fields, field.Key) {
+			all = append(all, field)
+		}
+	}
+	all = append(all, fields...)
+
+	return z.convertFields(all)
+}
+
+func hasKey(fields []ports.LogField, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
 	}
+	return false
 }
 
-// convertFields converts domain LogFields to zap.Fields
+// convertFields converts domain LogFields to zap.Fields, redacting the sensitive ones
 func (z *ZapLogger) convertFields(fields []ports.LogField) []zap.Field {
 	zapFields := make([]zap.Field, len(fields))
 	for i, field := range fields {
+		field = z.redaction.Apply(field)
 		switch v := field.Value.(type) {
 		case error:
 			zapFields[i] = zap.Error(v)
@@ -63,25 +166,25 @@ func (z *ZapLogger) convertFields(fields []ports.LogField) []zap.Field {
 
 // Debug logs a message at DebugLevel
 func (z *ZapLogger) Debug(ctx context.Context, msg string, fields ...ports.LogField) {
-	z.logger.Debug(msg, z.convertFields(fields)...)
+	z.logger.Debug(msg, z.fields(ctx, fields)...)
 }
 
 // Info logs a message at InfoLevel
 func (z *ZapLogger) Info(ctx context.Context, msg string, fields ...ports.LogField) {
-	z.logger.Info(msg, z.convertFields(fields)...)
+	z.logger.Info(msg, z.fields(ctx, fields)...)
 }
 
 // warn logs a message at WarnLevel
 func (z *ZapLogger) Warn(ctx context.Context, msg string, fields ...ports.LogField) {
-	z.logger.Warn(msg, z.convertFields(fields)...)
+	z.logger.Warn(msg, z.fields(ctx, fields)...)
 }
 
 // Error logs a message at ErrorLevel
 func (z *ZapLogger) Error(ctx context.Context, msg string, fields ...ports.LogField) {
-	z.logger.Error(msg, z.convertFields(fields)...)
+	z.logger.Error(msg, z.fields(ctx, fields)...)
 }
 
 // Fatal logs a message at FatalLevel
 func (z *ZapLogger) Fatal(ctx context.Context, msg string, fields ...ports.LogField) {
-	z.logger.Fatal(msg, z.convertFields(fields)...)
+	z.logger.Fatal(msg, z.fields(ctx, fields)...)
 }
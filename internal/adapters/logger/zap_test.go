@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+func newObserved() (*ZapLogger, *observer.ObservedLogs) {
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	core, logs := observer.New(level)
+	return &ZapLogger{logger: zap.New(core), level: level, redaction: DefaultRedaction()}, logs
+}
+
+func TestZapLogger_ContextFields(t *testing.T) {
+	log, logs := newObserved()
+
+	ctx := ports.WithLogFields(context.Background(),
+		ports.String(ports.LogKeyRequestID, "req-1"),
+		ports.String(ports.LogKeyTournamentID, "t-1"),
+	)
+	// a later field of the context replaces the earlier one with the same key
+	ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyTournamentID, "t-2"))
+
+	log.Info(ctx, "tournament found", ports.String(ports.LogKeyRequestID, "req-call"))
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-call", fields[ports.LogKeyRequestID])
+	assert.Equal(t, "t-2", fields[ports.LogKeyTournamentID])
+	assert.NotContains(t, fields, ports.LogKeyTraceID)
+}
+
+func TestZapLogger_Redaction(t *testing.T) {
+	log, logs := newObserved()
+
+	log.Info(context.Background(), "consumer registered",
+		ports.String("password", "hunter2"),
+		ports.String("Authorization", "Bearer abc"),
+		ports.String("refresh_token", "xyz"),
+		ports.String("email", "maria@example.com"),
+		ports.String("phone", "+34 600 123 456"),
+		ports.String("username", "maria"),
+		ports.Int("phone_digits", 9),
+		ports.Error("error", errors.New("boom")),
+	)
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "[REDACTED]", fields["password"])
+	assert.Equal(t, "[REDACTED]", fields["Authorization"])
+	assert.Equal(t, "[REDACTED]", fields["refresh_token"])
+	assert.Equal(t, "m***@example.com", fields["email"])
+	assert.Equal(t, "***56", fields["phone"])
+	assert.Equal(t, "m***", fields["username"])
+	assert.Equal(t, "[REDACTED]", fields["phone_digits"])
+	assert.Equal(t, "boom", fields["error"])
+}
+
+func TestZapLogger_SetLevel(t *testing.T) {
+	log, logs := newObserved()
+	ctx := context.Background()
+
+	log.Debug(ctx, "hidden")
+	require.NoError(t, log.SetLevel("debug"))
+	assert.Equal(t, "debug", log.Level())
+	log.Debug(ctx, "shown")
+
+	require.NoError(t, log.SetLevel("error"))
+	log.Warn(ctx, "hidden")
+
+	assert.Error(t, log.SetLevel("fatal"))
+	assert.Error(t, log.SetLevel("loud"))
+	assert.Equal(t, "error", log.Level())
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "shown", logs.All()[0].Message)
+}
@@ -0,0 +1,120 @@
+// Package metrics exports the metrics of maple to prometheus with its client
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// DefaultBuckets are the latency buckets in seconds, the ones of the prometheus client
+var DefaultBuckets = prometheus.DefBuckets
+
+// Registry registers the instruments with a prometheus registry of its own, along with the
+// collectors of the go runtime and the process. Registering a name again returns the
+// instrument already registered, with another kind, help or labels it panics as the two would
+// not fit in one family
+type Registry struct {
+	registry *prometheus.Registry
+}
+
+var _ ports.Metrics = (*Registry)(nil)
+
+func NewRegistry() *Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return &Registry{registry: registry}
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) ports.Counter {
+	return counter{register(r.registry, prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels))}
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) ports.Gauge {
+	return gauge{register(r.registry, prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels))}
+}
+
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	register(r.registry, prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn))
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) ports.Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return histogram{register(r.registry, prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels))}
+}
+
+// register returns the collector already registered with the same description in place of c
+func register[C prometheus.Collector](registry *prometheus.Registry, c C) C {
+	err := registry.Register(c)
+	if err == nil {
+		return c
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(C); ok {
+			return existing
+		}
+	}
+	panic(fmt.Sprintf("metrics: %v", err))
+}
+
+// counter, gauge and histogram take the label values in the order of the label names, another
+// number of them panics
+type counter struct{ vec *prometheus.CounterVec }
+
+func (c counter) Inc(labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Inc()
+}
+
+func (c counter) Add(delta float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+type gauge struct{ vec *prometheus.GaugeVec }
+
+func (g gauge) Set(value float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+func (g gauge) Add(delta float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+type histogram struct{ vec *prometheus.HistogramVec }
+
+func (h histogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+// Handler serves the metrics to the scrapers
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// Write writes the metrics in the text format the scrapers read
+func (r *Registry) Write(w io.Writer) error {
+	families, err := r.registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
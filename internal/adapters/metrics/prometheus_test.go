@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+
+	first := r.Counter("maple_tournaments_created_total", "Tournaments created.")
+	again := r.Counter("maple_tournaments_created_total", "Tournaments created.")
+	first.Inc()
+	again.Inc()
+
+	var out strings.Builder
+	require.NoError(t, r.Write(&out))
+	assert.Contains(t, out.String(), "maple_tournaments_created_total 2\n")
+
+	assert.Panics(t, func() { r.Gauge("maple_tournaments_created_total", "Tournaments created.") })
+	assert.Panics(t, func() { r.Counter("maple_tournaments_created_total", "Tournaments created.", "city") })
+	assert.Panics(t, func() { first.Inc("extra") })
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("maple_registrations_total", "Players registered in tournaments.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "maple_registrations_total 1\n")
+	assert.Contains(t, rec.Body.String(), "# TYPE go_goroutines gauge\n")
+}
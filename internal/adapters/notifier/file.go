@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// FileNotifier writes every email to a .eml file of a directory instead of sending it,
+// they can be opened with any mail client
+type FileNotifier struct {
+	dir  string
+	from string
+	now  func() time.Time
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFileNotifier(dir, from string) (ports.Notifier, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating the mail directory: %w", err)
+	}
+
+	return &FileNotifier{
+		dir:  dir,
+		from: from,
+		now:  time.Now,
+	}, nil
+}
+
+func (fn *FileNotifier) Send(ctx context.Context, email domain.Email) error {
+	now := fn.now()
+	msg, err := buildMessage(fn.from, email, now)
+	if err != nil {
+		return err
+	}
+
+	fn.mu.Lock()
+	fn.seq++
+	seq := fn.seq
+	fn.mu.Unlock()
+
+	// sortable by the time they were sent, the recipient makes them easy to find
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(email.To)
+	name := fmt.Sprintf("%s-%04d-%s.eml", now.UTC().Format("20060102T150405"), seq, recipient)
+
+	return os.WriteFile(filepath.Join(fn.dir, name), msg, 0o644)
+}
+
+// ConsoleNotifier prints every email, it is the default during development
+type ConsoleNotifier struct {
+	w    io.Writer
+	from string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+func NewConsoleNotifier(w io.Writer, from string) ports.Notifier {
+	return &ConsoleNotifier{
+		w:    w,
+		from: from,
+		now:  time.Now,
+	}
+}
+
+func (cn *ConsoleNotifier) Send(ctx context.Context, email domain.Email) error {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+
+	headers := []string{"From: " + cn.from, "To: " + email.To, "Subject: " + email.Subject}
+	keys := make([]string, 0, len(email.Headers))
+	for key := range email.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		headers = append(headers, key+": "+email.Headers[key])
+	}
+
+	_, err := fmt.Fprintf(cn.w, "----- email %s -----\n%s\n\n%s----- end of email -----\n",
+		cn.now().Format(time.RFC3339), strings.Join(headers, "\n"), email.Text)
+	return err
+}
@@ -0,0 +1,76 @@
+// Package notifier provides the Notifier adapters: an smtp client for production and file
+// and console notifiers that keep the emails local during development
+package notifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// buildMessage writes the email as an RFC 5322 message with a quoted printable utf-8 body,
+// the subject is encoded so accents survive every mail client
+func buildMessage(from string, email domain.Email, now time.Time) ([]byte, error) {
+	if _, err := mail.ParseAddress(email.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", email.To, err)
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		// values come from templates and players, a line break would inject headers
+		value = strings.NewReplacer("\r", "", "\n", " ").Replace(value)
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", from)
+	header("To", email.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+
+	keys := make([]string, 0, len(email.Headers))
+	for key := range email.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		header(key, email.Headers[key])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(email.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique id in the domain of the sender
+func messageID(from string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	host := "maple.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, domain, ok := strings.Cut(addr.Address, "@"); ok {
+			host = domain
+		}
+	}
+	return "<" + hex.EncodeToString(b) + "@" + host + ">"
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ctfrancia/maple/internal/adapters/notifier/smtptest"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+var testEmail = domain.Email{
+	To:      "nuria@example.com",
+	Subject: "Estàs inscrit a l'Open de Sants",
+	Text:    "Hola Núria,\n\nLa teva inscripció està confirmada.\n",
+	Headers: map[string]string{"List-Unsubscribe": "<https://maple.example/unsubscribe/token>"},
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	notifier := NewSMTPNotifier(SMTPConfig{Host: srv.Host(), Port: srv.Port(), From: "Maple <no-reply@maple.example>", Timeout: time.Second})
+
+	t.Run("should relay the email", func(t *testing.T) {
+		require.NoError(t, notifier.Send(context.Background(), testEmail))
+
+		messages := srv.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "no-reply@maple.example", messages[0].From)
+		assert.Equal(t, []string{"nuria@example.com"}, messages[0].To)
+
+		msg, err := messages[0].Parse()
+		require.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, testEmail.Subject, subject)
+		assert.Equal(t, testEmail.Headers["List-Unsubscribe"], msg.Header.Get("List-Unsubscribe"))
+		assert.Contains(t, msg.Header.Get("Message-Id"), "@maple.example>")
+
+		body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		require.NoError(t, err)
+		assert.Equal(t, testEmail.Text, string(body)) // the server reads the lines without CR
+	})
+
+	t.Run("should report a temporary failure", func(t *testing.T) {
+		srv.FailNext(1)
+		assert.Error(t, notifier.Send(context.Background(), testEmail))
+		assert.NoError(t, notifier.Send(context.Background(), testEmail))
+	})
+
+	t.Run("should reject an invalid recipient", func(t *testing.T) {
+		email := testEmail
+		email.To = "not an address"
+		assert.Error(t, notifier.Send(context.Background(), email))
+	})
+
+	t.Run("should not let a header value add headers", func(t *testing.T) {
+		email := testEmail
+		email.Headers = map[string]string{"X-Note": "hello\r\nBcc: someone@example.com"}
+		require.NoError(t, notifier.Send(context.Background(), email))
+
+		messages := srv.Messages()
+		msg, err := messages[len(messages)-1].Parse()
+		require.NoError(t, err)
+		assert.Empty(t, msg.Header.Get("Bcc"))
+	})
+}
+
+func TestSMTPNotifier_Ping(t *testing.T) {
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+
+	notifier := NewSMTPNotifier(SMTPConfig{Host: srv.Host(), Port: srv.Port(), From: "Maple <no-reply@maple.example>"}).(*SMTPNotifier)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, notifier.Ping(ctx))
+	assert.Empty(t, srv.Messages())
+
+	srv.Close()
+	assert.Error(t, notifier.Ping(ctx))
+}
+
+func TestFileNotifier_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	notifier, err := NewFileNotifier(dir, "no-reply@maple.example")
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Send(context.Background(), testEmail))
+	require.NoError(t, notifier.Send(context.Background(), testEmail))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*nuria_at_example.com.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: nuria@example.com\r\n")
+}
+
+func TestConsoleNotifier_Send(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := NewConsoleNotifier(&buf, "no-reply@maple.example")
+
+	require.NoError(t, notifier.Send(context.Background(), testEmail))
+	assert.Contains(t, buf.String(), "Subject: "+testEmail.Subject)
+	assert.Contains(t, buf.String(), testEmail.Text)
+}
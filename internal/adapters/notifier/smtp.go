@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// SMTPConfig - where the emails are relayed through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // no authentication when empty
+	Password string
+	From     string // e.g. "Maple <no-reply@maple.example>"
+	Timeout  time.Duration
+}
+
+// SMTPNotifier sends the emails through an smtp server, upgrading the connection with
+// STARTTLS when the server offers it
+type SMTPNotifier struct {
+	config SMTPConfig
+	now    func() time.Time
+}
+
+func NewSMTPNotifier(config SMTPConfig) ports.Notifier {
+	return &SMTPNotifier{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Ping greets the server and quits, it is not authenticated
+func (sn *SMTPNotifier) Ping(ctx context.Context) error {
+	addr := net.JoinHostPort(sn.config.Host, strconv.Itoa(sn.config.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sn.config.Host)
+	if err != nil {
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Noop(); err != nil {
+		return fmt.Errorf("smtp noop: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (sn *SMTPNotifier) Send(ctx context.Context, email domain.Email) error {
+	from, err := mail.ParseAddress(sn.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", sn.config.From, err)
+	}
+
+	msg, err := buildMessage(sn.config.From, email, sn.now())
+	if err != nil {
+		return err
+	}
+
+	if sn.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sn.config.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(sn.config.Host, strconv.Itoa(sn.config.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	// net/smtp has no context support, the deadline of ctx bounds the whole conversation
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sn.config.Host)
+	if err != nil {
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sn.config.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if sn.config.Username != "" {
+		auth := smtp.PlainAuth("", sn.config.Username, sn.config.Password, sn.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(email.To); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	return client.Quit()
+}
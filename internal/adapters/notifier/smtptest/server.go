@@ -0,0 +1,172 @@
+// Package smtptest provides a local smtp server for tests, it accepts every message and
+// keeps it so the test can read what was sent, like httptest does for http
+package smtptest
+
+import (
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message is an email the server received
+type Message struct {
+	From string
+	To   []string
+	Data []byte // the raw message as sent after DATA
+}
+
+// Parse reads the headers and the body of the message
+func (m Message) Parse() (*mail.Message, error) {
+	return mail.ReadMessage(strings.NewReader(string(m.Data)))
+}
+
+// Server is an smtp server listening on the loopback interface
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	failures int // messages rejected with a temporary error before accepting again
+}
+
+// NewServer starts a server, Close it when the test is done
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Host is the address the server listens on
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.listener.Addr().String())
+	return host
+}
+
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+// Messages returns the messages received so far
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// FailNext has the next n messages rejected with a temporary error
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Close stops the server and waits for the open connections to finish
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+// handle speaks the part of RFC 5321 net/smtp uses, without STARTTLS nor AUTH
+func (s *Server) handle(conn *textproto.Conn) {
+	reply := func(code int, msg string) bool {
+		return conn.PrintfLine("%d %s", code, msg) == nil
+	}
+
+	if !reply(220, "smtptest ready") {
+		return
+	}
+
+	var msg Message
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			msg = Message{}
+			reply(250, "smtptest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			reply(250, "ok")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			reply(250, "ok")
+		case "DATA":
+			if !reply(354, "end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = data
+
+			s.mu.Lock()
+			failed := s.failures > 0
+			if failed {
+				s.failures--
+			} else {
+				s.messages = append(s.messages, msg)
+			}
+			s.mu.Unlock()
+
+			if failed {
+				reply(451, "try again later")
+			} else {
+				reply(250, "queued")
+			}
+			msg = Message{}
+		case "RSET":
+			msg = Message{}
+			reply(250, "ok")
+		case "NOOP":
+			reply(250, "ok")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+// address reads the address of "FROM:<a@b.c> SIZE=1" and "TO:<a@b.c>"
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
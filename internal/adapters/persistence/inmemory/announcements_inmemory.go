@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryAnnouncementRepository struct {
+	announcements map[uuid.UUID]domain.Announcement
+	fingerprints  map[string]uuid.UUID
+	seq           int
+}
+
+func NewInMemoryAnnouncementRepository() ports.AnnouncementRepository {
+	return &InMemoryAnnouncementRepository{
+		announcements: make(map[uuid.UUID]domain.Announcement),
+		fingerprints:  make(map[string]uuid.UUID),
+	}
+}
+
+func NewAnnouncementRepositoryProvider(repo ports.AnnouncementRepository) ports.AnnouncementRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryAnnouncementRepository) CreateAnnouncement(announcement domain.Announcement) (domain.Announcement, error) {
+	ir.seq++
+	announcement.ID = ir.seq
+	announcement.PublicID = uuid.New()
+	announcement.CreatedAt = time.Now()
+	announcement.UpdatedAt = announcement.CreatedAt
+	if announcement.Status == "" {
+		announcement.Status = domain.AnnouncementPending
+	}
+
+	ir.announcements[announcement.PublicID] = announcement
+	if announcement.Fingerprint != "" {
+		ir.fingerprints[announcement.Fingerprint] = announcement.PublicID
+	}
+
+	return announcement, nil
+}
+
+func (ir *InMemoryAnnouncementRepository) UpdateAnnouncement(announcement domain.Announcement) (domain.Announcement, error) {
+	if _, ok := ir.announcements[announcement.PublicID]; !ok {
+		return domain.Announcement{}, domain.ErrAnnouncementNotFound
+	}
+
+	announcement.UpdatedAt = time.Now()
+	ir.announcements[announcement.PublicID] = announcement
+
+	return announcement, nil
+}
+
+func (ir *InMemoryAnnouncementRepository) FindAnnouncement(id uuid.UUID) (domain.Announcement, error) {
+	found, ok := ir.announcements[id]
+	if !ok {
+		return domain.Announcement{}, domain.ErrAnnouncementNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryAnnouncementRepository) HasFingerprint(fingerprint string) (bool, error) {
+	_, ok := ir.fingerprints[fingerprint]
+	return ok, nil
+}
+
+func (ir *InMemoryAnnouncementRepository) ListAnnouncements(filter domain.AnnouncementFilter) ([]domain.Announcement, error) {
+	announcements := make([]domain.Announcement, 0)
+	for _, announcement := range ir.announcements {
+		if filter.Matches(announcement) {
+			announcements = append(announcements, announcement)
+		}
+	}
+
+	sort.Slice(announcements, func(i, j int) bool {
+		if !announcements[i].PostedAt.Equal(announcements[j].PostedAt) {
+			return announcements[i].PostedAt.Before(announcements[j].PostedAt)
+		}
+		return announcements[i].ID < announcements[j].ID
+	})
+
+	return announcements, nil
+}
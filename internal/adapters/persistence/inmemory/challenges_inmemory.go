@@ -0,0 +1,103 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryChallengeRepository struct {
+	eventBuffer
+	challenges map[uuid.UUID]domain.Challenge
+	seq        int
+}
+
+func NewInMemoryChallengeRepository() ports.ChallengeRepository {
+	return &InMemoryChallengeRepository{
+		challenges: make(map[uuid.UUID]domain.Challenge),
+	}
+}
+
+// NewChallengeRepositoryProvider - the events recorded in a WriteTx are appended to the outbox,
+// a nil outbox discards them
+func NewChallengeRepositoryProvider(repo ports.ChallengeRepository, outbox ports.OutboxRepositoryProvider) ports.ChallengeRepositoryProvider {
+	provider := newTxProvider(repo)
+	provider.outbox = outbox
+	return provider
+}
+
+func (ir *InMemoryChallengeRepository) CreateChallenge(challenge domain.Challenge) (domain.Challenge, error) {
+	ir.seq++
+	challenge.ID = ir.seq
+	challenge.PublicID = uuid.New()
+	challenge.CreatedAt = time.Now()
+	challenge.UpdatedAt = challenge.CreatedAt
+	if challenge.Status == "" {
+		challenge.Status = domain.ChallengeStatusOpen
+	}
+	if challenge.Moderation == "" {
+		challenge.Moderation = domain.ModerationApproved
+	}
+
+	ir.challenges[challenge.PublicID] = challenge
+
+	return challenge, nil
+}
+
+func (ir *InMemoryChallengeRepository) UpdateChallenge(challenge domain.Challenge) (domain.Challenge, error) {
+	stored, ok := ir.challenges[challenge.PublicID]
+	if !ok {
+		return domain.Challenge{}, domain.ErrChallengeNotFound
+	}
+	if stored.Status != domain.ChallengeStatusOpen {
+		return domain.Challenge{}, domain.ErrChallengeNotOpen
+	}
+
+	challenge.UpdatedAt = time.Now()
+	ir.challenges[challenge.PublicID] = challenge
+
+	return challenge, nil
+}
+
+func (ir *InMemoryChallengeRepository) ModerateChallenge(id uuid.UUID, status domain.ModerationStatus) (domain.Challenge, error) {
+	challenge, ok := ir.challenges[id]
+	if !ok {
+		return domain.Challenge{}, domain.ErrChallengeNotFound
+	}
+
+	challenge.Moderation = status
+	challenge.UpdatedAt = time.Now()
+	ir.challenges[id] = challenge
+
+	return challenge, nil
+}
+
+func (ir *InMemoryChallengeRepository) FindChallenge(id uuid.UUID) (domain.Challenge, error) {
+	found, ok := ir.challenges[id]
+	if !ok {
+		return domain.Challenge{}, domain.ErrChallengeNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryChallengeRepository) ListChallenges(filter domain.ChallengeFilter) ([]domain.Challenge, error) {
+	challenges := make([]domain.Challenge, 0)
+	for _, challenge := range ir.challenges {
+		if filter.Matches(challenge) {
+			challenges = append(challenges, challenge)
+		}
+	}
+
+	sort.Slice(challenges, func(i, j int) bool {
+		if !challenges[i].StartsAt.Equal(challenges[j].StartsAt) {
+			return challenges[i].StartsAt.Before(challenges[j].StartsAt)
+		}
+		return challenges[i].ID < challenges[j].ID
+	})
+
+	return challenges, nil
+}
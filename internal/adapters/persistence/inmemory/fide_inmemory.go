@@ -0,0 +1,108 @@
+package inmemory
+
+import (
+	"sort"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+type fideKey struct {
+	fideID int
+	period string
+}
+
+type InMemoryFideRepository struct {
+	records map[fideKey]domain.FideRecord
+	// byName indexes the records of every period by domain.FideNameKey
+	byName  map[string]map[fideKey]struct{}
+	periods map[string]struct{}
+}
+
+func NewInMemoryFideRepository() ports.FideRepository {
+	return &InMemoryFideRepository{
+		records: make(map[fideKey]domain.FideRecord),
+		byName:  make(map[string]map[fideKey]struct{}),
+		periods: make(map[string]struct{}),
+	}
+}
+
+func (ir *InMemoryFideRepository) UpsertRecord(record domain.FideRecord) (bool, error) {
+	key := fideKey{fideID: record.FideID, period: record.Period}
+
+	old, exists := ir.records[key]
+	if exists {
+		delete(ir.byName[domain.FideNameKey(old.Name)], key)
+	}
+
+	ir.records[key] = record
+	ir.periods[record.Period] = struct{}{}
+
+	nameKey := domain.FideNameKey(record.Name)
+	if ir.byName[nameKey] == nil {
+		ir.byName[nameKey] = make(map[fideKey]struct{})
+	}
+	ir.byName[nameKey][key] = struct{}{}
+
+	return !exists, nil
+}
+
+func (ir *InMemoryFideRepository) FindRecord(fideID int, period string) (domain.FideRecord, error) {
+	if period == "" {
+		// the player may not be on the latest list, fall back to the newest one they are on
+		for _, p := range ir.sortedPeriods() {
+			if found, ok := ir.records[fideKey{fideID: fideID, period: p}]; ok {
+				return found, nil
+			}
+		}
+		return domain.FideRecord{}, domain.ErrFideRecordNotFound
+	}
+
+	found, ok := ir.records[fideKey{fideID: fideID, period: period}]
+	if !ok {
+		return domain.FideRecord{}, domain.ErrFideRecordNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryFideRepository) ListRecordsByNameKey(key string, period string) ([]domain.FideRecord, error) {
+	records := make([]domain.FideRecord, 0)
+	for k := range ir.byName[key] {
+		if k.period == period {
+			records = append(records, ir.records[k])
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].FideID < records[j].FideID
+	})
+
+	return records, nil
+}
+
+func (ir *InMemoryFideRepository) LatestPeriod() (string, error) {
+	periods := ir.sortedPeriods()
+	if len(periods) == 0 {
+		return "", nil
+	}
+
+	return periods[0], nil
+}
+
+func (ir *InMemoryFideRepository) HasPeriod(period string) (bool, error) {
+	_, ok := ir.periods[period]
+	return ok, nil
+}
+
+// sortedPeriods returns the imported periods newest first, YYYY-MM sorts as text
+func (ir *InMemoryFideRepository) sortedPeriods() []string {
+	periods := make([]string, 0, len(ir.periods))
+	for p := range ir.periods {
+		periods = append(periods, p)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(periods)))
+
+	return periods
+}
@@ -0,0 +1,15 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// Probe checks the read lock of the provider can be taken, a transaction holding the write
+// lock for longer than the timeout of the probe marks the repository down
+func Probe[R any](provider interface{ ReadTx(func(R) error) error }) ports.HealthProbe {
+	return func(ctx context.Context) error {
+		return provider.ReadTx(func(R) error { return nil })
+	}
+}
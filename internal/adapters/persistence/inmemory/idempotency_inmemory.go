@@ -0,0 +1,87 @@
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// idempotencySweepInterval is how often the expired keys are removed, they are ignored until then
+const idempotencySweepInterval = time.Minute
+
+// InMemoryIdempotencyStore keeps the keys of a single instance, the retries that reach another
+// instance run again
+type InMemoryIdempotencyStore struct {
+	records   map[string]*domain.IdempotencyRecord
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+func NewInMemoryIdempotencyStore() ports.IdempotencyStore {
+	return &InMemoryIdempotencyStore{
+		records:   make(map[string]*domain.IdempotencyRecord),
+		lastSweep: time.Now(),
+	}
+}
+
+func (is *InMemoryIdempotencyStore) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(is.lastSweep) >= idempotencySweepInterval {
+		maps.DeleteFunc(is.records, func(_ string, record *domain.IdempotencyRecord) bool {
+			return !now.Before(record.ExpiresAt)
+		})
+		is.lastSweep = now
+	}
+
+	record, ok := is.records[key]
+	if !ok || !now.Before(record.ExpiresAt) {
+		is.records[key] = &domain.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		return nil, nil
+	}
+
+	switch {
+	case record.Fingerprint != fingerprint:
+		return nil, domain.ErrIdempotencyKeyReused
+	case !record.Completed():
+		return nil, domain.ErrIdempotencyKeyInFlight
+	}
+	replay := *record
+	return &replay, nil
+}
+
+func (is *InMemoryIdempotencyStore) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	record, ok := is.records[key]
+	if !ok {
+		// expired and swept while the request ran, there is nothing to replay
+		return nil
+	}
+	response.Header = maps.Clone(response.Header)
+	response.Body = append([]byte(nil), response.Body...)
+	record.Response = &response
+	return nil
+}
+
+func (is *InMemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if record, ok := is.records[key]; ok && !record.Completed() {
+		delete(is.records, key)
+	}
+	return nil
+}
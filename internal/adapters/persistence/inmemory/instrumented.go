@@ -0,0 +1,54 @@
+package inmemory
+
+import (
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// txRunner is what every repository provider implements for its repository
+type txRunner[R any] interface {
+	WriteTx(func(R) error) error
+	ReadTx(func(R) error) error
+}
+
+// InstrumentedProvider measures how long the transactions of the provider it wraps wait for
+// the lock and how long they hold it
+type InstrumentedProvider[R any] struct {
+	provider txRunner[R]
+	name     string
+	wait     ports.Histogram
+	hold     ports.Histogram
+}
+
+// Instrument wraps the provider, name is the repository label of its metrics
+func Instrument[R any](m ports.Metrics, name string, provider txRunner[R]) *InstrumentedProvider[R] {
+	return &InstrumentedProvider[R]{
+		provider: provider,
+		name:     name,
+		wait:     m.Histogram("maple_repository_lock_wait_seconds", "Time the transactions waited for the lock of the repository.", nil, "repository", "mode"),
+		hold:     m.Histogram("maple_repository_lock_hold_seconds", "Time the transactions held the lock of the repository.", nil, "repository", "mode"),
+	}
+}
+
+func (ip *InstrumentedProvider[R]) WriteTx(do func(R) error) error {
+	return ip.provider.WriteTx(ip.measure("write", do))
+}
+
+func (ip *InstrumentedProvider[R]) ReadTx(do func(R) error) error {
+	return ip.provider.ReadTx(ip.measure("read", do))
+}
+
+// measure times do from the call, the wait is up to the lock being taken and do starting
+func (ip *InstrumentedProvider[R]) measure(mode string, do func(R) error) func(R) error {
+	called := time.Now()
+	return func(repo R) error {
+		start := time.Now()
+		ip.wait.Observe(start.Sub(called).Seconds(), ip.name, mode)
+		defer func() {
+			ip.hold.Observe(time.Since(start).Seconds(), ip.name, mode)
+		}()
+
+		return do(repo)
+	}
+}
@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryLocationRepository struct {
+	locations map[uuid.UUID]domain.Location
+	seq       int
+}
+
+func NewInMemoryLocationRepository() ports.LocationRepository {
+	return &InMemoryLocationRepository{
+		locations: make(map[uuid.UUID]domain.Location),
+	}
+}
+
+func NewLocationRepositoryProvider(repo ports.LocationRepository) ports.LocationRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryLocationRepository) CreateLocation(location domain.Location) (domain.Location, error) {
+	ir.seq++
+	location.ID = ir.seq
+	location.PublicID = uuid.New()
+	location.CreatedAt = time.Now()
+	location.UpdatedAt = location.CreatedAt
+
+	ir.locations[location.PublicID] = location
+
+	return location, nil
+}
+
+func (ir *InMemoryLocationRepository) UpdateLocation(location domain.Location) (domain.Location, error) {
+	stored, ok := ir.locations[location.PublicID]
+	if !ok {
+		return domain.Location{}, domain.ErrLocationNotFound
+	}
+
+	location.ID = stored.ID
+	location.CreatedAt = stored.CreatedAt
+	location.UpdatedAt = time.Now()
+	ir.locations[location.PublicID] = location
+
+	return location, nil
+}
+
+func (ir *InMemoryLocationRepository) FindLocation(id uuid.UUID) (domain.Location, error) {
+	found, ok := ir.locations[id]
+	if !ok {
+		return domain.Location{}, domain.ErrLocationNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryLocationRepository) DeleteLocation(id uuid.UUID) error {
+	if _, ok := ir.locations[id]; !ok {
+		return domain.ErrLocationNotFound
+	}
+
+	delete(ir.locations, id)
+
+	return nil
+}
+
+func (ir *InMemoryLocationRepository) ListLocations(filter domain.LocationFilter) ([]domain.Location, error) {
+	locations := make([]domain.Location, 0)
+	for _, location := range ir.locations {
+		if filter.Matches(location) {
+			locations = append(locations, location)
+		}
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Name != locations[j].Name {
+			return locations[i].Name < locations[j].Name
+		}
+		return locations[i].ID < locations[j].ID
+	})
+
+	return locations, nil
+}
@@ -0,0 +1,118 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryMatchRepository struct {
+	eventBuffer
+	matches map[uuid.UUID]domain.Match
+	nextID  int
+}
+
+func NewInMemoryMatchRepository() ports.MatchRepository {
+	return &InMemoryMatchRepository{
+		matches: make(map[uuid.UUID]domain.Match),
+	}
+}
+
+func (ir *InMemoryMatchRepository) CreateMatch(match domain.Match) (domain.Match, error) {
+	ir.nextID++
+	match.ID = ir.nextID
+	match.UUID = uuid.New()
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = time.Now()
+	if match.Result == "" {
+		match.Result = domain.MatchResultOngoing
+	}
+	if match.Moderation == "" {
+		match.Moderation = domain.ModerationApproved
+	}
+
+	ir.matches[match.UUID] = match
+
+	return match, nil
+}
+
+func (ir *InMemoryMatchRepository) UpdateMatch(match domain.Match) (domain.Match, error) {
+	if _, ok := ir.matches[match.UUID]; !ok {
+		return domain.Match{}, domain.ErrMatchNotFound
+	}
+
+	match.UpdatedAt = time.Now()
+	ir.matches[match.UUID] = match
+
+	return match, nil
+}
+
+func (ir *InMemoryMatchRepository) DeleteMatch(id uuid.UUID) error {
+	if _, ok := ir.matches[id]; !ok {
+		return domain.ErrMatchNotFound
+	}
+
+	delete(ir.matches, id)
+
+	return nil
+}
+
+func (ir *InMemoryMatchRepository) FindMatch(id uuid.UUID) (domain.Match, error) {
+	found, ok := ir.matches[id]
+	if !ok {
+		return domain.Match{}, domain.ErrMatchNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryMatchRepository) ListMatchesByPlayer(playerID uuid.UUID) ([]domain.Match, error) {
+	matches := make([]domain.Match, 0)
+	for _, match := range ir.matches {
+		if match.WhitePlayer == playerID || match.BlackPlayer == playerID {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches, nil
+}
+
+func (ir *InMemoryMatchRepository) ListMatches(filter domain.MatchFilter) ([]domain.Match, error) {
+	matches := make([]domain.Match, 0)
+	for _, match := range ir.matches {
+		if filter.Matches(match) {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches, nil
+}
+
+func (ir *InMemoryMatchRepository) ListCompletedMatches() ([]domain.Match, error) {
+	matches := make([]domain.Match, 0)
+	for _, match := range ir.matches {
+		if match.Result.Finished() {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CompletedAt.Equal(matches[j].CompletedAt) {
+			return matches[i].CompletedAt.Before(matches[j].CompletedAt)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches, nil
+}
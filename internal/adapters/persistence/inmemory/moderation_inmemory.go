@@ -0,0 +1,111 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryModerationRepository struct {
+	reports     map[uuid.UUID]domain.Report
+	decisions   []domain.ModerationDecision
+	standings   map[string]domain.ConsumerStanding
+	reportSeq   int
+	decisionSeq int
+}
+
+func NewInMemoryModerationRepository() ports.ModerationRepository {
+	return &InMemoryModerationRepository{
+		reports:   make(map[uuid.UUID]domain.Report),
+		standings: make(map[string]domain.ConsumerStanding),
+	}
+}
+
+func NewModerationRepositoryProvider(repo ports.ModerationRepository) ports.ModerationRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryModerationRepository) CreateReport(report domain.Report) (domain.Report, error) {
+	ir.reportSeq++
+	report.ID = ir.reportSeq
+	report.PublicID = uuid.New()
+	report.CreatedAt = time.Now()
+	if report.Status == "" {
+		report.Status = domain.ReportOpen
+	}
+
+	ir.reports[report.PublicID] = report
+
+	return report, nil
+}
+
+func (ir *InMemoryModerationRepository) UpdateReport(report domain.Report) (domain.Report, error) {
+	if _, ok := ir.reports[report.PublicID]; !ok {
+		return domain.Report{}, domain.ErrReportNotFound
+	}
+
+	ir.reports[report.PublicID] = report
+
+	return report, nil
+}
+
+func (ir *InMemoryModerationRepository) ListReports(filter domain.ReportFilter) ([]domain.Report, error) {
+	reports := make([]domain.Report, 0)
+	for _, report := range ir.reports {
+		if filter.Matches(report) {
+			reports = append(reports, report)
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if !reports[i].CreatedAt.Equal(reports[j].CreatedAt) {
+			return reports[i].CreatedAt.Before(reports[j].CreatedAt)
+		}
+		return reports[i].ID < reports[j].ID
+	})
+
+	return reports, nil
+}
+
+func (ir *InMemoryModerationRepository) CreateDecision(decision domain.ModerationDecision) (domain.ModerationDecision, error) {
+	ir.decisionSeq++
+	decision.ID = ir.decisionSeq
+	decision.PublicID = uuid.New()
+	if decision.At.IsZero() {
+		decision.At = time.Now()
+	}
+
+	ir.decisions = append(ir.decisions, decision)
+
+	return decision, nil
+}
+
+func (ir *InMemoryModerationRepository) ListDecisions(subject domain.ModerationSubject) ([]domain.ModerationDecision, error) {
+	decisions := make([]domain.ModerationDecision, 0)
+	for _, decision := range ir.decisions {
+		if decision.Subject == subject {
+			decisions = append(decisions, decision)
+		}
+	}
+
+	return decisions, nil
+}
+
+func (ir *InMemoryModerationRepository) FindStanding(consumerID string) (domain.ConsumerStanding, error) {
+	standing, ok := ir.standings[consumerID]
+	if !ok {
+		return domain.NewConsumerStanding(consumerID), nil
+	}
+
+	return standing, nil
+}
+
+func (ir *InMemoryModerationRepository) SaveStanding(standing domain.ConsumerStanding) (domain.ConsumerStanding, error) {
+	standing.UpdatedAt = time.Now()
+	ir.standings[standing.ConsumerID] = standing
+
+	return standing, nil
+}
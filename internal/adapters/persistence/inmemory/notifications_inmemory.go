@@ -0,0 +1,127 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryNotificationRepository struct {
+	notifications map[uuid.UUID]domain.Notification
+	keys          map[string]bool
+	preferences   map[uuid.UUID]domain.NotificationPreferences
+	tokens        map[string]uuid.UUID // unsubscribe token to player
+	seq           int
+}
+
+func NewInMemoryNotificationRepository() ports.NotificationRepository {
+	return &InMemoryNotificationRepository{
+		notifications: make(map[uuid.UUID]domain.Notification),
+		keys:          make(map[string]bool),
+		preferences:   make(map[uuid.UUID]domain.NotificationPreferences),
+		tokens:        make(map[string]uuid.UUID),
+	}
+}
+
+func NewNotificationRepositoryProvider(repo ports.NotificationRepository) ports.NotificationRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryNotificationRepository) EnqueueNotification(notification domain.Notification) (domain.Notification, error) {
+	ir.seq++
+	notification.ID = ir.seq
+	notification.PublicID = uuid.New()
+	notification.CreatedAt = time.Now()
+	notification.UpdatedAt = notification.CreatedAt
+
+	ir.notifications[notification.PublicID] = notification
+	if notification.Key != "" {
+		ir.keys[notification.Key] = true
+	}
+
+	return notification, nil
+}
+
+func (ir *InMemoryNotificationRepository) UpdateNotification(notification domain.Notification) (domain.Notification, error) {
+	if _, ok := ir.notifications[notification.PublicID]; !ok {
+		return domain.Notification{}, domain.ErrNotificationNotFound
+	}
+
+	notification.UpdatedAt = time.Now()
+	ir.notifications[notification.PublicID] = notification
+
+	return notification, nil
+}
+
+func (ir *InMemoryNotificationRepository) HasNotification(key string) (bool, error) {
+	return ir.keys[key], nil
+}
+
+func (ir *InMemoryNotificationRepository) DueNotifications(now time.Time, limit int) ([]domain.Notification, error) {
+	notifications := make([]domain.Notification, 0)
+	for _, n := range ir.notifications {
+		if n.Status == domain.NotificationPending && !n.NextAttemptAt.After(now) {
+			notifications = append(notifications, n)
+		}
+	}
+
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID < notifications[j].ID })
+	if limit > 0 && limit < len(notifications) {
+		notifications = notifications[:limit]
+	}
+
+	return notifications, nil
+}
+
+func (ir *InMemoryNotificationRepository) ListNotifications(playerID uuid.UUID, limit int) ([]domain.Notification, error) {
+	notifications := make([]domain.Notification, 0)
+	for _, n := range ir.notifications {
+		if n.PlayerID == playerID {
+			notifications = append(notifications, n)
+		}
+	}
+
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID > notifications[j].ID })
+	if limit > 0 && limit < len(notifications) {
+		notifications = notifications[:limit]
+	}
+
+	return notifications, nil
+}
+
+func (ir *InMemoryNotificationRepository) FindPreferences(playerID uuid.UUID) (domain.NotificationPreferences, error) {
+	prefs, ok := ir.preferences[playerID]
+	if !ok {
+		return domain.NotificationPreferences{}, domain.ErrNotificationPrefsNotFound
+	}
+
+	prefs.Muted = append([]domain.NotificationKind(nil), prefs.Muted...)
+	return prefs, nil
+}
+
+func (ir *InMemoryNotificationRepository) FindPreferencesByToken(token string) (domain.NotificationPreferences, error) {
+	playerID, ok := ir.tokens[token]
+	if !ok {
+		return domain.NotificationPreferences{}, domain.ErrNotificationPrefsNotFound
+	}
+
+	return ir.FindPreferences(playerID)
+}
+
+func (ir *InMemoryNotificationRepository) SavePreferences(prefs domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	if previous, ok := ir.preferences[prefs.PlayerID]; ok && previous.UnsubscribeToken != prefs.UnsubscribeToken {
+		delete(ir.tokens, previous.UnsubscribeToken)
+	}
+
+	prefs.UpdatedAt = time.Now()
+	prefs.Muted = append([]domain.NotificationKind(nil), prefs.Muted...)
+	ir.preferences[prefs.PlayerID] = prefs
+	if prefs.UnsubscribeToken != "" {
+		ir.tokens[prefs.UnsubscribeToken] = prefs.PlayerID
+	}
+
+	return prefs, nil
+}
@@ -0,0 +1,181 @@
+package inmemory
+
+import (
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryOutboxRepository struct {
+	entries map[int64]*domain.OutboxEntry
+	pending []int64 // ids neither dispatched nor dead, in order
+	nextID  int64
+}
+
+func NewInMemoryOutboxRepository() ports.OutboxRepository {
+	return &InMemoryOutboxRepository{
+		entries: make(map[int64]*domain.OutboxEntry),
+	}
+}
+
+func NewOutboxRepositoryProvider(repo ports.OutboxRepository) ports.OutboxRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryOutboxRepository) Append(events ...domain.Event) error {
+	now := time.Now()
+	for _, event := range events {
+		ir.nextID++
+		ir.entries[ir.nextID] = &domain.OutboxEntry{
+			ID:            ir.nextID,
+			EventID:       uuid.New(),
+			Event:         event,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		ir.pending = append(ir.pending, ir.nextID)
+	}
+	return nil
+}
+
+func (ir *InMemoryOutboxRepository) Pending(limit int) ([]domain.OutboxEntry, error) {
+	n := len(ir.pending)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+
+	entries := make([]domain.OutboxEntry, n)
+	for i, id := range ir.pending[:n] {
+		entry := *ir.entries[id]
+		entry.Delivered = append([]string(nil), entry.Delivered...)
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+func (ir *InMemoryOutboxRepository) Due(now time.Time, limit int) ([]domain.OutboxEntry, error) {
+	var entries []domain.OutboxEntry
+	blocked := make(map[uuid.UUID]bool) // the aggregates waiting for the retry of an entry
+	for _, id := range ir.pending {
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+
+		entry := *ir.entries[id]
+		aggregate := entry.Event.AggregateID()
+		if blocked[aggregate] {
+			continue
+		}
+		if entry.NextAttemptAt.After(now) {
+			blocked[aggregate] = true
+			continue
+		}
+
+		entry.Delivered = append([]string(nil), entry.Delivered...)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (ir *InMemoryOutboxRepository) MarkDelivered(id int64, subscriber string) error {
+	entry, ok := ir.entries[id]
+	if !ok {
+		return domain.ErrOutboxEntryNotFound
+	}
+	entry.Delivered = append(entry.Delivered, subscriber)
+	return nil
+}
+
+func (ir *InMemoryOutboxRepository) MarkDispatched(id int64, at time.Time) error {
+	entry, ok := ir.entries[id]
+	if !ok {
+		return domain.ErrOutboxEntryNotFound
+	}
+	entry.DispatchedAt = at
+	ir.removePending(id)
+	return nil
+}
+
+func (ir *InMemoryOutboxRepository) MarkFailed(id int64, reason string, nextAttempt time.Time) error {
+	entry, ok := ir.entries[id]
+	if !ok {
+		return domain.ErrOutboxEntryNotFound
+	}
+	entry.Attempts++
+	entry.LastError = reason
+	entry.NextAttemptAt = nextAttempt
+	return nil
+}
+
+func (ir *InMemoryOutboxRepository) MarkDead(id int64, reason string, at time.Time) error {
+	entry, ok := ir.entries[id]
+	if !ok {
+		return domain.ErrOutboxEntryNotFound
+	}
+	entry.Attempts++
+	entry.LastError = reason
+	entry.DeadAt = at
+	ir.removePending(id)
+	return nil
+}
+
+func (ir *InMemoryOutboxRepository) Prune(before time.Time) (int, error) {
+	pruned := 0
+	for id, entry := range ir.entries {
+		finished := entry.DispatchedAt
+		if finished.IsZero() {
+			finished = entry.DeadAt
+		}
+		if !finished.IsZero() && finished.Before(before) {
+			delete(ir.entries, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func (ir *InMemoryOutboxRepository) removePending(id int64) {
+	for i, pending := range ir.pending {
+		if pending == id {
+			ir.pending = append(ir.pending[:i], ir.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// eventBuffer holds the events recorded during a WriteTx, the provider moves them to the
+// outbox once the transaction succeeded and drops them when it failed
+type eventBuffer struct {
+	events []domain.Event
+}
+
+func (eb *eventBuffer) RecordEvents(events ...domain.Event) error {
+	eb.events = append(eb.events, events...)
+	return nil
+}
+
+func (eb *eventBuffer) takeEvents() []domain.Event {
+	events := eb.events
+	eb.events = nil
+	return events
+}
+
+// commitEvents moves the events recorded by the repository to the outbox when the transaction
+// succeeded, a SQL adapter would insert them into the outbox table within the same transaction
+func commitEvents(repo any, outbox ports.OutboxRepositoryProvider, txErr error) error {
+	buffer, ok := repo.(interface{ takeEvents() []domain.Event })
+	if !ok {
+		return txErr
+	}
+
+	events := buffer.takeEvents()
+	if txErr != nil || len(events) == 0 || outbox == nil {
+		return txErr
+	}
+
+	return outbox.WriteTx(func(o ports.OutboxRepository) error {
+		return o.Append(events...)
+	})
+}
@@ -0,0 +1,120 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryPlayerRepository struct {
+	players map[uuid.UUID]domain.Player
+	history map[uuid.UUID][]domain.RatingChange
+	changes int
+}
+
+func NewInMemoryPlayerRepository() ports.PlayerRepository {
+	return &InMemoryPlayerRepository{
+		players: make(map[uuid.UUID]domain.Player),
+		history: make(map[uuid.UUID][]domain.RatingChange),
+	}
+}
+
+func (ir *InMemoryPlayerRepository) CreatePlayer(player domain.Player) (domain.Player, error) {
+	player.ID = len(ir.players) + 1
+	player.PublicID = uuid.New()
+	player.CreatedAt = time.Now()
+	player.UpdatedAt = time.Now()
+	if player.Moderation == "" {
+		player.Moderation = domain.ModerationApproved
+	}
+
+	ir.players[player.PublicID] = player
+
+	return player, nil
+}
+
+func (ir *InMemoryPlayerRepository) UpdatePlayer(player domain.Player) (domain.Player, error) {
+	if _, ok := ir.players[player.PublicID]; !ok {
+		return domain.Player{}, domain.ErrPlayerNotFound
+	}
+
+	player.UpdatedAt = time.Now()
+	ir.players[player.PublicID] = player
+
+	return player, nil
+}
+
+func (ir *InMemoryPlayerRepository) FindPlayer(id uuid.UUID) (domain.Player, error) {
+	found, ok := ir.players[id]
+	if !ok {
+		return domain.Player{}, domain.ErrPlayerNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryPlayerRepository) FindPlayerByFideID(fideID int) (domain.Player, error) {
+	if fideID <= 0 {
+		return domain.Player{}, domain.ErrPlayerNotFound
+	}
+
+	for _, player := range ir.players {
+		if player.FIDE.ID == fideID {
+			return player, nil
+		}
+	}
+
+	return domain.Player{}, domain.ErrPlayerNotFound
+}
+
+func (ir *InMemoryPlayerRepository) AddRatingChange(change domain.RatingChange) (domain.RatingChange, error) {
+	if _, ok := ir.players[change.PlayerID]; !ok {
+		return domain.RatingChange{}, domain.ErrPlayerNotFound
+	}
+
+	ir.changes++
+	change.ID = ir.changes
+	ir.history[change.PlayerID] = append(ir.history[change.PlayerID], change)
+
+	return change, nil
+}
+
+func (ir *InMemoryPlayerRepository) ListRatingHistory(playerID uuid.UUID, source domain.RatingSource, rt domain.RatingType) ([]domain.RatingChange, error) {
+	if _, ok := ir.players[playerID]; !ok {
+		return nil, domain.ErrPlayerNotFound
+	}
+
+	changes := make([]domain.RatingChange, 0, len(ir.history[playerID]))
+	for _, change := range ir.history[playerID] {
+		if source != "" && change.Source != source {
+			continue
+		}
+		if rt != "" && change.Type != rt {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].RecordedAt.Before(changes[j].RecordedAt)
+	})
+
+	return changes, nil
+}
+
+func (ir *InMemoryPlayerRepository) DeleteRatingHistory(source domain.RatingSource) error {
+	for playerID, changes := range ir.history {
+		kept := changes[:0]
+		for _, change := range changes {
+			if change.Source != source {
+				kept = append(kept, change)
+			}
+		}
+		ir.history[playerID] = kept
+	}
+
+	return nil
+}
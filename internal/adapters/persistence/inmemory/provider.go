@@ -8,12 +8,16 @@ import (
 
 type InMemoryTournamentProvider struct {
 	repository ports.TournamentRepository
+	outbox     ports.OutboxRepositoryProvider
 	mu         *sync.RWMutex
 }
 
-func NewTournamentRepositoryProvider(repo ports.TournamentRepository) ports.TournamentRepositoryProvider {
+// NewTournamentRepositoryProvider - the events recorded in a WriteTx are appended to the outbox,
+// a nil outbox discards them
+func NewTournamentRepositoryProvider(repo ports.TournamentRepository, outbox ports.OutboxRepositoryProvider) ports.TournamentRepositoryProvider {
 	return &InMemoryTournamentProvider{
 		repository: repo,
+		outbox:     outbox,
 		mu:         &sync.RWMutex{},
 	}
 }
@@ -22,7 +26,7 @@ func (itp *InMemoryTournamentProvider) WriteTx(do func(ports.TournamentRepositor
 	itp.mu.Lock()
 	defer itp.mu.Unlock()
 
-	return do(itp.repository)
+	return commitEvents(itp.repository, itp.outbox, do(itp.repository))
 }
 
 func (itp *InMemoryTournamentProvider) ReadTx(do func(ports.TournamentRepository) error) error {
@@ -31,3 +35,51 @@ func (itp *InMemoryTournamentProvider) ReadTx(do func(ports.TournamentRepository
 
 	return do(itp.repository)
 }
+
+// txProvider provides thread safe access to any in memory repository
+type txProvider[R any] struct {
+	repository R
+	outbox     ports.OutboxRepositoryProvider // where the events recorded by the repository go
+	mu         *sync.RWMutex
+}
+
+func newTxProvider[R any](repo R) *txProvider[R] {
+	return &txProvider[R]{
+		repository: repo,
+		mu:         &sync.RWMutex{},
+	}
+}
+
+func (tp *txProvider[R]) WriteTx(do func(R) error) error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	return commitEvents(tp.repository, tp.outbox, do(tp.repository))
+}
+
+func (tp *txProvider[R]) ReadTx(do func(R) error) error {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	return do(tp.repository)
+}
+
+func NewPlayerRepositoryProvider(repo ports.PlayerRepository) ports.PlayerRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+// NewMatchRepositoryProvider - the events recorded in a WriteTx are appended to the outbox,
+// a nil outbox discards them
+func NewMatchRepositoryProvider(repo ports.MatchRepository, outbox ports.OutboxRepositoryProvider) ports.MatchRepositoryProvider {
+	provider := newTxProvider(repo)
+	provider.outbox = outbox
+	return provider
+}
+
+func NewRatingRepositoryProvider(repo ports.RatingRepository) ports.RatingRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func NewFideRepositoryProvider(repo ports.FideRepository) ports.FideRepositoryProvider {
+	return newTxProvider(repo)
+}
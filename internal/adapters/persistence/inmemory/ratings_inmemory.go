@@ -0,0 +1,63 @@
+package inmemory
+
+import (
+	"sort"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type ratingKey struct {
+	playerID uuid.UUID
+	pool     domain.RatingType
+}
+
+type InMemoryRatingRepository struct {
+	ratings map[ratingKey]domain.MapleRating
+}
+
+func NewInMemoryRatingRepository() ports.RatingRepository {
+	return &InMemoryRatingRepository{
+		ratings: make(map[ratingKey]domain.MapleRating),
+	}
+}
+
+func (ir *InMemoryRatingRepository) FindRating(playerID uuid.UUID, pool domain.RatingType) (domain.MapleRating, error) {
+	found, ok := ir.ratings[ratingKey{playerID: playerID, pool: pool}]
+	if !ok {
+		return domain.MapleRating{}, domain.ErrRatingNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryRatingRepository) SaveRating(rating domain.MapleRating) (domain.MapleRating, error) {
+	ir.ratings[ratingKey{playerID: rating.PlayerID, pool: rating.Pool}] = rating
+
+	return rating, nil
+}
+
+func (ir *InMemoryRatingRepository) ListRatings(pool domain.RatingType) ([]domain.MapleRating, error) {
+	ratings := make([]domain.MapleRating, 0)
+	for key, rating := range ir.ratings {
+		if key.pool == pool {
+			ratings = append(ratings, rating)
+		}
+	}
+
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Rating != ratings[j].Rating {
+			return ratings[i].Rating > ratings[j].Rating
+		}
+		return ratings[i].PlayerID.String() < ratings[j].PlayerID.String()
+	})
+
+	return ratings, nil
+}
+
+func (ir *InMemoryRatingRepository) DeleteRatings() error {
+	ir.ratings = make(map[ratingKey]domain.MapleRating)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package inmemory
+
+import (
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryRelayRepository struct {
+	relays map[uuid.UUID]domain.Relay // by match
+}
+
+func NewInMemoryRelayRepository() ports.RelayRepository {
+	return &InMemoryRelayRepository{
+		relays: make(map[uuid.UUID]domain.Relay),
+	}
+}
+
+func NewRelayRepositoryProvider(repo ports.RelayRepository) ports.RelayRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryRelayRepository) CreateRelay(relay domain.Relay) (domain.Relay, error) {
+	if _, ok := ir.relays[relay.MatchID]; ok {
+		return domain.Relay{}, domain.ErrRelayAlreadyStarted
+	}
+
+	relay.UpdatedAt = time.Now()
+	relay.Moves = append([]domain.RelayMove(nil), relay.Moves...)
+	ir.relays[relay.MatchID] = relay
+
+	return relay, nil
+}
+
+func (ir *InMemoryRelayRepository) UpdateRelay(relay domain.Relay) (domain.Relay, error) {
+	if _, ok := ir.relays[relay.MatchID]; !ok {
+		return domain.Relay{}, domain.ErrRelayNotFound
+	}
+
+	relay.UpdatedAt = time.Now()
+	relay.Moves = append([]domain.RelayMove(nil), relay.Moves...)
+	ir.relays[relay.MatchID] = relay
+
+	return relay, nil
+}
+
+func (ir *InMemoryRelayRepository) FindRelay(matchID uuid.UUID) (domain.Relay, error) {
+	found, ok := ir.relays[matchID]
+	if !ok {
+		return domain.Relay{}, domain.ErrRelayNotFound
+	}
+
+	found.Moves = append([]domain.RelayMove(nil), found.Moves...)
+	return found, nil
+}
@@ -3,6 +3,7 @@
 package inmemory
 
 import (
+	"sort"
 	"time"
 
 	"github.com/ctfrancia/maple/internal/core/domain"
@@ -11,6 +12,7 @@ import (
 )
 
 type InMemoryTournamentRepository struct {
+	eventBuffer
 	tournaments map[uuid.UUID]domain.Tournament
 }
 
@@ -25,6 +27,9 @@ func (ir *InMemoryTournamentRepository) CreateTournament(tournament domain.Tourn
 	tournament.PublicID = uuid.New()
 	tournament.CreatedAt = time.Now()
 	tournament.UpdatedAt = time.Now()
+	if tournament.Moderation == "" {
+		tournament.Moderation = domain.ModerationApproved
+	}
 
 	ir.tournaments[tournament.PublicID] = tournament
 
@@ -40,11 +45,28 @@ func (ir *InMemoryTournamentRepository) FindTournament(id uuid.UUID) (domain.Tou
 	return found, nil
 }
 
-func (ir *InMemoryTournamentRepository) ListTournaments(params any) ([]domain.Tournament, error) {
+func (ir *InMemoryTournamentRepository) UpdateTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	if _, ok := ir.tournaments[tournament.PublicID]; !ok {
+		return domain.Tournament{}, domain.ErrTournamentNotFound
+	}
+
+	tournament.UpdatedAt = time.Now()
+	ir.tournaments[tournament.PublicID] = tournament
+
+	return tournament, nil
+}
+
+func (ir *InMemoryTournamentRepository) ListTournaments(filter domain.TournamentFilter) ([]domain.Tournament, error) {
 	tournaments := make([]domain.Tournament, 0, len(ir.tournaments))
 	for _, tournament := range ir.tournaments {
-		tournaments = append(tournaments, tournament)
+		if filter.Matches(tournament) {
+			tournaments = append(tournaments, tournament)
+		}
 	}
 
+	sort.Slice(tournaments, func(i, j int) bool {
+		return tournaments[i].ID > tournaments[j].ID
+	})
+
 	return tournaments, nil
 }
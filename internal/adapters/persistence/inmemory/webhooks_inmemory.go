@@ -0,0 +1,164 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type InMemoryWebhookRepository struct {
+	webhooks    map[uuid.UUID]domain.Webhook
+	deliveries  map[uuid.UUID]domain.WebhookDelivery
+	webhookSeq  int
+	deliverySeq int
+}
+
+func NewInMemoryWebhookRepository() ports.WebhookRepository {
+	return &InMemoryWebhookRepository{
+		webhooks:   make(map[uuid.UUID]domain.Webhook),
+		deliveries: make(map[uuid.UUID]domain.WebhookDelivery),
+	}
+}
+
+func NewWebhookRepositoryProvider(repo ports.WebhookRepository) ports.WebhookRepositoryProvider {
+	return newTxProvider(repo)
+}
+
+func (ir *InMemoryWebhookRepository) CreateWebhook(webhook domain.Webhook) (domain.Webhook, error) {
+	ir.webhookSeq++
+	webhook.ID = ir.webhookSeq
+	webhook.PublicID = uuid.New()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	ir.webhooks[webhook.PublicID] = webhook
+
+	return webhook, nil
+}
+
+func (ir *InMemoryWebhookRepository) UpdateWebhook(webhook domain.Webhook) (domain.Webhook, error) {
+	if _, ok := ir.webhooks[webhook.PublicID]; !ok {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+
+	webhook.UpdatedAt = time.Now()
+	ir.webhooks[webhook.PublicID] = webhook
+
+	return webhook, nil
+}
+
+func (ir *InMemoryWebhookRepository) FindWebhook(id uuid.UUID) (domain.Webhook, error) {
+	found, ok := ir.webhooks[id]
+	if !ok {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+
+	return found, nil
+}
+
+func (ir *InMemoryWebhookRepository) ListWebhooks(consumerID string) ([]domain.Webhook, error) {
+	webhooks := make([]domain.Webhook, 0)
+	for _, webhook := range ir.webhooks {
+		if consumerID == "" || webhook.ConsumerID == consumerID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes the webhook, its delivery log is kept
+func (ir *InMemoryWebhookRepository) DeleteWebhook(id uuid.UUID) error {
+	if _, ok := ir.webhooks[id]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+
+	delete(ir.webhooks, id)
+
+	return nil
+}
+
+func (ir *InMemoryWebhookRepository) CreateDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	ir.deliverySeq++
+	delivery.ID = ir.deliverySeq
+	delivery.PublicID = uuid.New()
+	delivery.CreatedAt = time.Now()
+	delivery.UpdatedAt = delivery.CreatedAt
+
+	ir.deliveries[delivery.PublicID] = delivery
+
+	return copyDelivery(delivery), nil
+}
+
+func (ir *InMemoryWebhookRepository) UpdateDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	if _, ok := ir.deliveries[delivery.PublicID]; !ok {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+
+	delivery.UpdatedAt = time.Now()
+	ir.deliveries[delivery.PublicID] = copyDelivery(delivery)
+
+	return delivery, nil
+}
+
+func (ir *InMemoryWebhookRepository) FindDelivery(id uuid.UUID) (domain.WebhookDelivery, error) {
+	found, ok := ir.deliveries[id]
+	if !ok {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+
+	return copyDelivery(found), nil
+}
+
+func (ir *InMemoryWebhookRepository) HasDelivery(webhookID, eventID uuid.UUID) (bool, error) {
+	for _, delivery := range ir.deliveries {
+		if delivery.WebhookID == webhookID && delivery.EventID == eventID && delivery.RedeliveryOf == uuid.Nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (ir *InMemoryWebhookRepository) ListDeliveries(webhookID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range ir.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if limit > 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (ir *InMemoryWebhookRepository) DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range ir.deliveries {
+		if delivery.Status == domain.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	if limit > 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// copyDelivery keeps callers from appending to the attempts stored in the map
+func copyDelivery(delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	delivery.Attempts = append([]domain.WebhookAttempt(nil), delivery.Attempts...)
+	return delivery
+}
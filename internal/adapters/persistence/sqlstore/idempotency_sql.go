@@ -0,0 +1,169 @@
+// Package sqlstore keeps the state shared by the instances of maple in a database/sql database.
+// The statements are written for PostgreSQL and run on SQLite as well
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// idempotencySweepInterval is how often the expired keys are deleted, they are ignored until then
+const idempotencySweepInterval = time.Minute
+
+const createIdempotencyTable = `CREATE TABLE IF NOT EXISTS idempotency_keys (
+	idempotency_key TEXT PRIMARY KEY,
+	fingerprint     TEXT NOT NULL,
+	response        TEXT,
+	created_at      BIGINT NOT NULL,
+	expires_at      BIGINT NOT NULL
+)`
+
+// storedResponse is the response column, it is null while the first request is in flight
+type storedResponse struct {
+	Status int                 `json:"status"`
+	Header map[string][]string `json:"header,omitempty"`
+	Body   []byte              `json:"body,omitempty"`
+}
+
+// SQLIdempotencyStore shares the keys between the instances, a retry is replayed whichever
+// instance it reaches. The times are stored as unix milliseconds
+type SQLIdempotencyStore struct {
+	db        *sql.DB
+	lastSweep time.Time
+	mu        sync.Mutex // guards lastSweep
+}
+
+// NewSQLIdempotencyStore creates the idempotency_keys table when it does not exist, the store is
+// a ports.Pinger of the database
+func NewSQLIdempotencyStore(ctx context.Context, db *sql.DB) (ports.IdempotencyStore, error) {
+	if _, err := db.ExecContext(ctx, createIdempotencyTable); err != nil {
+		return nil, fmt.Errorf("creating the idempotency_keys table: %w", err)
+	}
+
+	return &SQLIdempotencyStore{
+		db:        db,
+		lastSweep: time.Now(),
+	}, nil
+}
+
+func (ss *SQLIdempotencyStore) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	now := time.Now()
+	if err := ss.sweep(ctx, now); err != nil {
+		return nil, err
+	}
+
+	// the expired record of the key gives way to the claim
+	if _, err := ss.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND expires_at <= $2`,
+		key, now.UnixMilli()); err != nil {
+		return nil, fmt.Errorf("deleting the expired idempotency key: %w", err)
+	}
+
+	result, err := ss.db.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (idempotency_key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT (idempotency_key) DO NOTHING`,
+		key, fingerprint, now.UnixMilli(), now.Add(ttl).UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("claiming the idempotency key: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("claiming the idempotency key: %w", err)
+	}
+	if claimed == 1 {
+		return nil, nil
+	}
+
+	record := domain.IdempotencyRecord{Key: key}
+	var response sql.NullString
+	var createdAt, expiresAt int64
+	err = ss.db.QueryRowContext(ctx,
+		`SELECT fingerprint, response, created_at, expires_at FROM idempotency_keys WHERE idempotency_key = $1`,
+		key).Scan(&record.Fingerprint, &response, &createdAt, &expiresAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// released by the first request since the claim failed, the retry can be sent again
+		return nil, domain.ErrIdempotencyKeyInFlight
+	case err != nil:
+		return nil, fmt.Errorf("reading the idempotency key: %w", err)
+	}
+	record.CreatedAt = time.UnixMilli(createdAt)
+	record.ExpiresAt = time.UnixMilli(expiresAt)
+
+	switch {
+	case record.Fingerprint != fingerprint:
+		return nil, domain.ErrIdempotencyKeyReused
+	case !response.Valid:
+		return nil, domain.ErrIdempotencyKeyInFlight
+	}
+
+	var stored storedResponse
+	if err := json.Unmarshal([]byte(response.String), &stored); err != nil {
+		return nil, fmt.Errorf("decoding the stored response: %w", err)
+	}
+	record.Response = &domain.IdempotentResponse{
+		Status: stored.Status,
+		Header: stored.Header,
+		Body:   stored.Body,
+	}
+	return &record, nil
+}
+
+func (ss *SQLIdempotencyStore) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	raw, err := json.Marshal(storedResponse{
+		Status: response.Status,
+		Header: response.Header,
+		Body:   response.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding the response: %w", err)
+	}
+
+	if _, err := ss.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET response = $1 WHERE idempotency_key = $2`,
+		string(raw), key); err != nil {
+		return fmt.Errorf("storing the response of the idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (ss *SQLIdempotencyStore) Release(ctx context.Context, key string) error {
+	if _, err := ss.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND response IS NULL`,
+		key); err != nil {
+		return fmt.Errorf("releasing the idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Ping tells whether the database answers
+func (ss *SQLIdempotencyStore) Ping(ctx context.Context) error {
+	return ss.db.PingContext(ctx)
+}
+
+// sweep deletes the expired keys once per interval, whichever instance gets there first
+func (ss *SQLIdempotencyStore) sweep(ctx context.Context, now time.Time) error {
+	ss.mu.Lock()
+	due := now.Sub(ss.lastSweep) >= idempotencySweepInterval
+	if due {
+		ss.lastSweep = now
+	}
+	ss.mu.Unlock()
+	if !due {
+		return nil
+	}
+
+	if _, err := ss.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now.UnixMilli()); err != nil {
+		return fmt.Errorf("deleting the expired idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newStore(t *testing.T) (ports.IdempotencyStore, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "maple.db")+"?_pragma=busy_timeout(5000)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := NewSQLIdempotencyStore(context.Background(), db)
+	require.NoError(t, err)
+	return store, db
+}
+
+func TestSQLIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	response := domain.IdempotentResponse{
+		Status: 201,
+		Header: map[string][]string{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"tournament":{"id":"1"}}`),
+	}
+
+	t.Run("replays the response of a completed key", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		record, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+		require.NoError(t, store.Complete(ctx, "key", response))
+
+		record, err = store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		require.NotNil(t, record)
+		assert.True(t, record.Completed())
+		assert.Equal(t, response, *record.Response)
+		assert.True(t, record.ExpiresAt.After(record.CreatedAt))
+	})
+
+	t.Run("a key in flight is a conflict", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		_, err = store.Begin(ctx, "key", "body", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInFlight)
+	})
+
+	t.Run("a key reused with another body", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete(ctx, "key", response))
+
+		_, err = store.Begin(ctx, "key", "other body", time.Hour)
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("a released key runs again", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.Release(ctx, "key"))
+
+		record, err := store.Begin(ctx, "key", "other body", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("a completed key is not released", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete(ctx, "key", response))
+		require.NoError(t, store.Release(ctx, "key"))
+
+		record, err := store.Begin(ctx, "key", "body", time.Hour)
+		require.NoError(t, err)
+		assert.NotNil(t, record)
+	})
+
+	t.Run("an expired key runs again", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, err := store.Begin(ctx, "key", "body", time.Millisecond)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete(ctx, "key", response))
+		time.Sleep(5 * time.Millisecond)
+
+		record, err := store.Begin(ctx, "key", "other body", time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("one of the concurrent requests claims the key", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		claimed, inFlight := 0, 0
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := store.Begin(ctx, "key", "body", time.Hour)
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err == nil:
+					claimed++
+				case assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInFlight):
+					inFlight++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, 7, inFlight)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		store, db := newStore(t)
+
+		pinger, ok := store.(ports.Pinger)
+		require.True(t, ok)
+		assert.NoError(t, pinger.Ping(ctx))
+
+		db.Close()
+		assert.Error(t, pinger.Ping(ctx))
+	})
+}
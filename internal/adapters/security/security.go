@@ -14,10 +14,16 @@ import (
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
-type SecurityAdapter struct{}
+// SecurityAdapter hashes the passwords with argon2id, the parameters are written in the hash
+// so the ones of the config can change without breaking the stored hashes
+type SecurityAdapter struct {
+	params domain.A2params
+}
 
-func NewSecurityAdapter() ports.SecurityAdapter {
-	return &SecurityAdapter{}
+func NewSecurityAdapter(params domain.A2params) ports.SecurityAdapter {
+	return &SecurityAdapter{
+		params: params,
+	}
 }
 
 // CreateSecretKey creates a new secret key, or password, used for user's credentials
@@ -50,15 +56,7 @@ func (sa *SecurityAdapter) CreateSecretKey(length int) (string, error) {
 
 // Hash hashes the password
 func (sa *SecurityAdapter) Hash(password string) (string, error) {
-	// Define the parameters for the Argon2 algorithm
-	// FIXME: These should be configuration options
-	p := domain.A2params{
-		Memory:      64 * 1024,
-		Iterations:  3,
-		Parallelism: 2,
-		SaltLength:  16,
-		KeyLength:   32,
-	}
+	p := sa.params
 	hash, err := generateFromPassword(password, &p)
 	if err != nil {
 		return "", fmt.Errorf("error generating hash: %v", err)
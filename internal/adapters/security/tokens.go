@@ -0,0 +1,46 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// TokenAuthenticator authenticates the bearer tokens of the configuration. They are kept as their
+// sha256, looking one up does not tell how much of it another token shares
+type TokenAuthenticator struct {
+	principals map[[sha256.Size]byte]domain.Principal
+}
+
+// NewTokenAuthenticator - tokens maps each role to the tokens of who has it by their id, the
+// consumer ids, the names of the moderators and arbiters and the public ids of the players. An empty token
+// authenticates nobody
+func NewTokenAuthenticator(adminToken string, tokens map[domain.Role]map[string]string) ports.Authenticator {
+	ta := &TokenAuthenticator{
+		principals: make(map[[sha256.Size]byte]domain.Principal),
+	}
+	for role, ids := range tokens {
+		for id, token := range ids {
+			ta.add(token, domain.Principal{Role: role, ID: id})
+		}
+	}
+	ta.add(adminToken, domain.Principal{Role: domain.RoleAdmin, ID: "admin"})
+
+	return ta
+}
+
+func (ta *TokenAuthenticator) add(token string, principal domain.Principal) {
+	if token != "" {
+		ta.principals[sha256.Sum256([]byte(token))] = principal
+	}
+}
+
+func (ta *TokenAuthenticator) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	principal, ok := ta.principals[sha256.Sum256([]byte(token))]
+	if token == "" || !ok {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+	return principal, nil
+}
@@ -2,19 +2,59 @@
 package system
 
 import (
+	"runtime"
+	"runtime/debug"
+
 	"github.com/ctfrancia/maple/internal/core/domain"
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
-type SystemAdapter struct{}
+// Set when the binary is built, e.g.
+//
+//	go build -ldflags "-X github.com/ctfrancia/maple/internal/adapters/system.version=1.4.0 \
+//		-X github.com/ctfrancia/maple/internal/adapters/system.commit=$(git rev-parse HEAD) \
+//		-X github.com/ctfrancia/maple/internal/adapters/system.buildTime=$(date -u +%FT%TZ)"
+//
+// the commit and the build time fall back to the ones go stamps from the vcs
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
+type SystemAdapter struct {
+	system domain.System
+}
 
 func NewSystemAdapter() ports.SystemAdapter {
-	return &SystemAdapter{}
+	return &SystemAdapter{
+		system: buildInfo(),
+	}
 }
 
 func (sha *SystemAdapter) GetSystemInfo() domain.System {
+	return sha.system
+}
+
+func buildInfo() domain.System {
 	system := domain.System{
-		Version: "1.0.0",
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return system
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && system.Commit == "":
+			system.Commit = setting.Value
+		case setting.Key == "vcs.time" && system.BuildTime == "":
+			system.BuildTime = setting.Value
+		}
 	}
 
 	return system
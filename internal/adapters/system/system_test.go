@@ -1,6 +1,7 @@
 package system
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/ctfrancia/maple/internal/core/domain"
@@ -20,10 +21,10 @@ func TestNewSystemAdapter(t *testing.T) {
 }
 
 func TestSystemAdapter_GetSystemInfo(t *testing.T) {
-	t.Run("should return system info with correct version", func(t *testing.T) {
+	t.Run("should return system info with the version of the build", func(t *testing.T) {
 		// Arrange
 		adapter := NewSystemAdapter()
-		expectedVersion := "1.0.0"
+		expectedVersion := "dev"
 
 		// Act
 		result := adapter.GetSystemInfo()
@@ -55,6 +56,7 @@ func TestSystemAdapter_GetSystemInfo(t *testing.T) {
 
 		// Assert
 		assert.NotEmpty(t, result.Version)
+		assert.Equal(t, runtime.Version(), result.GoVersion)
 	})
 }
 
@@ -78,13 +80,22 @@ func TestSystemAdapter_Interface(t *testing.T) {
 // Table-driven test example (useful if you add more fields to System)
 func TestSystemAdapter_GetSystemInfo_TableDriven(t *testing.T) {
 	tests := []struct {
-		name     string
-		expected domain.System
+		name      string
+		version   string
+		commit    string
+		buildTime string
+		expected  domain.System
 	}{
 		{
-			name: "should return expected system information",
+			name:      "should return the build information set with ldflags",
+			version:   "1.4.0",
+			commit:    "4fb852b",
+			buildTime: "2026-10-19T08:00:00Z",
 			expected: domain.System{
-				Version: "1.0.0",
+				Version:   "1.4.0",
+				Commit:    "4fb852b",
+				BuildTime: "2026-10-19T08:00:00Z",
+				GoVersion: runtime.Version(),
 			},
 		},
 	}
@@ -92,6 +103,8 @@ func TestSystemAdapter_GetSystemInfo_TableDriven(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
+			defer func(v, c, b string) { version, commit, buildTime = v, c, b }(version, commit, buildTime)
+			version, commit, buildTime = tt.version, tt.commit, tt.buildTime
 			adapter := NewSystemAdapter()
 
 			// Act
@@ -0,0 +1,197 @@
+// Package tracing records the spans of the requests with the OpenTelemetry SDK and exports
+// them to a collector over OTLP, or writes them out for the tests and local runs
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config tunes the tracer
+type Config struct {
+	// ServiceName is the service.name of the resource the spans belong to
+	ServiceName string
+	// SampleRatio is the share of the traces started here that are recorded, the traces
+	// continued from a caller follow its decision
+	SampleRatio float64
+	// BatchSize is how many spans are exported at once
+	BatchSize int
+	// QueueSize is how many ended spans wait for the exporter, the ones over it are dropped
+	QueueSize int
+	// FlushInterval is how often the spans waiting are exported when the batch is not full
+	FlushInterval time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ServiceName:   "maple",
+		SampleRatio:   1,
+		BatchSize:     512,
+		QueueSize:     2048,
+		FlushInterval: 5 * time.Second,
+	}
+}
+
+// Tracer implements ports.Tracer with an SDK tracer provider, the recorded spans are exported
+// in batches in the background
+type Tracer struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer - the exporter is one of the SDK, see NewOTLPExporter and NewWriterExporter
+func NewTracer(log ports.Logger, exporter sdktrace.SpanExporter, config Config) *Tracer {
+	defaults := DefaultConfig()
+	if config.ServiceName == "" {
+		config.ServiceName = defaults.ServiceName
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(loggedExporter{SpanExporter: exporter, logger: log},
+			sdktrace.WithMaxExportBatchSize(config.BatchSize),
+			sdktrace.WithMaxQueueSize(config.QueueSize),
+			sdktrace.WithBatchTimeout(config.FlushInterval),
+		),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(sdkresource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+	)
+
+	return &Tracer{
+		provider:   provider,
+		tracer:     provider.Tracer("github.com/ctfrancia/maple"),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, kind ports.SpanKind, fields ...ports.LogField) (context.Context, ports.Span) {
+	ctx, s := t.tracer.Start(parentOf(ctx), name, trace.WithSpanKind(spanKind(kind)), trace.WithAttributes(attributes(fields)...))
+	return ports.WithSpan(ctx, span{s}), span{s}
+}
+
+func (t *Tracer) Inject(ctx context.Context, carrier map[string]string) {
+	t.propagator.Inject(parentOf(ctx), propagation.MapCarrier(carrier))
+}
+
+func (t *Tracer) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return t.propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// Shutdown exports the spans still waiting, the spans ended after it are dropped
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+// parentOf puts the current span of the context where the SDK looks for the parent, the
+// worker pool carries the span across goroutines with ports.WithSpan only
+func parentOf(ctx context.Context) context.Context {
+	if s, ok := ports.SpanFromContext(ctx).(span); ok {
+		return trace.ContextWithSpan(ctx, s.span)
+	}
+	return ctx
+}
+
+// loggedExporter logs the failed exports, the batcher only hands them to the global handler
+type loggedExporter struct {
+	sdktrace.SpanExporter
+	logger ports.Logger
+}
+
+func (e loggedExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.logger.Warn(ctx, "spans export failed", ports.Int("spans", len(spans)), ports.Error("error", err))
+	}
+	return err
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetName(name string) {
+	s.span.SetName(name)
+}
+
+func (s span) SetAttributes(fields ...ports.LogField) {
+	s.span.SetAttributes(attributes(fields)...)
+}
+
+func (s span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End records the span when it is sampled, ending it again does nothing
+func (s span) End() {
+	s.span.End()
+}
+
+func (s span) TraceID() string {
+	return s.span.SpanContext().TraceID().String()
+}
+
+func (s span) SpanID() string {
+	return s.span.SpanContext().SpanID().String()
+}
+
+func spanKind(kind ports.SpanKind) trace.SpanKind {
+	switch kind {
+	case ports.SpanKindServer:
+		return trace.SpanKindServer
+	case ports.SpanKindClient:
+		return trace.SpanKindClient
+	case ports.SpanKindProducer:
+		return trace.SpanKindProducer
+	case ports.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+// attributes keeps the numbers and booleans typed, anything else is written as text
+func attributes(fields []ports.LogField) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(fields))
+	for _, field := range fields {
+		switch value := field.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(field.Key, value))
+		case bool:
+			kvs = append(kvs, attribute.Bool(field.Key, value))
+		case int:
+			kvs = append(kvs, attribute.Int(field.Key, value))
+		case int64:
+			kvs = append(kvs, attribute.Int64(field.Key, value))
+		case float64:
+			kvs = append(kvs, attribute.Float64(field.Key, value))
+		case error:
+			kvs = append(kvs, attribute.String(field.Key, value.Error()))
+		default:
+			kvs = append(kvs, attribute.String(field.Key, fmt.Sprint(value)))
+		}
+	}
+	return kvs
+}
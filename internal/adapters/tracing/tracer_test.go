@@ -0,0 +1,161 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/logger"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder keeps the exported spans past the shutdown of the tracer, the in-memory exporter
+// forgets them on its own shutdown
+type recorder struct {
+	*tracetest.InMemoryExporter
+}
+
+func (recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// newTestTracer - the spans are read with the returned function, it shuts the tracer down
+func newTestTracer(t *testing.T, config Config) (*Tracer, func() tracetest.SpanStubs) {
+	t.Helper()
+
+	exported := recorder{tracetest.NewInMemoryExporter()}
+	tracer := NewTracer(logger.NewZapLogger("test"), exported, config)
+	return tracer, func() tracetest.SpanStubs {
+		require.NoError(t, tracer.Shutdown(context.Background()))
+		return exported.GetSpans()
+	}
+}
+
+func TestTracer_ParentAndChild(t *testing.T) {
+	tracer, spans := newTestTracer(t, DefaultConfig())
+
+	ctx := ports.WithTracer(context.Background(), tracer)
+	ctx, parent := ports.StartSpan(ctx, "GET /v1/tournament/find/{id}", ports.SpanKindServer)
+	_, child := ports.StartSpan(ctx, "TournamentServicer.FindTournament", ports.SpanKindInternal, ports.String("tournament_id", "42"))
+	child.RecordError(errors.New("tournament not found"))
+	child.End()
+	parent.End()
+	parent.End()
+
+	// ended twice but exported once
+	exported := spans()
+	require.Len(t, exported, 2)
+	childData, parentData := exported[0], exported[1]
+
+	assert.Equal(t, "TournamentServicer.FindTournament", childData.Name)
+	assert.Equal(t, trace.SpanKindServer, parentData.SpanKind)
+	assert.Equal(t, parentData.SpanContext.TraceID(), childData.SpanContext.TraceID())
+	assert.Equal(t, parentData.SpanContext.SpanID(), childData.Parent.SpanID())
+	assert.False(t, parentData.Parent.IsValid())
+	assert.Equal(t, codes.Error, childData.Status.Code)
+	assert.Equal(t, "tournament not found", childData.Status.Description)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tournament_id", "42")}, childData.Attributes)
+	service, _ := parentData.Resource.Set().Value("service.name")
+	assert.Equal(t, "maple", service.AsString())
+	assert.Len(t, parent.TraceID(), 32)
+	assert.Len(t, parent.SpanID(), 16)
+}
+
+func TestTracer_Propagation(t *testing.T) {
+	tracer, spans := newTestTracer(t, DefaultConfig())
+
+	incoming := map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"tracestate":  "congo=t61rcWkgMzE",
+	}
+	ctx := tracer.Extract(context.Background(), incoming)
+	ctx, span := tracer.Start(ctx, "POST /v1/webhook/new", ports.SpanKindServer)
+
+	outgoing := make(map[string]string)
+	tracer.Inject(ctx, outgoing)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanID()+"-01", outgoing["traceparent"])
+	assert.Equal(t, "congo=t61rcWkgMzE", outgoing["tracestate"])
+
+	span.End()
+	exported := spans()
+	require.Len(t, exported, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", exported[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", exported[0].Parent.SpanID().String())
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	tracer, spans := newTestTracer(t, Config{SampleRatio: 0})
+
+	// the caller sampled the trace, the ratio does not apply
+	ctx := tracer.Extract(context.Background(), map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+	_, sampled := tracer.Start(ctx, "sampled", ports.SpanKindServer)
+	sampled.End()
+
+	// a root trace is left out, its context still goes to the callees
+	ctx, dropped := tracer.Start(context.Background(), "dropped", ports.SpanKindServer)
+	outgoing := make(map[string]string)
+	tracer.Inject(ctx, outgoing)
+	assert.True(t, strings.HasSuffix(outgoing["traceparent"], "-00"))
+	dropped.End()
+
+	exported := spans()
+	require.Len(t, exported, 1)
+	assert.Equal(t, "sampled", exported[0].Name)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("api-key"))
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), server.URL, map[string]string{"api-key": "secret"})
+	require.NoError(t, err)
+	tracer := NewTracer(logger.NewZapLogger("test"), exporter, DefaultConfig())
+	_, span := tracer.Start(context.Background(), "GET /v1/tournament/", ports.SpanKindServer)
+	span.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	assert.Contains(t, string(body), "GET /v1/tournament/")
+	assert.Contains(t, string(body), "maple")
+}
+
+func TestWriterExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := NewWriterExporter(&out)
+	require.NoError(t, err)
+	tracer := NewTracer(logger.NewZapLogger("test"), exporter, DefaultConfig())
+
+	ctx, first := tracer.Start(context.Background(), "first", ports.SpanKindClient)
+	_, second := tracer.Start(ctx, "second", ports.SpanKindInternal)
+	second.End()
+	first.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var written struct {
+		Name   string
+		Parent struct{ SpanID string }
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &written))
+	assert.Equal(t, "second", written.Name)
+	assert.Equal(t, first.SpanID(), written.Parent.SpanID)
+}
@@ -0,0 +1,13 @@
+package tracing
+
+import (
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+)
+
+// NewWriterExporter writes a line of json per span, to stdout or a file for the local runs and
+// the tests
+func NewWriterExporter(w io.Writer) (*stdouttrace.Exporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ImportChatCommand represents the intent to read the announcements of a chat export file
+type ImportChatCommand struct {
+	Path   string                  `json:"path"`   // relative to the import directory
+	Format domain.ChatExportFormat `json:"format"` // optional, told by the extension: .txt whatsapp, .json telegram
+	// Timezone is optional, the IANA zone the chat happened in, defaults to Europe/Madrid
+	Timezone domain.Timezone `json:"timezone"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ImportChatCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if strings.TrimSpace(cmd.Path) == "" {
+		errors["path"] = validation.Required()
+	} else if !filepath.IsLocal(cmd.Path) {
+		errors["path"] = validation.LocalPath()
+	}
+	if cmd.Format != "" && !cmd.Format.Valid() {
+		errors["format"] = validation.OneOf("whatsapp", "telegram")
+	}
+	validateTimezone(cmd.Timezone, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ListAnnouncementsCommand represents the intent to read the review queue, pending by default
+type ListAnnouncementsCommand struct {
+	Status domain.AnnouncementStatus `json:"status"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListAnnouncementsCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Status != "" && !cmd.Status.Valid() {
+		errors["status"] = validation.OneOf("pending", "confirmed", "rejected")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindImportCommand represents the intent to read how an import of a chat export is going
+type FindImportCommand struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindImportCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindAnnouncementCommand represents the intent to read an announcement
+type FindAnnouncementCommand struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindAnnouncementCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ConfirmAnnouncementCommand represents an organizer turning an announcement into a draft
+// tournament. Every field but the ID is optional and replaces what was read from the message
+type ConfirmAnnouncementCommand struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	StartsAt    *time.Time `json:"starts_at"`
+	Venue       string     `json:"venue"`
+	Address     string     `json:"address"`
+	City        string     `json:"city"`
+	Fee         *int64     `json:"fee"` // in cents
+	TimeControl string     `json:"time_control"`
+	Phone       string     `json:"phone"`
+	Note        string     `json:"note"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ConfirmAnnouncementCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if len(cmd.Name) > 120 {
+		errors["name"] = validation.TooLong(120)
+	}
+	if cmd.Fee != nil && *cmd.Fee < 0 {
+		errors["fee"] = validation.Positive()
+	}
+	if cmd.TimeControl != "" {
+		if _, err := domain.ParseTimeControl(cmd.TimeControl); err != nil {
+			errors["time_control"] = validation.InvalidTimeControl()
+		}
+	}
+	if len(cmd.Note) > 500 {
+		errors["note"] = validation.TooLong(500)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// RejectAnnouncementCommand represents an organizer dismissing an announcement
+type RejectAnnouncementCommand struct {
+	ID   uuid.UUID `json:"id"`
+	Note string    `json:"note"` // optional, why it was rejected
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RejectAnnouncementCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if len(cmd.Note) > 500 {
+		errors["note"] = validation.TooLong(500)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+func validateTimezone(tz domain.Timezone, errors validation.Errors) {
+	if tz == "" {
+		return
+	}
+	if _, err := time.LoadLocation(string(tz)); err != nil {
+		errors["timezone"] = validation.InvalidFormat("Europe/Madrid")
+	}
+}
@@ -0,0 +1,18 @@
+// Package commands - Represents the user's intent to perform an action on the announcements read from chat exports
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// DefaultTimezone is where the chats are assumed to happen when the command does not say,
+// whatsapp exports carry no zone and the parsers are tuned for Spain
+const DefaultTimezone = "Europe/Madrid"
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
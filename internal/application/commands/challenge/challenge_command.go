@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// CreateChallengeCommand represents the intent of a player to post an open challenge
+type CreateChallengeCommand struct {
+	ChallengerID uuid.UUID `json:"challenger_id"`
+	// LocationName is optional, e.g. the club or the café the challenger plays at
+	LocationName string          `json:"location_name"`
+	Address      string          `json:"address"`
+	City         string          `json:"city"`
+	Country      string          `json:"country"`
+	Latitude     float64         `json:"latitude"`
+	Longitude    float64         `json:"longitude"`
+	Timezone     domain.Timezone `json:"timezone"`
+	// RadiusKm is optional, how far from the location the challenger is willing to play
+	RadiusKm    float64   `json:"radius_km"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	TimeControl string    `json:"time_control"`
+	Rated       bool      `json:"rated"`
+	// MinRating and MaxRating are optional, the range of the players that can accept it in
+	// the rating pool of the time control
+	MinRating int    `json:"min_rating"`
+	MaxRating int    `json:"max_rating"`
+	Note      string `json:"note"`
+	// ConsumerID is the authenticated api consumer posting the challenge for the player, never the request
+	ConsumerID string `json:"consumer_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CreateChallengeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ChallengerID == uuid.Nil {
+		errors["challenger_id"] = validation.NotNil()
+	}
+	if len(cmd.ConsumerID) > maxConsumerIDLength {
+		errors["consumer_id"] = validation.TooLong(maxConsumerIDLength)
+	}
+	if cmd.Latitude == 0 && cmd.Longitude == 0 {
+		// the distance to the other players is measured from them
+		errors["latitude"] = validation.Required()
+		errors["longitude"] = validation.Required()
+	} else {
+		validateCoordinates(cmd.Latitude, cmd.Longitude, errors)
+	}
+	if cmd.RadiusKm < 0 || cmd.RadiusKm > MaxRadiusKm {
+		errors["radius_km"] = validation.Between(0, MaxRadiusKm)
+	}
+	if len(cmd.LocationName) > maxLocationNameLength {
+		errors["location_name"] = validation.TooLong(maxLocationNameLength)
+	}
+	if cmd.StartsAt.IsZero() {
+		errors["starts_at"] = validation.Required()
+	}
+	if cmd.EndsAt.IsZero() {
+		errors["ends_at"] = validation.Required()
+	} else if !cmd.StartsAt.IsZero() && !cmd.EndsAt.After(cmd.StartsAt) {
+		errors["ends_at"] = validation.After("starts_at")
+	}
+	if strings.TrimSpace(cmd.TimeControl) == "" {
+		errors["time_control"] = validation.Required()
+	} else if _, err := domain.ParseTimeControl(cmd.TimeControl); err != nil {
+		errors["time_control"] = validation.InvalidTimeControl()
+	}
+	validateRatingRange(cmd.MinRating, cmd.MaxRating, errors)
+	if len(cmd.Note) > maxNoteLength {
+		errors["note"] = validation.TooLong(maxNoteLength)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindChallengeCommand represents the intent to see a challenge
+type FindChallengeCommand struct {
+	ID uuid.UUID `json:"id"` // public uuid
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindChallengeCommand) Validate() error {
+	if cmd.ID == uuid.Nil {
+		return ValidationError{Errors: validation.Errors{"id": validation.NotNil()}}
+	}
+
+	return nil
+}
+
+// ListChallengesCommand represents the intent of a player to browse the open challenges,
+// every field is optional
+type ListChallengesCommand struct {
+	// Latitude and Longitude are where the player is, only the challenges within RadiusKm of
+	// the player, plus the radius of the challenge, are listed
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	RadiusKm  float64  `json:"radius_km"` // DefaultSearchRadiusKm when 0
+	// PlayerID lists only the challenges the player can accept, by their rating
+	PlayerID uuid.UUID `json:"player_id"`
+	// MinRating and MaxRating narrow the challengers by their rating in the pool of the
+	// time control of the challenge
+	MinRating int                        `json:"min_rating"`
+	MaxRating int                        `json:"max_rating"`
+	Category  domain.TimeControlCategory `json:"category"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListChallengesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	switch {
+	case cmd.Latitude != nil && cmd.Longitude != nil:
+		validateCoordinates(*cmd.Latitude, *cmd.Longitude, errors)
+	case cmd.Latitude != nil:
+		errors["longitude"] = validation.Required()
+	case cmd.Longitude != nil:
+		errors["latitude"] = validation.Required()
+	}
+	if cmd.RadiusKm < 0 || cmd.RadiusKm > MaxRadiusKm {
+		errors["radius_km"] = validation.Between(0, MaxRadiusKm)
+	}
+	validateRatingRange(cmd.MinRating, cmd.MaxRating, errors)
+	if cmd.Category != "" && !cmd.Category.Valid() {
+		errors["category"] = validation.OneOf("bullet", "blitz", "rapid", "classical")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// AcceptChallengeCommand represents the intent of a player to accept a challenge
+type AcceptChallengeCommand struct {
+	ID       uuid.UUID `json:"id"` // public uuid
+	PlayerID uuid.UUID `json:"player_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd AcceptChallengeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// CancelChallengeCommand represents the intent of the challenger to withdraw a challenge
+type CancelChallengeCommand struct {
+	ID       uuid.UUID `json:"id"` // public uuid
+	PlayerID uuid.UUID `json:"player_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CancelChallengeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package commands - Represents the user's intent to perform an action on an open challenge
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
+const (
+	// MaxRadiusKm is how far from the location a challenger or a search can reach
+	MaxRadiusKm = 200
+	// DefaultSearchRadiusKm is how far from the coordinates a search looks when not told
+	DefaultSearchRadiusKm = 25
+	maxRating             = 3500
+	maxNoteLength         = 280
+	maxLocationNameLength = 120
+	maxConsumerIDLength   = 64
+)
+
+func validateCoordinates(latitude, longitude float64, errors validation.Errors) {
+	if latitude < -90 || latitude > 90 {
+		errors["latitude"] = validation.Between(-90, 90)
+	}
+	if longitude < -180 || longitude > 180 {
+		errors["longitude"] = validation.Between(-180, 180)
+	}
+}
+
+func validateRatingRange(min, max int, errors validation.Errors) {
+	if min < 0 || min > maxRating {
+		errors["min_rating"] = validation.Between(0, maxRating)
+	}
+	if max < 0 || max > maxRating {
+		errors["max_rating"] = validation.Between(0, maxRating)
+	} else if max > 0 && max < min {
+		errors["max_rating"] = validation.Between(min, maxRating)
+	}
+}
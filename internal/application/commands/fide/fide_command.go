@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// FindRecordCommand represents the intent to read a fide record, an empty period means the latest
+type FindRecordCommand struct {
+	FideID int    `json:"fide_id"`
+	Period string `json:"period"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindRecordCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.FideID <= 0 {
+		errors["fide_id"] = validation.Positive()
+	}
+	validatePeriod(cmd.Period, false, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// MatchCandidatesCommand represents the intent to find the fide records that could be a player
+type MatchCandidatesCommand struct {
+	PlayerID uuid.UUID `json:"player_id"`
+	Limit    int       `json:"limit"` // optional, defaults to 5
+}
+
+// Validate is where we handle the validation of the command
+func (cmd MatchCandidatesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if cmd.Limit < 0 || cmd.Limit > 50 {
+		errors["limit"] = validation.Between(0, 50)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ConfirmMatchCommand represents the confirmation that a player and a fide id are the same person
+type ConfirmMatchCommand struct {
+	PlayerID uuid.UUID `json:"player_id"`
+	FideID   int       `json:"fide_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ConfirmMatchCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if cmd.FideID <= 0 {
+		errors["fide_id"] = validation.Positive()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// RefreshTournamentRatingsCommand represents the intent to set the entrants' fide ratings
+// to the ones of a rating period before pairing, an empty period means the latest
+type RefreshTournamentRatingsCommand struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Period       string    `json:"period"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RefreshTournamentRatingsCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.TournamentID == uuid.Nil {
+		errors["tournament_id"] = validation.NotNil()
+	}
+	validatePeriod(cmd.Period, false, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// periodRX matches a rating period such as 2026-10
+var periodRX = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
+// ImportRatingListCommand represents the intent to import an official fide rating list file
+type ImportRatingListCommand struct {
+	Path   string                `json:"path"` // relative to the import directory
+	Format domain.FideListFormat `json:"format"`
+	Period string                `json:"period"` // e.g. 2026-10
+	// RatingType is only used for lists that carry a single rating column, such
+	// as the standard only txt list. Combined lists carry all three ratings
+	RatingType domain.RatingType `json:"rating_type"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ImportRatingListCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if strings.TrimSpace(cmd.Path) == "" {
+		errors["path"] = validation.Required()
+	} else if !filepath.IsLocal(cmd.Path) {
+		errors["path"] = validation.LocalPath()
+	}
+	if cmd.Format != domain.FideListFormatXML && cmd.Format != domain.FideListFormatTXT {
+		errors["format"] = validation.OneOf("xml", "txt")
+	}
+	validatePeriod(cmd.Period, true, errors)
+	if cmd.RatingType != "" && !cmd.RatingType.Valid() {
+		errors["rating_type"] = validation.OneOf("standard", "rapid", "blitz")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindImportCommand represents the intent to read how an import of a rating list is going
+type FindImportCommand struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindImportCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+func validatePeriod(period string, required bool, errors validation.Errors) {
+	if period == "" {
+		if required {
+			errors["period"] = validation.Required()
+		}
+		return
+	}
+	if !periodRX.MatchString(period) {
+		errors["period"] = validation.InvalidFormat("YYYY-MM")
+	}
+}
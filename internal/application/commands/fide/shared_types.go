@@ -0,0 +1,14 @@
+// Package commands - Represents the user's intent to perform an action on the fide rating lists
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
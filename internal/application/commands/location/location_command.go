@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// CreateLocationCommand represents the intent to register a venue
+type CreateLocationCommand struct {
+	LocationDetails
+	// Force registers the venue even when a similar one is already registered
+	Force bool `json:"force"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CreateLocationCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	cmd.validate(errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// UpdateLocationCommand represents the intent to replace the details of a registered venue
+type UpdateLocationCommand struct {
+	ID uuid.UUID `json:"id"`
+	LocationDetails
+}
+
+// Validate is where we handle the validation of the command
+func (cmd UpdateLocationCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	cmd.validate(errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindLocationCommand represents the intent to read a registered venue
+type FindLocationCommand struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindLocationCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ListLocationsCommand represents the intent to search the registry, every field is optional
+type ListLocationsCommand struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+	Query   string `json:"q"` // part of the name or the address
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListLocationsCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if len(cmd.Query) > maxNameLength {
+		errors["q"] = validation.TooLong(maxNameLength)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// DeleteLocationCommand represents the intent to remove a venue nothing references
+type DeleteLocationCommand struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd DeleteLocationCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// MergeLocationsCommand represents the intent to fold the duplicate ID into IntoID
+type MergeLocationsCommand struct {
+	ID     uuid.UUID `json:"id"` // the duplicate, it is removed
+	IntoID uuid.UUID `json:"into"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd MergeLocationsCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if cmd.IntoID == uuid.Nil {
+		errors["into"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// GeocodeCommand represents the intent to resolve an address, at least the address, the
+// postal code or the city is needed
+type GeocodeCommand struct {
+	Address    string `json:"address"`
+	PostalCode string `json:"postal_code"`
+	City       string `json:"city"`
+	Province   string `json:"province"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2 code, e.g. ES
+}
+
+// Validate is where we handle the validation of the command
+func (cmd GeocodeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ToQuery().Empty() {
+		errors["address"] = validation.Required()
+	}
+	if len(cmd.Address) > maxAddressLength {
+		errors["address"] = validation.TooLong(maxAddressLength)
+	}
+	for field, value := range map[string]string{"postal_code": cmd.PostalCode, "city": cmd.City, "province": cmd.Province} {
+		if len(value) > maxPlaceLength {
+			errors[field] = validation.TooLong(maxPlaceLength)
+		}
+	}
+	if cmd.Country != "" && len(cmd.Country) != 2 {
+		errors["country"] = validation.InvalidFormat("ES")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+func (cmd GeocodeCommand) ToQuery() domain.GeocodeQuery {
+	return domain.GeocodeQuery{
+		Address:    strings.TrimSpace(cmd.Address),
+		PostalCode: strings.TrimSpace(cmd.PostalCode),
+		City:       strings.TrimSpace(cmd.City),
+		Province:   strings.TrimSpace(cmd.Province),
+		Country:    strings.ToUpper(strings.TrimSpace(cmd.Country)),
+	}
+}
+
+// ReverseGeocodeCommand represents the intent to name the place at the coordinates
+type ReverseGeocodeCommand struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ReverseGeocodeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Latitude < -90 || cmd.Latitude > 90 {
+		errors["latitude"] = validation.Between(-90, 90)
+	}
+	if cmd.Longitude < -180 || cmd.Longitude > 180 {
+		errors["longitude"] = validation.Between(-180, 180)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
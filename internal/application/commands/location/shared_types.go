@@ -0,0 +1,88 @@
+// Package commands - Represents the user's intent to perform an action on the registry of venues
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
+const (
+	maxNameLength    = 120
+	maxAddressLength = 200
+	maxPlaceLength   = 80 // city, state, county, province and country
+)
+
+// LocationDetails are the fields of a venue shared by its creation and its updates
+type LocationDetails struct {
+	Name       string  `json:"name"`
+	Address    string  `json:"address"`
+	PostalCode string  `json:"postal_code"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	County     string  `json:"county"`
+	Province   string  `json:"province"`
+	Country    string  `json:"country"`
+	Latitude   float64 `json:"latitude"` // optional, 0,0 is no position
+	Longitude  float64 `json:"longitude"`
+	// Timezone is optional, the IANA zone of the venue
+	Timezone domain.Timezone `json:"timezone"`
+}
+
+// ToLocation is the location the details describe, outside the registry
+func (d LocationDetails) ToLocation() domain.Location {
+	return domain.Location{
+		Name:       strings.TrimSpace(d.Name),
+		Address:    strings.TrimSpace(d.Address),
+		PostalCode: strings.TrimSpace(d.PostalCode),
+		City:       strings.TrimSpace(d.City),
+		State:      strings.TrimSpace(d.State),
+		County:     strings.TrimSpace(d.County),
+		Province:   strings.TrimSpace(d.Province),
+		Country:    strings.TrimSpace(d.Country),
+		Latitude:   d.Latitude,
+		Longitude:  d.Longitude,
+		Timezone:   d.Timezone,
+	}
+}
+
+func (d LocationDetails) validate(errors validation.Errors) {
+	if strings.TrimSpace(d.Name) == "" {
+		errors["name"] = validation.Required()
+	} else if len(d.Name) > maxNameLength {
+		errors["name"] = validation.TooLong(maxNameLength)
+	}
+	if strings.TrimSpace(d.City) == "" {
+		// duplicates are looked for among the venues of the city
+		errors["city"] = validation.Required()
+	}
+	if len(d.Address) > maxAddressLength {
+		errors["address"] = validation.TooLong(maxAddressLength)
+	}
+	for field, value := range map[string]string{"city": d.City, "state": d.State, "county": d.County, "province": d.Province, "country": d.Country} {
+		if len(value) > maxPlaceLength {
+			errors[field] = validation.TooLong(maxPlaceLength)
+		}
+	}
+	if d.Latitude < -90 || d.Latitude > 90 {
+		errors["latitude"] = validation.Between(-90, 90)
+	}
+	if d.Longitude < -180 || d.Longitude > 180 {
+		errors["longitude"] = validation.Between(-180, 180)
+	}
+	if d.Timezone != "" {
+		if _, err := time.LoadLocation(string(d.Timezone)); err != nil {
+			errors["timezone"] = validation.InvalidFormat("Europe/Madrid")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// CreateMatchCommand represents the user's intent to create a match between two players
+type CreateMatchCommand struct {
+	TournamentID uuid.UUID         `json:"tournament_id"` // optional, casual games have none
+	WhitePlayer  uuid.UUID         `json:"white_player"`
+	BlackPlayer  uuid.UUID         `json:"black_player"`
+	Rated        bool              `json:"rated"`
+	RatingType   domain.RatingType `json:"rating_type"` // optional, defaults to the pool of the time control category
+	// TimeControl is required for casual games, tournament games default to the tournament's
+	TimeControl string `json:"time_control"`
+	// LocationID is optional, the registered venue, tournament games default to the tournament's
+	LocationID uuid.UUID `json:"location_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CreateMatchCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.WhitePlayer == uuid.Nil {
+		errors["white_player"] = validation.Required()
+	}
+	if cmd.BlackPlayer == uuid.Nil {
+		errors["black_player"] = validation.Required()
+	}
+	if cmd.WhitePlayer != uuid.Nil && cmd.WhitePlayer == cmd.BlackPlayer {
+		errors["black_player"] = validation.DifferentPlayer()
+	}
+	validateTimeControl(cmd.TimeControl, cmd.TournamentID == uuid.Nil, errors)
+	if cmd.RatingType != "" && !cmd.RatingType.Valid() {
+		errors["rating_type"] = validation.OneOf("standard", "rapid", "blitz")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
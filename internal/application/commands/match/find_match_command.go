@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// FindMatchCommand represents the user's intent to find a match
+type FindMatchCommand struct {
+	ID uuid.UUID `json:"id"` // public uuid
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindMatchCommand) Validate() error {
+	if cmd.ID == uuid.Nil {
+		return ValidationError{Errors: validation.Errors{"id": validation.NotNil()}}
+	}
+
+	return nil
+}
+
+// ListMatchesCommand represents the user's intent to search the matches, every field is optional
+type ListMatchesCommand struct {
+	PlayerID    uuid.UUID                  `json:"player_id"`
+	Category    domain.TimeControlCategory `json:"category"`
+	TimeControl string                     `json:"time_control"` // only matches played at exactly this time control
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListMatchesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Category != "" && !cmd.Category.Valid() {
+		errors["category"] = validation.OneOf("bullet", "blitz", "rapid", "classical")
+	}
+	validateTimeControl(cmd.TimeControl, false, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// RecordResultCommand represents the intent to record (or correct) the final result of a match
+type RecordResultCommand struct {
+	ID     uuid.UUID          `json:"id"` // public uuid
+	Result domain.MatchResult `json:"result"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RecordResultCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if !cmd.Result.Finished() {
+		errors["result"] = validation.OneOf("1-0", "0-1", "1/2-1/2")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+// Package commands - Represents the user's intent to perform an action on a match
+package commands
+
+import (
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
+// validateTimeControl checks the notation can be parsed by domain.ParseTimeControl
+func validateTimeControl(notation string, required bool, errors validation.Errors) {
+	if strings.TrimSpace(notation) == "" {
+		if required {
+			errors["time_control"] = validation.Required()
+		}
+		return
+	}
+	if _, err := domain.ParseTimeControl(notation); err != nil {
+		errors["time_control"] = validation.InvalidTimeControl()
+	}
+}
@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ReportCommand represents someone flagging a tournament, match, player or challenge
+type ReportCommand struct {
+	Subject    domain.ModerationSubject `json:"subject"`
+	ReporterID string                   `json:"reporter_id"` // optional, reports without one are never duplicates
+	Reason     domain.ReportReason      `json:"reason"`
+	Details    string                   `json:"details"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ReportCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateSubject(cmd.Subject, errors)
+	if len(cmd.ReporterID) > maxConsumerIDLength {
+		errors["reporter_id"] = validation.TooLong(maxConsumerIDLength)
+	}
+	if !cmd.Reason.Valid() {
+		errors["reason"] = validation.OneOf("spam", "offensive", "incorrect", "duplicate", "other")
+	}
+	if cmd.Reason == domain.ReportOther && strings.TrimSpace(cmd.Details) == "" {
+		errors["details"] = validation.Required()
+	}
+	if len(cmd.Details) > maxDetailsLength {
+		errors["details"] = validation.TooLong(maxDetailsLength)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ModerationQueueCommand represents a moderator reading what waits for them, every type by default
+type ModerationQueueCommand struct {
+	Type domain.SubjectType `json:"type"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ModerationQueueCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Type != "" && !cmd.Type.Valid() {
+		errors["type"] = validation.OneOf("tournament", "match", "player", "challenge")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ModerateCommand represents a moderator approving, rejecting or hiding a subject
+type ModerateCommand struct {
+	Subject   domain.ModerationSubject `json:"subject"`
+	Action    domain.ModerationAction  `json:"action"`
+	Moderator string                   `json:"moderator"`
+	Note      string                   `json:"note"` // optional
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ModerateCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateSubject(cmd.Subject, errors)
+	if !cmd.Action.Valid() {
+		errors["action"] = validation.OneOf("approve", "reject", "hide")
+	}
+	if strings.TrimSpace(cmd.Moderator) == "" {
+		errors["moderator"] = validation.Required()
+	} else if len(cmd.Moderator) > maxConsumerIDLength {
+		errors["moderator"] = validation.TooLong(maxConsumerIDLength)
+	}
+	if len(cmd.Note) > maxNoteLength {
+		errors["note"] = validation.TooLong(maxNoteLength)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ModerationHistoryCommand represents the intent to read the reports and decisions of a subject
+type ModerationHistoryCommand struct {
+	Subject domain.ModerationSubject `json:"subject"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ModerationHistoryCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateSubject(cmd.Subject, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindStandingCommand represents the intent to read how the listings of a consumer were moderated
+type FindStandingCommand struct {
+	ConsumerID string `json:"consumer_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindStandingCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateConsumerID(cmd.ConsumerID, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ReinstateConsumerCommand represents a moderator lifting the suspension of a consumer
+type ReinstateConsumerCommand struct {
+	ConsumerID string `json:"consumer_id"`
+	Moderator  string `json:"moderator"`
+	Note       string `json:"note"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ReinstateConsumerCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateConsumerID(cmd.ConsumerID, errors)
+	if strings.TrimSpace(cmd.Moderator) == "" {
+		errors["moderator"] = validation.Required()
+	}
+	if len(cmd.Note) > maxNoteLength {
+		errors["note"] = validation.TooLong(maxNoteLength)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
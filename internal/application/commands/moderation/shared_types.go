@@ -0,0 +1,39 @@
+// Package commands - Represents the user's intent to perform an action on the moderation of the listings
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
+const (
+	maxDetailsLength    = 1000
+	maxNoteLength       = 500
+	maxConsumerIDLength = 64
+)
+
+func validateSubject(subject domain.ModerationSubject, errors validation.Errors) {
+	if !subject.Type.Valid() {
+		errors["type"] = validation.OneOf("tournament", "match", "player", "challenge")
+	}
+	if subject.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+}
+
+func validateConsumerID(consumerID string, errors validation.Errors) {
+	if consumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	} else if len(consumerID) > maxConsumerIDLength {
+		errors["consumer_id"] = validation.TooLong(maxConsumerIDLength)
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+)
+
+// FindPreferencesCommand represents the intent to read the notification preferences of a player
+type FindPreferencesCommand struct {
+	PlayerID uuid.UUID `json:"player_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindPreferencesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// UpdatePreferencesCommand represents the intent of a player to choose what they are told about,
+// it replaces the preferences
+type UpdatePreferencesCommand struct {
+	PlayerID     uuid.UUID                 `json:"player_id"`
+	Locale       i18n.Locale               `json:"locale"` // optional, the default locale when empty
+	Muted        []domain.NotificationKind `json:"muted"`
+	Unsubscribed bool                      `json:"unsubscribed"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd UpdatePreferencesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+
+	if cmd.Locale != "" && !i18n.Default().Supports(cmd.Locale) {
+		locales := make([]string, 0, len(i18n.Default().Locales()))
+		for _, l := range i18n.Default().Locales() {
+			locales = append(locales, string(l))
+		}
+		errors["locale"] = validation.OneOf(locales...)
+	}
+
+	kinds := make([]string, len(domain.NotificationKinds))
+	for i, k := range domain.NotificationKinds {
+		kinds[i] = string(k)
+	}
+	for i, k := range cmd.Muted {
+		if !k.Valid() {
+			errors["muted."+strconv.Itoa(i)] = validation.OneOf(kinds...)
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// UnsubscribeCommand represents a player following the unsubscribe link of an email
+type UnsubscribeCommand struct {
+	Token string `json:"token"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd UnsubscribeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Token == "" {
+		errors["token"] = validation.Required()
+	} else if len(cmd.Token) > 128 {
+		errors["token"] = validation.TooLong(128)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// CreatePlayerCommand represents the user's intent to create a player profile
+type CreatePlayerCommand struct {
+	Username      string  `json:"username"`
+	Email         string  `json:"email"`
+	FirstName     string  `json:"first_name"`
+	LastName      string  `json:"last_name"`
+	Website       string  `json:"website"`        // optional
+	FideTitle     string  `json:"fide_title"`     // optional
+	FideRatings   Ratings `json:"fide_ratings"`   // optional
+	RegionalTitle string  `json:"regional_title"` // optional
+	Country       string  `json:"country"`        // optional
+	City          string  `json:"city"`           // optional
+	Ratings       Ratings `json:"ratings"`        // optional, regional ratings
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CreatePlayerCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	validateUsername(cmd.Username, errors)
+
+	if strings.TrimSpace(cmd.Email) == "" {
+		errors["email"] = validation.Required()
+	}
+	if strings.TrimSpace(cmd.FirstName) == "" {
+		errors["first_name"] = validation.Required()
+	}
+	if strings.TrimSpace(cmd.LastName) == "" {
+		errors["last_name"] = validation.Required()
+	}
+
+	validateRatings("fide_ratings", cmd.FideRatings, errors)
+	validateRatings("ratings", cmd.Ratings, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+func validateUsername(username string, errors validation.Errors) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		errors["username"] = validation.Required()
+	} else if len(username) < 3 {
+		errors["username"] = validation.TooShort(3)
+	} else if len(username) > 50 {
+		errors["username"] = validation.TooLong(50)
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// FindPlayerCommand represents the user's intent to find a player's public profile
+type FindPlayerCommand struct {
+	ID uuid.UUID `json:"id"` // public uuid
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindPlayerCommand) Validate() error {
+	if cmd.ID == uuid.Nil {
+		return ValidationError{Errors: validation.Errors{"id": validation.NotNil()}}
+	}
+
+	return nil
+}
+
+// HeadToHeadCommand represents the user's intent to see the record between two players
+type HeadToHeadCommand struct {
+	PlayerID   uuid.UUID `json:"player_id"`
+	OpponentID uuid.UUID `json:"opponent_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd HeadToHeadCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if cmd.OpponentID == uuid.Nil {
+		errors["opponent_id"] = validation.NotNil()
+	}
+	if cmd.PlayerID != uuid.Nil && cmd.PlayerID == cmd.OpponentID {
+		errors["opponent_id"] = validation.DifferentPlayer()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// RecordRatingChangeCommand represents the intent to add a point to a player's rating history,
+// the player's current rating for the source and type is updated too. The maple ratings are only
+// computed from the results, never recorded
+type RecordRatingChangeCommand struct {
+	PlayerID   uuid.UUID           `json:"player_id"`
+	Source     domain.RatingSource `json:"source"`
+	Type       domain.RatingType   `json:"type"`
+	EventID    uuid.UUID           `json:"event_id"`   // optional, tournament or match
+	EventName  string              `json:"event_name"` // optional
+	Rating     int                 `json:"rating"`
+	RecordedAt time.Time           `json:"recorded_at"` // optional, defaults to now
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RecordRatingChangeCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if !cmd.Source.Valid() || cmd.Source == domain.RatingSourceMaple {
+		errors["source"] = validation.OneOf("fide", "regional")
+	}
+	if !cmd.Type.Valid() {
+		errors["type"] = validation.OneOf("standard", "rapid", "blitz")
+	}
+	if cmd.Rating < 0 || cmd.Rating > 3500 {
+		errors["rating"] = validation.Between(0, 3500)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// RatingHistoryCommand represents the intent to read a player's rating time series,
+// empty Source or Type means all of them
+type RatingHistoryCommand struct {
+	PlayerID uuid.UUID           `json:"player_id"`
+	Source   domain.RatingSource `json:"source"`
+	Type     domain.RatingType   `json:"type"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RatingHistoryCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if cmd.Source != "" && !cmd.Source.Valid() {
+		errors["source"] = validation.OneOf("fide", "regional", "maple")
+	}
+	if cmd.Type != "" && !cmd.Type.Valid() {
+		errors["type"] = validation.OneOf("standard", "rapid", "blitz")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
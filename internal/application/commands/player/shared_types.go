@@ -0,0 +1,35 @@
+// Package commands - Represents the user's intent to perform an action on a player
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
+// Ratings represents the numeric ratings of a player per rating type
+type Ratings struct {
+	Standard int `json:"standard"`
+	Rapid    int `json:"rapid"`
+	Blitz    int `json:"blitz"`
+}
+
+// validateRatings checks that every rating is within a sane range
+func validateRatings(prefix string, r Ratings, errors validation.Errors) {
+	ratings := map[string]int{
+		"standard": r.Standard,
+		"rapid":    r.Rapid,
+		"blitz":    r.Blitz,
+	}
+	for name, rating := range ratings {
+		if rating < 0 || rating > 3500 {
+			errors[prefix+"."+name] = validation.Between(0, 3500)
+		}
+	}
+}
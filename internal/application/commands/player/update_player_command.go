@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// UpdatePlayerCommand represents the user's intent to update a player profile,
+// nil fields are left untouched
+type UpdatePlayerCommand struct {
+	ID            uuid.UUID `json:"id"`
+	Username      *string   `json:"username"`
+	Email         *string   `json:"email"`
+	FirstName     *string   `json:"first_name"`
+	LastName      *string   `json:"last_name"`
+	Website       *string   `json:"website"`
+	FideTitle     *string   `json:"fide_title"`
+	RegionalTitle *string   `json:"regional_title"`
+	Country       *string   `json:"country"`
+	City          *string   `json:"city"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd UpdatePlayerCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+	if cmd.Username != nil {
+		validateUsername(*cmd.Username, errors)
+	}
+	if cmd.Email != nil && strings.TrimSpace(*cmd.Email) == "" {
+		errors["email"] = validation.NotEmpty()
+	}
+	if cmd.FirstName != nil && strings.TrimSpace(*cmd.FirstName) == "" {
+		errors["first_name"] = validation.NotEmpty()
+	}
+	if cmd.LastName != nil && strings.TrimSpace(*cmd.LastName) == "" {
+		errors["last_name"] = validation.NotEmpty()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// FindRatingCommand represents the intent to read a player's maple rating in a pool
+type FindRatingCommand struct {
+	PlayerID uuid.UUID         `json:"player_id"`
+	Pool     domain.RatingType `json:"pool"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindRatingCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.NotNil()
+	}
+	if !cmd.Pool.Valid() {
+		errors["pool"] = validation.OneOf("standard", "rapid", "blitz")
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ListRatingsCommand represents the intent to read the leaderboard of a pool
+type ListRatingsCommand struct {
+	Pool               domain.RatingType `json:"pool"`
+	IncludeProvisional bool              `json:"include_provisional"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListRatingsCommand) Validate() error {
+	if !cmd.Pool.Valid() {
+		return ValidationError{Errors: validation.Errors{"pool": validation.OneOf("standard", "rapid", "blitz")}}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/chess"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// StartRelayCommand represents the intent to relay a match move by move
+type StartRelayCommand struct {
+	MatchID uuid.UUID `json:"match_id"`
+	FEN     string    `json:"fen"` // optional, the standard initial position when empty
+}
+
+// Validate is where we handle the validation of the command
+func (cmd StartRelayCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.MatchID == uuid.Nil {
+		errors["match_id"] = validation.NotNil()
+	}
+	if cmd.FEN != "" {
+		if _, err := chess.ParseFEN(cmd.FEN); err != nil {
+			errors["fen"] = validation.InvalidFormat("FEN")
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindRelayCommand represents the intent to see the relay of a match
+type FindRelayCommand struct {
+	MatchID uuid.UUID `json:"match_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindRelayCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.MatchID == uuid.Nil {
+		errors["match_id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// RecordMoveCommand represents the intent of an arbiter or a board to relay a move
+type RecordMoveCommand struct {
+	MatchID uuid.UUID `json:"match_id"`
+	Move    string    `json:"move"` // standard algebraic or uci notation
+	// Ply is optional, the number of the move in the game (1 based). A board sending a move
+	// again gets the relay back unchanged and a move that skips one is refused
+	Ply int `json:"ply"`
+	// Clock is optional, the time the clock shows for the player after the move. The server
+	// works it out from the time control when the board does not have a clock
+	Clock *time.Duration `json:"clock"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RecordMoveCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.MatchID == uuid.Nil {
+		errors["match_id"] = validation.NotNil()
+	}
+	if cmd.Move == "" {
+		errors["move"] = validation.Required()
+	} else if len(cmd.Move) > 16 {
+		errors["move"] = validation.TooLong(16)
+	}
+	if cmd.Ply < 0 {
+		errors["ply"] = validation.Positive()
+	}
+	if cmd.Clock != nil && *cmd.Clock < 0 {
+		errors["clock"] = validation.Positive()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FinishRelayCommand represents the intent of an arbiter to end a relayed game that did not
+// end on the board, e.g. a resignation
+type FinishRelayCommand struct {
+	MatchID     uuid.UUID               `json:"match_id"`
+	Result      domain.MatchResult      `json:"result"`
+	Termination domain.RelayTermination `json:"termination"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FinishRelayCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.MatchID == uuid.Nil {
+		errors["match_id"] = validation.NotNil()
+	}
+	if !cmd.Result.Finished() {
+		errors["result"] = validation.OneOf("1-0", "0-1", "1/2-1/2")
+	}
+
+	terminations := make([]string, len(domain.RelayReportedTerminations))
+	valid := false
+	for i, t := range domain.RelayReportedTerminations {
+		terminations[i] = string(t)
+		valid = valid || t == cmd.Termination
+	}
+	if !valid {
+		errors["termination"] = validation.OneOf(terminations...)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
 )
 
 func TestCreateTournamentCommand_Validate(t *testing.T) {
@@ -17,6 +19,7 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			name: "valid tournament with all required fields",
 			cmd: CreateTournamentCommand{
 				Name:        "Test Tournament",
+				TimeControl: "90+30",
 				Description: "A test tournament",
 			},
 			wantErr:      false,
@@ -25,7 +28,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "valid tournament with minimal fields",
 			cmd: CreateTournamentCommand{
-				Name: "Min",
+				Name:        "Min",
+				TimeControl: "90+30",
 			},
 			wantErr:      false,
 			expectedErrs: nil,
@@ -33,7 +37,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "valid tournament with maximum name length",
 			cmd: CreateTournamentCommand{
-				Name: "A very long tournament name that is exactly one hundred characters long for testing purposes!!",
+				Name:        "A very long tournament name that is exactly one hundred characters long for testing purposes!!",
+				TimeControl: "90+30",
 			},
 			wantErr:      false,
 			expectedErrs: nil,
@@ -42,6 +47,7 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			name: "valid tournament with maximum description length",
 			cmd: CreateTournamentCommand{
 				Name:        "Test Tournament",
+				TimeControl: "90+30",
 				Description: "A" + string(make([]byte, 499)), // 500 characters total
 			},
 			wantErr:      false,
@@ -50,7 +56,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "empty name",
 			cmd: CreateTournamentCommand{
-				Name: "",
+				Name:        "",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -60,7 +67,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "whitespace only name",
 			cmd: CreateTournamentCommand{
-				Name: "   ",
+				Name:        "   ",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -70,7 +78,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "name too short",
 			cmd: CreateTournamentCommand{
-				Name: "AB",
+				Name:        "AB",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -80,7 +89,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "name too short with whitespace",
 			cmd: CreateTournamentCommand{
-				Name: "  A ",
+				Name:        "  A ",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -90,7 +100,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "name too long",
 			cmd: CreateTournamentCommand{
-				Name: "A very long tournament name that definitely exceeds one hundred characters and should trigger validation error here",
+				Name:        "A very long tournament name that definitely exceeds one hundred characters and should trigger validation error here",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -100,7 +111,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "name exactly 101 characters",
 			cmd: CreateTournamentCommand{
-				Name: "A very long tournament name that is exactly one hundred and one characters long for testing purposes!",
+				Name:        "A very long tournament name that is exactly one hundred and one characters long for testing purposes!",
+				TimeControl: "90+30",
 			},
 			wantErr: true,
 			expectedErrs: map[string]string{
@@ -111,6 +123,7 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			name: "description too long",
 			cmd: CreateTournamentCommand{
 				Name:        "Valid Tournament",
+				TimeControl: "90+30",
 				Description: "A" + string(make([]byte, 500)), // 501 characters total
 			},
 			wantErr: true,
@@ -122,6 +135,7 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			name: "multiple validation errors",
 			cmd: CreateTournamentCommand{
 				Name:        "AB",
+				TimeControl: "90+30",
 				Description: "A" + string(make([]byte, 500)), // 501 characters total
 			},
 			wantErr: true,
@@ -130,11 +144,42 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 				"description": "must be less than 500 characters",
 			},
 		},
+		{
+			name: "missing time control",
+			cmd: CreateTournamentCommand{
+				Name: "Test Tournament",
+			},
+			wantErr: true,
+			expectedErrs: map[string]string{
+				"time_control": "is required",
+			},
+		},
+		{
+			name: "invalid time control",
+			cmd: CreateTournamentCommand{
+				Name:        "Test Tournament",
+				TimeControl: "ninety plus thirty",
+			},
+			wantErr: true,
+			expectedErrs: map[string]string{
+				"time_control": "must be a time control such as 90+30 or 90/40+30, 30+30",
+			},
+		},
+		{
+			name: "valid tournament with multi period time control",
+			cmd: CreateTournamentCommand{
+				Name:        "Test Tournament",
+				TimeControl: "90/40+30, 30+30",
+			},
+			wantErr:      false,
+			expectedErrs: nil,
+		},
 		{
 			name: "valid tournament with empty schedule",
 			cmd: CreateTournamentCommand{
-				Name:     "Test Tournament",
-				Schedule: []Schedule{},
+				Name:        "Test Tournament",
+				TimeControl: "90+30",
+				Schedule:    []Schedule{},
 			},
 			wantErr:      false,
 			expectedErrs: nil,
@@ -142,7 +187,8 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 		{
 			name: "valid tournament with schedule",
 			cmd: CreateTournamentCommand{
-				Name: "Test Tournament",
+				Name:        "Test Tournament",
+				TimeControl: "90+30",
 				Schedule: []Schedule{
 					{
 						StartTime: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
@@ -157,9 +203,10 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			name: "valid tournament with all optional fields",
 			cmd: CreateTournamentCommand{
 				Name:               "Complete Tournament",
+				TimeControl:        "90+30",
 				Description:        "A complete tournament with all fields",
 				AdditionalInfo:     "Some additional info",
-				LocationID:         "loc123",
+				LocationID:         "0f8fad5b-d9cb-469f-a165-70867728950e",
 				MaxPlayers:         100,
 				OpenToPublic:       true,
 				OpenToRegistration: true,
@@ -194,6 +241,41 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 			wantErr:      false,
 			expectedErrs: nil,
 		},
+		{
+			name: "translated descriptions",
+			cmd: CreateTournamentCommand{
+				Name:         "Test Tournament",
+				TimeControl:  "90+30",
+				Description:  "A test tournament",
+				Descriptions: map[string]string{"ca": "Un torneig de prova", "es": "Un torneo de prueba"},
+			},
+			wantErr:      false,
+			expectedErrs: nil,
+		},
+		{
+			name: "description in an unsupported locale",
+			cmd: CreateTournamentCommand{
+				Name:         "Test Tournament",
+				TimeControl:  "90+30",
+				Descriptions: map[string]string{"fr": "Un tournoi"},
+			},
+			wantErr: true,
+			expectedErrs: map[string]string{
+				"descriptions.fr": "must be one of en, ca or es",
+			},
+		},
+		{
+			name: "translated description too long",
+			cmd: CreateTournamentCommand{
+				Name:         "Test Tournament",
+				TimeControl:  "90+30",
+				Descriptions: map[string]string{"ca": string(make([]byte, 501))},
+			},
+			wantErr: true,
+			expectedErrs: map[string]string{
+				"descriptions.ca": "must be less than 500 characters",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -219,7 +301,7 @@ func TestCreateTournamentCommand_Validate(t *testing.T) {
 				for field, expectedMsg := range tt.expectedErrs {
 					if actualMsg, exists := ve.Errors[field]; !exists {
 						t.Errorf("CreateTournamentCommand.Validate() missing error for field '%s'", field)
-					} else if actualMsg != expectedMsg {
+					} else if actualMsg.String() != expectedMsg {
 						t.Errorf("CreateTournamentCommand.Validate() for field '%s' = '%s', want '%s'", field, actualMsg, expectedMsg)
 					}
 				}
@@ -240,14 +322,14 @@ func TestValidationError_Error(t *testing.T) {
 	}{
 		{
 			name:     "empty errors",
-			ve:       ValidationError{Errors: map[string]string{}},
+			ve:       ValidationError{Errors: validation.Errors{}},
 			expected: "validation failed",
 		},
 		{
 			name: "single error",
 			ve: ValidationError{
-				Errors: map[string]string{
-					"name": "is required",
+				Errors: validation.Errors{
+					"name": validation.Required(),
 				},
 			},
 			expected: "validation failed: name: is required",
@@ -255,9 +337,9 @@ func TestValidationError_Error(t *testing.T) {
 		{
 			name: "multiple errors",
 			ve: ValidationError{
-				Errors: map[string]string{
-					"name":        "is required",
-					"description": "too long",
+				Errors: validation.Errors{
+					"name":        validation.Required(),
+					"description": validation.TooLong(500),
 				},
 			},
 			// Note: map iteration order is not guaranteed, so we need to check both possibilities
@@ -271,7 +353,7 @@ func TestValidationError_Error(t *testing.T) {
 
 			if tt.name == "multiple errors" {
 				// For multiple errors, just check that it contains both expected parts
-				if !contains(result, "name: is required") || !contains(result, "description: too long") {
+				if !contains(result, "name: is required") || !contains(result, "description: must be less than 500 characters") {
 					t.Errorf("ValidationError.Error() = '%s', should contain both error messages", result)
 				}
 				if !contains(result, "validation failed:") {
@@ -296,8 +378,8 @@ func TestIsValidationError(t *testing.T) {
 		{
 			name: "is validation error",
 			err: ValidationError{
-				Errors: map[string]string{
-					"name": "is required",
+				Errors: validation.Errors{
+					"name": validation.Required(),
 				},
 			},
 			expectVE: true,
@@ -340,7 +422,7 @@ func TestIsValidationError(t *testing.T) {
 				for field, expectedMsg := range tt.expectedErrs {
 					if actualMsg, exists := ve.Errors[field]; !exists {
 						t.Errorf("IsValidationError() missing error for field '%s'", field)
-					} else if actualMsg != expectedMsg {
+					} else if actualMsg.String() != expectedMsg {
 						t.Errorf("IsValidationError() for field '%s' = '%s', want '%s'", field, actualMsg, expectedMsg)
 					}
 				}
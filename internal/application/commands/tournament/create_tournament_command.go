@@ -4,21 +4,28 @@ package commands
 import (
 	"strings"
 	"time"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/google/uuid"
 )
 
 // CreateTournamentCommand represents the user's intent to create a tournament
 // This represents all fields that are accepted by the API when creating a tournament
 type CreateTournamentCommand struct {
-	Name               string       `json:"name"`        //`json:"name" validate:"required,gte=3,lte=100"` look into this?
-	Description        string       `json:"description"` // optional
-	Schedule           []Schedule   `json:"schedule,omitempty"`
-	AdditionalInfo     string       `json:"additional_info"`      // optional TODO: add this to the DTO
-	LocationID         string       `json:"location_id"`          // need to revisit
-	MaxPlayers         int          `json:"max_players"`          // optional when creating
-	Contact            Contact      `json:"contact"`              // optional
-	OpenToPublic       bool         `json:"open_to_public"`       // optional
-	OpenToRegistration bool         `json:"open_to_registration"` // optional
-	Registration       Registration `json:"registration"`         // optional
+	Name               string            `json:"name"`         //`json:"name" validate:"required,gte=3,lte=100"` look into this?
+	Description        string            `json:"description"`  // optional
+	Descriptions       map[string]string `json:"descriptions"` // optional, translations of the description by locale e.g. "ca"
+	Schedule           []Schedule        `json:"schedule,omitempty"`
+	TimeControl        string            `json:"time_control"`         // e.g. "90+30", see domain.ParseTimeControl
+	AdditionalInfo     string            `json:"additional_info"`      // optional TODO: add this to the DTO
+	LocationID         string            `json:"location_id"`          // optional, public uuid of a registered venue
+	MaxPlayers         int               `json:"max_players"`          // optional when creating
+	Contact            Contact           `json:"contact"`              // optional
+	OpenToPublic       bool              `json:"open_to_public"`       // optional
+	OpenToRegistration bool              `json:"open_to_registration"` // optional
+	Registration       Registration      `json:"registration"`         // optional
+	ConsumerID         string            `json:"consumer_id"`          // the authenticated api consumer posting it
 }
 
 // Registration represents the registration information for the tournament
@@ -55,22 +62,35 @@ type Contact struct {
 
 // Validate is where we handle the validation of the command
 func (cmd CreateTournamentCommand) Validate() error {
-	errors := make(map[string]string)
+	errors := make(validation.Errors)
 
 	// Name validation
 	if strings.TrimSpace(cmd.Name) == "" {
-		errors["name"] = "is required"
+		errors["name"] = validation.Required()
 	} else if len(strings.TrimSpace(cmd.Name)) < 3 {
-		errors["name"] = "must be at least 3 characters"
+		errors["name"] = validation.TooShort(3)
 	} else if len(strings.TrimSpace(cmd.Name)) > 100 {
-		errors["name"] = "must be less than 100 characters"
+		errors["name"] = validation.TooLong(100)
 	}
 
 	// Description validation (optional but if provided, check length)
 	if len(cmd.Description) > 500 {
-		errors["description"] = "must be less than 500 characters"
+		errors["description"] = validation.TooLong(500)
+	}
+	cmd.validateDescriptions(errors)
+
+	if len(cmd.ConsumerID) > 64 {
+		errors["consumer_id"] = validation.TooLong(64)
 	}
 
+	if cmd.LocationID != "" {
+		if _, err := uuid.Parse(cmd.LocationID); err != nil {
+			errors["location_id"] = validation.InvalidFormat("uuid")
+		}
+	}
+
+	validateTimeControl(cmd.TimeControl, true, errors)
+
 	// Date validation (optional but if provided, check relationship)
 	cmd.validateDates(errors)
 
@@ -81,16 +101,25 @@ func (cmd CreateTournamentCommand) Validate() error {
 	return nil
 }
 
-// IsValidationError Helper function to check if an error is a ValidationError
-func IsValidationError(err error) (*ValidationError, bool) {
-	if ve, ok := err.(ValidationError); ok {
-		return &ve, true
+// validateDescriptions checks the translations are in a supported locale and not too long
+func (cmd CreateTournamentCommand) validateDescriptions(errors validation.Errors) {
+	bundle := i18n.Default()
+	for locale, description := range cmd.Descriptions {
+		key := "descriptions." + locale
+		if !bundle.Supports(i18n.Locale(locale)) {
+			supported := make([]string, 0, len(bundle.Locales()))
+			for _, l := range bundle.Locales() {
+				supported = append(supported, string(l))
+			}
+			errors[key] = validation.OneOf(supported...)
+		} else if len(description) > 500 {
+			errors[key] = validation.TooLong(500)
+		}
 	}
-	return nil, false
 }
 
 // validateDates handles date validation logic
-func (cmd CreateTournamentCommand) validateDates(errors map[string]string) {
+func (cmd CreateTournamentCommand) validateDates(errors validation.Errors) {
 	// Check if dates are provided but zero (invalid state)
 	if len(cmd.Schedule) == 0 {
 		return
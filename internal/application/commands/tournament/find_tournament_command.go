@@ -2,6 +2,8 @@ package commands
 
 import (
 	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
 )
 
 // FindTournamentCommand represents the user's intent to find a tournament
@@ -13,10 +15,10 @@ type FindTournamentCommand struct {
 // Validate represents multiple field validation errors
 // logic for validating the command
 func (cmd FindTournamentCommand) Validate() error {
-	errors := make(map[string]string)
+	errors := make(validation.Errors)
 
 	if cmd.ID == uuid.Nil {
-		errors["id"] = "cannot be nil"
+		errors["id"] = validation.NotNil()
 	}
 
 	return nil
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ListTournamentsCommand represents the user's intent to search the tournaments,
+// every field is optional
+type ListTournamentsCommand struct {
+	Category    domain.TimeControlCategory `json:"category"`
+	TimeControl string                     `json:"time_control"` // only tournaments played at exactly this time control
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListTournamentsCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.Category != "" && !cmd.Category.Valid() {
+		errors["category"] = validation.OneOf("bullet", "blitz", "rapid", "classical")
+	}
+	validateTimeControl(cmd.TimeControl, false, errors)
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// PairRoundCommand represents the arbiter's intent to publish the pairings of the next round
+// of a tournament
+type PairRoundCommand struct {
+	TournamentID uuid.UUID `json:"tournament_id"` // public uuid
+	Pairings     []Pairing `json:"pairings"`
+}
+
+// Pairing is a game of the round, the players by their public uuid
+type Pairing struct {
+	White uuid.UUID `json:"white"`
+	Black uuid.UUID `json:"black"`
+}
+
+// Validate is where we handle the validation of the command, whether the players are registered
+// is checked against the tournament
+func (cmd PairRoundCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.TournamentID == uuid.Nil {
+		errors["tournament_id"] = validation.NotNil()
+	}
+
+	if len(cmd.Pairings) == 0 {
+		errors["pairings"] = validation.NotEmpty()
+	}
+	for i, p := range cmd.Pairings {
+		field := "pairings." + strconv.Itoa(i)
+		if p.White == uuid.Nil {
+			errors[field+".white"] = validation.Required()
+		}
+		if p.Black == uuid.Nil {
+			errors[field+".black"] = validation.Required()
+		}
+		if p.White != uuid.Nil && p.White == p.Black {
+			errors[field+".black"] = validation.DifferentPlayer()
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+)
+
+// RegisterPlayerCommand represents the user's intent to register a player for a tournament
+type RegisterPlayerCommand struct {
+	TournamentID uuid.UUID `json:"tournament_id"` // public uuid
+	PlayerID     uuid.UUID `json:"player_id"`     // public uuid
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RegisterPlayerCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.TournamentID == uuid.Nil {
+		errors["tournament_id"] = validation.NotNil()
+	}
+	if cmd.PlayerID == uuid.Nil {
+		errors["player_id"] = validation.Required()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
@@ -1,10 +1,20 @@
 package commands
 
 import (
-	"fmt"
 	"strings"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
 )
 
+// ValidationError represents multiple field validation errors
+type ValidationError = validation.Error
+
+// IsValidationError Helper function to check if an error is a ValidationError
+func IsValidationError(err error) (*ValidationError, bool) {
+	return validation.AsError(err)
+}
+
 type RegistrationStatus string
 
 const (
@@ -20,20 +30,15 @@ const (
 	PaymentTypeOther    PaymentType = "other"
 )
 
-// ValidationError represents multiple field validation errors
-type ValidationError struct {
-	Errors map[string]string `json:"errors"`
-}
-
-func (ve ValidationError) Error() string {
-	if len(ve.Errors) == 0 {
-		return "validation failed"
+// validateTimeControl checks the notation can be parsed by domain.ParseTimeControl
+func validateTimeControl(notation string, required bool, errors validation.Errors) {
+	if strings.TrimSpace(notation) == "" {
+		if required {
+			errors["time_control"] = validation.Required()
+		}
+		return
 	}
-
-	var messages []string
-	for field, msg := range ve.Errors {
-		messages = append(messages, fmt.Sprintf("%s: %s", field, msg))
+	if _, err := domain.ParseTimeControl(notation); err != nil {
+		errors["time_control"] = validation.InvalidTimeControl()
 	}
-
-	return fmt.Sprintf("validation failed: %s", strings.Join(messages, ", "))
 }
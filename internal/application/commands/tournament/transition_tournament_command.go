@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// TransitionTournamentCommand represents the user's intent to move a tournament through its
+// lifecycle, e.g. to start it
+type TransitionTournamentCommand struct {
+	ID     uuid.UUID               `json:"id"` // public uuid
+	Action domain.TournamentAction `json:"action"`
+	Actor  domain.Principal        `json:"actor"`  // who takes the action, the authenticated consumer or the admin
+	Reason string                  `json:"reason"` // optional
+}
+
+// Validate is where we handle the validation of the command
+func (cmd TransitionTournamentCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if !cmd.Action.Valid() {
+		actions := make([]string, len(domain.TournamentActions))
+		for i, action := range domain.TournamentActions {
+			actions[i] = string(action)
+		}
+		errors["action"] = validation.OneOf(actions...)
+	}
+
+	if strings.TrimSpace(cmd.Actor.ID) == "" {
+		errors["actor"] = validation.Required()
+	} else if len(cmd.Actor.ID) > 100 {
+		errors["actor"] = validation.TooLong(100)
+	}
+
+	if len(cmd.Reason) > 500 {
+		errors["reason"] = validation.TooLong(500)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
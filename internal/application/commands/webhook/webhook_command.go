@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// CreateWebhookCommand represents the intent of an api consumer to be told about events
+type CreateWebhookCommand struct {
+	ConsumerID    string             `json:"consumer_id"` // the authenticated consumer, never the request
+	URL           string             `json:"url"`
+	EventTypes    []domain.EventType `json:"event_types"`    // optional, every type when empty
+	TournamentIDs []uuid.UUID        `json:"tournament_ids"` // optional, every tournament when empty
+}
+
+// Validate is where we handle the validation of the command
+func (cmd CreateWebhookCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ConsumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	}
+
+	if cmd.URL == "" {
+		errors["url"] = validation.Required()
+	} else if len(cmd.URL) > 2048 {
+		errors["url"] = validation.TooLong(2048)
+	} else if u, err := url.Parse(cmd.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		errors["url"] = validation.InvalidURL("http", "https")
+	} else if !domain.PublicHost(u.Hostname()) {
+		errors["url"] = validation.PublicURL()
+	}
+
+	types := make([]string, len(domain.EventTypes))
+	for i, t := range domain.EventTypes {
+		types[i] = string(t)
+	}
+	for i, t := range cmd.EventTypes {
+		if !t.Valid() {
+			errors["event_types."+strconv.Itoa(i)] = validation.OneOf(types...)
+		}
+	}
+
+	for i, id := range cmd.TournamentIDs {
+		if id == uuid.Nil {
+			errors["tournament_ids."+strconv.Itoa(i)] = validation.NotNil()
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ListWebhooksCommand represents the intent to list the webhooks of an api consumer
+type ListWebhooksCommand struct {
+	ConsumerID string `json:"consumer_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListWebhooksCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ConsumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// FindWebhookCommand represents the intent of an api consumer to read, delete or enable one of its webhooks
+type FindWebhookCommand struct {
+	ConsumerID string    `json:"consumer_id"`
+	ID         uuid.UUID `json:"id"` // public uuid
+}
+
+// Validate is where we handle the validation of the command
+func (cmd FindWebhookCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ConsumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	}
+	if cmd.ID == uuid.Nil {
+		errors["id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// ListDeliveriesCommand represents the intent of an api consumer to read the delivery log of one of its webhooks
+type ListDeliveriesCommand struct {
+	ConsumerID string    `json:"consumer_id"`
+	WebhookID  uuid.UUID `json:"webhook_id"`
+	Limit      int       `json:"limit"` // optional, defaults to 50
+}
+
+// Validate is where we handle the validation of the command
+func (cmd ListDeliveriesCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ConsumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	}
+	if cmd.WebhookID == uuid.Nil {
+		errors["webhook_id"] = validation.NotNil()
+	}
+	if cmd.Limit < 0 || cmd.Limit > 500 {
+		errors["limit"] = validation.Between(0, 500)
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+// RedeliverCommand represents the intent of an api consumer to send a delivery of one of its webhooks again
+type RedeliverCommand struct {
+	ConsumerID string    `json:"consumer_id"`
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// Validate is where we handle the validation of the command
+func (cmd RedeliverCommand) Validate() error {
+	errors := make(validation.Errors)
+
+	if cmd.ConsumerID == "" {
+		errors["consumer_id"] = validation.Required()
+	}
+	if cmd.DeliveryID == uuid.Nil {
+		errors["delivery_id"] = validation.NotNil()
+	}
+
+	if len(errors) > 0 {
+		return ValidationError{Errors: errors}
+	}
+
+	return nil
+}
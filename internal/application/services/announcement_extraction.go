@@ -0,0 +1,512 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// The rules below read the announcements of chess events written in spanish and catalan
+// chats. They only look for what organisers usually write, anything they miss is filled
+// in by whoever reviews the announcement
+
+// announcementMaxTitle is how many characters of the first line make the title
+const announcementMaxTitle = 120
+
+// announcementPastWindow is how far before the message a date without a year can be,
+// older dates are taken to be next year's: "5 de enero" posted in december
+const announcementPastWindow = 60 * 24 * time.Hour
+
+// chessKeywords are the normalized words a message needs one of to be an announcement
+var chessKeywords = map[string]struct{}{
+	"escacs": {}, "ajedrez": {}, "chess": {}, "torneig": {}, "tornejos": {}, "torneo": {}, "torneos": {},
+	"open": {}, "obert": {}, "blitz": {}, "rapides": {}, "rapidas": {}, "simultanies": {}, "simultaneas": {},
+	"campionat": {}, "campeonato": {}, "partides": {}, "partidas": {}, "memorial": {}, "trofeu": {}, "trofeo": {},
+}
+
+// catalanMarkers and spanishMarkers are common words of one language and not the other
+var catalanMarkers = map[string]struct{}{
+	"escacs": {}, "torneig": {}, "inscripcio": {}, "inscripcions": {}, "lloc": {}, "dissabte": {}, "diumenge": {},
+	"divendres": {}, "partides": {}, "amb": {}, "carrer": {}, "gratuit": {}, "gratuita": {}, "preu": {}, "obert": {},
+	"quota": {}, "rapides": {}, "gener": {}, "febrer": {}, "marc": {}, "maig": {}, "juny": {}, "juliol": {},
+	"setembre": {}, "novembre": {}, "desembre": {}, "els": {}, "les": {}, "i": {}, "informacio": {}, "hores": {},
+	"premis": {}, "jugadors": {}, "aquest": {}, "aquesta": {},
+}
+
+var spanishMarkers = map[string]struct{}{
+	"ajedrez": {}, "torneo": {}, "inscripcion": {}, "inscripciones": {}, "lugar": {}, "sabado": {}, "domingo": {},
+	"viernes": {}, "partidas": {}, "con": {}, "calle": {}, "gratis": {}, "gratuito": {}, "precio": {}, "abierto": {},
+	"cuota": {}, "rapidas": {}, "enero": {}, "febrero": {}, "marzo": {}, "mayo": {}, "junio": {}, "julio": {},
+	"septiembre": {}, "noviembre": {}, "diciembre": {}, "los": {}, "las": {}, "y": {}, "informacion": {}, "horas": {},
+	"premios": {}, "jugadores": {}, "este": {}, "esta": {},
+}
+
+// announcementMonths are the months in spanish and catalan, longest first so "juliol" is not read as "julio"
+var announcementMonths = map[string]time.Month{
+	"enero": time.January, "gener": time.January,
+	"febrero": time.February, "febrer": time.February,
+	"marzo": time.March, "març": time.March, "marc": time.March,
+	"abril": time.April,
+	"mayo":  time.May, "maig": time.May,
+	"junio": time.June, "juny": time.June,
+	"julio": time.July, "juliol": time.July,
+	"agosto": time.August, "agost": time.August,
+	"septiembre": time.September, "setiembre": time.September, "setembre": time.September,
+	"octubre":   time.October,
+	"noviembre": time.November, "novembre": time.November,
+	"diciembre": time.December, "desembre": time.December,
+}
+
+var (
+	// textDateRX matches "12 de octubre", "12 d'octubre de 2026" and "12 octubre"
+	textDateRX = regexp.MustCompile(`(?i)\b(\d{1,2})(?:\s+de|\s+d['’])?\s*(` + monthAlternatives() + `)(?:\s*(?:de|del|,)?\s*(\d{4}))?`)
+	// dateListRX matches the days listed before a textual date, "del 12 al " or "12, 13 i "
+	dateListRX = regexp.MustCompile(`(?i)(?:^|\D)((?:\d{1,2}\s*(?:,|-|al|a|i|y)\s*)+)$`)
+	dayRX      = regexp.MustCompile(`\d{1,2}`)
+	// rangeRX tells a range, "del 12 al 14", from a list of days
+	rangeRX = regexp.MustCompile(`(?i)(?:-|\ba|\bal)\s*$`)
+	// sentenceEndRX splits a line into sentences
+	sentenceEndRX = regexp.MustCompile(`[.!?]+\s+`)
+	// numericDateRX matches "12/10", "12/10/26" and "12.10.2026", dots and dashes need the year
+	// not to be read from times and scores
+	numericDateRX = regexp.MustCompile(`\b(\d{1,2})([/.-])(\d{1,2})(?:[/.-](\d{4}|\d{2}))?\b`)
+	// labelledTimeRX matches "a les 10h", "a las 18:30" and "hora: 10.00"
+	labelledTimeRX = regexp.MustCompile(`(?i)\b(?:a les|a las|hora|horari|horario|inici|inicio)\s*:?\s*(\d{1,2})(?:[:.h](\d{2}))?\s*h?\b`)
+	// clockTimeRX matches "10:30", "10h30" and "18h"
+	clockTimeRX = regexp.MustCompile(`(?i)\b(\d{1,2})(?:[:h](\d{2})|\s?h)\b`)
+	// amountRX matches "10€", "10,50 €", "€10" and "15 euros"
+	amountRX = regexp.MustCompile(`(?i)(\d+(?:[.,]\d{1,2})?)\s*(?:€|eur\b|euros?\b)|€\s*(\d+(?:[.,]\d{1,2})?)`)
+	// phoneRX matches anything that could be a phone number, see normalizePhone
+	phoneRX = regexp.MustCompile(`(?:\+|00)?\d[\d \-.]{7,16}\d`)
+	// timeControlRX matches "90+30", "90' + 30''" and "5 min + 3 seg"
+	timeControlRX = regexp.MustCompile(`(?i)\b(\d{1,3})\s*(?:'|’|min(?:uts|utos)?)?\s*\+\s*(\d{1,2})\b`)
+	// venueLabelRX matches the lines that say where, "Lloc: Casal de Gràcia" or "📍 Ateneu"
+	venueLabelRX = regexp.MustCompile(`(?i)^\s*(?:📍\s*:?\s*(?:(?:lloc|lugar|local|seu|sede)\s*:)?|(?:lloc|lugar|local|seu|sede)\s*:)\s*(.+)$`)
+	// addressLabelRX matches the lines that only give the address
+	addressLabelRX = regexp.MustCompile(`(?i)^\s*(?:adreça|direcció|dirección|direccion)\s*:\s*(.+)$`)
+	// venueRX finds a venue in running text, "al Casal de Sants" or "en el Club Ajedrez Alcalá"
+	venueRX = regexp.MustCompile(`(?i)\b(?:al|a la|a l['’]|en el|en la|a)\s+((?:club|casal|centre cívic|centro cívico|centre|centro|ateneu|ateneo|biblioteca|escola|escuela|pavelló|pabellón|hotel|sala|local|bar|restaurant|restaurante|col·legi|colegio|institut|instituto)(?:\s[^,.;\n]*)?)`)
+	// streetRX finds an address, "C/ Major 12", "Carrer de Sants, 79" or "Avda. Diagonal 3"
+	streetRX = regexp.MustCompile(`(?i)(?:^|\s|\()(` + streetPrefixes + `\s*[^,;\n()]+(?:,\s*\d+[a-z]?)?)`)
+	// streetStartRX tells whether a part of a place is the street
+	streetStartRX = regexp.MustCompile(`(?i)^` + streetPrefixes + `\s`)
+	// postalCityRX reads the city after a postal code, "08014 Barcelona"
+	postalCityRX = regexp.MustCompile(`\b\d{5}\s+(\p{Lu}[\p{L}'’ -]*\p{L})`)
+)
+
+// streetPrefixes are the ways streets are written in spanish and catalan addresses
+const streetPrefixes = `(?:c/|c\.|carrer|calle|avda\.?|av\.|avinguda|avenida|plaça|plaza|pl\.|passeig|paseo|rambla|ronda|travessera|camí|camino)`
+
+// placeStoppers are where a place read from running text ends, what follows is when
+var placeStoppers = []string{
+	" a les ", " a las ", " a partir ", " des de ", " desde ", " el dia ", " el día ",
+	" el dissabte", " el diumenge", " el divendres", " el sábado", " el domingo", " el viernes",
+	" dissabte", " diumenge", " divendres", " sábado", " domingo", " viernes",
+}
+
+func monthAlternatives() string {
+	months := make([]string, 0, len(announcementMonths))
+	for month := range announcementMonths {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if len(months[i]) != len(months[j]) {
+			return len(months[i]) > len(months[j])
+		}
+		return months[i] < months[j]
+	})
+	return strings.Join(months, "|")
+}
+
+// readAnnouncement returns the announcement the message makes, false when it does not
+// mention chess or a date
+func readAnnouncement(msg domain.ChatMessage, loc *time.Location) (domain.Announcement, bool) {
+	tokens := strings.Fields(domain.NormalizeName(msg.Text))
+	if !hasChessKeyword(tokens) {
+		return domain.Announcement{}, false
+	}
+
+	posted := msg.SentAt.In(loc)
+	dates := readDates(msg.Text, posted)
+	if len(dates) == 0 {
+		return domain.Announcement{}, false
+	}
+
+	a := domain.Announcement{
+		Author:      msg.Author,
+		PostedAt:    msg.SentAt,
+		Text:        msg.Text,
+		Fingerprint: domain.AnnouncementFingerprint(msg.Text),
+		Language:    detectLanguage(tokens),
+		Title:       readTitle(msg.Text),
+		Dates:       dates,
+		StartsAt:    firstUpcoming(dates, posted),
+		Timezone:    domain.Timezone(loc.String()),
+		Phones:      readPhones(msg.Text),
+		TimeControl: readTimeControl(msg.Text),
+	}
+	if hour, minute, ok := readTime(msg.Text); ok {
+		a.StartsAt = time.Date(a.StartsAt.Year(), a.StartsAt.Month(), a.StartsAt.Day(), hour, minute, 0, 0, loc)
+		a.HasTime = true
+	}
+	a.FeeCents, a.Free = readFee(msg.Text)
+	a.Venue, a.Address, a.City = readPlace(msg.Text)
+
+	return a, true
+}
+
+func hasChessKeyword(tokens []string) bool {
+	for _, token := range tokens {
+		if _, ok := chessKeywords[token]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// detectLanguage counts the words only one of the languages uses, ties are left undecided
+func detectLanguage(tokens []string) string {
+	ca, es := 0, 0
+	for _, token := range tokens {
+		if _, ok := catalanMarkers[token]; ok {
+			ca++
+		}
+		if _, ok := spanishMarkers[token]; ok {
+			es++
+		}
+	}
+	switch {
+	case ca > es:
+		return "ca"
+	case es > ca:
+		return "es"
+	}
+	return ""
+}
+
+// readDates returns every date of the text at midnight, oldest first
+func readDates(text string, posted time.Time) []time.Time {
+	seen := make(map[time.Time]struct{})
+	add := func(day, month, year int, hasYear bool) {
+		if !hasYear {
+			year = posted.Year()
+		} else if year < 100 {
+			year += 2000
+		}
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, posted.Location())
+		if date.Day() != day || date.Month() != time.Month(month) {
+			// 31 of november and the like
+			return
+		}
+		if !hasYear && date.Before(posted.Add(-announcementPastWindow)) {
+			date = date.AddDate(1, 0, 0)
+		}
+		seen[date] = struct{}{}
+	}
+
+	for _, m := range textDateRX.FindAllStringSubmatchIndex(text, -1) {
+		monthEnd := m[5]
+		if r, _ := utf8.DecodeRuneInString(text[monthEnd:]); m[6] < 0 && unicode.IsLetter(r) {
+			// a month followed by more letters is another word
+			continue
+		}
+		month := announcementMonths[strings.ToLower(text[m[4]:m[5]])]
+		year, hasYear := 0, m[6] >= 0
+		if hasYear {
+			year, _ = strconv.Atoi(text[m[6]:m[7]])
+		}
+
+		day, _ := strconv.Atoi(text[m[2]:m[3]])
+		add(day, int(month), year, hasYear)
+
+		// the days listed before share the month: "12, 13 i 14 de octubre" and "del 12 al 14 de octubre"
+		if list := dateListRX.FindStringSubmatch(text[:m[0]]); list != nil {
+			days := dayRX.FindAllString(list[1], -1)
+			first, _ := strconv.Atoi(days[0])
+			if len(days) == 1 && rangeRX.MatchString(list[1]) && first < day {
+				for d := first; d < day; d++ {
+					add(d, int(month), year, hasYear)
+				}
+				continue
+			}
+			for _, d := range days {
+				day, _ := strconv.Atoi(d)
+				add(day, int(month), year, hasYear)
+			}
+		}
+	}
+
+	for _, m := range numericDateRX.FindAllStringSubmatch(text, -1) {
+		if m[2] != "/" && m[4] == "" {
+			continue
+		}
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[3])
+		year, _ := strconv.Atoi(m[4])
+		add(day, month, year, m[4] != "")
+	}
+
+	dates := make([]time.Time, 0, len(seen))
+	for date := range seen {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	return dates
+}
+
+// firstUpcoming is the first date from the day of the message, the results of past
+// events are sometimes mentioned before the next one
+func firstUpcoming(dates []time.Time, posted time.Time) time.Time {
+	today := time.Date(posted.Year(), posted.Month(), posted.Day(), 0, 0, 0, 0, posted.Location())
+	for _, date := range dates {
+		if !date.Before(today) {
+			return date
+		}
+	}
+	return dates[0]
+}
+
+// readTime prefers the times that are introduced as such over any clock time of the text
+func readTime(text string) (hour, minute int, ok bool) {
+	for _, rx := range []*regexp.Regexp{labelledTimeRX, clockTimeRX} {
+		for _, m := range rx.FindAllStringSubmatch(text, -1) {
+			hour, _ = strconv.Atoi(m[1])
+			minute, _ = strconv.Atoi(m[2])
+			if hour < 24 && minute < 60 {
+				return hour, minute, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// readFee only looks at the lines about the registration, the rest of the amounts are usually prizes
+func readFee(text string) (cents int64, free bool) {
+	for _, line := range strings.Split(text, "\n") {
+		normalized := " " + domain.NormalizeName(line) + " "
+		if containsAny(normalized, " premi ", " premis ", " premio ", " premios ", " bossa ", " bolsa ") {
+			continue
+		}
+		if containsAny(normalized, " gratuit ", " gratuita ", " gratuito ", " gratis ", " entrada lliure ", " entrada libre ") {
+			free = true
+		}
+		if !containsAny(normalized, " inscripcio ", " inscripcions ", " inscripcion ", " inscripciones ", " preu ", " precio ",
+			" quota ", " cuota ", " matricula ", " entrada ", " cost ", " coste ") {
+			continue
+		}
+		if m := amountRX.FindStringSubmatch(line); m != nil {
+			amount := m[1]
+			if amount == "" {
+				amount = m[2]
+			}
+			return parseCents(amount), false
+		}
+	}
+	return 0, free
+}
+
+// parseCents reads "10", "10,5" and "10.50" as euros
+func parseCents(amount string) int64 {
+	euros, decimals, _ := strings.Cut(strings.ReplaceAll(amount, ",", "."), ".")
+	cents, _ := strconv.ParseInt(euros, 10, 64)
+	cents *= 100
+	if decimals != "" {
+		d, _ := strconv.ParseInt((decimals + "0")[:2], 10, 64)
+		cents += d
+	}
+	return cents
+}
+
+// readPhones returns the spanish phone numbers of the text as +34 and the nine digits
+func readPhones(text string) []string {
+	phones := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, candidate := range phoneRX.FindAllString(text, -1) {
+		phone, ok := normalizePhone(candidate)
+		if !ok {
+			continue
+		}
+		if _, dup := seen[phone]; !dup {
+			seen[phone] = struct{}{}
+			phones = append(phones, phone)
+		}
+	}
+	return phones
+}
+
+func normalizePhone(s string) (string, bool) {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	switch {
+	case len(digits) == 13 && strings.HasPrefix(digits, "0034"):
+		digits = digits[4:]
+	case len(digits) == 11 && strings.HasPrefix(digits, "34"):
+		digits = digits[2:]
+	}
+	if len(digits) != 9 || !strings.ContainsAny(digits[:1], "6789") {
+		return "", false
+	}
+	return "+34" + digits, true
+}
+
+// readTimeControl returns the first base plus increment the text mentions that is a valid time control
+func readTimeControl(text string) string {
+	for _, m := range timeControlRX.FindAllStringSubmatch(text, -1) {
+		base, _ := strconv.Atoi(m[1])
+		increment, _ := strconv.Atoi(m[2])
+		if base == 0 || base > 180 || increment > 60 {
+			continue
+		}
+		notation := fmt.Sprintf("%d+%d", base, increment)
+		if _, err := domain.ParseTimeControl(notation); err == nil {
+			return notation
+		}
+	}
+	return ""
+}
+
+// readTitle is the first line or sentence that mentions chess without the formatting of the chat
+func readTitle(text string) string {
+	title := chessSentence(text)
+	if utf8.RuneCountInString(title) > announcementMaxTitle {
+		title = strings.TrimSpace(string([]rune(title)[:announcementMaxTitle]))
+	}
+	return title
+}
+
+// chessSentence returns the first sentence with a chess keyword, or the first sentence if none has one
+func chessSentence(text string) string {
+	first := ""
+	for _, line := range strings.Split(text, "\n") {
+		for _, sentence := range sentenceEndRX.Split(line, -1) {
+			sentence = cleanTitle(sentence)
+			if sentence == "" {
+				continue
+			}
+			if hasChessKeyword(strings.Fields(domain.NormalizeName(sentence))) {
+				return sentence
+			}
+			if first == "" {
+				first = sentence
+			}
+		}
+	}
+	return first
+}
+
+// cleanTitle drops the *bold* and _italic_ marks, the emojis around the line and a trailing colon
+func cleanTitle(line string) string {
+	line = strings.NewReplacer("*", "", "_", "", "~", "").Replace(line)
+	line = strings.TrimFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '(' && r != ')' && r != '!' && r != '?'
+	})
+	return strings.TrimSpace(line)
+}
+
+// readPlace prefers the lines labelled as the place of the event over the places mentioned in the text
+func readPlace(text string) (venue, address, city string) {
+	for _, line := range strings.Split(text, "\n") {
+		if m := venueLabelRX.FindStringSubmatch(line); m != nil && venue == "" {
+			venue, address, city = splitPlace(m[1])
+			continue
+		}
+		if m := addressLabelRX.FindStringSubmatch(line); m != nil && address == "" {
+			_, address, city = splitPlace(m[1])
+			if address == "" {
+				address = cleanTitle(m[1])
+			}
+		}
+	}
+
+	if venue == "" {
+		if m := venueRX.FindStringSubmatch(text); m != nil {
+			venue = trimPlace(m[1])
+		}
+	}
+	if address == "" {
+		if m := streetRX.FindStringSubmatch(text); m != nil {
+			address = trimPlace(m[1])
+		}
+	}
+	if city == "" {
+		if m := postalCityRX.FindStringSubmatch(text); m != nil {
+			city = strings.TrimSpace(m[1])
+		}
+	}
+
+	return venue, address, city
+}
+
+// splitPlace reads "Casal de Sants, C/ Olzinelles 30, 08014 Barcelona", the part before the
+// street is the venue and the part after it the city
+func splitPlace(place string) (venue, address, city string) {
+	parts := strings.Split(place, ",")
+	street := -1
+	for i, part := range parts {
+		parts[i] = cleanTitle(part)
+		if street < 0 && streetStartRX.MatchString(parts[i]) {
+			street = i
+		}
+	}
+
+	if street < 0 {
+		venue = parts[0]
+		if len(parts) > 1 {
+			city = postalCity(parts[len(parts)-1])
+		}
+		return venue, "", city
+	}
+
+	venue = strings.Join(parts[:street], ", ")
+	address = parts[street]
+	rest := parts[street+1:]
+	// "C/ Major, 12" the number goes with the street
+	if len(rest) > 0 && rest[0] != "" && unicode.IsDigit(rune(rest[0][0])) && !postalCodeRX.MatchString(rest[0]) {
+		address += ", " + rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		city = postalCity(rest[len(rest)-1])
+	}
+
+	return venue, address, city
+}
+
+// trimPlace cuts a place read from running text where the time of the event starts
+func trimPlace(place string) string {
+	lower := strings.ToLower(place)
+	for _, stopper := range placeStoppers {
+		if i := strings.Index(lower, stopper); i >= 0 {
+			place, lower = place[:i], lower[:i]
+		}
+	}
+	return strings.TrimSpace(place)
+}
+
+var postalCodeRX = regexp.MustCompile(`^\d{5}\b`)
+
+// postalCity drops the postal code in front of a city
+func postalCity(part string) string {
+	return strings.TrimSpace(postalCodeRX.ReplaceAllString(strings.TrimSpace(part), ""))
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
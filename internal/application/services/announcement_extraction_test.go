@@ -0,0 +1,155 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+func TestReadAnnouncement(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatalf("error loading location: %v", err)
+	}
+	posted := time.Date(2026, 9, 20, 19, 2, 0, 0, madrid)
+
+	tests := []struct {
+		name        string
+		text        string
+		want        bool
+		language    string
+		title       string
+		startsAt    time.Time
+		hasTime     bool
+		dates       int
+		venue       string
+		address     string
+		city        string
+		fee         int64
+		free        bool
+		phones      []string
+		timeControl string
+	}{
+		{
+			name: "catalan with labels",
+			text: "♟️ *OPEN D'ESCACS DE SANTS* ♟️\n" +
+				"📅 Dissabte 12 d'octubre a les 10h\n" +
+				"📍 Lloc: Casal de Sants, C/ Olzinelles 30, 08014 Barcelona\n" +
+				"Ritme 90' + 30''\n" +
+				"Inscripció: 15€ (10€ socis)\n" +
+				"Premis: 200€ al primer\n" +
+				"Info i inscripcions: 612 34 56 78",
+			want:        true,
+			language:    "ca",
+			title:       "OPEN D'ESCACS DE SANTS",
+			startsAt:    time.Date(2026, 10, 12, 10, 0, 0, 0, madrid),
+			hasTime:     true,
+			dates:       1,
+			venue:       "Casal de Sants",
+			address:     "C/ Olzinelles 30",
+			city:        "Barcelona",
+			fee:         1500,
+			phones:      []string{"+34612345678"},
+			timeControl: "90+30",
+		},
+		{
+			name: "spanish running text",
+			text: "Hola a todos! Este sábado 4/10 hay torneo de ajedrez rápidas en el Club Ajedrez Alcalá a las 17:30. " +
+				"Partidas a 5+3, cuota de inscripción 5 euros. Llamad al +34 699-111-222",
+			want:        true,
+			language:    "es",
+			title:       "Este sábado 4/10 hay torneo de ajedrez rápidas en el Club Ajedrez Alcalá a las 17:30",
+			startsAt:    time.Date(2026, 10, 4, 17, 30, 0, 0, madrid),
+			hasTime:     true,
+			dates:       1,
+			venue:       "Club Ajedrez Alcalá",
+			fee:         500,
+			phones:      []string{"+34699111222"},
+			timeControl: "5+3",
+		},
+		{
+			name:     "date range and free entry",
+			text:     "Campionat de Catalunya de partides ràpides\ndel 12 al 14 de desembre\nEntrada lliure",
+			want:     true,
+			language: "ca",
+			title:    "Campionat de Catalunya de partides ràpides",
+			startsAt: time.Date(2026, 12, 12, 0, 0, 0, 0, madrid),
+			dates:    3,
+			free:     true,
+			phones:   []string{},
+		},
+		{
+			name:     "january posted in september is next year",
+			text:     "Torneo de Reyes el 5 de enero, Calle Mayor, 3",
+			want:     true,
+			language: "es",
+			title:    "Torneo de Reyes el 5 de enero, Calle Mayor, 3",
+			startsAt: time.Date(2027, 1, 5, 0, 0, 0, 0, madrid),
+			dates:    1,
+			address:  "Calle Mayor, 3",
+			phones:   []string{},
+		},
+		{
+			name: "no date",
+			text: "Algú s'apunta al torneig de blitz? Porto rellotges",
+		},
+		{
+			name: "no chess",
+			text: "Sopar de Nadal el 20 de desembre a les 21h",
+		},
+		{
+			name: "times and scores are not dates",
+			text: "Resultats del torneig: 2.5-1.5, ronda a les 18.30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok := readAnnouncement(domain.ChatMessage{Author: "Jordi", SentAt: posted, Text: tt.text}, madrid)
+			if ok != tt.want {
+				t.Fatalf("expected announcement %t, got %t: %+v", tt.want, ok, a)
+			}
+			if !ok {
+				return
+			}
+
+			if a.Language != tt.language {
+				t.Errorf("expected language %q, got %q", tt.language, a.Language)
+			}
+			if a.Title != tt.title {
+				t.Errorf("expected title %q, got %q", tt.title, a.Title)
+			}
+			if !a.StartsAt.Equal(tt.startsAt) || a.HasTime != tt.hasTime {
+				t.Errorf("expected to start at %s (time %t), got %s (time %t)", tt.startsAt, tt.hasTime, a.StartsAt, a.HasTime)
+			}
+			if len(a.Dates) != tt.dates {
+				t.Errorf("expected %d dates, got %v", tt.dates, a.Dates)
+			}
+			if a.Venue != tt.venue || a.Address != tt.address || a.City != tt.city {
+				t.Errorf("expected place %q, %q, %q, got %q, %q, %q", tt.venue, tt.address, tt.city, a.Venue, a.Address, a.City)
+			}
+			if a.FeeCents != tt.fee || a.Free != tt.free {
+				t.Errorf("expected fee %d (free %t), got %d (free %t)", tt.fee, tt.free, a.FeeCents, a.Free)
+			}
+			if !reflect.DeepEqual(a.Phones, tt.phones) {
+				t.Errorf("expected phones %v, got %v", tt.phones, a.Phones)
+			}
+			if a.TimeControl != tt.timeControl {
+				t.Errorf("expected time control %q, got %q", tt.timeControl, a.TimeControl)
+			}
+			if a.Fingerprint != domain.AnnouncementFingerprint(tt.text) || a.Timezone != "Europe/Madrid" {
+				t.Errorf("unexpected fingerprint or timezone %+v", a)
+			}
+		})
+	}
+}
+
+func TestAnnouncementFingerprint(t *testing.T) {
+	a := domain.AnnouncementFingerprint("♟️ *Open d'Escacs* 12 d'octubre")
+	b := domain.AnnouncementFingerprint("open d escacs   12 D'OCTUBRE")
+	if a != b {
+		t.Errorf("expected the formatting not to change the fingerprint")
+	}
+}
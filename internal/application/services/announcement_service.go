@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/announcement"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// chatImportTimeout is how long an import of a chat export has from being queued
+const chatImportTimeout = 10 * time.Minute
+
+type AnnouncementServicer struct {
+	logger        ports.Logger
+	reader        ports.ChatExportReader
+	announcements ports.AnnouncementRepositoryProvider
+	tournaments   ports.TournamentRepositoryProvider
+	imports       *importJobs[commands.ImportChatCommand, domain.ChatImportSummary]
+}
+
+// NewAnnouncementServicer - the chat exports are imported on the low lane of wp
+func NewAnnouncementServicer(log ports.Logger, reader ports.ChatExportReader, ar ports.AnnouncementRepositoryProvider, tr ports.TournamentRepositoryProvider, wp *WorkerPool) (ports.AnnouncementServicer, error) {
+	as := &AnnouncementServicer{
+		logger:        log,
+		reader:        reader,
+		announcements: ar,
+		tournaments:   tr,
+	}
+
+	var err error
+	if as.imports, err = newImportJobs(wp, "announcement.ImportChat", as.importChat, chatImportTimeout); err != nil {
+		return nil, err
+	}
+
+	return as, nil
+}
+
+// ImportChat queues the import of the chat export, the job it returns tells how it goes with
+// FindImport
+func (as *AnnouncementServicer) ImportChat(ctx context.Context, cmd commands.ImportChatCommand) (domain.ImportJob[domain.ChatImportSummary], error) {
+	return as.imports.queue(ctx, cmd)
+}
+
+// FindImport returns the job of an import of a chat export, it is kept for a day once finished
+func (as *AnnouncementServicer) FindImport(ctx context.Context, cmd commands.FindImportCommand) (domain.ImportJob[domain.ChatImportSummary], error) {
+	return as.imports.find(cmd.ID)
+}
+
+// importChat reads every message of the export, the ones that look like announcements are
+// queued unless the same text was queued before, by an earlier import or another message
+func (as *AnnouncementServicer) importChat(ctx context.Context, cmd commands.ImportChatCommand) (domain.ChatImportSummary, error) {
+	timezone := cmd.Timezone
+	if timezone == "" {
+		timezone = commands.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(string(timezone))
+	if err != nil {
+		return domain.ChatImportSummary{}, err
+	}
+
+	export, err := as.reader.Read(ctx, domain.ChatExportFile{Path: cmd.Path, Format: cmd.Format, Location: loc})
+	if err != nil {
+		return domain.ChatImportSummary{}, err
+	}
+
+	summary := domain.ChatImportSummary{
+		Format:   export.Format,
+		Chat:     export.Chat,
+		Messages: len(export.Messages),
+	}
+
+	err = as.announcements.WriteTx(func(repo ports.AnnouncementRepository) error {
+		for _, msg := range export.Messages {
+			announcement, ok := readAnnouncement(msg, loc)
+			if !ok {
+				continue
+			}
+
+			seen, err := repo.HasFingerprint(announcement.Fingerprint)
+			if err != nil {
+				return err
+			}
+			if seen {
+				summary.Duplicates++
+				continue
+			}
+
+			announcement.Source = export.Format
+			announcement.Chat = export.Chat
+			announcement.Status = domain.AnnouncementPending
+			if _, err := repo.CreateAnnouncement(announcement); err != nil {
+				return err
+			}
+			summary.Announcements++
+		}
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	as.logger.Info(ctx, "chat export imported",
+		ports.String("format", string(summary.Format)),
+		ports.String("chat", summary.Chat),
+		ports.Int("messages", summary.Messages),
+		ports.Int("announcements", summary.Announcements),
+		ports.Int("duplicates", summary.Duplicates),
+	)
+
+	return summary, nil
+}
+
+func (as *AnnouncementServicer) ListAnnouncements(ctx context.Context, cmd commands.ListAnnouncementsCommand) ([]domain.Announcement, error) {
+	filter := domain.AnnouncementFilter{Status: cmd.Status}
+	if filter.Status == "" {
+		filter.Status = domain.AnnouncementPending
+	}
+
+	var result []domain.Announcement
+	err := as.announcements.ReadTx(func(repo ports.AnnouncementRepository) error {
+		var err error
+		result, err = repo.ListAnnouncements(filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (as *AnnouncementServicer) FindAnnouncement(ctx context.Context, cmd commands.FindAnnouncementCommand) (domain.Announcement, error) {
+	var result domain.Announcement
+	err := as.announcements.ReadTx(func(repo ports.AnnouncementRepository) error {
+		var err error
+		result, err = repo.FindAnnouncement(cmd.ID)
+		return err
+	})
+	if err != nil {
+		return domain.Announcement{}, err
+	}
+
+	return result, nil
+}
+
+// ConfirmAnnouncement creates the draft tournament inside the announcements' WriteTx so
+// two organizers confirming the same announcement cannot create two drafts
+func (as *AnnouncementServicer) ConfirmAnnouncement(ctx context.Context, cmd commands.ConfirmAnnouncementCommand) (domain.Announcement, error) {
+	var result domain.Announcement
+	err := as.announcements.WriteTx(func(repo ports.AnnouncementRepository) error {
+		announcement, err := repo.FindAnnouncement(cmd.ID)
+		if err != nil {
+			return err
+		}
+		if announcement.Status != domain.AnnouncementPending {
+			return domain.ErrAnnouncementNotPending
+		}
+
+		announcement = applyCorrections(announcement, cmd)
+		if announcement.Title == "" || announcement.StartsAt.IsZero() {
+			return domain.ErrAnnouncementIncomplete
+		}
+
+		tournament, err := as.createDraft(announcement)
+		if err != nil {
+			return err
+		}
+
+		announcement.Status = domain.AnnouncementConfirmed
+		announcement.TournamentID = tournament.PublicID
+		announcement.ReviewNote = cmd.Note
+		announcement.ReviewedAt = time.Now()
+		result, err = repo.UpdateAnnouncement(announcement)
+		return err
+	})
+	if err != nil {
+		return domain.Announcement{}, err
+	}
+
+	as.logger.Info(ctx, "announcement confirmed",
+		ports.String("announcement_id", result.PublicID.String()),
+		ports.String("tournament_id", result.TournamentID.String()),
+	)
+
+	return result, nil
+}
+
+func (as *AnnouncementServicer) RejectAnnouncement(ctx context.Context, cmd commands.RejectAnnouncementCommand) (domain.Announcement, error) {
+	var result domain.Announcement
+	err := as.announcements.WriteTx(func(repo ports.AnnouncementRepository) error {
+		announcement, err := repo.FindAnnouncement(cmd.ID)
+		if err != nil {
+			return err
+		}
+		if announcement.Status != domain.AnnouncementPending {
+			return domain.ErrAnnouncementNotPending
+		}
+
+		announcement.Status = domain.AnnouncementRejected
+		announcement.ReviewNote = cmd.Note
+		announcement.ReviewedAt = time.Now()
+		result, err = repo.UpdateAnnouncement(announcement)
+		return err
+	})
+	if err != nil {
+		return domain.Announcement{}, err
+	}
+
+	return result, nil
+}
+
+// applyCorrections replaces what was read from the message with what the organizer set
+func applyCorrections(a domain.Announcement, cmd commands.ConfirmAnnouncementCommand) domain.Announcement {
+	if name := strings.TrimSpace(cmd.Name); name != "" {
+		a.Title = name
+	}
+	if cmd.StartsAt != nil {
+		a.StartsAt = *cmd.StartsAt
+		a.HasTime = true
+	}
+	if cmd.Venue != "" {
+		a.Venue = cmd.Venue
+	}
+	if cmd.Address != "" {
+		a.Address = cmd.Address
+	}
+	if cmd.City != "" {
+		a.City = cmd.City
+	}
+	if cmd.Fee != nil {
+		a.FeeCents = *cmd.Fee
+		a.Free = *cmd.Fee == 0
+	}
+	if cmd.TimeControl != "" {
+		a.TimeControl = cmd.TimeControl
+	}
+	if cmd.Phone != "" {
+		a.Phones = append([]string{cmd.Phone}, a.Phones...)
+	}
+	return a
+}
+
+// createDraft stores the tournament of the announcement, the message is kept as its
+// description for the organizer to rewrite
+func (as *AnnouncementServicer) createDraft(a domain.Announcement) (domain.Tournament, error) {
+	tournament := domain.NewTournament(a.Title, a.Text)
+	tournament.Location = domain.Location{
+		Name:     a.Venue,
+		Address:  a.Address,
+		City:     a.City,
+		Timezone: a.Timezone,
+	}
+	tournament.Contact = domain.Contact{Name: a.Author}
+	if len(a.Phones) > 0 {
+		tournament.Contact.Phone = a.Phones[0]
+	}
+	tournament.Registration.PublicFee = a.FeeCents
+	tournament.Schedule = []domain.Schedule{{StartTime: a.StartsAt}}
+	if a.TimeControl != "" {
+		// the time control read from the message is not always one, the draft is created without it
+		if tc, err := domain.ParseTimeControl(a.TimeControl); err == nil {
+			tournament.TimeControl = tc
+		}
+	}
+
+	var result domain.Tournament
+	err := as.tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		result, err = repo.CreateTournament(*tournament)
+		if err != nil {
+			return err
+		}
+
+		return repo.RecordEvents(domain.TournamentCreated{
+			TournamentID: result.PublicID,
+			Name:         result.Name,
+			TimeControl:  result.TimeControl.String(),
+			At:           result.CreatedAt,
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/announcement"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// stubChatReader serves the export named in the file path
+type stubChatReader struct {
+	exports map[string]domain.ChatExport
+}
+
+func (sr stubChatReader) Read(ctx context.Context, file domain.ChatExportFile) (domain.ChatExport, error) {
+	export, ok := sr.exports[file.Path]
+	if !ok {
+		return domain.ChatExport{}, domain.ErrChatExportFormat
+	}
+	return export, nil
+}
+
+func TestAnnouncementServicer_ImportAndReview(t *testing.T) {
+	ctx := context.Background()
+	posted := time.Date(2026, 9, 20, 19, 2, 0, 0, time.UTC)
+	open := "Open d'escacs de Gràcia el 12 d'octubre a les 10h\nLloc: Ateneu Gracienc\nInscripció 12€\nInfo: 655 44 33 22"
+	reader := stubChatReader{exports: map[string]domain.ChatExport{
+		"chat.txt": {
+			Format: domain.ChatExportWhatsApp,
+			Chat:   "Escacs BCN",
+			Messages: []domain.ChatMessage{
+				{Author: "Núria", SentAt: posted, Text: open},
+				{Author: "Pau", SentAt: posted.Add(time.Minute), Text: "Hi serem!"},
+				{Author: "Pau", SentAt: posted.Add(time.Hour), Text: "*OPEN D'ESCACS DE GRÀCIA* el 12 d'octubre a les 10h\nLloc: Ateneu Gracienc\nInscripció 12€\nInfo: 655 44 33 22"},
+				{Author: "Marta", SentAt: posted.Add(2 * time.Hour), Text: "Torneo blitz en el Bar Peón el 3 de octubre"},
+			},
+		},
+	}}
+
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+	service, err := NewAnnouncementServicer(lggr, reader, inmemory.NewAnnouncementRepositoryProvider(inmemory.NewInMemoryAnnouncementRepository()), tournaments, wp)
+	if err != nil {
+		t.Fatalf("error creating announcement service: %v", err)
+	}
+	importChat := func(path string) domain.ImportJob[domain.ChatImportSummary] {
+		t.Helper()
+		job, err := service.ImportChat(ctx, commands.ImportChatCommand{Path: path})
+		if err != nil {
+			t.Fatalf("error queueing the import of %s: %v", path, err)
+		}
+		return awaitImport(t, job, func(id uuid.UUID) (domain.ImportJob[domain.ChatImportSummary], error) {
+			return service.FindImport(ctx, commands.FindImportCommand{ID: id})
+		})
+	}
+
+	job := importChat("chat.txt")
+	if job.Status != domain.ImportJobDone {
+		t.Fatalf("error importing chat: %s", job.Error)
+	}
+	summary := job.Summary
+	// the forwarded copy only differs in its formatting
+	if summary.Messages != 4 || summary.Announcements != 2 || summary.Duplicates != 1 || summary.Chat != "Escacs BCN" {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	summary = importChat("chat.txt").Summary
+	if summary.Announcements != 0 || summary.Duplicates != 3 {
+		t.Errorf("expected everything to be a duplicate the second time, got %+v", summary)
+	}
+	if failed := importChat("missing.txt"); failed.Status != domain.ImportJobFailed {
+		t.Errorf("expected the import of an unreadable export to fail, got %+v", failed)
+	}
+
+	pending, err := service.ListAnnouncements(ctx, commands.ListAnnouncementsCommand{})
+	if err != nil {
+		t.Fatalf("error listing announcements: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Author != "Núria" || pending[0].Source != domain.ChatExportWhatsApp {
+		t.Fatalf("unexpected queue %+v", pending)
+	}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	if !pending[0].StartsAt.Equal(time.Date(2026, 10, 12, 10, 0, 0, 0, madrid)) {
+		t.Errorf("expected the dates to be read in Europe/Madrid, got %s", pending[0].StartsAt)
+	}
+
+	confirmed, err := service.ConfirmAnnouncement(ctx, commands.ConfirmAnnouncementCommand{
+		ID:   pending[0].PublicID,
+		Name: "Open de Gràcia 2026",
+		City: "Barcelona",
+	})
+	if err != nil {
+		t.Fatalf("error confirming announcement: %v", err)
+	}
+	if confirmed.Status != domain.AnnouncementConfirmed || confirmed.ReviewedAt.IsZero() {
+		t.Errorf("unexpected announcement %+v", confirmed)
+	}
+
+	var draft domain.Tournament
+	err = tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		var err error
+		draft, err = repo.FindTournament(confirmed.TournamentID)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error finding the draft: %v", err)
+	}
+	if draft.Status != domain.TournamentStatusDraft || draft.Name != "Open de Gràcia 2026" || draft.Description != open {
+		t.Errorf("unexpected draft %+v", draft)
+	}
+	if draft.Location.Name != "Ateneu Gracienc" || draft.Location.City != "Barcelona" || draft.Location.Timezone != "Europe/Madrid" {
+		t.Errorf("unexpected location %+v", draft.Location)
+	}
+	if draft.Contact.Phone != "+34655443322" || draft.Registration.PublicFee != 1200 || len(draft.Schedule) != 1 {
+		t.Errorf("unexpected contact, fee or schedule %+v", draft)
+	}
+
+	if _, err := service.ConfirmAnnouncement(ctx, commands.ConfirmAnnouncementCommand{ID: confirmed.PublicID}); !errors.Is(err, domain.ErrAnnouncementNotPending) {
+		t.Errorf("expected ErrAnnouncementNotPending, got %v", err)
+	}
+
+	rejected, err := service.RejectAnnouncement(ctx, commands.RejectAnnouncementCommand{ID: pending[1].PublicID, Note: "already over"})
+	if err != nil {
+		t.Fatalf("error rejecting announcement: %v", err)
+	}
+	if rejected.Status != domain.AnnouncementRejected || rejected.ReviewNote != "already over" {
+		t.Errorf("unexpected announcement %+v", rejected)
+	}
+
+	pending, err = service.ListAnnouncements(ctx, commands.ListAnnouncementsCommand{})
+	if err != nil || len(pending) != 0 {
+		t.Errorf("expected the queue to be empty, got %d, %v", len(pending), err)
+	}
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	ratingcommands "github.com/ctfrancia/maple/internal/application/commands/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// ChallengeConfig - how often the challenges nobody accepted are expired
+type ChallengeConfig struct {
+	ExpireInterval time.Duration
+}
+
+func DefaultChallengeConfig() ChallengeConfig {
+	return ChallengeConfig{
+		ExpireInterval: time.Minute,
+	}
+}
+
+// ChallengeServicer is the board of open challenges for casual games. Accepting a challenge
+// creates its match while the challenge is locked, so of two players accepting at the same
+// time only the first one gets the game. The challenges nobody accepted expire in the
+// background when their window is over, until then they are reported as expired anyway
+type ChallengeServicer struct {
+	logger     ports.Logger
+	challenges ports.ChallengeRepositoryProvider
+	players    ports.PlayerRepositoryProvider
+	matches    ports.MatchRepositoryProvider
+	ratings    ports.RatingServicer
+	policy     ports.ListingPolicy
+	config     ChallengeConfig
+	now        func() time.Time
+
+	lifecycle sync.Mutex
+	started   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewChallengeServicer(log ports.Logger, cr ports.ChallengeRepositoryProvider, pr ports.PlayerRepositoryProvider, mr ports.MatchRepositoryProvider, rs ports.RatingServicer, policy ports.ListingPolicy, config ChallengeConfig) *ChallengeServicer {
+	return &ChallengeServicer{
+		logger:     log,
+		challenges: cr,
+		players:    pr,
+		matches:    mr,
+		ratings:    rs,
+		policy:     policy,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+func (cs *ChallengeServicer) CreateChallenge(ctx context.Context, cmd commands.CreateChallengeCommand) (domain.Challenge, error) {
+	if err := cs.players.ReadTx(func(repo ports.PlayerRepository) error {
+		_, err := repo.FindPlayer(cmd.ChallengerID)
+		return err
+	}); err != nil {
+		return domain.Challenge{}, err
+	}
+
+	if !cmd.EndsAt.After(cs.now()) {
+		return domain.Challenge{}, domain.ErrChallengeWindowPassed
+	}
+	timeControl, err := domain.ParseTimeControl(cmd.TimeControl)
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+	moderation := domain.ModerationApproved
+	if cs.policy != nil {
+		moderation, err = cs.policy.Admit(ctx, cmd.ConsumerID)
+		if err != nil {
+			return domain.Challenge{}, err
+		}
+	}
+
+	challenge := domain.Challenge{
+		ChallengerID: cmd.ChallengerID,
+		Location: domain.Location{
+			Name:      strings.TrimSpace(cmd.LocationName),
+			Address:   strings.TrimSpace(cmd.Address),
+			City:      strings.TrimSpace(cmd.City),
+			Country:   strings.TrimSpace(cmd.Country),
+			Latitude:  cmd.Latitude,
+			Longitude: cmd.Longitude,
+			Timezone:  cmd.Timezone,
+		},
+		RadiusKm:    cmd.RadiusKm,
+		StartsAt:    cmd.StartsAt,
+		EndsAt:      cmd.EndsAt,
+		TimeControl: timeControl,
+		Rated:       cmd.Rated,
+		MinRating:   cmd.MinRating,
+		MaxRating:   cmd.MaxRating,
+		Note:        strings.TrimSpace(cmd.Note),
+		Status:      domain.ChallengeStatusOpen,
+		ConsumerID:  cmd.ConsumerID,
+		Moderation:  moderation,
+	}
+
+	err = cs.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+		var err error
+		challenge, err = repo.CreateChallenge(challenge)
+		return err
+	})
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+
+	cs.logger.Info(ctx, "challenge posted", ports.String("challenge_id", challenge.PublicID.String()), ports.String("challenger_id", challenge.ChallengerID.String()))
+
+	return challenge, nil
+}
+
+func (cs *ChallengeServicer) FindChallenge(ctx context.Context, cmd commands.FindChallengeCommand) (domain.Challenge, error) {
+	var challenge domain.Challenge
+	err := cs.challenges.ReadTx(func(repo ports.ChallengeRepository) error {
+		var err error
+		challenge, err = repo.FindChallenge(cmd.ID)
+		return err
+	})
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+
+	challenge.Status = challenge.StatusAt(cs.now())
+	return challenge, nil
+}
+
+func (cs *ChallengeServicer) ListChallenges(ctx context.Context, cmd commands.ListChallengesCommand) ([]domain.Challenge, error) {
+	var open []domain.Challenge
+	err := cs.challenges.ReadTx(func(repo ports.ChallengeRepository) error {
+		var err error
+		open, err = repo.ListChallenges(domain.ChallengeFilter{Status: domain.ChallengeStatusOpen, Moderation: domain.ModerationApproved})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.PlayerID != uuid.Nil {
+		if err := cs.players.ReadTx(func(repo ports.PlayerRepository) error {
+			_, err := repo.FindPlayer(cmd.PlayerID)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	radius := cmd.RadiusKm
+	if radius == 0 {
+		radius = commands.DefaultSearchRadiusKm
+	}
+
+	now := cs.now()
+	challenges := make([]domain.Challenge, 0, len(open))
+	for _, challenge := range open {
+		if challenge.StatusAt(now) != domain.ChallengeStatusOpen {
+			continue
+		}
+		if cmd.Category != "" && challenge.TimeControl.Category() != cmd.Category {
+			continue
+		}
+		if cmd.Latitude != nil && cmd.Longitude != nil && !challenge.Reaches(*cmd.Latitude, *cmd.Longitude, radius) {
+			continue
+		}
+
+		if cmd.PlayerID != uuid.Nil {
+			if challenge.ChallengerID == cmd.PlayerID {
+				continue
+			}
+			if challenge.HasRatingRange() {
+				rating, err := cs.rating(ctx, cmd.PlayerID, challenge.RatingPool())
+				if err != nil {
+					return nil, err
+				}
+				if !challenge.AdmitsRating(rating) {
+					continue
+				}
+			}
+		}
+
+		if cmd.MinRating > 0 || cmd.MaxRating > 0 {
+			rating, err := cs.rating(ctx, challenge.ChallengerID, challenge.RatingPool())
+			if err != nil {
+				return nil, err
+			}
+			if (cmd.MinRating > 0 && rating < cmd.MinRating) || (cmd.MaxRating > 0 && rating > cmd.MaxRating) {
+				continue
+			}
+		}
+
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, nil
+}
+
+// AcceptChallenge checks and closes the challenge in the same transaction the match is
+// created in, a second player accepting it waits for the first and finds it closed
+func (cs *ChallengeServicer) AcceptChallenge(ctx context.Context, cmd commands.AcceptChallengeCommand) (domain.Challenge, error) {
+	if err := cs.players.ReadTx(func(repo ports.PlayerRepository) error {
+		_, err := repo.FindPlayer(cmd.PlayerID)
+		return err
+	}); err != nil {
+		return domain.Challenge{}, err
+	}
+
+	// the rating is read before the challenge is locked, the pool does not change
+	challenge, err := cs.FindChallenge(ctx, commands.FindChallengeCommand{ID: cmd.ID})
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+	var rating int
+	if challenge.HasRatingRange() && challenge.Status == domain.ChallengeStatusOpen {
+		rating, err = cs.rating(ctx, cmd.PlayerID, challenge.RatingPool())
+		if err != nil {
+			return domain.Challenge{}, err
+		}
+	}
+
+	err = cs.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+		current, err := repo.FindChallenge(cmd.ID)
+		if err != nil {
+			return err
+		}
+		now := cs.now()
+		if err := current.CanAccept(cmd.PlayerID, rating, now); err != nil {
+			return err
+		}
+
+		match, err := cs.createMatch(current, cmd.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		current.Status = domain.ChallengeStatusAccepted
+		current.AcceptedBy = cmd.PlayerID
+		current.MatchID = match.UUID
+		current.AcceptedAt = now
+		challenge, err = repo.UpdateChallenge(current)
+		if err == nil {
+			// both players are told from the outbox once the challenge is stored
+			err = repo.RecordEvents(domain.ChallengeAccepted{
+				ChallengeID:  challenge.PublicID,
+				MatchID:      challenge.MatchID,
+				ChallengerID: challenge.ChallengerID,
+				AcceptedBy:   challenge.AcceptedBy,
+				At:           now,
+			})
+		}
+		if err != nil {
+			// the match is committed on its own, it goes with the challenge that failed
+			return errors.Join(err, cs.deleteMatch(match.UUID))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+
+	cs.logger.Info(ctx, "challenge accepted",
+		ports.String("challenge_id", challenge.PublicID.String()),
+		ports.String("player_id", cmd.PlayerID.String()),
+		ports.String("match_id", challenge.MatchID.String()),
+	)
+
+	return challenge, nil
+}
+
+// createMatch creates the casual match of the challenge at its location, the colours are
+// drawn by lot
+func (cs *ChallengeServicer) createMatch(challenge domain.Challenge, opponentID uuid.UUID) (domain.Match, error) {
+	white, black := challenge.ChallengerID, opponentID
+	if rand.IntN(2) == 0 {
+		white, black = black, white
+	}
+
+	match := domain.Match{
+		Location:    challenge.Location,
+		City:        challenge.Location.City,
+		Country:     challenge.Location.Country,
+		Rated:       challenge.Rated,
+		RatingType:  challenge.RatingPool(),
+		TimeControl: challenge.TimeControl,
+		WhitePlayer: white,
+		BlackPlayer: black,
+		Result:      domain.MatchResultOngoing,
+	}
+
+	err := cs.matches.WriteTx(func(repo ports.MatchRepository) error {
+		var err error
+		match, err = repo.CreateMatch(match)
+		return err
+	})
+
+	return match, err
+}
+
+// deleteMatch removes the match of an acceptance that could not be stored
+func (cs *ChallengeServicer) deleteMatch(id uuid.UUID) error {
+	return cs.matches.WriteTx(func(repo ports.MatchRepository) error {
+		return repo.DeleteMatch(id)
+	})
+}
+
+func (cs *ChallengeServicer) CancelChallenge(ctx context.Context, cmd commands.CancelChallengeCommand) (domain.Challenge, error) {
+	var challenge domain.Challenge
+	err := cs.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+		current, err := repo.FindChallenge(cmd.ID)
+		if err != nil {
+			return err
+		}
+		if current.ChallengerID != cmd.PlayerID {
+			return domain.ErrChallengeNotOwner
+		}
+		now := cs.now()
+		if current.StatusAt(now) != domain.ChallengeStatusOpen {
+			return domain.ErrChallengeNotOpen
+		}
+
+		current.Status = domain.ChallengeStatusCancelled
+		current.CancelledAt = now
+		challenge, err = repo.UpdateChallenge(current)
+		return err
+	})
+	if err != nil {
+		return domain.Challenge{}, err
+	}
+
+	cs.logger.Info(ctx, "challenge cancelled", ports.String("challenge_id", challenge.PublicID.String()))
+
+	return challenge, nil
+}
+
+// ExpireDue stores the challenges whose window is over as expired and returns how many
+func (cs *ChallengeServicer) ExpireDue(ctx context.Context) (int, error) {
+	expired := 0
+	err := cs.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+		open, err := repo.ListChallenges(domain.ChallengeFilter{Status: domain.ChallengeStatusOpen})
+		if err != nil {
+			return err
+		}
+
+		now := cs.now()
+		for _, challenge := range open {
+			if challenge.StatusAt(now) != domain.ChallengeStatusExpired {
+				continue
+			}
+			challenge.Status = domain.ChallengeStatusExpired
+			if _, err := repo.UpdateChallenge(challenge); err != nil {
+				return err
+			}
+			expired++
+		}
+		return nil
+	})
+
+	return expired, err
+}
+
+// Start expires the challenges until ctx is done or Stop is called
+func (cs *ChallengeServicer) Start(ctx context.Context) {
+	cs.lifecycle.Lock()
+	defer cs.lifecycle.Unlock()
+
+	if cs.started {
+		return
+	}
+	cs.stop = make(chan struct{})
+	cs.done = make(chan struct{})
+	cs.started = true
+
+	go cs.run(ctx, cs.stop, cs.done)
+}
+
+// Stop waits for the expiry in progress to finish
+func (cs *ChallengeServicer) Stop() {
+	cs.lifecycle.Lock()
+	defer cs.lifecycle.Unlock()
+
+	if !cs.started {
+		return
+	}
+	close(cs.stop)
+	<-cs.done
+	cs.started = false
+}
+
+func (cs *ChallengeServicer) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(cs.config.ExpireInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := cs.ExpireDue(ctx)
+			if err != nil {
+				cs.logger.Error(ctx, "expiring challenges failed", ports.Error("error", err))
+				continue
+			}
+			if expired > 0 {
+				cs.logger.Debug(ctx, "challenges expired", ports.Int("expired", expired))
+			}
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// rating returns the rating of the player in the pool, the initial one before their first game
+func (cs *ChallengeServicer) rating(ctx context.Context, playerID uuid.UUID, pool domain.RatingType) (int, error) {
+	rating, err := cs.ratings.FindRating(ctx, ratingcommands.FindRatingCommand{PlayerID: playerID, Pool: pool})
+	if err != nil {
+		return 0, err
+	}
+	return rating.Rounded(), nil
+}
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	matchcommands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+type challengeFixture struct {
+	cs      *ChallengeServicer
+	ps      ports.PlayerServicer
+	ms      ports.MatchServicer
+	matches ports.MatchRepositoryProvider
+	ratings ports.RatingRepositoryProvider
+	outbox  ports.OutboxRepositoryProvider
+	now     *time.Time
+}
+
+func newChallengeFixture(t *testing.T) *challengeFixture {
+	t.Helper()
+
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	ratings := inmemory.NewRatingRepositoryProvider(inmemory.NewInMemoryRatingRepository())
+	challenges := inmemory.NewChallengeRepositoryProvider(inmemory.NewInMemoryChallengeRepository(), outbox)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	rs, err := NewRatingServicer(lggr, NewEloCalculator(DefaultEloConfig()), ratings, players, matches)
+	if err != nil {
+		t.Fatalf("error creating rating service: %v", err)
+	}
+	ms, err := NewMatchServicer(lggr, matches, players, tournaments, nil)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+
+	f := &challengeFixture{
+		cs:      NewChallengeServicer(lggr, challenges, players, matches, rs, nil, DefaultChallengeConfig()),
+		ps:      ps,
+		ms:      ms,
+		matches: matches,
+		ratings: ratings,
+		outbox:  outbox,
+	}
+	now := time.Date(2026, 6, 12, 17, 0, 0, 0, time.UTC)
+	f.now = &now
+	f.cs.now = func() time.Time { return *f.now }
+
+	return f
+}
+
+// post has the challenger play blitz at Plaça de Catalunya this evening
+func (f *challengeFixture) post(t *testing.T, challenger domain.Player, change func(*commands.CreateChallengeCommand)) domain.Challenge {
+	t.Helper()
+
+	cmd := commands.CreateChallengeCommand{
+		ChallengerID: challenger.PublicID,
+		LocationName: "Plaça de Catalunya",
+		City:         "Barcelona",
+		Latitude:     41.3870,
+		Longitude:    2.1701,
+		StartsAt:     f.now.Add(time.Hour),
+		EndsAt:       f.now.Add(4 * time.Hour),
+		TimeControl:  "5+3",
+		Rated:        true,
+	}
+	if change != nil {
+		change(&cmd)
+	}
+	if err := cmd.Validate(); err != nil {
+		t.Fatalf("invalid challenge: %v", err)
+	}
+
+	challenge, err := f.cs.CreateChallenge(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("error creating challenge: %v", err)
+	}
+	return challenge
+}
+
+func (f *challengeFixture) setRating(t *testing.T, player domain.Player, pool domain.RatingType, rating float64) {
+	t.Helper()
+	err := f.ratings.WriteTx(func(repo ports.RatingRepository) error {
+		_, err := repo.SaveRating(domain.MapleRating{PlayerID: player.PublicID, Pool: pool, Rating: rating})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error saving rating: %v", err)
+	}
+}
+
+func TestChallengeServicer_Accept(t *testing.T) {
+	f := newChallengeFixture(t)
+	ctx := context.Background()
+	challenger := createTestPlayer(t, f.ps, "challenger")
+	opponent := createTestPlayer(t, f.ps, "opponent")
+	late := createTestPlayer(t, f.ps, "late")
+
+	challenge := f.post(t, challenger, nil)
+	if challenge.Status != domain.ChallengeStatusOpen {
+		t.Fatalf("expected an open challenge, got %s", challenge.Status)
+	}
+
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: challenge.PublicID, PlayerID: challenger.PublicID}); !errors.Is(err, domain.ErrChallengeOwn) {
+		t.Errorf("expected ErrChallengeOwn, got %v", err)
+	}
+
+	accepted, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: challenge.PublicID, PlayerID: opponent.PublicID})
+	if err != nil {
+		t.Fatalf("error accepting challenge: %v", err)
+	}
+	if accepted.Status != domain.ChallengeStatusAccepted || accepted.AcceptedBy != opponent.PublicID || !accepted.AcceptedAt.Equal(*f.now) {
+		t.Errorf("unexpected challenge %+v", accepted)
+	}
+
+	match, err := f.ms.FindMatch(ctx, matchcommands.FindMatchCommand{ID: accepted.MatchID})
+	if err != nil {
+		t.Fatalf("error finding the match of the challenge: %v", err)
+	}
+	if !match.Between(challenger.PublicID, opponent.PublicID) || !match.Rated || match.RatingType != domain.RatingTypeBlitz || match.TimeControl.String() != "5+3" {
+		t.Errorf("unexpected match %+v", match)
+	}
+	if match.Location.Name != "Plaça de Catalunya" || match.City != "Barcelona" {
+		t.Errorf("expected the match at the location of the challenge, got %+v", match.Location)
+	}
+
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: challenge.PublicID, PlayerID: late.PublicID}); !errors.Is(err, domain.ErrChallengeNotOpen) {
+		t.Errorf("expected ErrChallengeNotOpen for the second player, got %v", err)
+	}
+	if _, err := f.cs.CancelChallenge(ctx, commands.CancelChallengeCommand{ID: challenge.PublicID, PlayerID: challenger.PublicID}); !errors.Is(err, domain.ErrChallengeNotOpen) {
+		t.Errorf("expected an accepted challenge not to be cancelled, got %v", err)
+	}
+
+	// both players are told from the outbox
+	var entries []domain.OutboxEntry
+	err = f.outbox.ReadTx(func(repo ports.OutboxRepository) error {
+		var err error
+		entries, err = repo.Pending(0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error reading outbox: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(entries))
+	}
+	event, ok := entries[0].Event.(domain.ChallengeAccepted)
+	if !ok || event.MatchID != match.UUID || event.ChallengerID != challenger.PublicID || event.AcceptedBy != opponent.PublicID {
+		t.Errorf("unexpected event %+v", entries[0].Event)
+	}
+}
+
+// failingUpdates is a challenge store that cannot save the challenges
+type failingUpdates struct {
+	ports.ChallengeRepositoryProvider
+}
+
+func (f failingUpdates) WriteTx(fn func(ports.ChallengeRepository) error) error {
+	return f.ChallengeRepositoryProvider.WriteTx(func(repo ports.ChallengeRepository) error {
+		return fn(failingUpdate{repo})
+	})
+}
+
+type failingUpdate struct {
+	ports.ChallengeRepository
+}
+
+func (failingUpdate) UpdateChallenge(domain.Challenge) (domain.Challenge, error) {
+	return domain.Challenge{}, errors.New("disk full")
+}
+
+func TestChallengeServicer_AcceptFailureDeletesMatch(t *testing.T) {
+	f := newChallengeFixture(t)
+	ctx := context.Background()
+	challenger := createTestPlayer(t, f.ps, "challenger")
+	opponent := createTestPlayer(t, f.ps, "opponent")
+	challenge := f.post(t, challenger, nil)
+
+	f.cs.challenges = failingUpdates{f.cs.challenges}
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: challenge.PublicID, PlayerID: opponent.PublicID}); err == nil {
+		t.Fatal("expected the acceptance to fail")
+	}
+
+	err := f.matches.ReadTx(func(repo ports.MatchRepository) error {
+		matches, err := repo.ListMatchesByPlayer(opponent.PublicID)
+		if err != nil {
+			return err
+		}
+		if len(matches) != 0 {
+			t.Errorf("expected the match of the failed acceptance to be deleted, got %d", len(matches))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error listing matches: %v", err)
+	}
+}
+
+func TestChallengeServicer_ConcurrentAccepts(t *testing.T) {
+	f := newChallengeFixture(t)
+	ctx := context.Background()
+	challenger := createTestPlayer(t, f.ps, "challenger")
+	challenge := f.post(t, challenger, nil)
+
+	const accepters = 8
+	players := make([]domain.Player, accepters)
+	for i := range players {
+		players[i] = createTestPlayer(t, f.ps, "accepter"+string(rune('a'+i)))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		winners  []domain.Challenge
+		conflict int
+	)
+	start := make(chan struct{})
+	for _, player := range players {
+		wg.Add(1)
+		go func(player domain.Player) {
+			defer wg.Done()
+			<-start
+			accepted, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: challenge.PublicID, PlayerID: player.PublicID})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				winners = append(winners, accepted)
+			case errors.Is(err, domain.ErrChallengeNotOpen):
+				conflict++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(player)
+	}
+	close(start)
+	wg.Wait()
+
+	if len(winners) != 1 || conflict != accepters-1 {
+		t.Fatalf("expected a single winner, got %d winners and %d conflicts", len(winners), conflict)
+	}
+
+	// a single match was created, for the winner
+	matches, err := f.ms.ListMatches(ctx, matchcommands.ListMatchesCommand{PlayerID: challenger.PublicID})
+	if err != nil {
+		t.Fatalf("error listing matches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].UUID != winners[0].MatchID || !matches[0].Between(challenger.PublicID, winners[0].AcceptedBy) {
+		t.Errorf("expected the match of the winner only, got %+v", matches)
+	}
+
+	stored, err := f.cs.FindChallenge(ctx, commands.FindChallengeCommand{ID: challenge.PublicID})
+	if err != nil {
+		t.Fatalf("error finding challenge: %v", err)
+	}
+	if stored.AcceptedBy != winners[0].AcceptedBy || stored.MatchID != winners[0].MatchID {
+		t.Errorf("expected the winner to be stored, got %+v", stored)
+	}
+}
+
+func TestChallengeServicer_CancelAndExpire(t *testing.T) {
+	f := newChallengeFixture(t)
+	ctx := context.Background()
+	challenger := createTestPlayer(t, f.ps, "challenger")
+	opponent := createTestPlayer(t, f.ps, "opponent")
+
+	cancelled := f.post(t, challenger, nil)
+	if _, err := f.cs.CancelChallenge(ctx, commands.CancelChallengeCommand{ID: cancelled.PublicID, PlayerID: opponent.PublicID}); !errors.Is(err, domain.ErrChallengeNotOwner) {
+		t.Errorf("expected ErrChallengeNotOwner, got %v", err)
+	}
+	cancelled, err := f.cs.CancelChallenge(ctx, commands.CancelChallengeCommand{ID: cancelled.PublicID, PlayerID: challenger.PublicID})
+	if err != nil {
+		t.Fatalf("error cancelling challenge: %v", err)
+	}
+	if cancelled.Status != domain.ChallengeStatusCancelled || cancelled.CancelledAt.IsZero() {
+		t.Errorf("unexpected challenge %+v", cancelled)
+	}
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: cancelled.PublicID, PlayerID: opponent.PublicID}); !errors.Is(err, domain.ErrChallengeNotOpen) {
+		t.Errorf("expected a cancelled challenge not to be accepted, got %v", err)
+	}
+
+	expiring := f.post(t, challenger, nil)
+	*f.now = expiring.EndsAt
+
+	// expired as soon as the window is over, before the background expiry ran
+	found, err := f.cs.FindChallenge(ctx, commands.FindChallengeCommand{ID: expiring.PublicID})
+	if err != nil || found.Status != domain.ChallengeStatusExpired {
+		t.Errorf("expected the challenge to be expired, got %s, %v", found.Status, err)
+	}
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: expiring.PublicID, PlayerID: opponent.PublicID}); !errors.Is(err, domain.ErrChallengeNotOpen) {
+		t.Errorf("expected an expired challenge not to be accepted, got %v", err)
+	}
+	listed, err := f.cs.ListChallenges(ctx, commands.ListChallengesCommand{})
+	if err != nil || len(listed) != 0 {
+		t.Errorf("expected no open challenge, got %d, %v", len(listed), err)
+	}
+
+	expired, err := f.cs.ExpireDue(ctx)
+	if err != nil || expired != 1 {
+		t.Fatalf("expected 1 challenge expired, got %d, %v", expired, err)
+	}
+	if expired, _ := f.cs.ExpireDue(ctx); expired != 0 {
+		t.Errorf("expected nothing left to expire, got %d", expired)
+	}
+
+	if _, err := f.cs.CreateChallenge(ctx, commands.CreateChallengeCommand{
+		ChallengerID: challenger.PublicID,
+		Latitude:     41.38,
+		Longitude:    2.17,
+		StartsAt:     f.now.Add(-2 * time.Hour),
+		EndsAt:       f.now.Add(-time.Hour),
+		TimeControl:  "5+3",
+	}); !errors.Is(err, domain.ErrChallengeWindowPassed) {
+		t.Errorf("expected ErrChallengeWindowPassed, got %v", err)
+	}
+}
+
+func TestChallengeServicer_List(t *testing.T) {
+	f := newChallengeFixture(t)
+	ctx := context.Background()
+	strong := createTestPlayer(t, f.ps, "strong")
+	weak := createTestPlayer(t, f.ps, "weak")
+	browser := createTestPlayer(t, f.ps, "browser")
+	f.setRating(t, strong, domain.RatingTypeBlitz, 2100)
+	f.setRating(t, weak, domain.RatingTypeBlitz, 1300)
+	f.setRating(t, browser, domain.RatingTypeBlitz, 1650)
+
+	// in the city centre, for the players between 1800 and 2200
+	central := f.post(t, strong, func(cmd *commands.CreateChallengeCommand) {
+		cmd.MinRating = 1800
+		cmd.MaxRating = 2200
+	})
+	// in Sabadell, 20 km away, but the challenger travels 15 km
+	sabadell := f.post(t, weak, func(cmd *commands.CreateChallengeCommand) {
+		cmd.LocationName = "Casino de Sabadell"
+		cmd.City = "Sabadell"
+		cmd.Latitude = 41.5463
+		cmd.Longitude = 2.1086
+		cmd.RadiusKm = 15
+	})
+	// in Girona, far from everyone
+	f.post(t, weak, func(cmd *commands.CreateChallengeCommand) {
+		cmd.City = "Girona"
+		cmd.Latitude = 41.9794
+		cmd.Longitude = 2.8214
+	})
+
+	ids := func(challenges []domain.Challenge) map[string]bool {
+		found := make(map[string]bool)
+		for _, c := range challenges {
+			found[c.PublicID.String()] = true
+		}
+		return found
+	}
+	latitude, longitude := 41.3870, 2.1701
+
+	tests := []struct {
+		name string
+		cmd  commands.ListChallengesCommand
+		want []domain.Challenge
+	}{
+		{name: "near the player", cmd: commands.ListChallengesCommand{Latitude: &latitude, Longitude: &longitude, RadiusKm: 10}, want: []domain.Challenge{central, sabadell}},
+		{name: "too far for the challenger", cmd: commands.ListChallengesCommand{Latitude: &latitude, Longitude: &longitude, RadiusKm: 2}, want: []domain.Challenge{central}},
+		{name: "the player can accept", cmd: commands.ListChallengesCommand{Latitude: &latitude, Longitude: &longitude, PlayerID: browser.PublicID}, want: []domain.Challenge{sabadell}},
+		{name: "not their own", cmd: commands.ListChallengesCommand{Latitude: &latitude, Longitude: &longitude, PlayerID: weak.PublicID}, want: []domain.Challenge{}},
+		{name: "strong challengers", cmd: commands.ListChallengesCommand{MinRating: 2000}, want: []domain.Challenge{central}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cmd.Validate(); err != nil {
+				t.Fatalf("invalid command: %v", err)
+			}
+			got, err := f.cs.ListChallenges(ctx, tt.cmd)
+			if err != nil {
+				t.Fatalf("error listing challenges: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d challenges, got %d", len(tt.want), len(got))
+			}
+			found := ids(got)
+			for _, c := range tt.want {
+				if !found[c.PublicID.String()] {
+					t.Errorf("expected %s to be listed", c.Location.City)
+				}
+			}
+		})
+	}
+
+	if _, err := f.cs.AcceptChallenge(ctx, commands.AcceptChallengeCommand{ID: central.PublicID, PlayerID: browser.PublicID}); !errors.Is(err, domain.ErrChallengeRatingOutOfRange) {
+		t.Errorf("expected ErrChallengeRatingOutOfRange, got %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var ErrSubscriberExists = errors.New("a subscriber with this name already exists")
+
+// EventDispatcherConfig - how often the outbox is read, how failed deliveries are retried and
+// how long the entries are kept once dispatched or dead. Nothing is pruned when Retention or
+// PruneInterval is zero
+type EventDispatcherConfig struct {
+	PollInterval  time.Duration
+	BatchSize     int // entries read from the outbox per poll
+	MaxAttempts   int // failed deliveries before an entry is given up on
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+	Retention     time.Duration
+	PruneInterval time.Duration
+}
+
+func DefaultEventDispatcherConfig() EventDispatcherConfig {
+	return EventDispatcherConfig{
+		PollInterval:  500 * time.Millisecond,
+		BatchSize:     100,
+		MaxAttempts:   8,
+		BaseBackoff:   time.Second,
+		MaxBackoff:    5 * time.Minute,
+		Retention:     24 * time.Hour,
+		PruneInterval: 10 * time.Minute,
+	}
+}
+
+type subscriber struct {
+	name    string
+	types   map[domain.EventType]bool // nil for every type
+	handler ports.EventHandler
+}
+
+func (s subscriber) wants(eventType domain.EventType) bool {
+	return s.types == nil || s.types[eventType]
+}
+
+// EventDispatcher is the in process event bus, it delivers the events of the outbox to the
+// subscribers. Events of the same aggregate are delivered in order: while one waits for a
+// retry the ones recorded after it wait too. Events of different aggregates are delivered
+// concurrently
+type EventDispatcher struct {
+	logger ports.Logger
+	outbox ports.OutboxRepositoryProvider
+	config EventDispatcherConfig
+	now    func() time.Time
+
+	mu          sync.RWMutex
+	subscribers []subscriber
+
+	dispatching sync.Mutex // one DispatchPending at a time so no entry is delivered twice
+	lifecycle   sync.Mutex
+	started     bool
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func NewEventDispatcher(log ports.Logger, outbox ports.OutboxRepositoryProvider, config EventDispatcherConfig) *EventDispatcher {
+	return &EventDispatcher{
+		logger: log,
+		outbox: outbox,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Subscribe registers the handler under a unique name for the event types, every type when none is given.
+// The name is what the outbox remembers deliveries by, it must not change between restarts
+func (ed *EventDispatcher) Subscribe(name string, handler ports.EventHandler, types ...domain.EventType) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	for _, s := range ed.subscribers {
+		if s.name == name {
+			return fmt.Errorf("%w: %s", ErrSubscriberExists, name)
+		}
+	}
+
+	sub := subscriber{name: name, handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[domain.EventType]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	ed.subscribers = append(ed.subscribers, sub)
+
+	return nil
+}
+
+// Start polls the outbox until ctx is done or Stop is called
+func (ed *EventDispatcher) Start(ctx context.Context) {
+	ed.lifecycle.Lock()
+	defer ed.lifecycle.Unlock()
+
+	if ed.started {
+		return
+	}
+	ed.stop = make(chan struct{})
+	ed.done = make(chan struct{})
+	ed.started = true
+
+	go ed.run(ctx, ed.stop, ed.done)
+}
+
+// Stop waits for the deliveries in progress to finish
+func (ed *EventDispatcher) Stop() {
+	ed.lifecycle.Lock()
+	defer ed.lifecycle.Unlock()
+
+	if !ed.started {
+		return
+	}
+	close(ed.stop)
+	<-ed.done
+	ed.started = false
+}
+
+func (ed *EventDispatcher) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ed.config.PollInterval)
+	defer ticker.Stop()
+
+	var prune <-chan time.Time // never fires when nothing is pruned
+	if ed.config.Retention > 0 && ed.config.PruneInterval > 0 {
+		pruneTicker := time.NewTicker(ed.config.PruneInterval)
+		defer pruneTicker.Stop()
+		prune = pruneTicker.C
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := ed.DispatchPending(ctx); err != nil {
+				ed.logger.Error(ctx, "dispatching events failed", ports.Error("error", err))
+			}
+		case <-prune:
+			if _, err := ed.Prune(ctx); err != nil {
+				ed.logger.Error(ctx, "pruning the outbox failed", ports.Error("error", err))
+			}
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Prune removes the entries of the outbox dispatched or given up on longer than the retention
+// ago and returns how many
+func (ed *EventDispatcher) Prune(ctx context.Context) (int, error) {
+	var pruned int
+	err := ed.outbox.WriteTx(func(repo ports.OutboxRepository) error {
+		var err error
+		pruned, err = repo.Prune(ed.now().Add(-ed.config.Retention))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if pruned > 0 {
+		ed.logger.Debug(ctx, "outbox pruned", ports.Int("entries", pruned))
+	}
+	return pruned, nil
+}
+
+// DispatchPending delivers the entries of the outbox that are due and returns how many were dispatched
+func (ed *EventDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	ed.dispatching.Lock()
+	defer ed.dispatching.Unlock()
+
+	var entries []domain.OutboxEntry
+	err := ed.outbox.ReadTx(func(repo ports.OutboxRepository) error {
+		var err error
+		entries, err = repo.Due(ed.now(), ed.config.BatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// group by aggregate keeping the order of the outbox
+	var order []uuid.UUID
+	byAggregate := make(map[uuid.UUID][]domain.OutboxEntry)
+	for _, entry := range entries {
+		id := entry.Event.AggregateID()
+		if _, ok := byAggregate[id]; !ok {
+			order = append(order, id)
+		}
+		byAggregate[id] = append(byAggregate[id], entry)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		dispatched int
+	)
+	for _, id := range order {
+		wg.Add(1)
+		go func(entries []domain.OutboxEntry) {
+			defer wg.Done()
+			n := ed.dispatchAggregate(ctx, entries)
+
+			mu.Lock()
+			dispatched += n
+			mu.Unlock()
+		}(byAggregate[id])
+	}
+	wg.Wait()
+
+	return dispatched, nil
+}
+
+// dispatchAggregate delivers the entries of one aggregate in order, stopping at the first one
+// that is not delivered
+func (ed *EventDispatcher) dispatchAggregate(ctx context.Context, entries []domain.OutboxEntry) int {
+	dispatched := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return dispatched
+		}
+		if !ed.deliver(ctx, entry) {
+			return dispatched
+		}
+		dispatched++
+	}
+	return dispatched
+}
+
+// deliver calls the subscribers that have not handled the entry yet, it returns false when
+// the entry has to be delivered again later
+func (ed *EventDispatcher) deliver(ctx context.Context, entry domain.OutboxEntry) bool {
+	delivered := make(map[string]bool, len(entry.Delivered))
+	for _, name := range entry.Delivered {
+		delivered[name] = true
+	}
+
+	ed.mu.RLock()
+	subscribers := append([]subscriber(nil), ed.subscribers...)
+	ed.mu.RUnlock()
+
+	var failures []string
+	for _, sub := range subscribers {
+		if delivered[sub.name] || !sub.wants(entry.Event.EventType()) {
+			continue
+		}
+
+		if err := ed.call(ctx, sub, entry); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+			continue
+		}
+
+		err := ed.outbox.WriteTx(func(repo ports.OutboxRepository) error {
+			return repo.MarkDelivered(entry.ID, sub.name)
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+		}
+	}
+
+	now := ed.now()
+	if len(failures) == 0 {
+		if err := ed.outbox.WriteTx(func(repo ports.OutboxRepository) error {
+			return repo.MarkDispatched(entry.ID, now)
+		}); err != nil {
+			ed.logger.Error(ctx, "marking event as dispatched failed", ports.Error("error", err))
+			return false
+		}
+		return true
+	}
+
+	reason := strings.Join(failures, "; ")
+	attempts := entry.Attempts + 1
+	fields := []ports.LogField{
+		ports.String("event", string(entry.Event.EventType())),
+		ports.String("event_id", entry.EventID.String()),
+		ports.String("aggregate_id", entry.Event.AggregateID().String()),
+		ports.Int("attempts", attempts),
+		ports.String("reason", reason),
+	}
+
+	// given up on, the aggregate is not held back by it any longer
+	if attempts >= ed.config.MaxAttempts {
+		ed.logger.Error(ctx, "event delivery abandoned", fields...)
+		if err := ed.outbox.WriteTx(func(repo ports.OutboxRepository) error {
+			return repo.MarkDead(entry.ID, reason, now)
+		}); err != nil {
+			ed.logger.Error(ctx, "marking event as dead failed", ports.Error("error", err))
+			return false
+		}
+		return true
+	}
+
+	ed.logger.Warn(ctx, "event delivery failed, retrying", fields...)
+	if err := ed.outbox.WriteTx(func(repo ports.OutboxRepository) error {
+		return repo.MarkFailed(entry.ID, reason, now.Add(backoff(ed.config.BaseBackoff, ed.config.MaxBackoff, attempts)))
+	}); err != nil {
+		ed.logger.Error(ctx, "marking event as failed failed", ports.Error("error", err))
+	}
+	return false
+}
+
+// call runs the handler, a panic is a failed delivery
+func (ed *EventDispatcher) call(ctx context.Context, sub subscriber, entry domain.OutboxEntry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler(context.WithValue(ctx, eventIDKey{}, entry.EventID), entry.Event)
+}
+
+type eventIDKey struct{}
+
+// EventIDFromContext returns the id the outbox gave to the event being handled, it is the
+// same on every delivery of the event so subscribers can use it to deduplicate
+func EventIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(eventIDKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// backoff doubles the wait from base after every failed attempt up to max
+func backoff(base, max time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	return min(d, max)
+}
+
+// HandleEvent adapts a handler of one type of event, the others are ignored. Subscribe it
+// for the type of E so it is not called for nothing
+func HandleEvent[E domain.Event](handle func(ctx context.Context, event E) error) ports.EventHandler {
+	return func(ctx context.Context, event domain.Event) error {
+		e, ok := event.(E)
+		if !ok {
+			return nil
+		}
+		return handle(ctx, e)
+	}
+}
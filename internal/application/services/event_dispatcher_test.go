@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// recorder is a subscriber that remembers the events it handled and fails on demand
+type recorder struct {
+	mu     sync.Mutex
+	events []domain.Event
+	fail   func(domain.Event) error
+}
+
+func (r *recorder) handle(_ context.Context, event domain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail != nil {
+		if err := r.fail(event); err != nil {
+			return err
+		}
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) handled() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Event(nil), r.events...)
+}
+
+func newTestDispatcher(t *testing.T) (*EventDispatcher, ports.OutboxRepositoryProvider, *time.Time) {
+	t.Helper()
+
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	dispatcher := NewEventDispatcher(lggr, outbox, EventDispatcherConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    100,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		Retention:    time.Hour,
+	})
+
+	// the outbox stamps the entries with the wall clock, the dispatcher only moves forward from it
+	now := time.Now().Add(time.Minute)
+	dispatcher.now = func() time.Time { return now }
+
+	return dispatcher, outbox, &now
+}
+
+func appendEvents(t *testing.T, outbox ports.OutboxRepositoryProvider, events ...domain.Event) {
+	t.Helper()
+	err := outbox.WriteTx(func(repo ports.OutboxRepository) error {
+		return repo.Append(events...)
+	})
+	if err != nil {
+		t.Fatalf("error appending events: %v", err)
+	}
+}
+
+func pendingEntries(t *testing.T, outbox ports.OutboxRepositoryProvider) []domain.OutboxEntry {
+	t.Helper()
+	var entries []domain.OutboxEntry
+	err := outbox.ReadTx(func(repo ports.OutboxRepository) error {
+		var err error
+		entries, err = repo.Pending(0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error reading outbox: %v", err)
+	}
+	return entries
+}
+
+func TestEventDispatcher_OrderPerAggregate(t *testing.T) {
+	dispatcher, outbox, now := newTestDispatcher(t)
+
+	a, b := uuid.New(), uuid.New()
+	failOnce := true
+	rec := &recorder{fail: func(event domain.Event) error {
+		if event.AggregateID() == a && failOnce {
+			failOnce = false
+			return errors.New("unavailable")
+		}
+		return nil
+	}}
+	if err := dispatcher.Subscribe("recorder", rec.handle); err != nil {
+		t.Fatalf("error subscribing: %v", err)
+	}
+
+	appendEvents(t, outbox,
+		domain.TournamentCreated{TournamentID: a, Name: "A"},
+		domain.TournamentCreated{TournamentID: b, Name: "B"},
+		domain.TournamentCompleted{TournamentID: a},
+	)
+
+	dispatched, err := dispatcher.DispatchPending(context.Background())
+	if err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+	if dispatched != 1 {
+		t.Fatalf("expected only the event of b to be dispatched, got %d", dispatched)
+	}
+
+	// the first event of a waits for its retry and the second waits behind it
+	if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+	if got := len(rec.handled()); got != 1 {
+		t.Fatalf("expected no delivery before the backoff, got %d events", got)
+	}
+
+	entries := pendingEntries(t, outbox)
+	if len(entries) != 2 || entries[0].Attempts != 1 || entries[0].LastError == "" {
+		t.Fatalf("expected the failed entry to be scheduled for a retry, got %+v", entries)
+	}
+
+	*now = now.Add(time.Second)
+	dispatched, err = dispatcher.DispatchPending(context.Background())
+	if err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+	if dispatched != 2 {
+		t.Fatalf("expected both events of a to be dispatched, got %d", dispatched)
+	}
+
+	events := rec.handled()
+	if _, ok := events[1].(domain.TournamentCreated); !ok {
+		t.Errorf("expected TournamentCreated of a before its TournamentCompleted, got %T", events[1])
+	}
+	if _, ok := events[2].(domain.TournamentCompleted); !ok {
+		t.Errorf("expected TournamentCompleted last, got %T", events[2])
+	}
+	if len(pendingEntries(t, outbox)) != 0 {
+		t.Errorf("expected the outbox to be empty")
+	}
+}
+
+func TestEventDispatcher_BackoffDoesNotStarveTheOthers(t *testing.T) {
+	dispatcher, outbox, _ := newTestDispatcher(t)
+	dispatcher.config.BatchSize = 2
+
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	rec := &recorder{fail: func(event domain.Event) error {
+		if event.AggregateID() != c {
+			return errors.New("unavailable")
+		}
+		return nil
+	}}
+	if err := dispatcher.Subscribe("recorder", rec.handle); err != nil {
+		t.Fatalf("error subscribing: %v", err)
+	}
+
+	appendEvents(t, outbox,
+		domain.TournamentCreated{TournamentID: a, Name: "A"},
+		domain.TournamentCreated{TournamentID: b, Name: "B"},
+		domain.TournamentCompleted{TournamentID: a},
+	)
+	if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+
+	// a and b wait for their retry at the head of the outbox, the batch goes past them
+	appendEvents(t, outbox, domain.TournamentCreated{TournamentID: c, Name: "C"})
+	dispatched, err := dispatcher.DispatchPending(context.Background())
+	if err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+	if dispatched != 1 {
+		t.Fatalf("expected the event of c to be dispatched, got %d", dispatched)
+	}
+	if events := rec.handled(); len(events) != 1 || events[0].AggregateID() != c {
+		t.Errorf("expected only the event of c to be handled, got %+v", events)
+	}
+	if got := len(pendingEntries(t, outbox)); got != 3 {
+		t.Errorf("expected the events of a and b to still be pending, got %d", got)
+	}
+}
+
+func TestEventDispatcher_RetriesOnlyFailedSubscribers(t *testing.T) {
+	dispatcher, outbox, now := newTestDispatcher(t)
+
+	ok := &recorder{}
+	failing := &recorder{fail: func(domain.Event) error { return errors.New("unavailable") }}
+	_ = dispatcher.Subscribe("ok", ok.handle)
+	_ = dispatcher.Subscribe("failing", failing.handle)
+
+	appendEvents(t, outbox, domain.TournamentCreated{TournamentID: uuid.New()})
+
+	for range 2 {
+		if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("error dispatching: %v", err)
+		}
+		*now = now.Add(time.Hour)
+	}
+
+	if got := len(ok.handled()); got != 1 {
+		t.Errorf("expected the subscriber that succeeded to be called once, got %d", got)
+	}
+	if entries := pendingEntries(t, outbox); len(entries) != 1 || entries[0].Attempts != 2 {
+		t.Errorf("expected the entry to still be pending after 2 attempts, got %+v", entries)
+	}
+}
+
+func TestEventDispatcher_DeadLetter(t *testing.T) {
+	dispatcher, outbox, now := newTestDispatcher(t)
+
+	a := uuid.New()
+	rec := &recorder{fail: func(event domain.Event) error {
+		if _, ok := event.(domain.TournamentCreated); ok {
+			panic("broken subscriber")
+		}
+		return nil
+	}}
+	_ = dispatcher.Subscribe("recorder", rec.handle)
+
+	appendEvents(t, outbox,
+		domain.TournamentCreated{TournamentID: a},
+		domain.TournamentCompleted{TournamentID: a},
+	)
+
+	for range 3 {
+		if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+			t.Fatalf("error dispatching: %v", err)
+		}
+		*now = now.Add(time.Hour)
+	}
+
+	// abandoned after 3 attempts, the next event of the aggregate goes through
+	events := rec.handled()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event delivered, got %d", len(events))
+	}
+	if _, ok := events[0].(domain.TournamentCompleted); !ok {
+		t.Errorf("expected TournamentCompleted to be delivered, got %T", events[0])
+	}
+	if len(pendingEntries(t, outbox)) != 0 {
+		t.Errorf("expected the outbox to be empty")
+	}
+}
+
+func TestEventDispatcher_Prune(t *testing.T) {
+	dispatcher, outbox, now := newTestDispatcher(t)
+	ctx := context.Background()
+
+	a, b := uuid.New(), uuid.New()
+	rec := &recorder{fail: func(event domain.Event) error {
+		if event.AggregateID() == b {
+			return errors.New("unavailable")
+		}
+		return nil
+	}}
+	_ = dispatcher.Subscribe("recorder", rec.handle)
+
+	appendEvents(t, outbox, domain.TournamentCreated{TournamentID: a}, domain.TournamentCreated{TournamentID: b})
+	if _, err := dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+
+	// kept for the retention once dispatched
+	if pruned, err := dispatcher.Prune(ctx); err != nil || pruned != 0 {
+		t.Fatalf("expected nothing to be pruned yet, got %d, %v", pruned, err)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	if pruned, err := dispatcher.Prune(ctx); err != nil || pruned != 1 {
+		t.Fatalf("expected the dispatched entry to be pruned, got %d, %v", pruned, err)
+	}
+	// the one waiting for a retry stays
+	if entries := pendingEntries(t, outbox); len(entries) != 1 || entries[0].Event.AggregateID() != b {
+		t.Errorf("expected the pending entry to stay, got %+v", entries)
+	}
+}
+
+func TestEventDispatcher_Subscribe(t *testing.T) {
+	dispatcher, outbox, _ := newTestDispatcher(t)
+
+	var created []domain.TournamentCreated
+	err := dispatcher.Subscribe("created", HandleEvent(func(_ context.Context, e domain.TournamentCreated) error {
+		created = append(created, e)
+		return nil
+	}), domain.EventTournamentCreated)
+	if err != nil {
+		t.Fatalf("error subscribing: %v", err)
+	}
+
+	if err := dispatcher.Subscribe("created", func(context.Context, domain.Event) error { return nil }); !errors.Is(err, ErrSubscriberExists) {
+		t.Errorf("expected ErrSubscriberExists, got %v", err)
+	}
+
+	appendEvents(t, outbox,
+		domain.TournamentCreated{TournamentID: uuid.New(), Name: "Open"},
+		domain.TournamentCompleted{TournamentID: uuid.New()},
+	)
+	if _, err := dispatcher.DispatchPending(context.Background()); err != nil {
+		t.Fatalf("error dispatching: %v", err)
+	}
+
+	if len(created) != 1 || created[0].Name != "Open" {
+		t.Errorf("expected the typed handler to get the TournamentCreated event only, got %+v", created)
+	}
+}
+
+func TestEventDispatcher_Start(t *testing.T) {
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	dispatcher := NewEventDispatcher(lggr, outbox, EventDispatcherConfig{PollInterval: 5 * time.Millisecond, BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	delivered := make(chan domain.Event, 1)
+	_ = dispatcher.Subscribe("channel", func(_ context.Context, event domain.Event) error {
+		delivered <- event
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	dispatcher.Start(ctx)
+	defer dispatcher.Stop()
+
+	appendEvents(t, outbox, domain.TournamentCreated{TournamentID: uuid.New()})
+
+	select {
+	case <-delivered:
+	case <-ctx.Done():
+		t.Fatal("the event was not delivered")
+	}
+}
+
+func TestOutbox_WrittenWithTheTransaction(t *testing.T) {
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), outbox)
+
+	// a failed transaction records nothing
+	err := repo.WriteTx(func(r ports.TournamentRepository) error {
+		_ = r.RecordEvents(domain.TournamentCreated{TournamentID: uuid.New()})
+		return errors.New("rolled back")
+	})
+	if err == nil {
+		t.Fatal("expected the transaction to fail")
+	}
+	if entries := pendingEntries(t, outbox); len(entries) != 0 {
+		t.Fatalf("expected no events from a failed transaction, got %d", len(entries))
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), outbox), nil, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+
+	tournament, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Spring Open", TimeControl: "90+30"})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	entries := pendingEntries(t, outbox)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 event in the outbox, got %d", len(entries))
+	}
+	created, ok := entries[0].Event.(domain.TournamentCreated)
+	if !ok || created.TournamentID != tournament.PublicID || created.TimeControl != "90+30" {
+		t.Errorf("unexpected event in the outbox: %+v", entries[0].Event)
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// EventMetrics counts the business events, it is subscribed to the event bus so the services
+// raising them know nothing about the metrics. The events are counted once they are
+// delivered, a little after the change is committed
+type EventMetrics struct {
+	events        ports.Counter
+	tournaments   ports.Counter
+	registrations ports.Counter
+	results       ports.Counter
+}
+
+func NewEventMetrics(m ports.Metrics) *EventMetrics {
+	return &EventMetrics{
+		events:        m.Counter("maple_domain_events_total", "Domain events delivered.", "event"),
+		tournaments:   m.Counter("maple_tournaments_created_total", "Tournaments created."),
+		registrations: m.Counter("maple_registrations_total", "Players registered in tournaments."),
+		results:       m.Counter("maple_results_recorded_total", "Results of matches recorded."),
+	}
+}
+
+func (em *EventMetrics) HandleEvent(ctx context.Context, event domain.Event) error {
+	em.events.Inc(string(event.EventType()))
+
+	switch event.EventType() {
+	case domain.EventTournamentCreated:
+		em.tournaments.Inc()
+	case domain.EventPlayerRegistered:
+		em.registrations.Inc()
+	case domain.EventResultRecorded:
+		em.results.Inc()
+	}
+	return nil
+}
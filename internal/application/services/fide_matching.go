@@ -0,0 +1,132 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// fideMinimumScore is the lowest score a fide record needs to be offered as a candidate
+const fideMinimumScore = 0.8
+
+// fideNameScore compares a maple player's name with a fide "Surname, Firstname" name.
+// Both the full name and the sorted tokens are compared so "Maria Garcia Lopez" still
+// matches "Garcia Lopez, Maria" and "Lopez, Maria Garcia"
+func fideNameScore(firstName, lastName, fideName string) float64 {
+	surname, given := fideName, ""
+	if i := strings.Index(fideName, ","); i >= 0 {
+		surname, given = fideName[:i], fideName[i+1:]
+	}
+
+	player := domain.NormalizeName(lastName + " " + firstName)
+	fide := domain.NormalizeName(surname + " " + given)
+
+	return max(jaroWinkler(player, fide), jaroWinkler(sortedTokens(player), sortedTokens(fide)))
+}
+
+// fideCandidateScore is the name score with a small bonus for every detail that also agrees
+func fideCandidateScore(player domain.Player, record domain.FideRecord) float64 {
+	score := fideNameScore(player.FirstName, player.LastName, record.Name)
+
+	federation := player.FIDE.Federation
+	if federation == "" {
+		federation = player.Regional.Country
+	}
+	if federation != "" && strings.EqualFold(federation, record.Federation) {
+		score += 0.05
+	}
+	if player.FIDE.BirthYear > 0 && player.FIDE.BirthYear == record.BirthYear {
+		score += 0.05
+	}
+
+	return min(score, 1)
+}
+
+// rankFideCandidates scores the records against the player and returns the best limit ones
+func rankFideCandidates(player domain.Player, records []domain.FideRecord, limit int) []domain.FideMatchCandidate {
+	candidates := make([]domain.FideMatchCandidate, 0)
+	for _, record := range records {
+		score := fideCandidateScore(player, record)
+		if score >= fideMinimumScore {
+			candidates = append(candidates, domain.FideMatchCandidate{Record: record, Score: score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Record.FideID < candidates[j].Record.FideID
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates
+}
+
+func sortedTokens(s string) string {
+	tokens := strings.Fields(s)
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1
+func jaroWinkler(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+			matchedA[i], matchedB[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
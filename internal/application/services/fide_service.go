@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/fide"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// fideImportBatchSize is how many records are written per transaction while importing,
+// the full list has over a million players so holding the lock for all of them is not an option
+const fideImportBatchSize = 1000
+
+// fideDefaultCandidates is how many candidates are returned when the command does not say
+const fideDefaultCandidates = 5
+
+// fideImportTimeout is how long an import of a rating list has from being queued
+const fideImportTimeout = time.Hour
+
+type FideServicer struct {
+	logger      ports.Logger
+	reader      ports.FideListReader
+	fide        ports.FideRepositoryProvider
+	players     ports.PlayerRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+	imports     *importJobs[commands.ImportRatingListCommand, domain.FideImportSummary]
+}
+
+// NewFideServicer - the rating lists are imported on the low lane of wp
+func NewFideServicer(log ports.Logger, reader ports.FideListReader, fr ports.FideRepositoryProvider, pr ports.PlayerRepositoryProvider, tr ports.TournamentRepositoryProvider, wp *WorkerPool) (ports.FideServicer, error) {
+	fs := &FideServicer{
+		logger:      log,
+		reader:      reader,
+		fide:        fr,
+		players:     pr,
+		tournaments: tr,
+	}
+
+	var err error
+	if fs.imports, err = newImportJobs(wp, "fide.ImportRatingList", fs.importRatingList, fideImportTimeout); err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+// ImportRatingList queues the import of the rating list, the job it returns tells how it goes
+// with FindImport
+func (fs *FideServicer) ImportRatingList(ctx context.Context, cmd commands.ImportRatingListCommand) (domain.ImportJob[domain.FideImportSummary], error) {
+	return fs.imports.queue(ctx, cmd)
+}
+
+// FindImport returns the job of an import of a rating list, it is kept for a day once finished
+func (fs *FideServicer) FindImport(ctx context.Context, cmd commands.FindImportCommand) (domain.ImportJob[domain.FideImportSummary], error) {
+	return fs.imports.find(cmd.ID)
+}
+
+// importRatingList streams the rating list into the repository, records that are already
+// stored for the period are replaced so importing the same list twice is harmless
+func (fs *FideServicer) importRatingList(ctx context.Context, cmd commands.ImportRatingListCommand) (domain.FideImportSummary, error) {
+	summary := domain.FideImportSummary{Period: cmd.Period}
+	file := domain.FideListFile{
+		Path:             cmd.Path,
+		Format:           cmd.Format,
+		SingleRatingType: cmd.RatingType,
+	}
+
+	batch := make([]domain.FideRecord, 0, fideImportBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := fs.fide.WriteTx(func(repo ports.FideRepository) error {
+			for _, record := range batch {
+				inserted, err := repo.UpsertRecord(record)
+				if err != nil {
+					return err
+				}
+				if inserted {
+					summary.Inserted++
+				} else {
+					summary.Updated++
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	err := fs.reader.Read(ctx, file, func(record domain.FideRecord) error {
+		summary.Read++
+		if record.FideID <= 0 || strings.TrimSpace(record.Name) == "" {
+			summary.Skipped++
+			return nil
+		}
+
+		record.Period = cmd.Period
+		batch = append(batch, record)
+		if len(batch) == fideImportBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return summary, err
+	}
+
+	fs.logger.Info(ctx, "fide rating list imported",
+		ports.String("period", summary.Period),
+		ports.Int("read", summary.Read),
+		ports.Int("inserted", summary.Inserted),
+		ports.Int("updated", summary.Updated),
+		ports.Int("skipped", summary.Skipped),
+	)
+
+	return summary, nil
+}
+
+func (fs *FideServicer) FindRecord(ctx context.Context, cmd commands.FindRecordCommand) (domain.FideRecord, error) {
+	var result domain.FideRecord
+	err := fs.fide.ReadTx(func(repo ports.FideRepository) error {
+		var err error
+		result, err = repo.FindRecord(cmd.FideID, cmd.Period)
+		return err
+	})
+	if err != nil {
+		return domain.FideRecord{}, err
+	}
+
+	return result, nil
+}
+
+// MatchCandidates looks for the player in the latest rating list. Only records whose
+// surname starts like one of the player's surnames are scored, see domain.FideNameKey
+func (fs *FideServicer) MatchCandidates(ctx context.Context, cmd commands.MatchCandidatesCommand) ([]domain.FideMatchCandidate, error) {
+	player, err := fs.findPlayer(cmd.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := cmd.Limit
+	if limit == 0 {
+		limit = fideDefaultCandidates
+	}
+
+	var records []domain.FideRecord
+	err = fs.fide.ReadTx(func(repo ports.FideRepository) error {
+		period, err := repo.LatestPeriod()
+		if err != nil || period == "" {
+			return err
+		}
+
+		seen := make(map[int]struct{})
+		for _, key := range fideNameKeys(player.LastName) {
+			found, err := repo.ListRecordsByNameKey(key, period)
+			if err != nil {
+				return err
+			}
+			for _, record := range found {
+				if _, ok := seen[record.FideID]; ok {
+					continue
+				}
+				seen[record.FideID] = struct{}{}
+				records = append(records, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rankFideCandidates(player, records, limit), nil
+}
+
+// ConfirmMatch links the player to the fide id and copies the official details of the latest record
+func (fs *FideServicer) ConfirmMatch(ctx context.Context, cmd commands.ConfirmMatchCommand) (domain.Player, error) {
+	var record domain.FideRecord
+	err := fs.fide.ReadTx(func(repo ports.FideRepository) error {
+		var err error
+		record, err = repo.FindRecord(cmd.FideID, "")
+		return err
+	})
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	var result domain.Player
+	err = fs.players.WriteTx(func(repo ports.PlayerRepository) error {
+		linked, err := repo.FindPlayerByFideID(cmd.FideID)
+		switch {
+		case err == nil && linked.PublicID != cmd.PlayerID:
+			return domain.ErrFideAlreadyLinked
+		case err != nil && !errors.Is(err, domain.ErrPlayerNotFound):
+			return err
+		}
+
+		player, err := repo.FindPlayer(cmd.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		applyFideRecord(&player, record)
+		result, err = repo.UpdatePlayer(player)
+		return err
+	})
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	return result, nil
+}
+
+// RefreshTournamentRatings moves every linked entrant to the ratings of the period and records
+// the changes in their fide rating history. Entrants that are not linked or not on the list are
+// left alone, the number of players refreshed is returned
+func (fs *FideServicer) RefreshTournamentRatings(ctx context.Context, cmd commands.RefreshTournamentRatingsCommand) (int, error) {
+	var tournament domain.Tournament
+	err := fs.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournament, err = repo.FindTournament(cmd.TournamentID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	period := cmd.Period
+	err = fs.fide.ReadTx(func(repo ports.FideRepository) error {
+		if period == "" {
+			var err error
+			period, err = repo.LatestPeriod()
+			if err != nil {
+				return err
+			}
+		}
+		ok, err := repo.HasPeriod(period)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrFidePeriodNotImported
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	recordedAt := time.Now()
+	eventName := fmt.Sprintf("FIDE rating list %s", period)
+	for _, playerID := range tournament.Players {
+		player, err := fs.findPlayer(playerID)
+		if err != nil {
+			return refreshed, err
+		}
+		if player.FIDE.ID == 0 {
+			continue
+		}
+
+		var record domain.FideRecord
+		err = fs.fide.ReadTx(func(repo ports.FideRepository) error {
+			var err error
+			record, err = repo.FindRecord(player.FIDE.ID, period)
+			return err
+		})
+		if errors.Is(err, domain.ErrFideRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return refreshed, err
+		}
+
+		err = fs.players.WriteTx(func(repo ports.PlayerRepository) error {
+			// read again inside the transaction so a concurrent update is not overwritten
+			player, err := repo.FindPlayer(playerID)
+			if err != nil {
+				return err
+			}
+
+			previous := player.FIDE.Ratings
+			applyFideRecord(&player, record)
+			if _, err := repo.UpdatePlayer(player); err != nil {
+				return err
+			}
+
+			for _, rt := range domain.RatingTypes {
+				if previous.Get(rt) == record.Ratings.Get(rt) {
+					continue
+				}
+				_, err := repo.AddRatingChange(domain.RatingChange{
+					PlayerID:   player.PublicID,
+					Source:     domain.RatingSourceFIDE,
+					Type:       rt,
+					EventID:    tournament.PublicID,
+					EventName:  eventName,
+					Previous:   previous.Get(rt),
+					Rating:     record.Ratings.Get(rt),
+					RecordedAt: recordedAt,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+func (fs *FideServicer) findPlayer(id uuid.UUID) (domain.Player, error) {
+	var result domain.Player
+	err := fs.players.ReadTx(func(repo ports.PlayerRepository) error {
+		var err error
+		result, err = repo.FindPlayer(id)
+		return err
+	})
+
+	return result, err
+}
+
+// applyFideRecord copies the official details of the record onto the player
+func applyFideRecord(player *domain.Player, record domain.FideRecord) {
+	player.FIDE.ID = record.FideID
+	player.FIDE.Federation = record.Federation
+	player.FIDE.BirthYear = record.BirthYear
+	player.FIDE.Title = record.Title
+	player.FIDE.URL = record.ProfileURL()
+	player.FIDE.Ratings = record.Ratings
+}
+
+// fideNameKeys returns the name keys of every surname, spanish and catalan players
+// usually have two and fide lists them in any order
+func fideNameKeys(lastName string) []string {
+	keys := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, surname := range strings.Fields(domain.NormalizeName(lastName)) {
+		key := domain.FideNameKey(surname)
+		if _, ok := seen[key]; ok || key == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/fide"
+	playercommands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// stubListReader serves the records of the period named in the file path
+type stubListReader struct {
+	lists map[string][]domain.FideRecord
+}
+
+func (sr stubListReader) Read(ctx context.Context, file domain.FideListFile, fn func(domain.FideRecord) error) error {
+	records, ok := sr.lists[file.Path]
+	if !ok {
+		return domain.ErrImportFileNotFound
+	}
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fideTestEnv struct {
+	fs          ports.FideServicer
+	ps          ports.PlayerServicer
+	players     ports.PlayerRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+}
+
+func newTestFideServicer(t *testing.T) fideTestEnv {
+	t.Helper()
+
+	reader := stubListReader{lists: map[string][]domain.FideRecord{
+		"2026-09": {
+			{FideID: 2201, Name: "Garcia Lopez, Maria", Federation: "ESP", Sex: "F", BirthYear: 1990, Ratings: domain.Ratings{Standard: 2050}},
+			{FideID: 2202, Name: "Garcia, Marc", Federation: "ESP", BirthYear: 1985, Ratings: domain.Ratings{Standard: 1900}},
+			{FideID: 0, Name: "Missing, Id"},
+		},
+		"2026-10": {
+			{FideID: 2201, Name: "Garcia Lopez, Maria", Federation: "ESP", Sex: "F", Title: "WFM", BirthYear: 1990, Ratings: domain.Ratings{Standard: 2075, Rapid: 2010}},
+			{FideID: 2202, Name: "Garcia, Marc", Federation: "ESP", BirthYear: 1985, Ratings: domain.Ratings{Standard: 1900}},
+			{FideID: 2203, Name: "Puig, Jordi", Federation: "AND", BirthYear: 2001, Ratings: domain.Ratings{Standard: 1750}},
+		},
+	}}
+
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	fides := inmemory.NewFideRepositoryProvider(inmemory.NewInMemoryFideRepository())
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	wp := NewWorkerPool(context.Background(), lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	t.Cleanup(wp.Stop)
+	fs, err := NewFideServicer(lggr, reader, fides, players, tournaments, wp)
+	if err != nil {
+		t.Fatalf("error creating fide service: %v", err)
+	}
+
+	return fideTestEnv{fs: fs, ps: ps, players: players, tournaments: tournaments}
+}
+
+func (env fideTestEnv) importPeriod(t *testing.T, period string) domain.FideImportSummary {
+	t.Helper()
+
+	job := env.importFile(t, period, period)
+	if job.Status != domain.ImportJobDone {
+		t.Fatalf("error importing %s: %s", period, job.Error)
+	}
+
+	return job.Summary
+}
+
+// importFile queues the import and waits for it to finish
+func (env fideTestEnv) importFile(t *testing.T, path, period string) domain.ImportJob[domain.FideImportSummary] {
+	t.Helper()
+	ctx := context.Background()
+
+	job, err := env.fs.ImportRatingList(ctx, commands.ImportRatingListCommand{
+		Path:   path,
+		Format: domain.FideListFormatXML,
+		Period: period,
+	})
+	if err != nil {
+		t.Fatalf("error queueing the import of %s: %v", path, err)
+	}
+
+	return awaitImport(t, job, func(id uuid.UUID) (domain.ImportJob[domain.FideImportSummary], error) {
+		return env.fs.FindImport(ctx, commands.FindImportCommand{ID: id})
+	})
+}
+
+// awaitImport polls the job until it is finished
+func awaitImport[S any](t *testing.T, job domain.ImportJob[S], find func(id uuid.UUID) (domain.ImportJob[S], error)) domain.ImportJob[S] {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !job.Finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("the import did not finish: %+v", job)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var err error
+		if job, err = find(job.ID); err != nil {
+			t.Fatalf("error finding the import: %v", err)
+		}
+	}
+
+	return job
+}
+
+func (env fideTestEnv) createPlayer(t *testing.T, username, first, last string) domain.Player {
+	t.Helper()
+
+	player, err := env.ps.CreatePlayer(context.Background(), playercommands.CreatePlayerCommand{
+		Username:  username,
+		Email:     username + "@example.com",
+		FirstName: first,
+		LastName:  last,
+		Country:   "ESP",
+	})
+	if err != nil {
+		t.Fatalf("error creating player: %v", err)
+	}
+
+	return player
+}
+
+func TestFideServicer_ImportRatingList(t *testing.T) {
+	env := newTestFideServicer(t)
+
+	first := env.importPeriod(t, "2026-09")
+	if first.Read != 3 || first.Inserted != 2 || first.Skipped != 1 {
+		t.Errorf("unexpected first import summary: %+v", first)
+	}
+
+	// importing the same period again replaces the records
+	again := env.importPeriod(t, "2026-09")
+	if again.Inserted != 0 || again.Updated != 2 {
+		t.Errorf("unexpected second import summary: %+v", again)
+	}
+
+	env.importPeriod(t, "2026-10")
+
+	latest, err := env.fs.FindRecord(context.Background(), commands.FindRecordCommand{FideID: 2201})
+	if err != nil {
+		t.Fatalf("error finding record: %v", err)
+	}
+	if latest.Period != "2026-10" || latest.Ratings.Standard != 2075 {
+		t.Errorf("expected the 2026-10 record, got %+v", latest)
+	}
+
+	old, err := env.fs.FindRecord(context.Background(), commands.FindRecordCommand{FideID: 2201, Period: "2026-09"})
+	if err != nil {
+		t.Fatalf("error finding record: %v", err)
+	}
+	if old.Ratings.Standard != 2050 {
+		t.Errorf("expected the 2026-09 rating 2050, got %d", old.Ratings.Standard)
+	}
+
+	_, err = env.fs.FindRecord(context.Background(), commands.FindRecordCommand{FideID: 9999})
+	if !errors.Is(err, domain.ErrFideRecordNotFound) {
+		t.Errorf("expected ErrFideRecordNotFound, got %v", err)
+	}
+}
+
+func TestFideServicer_ImportJobs(t *testing.T) {
+	env := newTestFideServicer(t)
+
+	done := env.importFile(t, "2026-09", "2026-09")
+	if done.StartedAt.IsZero() || done.FinishedAt.Before(done.StartedAt) || done.Summary.Inserted != 2 {
+		t.Errorf("unexpected finished import: %+v", done)
+	}
+
+	failed := env.importFile(t, "2026-11", "2026-11")
+	if failed.Status != domain.ImportJobFailed || failed.Error != domain.ErrImportFileNotFound.Error() {
+		t.Errorf("expected the import of a missing file to fail, got %+v", failed)
+	}
+
+	_, err := env.fs.FindImport(context.Background(), commands.FindImportCommand{ID: uuid.New()})
+	if !errors.Is(err, domain.ErrImportJobNotFound) {
+		t.Errorf("expected ErrImportJobNotFound, got %v", err)
+	}
+}
+
+func TestFideServicer_MatchCandidates(t *testing.T) {
+	env := newTestFideServicer(t)
+	env.importPeriod(t, "2026-10")
+
+	// accents and the order of the surnames should not matter
+	player := env.createPlayer(t, "maria", "María", "López García")
+
+	candidates, err := env.fs.MatchCandidates(context.Background(), commands.MatchCandidatesCommand{PlayerID: player.PublicID})
+	if err != nil {
+		t.Fatalf("error matching candidates: %v", err)
+	}
+	if len(candidates) == 0 {
+		t.Fatal("expected candidates, got none")
+	}
+	if candidates[0].Record.FideID != 2201 {
+		t.Errorf("expected 2201 as the best candidate, got %d", candidates[0].Record.FideID)
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score > candidates[i-1].Score {
+			t.Errorf("candidates are not sorted by score: %+v", candidates)
+		}
+	}
+	for _, c := range candidates {
+		if c.Record.FideID == 2203 {
+			t.Errorf("unrelated record offered as a candidate: %+v", c)
+		}
+	}
+}
+
+func TestFideServicer_ConfirmMatch(t *testing.T) {
+	env := newTestFideServicer(t)
+	env.importPeriod(t, "2026-10")
+
+	maria := env.createPlayer(t, "maria", "Maria", "Garcia Lopez")
+	other := env.createPlayer(t, "other", "Maria", "Garcia")
+
+	linked, err := env.fs.ConfirmMatch(context.Background(), commands.ConfirmMatchCommand{PlayerID: maria.PublicID, FideID: 2201})
+	if err != nil {
+		t.Fatalf("error confirming match: %v", err)
+	}
+	if linked.FIDE.ID != 2201 || linked.FIDE.Title != "WFM" || linked.FIDE.Ratings.Standard != 2075 {
+		t.Errorf("fide details not copied to the player: %+v", linked.FIDE)
+	}
+	if linked.FIDE.URL != "https://ratings.fide.com/profile/2201" {
+		t.Errorf("unexpected profile url %s", linked.FIDE.URL)
+	}
+
+	// confirming again for the same player is fine, for another one it is not
+	if _, err := env.fs.ConfirmMatch(context.Background(), commands.ConfirmMatchCommand{PlayerID: maria.PublicID, FideID: 2201}); err != nil {
+		t.Errorf("expected confirming twice to succeed, got %v", err)
+	}
+	_, err = env.fs.ConfirmMatch(context.Background(), commands.ConfirmMatchCommand{PlayerID: other.PublicID, FideID: 2201})
+	if !errors.Is(err, domain.ErrFideAlreadyLinked) {
+		t.Errorf("expected ErrFideAlreadyLinked, got %v", err)
+	}
+}
+
+func TestFideServicer_RefreshTournamentRatings(t *testing.T) {
+	env := newTestFideServicer(t)
+	env.importPeriod(t, "2026-09")
+	env.importPeriod(t, "2026-10")
+
+	maria := env.createPlayer(t, "maria", "Maria", "Garcia Lopez")
+	unlinked := env.createPlayer(t, "unlinked", "Pere", "Vila")
+	if _, err := env.fs.ConfirmMatch(context.Background(), commands.ConfirmMatchCommand{PlayerID: maria.PublicID, FideID: 2201}); err != nil {
+		t.Fatalf("error confirming match: %v", err)
+	}
+
+	var tournament domain.Tournament
+	err := env.tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournament, err = repo.CreateTournament(domain.Tournament{
+			Name:    "Open de Sitges",
+			Players: []uuid.UUID{maria.PublicID, unlinked.PublicID},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	// the tournament is rated with the september list
+	refreshed, err := env.fs.RefreshTournamentRatings(context.Background(), commands.RefreshTournamentRatingsCommand{
+		TournamentID: tournament.PublicID,
+		Period:       "2026-09",
+	})
+	if err != nil {
+		t.Fatalf("error refreshing ratings: %v", err)
+	}
+	if refreshed != 1 {
+		t.Errorf("expected 1 player refreshed, got %d", refreshed)
+	}
+
+	history, err := env.ps.RatingHistory(context.Background(), playercommands.RatingHistoryCommand{
+		PlayerID: maria.PublicID,
+		Source:   domain.RatingSourceFIDE,
+		Type:     domain.RatingTypeStandard,
+	})
+	if err != nil {
+		t.Fatalf("error reading rating history: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("expected 1 rating change, got %d", len(history))
+	}
+	change := history[0]
+	if change.Previous != 2075 || change.Rating != 2050 || change.EventID != tournament.PublicID {
+		t.Errorf("unexpected rating change: %+v", change)
+	}
+
+	_, err = env.fs.RefreshTournamentRatings(context.Background(), commands.RefreshTournamentRatingsCommand{
+		TournamentID: tournament.PublicID,
+		Period:       "2025-01",
+	})
+	if !errors.Is(err, domain.ErrFidePeriodNotImported) {
+		t.Errorf("expected ErrFidePeriodNotImported, got %v", err)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dwayne", "duane", 0.840},
+		{"dixon", "dicksonx", 0.813},
+		{"same", "same", 1},
+		{"abc", "", 0},
+	}
+
+	for _, tt := range tests {
+		got := jaroWinkler(tt.a, tt.b)
+		if got < tt.expected-0.001 || got > tt.expected+0.001 {
+			t.Errorf("jaroWinkler(%q, %q): expected %.3f, got %.3f", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
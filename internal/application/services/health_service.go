@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// DefaultHealthTimeout bounds the probes registered without a timeout
+const DefaultHealthTimeout = 2 * time.Second
+
+// HealthRegistry holds the probes of the dependencies, the adapters register theirs when
+// they are wired. The probes of a report run at the same time, each bounded by its timeout,
+// so a report takes as long as the slowest of them
+type HealthRegistry struct {
+	mu     sync.RWMutex
+	checks []ports.HealthCheckConfig
+	system ports.SystemAdapter // nil leaves the build out of the reports
+}
+
+var _ ports.HealthChecker = (*HealthRegistry)(nil)
+
+func NewHealthRegistry(sa ports.SystemAdapter) *HealthRegistry {
+	return &HealthRegistry{system: sa}
+}
+
+func (hr *HealthRegistry) Register(check ports.HealthCheckConfig) {
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultHealthTimeout
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.checks = append(hr.checks, check)
+}
+
+// Liveness runs the liveness probes only
+func (hr *HealthRegistry) Liveness(ctx context.Context) domain.HealthReport {
+	return hr.report(ctx, true)
+}
+
+// Readiness runs every probe
+func (hr *HealthRegistry) Readiness(ctx context.Context) domain.HealthReport {
+	return hr.report(ctx, false)
+}
+
+func (hr *HealthRegistry) report(ctx context.Context, livenessOnly bool) domain.HealthReport {
+	hr.mu.RLock()
+	checks := make([]ports.HealthCheckConfig, 0, len(hr.checks))
+	for _, check := range hr.checks {
+		if check.Liveness || !livenessOnly {
+			checks = append(checks, check)
+		}
+	}
+	hr.mu.RUnlock()
+
+	report := domain.HealthReport{
+		Status: domain.HealthUp,
+		Checks: make([]domain.HealthCheck, len(checks)),
+	}
+	if hr.system != nil {
+		report.System = hr.system.GetSystemInfo()
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = probe(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status != domain.HealthUp {
+			report.Status = domain.HealthDown
+		}
+	}
+
+	return report
+}
+
+// probe runs the probe in its own goroutine, the ones that ignore the context are given up
+// on when their timeout is over and left to return on their own
+func probe(ctx context.Context, check ports.HealthCheckConfig) domain.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("probe panicked: %v", r)
+			}
+		}()
+		done <- check.Probe(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := domain.HealthCheck{Name: check.Name, Status: domain.HealthUp, Latency: time.Since(start)}
+	if err != nil {
+		result.Status = domain.HealthDown
+		result.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("timed out after %s", check.Timeout)
+		}
+	}
+
+	return result
+}
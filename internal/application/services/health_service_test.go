@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	"github.com/ctfrancia/maple/internal/adapters/system"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+func TestHealthRegistry_Reports(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+
+	health := NewHealthRegistry(system.NewSystemAdapter())
+	health.Register(ports.HealthCheckConfig{Name: "worker_pool", Probe: wp.Ping, Liveness: true})
+	health.Register(ports.HealthCheckConfig{Name: "repository.tournaments", Probe: inmemory.Probe(tournaments)})
+	health.Register(ports.HealthCheckConfig{Name: "smtp", Probe: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}})
+
+	live := health.Liveness(ctx)
+	if live.Status != domain.HealthUp || len(live.Checks) != 1 || live.Checks[0].Name != "worker_pool" {
+		t.Errorf("liveness should run the worker pool probe only and be up, got %+v", live)
+	}
+	if live.System.GoVersion == "" {
+		t.Errorf("the report should have the build, got %+v", live.System)
+	}
+
+	ready := health.Readiness(ctx)
+	if ready.Status != domain.HealthDown {
+		t.Errorf("readiness should be down while smtp is, got %s", ready.Status)
+	}
+	want := []domain.HealthStatus{domain.HealthUp, domain.HealthUp, domain.HealthDown}
+	for i, check := range ready.Checks {
+		if check.Status != want[i] {
+			t.Errorf("check %s: expected %s, got %s", check.Name, want[i], check.Status)
+		}
+	}
+	if ready.Checks[2].Error != "connection refused" {
+		t.Errorf("the check should have the error of the probe, got %q", ready.Checks[2].Error)
+	}
+
+	wp.Stop()
+	if live := health.Liveness(ctx); live.Status != domain.HealthDown {
+		t.Errorf("liveness should be down once the pool is stopped, got %+v", live)
+	}
+}
+
+func TestHealthRegistry_Timeout(t *testing.T) {
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+
+	// a transaction holding the write lock for longer than the timeout of the probe
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go tournaments.WriteTx(func(ports.TournamentRepository) error {
+		close(locked)
+		<-release
+		return nil
+	})
+	<-locked
+	defer close(release)
+
+	health := NewHealthRegistry(nil)
+	health.Register(ports.HealthCheckConfig{Name: "repository.tournaments", Probe: inmemory.Probe(tournaments), Timeout: 50 * time.Millisecond})
+	health.Register(ports.HealthCheckConfig{Name: "panicking", Probe: func(ctx context.Context) error {
+		panic("boom")
+	}})
+
+	start := time.Now()
+	report := health.Readiness(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the report should not wait for the locked repository, took %s", elapsed)
+	}
+
+	if report.Status != domain.HealthDown {
+		t.Errorf("expected the report down, got %s", report.Status)
+	}
+	if check := report.Checks[0]; check.Status != domain.HealthDown || !strings.Contains(check.Error, "timed out after 50ms") {
+		t.Errorf("expected the locked repository to time out, got %+v", check)
+	}
+	if check := report.Checks[1]; check.Status != domain.HealthDown || !strings.Contains(check.Error, "boom") {
+		t.Errorf("expected the panic to mark the check down, got %+v", check)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// importJobRetention is how long the status of a finished import is kept
+const importJobRetention = 24 * time.Hour
+
+// importJobs are the imports of a kind run on the low lane of the worker pool. They are kept
+// in memory, a restart forgets them along with the tasks still queued
+type importJobs[Req, S any] struct {
+	task *Task[importJobRequest[Req], S]
+	now  func() time.Time
+
+	mu   sync.Mutex
+	jobs map[uuid.UUID]domain.ImportJob[S]
+}
+
+// importJobRequest is the request of the task of an import along with the job it reports to
+type importJobRequest[Req any] struct {
+	jobID uuid.UUID
+	req   Req
+}
+
+// newImportJobs registers run as the low priority task of the imports named name, timeout is
+// how long an import has from being queued
+func newImportJobs[Req, S any](wp *WorkerPool, name string, run TaskHandler[Req, S], timeout time.Duration) (*importJobs[Req, S], error) {
+	ij := &importJobs[Req, S]{
+		now:  time.Now,
+		jobs: make(map[uuid.UUID]domain.ImportJob[S]),
+	}
+
+	var err error
+	ij.task, err = Register(wp, func(ctx context.Context, req importJobRequest[Req]) (S, error) {
+		ij.update(req.jobID, func(job *domain.ImportJob[S]) {
+			job.Status = domain.ImportJobRunning
+			job.StartedAt = ij.now()
+		})
+		return run(ctx, req.req)
+	}, TaskOptions{Priority: TaskPriorityLow, Timeout: timeout, Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	return ij, nil
+}
+
+// queue submits the import and returns its job. The import outlives ctx, only its values are
+// kept. An import the pool turns away, e.g. when its lane is full, fails with the error of
+// the pool and leaves no job
+func (ij *importJobs[Req, S]) queue(ctx context.Context, req Req) (domain.ImportJob[S], error) {
+	job := domain.ImportJob[S]{
+		ID:       uuid.New(),
+		Status:   domain.ImportJobQueued,
+		QueuedAt: ij.now(),
+	}
+
+	ij.mu.Lock()
+	ij.prune()
+	ij.jobs[job.ID] = job
+	ij.mu.Unlock()
+
+	future := ij.task.Submit(context.WithoutCancel(ctx), importJobRequest[Req]{jobID: job.ID, req: req})
+	select {
+	case <-future.Done():
+		if _, err := future.Await(ctx); err != nil && !ij.started(job.ID) {
+			ij.mu.Lock()
+			delete(ij.jobs, job.ID)
+			ij.mu.Unlock()
+			return domain.ImportJob[S]{}, err
+		}
+	default:
+	}
+
+	go func() {
+		summary, err := future.Await(context.Background())
+		ij.update(job.ID, func(job *domain.ImportJob[S]) {
+			job.Status, job.Summary, job.FinishedAt = domain.ImportJobDone, summary, ij.now()
+			if err != nil {
+				job.Status, job.Error = domain.ImportJobFailed, err.Error()
+			}
+		})
+	}()
+
+	return job, nil
+}
+
+// find returns the job of the import
+func (ij *importJobs[Req, S]) find(id uuid.UUID) (domain.ImportJob[S], error) {
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+
+	job, ok := ij.jobs[id]
+	if !ok {
+		return domain.ImportJob[S]{}, domain.ErrImportJobNotFound
+	}
+	return job, nil
+}
+
+func (ij *importJobs[Req, S]) started(id uuid.UUID) bool {
+	job, err := ij.find(id)
+	return err == nil && job.Status != domain.ImportJobQueued
+}
+
+func (ij *importJobs[Req, S]) update(id uuid.UUID, fn func(job *domain.ImportJob[S])) {
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+
+	if job, ok := ij.jobs[id]; ok {
+		fn(&job)
+		ij.jobs[id] = job
+	}
+}
+
+// prune forgets the jobs finished for longer than importJobRetention, the caller holds mu
+func (ij *importJobs[Req, S]) prune() {
+	for id, job := range ij.jobs {
+		if job.Finished() && ij.now().Sub(job.FinishedAt) > importJobRetention {
+			delete(ij.jobs, id)
+		}
+	}
+}
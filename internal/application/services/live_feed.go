@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// LiveFeed relays the events of the tournaments open to spectators to the live feed, a
+// recorded result is followed by the new standings of the tournament
+type LiveFeed struct {
+	logger      ports.Logger
+	tournaments ports.TournamentRepositoryProvider
+	matches     ports.MatchRepositoryProvider
+	broadcaster ports.LiveBroadcaster
+}
+
+func NewLiveFeed(log ports.Logger, tr ports.TournamentRepositoryProvider, mr ports.MatchRepositoryProvider, broadcaster ports.LiveBroadcaster) *LiveFeed {
+	return &LiveFeed{
+		logger:      log,
+		tournaments: tr,
+		matches:     mr,
+		broadcaster: broadcaster,
+	}
+}
+
+// HandleEvent is the event subscriber of the live feed, the spectators are not worth a retry
+// so publishing errors are only logged
+func (lf *LiveFeed) HandleEvent(ctx context.Context, event domain.Event) error {
+	tournamentID := liveTournamentID(event)
+	if tournamentID == uuid.Nil {
+		return nil
+	}
+
+	var tournament domain.Tournament
+	err := lf.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournament, err = repo.FindTournament(tournamentID)
+		return err
+	})
+	if errors.Is(err, domain.ErrTournamentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !tournament.OpenToSpectators {
+		return nil
+	}
+
+	lf.publish(ctx, domain.LiveUpdate{
+		TournamentID: tournamentID,
+		Type:         string(event.EventType()),
+		Data:         event,
+		At:           event.OccurredAt(),
+	})
+
+	if _, ok := event.(domain.ResultRecorded); !ok {
+		return nil
+	}
+
+	var matches []domain.Match
+	err = lf.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		matches, err = repo.ListMatches(domain.MatchFilter{TournamentID: tournamentID})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	lf.publish(ctx, domain.LiveUpdate{
+		TournamentID: tournamentID,
+		Type:         domain.LiveStandings,
+		Data:         domain.ComputeStandings(tournament.Players, matches),
+		At:           event.OccurredAt(),
+	})
+
+	return nil
+}
+
+func (lf *LiveFeed) publish(ctx context.Context, update domain.LiveUpdate) {
+	if err := lf.broadcaster.Publish(update); err != nil {
+		lf.logger.Warn(ctx, "live update not published", ports.String("type", update.Type), ports.Error("error", err))
+	}
+}
+
+// liveTournamentID is the tournament the event is about, uuid.Nil for casual games
+func liveTournamentID(event domain.Event) uuid.UUID {
+	switch e := event.(type) {
+	case domain.ResultRecorded:
+		return e.TournamentID
+	case domain.PlayerRegistered, domain.RoundPaired, domain.TournamentCompleted:
+		return event.AggregateID()
+	default:
+		return uuid.Nil
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type recordingBroadcaster struct {
+	updates []domain.LiveUpdate
+}
+
+func (rb *recordingBroadcaster) Publish(update domain.LiveUpdate) error {
+	rb.updates = append(rb.updates, update)
+	return nil
+}
+
+func TestLiveFeed_HandleEvent(t *testing.T) {
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	white, black := uuid.New(), uuid.New()
+
+	var open, private domain.Tournament
+	err := tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		if open, err = repo.CreateTournament(domain.Tournament{Name: "Open", OpenToSpectators: true, Players: []uuid.UUID{white, black}}); err != nil {
+			return err
+		}
+		private, err = repo.CreateTournament(domain.Tournament{Name: "Private"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournaments: %v", err)
+	}
+
+	var match domain.Match
+	err = matches.WriteTx(func(repo ports.MatchRepository) error {
+		match, err = repo.CreateMatch(domain.Match{TournamentID: open.PublicID, WhitePlayer: white, BlackPlayer: black, Result: domain.MatchResultDraw})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+
+	broadcaster := &recordingBroadcaster{}
+	feed := NewLiveFeed(lggr, tournaments, matches, broadcaster)
+
+	now := time.Now()
+	events := []domain.Event{
+		domain.RoundPaired{TournamentID: private.PublicID, Round: 1, At: now},
+		domain.ResultRecorded{MatchID: uuid.New(), WhitePlayer: white, BlackPlayer: black, Result: domain.MatchResultDraw, At: now},
+		domain.RoundPaired{TournamentID: open.PublicID, Round: 1, Matches: []uuid.UUID{match.UUID}, At: now},
+		domain.ResultRecorded{MatchID: match.UUID, TournamentID: open.PublicID, WhitePlayer: white, BlackPlayer: black, Result: domain.MatchResultDraw, At: now},
+	}
+	for _, event := range events {
+		if err := feed.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("error handling %s: %v", event.EventType(), err)
+		}
+	}
+
+	// the private tournament and the casual game are not relayed
+	got := broadcaster.updates
+	if len(got) != 3 {
+		t.Fatalf("expected 3 live updates, got %+v", got)
+	}
+	if got[0].Type != string(domain.EventRoundPaired) || got[1].Type != string(domain.EventResultRecorded) || got[2].Type != domain.LiveStandings {
+		t.Errorf("unexpected updates %+v", got)
+	}
+	for _, update := range got {
+		if update.TournamentID != open.PublicID {
+			t.Errorf("expected the updates of %s, got %s", open.PublicID, update.TournamentID)
+		}
+	}
+
+	standings, ok := got[2].Data.([]domain.Standing)
+	if !ok || len(standings) != 2 || standings[0].Points != 0.5 || standings[1].Points != 0.5 {
+		t.Errorf("unexpected standings %+v", got[2].Data)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+const (
+	// locationDuplicateScore is the lowest score two locations need to be offered as duplicates
+	locationDuplicateScore = 0.88
+	// locationSameSpotKm is how close two venues are to count as the same spot
+	locationSameSpotKm = 0.15
+	// locationFarKm is how far apart two venues can be before they are taken as different ones
+	locationFarKm = 1.0
+)
+
+// venueWords are left out of the venue names, "Club d'Escacs Sant Martí" and "C.E. Sant
+// Martí" are the same club
+var venueWords = map[string]bool{
+	"club": true, "ce": true, "cd": true, "ca": true, "ae": true, "cda": true,
+	"escacs": true, "ajedrez": true, "xadrez": true, "chess": true,
+	"d": true, "de": true, "del": true, "la": true, "el": true, "l": true, "les": true, "los": true, "las": true,
+	"i": true, "y": true, "the": true, "and": true,
+}
+
+// streetWords folds the street types written in full and abbreviated in spanish and catalan,
+// the ones mapped to "" are left out
+var streetWords = map[string]string{
+	"c": "street", "cl": "street", "calle": "street", "carrer": "street", "cr": "street",
+	"av": "avenue", "avda": "avenue", "avinguda": "avenue", "avenida": "avenue",
+	"pl": "square", "pza": "square", "placa": "square", "plaza": "square",
+	"pg": "walk", "pso": "walk", "passeig": "walk", "paseo": "walk",
+	"rbla": "rambla", "rb": "rambla",
+	"de": "", "del": "", "d": "", "la": "", "l": "", "dels": "", "n": "", "no": "", "num": "", "numero": "",
+}
+
+// venueName is the normalized name without the words every club has
+func venueName(name string) string {
+	tokens := strings.Fields(domain.NormalizeName(name))
+	kept := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !venueWords[token] {
+			kept = append(kept, token)
+		}
+	}
+	if len(kept) == 0 {
+		// a club called just "Club d'Escacs" keeps its name
+		return strings.Join(tokens, " ")
+	}
+	return strings.Join(kept, " ")
+}
+
+// venueAddress is the normalized address with the street types folded
+func venueAddress(address string) string {
+	tokens := strings.Fields(domain.NormalizeName(address))
+	kept := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		folded, ok := streetWords[token]
+		if !ok {
+			folded = token
+		}
+		if folded != "" {
+			kept = append(kept, folded)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// locationScore compares the names of two locations and adjusts it with what else agrees
+// or not: the address, the city and how far apart they are. It returns the distance too,
+// -1 when either has no coordinates
+func locationScore(a, b domain.Location) (float64, float64) {
+	nameA, nameB := venueName(a.Name), venueName(b.Name)
+	score := max(jaroWinkler(nameA, nameB), jaroWinkler(sortedTokens(nameA), sortedTokens(nameB)))
+
+	if a.Address != "" && b.Address != "" && venueAddress(a.Address) == venueAddress(b.Address) {
+		score += 0.1
+	}
+	if a.City != "" && b.City != "" && domain.NormalizeName(a.City) != domain.NormalizeName(b.City) {
+		score -= 0.3
+	}
+
+	distance := -1.0
+	if a.HasCoordinates() && b.HasCoordinates() {
+		distance = domain.DistanceKm(a.Latitude, a.Longitude, b.Latitude, b.Longitude)
+		switch {
+		case distance <= locationSameSpotKm:
+			score += 0.1
+		case distance > locationFarKm:
+			score -= 0.3
+		}
+	}
+
+	return min(max(score, 0), 1), distance
+}
+
+// findLocationDuplicates scores the candidates against the location and returns the ones
+// that look like the same place, best first. The location itself is skipped
+func findLocationDuplicates(location domain.Location, candidates []domain.Location) []domain.LocationDuplicate {
+	duplicates := make([]domain.LocationDuplicate, 0)
+	for _, candidate := range candidates {
+		if location.Registered() && candidate.PublicID == location.PublicID {
+			continue
+		}
+		score, distance := locationScore(location, candidate)
+		if score >= locationDuplicateScore {
+			duplicates = append(duplicates, domain.LocationDuplicate{Location: candidate, Score: score, DistanceKm: distance})
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].Location.ID < duplicates[j].Location.ID
+	})
+
+	return duplicates
+}
+
+// mergeLocation fills the details the kept location is missing with the duplicate's
+func mergeLocation(kept, duplicate domain.Location) domain.Location {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&kept.Address, duplicate.Address)
+	fill(&kept.PostalCode, duplicate.PostalCode)
+	fill(&kept.State, duplicate.State)
+	fill(&kept.County, duplicate.County)
+	fill(&kept.Province, duplicate.Province)
+	fill(&kept.Country, duplicate.Country)
+	if kept.Timezone == "" {
+		kept.Timezone = duplicate.Timezone
+	}
+	if !kept.HasCoordinates() {
+		kept.Latitude, kept.Longitude = duplicate.Latitude, duplicate.Longitude
+	}
+	if kept.ClubAffil == nil {
+		kept.ClubAffil = duplicate.ClubAffil
+	}
+	return kept
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/location"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// LocationServicer is the registry of venues. Tournaments and matches keep a copy of the
+// location they reference, the copies are re-written when the location is updated or
+// merged into another one. Every change happens inside the locations' WriteTx so a
+// duplicate cannot be registered while another is being merged
+type LocationServicer struct {
+	logger      ports.Logger
+	locations   ports.LocationRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+	matches     ports.MatchRepositoryProvider
+	geocoder    ports.Geocoder // nil when the venues are not geocoded
+}
+
+func NewLocationServicer(log ports.Logger, lr ports.LocationRepositoryProvider, tr ports.TournamentRepositoryProvider, mr ports.MatchRepositoryProvider, geocoder ports.Geocoder) (ports.LocationServicer, error) {
+	return &LocationServicer{
+		logger:      log,
+		locations:   lr,
+		tournaments: tr,
+		matches:     mr,
+		geocoder:    geocoder,
+	}, nil
+}
+
+func (ls *LocationServicer) CreateLocation(ctx context.Context, cmd commands.CreateLocationCommand) (domain.Location, error) {
+	// geocoded before the duplicates are looked for, the coordinates count for them
+	location := ls.locate(ctx, cmd.ToLocation())
+
+	var result domain.Location
+	err := ls.locations.WriteTx(func(repo ports.LocationRepository) error {
+		registered, err := repo.ListLocations(domain.LocationFilter{})
+		if err != nil {
+			return err
+		}
+		if duplicates := findLocationDuplicates(location, registered); len(duplicates) > 0 && !cmd.Force {
+			return domain.ErrLocationDuplicate
+		}
+
+		result, err = repo.CreateLocation(location)
+		return err
+	})
+	if err != nil {
+		return domain.Location{}, err
+	}
+
+	ls.logger.Info(ctx, "location registered", ports.String("location_id", result.PublicID.String()), ports.String("name", result.Name))
+
+	return result, nil
+}
+
+func (ls *LocationServicer) UpdateLocation(ctx context.Context, cmd commands.UpdateLocationCommand) (domain.Location, error) {
+	location := ls.locate(ctx, cmd.ToLocation())
+
+	var result domain.Location
+	err := ls.locations.WriteTx(func(repo ports.LocationRepository) error {
+		stored, err := repo.FindLocation(cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		location.PublicID = stored.PublicID
+		location.ClubAffil = stored.ClubAffil
+		result, err = repo.UpdateLocation(location)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = ls.repoint(result.PublicID, result)
+		return err
+	})
+	if err != nil {
+		return domain.Location{}, err
+	}
+
+	return result, nil
+}
+
+func (ls *LocationServicer) FindLocation(ctx context.Context, cmd commands.FindLocationCommand) (domain.Location, error) {
+	return findLocation(ls.locations, cmd.ID)
+}
+
+func (ls *LocationServicer) ListLocations(ctx context.Context, cmd commands.ListLocationsCommand) ([]domain.Location, error) {
+	var result []domain.Location
+	err := ls.locations.ReadTx(func(repo ports.LocationRepository) error {
+		var err error
+		result, err = repo.ListLocations(domain.LocationFilter{City: cmd.City, Country: cmd.Country, Query: cmd.Query})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (ls *LocationServicer) DeleteLocation(ctx context.Context, cmd commands.DeleteLocationCommand) error {
+	return ls.locations.WriteTx(func(repo ports.LocationRepository) error {
+		if _, err := repo.FindLocation(cmd.ID); err != nil {
+			return err
+		}
+
+		tournaments, matches, err := ls.references(cmd.ID)
+		if err != nil {
+			return err
+		}
+		if len(tournaments) > 0 || len(matches) > 0 {
+			return domain.ErrLocationInUse
+		}
+
+		return repo.DeleteLocation(cmd.ID)
+	})
+}
+
+func (ls *LocationServicer) CheckLocation(ctx context.Context, cmd commands.CreateLocationCommand) ([]domain.LocationDuplicate, error) {
+	return ls.duplicatesOf(cmd.ToLocation())
+}
+
+func (ls *LocationServicer) FindDuplicates(ctx context.Context, cmd commands.FindLocationCommand) ([]domain.LocationDuplicate, error) {
+	location, err := findLocation(ls.locations, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ls.duplicatesOf(location)
+}
+
+func (ls *LocationServicer) MergeLocations(ctx context.Context, cmd commands.MergeLocationsCommand) (domain.LocationMerge, error) {
+	if cmd.ID == cmd.IntoID {
+		return domain.LocationMerge{}, domain.ErrLocationMergeSelf
+	}
+
+	var result domain.LocationMerge
+	err := ls.locations.WriteTx(func(repo ports.LocationRepository) error {
+		duplicate, err := repo.FindLocation(cmd.ID)
+		if err != nil {
+			return err
+		}
+		kept, err := repo.FindLocation(cmd.IntoID)
+		if err != nil {
+			return err
+		}
+
+		kept, err = repo.UpdateLocation(mergeLocation(kept, duplicate))
+		if err != nil {
+			return err
+		}
+
+		result = domain.LocationMerge{Location: kept, Merged: duplicate.PublicID}
+		result.Tournaments, result.Matches, err = ls.repoint(duplicate.PublicID, kept)
+		if err != nil {
+			return err
+		}
+		// the kept location may have got details from the duplicate
+		if _, _, err := ls.repoint(kept.PublicID, kept); err != nil {
+			return err
+		}
+
+		return repo.DeleteLocation(duplicate.PublicID)
+	})
+	if err != nil {
+		return domain.LocationMerge{}, err
+	}
+
+	ls.logger.Info(ctx, "locations merged",
+		ports.String("location_id", result.Location.PublicID.String()),
+		ports.String("merged_id", result.Merged.String()),
+		ports.Int("tournaments", result.Tournaments),
+		ports.Int("matches", result.Matches),
+	)
+
+	return result, nil
+}
+
+func (ls *LocationServicer) Geocode(ctx context.Context, cmd commands.GeocodeCommand) (domain.Place, error) {
+	if ls.geocoder == nil {
+		return domain.Place{}, domain.ErrGeocoderUnavailable
+	}
+
+	return ls.geocoder.Geocode(ctx, cmd.ToQuery())
+}
+
+func (ls *LocationServicer) ReverseGeocode(ctx context.Context, cmd commands.ReverseGeocodeCommand) (domain.Place, error) {
+	if ls.geocoder == nil {
+		return domain.Place{}, domain.ErrGeocoderUnavailable
+	}
+
+	return ls.geocoder.Reverse(ctx, cmd.Latitude, cmd.Longitude)
+}
+
+// locate fills the coordinates and the regions the location is missing. A location found by
+// its address takes the canonical name of its city, one with coordinates keeps it as they
+// may be on the border. The venue is registered all the same when it cannot be geocoded
+func (ls *LocationServicer) locate(ctx context.Context, location domain.Location) domain.Location {
+	if ls.geocoder == nil || (location.HasCoordinates() && location.County != "" && location.Province != "") {
+		return location
+	}
+
+	var place domain.Place
+	var err error
+	if location.HasCoordinates() {
+		place, err = ls.geocoder.Reverse(ctx, location.Latitude, location.Longitude)
+	} else {
+		query := domain.GeocodeQuery{Address: location.Address, PostalCode: location.PostalCode, City: location.City, Province: location.Province}
+		if len(location.Country) == 2 {
+			query.Country = location.Country
+		}
+		place, err = ls.geocoder.Geocode(ctx, query)
+		if err == nil {
+			location.City = place.City
+		}
+	}
+	if err != nil {
+		if !errors.Is(err, domain.ErrGeocodeNotFound) {
+			ls.logger.Warn(ctx, "location geocoding failed", ports.String("name", location.Name), ports.Error("error", err))
+		}
+		return location
+	}
+
+	return place.Fill(location)
+}
+
+// duplicatesOf scores the location against every registered one, the registry is small
+// enough not to need a blocking key
+func (ls *LocationServicer) duplicatesOf(location domain.Location) ([]domain.LocationDuplicate, error) {
+	var registered []domain.Location
+	err := ls.locations.ReadTx(func(repo ports.LocationRepository) error {
+		var err error
+		registered, err = repo.ListLocations(domain.LocationFilter{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return findLocationDuplicates(location, registered), nil
+}
+
+func (ls *LocationServicer) references(id uuid.UUID) ([]domain.Tournament, []domain.Match, error) {
+	var tournaments []domain.Tournament
+	err := ls.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournaments, err = repo.ListTournaments(domain.TournamentFilter{LocationID: id})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matches []domain.Match
+	err = ls.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		matches, err = repo.ListMatches(domain.MatchFilter{LocationID: id})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tournaments, matches, nil
+}
+
+// repoint replaces the copy of the location from kept by the tournaments and matches with
+// location, it returns how many of each were re-written
+func (ls *LocationServicer) repoint(from uuid.UUID, location domain.Location) (int, int, error) {
+	var tournaments, matches int
+	err := ls.tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		found, err := repo.ListTournaments(domain.TournamentFilter{LocationID: from})
+		if err != nil {
+			return err
+		}
+		for _, tournament := range found {
+			tournament.Location = location
+			if _, err := repo.UpdateTournament(tournament); err != nil {
+				return err
+			}
+			tournaments++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = ls.matches.WriteTx(func(repo ports.MatchRepository) error {
+		found, err := repo.ListMatches(domain.MatchFilter{LocationID: from})
+		if err != nil {
+			return err
+		}
+		for _, match := range found {
+			match.Location = location
+			match.City, match.State, match.Country = location.City, location.State, location.Country
+			if _, err := repo.UpdateMatch(match); err != nil {
+				return err
+			}
+			matches++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return tournaments, matches, nil
+}
+
+// findLocation reads a location of the registry, the services linking to it use it too
+func findLocation(locations ports.LocationRepositoryProvider, id uuid.UUID) (domain.Location, error) {
+	if locations == nil {
+		return domain.Location{}, domain.ErrLocationNotFound
+	}
+
+	var result domain.Location
+	err := locations.ReadTx(func(repo ports.LocationRepository) error {
+		var err error
+		result, err = repo.FindLocation(id)
+		return err
+	})
+	if err != nil {
+		return domain.Location{}, err
+	}
+
+	return result, nil
+}
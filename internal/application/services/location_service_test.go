@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/location"
+	matchcommands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+func TestLocationScore(t *testing.T) {
+	santMarti := domain.Location{
+		Name:      "Club d'Escacs Sant Martí",
+		Address:   "C/ Provençals 40",
+		City:      "Barcelona",
+		Latitude:  41.4097,
+		Longitude: 2.2001,
+	}
+
+	tests := []struct {
+		name      string
+		location  domain.Location
+		duplicate bool
+	}{
+		{
+			name:      "abbreviations and accents",
+			location:  domain.Location{Name: "Club Escacs Sant Marti", Address: "Carrer de Provençals, 40", City: "barcelona"},
+			duplicate: true,
+		},
+		{
+			name:      "club initials and the same spot",
+			location:  domain.Location{Name: "C.E. Sant Martí", City: "Barcelona", Latitude: 41.4099, Longitude: 2.2003},
+			duplicate: true,
+		},
+		{
+			name:      "another club of the city",
+			location:  domain.Location{Name: "Club d'Escacs Sants", City: "Barcelona"},
+			duplicate: false,
+		},
+		{
+			name:      "same name in another city",
+			location:  domain.Location{Name: "Club d'Escacs Sant Martí", City: "Girona"},
+			duplicate: false,
+		},
+		{
+			name:      "same name far away",
+			location:  domain.Location{Name: "Club d'Escacs Sant Martí", City: "Barcelona", Latitude: 41.3809, Longitude: 2.1228},
+			duplicate: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := locationScore(santMarti, tt.location)
+			if got := score >= locationDuplicateScore; got != tt.duplicate {
+				t.Errorf("expected duplicate %v, got score %.2f", tt.duplicate, score)
+			}
+		})
+	}
+}
+
+func TestLocationServicer_Duplicates(t *testing.T) {
+	ctx := context.Background()
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	ls, err := NewLocationServicer(lggr, inmemory.NewLocationRepositoryProvider(inmemory.NewInMemoryLocationRepository()), tournaments, matches, nil)
+	if err != nil {
+		t.Fatalf("error creating location service: %v", err)
+	}
+
+	venue := commands.LocationDetails{Name: "Club d'Escacs Sant Martí", Address: "C/ Provençals 40", City: "Barcelona"}
+	if err := (commands.CreateLocationCommand{LocationDetails: venue}).Validate(); err != nil {
+		t.Fatalf("expected a valid command, got %v", err)
+	}
+	first, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: venue})
+	if err != nil {
+		t.Fatalf("error creating location: %v", err)
+	}
+
+	similar := commands.LocationDetails{Name: "Club Escacs Sant Marti", Address: "Carrer de Provençals, 40", City: "Barcelona"}
+	duplicates, err := ls.CheckLocation(ctx, commands.CreateLocationCommand{LocationDetails: similar})
+	if err != nil {
+		t.Fatalf("error checking location: %v", err)
+	}
+	if len(duplicates) != 1 || duplicates[0].Location.PublicID != first.PublicID {
+		t.Fatalf("expected the registered venue as duplicate, got %+v", duplicates)
+	}
+	if duplicates[0].DistanceKm != -1 {
+		t.Errorf("expected no distance without coordinates, got %v", duplicates[0].DistanceKm)
+	}
+
+	_, err = ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: similar})
+	if !errors.Is(err, domain.ErrLocationDuplicate) {
+		t.Fatalf("expected ErrLocationDuplicate, got %v", err)
+	}
+
+	// the organizer knows better
+	second, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: similar, Force: true})
+	if err != nil {
+		t.Fatalf("error forcing location: %v", err)
+	}
+
+	duplicates, err = ls.FindDuplicates(ctx, commands.FindLocationCommand{ID: second.PublicID})
+	if err != nil {
+		t.Fatalf("error finding duplicates: %v", err)
+	}
+	if len(duplicates) != 1 || duplicates[0].Location.PublicID != first.PublicID {
+		t.Errorf("expected the first venue only, got %+v", duplicates)
+	}
+
+	other, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: commands.LocationDetails{Name: "Club d'Escacs Sants", City: "Barcelona"}})
+	if err != nil {
+		t.Fatalf("expected another club to be registered, got %v", err)
+	}
+	listed, err := ls.ListLocations(ctx, commands.ListLocationsCommand{Query: "sants"})
+	if err != nil {
+		t.Fatalf("error listing locations: %v", err)
+	}
+	if len(listed) != 1 || listed[0].PublicID != other.PublicID {
+		t.Errorf("expected only the Sants club, got %+v", listed)
+	}
+}
+
+func TestLocationServicer_Merge(t *testing.T) {
+	ctx := context.Background()
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	locations := inmemory.NewLocationRepositoryProvider(inmemory.NewInMemoryLocationRepository())
+
+	ls, err := NewLocationServicer(lggr, locations, tournaments, matches, nil)
+	if err != nil {
+		t.Fatalf("error creating location service: %v", err)
+	}
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	ms, err := NewMatchServicer(lggr, matches, players, tournaments, locations)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+
+	kept, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: commands.LocationDetails{Name: "Club d'Escacs Sant Martí", City: "Barcelona"}})
+	if err != nil {
+		t.Fatalf("error creating location: %v", err)
+	}
+	duplicate, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{
+		LocationDetails: commands.LocationDetails{Name: "CE Sant Marti", Address: "Carrer de Provençals 40", City: "Barcelona"},
+		Force:           true,
+	})
+	if err != nil {
+		t.Fatalf("error creating duplicate: %v", err)
+	}
+
+	var tournament domain.Tournament
+	err = tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournament, err = repo.CreateTournament(domain.Tournament{Name: "Open", Location: duplicate})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	white := createTestPlayer(t, ps, "white")
+	black := createTestPlayer(t, ps, "black")
+	game, err := ms.CreateMatch(ctx, matchcommands.CreateMatchCommand{
+		WhitePlayer: white.PublicID,
+		BlackPlayer: black.PublicID,
+		TimeControl: "15+10",
+		LocationID:  duplicate.PublicID,
+	})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+	if game.Location.PublicID != duplicate.PublicID || game.City != "Barcelona" {
+		t.Fatalf("expected the match at the registered venue, got %+v", game.Location)
+	}
+
+	err = ls.DeleteLocation(ctx, commands.DeleteLocationCommand{ID: duplicate.PublicID})
+	if !errors.Is(err, domain.ErrLocationInUse) {
+		t.Fatalf("expected ErrLocationInUse, got %v", err)
+	}
+
+	_, err = ls.MergeLocations(ctx, commands.MergeLocationsCommand{ID: kept.PublicID, IntoID: kept.PublicID})
+	if !errors.Is(err, domain.ErrLocationMergeSelf) {
+		t.Fatalf("expected ErrLocationMergeSelf, got %v", err)
+	}
+
+	merge, err := ls.MergeLocations(ctx, commands.MergeLocationsCommand{ID: duplicate.PublicID, IntoID: kept.PublicID})
+	if err != nil {
+		t.Fatalf("error merging locations: %v", err)
+	}
+	if merge.Tournaments != 1 || merge.Matches != 1 {
+		t.Errorf("expected 1 tournament and 1 match re-pointed, got %d and %d", merge.Tournaments, merge.Matches)
+	}
+	// the kept venue learns the address only the duplicate had
+	if merge.Location.Address != "Carrer de Provençals 40" {
+		t.Errorf("expected the address of the duplicate, got %q", merge.Location.Address)
+	}
+
+	if _, err := ls.FindLocation(ctx, commands.FindLocationCommand{ID: duplicate.PublicID}); !errors.Is(err, domain.ErrLocationNotFound) {
+		t.Errorf("expected the duplicate to be removed, got %v", err)
+	}
+
+	err = tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		stored, err := repo.FindTournament(tournament.PublicID)
+		if err != nil {
+			return err
+		}
+		if stored.Location.PublicID != kept.PublicID || stored.Location.Address != "Carrer de Provençals 40" {
+			t.Errorf("expected the tournament at the kept venue, got %+v", stored.Location)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error reading tournament: %v", err)
+	}
+
+	stored, err := ms.FindMatch(ctx, matchcommands.FindMatchCommand{ID: game.UUID})
+	if err != nil {
+		t.Fatalf("error finding match: %v", err)
+	}
+	if stored.Location.PublicID != kept.PublicID {
+		t.Errorf("expected the match at the kept venue, got %+v", stored.Location)
+	}
+}
+
+// stubGeocoder knows a single postal code
+type stubGeocoder struct{}
+
+func (stubGeocoder) Geocode(ctx context.Context, query domain.GeocodeQuery) (domain.Place, error) {
+	if query.PostalCode != "08019" {
+		return domain.Place{}, domain.ErrGeocodeNotFound
+	}
+	return domain.Place{City: "Barcelona", County: "Barcelonès", Province: "Barcelona", State: "Catalonia", Country: "ES", Latitude: 41.4036, Longitude: 2.1936}, nil
+}
+
+func (stubGeocoder) Reverse(ctx context.Context, latitude, longitude float64) (domain.Place, error) {
+	return domain.Place{}, domain.ErrGeocodeNotFound
+}
+
+func TestLocationServicer_Geocoding(t *testing.T) {
+	ctx := context.Background()
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	ls, err := NewLocationServicer(lggr, inmemory.NewLocationRepositoryProvider(inmemory.NewInMemoryLocationRepository()), tournaments, matches, stubGeocoder{})
+	if err != nil {
+		t.Fatalf("error creating location service: %v", err)
+	}
+
+	location, err := ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: commands.LocationDetails{Name: "Club d'Escacs Sant Martí", PostalCode: "08019", City: "BCN"}})
+	if err != nil {
+		t.Fatalf("error creating location: %v", err)
+	}
+	if location.City != "Barcelona" || location.County != "Barcelonès" || location.Country != "ES" || !location.HasCoordinates() {
+		t.Errorf("expected the location to be geocoded, got %+v", location)
+	}
+
+	// not found by the geocoder, registered as given
+	location, err = ls.CreateLocation(ctx, commands.CreateLocationCommand{LocationDetails: commands.LocationDetails{Name: "Casal de Mollerussa", City: "Mollerussa", Country: "Spain"}})
+	if err != nil {
+		t.Fatalf("error creating location: %v", err)
+	}
+	if location.City != "Mollerussa" || location.HasCoordinates() {
+		t.Errorf("expected the location as given, got %+v", location)
+	}
+
+	noGeocoder, err := NewLocationServicer(lggr, inmemory.NewLocationRepositoryProvider(inmemory.NewInMemoryLocationRepository()), tournaments, matches, nil)
+	if err != nil {
+		t.Fatalf("error creating location service: %v", err)
+	}
+	_, err = noGeocoder.Geocode(ctx, commands.GeocodeCommand{City: "Barcelona"})
+	if !errors.Is(err, domain.ErrGeocoderUnavailable) {
+		t.Errorf("expected ErrGeocoderUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type MatchServicer struct {
+	logger      ports.Logger
+	matches     ports.MatchRepositoryProvider
+	players     ports.PlayerRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+	locations   ports.LocationRepositoryProvider
+}
+
+func NewMatchServicer(log ports.Logger, mr ports.MatchRepositoryProvider, pr ports.PlayerRepositoryProvider, tr ports.TournamentRepositoryProvider, lr ports.LocationRepositoryProvider) (ports.MatchServicer, error) {
+	return &MatchServicer{
+		logger:      log,
+		matches:     mr,
+		players:     pr,
+		tournaments: tr,
+		locations:   lr,
+	}, nil
+}
+
+func (ms *MatchServicer) CreateMatch(ctx context.Context, cmd commands.CreateMatchCommand) (domain.Match, error) {
+	err := ms.players.ReadTx(func(repo ports.PlayerRepository) error {
+		if _, err := repo.FindPlayer(cmd.WhitePlayer); err != nil {
+			return err
+		}
+		_, err := repo.FindPlayer(cmd.BlackPlayer)
+		return err
+	})
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	var timeControl domain.TimeControl
+	if cmd.TimeControl != "" {
+		timeControl, err = domain.ParseTimeControl(cmd.TimeControl)
+		if err != nil {
+			return domain.Match{}, err
+		}
+	}
+
+	var location domain.Location
+	if cmd.LocationID != uuid.Nil {
+		location, err = findLocation(ms.locations, cmd.LocationID)
+		if err != nil {
+			return domain.Match{}, err
+		}
+	}
+
+	// tournament games are played at the tournament's time control and venue unless told otherwise
+	if cmd.TournamentID != uuid.Nil {
+		err = ms.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			tournament, err := repo.FindTournament(cmd.TournamentID)
+			if err != nil {
+				return err
+			}
+			if timeControl.IsZero() {
+				timeControl = tournament.TimeControl
+			}
+			if cmd.LocationID == uuid.Nil {
+				location = tournament.Location
+			}
+			return nil
+		})
+		if err != nil {
+			return domain.Match{}, err
+		}
+	}
+
+	ratingType := cmd.RatingType
+	if ratingType == "" {
+		ratingType = domain.RatingTypeStandard
+		if !timeControl.IsZero() {
+			ratingType = timeControl.Category().RatingType()
+		}
+	}
+
+	match := domain.Match{
+		TournamentID: cmd.TournamentID,
+		Location:     location,
+		City:         location.City,
+		State:        location.State,
+		Country:      location.Country,
+		WhitePlayer:  cmd.WhitePlayer,
+		BlackPlayer:  cmd.BlackPlayer,
+		Rated:        cmd.Rated,
+		RatingType:   ratingType,
+		TimeControl:  timeControl,
+		Result:       domain.MatchResultOngoing,
+	}
+
+	var result domain.Match
+	err = ms.matches.WriteTx(func(repo ports.MatchRepository) error {
+		var err error
+		result, err = repo.CreateMatch(match)
+		return err
+	})
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	return result, nil
+}
+
+func (ms *MatchServicer) ListMatches(ctx context.Context, cmd commands.ListMatchesCommand) ([]domain.Match, error) {
+	filter := domain.MatchFilter{
+		PlayerID:   cmd.PlayerID,
+		Category:   cmd.Category,
+		Moderation: domain.ModerationApproved,
+	}
+	if cmd.TimeControl != "" {
+		tc, err := domain.ParseTimeControl(cmd.TimeControl)
+		if err != nil {
+			return nil, err
+		}
+		filter.TimeControl = tc
+	}
+
+	var results []domain.Match
+	err := ms.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		results, err = repo.ListMatches(filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (ms *MatchServicer) FindMatch(ctx context.Context, cmd commands.FindMatchCommand) (domain.Match, error) {
+	var result domain.Match
+	err := ms.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		result, err = repo.FindMatch(cmd.ID)
+		return err
+	})
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	return result, nil
+}
+
+// RecordResult stores the final result of a match. The maple ratings are updated from the
+// domain.ResultRecorded it records, in the same commit, see RatingServicer.HandleEvent
+func (ms *MatchServicer) RecordResult(ctx context.Context, cmd commands.RecordResultCommand) (domain.Match, error) {
+	var result domain.Match
+	var previous domain.MatchResult
+	err := ms.matches.WriteTx(func(repo ports.MatchRepository) error {
+		match, err := repo.FindMatch(cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		previous = match.Result
+		if previous == cmd.Result {
+			result = match
+			return nil
+		}
+
+		match.Result = cmd.Result
+		if match.CompletedAt.IsZero() {
+			match.CompletedAt = time.Now()
+		}
+
+		result, err = repo.UpdateMatch(match)
+		if err != nil {
+			return err
+		}
+
+		return repo.RecordEvents(domain.ResultRecorded{
+			MatchID:      result.UUID,
+			TournamentID: result.TournamentID,
+			WhitePlayer:  result.WhitePlayer,
+			BlackPlayer:  result.BlackPlayer,
+			Result:       result.Result,
+			Previous:     previous,
+			Rated:        result.Rated,
+			At:           result.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	return result, nil
+}
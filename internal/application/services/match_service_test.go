@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/application/validation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func TestMatchServicer_TimeControl(t *testing.T) {
+	ctx := context.Background()
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	ms, err := NewMatchServicer(lggr, matches, players, tournaments, nil)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+
+	white := createTestPlayer(t, ps, "white")
+	black := createTestPlayer(t, ps, "black")
+
+	classical, _ := domain.ParseTimeControl("90/40+30, 30+30")
+	var tournament domain.Tournament
+	err = tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		tournament, err = repo.CreateTournament(domain.Tournament{Name: "Open", TimeControl: classical})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	// tournament games inherit the time control and the pool follows it
+	game, err := ms.CreateMatch(ctx, commands.CreateMatchCommand{
+		TournamentID: tournament.PublicID,
+		WhitePlayer:  white.PublicID,
+		BlackPlayer:  black.PublicID,
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament match: %v", err)
+	}
+	if !game.TimeControl.Equal(classical) {
+		t.Errorf("expected the tournament time control, got %s", game.TimeControl)
+	}
+	if game.RatingType != domain.RatingTypeStandard {
+		t.Errorf("expected the standard pool, got %s", game.RatingType)
+	}
+
+	casual, err := ms.CreateMatch(ctx, commands.CreateMatchCommand{
+		WhitePlayer: white.PublicID,
+		BlackPlayer: black.PublicID,
+		TimeControl: "3+2",
+	})
+	if err != nil {
+		t.Fatalf("error creating casual match: %v", err)
+	}
+	if casual.RatingType != domain.RatingTypeBlitz {
+		t.Errorf("expected the blitz pool, got %s", casual.RatingType)
+	}
+
+	_, err = ms.CreateMatch(ctx, commands.CreateMatchCommand{
+		TournamentID: uuid.New(),
+		WhitePlayer:  white.PublicID,
+		BlackPlayer:  black.PublicID,
+	})
+	if !errors.Is(err, domain.ErrTournamentNotFound) {
+		t.Errorf("expected ErrTournamentNotFound, got %v", err)
+	}
+
+	blitz, err := ms.ListMatches(ctx, commands.ListMatchesCommand{Category: domain.TimeControlCategoryBlitz})
+	if err != nil {
+		t.Fatalf("error listing matches: %v", err)
+	}
+	if len(blitz) != 1 || blitz[0].UUID != casual.UUID {
+		t.Errorf("expected only the casual match, got %d matches", len(blitz))
+	}
+}
+
+func TestCreateMatchCommand_TimeControlRequiredForCasualGames(t *testing.T) {
+	cmd := commands.CreateMatchCommand{WhitePlayer: uuid.New(), BlackPlayer: uuid.New()}
+
+	ve, ok := commands.IsValidationError(cmd.Validate())
+	if !ok || ve.Errors["time_control"].Code != validation.CodeRequired {
+		t.Errorf("expected time_control to be required, got %v", cmd.Validate())
+	}
+
+	cmd.TournamentID = uuid.New()
+	if err := cmd.Validate(); err != nil {
+		t.Errorf("expected tournament games to default the time control, got %v", err)
+	}
+}
+
+func TestMatchServicer_RecordResultEvents(t *testing.T) {
+	ctx := context.Background()
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), outbox)
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), outbox)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	ms, err := NewMatchServicer(lggr, matches, players, tournaments, nil)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+
+	white := createTestPlayer(t, ps, "white")
+	black := createTestPlayer(t, ps, "black")
+	match, err := ms.CreateMatch(ctx, commands.CreateMatchCommand{WhitePlayer: white.PublicID, BlackPlayer: black.PublicID, TimeControl: "5+3"})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+
+	// recorded, recorded again with no change and corrected
+	for _, result := range []domain.MatchResult{domain.MatchResultWhiteWins, domain.MatchResultWhiteWins, domain.MatchResultDraw} {
+		if _, err := ms.RecordResult(ctx, commands.RecordResultCommand{ID: match.UUID, Result: result}); err != nil {
+			t.Fatalf("error recording result: %v", err)
+		}
+	}
+
+	entries := pendingEntries(t, outbox)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(entries))
+	}
+
+	corrected, ok := entries[1].Event.(domain.ResultRecorded)
+	if !ok {
+		t.Fatalf("expected ResultRecorded, got %T", entries[1].Event)
+	}
+	if corrected.MatchID != match.UUID || corrected.Result != domain.MatchResultDraw || corrected.Previous != domain.MatchResultWhiteWins {
+		t.Errorf("unexpected event: %+v", corrected)
+	}
+	if corrected.AggregateID() != match.UUID {
+		t.Errorf("expected a casual game to be its own aggregate")
+	}
+}
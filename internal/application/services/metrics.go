@@ -0,0 +1,20 @@
+package services
+
+import "github.com/ctfrancia/maple/internal/core/ports"
+
+// nopMetrics stands in for the metrics of the services that are not given any
+type nopMetrics struct{}
+
+func (nopMetrics) Counter(name, help string, labels ...string) ports.Counter { return nopMetric{} }
+func (nopMetrics) Gauge(name, help string, labels ...string) ports.Gauge     { return nopMetric{} }
+func (nopMetrics) GaugeFunc(name, help string, fn func() float64)            {}
+func (nopMetrics) Histogram(name, help string, buckets []float64, labels ...string) ports.Histogram {
+	return nopMetric{}
+}
+
+type nopMetric struct{}
+
+func (nopMetric) Inc(labelValues ...string)                    {}
+func (nopMetric) Add(delta float64, labelValues ...string)     {}
+func (nopMetric) Set(value float64, labelValues ...string)     {}
+func (nopMetric) Observe(value float64, labelValues ...string) {}
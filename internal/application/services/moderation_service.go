@@ -0,0 +1,487 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/moderation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// ModerationConfig - when the listings of a consumer need approval and when the consumer is suspended
+type ModerationConfig struct {
+	Approval     domain.ApprovalMode
+	TrustAfter   int // approved listings after which a consumer posts without approval
+	SuspendAfter int // listings taken down after being reported after which a consumer is suspended, 0 never
+}
+
+func DefaultModerationConfig() ModerationConfig {
+	return ModerationConfig{
+		Approval:     domain.ApprovalOff,
+		TrustAfter:   1,
+		SuspendAfter: 3,
+	}
+}
+
+// ModerationServicer keeps the reports, the decisions of the moderators and the standing of
+// the consumers. The moderation status lives on the listings themselves so the public lists
+// only have to filter on it, the decisions are taken inside the moderation WriteTx so two
+// moderators acting on the same subject resolve its reports once
+type ModerationServicer struct {
+	logger      ports.Logger
+	moderation  ports.ModerationRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+	matches     ports.MatchRepositoryProvider
+	players     ports.PlayerRepositoryProvider
+	challenges  ports.ChallengeRepositoryProvider
+	config      ModerationConfig
+	now         func() time.Time
+}
+
+func NewModerationServicer(log ports.Logger, mr ports.ModerationRepositoryProvider, tr ports.TournamentRepositoryProvider, mtr ports.MatchRepositoryProvider, pr ports.PlayerRepositoryProvider, cr ports.ChallengeRepositoryProvider, config ModerationConfig) *ModerationServicer {
+	return &ModerationServicer{
+		logger:      log,
+		moderation:  mr,
+		tournaments: tr,
+		matches:     mtr,
+		players:     pr,
+		challenges:  cr,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// moderated is what the moderation needs to know of a subject whatever its type
+type moderated struct {
+	title      string
+	consumerID string
+	status     domain.ModerationStatus
+}
+
+func (ms *ModerationServicer) Admit(ctx context.Context, consumerID string) (domain.ModerationStatus, error) {
+	if consumerID == "" {
+		return "", domain.ErrConsumerRequired
+	}
+
+	var standing domain.ConsumerStanding
+	err := ms.moderation.ReadTx(func(repo ports.ModerationRepository) error {
+		var err error
+		standing, err = repo.FindStanding(consumerID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if standing.Status == domain.ConsumerStatusSuspended {
+		return "", domain.ErrConsumerSuspended
+	}
+
+	switch ms.config.Approval {
+	case domain.ApprovalAll:
+		return domain.ModerationPending, nil
+	case domain.ApprovalNew:
+		if standing.ApprovedListings < ms.config.TrustAfter {
+			return domain.ModerationPending, nil
+		}
+	}
+
+	return domain.ModerationApproved, nil
+}
+
+func (ms *ModerationServicer) Report(ctx context.Context, cmd commands.ReportCommand) (domain.Report, error) {
+	subject, err := ms.lookup(cmd.Subject)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	var result domain.Report
+	err = ms.moderation.WriteTx(func(repo ports.ModerationRepository) error {
+		if cmd.ReporterID != "" {
+			open, err := repo.ListReports(domain.ReportFilter{Subject: &cmd.Subject, Status: domain.ReportOpen})
+			if err != nil {
+				return err
+			}
+			for _, report := range open {
+				if report.ReporterID == cmd.ReporterID {
+					return domain.ErrReportDuplicate
+				}
+			}
+		}
+
+		var err error
+		result, err = repo.CreateReport(domain.Report{
+			Subject:    cmd.Subject,
+			ConsumerID: subject.consumerID,
+			ReporterID: cmd.ReporterID,
+			Reason:     cmd.Reason,
+			Details:    cmd.Details,
+			Status:     domain.ReportOpen,
+		})
+		return err
+	})
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	ms.logger.Info(ctx, "subject reported",
+		ports.String("report_id", result.PublicID.String()),
+		ports.String("subject_type", string(result.Subject.Type)),
+		ports.String("subject_id", result.Subject.ID.String()),
+		ports.String("reason", string(result.Reason)),
+	)
+
+	return result, nil
+}
+
+func (ms *ModerationServicer) Queue(ctx context.Context, cmd commands.ModerationQueueCommand) ([]domain.ModerationItem, error) {
+	items := make(map[domain.ModerationSubject]*domain.ModerationItem)
+
+	// only tournaments and challenges are posted by consumers, they are the ones waiting for approval
+	if cmd.Type == "" || cmd.Type == domain.SubjectTournament {
+		err := ms.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			pending, err := repo.ListTournaments(domain.TournamentFilter{Moderation: domain.ModerationPending})
+			for _, t := range pending {
+				subject := domain.ModerationSubject{Type: domain.SubjectTournament, ID: t.PublicID}
+				items[subject] = &domain.ModerationItem{Subject: subject, Title: t.Name, ConsumerID: t.ConsumerID, Status: t.Moderation, Since: t.CreatedAt}
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Type == "" || cmd.Type == domain.SubjectChallenge {
+		err := ms.challenges.ReadTx(func(repo ports.ChallengeRepository) error {
+			pending, err := repo.ListChallenges(domain.ChallengeFilter{Moderation: domain.ModerationPending})
+			for _, c := range pending {
+				subject := domain.ModerationSubject{Type: domain.SubjectChallenge, ID: c.PublicID}
+				items[subject] = &domain.ModerationItem{Subject: subject, Title: challengeTitle(c), ConsumerID: c.ConsumerID, Status: c.Moderation, Since: c.CreatedAt}
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var open []domain.Report
+	err := ms.moderation.ReadTx(func(repo ports.ModerationRepository) error {
+		var err error
+		open, err = repo.ListReports(domain.ReportFilter{Status: domain.ReportOpen})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, report := range open {
+		if cmd.Type != "" && report.Subject.Type != cmd.Type {
+			continue
+		}
+		item, ok := items[report.Subject]
+		if !ok {
+			subject, err := ms.lookup(report.Subject)
+			if err != nil {
+				return nil, err
+			}
+			item = &domain.ModerationItem{Subject: report.Subject, Title: subject.title, ConsumerID: subject.consumerID, Status: subject.status, Since: report.CreatedAt}
+			items[report.Subject] = item
+		}
+		item.Reports = append(item.Reports, report)
+		if report.CreatedAt.Before(item.Since) {
+			item.Since = report.CreatedAt
+		}
+	}
+
+	queue := make([]domain.ModerationItem, 0, len(items))
+	for _, item := range items {
+		queue = append(queue, *item)
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if !queue[i].Since.Equal(queue[j].Since) {
+			return queue[i].Since.Before(queue[j].Since)
+		}
+		return queue[i].Subject.ID.String() < queue[j].Subject.ID.String()
+	})
+
+	return queue, nil
+}
+
+func (ms *ModerationServicer) Moderate(ctx context.Context, cmd commands.ModerateCommand) (domain.ModerationDecision, error) {
+	var (
+		result     domain.ModerationDecision
+		consumerID string
+		suspended  bool
+	)
+	err := ms.moderation.WriteTx(func(repo ports.ModerationRepository) error {
+		subject, err := ms.lookup(cmd.Subject)
+		if err != nil {
+			return err
+		}
+
+		to := cmd.Action.Status()
+		if err := ms.setStatus(cmd.Subject, to); err != nil {
+			return err
+		}
+
+		open, err := repo.ListReports(domain.ReportFilter{Subject: &cmd.Subject, Status: domain.ReportOpen})
+		if err != nil {
+			return err
+		}
+		resolution := domain.ReportDismissed
+		if cmd.Action != domain.ModerationApprove {
+			resolution = domain.ReportUpheld
+		}
+		now := ms.now()
+		for _, report := range open {
+			report.Status = resolution
+			report.ResolvedAt = now
+			if _, err := repo.UpdateReport(report); err != nil {
+				return err
+			}
+		}
+
+		if subject.consumerID != "" {
+			consumerID = subject.consumerID
+			suspended, err = ms.updateStanding(repo, subject, cmd.Action, len(open))
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = repo.CreateDecision(domain.ModerationDecision{
+			Subject:   cmd.Subject,
+			Action:    cmd.Action,
+			From:      subject.status,
+			To:        to,
+			Moderator: cmd.Moderator,
+			Note:      cmd.Note,
+			Reports:   len(open),
+			At:        now,
+		})
+		return err
+	})
+	if err != nil {
+		return domain.ModerationDecision{}, err
+	}
+
+	ms.logger.Info(ctx, "subject moderated",
+		ports.String("subject_type", string(result.Subject.Type)),
+		ports.String("subject_id", result.Subject.ID.String()),
+		ports.String("action", string(result.Action)),
+		ports.String("moderator", result.Moderator),
+		ports.Int("reports", result.Reports),
+	)
+	if suspended {
+		ms.logger.Warn(ctx, "consumer suspended", ports.String("consumer_id", consumerID), ports.String("subject_id", result.Subject.ID.String()))
+	}
+
+	return result, nil
+}
+
+// updateStanding counts the decision for the consumer that posted the subject, it reports
+// whether the decision suspended the consumer
+func (ms *ModerationServicer) updateStanding(repo ports.ModerationRepository, subject moderated, action domain.ModerationAction, reports int) (bool, error) {
+	standing, err := repo.FindStanding(subject.consumerID)
+	if err != nil {
+		return false, err
+	}
+
+	suspended := false
+	switch {
+	case action == domain.ModerationApprove && subject.status == domain.ModerationPending:
+		standing.ApprovedListings++
+	case action != domain.ModerationApprove && reports > 0:
+		// a listing counts once however many reports it had
+		standing.UpheldReports++
+		if ms.config.SuspendAfter > 0 && standing.UpheldReports >= ms.config.SuspendAfter && standing.Status != domain.ConsumerStatusSuspended {
+			standing.Status = domain.ConsumerStatusSuspended
+			standing.SuspendedAt = ms.now()
+			standing.SuspendedReason = fmt.Sprintf("%d reported listings taken down", standing.UpheldReports)
+			suspended = true
+		}
+	default:
+		return false, nil
+	}
+
+	_, err = repo.SaveStanding(standing)
+	return suspended, err
+}
+
+func (ms *ModerationServicer) History(ctx context.Context, cmd commands.ModerationHistoryCommand) (domain.ModerationHistory, error) {
+	subject, err := ms.lookup(cmd.Subject)
+	if err != nil {
+		return domain.ModerationHistory{}, err
+	}
+
+	result := domain.ModerationHistory{Subject: cmd.Subject, Status: subject.status}
+	err = ms.moderation.ReadTx(func(repo ports.ModerationRepository) error {
+		var err error
+		result.Reports, err = repo.ListReports(domain.ReportFilter{Subject: &cmd.Subject})
+		if err != nil {
+			return err
+		}
+		result.Decisions, err = repo.ListDecisions(cmd.Subject)
+		return err
+	})
+	if err != nil {
+		return domain.ModerationHistory{}, err
+	}
+
+	return result, nil
+}
+
+func (ms *ModerationServicer) FindStanding(ctx context.Context, cmd commands.FindStandingCommand) (domain.ConsumerStanding, error) {
+	var result domain.ConsumerStanding
+	err := ms.moderation.ReadTx(func(repo ports.ModerationRepository) error {
+		var err error
+		result, err = repo.FindStanding(cmd.ConsumerID)
+		return err
+	})
+	if err != nil {
+		return domain.ConsumerStanding{}, err
+	}
+
+	return result, nil
+}
+
+func (ms *ModerationServicer) ReinstateConsumer(ctx context.Context, cmd commands.ReinstateConsumerCommand) (domain.ConsumerStanding, error) {
+	var result domain.ConsumerStanding
+	err := ms.moderation.WriteTx(func(repo ports.ModerationRepository) error {
+		standing, err := repo.FindStanding(cmd.ConsumerID)
+		if err != nil {
+			return err
+		}
+		if standing.Status != domain.ConsumerStatusSuspended {
+			return domain.ErrConsumerNotSuspended
+		}
+
+		standing.Status = domain.ConsumerStatusActive
+		standing.UpheldReports = 0
+		standing.SuspendedAt = time.Time{}
+		standing.SuspendedReason = ""
+		result, err = repo.SaveStanding(standing)
+		return err
+	})
+	if err != nil {
+		return domain.ConsumerStanding{}, err
+	}
+
+	ms.logger.Info(ctx, "consumer reinstated",
+		ports.String("consumer_id", result.ConsumerID),
+		ports.String("moderator", cmd.Moderator),
+		ports.String("note", cmd.Note),
+	)
+
+	return result, nil
+}
+
+// lookup reads the subject from the repository of its type, an unknown subject fails with
+// the not found error of the type
+func (ms *ModerationServicer) lookup(subject domain.ModerationSubject) (moderated, error) {
+	var result moderated
+	var err error
+	switch subject.Type {
+	case domain.SubjectTournament:
+		err = ms.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			t, err := repo.FindTournament(subject.ID)
+			result = moderated{title: t.Name, consumerID: t.ConsumerID, status: t.Moderation}
+			return err
+		})
+	case domain.SubjectChallenge:
+		err = ms.challenges.ReadTx(func(repo ports.ChallengeRepository) error {
+			c, err := repo.FindChallenge(subject.ID)
+			result = moderated{title: challengeTitle(c), consumerID: c.ConsumerID, status: c.Moderation}
+			return err
+		})
+	case domain.SubjectMatch:
+		var m domain.Match
+		err = ms.matches.ReadTx(func(repo ports.MatchRepository) error {
+			var err error
+			m, err = repo.FindMatch(subject.ID)
+			return err
+		})
+		if err == nil {
+			result = moderated{title: ms.matchTitle(m), status: m.Moderation}
+		}
+	case domain.SubjectPlayer:
+		err = ms.players.ReadTx(func(repo ports.PlayerRepository) error {
+			p, err := repo.FindPlayer(subject.ID)
+			result = moderated{title: p.Username, status: p.Moderation}
+			return err
+		})
+	default:
+		return moderated{}, domain.ErrModerationSubject
+	}
+	if err != nil {
+		return moderated{}, err
+	}
+
+	return result, nil
+}
+
+func (ms *ModerationServicer) setStatus(subject domain.ModerationSubject, status domain.ModerationStatus) error {
+	switch subject.Type {
+	case domain.SubjectTournament:
+		return ms.tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+			t, err := repo.FindTournament(subject.ID)
+			if err != nil {
+				return err
+			}
+			t.Moderation = status
+			_, err = repo.UpdateTournament(t)
+			return err
+		})
+	case domain.SubjectChallenge:
+		return ms.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+			_, err := repo.ModerateChallenge(subject.ID, status)
+			return err
+		})
+	case domain.SubjectMatch:
+		return ms.matches.WriteTx(func(repo ports.MatchRepository) error {
+			m, err := repo.FindMatch(subject.ID)
+			if err != nil {
+				return err
+			}
+			m.Moderation = status
+			_, err = repo.UpdateMatch(m)
+			return err
+		})
+	case domain.SubjectPlayer:
+		return ms.players.WriteTx(func(repo ports.PlayerRepository) error {
+			p, err := repo.FindPlayer(subject.ID)
+			if err != nil {
+				return err
+			}
+			p.Moderation = status
+			_, err = repo.UpdatePlayer(p)
+			return err
+		})
+	}
+	return domain.ErrModerationSubject
+}
+
+// matchTitle names the match by its players, the ids are kept for the ones that are gone
+func (ms *ModerationServicer) matchTitle(m domain.Match) string {
+	white, black := m.WhitePlayer.String(), m.BlackPlayer.String()
+	_ = ms.players.ReadTx(func(repo ports.PlayerRepository) error {
+		if p, err := repo.FindPlayer(m.WhitePlayer); err == nil {
+			white = p.Username
+		}
+		if p, err := repo.FindPlayer(m.BlackPlayer); err == nil {
+			black = p.Username
+		}
+		return nil
+	})
+	return white + " vs " + black
+}
+
+func challengeTitle(c domain.Challenge) string {
+	if c.Location.Name == "" {
+		return c.TimeControl.String()
+	}
+	return c.TimeControl.String() + " at " + c.Location.Name
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	challengecommands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	commands "github.com/ctfrancia/maple/internal/application/commands/moderation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type moderationFixture struct {
+	mods        *ModerationServicer
+	tournaments ports.TournamentRepositoryProvider
+	players     ports.PlayerRepositoryProvider
+	matches     ports.MatchRepositoryProvider
+	challenges  ports.ChallengeRepositoryProvider
+}
+
+func newModerationFixture(config ModerationConfig) *moderationFixture {
+	f := &moderationFixture{
+		tournaments: inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil),
+		players:     inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository()),
+		matches:     inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil),
+		challenges:  inmemory.NewChallengeRepositoryProvider(inmemory.NewInMemoryChallengeRepository(), nil),
+	}
+	f.mods = NewModerationServicer(lggr, inmemory.NewModerationRepositoryProvider(inmemory.NewInMemoryModerationRepository()), f.tournaments, f.matches, f.players, f.challenges, config)
+
+	return f
+}
+
+// post stores a tournament of the consumer the way the tournament service does
+func (f *moderationFixture) post(t *testing.T, consumerID, name string) domain.Tournament {
+	t.Helper()
+
+	status, err := f.mods.Admit(context.Background(), consumerID)
+	if err != nil {
+		t.Fatalf("error admitting the listing: %v", err)
+	}
+	tournament := domain.NewTournament(name, "")
+	tournament.ConsumerID = consumerID
+	tournament.Moderation = status
+
+	var result domain.Tournament
+	err = f.tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		var err error
+		result, err = repo.CreateTournament(*tournament)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+	return result
+}
+
+func (f *moderationFixture) moderate(t *testing.T, subject domain.ModerationSubject, action domain.ModerationAction) domain.ModerationDecision {
+	t.Helper()
+
+	decision, err := f.mods.Moderate(context.Background(), commands.ModerateCommand{Subject: subject, Action: action, Moderator: "marta"})
+	if err != nil {
+		t.Fatalf("error moderating %+v: %v", subject, err)
+	}
+	return decision
+}
+
+func tournamentSubject(t domain.Tournament) domain.ModerationSubject {
+	return domain.ModerationSubject{Type: domain.SubjectTournament, ID: t.PublicID}
+}
+
+func TestModerationServicer_ApprovalAndSuspension(t *testing.T) {
+	ctx := context.Background()
+	f := newModerationFixture(ModerationConfig{Approval: domain.ApprovalNew, TrustAfter: 1, SuspendAfter: 2})
+
+	first := f.post(t, "acme", "Open de Sants")
+	if first.Moderation != domain.ModerationPending {
+		t.Fatalf("expected the first listing of a new consumer to wait for approval, got %s", first.Moderation)
+	}
+	if _, err := f.mods.Admit(ctx, ""); !errors.Is(err, domain.ErrConsumerRequired) {
+		t.Errorf("expected the listings without consumer to be rejected, got %v", err)
+	}
+
+	queue, err := f.mods.Queue(ctx, commands.ModerationQueueCommand{})
+	if err != nil {
+		t.Fatalf("error reading the queue: %v", err)
+	}
+	if len(queue) != 1 || queue[0].Subject != tournamentSubject(first) || queue[0].ConsumerID != "acme" || len(queue[0].Reports) != 0 {
+		t.Fatalf("unexpected queue %+v", queue)
+	}
+
+	decision := f.moderate(t, tournamentSubject(first), domain.ModerationApprove)
+	if decision.From != domain.ModerationPending || decision.To != domain.ModerationApproved {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+
+	// one approved listing is enough to be trusted
+	second := f.post(t, "acme", "Blitz del divendres")
+	third := f.post(t, "acme", "Ràpides de Gràcia")
+	if second.Moderation != domain.ModerationApproved || third.Moderation != domain.ModerationApproved {
+		t.Fatalf("expected a trusted consumer to post without approval, got %s and %s", second.Moderation, third.Moderation)
+	}
+
+	for _, reporter := range []string{"alice", "bob"} {
+		if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: tournamentSubject(second), ReporterID: reporter, Reason: domain.ReportSpam}); err != nil {
+			t.Fatalf("error reporting: %v", err)
+		}
+	}
+	if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: tournamentSubject(second), ReporterID: "alice", Reason: domain.ReportOffensive}); !errors.Is(err, domain.ErrReportDuplicate) {
+		t.Errorf("expected ErrReportDuplicate, got %v", err)
+	}
+
+	queue, err = f.mods.Queue(ctx, commands.ModerationQueueCommand{Type: domain.SubjectTournament})
+	if err != nil || len(queue) != 1 || len(queue[0].Reports) != 2 || queue[0].Reports[0].ConsumerID != "acme" {
+		t.Fatalf("expected the reported listing in the queue, got %+v, %v", queue, err)
+	}
+
+	decision = f.moderate(t, tournamentSubject(second), domain.ModerationHide)
+	if decision.Reports != 2 {
+		t.Errorf("expected the decision to resolve 2 reports, got %d", decision.Reports)
+	}
+	standing, err := f.mods.FindStanding(ctx, commands.FindStandingCommand{ConsumerID: "acme"})
+	if err != nil {
+		t.Fatalf("error finding standing: %v", err)
+	}
+	if standing.UpheldReports != 1 || standing.ApprovedListings != 1 || standing.Status != domain.ConsumerStatusActive {
+		t.Errorf("expected one listing taken down however many reports it had, got %+v", standing)
+	}
+
+	if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: tournamentSubject(third), Reason: domain.ReportIncorrect}); err != nil {
+		t.Fatalf("error reporting: %v", err)
+	}
+	f.moderate(t, tournamentSubject(third), domain.ModerationReject)
+
+	standing, _ = f.mods.FindStanding(ctx, commands.FindStandingCommand{ConsumerID: "acme"})
+	if standing.Status != domain.ConsumerStatusSuspended || standing.SuspendedAt.IsZero() {
+		t.Fatalf("expected the consumer to be suspended, got %+v", standing)
+	}
+	if _, err := f.mods.Admit(ctx, "acme"); !errors.Is(err, domain.ErrConsumerSuspended) {
+		t.Errorf("expected ErrConsumerSuspended, got %v", err)
+	}
+
+	var public []domain.Tournament
+	_ = f.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		public, err = repo.ListTournaments(domain.TournamentFilter{Moderation: domain.ModerationApproved})
+		return err
+	})
+	if len(public) != 1 || public[0].PublicID != first.PublicID {
+		t.Errorf("expected only the approved listings to be public, got %d", len(public))
+	}
+
+	history, err := f.mods.History(ctx, commands.ModerationHistoryCommand{Subject: tournamentSubject(second)})
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if history.Status != domain.ModerationHidden || len(history.Reports) != 2 || history.Reports[0].Status != domain.ReportUpheld || len(history.Decisions) != 1 {
+		t.Errorf("unexpected history %+v", history)
+	}
+
+	standing, err = f.mods.ReinstateConsumer(ctx, commands.ReinstateConsumerCommand{ConsumerID: "acme", Moderator: "marta"})
+	if err != nil || standing.Status != domain.ConsumerStatusActive || standing.UpheldReports != 0 {
+		t.Errorf("unexpected standing after reinstating %+v, %v", standing, err)
+	}
+	if _, err := f.mods.ReinstateConsumer(ctx, commands.ReinstateConsumerCommand{ConsumerID: "acme", Moderator: "marta"}); !errors.Is(err, domain.ErrConsumerNotSuspended) {
+		t.Errorf("expected ErrConsumerNotSuspended, got %v", err)
+	}
+}
+
+func TestModerationServicer_ApprovingDismissesReports(t *testing.T) {
+	ctx := context.Background()
+	f := newModerationFixture(DefaultModerationConfig())
+
+	var player domain.Player
+	err := f.players.WriteTx(func(repo ports.PlayerRepository) error {
+		var err error
+		player, err = repo.CreatePlayer(domain.Player{Username: "jordi"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating player: %v", err)
+	}
+	subject := domain.ModerationSubject{Type: domain.SubjectPlayer, ID: player.PublicID}
+
+	if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: domain.ModerationSubject{Type: domain.SubjectPlayer, ID: uuid.New()}, Reason: domain.ReportSpam}); !errors.Is(err, domain.ErrPlayerNotFound) {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+	if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: subject, Reason: domain.ReportOther, Details: "impersonating a GM"}); err != nil {
+		t.Fatalf("error reporting: %v", err)
+	}
+
+	f.moderate(t, subject, domain.ModerationHide)
+	if err := f.players.ReadTx(func(repo ports.PlayerRepository) error {
+		player, err = repo.FindPlayer(player.PublicID)
+		return err
+	}); err != nil || player.Moderation != domain.ModerationHidden {
+		t.Fatalf("expected the player to be hidden, got %s, %v", player.Moderation, err)
+	}
+
+	if _, err := f.mods.Report(ctx, commands.ReportCommand{Subject: subject, Reason: domain.ReportIncorrect}); err != nil {
+		t.Fatalf("error reporting: %v", err)
+	}
+	decision := f.moderate(t, subject, domain.ModerationApprove)
+	if decision.From != domain.ModerationHidden || decision.Reports != 1 {
+		t.Errorf("unexpected decision %+v", decision)
+	}
+
+	history, err := f.mods.History(ctx, commands.ModerationHistoryCommand{Subject: subject})
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if len(history.Decisions) != 2 || history.Reports[1].Status != domain.ReportDismissed {
+		t.Errorf("unexpected history %+v", history)
+	}
+	queue, err := f.mods.Queue(ctx, commands.ModerationQueueCommand{})
+	if err != nil || len(queue) != 0 {
+		t.Errorf("expected an empty queue, got %+v, %v", queue, err)
+	}
+}
+
+func TestChallengeServicer_WaitsForApproval(t *testing.T) {
+	ctx := context.Background()
+	f := newModerationFixture(ModerationConfig{Approval: domain.ApprovalAll})
+
+	var challenger domain.Player
+	err := f.players.WriteTx(func(repo ports.PlayerRepository) error {
+		var err error
+		challenger, err = repo.CreatePlayer(domain.Player{Username: "nuria"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating player: %v", err)
+	}
+
+	cs := NewChallengeServicer(lggr, f.challenges, f.players, f.matches, nil, f.mods, DefaultChallengeConfig())
+	challenge, err := cs.CreateChallenge(ctx, challengecommands.CreateChallengeCommand{
+		ChallengerID: challenger.PublicID,
+		Latitude:     41.3870,
+		Longitude:    2.1701,
+		StartsAt:     time.Now().Add(time.Hour),
+		EndsAt:       time.Now().Add(4 * time.Hour),
+		TimeControl:  "5+3",
+		ConsumerID:   "club-app",
+	})
+	if err != nil {
+		t.Fatalf("error creating challenge: %v", err)
+	}
+	if challenge.Moderation != domain.ModerationPending || challenge.ConsumerID != "club-app" {
+		t.Fatalf("unexpected challenge %+v", challenge)
+	}
+
+	listed, err := cs.ListChallenges(ctx, challengecommands.ListChallengesCommand{})
+	if err != nil || len(listed) != 0 {
+		t.Fatalf("expected the pending challenge not to be listed, got %d, %v", len(listed), err)
+	}
+
+	f.moderate(t, domain.ModerationSubject{Type: domain.SubjectChallenge, ID: challenge.PublicID}, domain.ModerationApprove)
+
+	listed, err = cs.ListChallenges(ctx, challengecommands.ListChallengesCommand{})
+	if err != nil || len(listed) != 1 {
+		t.Errorf("expected the approved challenge to be listed, got %d, %v", len(listed), err)
+	}
+}
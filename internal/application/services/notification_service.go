@@ -0,0 +1,527 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/notification"
+	"github.com/ctfrancia/maple/internal/application/templates"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// NotificationConfig - how the queue is sent and retried, and when the reminders go out
+type NotificationConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int // notifications sent per poll
+	MaxAttempts    int // failed sends before a notification is given up on
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	ReminderBefore time.Duration // how long before a round its reminder is sent
+	BaseURL        string        // of the api, for the unsubscribe links
+}
+
+func DefaultNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		PollInterval:   5 * time.Second,
+		BatchSize:      50,
+		MaxAttempts:    5,
+		BaseBackoff:    30 * time.Second,
+		MaxBackoff:     30 * time.Minute,
+		ReminderBefore: 2 * time.Hour,
+		BaseURL:        "http://localhost:8080",
+	}
+}
+
+// NotificationServicer emails the players about their tournaments and games. The events queue the
+// notifications, rendered in the language of each player, and the queue is sent in the
+// background retrying the failures
+type NotificationServicer struct {
+	logger        ports.Logger
+	notifications ports.NotificationRepositoryProvider
+	players       ports.PlayerRepositoryProvider
+	tournaments   ports.TournamentRepositoryProvider
+	challenges    ports.ChallengeRepositoryProvider
+	notifier      ports.Notifier
+	emails        *templates.Emails
+	config        NotificationConfig
+	now           func() time.Time
+
+	sending   sync.Mutex
+	lifecycle sync.Mutex
+	started   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewNotificationServicer(log ports.Logger, nr ports.NotificationRepositoryProvider, pr ports.PlayerRepositoryProvider, tr ports.TournamentRepositoryProvider, cr ports.ChallengeRepositoryProvider, notifier ports.Notifier, config NotificationConfig) *NotificationServicer {
+	return &NotificationServicer{
+		logger:        log,
+		notifications: nr,
+		players:       pr,
+		tournaments:   tr,
+		challenges:    cr,
+		notifier:      notifier,
+		emails:        templates.Default(),
+		config:        config,
+		now:           time.Now,
+	}
+}
+
+// FindPreferences returns the preferences of the player, the defaults if they never chose
+func (ns *NotificationServicer) FindPreferences(ctx context.Context, cmd commands.FindPreferencesCommand) (domain.NotificationPreferences, error) {
+	if err := ns.players.ReadTx(func(repo ports.PlayerRepository) error {
+		_, err := repo.FindPlayer(cmd.PlayerID)
+		return err
+	}); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	var prefs domain.NotificationPreferences
+	err := ns.notifications.ReadTx(func(repo ports.NotificationRepository) error {
+		var err error
+		prefs, err = repo.FindPreferences(cmd.PlayerID)
+		if errors.Is(err, domain.ErrNotificationPrefsNotFound) {
+			prefs, err = domain.NotificationPreferences{PlayerID: cmd.PlayerID}, nil
+		}
+		return err
+	})
+
+	return prefs, err
+}
+
+// UpdatePreferences replaces the preferences of the player, the notifications already queued
+// that the player no longer wants are cancelled when they are due
+func (ns *NotificationServicer) UpdatePreferences(ctx context.Context, cmd commands.UpdatePreferencesCommand) (domain.NotificationPreferences, error) {
+	if err := ns.players.ReadTx(func(repo ports.PlayerRepository) error {
+		_, err := repo.FindPlayer(cmd.PlayerID)
+		return err
+	}); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	var prefs domain.NotificationPreferences
+	err := ns.notifications.WriteTx(func(repo ports.NotificationRepository) error {
+		current, err := ns.preferences(repo, cmd.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		current.Locale = cmd.Locale
+		current.Muted = cmd.Muted
+		current.Unsubscribed = cmd.Unsubscribed
+
+		prefs, err = repo.SavePreferences(current)
+		return err
+	})
+
+	return prefs, err
+}
+
+func (ns *NotificationServicer) Unsubscribe(ctx context.Context, cmd commands.UnsubscribeCommand) (domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	err := ns.notifications.WriteTx(func(repo ports.NotificationRepository) error {
+		current, err := repo.FindPreferencesByToken(cmd.Token)
+		if errors.Is(err, domain.ErrNotificationPrefsNotFound) {
+			return domain.ErrUnsubscribeTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		current.Unsubscribed = true
+		prefs, err = repo.SavePreferences(current)
+		return err
+	})
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	ns.logger.Info(ctx, "player unsubscribed from emails", ports.String("player_id", prefs.PlayerID.String()))
+
+	return prefs, nil
+}
+
+// HandleEvent queues the notifications of the event. It is idempotent: when the event bus
+// delivers the event again nothing is queued twice
+func (ns *NotificationServicer) HandleEvent(ctx context.Context, event domain.Event) error {
+	eventID, ok := EventIDFromContext(ctx)
+	if !ok {
+		eventID = uuid.New()
+	}
+	now := ns.now()
+
+	switch e := event.(type) {
+	case domain.PlayerRegistered:
+		return ns.queue(ctx, notificationRequest{
+			kind:         domain.NotificationRegistrationConfirmed,
+			key:          eventID.String(),
+			tournamentID: e.TournamentID,
+			players:      []uuid.UUID{e.PlayerID},
+			sendAt:       now,
+		})
+
+	case domain.RoundPaired:
+		var tournament domain.Tournament
+		if err := ns.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			var err error
+			tournament, err = repo.FindTournament(e.TournamentID)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		var startsAt time.Time
+		if e.Round > 0 && e.Round <= len(tournament.Schedule) {
+			startsAt = tournament.Schedule[e.Round-1].StartTime
+		}
+
+		err := ns.queue(ctx, notificationRequest{
+			kind:         domain.NotificationPairingsPublished,
+			key:          eventID.String(),
+			tournamentID: e.TournamentID,
+			players:      tournament.Players,
+			round:        e.Round,
+			startsAt:     startsAt,
+			sendAt:       now,
+		})
+		if err != nil {
+			return err
+		}
+
+		// there is nothing to remind of when the pairings come out that close to the round,
+		// the reminder is keyed by the round so pairing it again does not send another one
+		remindAt := startsAt.Add(-ns.config.ReminderBefore)
+		if startsAt.IsZero() || !remindAt.After(now) {
+			return nil
+		}
+		return ns.queue(ctx, notificationRequest{
+			kind:         domain.NotificationRoundReminder,
+			key:          fmt.Sprintf("%s/round/%d", e.TournamentID, e.Round),
+			tournamentID: e.TournamentID,
+			players:      tournament.Players,
+			round:        e.Round,
+			startsAt:     startsAt,
+			sendAt:       remindAt,
+		})
+
+	case domain.ChallengeAccepted:
+		var challenge domain.Challenge
+		if err := ns.challenges.ReadTx(func(repo ports.ChallengeRepository) error {
+			var err error
+			challenge, err = repo.FindChallenge(e.ChallengeID)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		return ns.queue(ctx, notificationRequest{
+			kind:      domain.NotificationChallengeAccepted,
+			key:       e.ChallengeID.String(),
+			challenge: &challenge,
+			players:   []uuid.UUID{e.ChallengerID, e.AcceptedBy},
+			startsAt:  challenge.StartsAt,
+			sendAt:    now,
+		})
+	}
+
+	return nil
+}
+
+type notificationRequest struct {
+	kind         domain.NotificationKind
+	key          string            // unique per kind, the player is added to it
+	tournamentID uuid.UUID         // uuid.Nil when the notification is not about a tournament
+	challenge    *domain.Challenge // the players of an accepted challenge are each other's opponent
+	players      []uuid.UUID
+	round        int
+	startsAt     time.Time
+	sendAt       time.Time
+}
+
+// queue renders the notification for every player that wants it and has an email address
+func (ns *NotificationServicer) queue(ctx context.Context, req notificationRequest) error {
+	var (
+		tournament domain.Tournament
+		players    []domain.Player
+	)
+	if req.tournamentID != uuid.Nil {
+		if err := ns.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			var err error
+			tournament, err = repo.FindTournament(req.tournamentID)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if err := ns.players.ReadTx(func(repo ports.PlayerRepository) error {
+		for _, id := range req.players {
+			player, err := repo.FindPlayer(id)
+			if errors.Is(err, domain.ErrPlayerNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			players = append(players, player)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	location := tournament.Location
+	var challenge domain.Challenge
+	if req.challenge != nil {
+		challenge = *req.challenge
+		location = challenge.Location
+	}
+	timezone, err := time.LoadLocation(string(location.Timezone))
+	if err != nil || location.Timezone == "" {
+		timezone = time.UTC
+	}
+
+	return ns.notifications.WriteTx(func(repo ports.NotificationRepository) error {
+		for _, player := range players {
+			key := strings.Join([]string{string(req.kind), req.key, player.PublicID.String()}, "/")
+			queued, err := repo.HasNotification(key)
+			if err != nil {
+				return err
+			}
+			if queued {
+				continue
+			}
+
+			if player.Email == "" {
+				ns.logger.Debug(ctx, "player has no email address", ports.String("player_id", player.PublicID.String()), ports.String("kind", string(req.kind)))
+				continue
+			}
+
+			prefs, err := ns.preferences(repo, player.PublicID)
+			if err != nil {
+				return err
+			}
+			if !prefs.Wants(req.kind) {
+				continue
+			}
+
+			var opponent domain.Player
+			if req.challenge != nil {
+				for _, other := range players {
+					if other.PublicID != player.PublicID {
+						opponent = other
+					}
+				}
+			}
+
+			unsubscribeURL := strings.TrimRight(ns.config.BaseURL, "/") + "/v1/notification/unsubscribe/" + prefs.UnsubscribeToken
+			subject, text, err := ns.emails.Render(prefs.EmailLocale(), req.kind, templates.EmailData{
+				Player:         player,
+				Tournament:     tournament,
+				Challenge:      challenge,
+				Opponent:       opponent,
+				Round:          req.round,
+				StartsAt:       req.startsAt,
+				UnsubscribeURL: unsubscribeURL,
+				Timezone:       timezone,
+			})
+			if err != nil {
+				return fmt.Errorf("rendering %s email: %w", req.kind, err)
+			}
+
+			_, err = repo.EnqueueNotification(domain.Notification{
+				Key:      key,
+				Kind:     req.kind,
+				PlayerID: player.PublicID,
+				Locale:   prefs.EmailLocale(),
+				Email: domain.Email{
+					To:      player.Email,
+					Subject: subject,
+					Text:    text,
+					Headers: map[string]string{
+						// RFC 8058 one click unsubscribe, the mail clients show their own button
+						"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+						"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+					},
+				},
+				Status:        domain.NotificationPending,
+				NextAttemptAt: req.sendAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// preferences returns the preferences of the player, saving the defaults with an unsubscribe
+// token the first time so every email can carry the link
+func (ns *NotificationServicer) preferences(repo ports.NotificationRepository, playerID uuid.UUID) (domain.NotificationPreferences, error) {
+	prefs, err := repo.FindPreferences(playerID)
+	if err == nil && prefs.UnsubscribeToken != "" {
+		return prefs, nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrNotificationPrefsNotFound) {
+		return domain.NotificationPreferences{}, err
+	}
+
+	token, err := newUnsubscribeToken()
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	prefs.PlayerID = playerID
+	prefs.UnsubscribeToken = token
+
+	return repo.SavePreferences(prefs)
+}
+
+// Start sends the queue until ctx is done or Stop is called
+func (ns *NotificationServicer) Start(ctx context.Context) {
+	ns.lifecycle.Lock()
+	defer ns.lifecycle.Unlock()
+
+	if ns.started {
+		return
+	}
+	ns.stop = make(chan struct{})
+	ns.done = make(chan struct{})
+	ns.started = true
+
+	go ns.run(ctx, ns.stop, ns.done)
+}
+
+// Stop waits for the notification being sent to finish
+func (ns *NotificationServicer) Stop() {
+	ns.lifecycle.Lock()
+	defer ns.lifecycle.Unlock()
+
+	if !ns.started {
+		return
+	}
+	close(ns.stop)
+	<-ns.done
+	ns.started = false
+}
+
+func (ns *NotificationServicer) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ns.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := ns.SendDue(ctx); err != nil {
+				ns.logger.Error(ctx, "sending notifications failed", ports.Error("error", err))
+			}
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// SendDue sends the notifications that are due and returns how many were sent
+func (ns *NotificationServicer) SendDue(ctx context.Context) (int, error) {
+	ns.sending.Lock()
+	defer ns.sending.Unlock()
+
+	var due []domain.Notification
+	err := ns.notifications.ReadTx(func(repo ports.NotificationRepository) error {
+		var err error
+		due, err = repo.DueNotifications(ns.now(), ns.config.BatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, notification := range due {
+		if ctx.Err() != nil {
+			return sent, nil
+		}
+
+		notification, err := ns.send(ctx, notification)
+		if err != nil {
+			return sent, err
+		}
+		if notification.Status == domain.NotificationSent {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// send delivers the notification unless the player opted out since it was queued
+func (ns *NotificationServicer) send(ctx context.Context, notification domain.Notification) (domain.Notification, error) {
+	var prefs domain.NotificationPreferences
+	err := ns.notifications.ReadTx(func(repo ports.NotificationRepository) error {
+		var err error
+		prefs, err = repo.FindPreferences(notification.PlayerID)
+		return err
+	})
+	if err != nil && !errors.Is(err, domain.ErrNotificationPrefsNotFound) {
+		return notification, err
+	}
+
+	fields := []ports.LogField{
+		ports.String("notification_id", notification.PublicID.String()),
+		ports.String("kind", string(notification.Kind)),
+		ports.String("player_id", notification.PlayerID.String()),
+	}
+
+	switch {
+	case err == nil && !prefs.Wants(notification.Kind):
+		notification.Status = domain.NotificationCancelled
+
+	default:
+		sendErr := ns.notifier.Send(ctx, notification.Email)
+		now := ns.now()
+		if sendErr == nil {
+			notification.Status = domain.NotificationSent
+			notification.SentAt = now
+			ns.logger.Debug(ctx, "notification sent", fields...)
+			break
+		}
+
+		notification.Attempts++
+		notification.LastError = sendErr.Error()
+		fields = append(fields, ports.Int("attempts", notification.Attempts), ports.Error("error", sendErr))
+		if notification.Attempts >= ns.config.MaxAttempts {
+			notification.Status = domain.NotificationFailed
+			ns.logger.Error(ctx, "notification abandoned", fields...)
+		} else {
+			notification.NextAttemptAt = now.Add(backoff(ns.config.BaseBackoff, ns.config.MaxBackoff, notification.Attempts))
+			ns.logger.Warn(ctx, "sending notification failed, retrying", fields...)
+		}
+	}
+
+	err = ns.notifications.WriteTx(func(repo ports.NotificationRepository) error {
+		var err error
+		notification, err = repo.UpdateNotification(notification)
+		return err
+	})
+
+	return notification, err
+}
+
+func newUnsubscribeToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating unsubscribe token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
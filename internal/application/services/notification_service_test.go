@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/notifier"
+	"github.com/ctfrancia/maple/internal/adapters/notifier/smtptest"
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/notification"
+	tournamentcommands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type notificationFixture struct {
+	ns            *NotificationServicer
+	smtp          *smtptest.Server
+	notifications ports.NotificationRepositoryProvider
+	challenges    ports.ChallengeRepositoryProvider
+	now           *time.Time
+	tournament    domain.Tournament
+	players       []domain.Player
+}
+
+// newNotificationFixture sends through the smtp adapter to a local server, the tournament
+// has a round tomorrow and a player per email given, an empty email is a player without one
+func newNotificationFixture(t *testing.T, emails ...string) *notificationFixture {
+	t.Helper()
+
+	srv, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatalf("error starting smtp server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	notifications := inmemory.NewNotificationRepositoryProvider(inmemory.NewInMemoryNotificationRepository())
+
+	challenges := inmemory.NewChallengeRepositoryProvider(inmemory.NewInMemoryChallengeRepository(), nil)
+
+	f := &notificationFixture{smtp: srv, notifications: notifications, challenges: challenges}
+	err = players.WriteTx(func(repo ports.PlayerRepository) error {
+		for i, email := range emails {
+			player, err := repo.CreatePlayer(domain.Player{Username: "player" + string(rune('a'+i)), FirstName: "Player", Email: email})
+			if err != nil {
+				return err
+			}
+			f.players = append(f.players, player)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error creating players: %v", err)
+	}
+
+	now := time.Now()
+	f.now = &now
+
+	ids := make([]uuid.UUID, len(f.players))
+	for i, p := range f.players {
+		ids[i] = p.PublicID
+	}
+	err = tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		f.tournament, err = repo.CreateTournament(domain.Tournament{
+			Name:     "Open de Sants",
+			Location: domain.Location{Name: "Casal de Sants", City: "Barcelona"},
+			Players:  ids,
+			Schedule: []domain.Schedule{{StartTime: now.Add(24 * time.Hour)}},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	mailer := notifier.NewSMTPNotifier(notifier.SMTPConfig{Host: srv.Host(), Port: srv.Port(), From: "Maple <no-reply@maple.example>", Timeout: time.Second})
+	f.ns = NewNotificationServicer(lggr, notifications, players, tournaments, challenges, mailer, NotificationConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		MaxAttempts:    2,
+		BaseBackoff:    time.Minute,
+		MaxBackoff:     time.Hour,
+		ReminderBefore: time.Hour,
+		BaseURL:        "https://maple.example/",
+	})
+	f.ns.now = func() time.Time { return *f.now }
+
+	return f
+}
+
+func (f *notificationFixture) sendDue(t *testing.T) int {
+	t.Helper()
+	sent, err := f.ns.SendDue(context.Background())
+	if err != nil {
+		t.Fatalf("error sending notifications: %v", err)
+	}
+	return sent
+}
+
+func (f *notificationFixture) queued(t *testing.T, player domain.Player) []domain.Notification {
+	t.Helper()
+	var notifications []domain.Notification
+	err := f.notifications.ReadTx(func(repo ports.NotificationRepository) error {
+		var err error
+		notifications, err = repo.ListNotifications(player.PublicID, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error listing notifications: %v", err)
+	}
+	return notifications
+}
+
+func TestNotificationServicer_RegistrationConfirmed(t *testing.T) {
+	f := newNotificationFixture(t, "nuria@example.com")
+	player := f.players[0]
+
+	_, err := f.ns.UpdatePreferences(context.Background(), commands.UpdatePreferencesCommand{PlayerID: player.PublicID, Locale: i18n.LocaleCatalan})
+	if err != nil {
+		t.Fatalf("error updating preferences: %v", err)
+	}
+
+	// delivered twice by the event bus
+	ctx := context.WithValue(context.Background(), eventIDKey{}, uuid.New())
+	for range 2 {
+		if err := f.ns.HandleEvent(ctx, domain.PlayerRegistered{TournamentID: f.tournament.PublicID, PlayerID: player.PublicID}); err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+
+	if sent := f.sendDue(t); sent != 1 {
+		t.Fatalf("expected 1 email sent, got %d", sent)
+	}
+
+	messages := f.smtp.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "nuria@example.com" {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	msg, err := messages[0].Parse()
+	if err != nil {
+		t.Fatalf("error parsing message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Estàs inscrit a Open de Sants" {
+		t.Errorf("expected the subject in catalan, got %q", subject)
+	}
+
+	// the one click unsubscribe link of the email opts the player out
+	link := strings.Trim(msg.Header.Get("List-Unsubscribe"), "<>")
+	token, ok := strings.CutPrefix(link, "https://maple.example/v1/notification/unsubscribe/")
+	if !ok || token == "" {
+		t.Fatalf("unexpected unsubscribe link %q", link)
+	}
+	prefs, err := f.ns.Unsubscribe(context.Background(), commands.UnsubscribeCommand{Token: token})
+	if err != nil {
+		t.Fatalf("error unsubscribing: %v", err)
+	}
+	if !prefs.Unsubscribed || prefs.PlayerID != player.PublicID || prefs.Locale != i18n.LocaleCatalan {
+		t.Errorf("unexpected preferences %+v", prefs)
+	}
+
+	if _, err := f.ns.Unsubscribe(context.Background(), commands.UnsubscribeCommand{Token: "unknown"}); !errors.Is(err, domain.ErrUnsubscribeTokenInvalid) {
+		t.Errorf("expected ErrUnsubscribeTokenInvalid, got %v", err)
+	}
+}
+
+func TestNotificationServicer_RoundPaired(t *testing.T) {
+	f := newNotificationFixture(t, "a@example.com", "b@example.com", "")
+
+	_, err := f.ns.UpdatePreferences(context.Background(), commands.UpdatePreferencesCommand{
+		PlayerID: f.players[1].PublicID,
+		Muted:    []domain.NotificationKind{domain.NotificationRoundReminder},
+	})
+	if err != nil {
+		t.Fatalf("error updating preferences: %v", err)
+	}
+
+	for range 2 {
+		// paired again, the reminder of the round is not queued twice
+		err := f.ns.HandleEvent(context.Background(), domain.RoundPaired{TournamentID: f.tournament.PublicID, Round: 1})
+		if err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+
+	// the pairings of both events go out now, the player without an email is skipped
+	if sent := f.sendDue(t); sent != 4 {
+		t.Fatalf("expected 4 pairing emails, got %d", sent)
+	}
+	if got := len(f.queued(t, f.players[2])); got != 0 {
+		t.Errorf("expected nothing queued for the player without email, got %d", got)
+	}
+
+	*f.now = f.now.Add(23*time.Hour - time.Second)
+	if sent := f.sendDue(t); sent != 0 {
+		t.Fatalf("expected no reminder before it is due, got %d", sent)
+	}
+
+	*f.now = f.now.Add(time.Second)
+	if sent := f.sendDue(t); sent != 1 {
+		t.Fatalf("expected 1 reminder, the other player muted them, got %d", sent)
+	}
+
+	last := f.smtp.Messages()[len(f.smtp.Messages())-1]
+	if last.To[0] != "a@example.com" {
+		t.Errorf("expected the reminder to go to a@example.com, got %v", last.To)
+	}
+}
+
+// the events of registering and pairing go through the outbox to the notifications like in main
+func TestNotificationServicer_FromTheTournamentService(t *testing.T) {
+	srv, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatalf("error starting smtp server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), outbox)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), outbox)
+	notifications := inmemory.NewNotificationRepositoryProvider(inmemory.NewInMemoryNotificationRepository())
+	challenges := inmemory.NewChallengeRepositoryProvider(inmemory.NewInMemoryChallengeRepository(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	ts, err := NewTournamentServicer(lggr, tournaments, matches, players, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating tournament service: %v", err)
+	}
+	mailer := notifier.NewSMTPNotifier(notifier.SMTPConfig{Host: srv.Host(), Port: srv.Port(), From: "Maple <no-reply@maple.example>", Timeout: time.Second})
+	ns := NewNotificationServicer(lggr, notifications, players, tournaments, challenges, mailer, NotificationConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		MaxAttempts:    2,
+		BaseBackoff:    time.Minute,
+		MaxBackoff:     time.Hour,
+		ReminderBefore: time.Hour,
+		BaseURL:        "https://maple.example/",
+	})
+
+	dispatcher := NewEventDispatcher(lggr, outbox, DefaultEventDispatcherConfig())
+	if err := dispatcher.Subscribe("notifications", ns.HandleEvent, domain.EventPlayerRegistered, domain.EventRoundPaired, domain.EventChallengeAccepted); err != nil {
+		t.Fatalf("error subscribing: %v", err)
+	}
+
+	var white, black domain.Player
+	err = players.WriteTx(func(repo ports.PlayerRepository) error {
+		if white, err = repo.CreatePlayer(domain.Player{Username: "white", FirstName: "Player", Email: "white@example.com"}); err != nil {
+			return err
+		}
+		black, err = repo.CreatePlayer(domain.Player{Username: "black", FirstName: "Player", Email: "black@example.com"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating players: %v", err)
+	}
+
+	tournament, err := ts.CreateTournament(ctx, tournamentcommands.CreateTournamentCommand{Name: "Open de Sants", TimeControl: "90+30"})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+	for _, player := range []domain.Player{white, black} {
+		if _, err := ts.RegisterPlayer(ctx, tournamentcommands.RegisterPlayerCommand{TournamentID: tournament.PublicID, PlayerID: player.PublicID}); err != nil {
+			t.Fatalf("error registering player: %v", err)
+		}
+	}
+	for _, action := range []domain.TournamentAction{domain.TournamentActionSubmit, domain.TournamentActionStart} {
+		if _, err := ts.TransitionTournament(ctx, tournamentcommands.TransitionTournamentCommand{ID: tournament.PublicID, Action: action, Actor: domain.Principal{Role: domain.RoleAdmin, ID: "arbiter"}}); err != nil {
+			t.Fatalf("error taking action %s: %v", action, err)
+		}
+	}
+	_, err = ts.PairRound(ctx, tournamentcommands.PairRoundCommand{
+		TournamentID: tournament.PublicID,
+		Pairings:     []tournamentcommands.Pairing{{White: white.PublicID, Black: black.PublicID}},
+	})
+	if err != nil {
+		t.Fatalf("error pairing round: %v", err)
+	}
+
+	if _, err := dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatalf("error dispatching events: %v", err)
+	}
+
+	// a confirmation of the registration and the pairings of the round to each player
+	sent, err := ns.SendDue(ctx)
+	if err != nil {
+		t.Fatalf("error sending notifications: %v", err)
+	}
+	if sent != 4 {
+		t.Fatalf("expected 4 emails sent, got %d", sent)
+	}
+
+	for _, player := range []domain.Player{white, black} {
+		var queued []domain.Notification
+		err := notifications.ReadTx(func(repo ports.NotificationRepository) error {
+			var err error
+			queued, err = repo.ListNotifications(player.PublicID, 0)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("error listing notifications: %v", err)
+		}
+
+		kinds := make(map[domain.NotificationKind]bool)
+		for _, n := range queued {
+			kinds[n.Kind] = true
+		}
+		if len(queued) != 2 || !kinds[domain.NotificationRegistrationConfirmed] || !kinds[domain.NotificationPairingsPublished] {
+			t.Errorf("unexpected notifications of %s: %+v", player.Username, queued)
+		}
+	}
+}
+
+func TestNotificationServicer_ChallengeAccepted(t *testing.T) {
+	f := newNotificationFixture(t, "a@example.com", "b@example.com")
+	challenger, opponent := f.players[0], f.players[1]
+
+	var challenge domain.Challenge
+	err := f.challenges.WriteTx(func(repo ports.ChallengeRepository) error {
+		var err error
+		challenge, err = repo.CreateChallenge(domain.Challenge{
+			ChallengerID: challenger.PublicID,
+			Location:     domain.Location{Name: "Bar Velódromo", City: "Barcelona", Latitude: 41.39, Longitude: 2.15},
+			StartsAt:     f.now.Add(time.Hour),
+			EndsAt:       f.now.Add(3 * time.Hour),
+			TimeControl:  domain.TimeControl{Periods: []domain.TimeControlPeriod{{Base: 10 * time.Minute}}},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating challenge: %v", err)
+	}
+
+	// the tournament of the fixture has nothing to do with it
+	event := domain.ChallengeAccepted{ChallengeID: challenge.PublicID, MatchID: uuid.New(), ChallengerID: challenger.PublicID, AcceptedBy: opponent.PublicID}
+	for range 2 {
+		if err := f.ns.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+
+	if sent := f.sendDue(t); sent != 2 {
+		t.Fatalf("expected an email to each player, got %d", sent)
+	}
+	for _, player := range []domain.Player{challenger, opponent} {
+		queued := f.queued(t, player)
+		if len(queued) != 1 || queued[0].Kind != domain.NotificationChallengeAccepted {
+			t.Fatalf("expected the challenge email for %s, got %+v", player.Username, queued)
+		}
+		if !strings.Contains(queued[0].Email.Text, "Bar Velódromo, Barcelona") {
+			t.Errorf("expected the location in the email, got\n%s", queued[0].Email.Text)
+		}
+	}
+	if text := f.queued(t, challenger)[0].Email.Text; !strings.Contains(text, "accepted your challenge") {
+		t.Errorf("expected the challenger to be told it was accepted, got\n%s", text)
+	}
+}
+
+func TestNotificationServicer_Retry(t *testing.T) {
+	f := newNotificationFixture(t, "a@example.com")
+	player := f.players[0]
+
+	f.smtp.FailNext(3)
+	for range 2 {
+		err := f.ns.HandleEvent(context.Background(), domain.PlayerRegistered{TournamentID: f.tournament.PublicID, PlayerID: player.PublicID})
+		if err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+
+	if sent := f.sendDue(t); sent != 0 {
+		t.Fatalf("expected the smtp server to refuse the emails, got %d sent", sent)
+	}
+	*f.now = f.now.Add(time.Minute)
+	if sent := f.sendDue(t); sent != 1 {
+		t.Fatalf("expected one email to go through on its retry, got %d", sent)
+	}
+
+	var failed, sent int
+	for _, n := range f.queued(t, player) {
+		switch n.Status {
+		case domain.NotificationFailed:
+			failed++
+			if n.Attempts != 2 || n.LastError == "" {
+				t.Errorf("expected the failure to be recorded, got %+v", n)
+			}
+		case domain.NotificationSent:
+			sent++
+		}
+	}
+	if failed != 1 || sent != 1 {
+		t.Errorf("expected one email sent and one given up on, got %d and %d", sent, failed)
+	}
+}
+
+func TestNotificationServicer_CancelledAfterUnsubscribing(t *testing.T) {
+	f := newNotificationFixture(t, "a@example.com")
+	player := f.players[0]
+
+	err := f.ns.HandleEvent(context.Background(), domain.PlayerRegistered{TournamentID: f.tournament.PublicID, PlayerID: player.PublicID})
+	if err != nil {
+		t.Fatalf("error handling event: %v", err)
+	}
+
+	_, err = f.ns.UpdatePreferences(context.Background(), commands.UpdatePreferencesCommand{PlayerID: player.PublicID, Unsubscribed: true})
+	if err != nil {
+		t.Fatalf("error updating preferences: %v", err)
+	}
+
+	if sent := f.sendDue(t); sent != 0 {
+		t.Fatalf("expected nothing sent, got %d", sent)
+	}
+	if queued := f.queued(t, player); len(queued) != 1 || queued[0].Status != domain.NotificationCancelled {
+		t.Errorf("expected the notification to be cancelled, got %+v", queued)
+	}
+	if len(f.smtp.Messages()) != 0 {
+		t.Errorf("expected no email")
+	}
+
+	if _, err := f.ns.FindPreferences(context.Background(), commands.FindPreferencesCommand{PlayerID: uuid.New()}); !errors.Is(err, domain.ErrPlayerNotFound) {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
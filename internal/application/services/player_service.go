@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+type PlayerServicer struct {
+	logger  ports.Logger
+	players ports.PlayerRepositoryProvider
+	matches ports.MatchRepositoryProvider
+}
+
+func NewPlayerServicer(log ports.Logger, pr ports.PlayerRepositoryProvider, mr ports.MatchRepositoryProvider) (ports.PlayerServicer, error) {
+	return &PlayerServicer{
+		logger:  log,
+		players: pr,
+		matches: mr,
+	}, nil
+}
+
+func (ps *PlayerServicer) CreatePlayer(ctx context.Context, cmd commands.CreatePlayerCommand) (domain.Player, error) {
+	player := domain.Player{
+		IsHuman:   true,
+		Username:  cmd.Username,
+		Email:     cmd.Email,
+		FirstName: cmd.FirstName,
+		LastName:  cmd.LastName,
+		Website:   cmd.Website,
+		FIDE: domain.Fide{
+			Title:   cmd.FideTitle,
+			Ratings: domain.Ratings(cmd.FideRatings),
+		},
+		Regional: domain.Regional{
+			Title:   cmd.RegionalTitle,
+			Country: cmd.Country,
+			City:    cmd.City,
+			Ratings: domain.Ratings(cmd.Ratings),
+		},
+	}
+
+	var result domain.Player
+	err := ps.players.WriteTx(func(repo ports.PlayerRepository) error {
+		var err error
+		result, err = repo.CreatePlayer(player)
+		return err
+	})
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	return result, nil
+}
+
+func (ps *PlayerServicer) UpdatePlayer(ctx context.Context, cmd commands.UpdatePlayerCommand) (domain.Player, error) {
+	var result domain.Player
+	err := ps.players.WriteTx(func(repo ports.PlayerRepository) error {
+		player, err := repo.FindPlayer(cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		applyString(&player.Username, cmd.Username)
+		applyString(&player.Email, cmd.Email)
+		applyString(&player.FirstName, cmd.FirstName)
+		applyString(&player.LastName, cmd.LastName)
+		applyString(&player.Website, cmd.Website)
+		applyString(&player.FIDE.Title, cmd.FideTitle)
+		applyString(&player.Regional.Title, cmd.RegionalTitle)
+		applyString(&player.Regional.Country, cmd.Country)
+		applyString(&player.Regional.City, cmd.City)
+
+		result, err = repo.UpdatePlayer(player)
+		return err
+	})
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	return result, nil
+}
+
+func (ps *PlayerServicer) FindPlayer(ctx context.Context, cmd commands.FindPlayerCommand) (domain.Player, error) {
+	var result domain.Player
+	err := ps.players.ReadTx(func(repo ports.PlayerRepository) error {
+		var err error
+		result, err = repo.FindPlayer(cmd.ID)
+		return err
+	})
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	return result, nil
+}
+
+// RecordRatingChange appends to the player's rating history and moves the player's
+// current rating for the source to the new value, both happen in the same transaction
+func (ps *PlayerServicer) RecordRatingChange(ctx context.Context, cmd commands.RecordRatingChangeCommand) (domain.RatingChange, error) {
+	recordedAt := cmd.RecordedAt
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+
+	var result domain.RatingChange
+	err := ps.players.WriteTx(func(repo ports.PlayerRepository) error {
+		player, err := repo.FindPlayer(cmd.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		ratings := currentRatings(&player, cmd.Source)
+		change := domain.RatingChange{
+			PlayerID:   player.PublicID,
+			Source:     cmd.Source,
+			Type:       cmd.Type,
+			EventID:    cmd.EventID,
+			EventName:  cmd.EventName,
+			Rating:     cmd.Rating,
+			RecordedAt: recordedAt,
+		}
+		if ratings != nil {
+			change.Previous = ratings.Get(cmd.Type)
+			ratings.Set(cmd.Type, cmd.Rating)
+			if _, err := repo.UpdatePlayer(player); err != nil {
+				return err
+			}
+		} else {
+			history, err := repo.ListRatingHistory(player.PublicID, cmd.Source, cmd.Type)
+			if err != nil {
+				return err
+			}
+			if len(history) > 0 {
+				change.Previous = history[len(history)-1].Rating
+			}
+		}
+
+		result, err = repo.AddRatingChange(change)
+		return err
+	})
+	if err != nil {
+		return domain.RatingChange{}, err
+	}
+
+	return result, nil
+}
+
+func (ps *PlayerServicer) RatingHistory(ctx context.Context, cmd commands.RatingHistoryCommand) ([]domain.RatingChange, error) {
+	var results []domain.RatingChange
+	err := ps.players.ReadTx(func(repo ports.PlayerRepository) error {
+		var err error
+		results, err = repo.ListRatingHistory(cmd.PlayerID, cmd.Source, cmd.Type)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// HeadToHead computes the record between two players from the stored matches
+func (ps *PlayerServicer) HeadToHead(ctx context.Context, cmd commands.HeadToHeadCommand) (domain.HeadToHead, error) {
+	err := ps.players.ReadTx(func(repo ports.PlayerRepository) error {
+		if _, err := repo.FindPlayer(cmd.PlayerID); err != nil {
+			return err
+		}
+		_, err := repo.FindPlayer(cmd.OpponentID)
+		return err
+	})
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+
+	var matches []domain.Match
+	err = ps.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		matches, err = repo.ListMatchesByPlayer(cmd.PlayerID)
+		return err
+	})
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+
+	return domain.NewHeadToHead(cmd.PlayerID, cmd.OpponentID, matches), nil
+}
+
+// currentRatings returns the ratings of the player that belong to the source,
+// maple ratings are not kept on the profile so nil is returned for them
+func currentRatings(player *domain.Player, source domain.RatingSource) *domain.Ratings {
+	switch source {
+	case domain.RatingSourceFIDE:
+		return &player.FIDE.Ratings
+	case domain.RatingSourceRegional:
+		return &player.Regional.Ratings
+	}
+	return nil
+}
+
+func applyString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func newTestPlayerServicer(t *testing.T) (ports.PlayerServicer, ports.MatchRepositoryProvider) {
+	t.Helper()
+
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+
+	return ps, matches
+}
+
+func createTestPlayer(t *testing.T, ps ports.PlayerServicer, username string) domain.Player {
+	t.Helper()
+
+	player, err := ps.CreatePlayer(context.Background(), commands.CreatePlayerCommand{
+		Username:    username,
+		Email:       username + "@example.com",
+		FirstName:   "Test",
+		LastName:    "Player",
+		FideRatings: commands.Ratings{Standard: 1800},
+	})
+	if err != nil {
+		t.Fatalf("error creating player: %v", err)
+	}
+
+	return player
+}
+
+func TestPlayerServicer_CreateAndFindPlayer(t *testing.T) {
+	ps, _ := newTestPlayerServicer(t)
+	created := createTestPlayer(t, ps, "maple")
+
+	found, err := ps.FindPlayer(context.Background(), commands.FindPlayerCommand{ID: created.PublicID})
+	if err != nil {
+		t.Fatalf("error finding player: %v", err)
+	}
+
+	if found.Username != "maple" {
+		t.Errorf("username is not correct: expected maple, got %s", found.Username)
+	}
+	if found.FIDE.Ratings.Get(domain.RatingTypeStandard) != 1800 {
+		t.Errorf("fide standard rating is not correct: expected 1800, got %d", found.FIDE.Ratings.Standard)
+	}
+
+	_, err = ps.FindPlayer(context.Background(), commands.FindPlayerCommand{ID: uuid.New()})
+	if !errors.Is(err, domain.ErrPlayerNotFound) {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestPlayerServicer_UpdatePlayer(t *testing.T) {
+	ps, _ := newTestPlayerServicer(t)
+	created := createTestPlayer(t, ps, "maple")
+
+	city := "Girona"
+	updated, err := ps.UpdatePlayer(context.Background(), commands.UpdatePlayerCommand{
+		ID:   created.PublicID,
+		City: &city,
+	})
+	if err != nil {
+		t.Fatalf("error updating player: %v", err)
+	}
+
+	if updated.Regional.City != city {
+		t.Errorf("city is not correct: expected %s, got %s", city, updated.Regional.City)
+	}
+	if updated.Username != created.Username {
+		t.Errorf("untouched fields should be kept: expected %s, got %s", created.Username, updated.Username)
+	}
+}
+
+func TestPlayerServicer_RatingHistory(t *testing.T) {
+	ps, _ := newTestPlayerServicer(t)
+	ctx := context.Background()
+	player := createTestPlayer(t, ps, "maple")
+
+	for _, rating := range []int{1812, 1830} {
+		_, err := ps.RecordRatingChange(ctx, commands.RecordRatingChangeCommand{
+			PlayerID: player.PublicID,
+			Source:   domain.RatingSourceFIDE,
+			Type:     domain.RatingTypeStandard,
+			Rating:   rating,
+		})
+		if err != nil {
+			t.Fatalf("error recording rating change: %v", err)
+		}
+	}
+
+	history, err := ps.RatingHistory(ctx, commands.RatingHistoryCommand{
+		PlayerID: player.PublicID,
+		Type:     domain.RatingTypeStandard,
+	})
+	if err != nil {
+		t.Fatalf("error reading rating history: %v", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("expected 2 rating changes, got %d", len(history))
+	}
+	if history[0].Previous != 1800 || history[0].Delta() != 12 {
+		t.Errorf("first change is not correct: got previous %d, delta %d", history[0].Previous, history[0].Delta())
+	}
+	if history[1].Previous != 1812 || history[1].Rating != 1830 {
+		t.Errorf("second change is not correct: got previous %d, rating %d", history[1].Previous, history[1].Rating)
+	}
+
+	found, err := ps.FindPlayer(ctx, commands.FindPlayerCommand{ID: player.PublicID})
+	if err != nil {
+		t.Fatalf("error finding player: %v", err)
+	}
+	if found.FIDE.Ratings.Standard != 1830 {
+		t.Errorf("current rating is not correct: expected 1830, got %d", found.FIDE.Ratings.Standard)
+	}
+}
+
+func TestPlayerServicer_HeadToHead(t *testing.T) {
+	ps, matches := newTestPlayerServicer(t)
+	a := createTestPlayer(t, ps, "alice")
+	b := createTestPlayer(t, ps, "bob")
+	c := createTestPlayer(t, ps, "carol")
+
+	games := []domain.Match{
+		{WhitePlayer: a.PublicID, BlackPlayer: b.PublicID, Result: domain.MatchResultWhiteWins},
+		{WhitePlayer: b.PublicID, BlackPlayer: a.PublicID, Result: domain.MatchResultWhiteWins},
+		{WhitePlayer: b.PublicID, BlackPlayer: a.PublicID, Result: domain.MatchResultDraw},
+		{WhitePlayer: a.PublicID, BlackPlayer: b.PublicID, Result: domain.MatchResultBlackWins},
+		{WhitePlayer: a.PublicID, BlackPlayer: b.PublicID, Result: domain.MatchResultOngoing},
+		{WhitePlayer: a.PublicID, BlackPlayer: c.PublicID, Result: domain.MatchResultWhiteWins},
+	}
+	err := matches.WriteTx(func(repo ports.MatchRepository) error {
+		for _, g := range games {
+			if _, err := repo.CreateMatch(g); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error creating matches: %v", err)
+	}
+
+	h2h, err := ps.HeadToHead(context.Background(), commands.HeadToHeadCommand{
+		PlayerID:   a.PublicID,
+		OpponentID: b.PublicID,
+	})
+	if err != nil {
+		t.Fatalf("error computing head to head: %v", err)
+	}
+
+	if h2h.Wins != 1 || h2h.Losses != 2 || h2h.Draws != 1 {
+		t.Errorf("record is not correct: got +%d -%d =%d", h2h.Wins, h2h.Losses, h2h.Draws)
+	}
+	if len(h2h.Matches) != 4 {
+		t.Errorf("expected 4 finished matches, got %d", len(h2h.Matches))
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"math"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// EloConfig holds the K-factors used by the elo calculator, the defaults follow the FIDE rules
+type EloConfig struct {
+	InitialRating    float64
+	ProvisionalGames int     // a player is provisional until they played this many games in the pool
+	ProvisionalK     float64 // K while provisional, new players move faster towards their strength
+	K                float64
+	MasterK          float64 // K once the player reached MasterThreshold
+	MasterThreshold  float64
+	Floor            float64 // a rating never drops below the floor
+}
+
+func DefaultEloConfig() EloConfig {
+	return EloConfig{
+		InitialRating:    1500,
+		ProvisionalGames: 30,
+		ProvisionalK:     40,
+		K:                20,
+		MasterK:          10,
+		MasterThreshold:  2400,
+		Floor:            100,
+	}
+}
+
+type EloCalculator struct {
+	config EloConfig
+}
+
+func NewEloCalculator(cfg EloConfig) ports.RatingCalculator {
+	return &EloCalculator{config: cfg}
+}
+
+func (e *EloCalculator) Algorithm() domain.RatingAlgorithm {
+	return domain.RatingAlgorithmElo
+}
+
+func (e *EloCalculator) NewRating(playerID uuid.UUID, pool domain.RatingType) domain.MapleRating {
+	return domain.MapleRating{
+		PlayerID:    playerID,
+		Pool:        pool,
+		Rating:      e.config.InitialRating,
+		Provisional: e.config.ProvisionalGames > 0,
+	}
+}
+
+func (e *EloCalculator) Rate(white, black domain.MapleRating, score float64) (domain.MapleRating, domain.MapleRating) {
+	expected := eloExpectedScore(white.Rating, black.Rating)
+
+	newWhite := e.update(white, score-expected)
+	newBlack := e.update(black, (1-score)-(1-expected))
+
+	return newWhite, newBlack
+}
+
+func (e *EloCalculator) update(r domain.MapleRating, diff float64) domain.MapleRating {
+	r.Rating = math.Max(e.config.Floor, r.Rating+e.kFactor(r)*diff)
+	r.Games++
+	r.Provisional = r.Games < e.config.ProvisionalGames
+
+	return r
+}
+
+func (e *EloCalculator) kFactor(r domain.MapleRating) float64 {
+	switch {
+	case r.Games < e.config.ProvisionalGames:
+		return e.config.ProvisionalK
+	case r.Rating >= e.config.MasterThreshold:
+		return e.config.MasterK
+	}
+	return e.config.K
+}
+
+// eloExpectedScore is the score a player rated a is expected to get against a player rated b
+func eloExpectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"math"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// glicko2Scale converts between the glicko and glicko-2 scales
+const glicko2Scale = 173.7178
+
+// glicko2Epsilon is the convergence tolerance of the volatility iteration
+const glicko2Epsilon = 0.000001
+
+// Glicko2Config holds the system constants of the glicko-2 calculator
+type Glicko2Config struct {
+	InitialRating        float64
+	InitialDeviation     float64
+	InitialVolatility    float64
+	Tau                  float64 // constrains the change in volatility over time, 0.3 to 1.2
+	ProvisionalDeviation float64 // a player is provisional while their deviation is above this
+}
+
+func DefaultGlicko2Config() Glicko2Config {
+	return Glicko2Config{
+		InitialRating:        1500,
+		InitialDeviation:     350,
+		InitialVolatility:    0.06,
+		Tau:                  0.5,
+		ProvisionalDeviation: 110,
+	}
+}
+
+// Glicko2Calculator rates every game as its own rating period
+type Glicko2Calculator struct {
+	config Glicko2Config
+}
+
+func NewGlicko2Calculator(cfg Glicko2Config) ports.RatingCalculator {
+	return &Glicko2Calculator{config: cfg}
+}
+
+func (g *Glicko2Calculator) Algorithm() domain.RatingAlgorithm {
+	return domain.RatingAlgorithmGlicko2
+}
+
+func (g *Glicko2Calculator) NewRating(playerID uuid.UUID, pool domain.RatingType) domain.MapleRating {
+	return domain.MapleRating{
+		PlayerID:    playerID,
+		Pool:        pool,
+		Rating:      g.config.InitialRating,
+		Deviation:   g.config.InitialDeviation,
+		Volatility:  g.config.InitialVolatility,
+		Provisional: g.config.InitialDeviation > g.config.ProvisionalDeviation,
+	}
+}
+
+func (g *Glicko2Calculator) Rate(white, black domain.MapleRating, score float64) (domain.MapleRating, domain.MapleRating) {
+	newWhite := g.update(white, []domain.MapleRating{black}, []float64{score})
+	newBlack := g.update(black, []domain.MapleRating{white}, []float64{1 - score})
+
+	return newWhite, newBlack
+}
+
+// update applies one rating period to r, following the steps in Glickman's
+// "Example of the Glicko-2 system"
+func (g *Glicko2Calculator) update(r domain.MapleRating, opponents []domain.MapleRating, scores []float64) domain.MapleRating {
+	mu := (r.Rating - 1500) / glicko2Scale
+	phi := r.Deviation / glicko2Scale
+	sigma := r.Volatility
+
+	var vInv, deltaSum float64
+	for i, o := range opponents {
+		muJ := (o.Rating - 1500) / glicko2Scale
+		gPhiJ := glicko2G(o.Deviation / glicko2Scale)
+		e := 1 / (1 + math.Exp(-gPhiJ*(mu-muJ)))
+
+		vInv += gPhiJ * gPhiJ * e * (1 - e)
+		deltaSum += gPhiJ * (scores[i] - e)
+	}
+	v := 1 / vInv
+	delta := v * deltaSum
+
+	sigma = g.volatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*deltaSum
+
+	r.Rating = newMu*glicko2Scale + 1500
+	r.Deviation = math.Min(newPhi*glicko2Scale, g.config.InitialDeviation)
+	r.Volatility = sigma
+	r.Games += len(opponents)
+	r.Provisional = r.Deviation > g.config.ProvisionalDeviation
+
+	return r
+}
+
+// volatility finds the new volatility with the Illinois algorithm (step 5)
+func (g *Glicko2Calculator) volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau := g.config.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA = fA / 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
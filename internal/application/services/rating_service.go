@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// RatingServicer is the maple rating engine, it keeps a rating per player and pool
+// for casual and club games that will never be FIDE rated
+type RatingServicer struct {
+	logger     ports.Logger
+	calculator ports.RatingCalculator
+	ratings    ports.RatingRepositoryProvider
+	players    ports.PlayerRepositoryProvider
+	matches    ports.MatchRepositoryProvider
+	// mu serialises applying matches and recomputing so ratings are always
+	// the result of replaying matches one after the other
+	mu sync.Mutex
+}
+
+func NewRatingServicer(log ports.Logger, calc ports.RatingCalculator, rr ports.RatingRepositoryProvider, pr ports.PlayerRepositoryProvider, mr ports.MatchRepositoryProvider) (ports.RatingServicer, error) {
+	if calc == nil {
+		return nil, errors.New("rating calculator is required")
+	}
+
+	return &RatingServicer{
+		logger:     log,
+		calculator: calc,
+		ratings:    rr,
+		players:    pr,
+		matches:    mr,
+	}, nil
+}
+
+// ApplyMatch updates both players' ratings, matches that are not rated or
+// not finished are ignored
+func (rs *RatingServicer) ApplyMatch(ctx context.Context, match domain.Match) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	return rs.applyMatch(match)
+}
+
+// HandleEvent updates the ratings when the result of a rated match is recorded, it is subscribed
+// to the event bus for domain.EventResultRecorded so a failed update is retried. A corrected
+// result recomputes every rating so the outcome is the same as if the right result had been
+// recorded in the first place
+func (rs *RatingServicer) HandleEvent(ctx context.Context, event domain.Event) error {
+	recorded, ok := event.(domain.ResultRecorded)
+	if !ok || !recorded.Rated || !recorded.Result.Finished() {
+		return nil
+	}
+
+	if recorded.Previous.Finished() {
+		rs.logger.Info(ctx, "match result corrected, recomputing ratings", ports.String("match", recorded.MatchID.String()))
+		_, err := rs.Recompute(ctx)
+		return err
+	}
+
+	var match domain.Match
+	err := rs.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		match, err = repo.FindMatch(recorded.MatchID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return rs.ApplyMatch(ctx, match)
+}
+
+// Recompute replays the completed matches in the order they finished and replaces every
+// maple rating with the result, which only depends on the match history. The new ratings
+// are built first and swapped in at once, readers see either the old ones or the new ones
+func (rs *RatingServicer) Recompute(ctx context.Context) (int, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var matches []domain.Match
+	err := rs.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		matches, err = repo.ListCompletedMatches()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	type ratingKey struct {
+		playerID uuid.UUID
+		pool     domain.RatingType
+	}
+	ratings := make(map[ratingKey]domain.MapleRating)
+	current := func(playerID uuid.UUID, pool domain.RatingType) domain.MapleRating {
+		if rating, ok := ratings[ratingKey{playerID: playerID, pool: pool}]; ok {
+			return rating
+		}
+		return rs.calculator.NewRating(playerID, pool)
+	}
+
+	var changes []domain.RatingChange
+	replayed := 0
+	for _, match := range matches {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		score, ok := match.Result.Score()
+		if !ok || !match.Rated {
+			continue
+		}
+
+		pool := ratingPool(match)
+		oldWhite, oldBlack := current(match.WhitePlayer, pool), current(match.BlackPlayer, pool)
+		newWhite, newBlack := rs.rate(match, score, oldWhite, oldBlack)
+		ratings[ratingKey{playerID: newWhite.PlayerID, pool: pool}] = newWhite
+		ratings[ratingKey{playerID: newBlack.PlayerID, pool: pool}] = newBlack
+		changes = append(changes, ratingChanges(match, pool, oldWhite, oldBlack, newWhite, newBlack)...)
+		replayed++
+	}
+
+	// the history is swapped while the ratings are locked so no reader sees one without the other
+	err = rs.ratings.WriteTx(func(ratingRepo ports.RatingRepository) error {
+		if err := ratingRepo.DeleteRatings(); err != nil {
+			return err
+		}
+		for _, rating := range ratings {
+			if _, err := ratingRepo.SaveRating(rating); err != nil {
+				return err
+			}
+		}
+
+		return rs.players.WriteTx(func(playerRepo ports.PlayerRepository) error {
+			if err := playerRepo.DeleteRatingHistory(domain.RatingSourceMaple); err != nil {
+				return err
+			}
+			return addRatingChanges(playerRepo, changes)
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rs.logger.Info(ctx, "maple ratings recomputed", ports.Int("matches", replayed), ports.String("algorithm", string(rs.calculator.Algorithm())))
+
+	return replayed, nil
+}
+
+func (rs *RatingServicer) FindRating(ctx context.Context, cmd commands.FindRatingCommand) (domain.MapleRating, error) {
+	var result domain.MapleRating
+	err := rs.ratings.ReadTx(func(repo ports.RatingRepository) error {
+		var err error
+		result, err = repo.FindRating(cmd.PlayerID, cmd.Pool)
+		return err
+	})
+	if errors.Is(err, domain.ErrRatingNotFound) {
+		// the player has not played in this pool yet
+		return rs.calculator.NewRating(cmd.PlayerID, cmd.Pool), nil
+	}
+	if err != nil {
+		return domain.MapleRating{}, err
+	}
+
+	return result, nil
+}
+
+func (rs *RatingServicer) ListRatings(ctx context.Context, cmd commands.ListRatingsCommand) ([]domain.MapleRating, error) {
+	var ratings []domain.MapleRating
+	err := rs.ratings.ReadTx(func(repo ports.RatingRepository) error {
+		var err error
+		ratings, err = repo.ListRatings(cmd.Pool)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the players a moderator hid are left out of the public ladder
+	listed := make([]domain.MapleRating, 0, len(ratings))
+	err = rs.players.ReadTx(func(repo ports.PlayerRepository) error {
+		for _, r := range ratings {
+			if !cmd.IncludeProvisional && r.Provisional {
+				continue
+			}
+			player, err := repo.FindPlayer(r.PlayerID)
+			if err == nil && !player.Moderation.Public() {
+				continue
+			}
+			listed = append(listed, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return listed, nil
+}
+
+// applyMatch saves the ratings and the history of the match at once, a match already in the
+// history is not applied again when its event is delivered twice
+func (rs *RatingServicer) applyMatch(match domain.Match) error {
+	score, ok := match.Result.Score()
+	if !ok || !match.Rated {
+		return nil
+	}
+
+	pool := ratingPool(match)
+	return rs.ratings.WriteTx(func(ratingRepo ports.RatingRepository) error {
+		return rs.players.WriteTx(func(playerRepo ports.PlayerRepository) error {
+			applied, err := ratingApplied(playerRepo, match, pool)
+			if err != nil || applied {
+				return err
+			}
+
+			oldWhite, err := rs.findOrNewRating(ratingRepo, match.WhitePlayer, pool)
+			if err != nil {
+				return err
+			}
+			oldBlack, err := rs.findOrNewRating(ratingRepo, match.BlackPlayer, pool)
+			if err != nil {
+				return err
+			}
+
+			newWhite, newBlack := rs.rate(match, score, oldWhite, oldBlack)
+			if _, err := ratingRepo.SaveRating(newWhite); err != nil {
+				return err
+			}
+			if _, err := ratingRepo.SaveRating(newBlack); err != nil {
+				return err
+			}
+			return addRatingChanges(playerRepo, ratingChanges(match, pool, oldWhite, oldBlack, newWhite, newBlack))
+		})
+	})
+}
+
+// rate is the ratings of the players after the match
+func (rs *RatingServicer) rate(match domain.Match, score float64, white, black domain.MapleRating) (domain.MapleRating, domain.MapleRating) {
+	white, black = rs.calculator.Rate(white, black, score)
+	// the completion time keeps recomputed ratings identical to the original ones
+	white.UpdatedAt = match.CompletedAt
+	black.UpdatedAt = match.CompletedAt
+
+	return white, black
+}
+
+// ratingPool is the pool the match is rated in, the matches without a rating type are standard
+func ratingPool(match domain.Match) domain.RatingType {
+	if !match.RatingType.Valid() {
+		return domain.RatingTypeStandard
+	}
+	return match.RatingType
+}
+
+func ratingChanges(match domain.Match, pool domain.RatingType, oldWhite, oldBlack, newWhite, newBlack domain.MapleRating) []domain.RatingChange {
+	changes := make([]domain.RatingChange, 0, 2)
+	for _, r := range [][2]domain.MapleRating{{oldWhite, newWhite}, {oldBlack, newBlack}} {
+		changes = append(changes, domain.RatingChange{
+			PlayerID:   r[1].PlayerID,
+			Source:     domain.RatingSourceMaple,
+			Type:       pool,
+			EventID:    match.UUID,
+			Previous:   r[0].Rounded(),
+			Rating:     r[1].Rounded(),
+			RecordedAt: match.CompletedAt,
+		})
+	}
+	return changes
+}
+
+// ratingApplied tells whether the match is in the maple history of one of its players
+func ratingApplied(repo ports.PlayerRepository, match domain.Match, pool domain.RatingType) (bool, error) {
+	for _, playerID := range []uuid.UUID{match.WhitePlayer, match.BlackPlayer} {
+		history, err := repo.ListRatingHistory(playerID, domain.RatingSourceMaple, pool)
+		if errors.Is(err, domain.ErrPlayerNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, change := range history {
+			if change.EventID == match.UUID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func addRatingChanges(repo ports.PlayerRepository, changes []domain.RatingChange) error {
+	for _, change := range changes {
+		_, err := repo.AddRatingChange(change)
+		// players removed since the match was played keep their rating but lose the history
+		if err != nil && !errors.Is(err, domain.ErrPlayerNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *RatingServicer) findOrNewRating(repo ports.RatingRepository, playerID uuid.UUID, pool domain.RatingType) (domain.MapleRating, error) {
+	rating, err := repo.FindRating(playerID, pool)
+	if errors.Is(err, domain.ErrRatingNotFound) {
+		return rs.calculator.NewRating(playerID, pool), nil
+	}
+
+	return rating, err
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	matchcommands "github.com/ctfrancia/maple/internal/application/commands/match"
+	playercommands "github.com/ctfrancia/maple/internal/application/commands/player"
+	commands "github.com/ctfrancia/maple/internal/application/commands/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func TestEloCalculator_Rate(t *testing.T) {
+	cfg := DefaultEloConfig()
+	calc := NewEloCalculator(cfg)
+
+	established := func(rating float64) domain.MapleRating {
+		return domain.MapleRating{PlayerID: uuid.New(), Rating: rating, Games: cfg.ProvisionalGames}
+	}
+
+	tests := []struct {
+		name      string
+		white     domain.MapleRating
+		black     domain.MapleRating
+		score     float64
+		wantWhite float64
+		wantBlack float64
+	}{
+		{
+			name:      "provisional players win between equals",
+			white:     calc.NewRating(uuid.New(), domain.RatingTypeStandard),
+			black:     calc.NewRating(uuid.New(), domain.RatingTypeStandard),
+			score:     1,
+			wantWhite: 1520,
+			wantBlack: 1480,
+		},
+		{
+			name:      "established players draw between equals",
+			white:     established(1600),
+			black:     established(1600),
+			score:     0.5,
+			wantWhite: 1600,
+			wantBlack: 1600,
+		},
+		{
+			name:      "established upset",
+			white:     established(1400),
+			black:     established(1800),
+			score:     1,
+			wantWhite: 1400 + 20*(1-1/(1+math.Pow(10, 1))),
+			wantBlack: 1800 - 20*(1-1/(1+math.Pow(10, 1))),
+		},
+		{
+			name:      "master uses the lower K-factor",
+			white:     established(2500),
+			black:     established(2500),
+			score:     0,
+			wantWhite: 2495,
+			wantBlack: 2505,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			white, black := calc.Rate(tt.white, tt.black, tt.score)
+
+			if math.Abs(white.Rating-tt.wantWhite) > 0.001 {
+				t.Errorf("white rating = %f, want %f", white.Rating, tt.wantWhite)
+			}
+			if math.Abs(black.Rating-tt.wantBlack) > 0.001 {
+				t.Errorf("black rating = %f, want %f", black.Rating, tt.wantBlack)
+			}
+			if white.Games != tt.white.Games+1 || black.Games != tt.black.Games+1 {
+				t.Errorf("games were not counted")
+			}
+		})
+	}
+}
+
+func TestEloCalculator_ProvisionalEnds(t *testing.T) {
+	cfg := DefaultEloConfig()
+	cfg.ProvisionalGames = 2
+	calc := NewEloCalculator(cfg)
+
+	white := calc.NewRating(uuid.New(), domain.RatingTypeBlitz)
+	black := calc.NewRating(uuid.New(), domain.RatingTypeBlitz)
+
+	white, black = calc.Rate(white, black, 0.5)
+	if !white.Provisional || !black.Provisional {
+		t.Errorf("ratings should still be provisional after 1 game")
+	}
+
+	white, _ = calc.Rate(white, black, 0.5)
+	if white.Provisional {
+		t.Errorf("rating should not be provisional after 2 games")
+	}
+}
+
+// TestGlicko2Calculator_Example checks the worked example of Glickman's paper
+func TestGlicko2Calculator_Example(t *testing.T) {
+	calc := &Glicko2Calculator{config: DefaultGlicko2Config()}
+
+	player := domain.MapleRating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	opponents := []domain.MapleRating{
+		{Rating: 1400, Deviation: 30},
+		{Rating: 1550, Deviation: 100},
+		{Rating: 1700, Deviation: 300},
+	}
+
+	result := calc.update(player, opponents, []float64{1, 0, 0})
+
+	if math.Abs(result.Rating-1464.06) > 0.01 {
+		t.Errorf("rating = %f, want 1464.06", result.Rating)
+	}
+	if math.Abs(result.Deviation-151.52) > 0.01 {
+		t.Errorf("deviation = %f, want 151.52", result.Deviation)
+	}
+	if math.Abs(result.Volatility-0.05999) > 0.00001 {
+		t.Errorf("volatility = %f, want 0.05999", result.Volatility)
+	}
+}
+
+type ratingTestEnv struct {
+	players    ports.PlayerServicer
+	matches    ports.MatchServicer
+	ratings    ports.RatingServicer
+	dispatcher *EventDispatcher
+}
+
+func newRatingTestEnv(t *testing.T) ratingTestEnv {
+	t.Helper()
+
+	pp := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	mp := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), outbox)
+	rp := inmemory.NewRatingRepositoryProvider(inmemory.NewInMemoryRatingRepository())
+
+	ps, err := NewPlayerServicer(lggr, pp, mp)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	rs, err := NewRatingServicer(lggr, NewEloCalculator(DefaultEloConfig()), rp, pp, mp)
+	if err != nil {
+		t.Fatalf("error creating rating service: %v", err)
+	}
+	tp := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	ms, err := NewMatchServicer(lggr, mp, pp, tp, nil)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+	dispatcher := NewEventDispatcher(lggr, outbox, DefaultEventDispatcherConfig())
+	if err := dispatcher.Subscribe("ratings", rs.HandleEvent, domain.EventResultRecorded); err != nil {
+		t.Fatalf("error subscribing: %v", err)
+	}
+
+	return ratingTestEnv{players: ps, matches: ms, ratings: rs, dispatcher: dispatcher}
+}
+
+func (env ratingTestEnv) play(t *testing.T, white, black uuid.UUID, rated bool, result domain.MatchResult) domain.Match {
+	t.Helper()
+	ctx := context.Background()
+
+	match, err := env.matches.CreateMatch(ctx, matchcommands.CreateMatchCommand{
+		WhitePlayer: white,
+		BlackPlayer: black,
+		Rated:       rated,
+		TimeControl: "15+10",
+	})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+
+	match, err = env.matches.RecordResult(ctx, matchcommands.RecordResultCommand{ID: match.UUID, Result: result})
+	if err != nil {
+		t.Fatalf("error recording result: %v", err)
+	}
+	if _, err := env.dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatalf("error dispatching events: %v", err)
+	}
+
+	return match
+}
+
+func (env ratingTestEnv) rating(t *testing.T, playerID uuid.UUID) domain.MapleRating {
+	t.Helper()
+
+	rating, err := env.ratings.FindRating(context.Background(), commands.FindRatingCommand{PlayerID: playerID, Pool: domain.RatingTypeRapid})
+	if err != nil {
+		t.Fatalf("error finding rating: %v", err)
+	}
+
+	return rating
+}
+
+func TestRatingServicer_OnlyRatedMatchesCount(t *testing.T) {
+	env := newRatingTestEnv(t)
+	a := createTestPlayer(t, env.players, "alice")
+	b := createTestPlayer(t, env.players, "bob")
+
+	env.play(t, a.PublicID, b.PublicID, false, domain.MatchResultWhiteWins)
+	if got := env.rating(t, a.PublicID); got.Games != 0 || got.Rating != 1500 {
+		t.Errorf("unrated match changed the rating: %+v", got)
+	}
+
+	env.play(t, a.PublicID, b.PublicID, true, domain.MatchResultWhiteWins)
+	if got := env.rating(t, a.PublicID); got.Games != 1 || got.Rounded() != 1520 {
+		t.Errorf("rated match was not applied: %+v", got)
+	}
+}
+
+// TestRatingServicer_CorrectionIsDeterministic corrects an old result and expects the same
+// ratings as a history where the right result was recorded from the start
+func TestRatingServicer_CorrectionIsDeterministic(t *testing.T) {
+	corrected := newRatingTestEnv(t)
+	ctx := context.Background()
+	a := createTestPlayer(t, corrected.players, "alice")
+	b := createTestPlayer(t, corrected.players, "bob")
+	c := createTestPlayer(t, corrected.players, "carol")
+
+	first := corrected.play(t, a.PublicID, b.PublicID, true, domain.MatchResultWhiteWins)
+	corrected.play(t, b.PublicID, c.PublicID, true, domain.MatchResultDraw)
+	corrected.play(t, c.PublicID, a.PublicID, true, domain.MatchResultBlackWins)
+
+	_, err := corrected.matches.RecordResult(ctx, matchcommands.RecordResultCommand{ID: first.UUID, Result: domain.MatchResultBlackWins})
+	if err != nil {
+		t.Fatalf("error correcting result: %v", err)
+	}
+	if _, err := corrected.dispatcher.DispatchPending(ctx); err != nil {
+		t.Fatalf("error dispatching events: %v", err)
+	}
+
+	replay := newRatingTestEnv(t)
+	ra := createTestPlayer(t, replay.players, "alice")
+	rb := createTestPlayer(t, replay.players, "bob")
+	rc := createTestPlayer(t, replay.players, "carol")
+	replay.play(t, ra.PublicID, rb.PublicID, true, domain.MatchResultBlackWins)
+	replay.play(t, rb.PublicID, rc.PublicID, true, domain.MatchResultDraw)
+	replay.play(t, rc.PublicID, ra.PublicID, true, domain.MatchResultBlackWins)
+
+	pairs := [][2]uuid.UUID{{a.PublicID, ra.PublicID}, {b.PublicID, rb.PublicID}, {c.PublicID, rc.PublicID}}
+	for _, p := range pairs {
+		got, want := corrected.rating(t, p[0]), replay.rating(t, p[1])
+		if math.Abs(got.Rating-want.Rating) > 1e-9 || got.Games != want.Games {
+			t.Errorf("corrected rating = %f (%d games), want %f (%d games)", got.Rating, got.Games, want.Rating, want.Games)
+		}
+	}
+
+	history, err := corrected.players.RatingHistory(ctx, playercommands.RatingHistoryCommand{
+		PlayerID: a.PublicID,
+		Source:   domain.RatingSourceMaple,
+	})
+	if err != nil {
+		t.Fatalf("error reading rating history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("expected the maple history to be rebuilt with 2 changes, got %d", len(history))
+	}
+}
+
+// TestRatingServicer_CancelledRecomputeKeepsTheRatings stops the replay halfway and expects
+// the ratings from before it, nothing is replaced until the new ones are all built
+func TestRatingServicer_CancelledRecomputeKeepsTheRatings(t *testing.T) {
+	env := newRatingTestEnv(t)
+	a := createTestPlayer(t, env.players, "alice")
+	b := createTestPlayer(t, env.players, "bob")
+	env.play(t, a.PublicID, b.PublicID, true, domain.MatchResultWhiteWins)
+	before := env.rating(t, a.PublicID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := env.ratings.Recompute(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the recompute to be cancelled, got %v", err)
+	}
+
+	if got := env.rating(t, a.PublicID); got != before {
+		t.Errorf("cancelled recompute changed the rating: %+v, want %+v", got, before)
+	}
+}
+
+// TestRatingServicer_RedeliveredResultIsAppliedOnce delivers the same result twice, as the
+// event bus does when an earlier delivery failed, and expects it to count once
+func TestRatingServicer_RedeliveredResultIsAppliedOnce(t *testing.T) {
+	env := newRatingTestEnv(t)
+	a := createTestPlayer(t, env.players, "alice")
+	b := createTestPlayer(t, env.players, "bob")
+	match := env.play(t, a.PublicID, b.PublicID, true, domain.MatchResultWhiteWins)
+
+	event := domain.ResultRecorded{
+		MatchID:     match.UUID,
+		WhitePlayer: match.WhitePlayer,
+		BlackPlayer: match.BlackPlayer,
+		Result:      match.Result,
+		Rated:       true,
+	}
+	if err := env.ratings.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("error handling the event again: %v", err)
+	}
+
+	if got := env.rating(t, a.PublicID); got.Games != 1 || got.Rounded() != 1520 {
+		t.Errorf("redelivered result was applied again: %+v", got)
+	}
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	matchcommands "github.com/ctfrancia/maple/internal/application/commands/match"
+	commands "github.com/ctfrancia/maple/internal/application/commands/relay"
+	"github.com/ctfrancia/maple/internal/core/chess"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// RelayServicer relays the games of the broadcast boards move by move. Every move is checked
+// against the position, the spectators of the tournament see it live and the final pgn is
+// written to the match when the game ends
+type RelayServicer struct {
+	logger      ports.Logger
+	relays      ports.RelayRepositoryProvider
+	matches     ports.MatchRepositoryProvider
+	players     ports.PlayerRepositoryProvider
+	tournaments ports.TournamentRepositoryProvider
+	results     ports.MatchServicer
+	broadcaster ports.LiveBroadcaster
+	now         func() time.Time
+}
+
+func NewRelayServicer(log ports.Logger, rr ports.RelayRepositoryProvider, mr ports.MatchRepositoryProvider, pr ports.PlayerRepositoryProvider, tr ports.TournamentRepositoryProvider, ms ports.MatchServicer, broadcaster ports.LiveBroadcaster) (ports.RelayServicer, error) {
+	return &RelayServicer{
+		logger:      log,
+		relays:      rr,
+		matches:     mr,
+		players:     pr,
+		tournaments: tr,
+		results:     ms,
+		broadcaster: broadcaster,
+		now:         time.Now,
+	}, nil
+}
+
+// StartRelay starts relaying the match, both clocks start with the base of its time control
+func (rs *RelayServicer) StartRelay(ctx context.Context, cmd commands.StartRelayCommand) (domain.Relay, error) {
+	var match domain.Match
+	err := rs.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		match, err = repo.FindMatch(cmd.MatchID)
+		return err
+	})
+	if err != nil {
+		return domain.Relay{}, err
+	}
+	if match.Result.Finished() {
+		return domain.Relay{}, domain.ErrMatchFinished
+	}
+
+	initial := chess.StartingPosition()
+	if cmd.FEN != "" {
+		if initial, err = chess.ParseFEN(cmd.FEN); err != nil {
+			return domain.Relay{}, err
+		}
+	}
+
+	now := rs.now()
+	clock := match.TimeControl.InitialClock()
+	relay := domain.Relay{
+		MatchID:      match.UUID,
+		TournamentID: match.TournamentID,
+		TimeControl:  match.TimeControl,
+		InitialFEN:   initial.FEN(),
+		FEN:          initial.FEN(),
+		WhiteClock:   clock,
+		BlackClock:   clock,
+		Status:       domain.RelayLive,
+		Result:       domain.MatchResultOngoing,
+		StartedAt:    now,
+		LastMoveAt:   now,
+	}
+
+	err = rs.relays.WriteTx(func(repo ports.RelayRepository) error {
+		relay, err = repo.CreateRelay(relay)
+		return err
+	})
+	if err != nil {
+		return domain.Relay{}, err
+	}
+
+	rs.publish(ctx, relay, domain.LiveRelayStarted, nil)
+
+	return relay, nil
+}
+
+func (rs *RelayServicer) FindRelay(ctx context.Context, cmd commands.FindRelayCommand) (domain.Relay, error) {
+	var relay domain.Relay
+	err := rs.relays.ReadTx(func(repo ports.RelayRepository) error {
+		var err error
+		relay, err = repo.FindRelay(cmd.MatchID)
+		return err
+	})
+
+	return relay, err
+}
+
+func (rs *RelayServicer) RecordMove(ctx context.Context, cmd commands.RecordMoveCommand) (domain.Relay, error) {
+	var relay domain.Relay
+	var recorded bool
+	err := rs.relays.WriteTx(func(repo ports.RelayRepository) error {
+		var err error
+		relay, err = repo.FindRelay(cmd.MatchID)
+		if err != nil {
+			return err
+		}
+
+		// a board repeating the last move it sent is answered with the relay as it is
+		if cmd.Ply > 0 && cmd.Ply <= len(relay.Moves) {
+			if sent := relay.Moves[cmd.Ply-1]; sameMove(sent, cmd.Move) {
+				return nil
+			}
+			return domain.ErrRelayOutOfSync
+		}
+		if relay.Status == domain.RelayFinished {
+			return domain.ErrRelayFinished
+		}
+		if cmd.Ply > 0 && cmd.Ply != len(relay.Moves)+1 {
+			return domain.ErrRelayOutOfSync
+		}
+
+		game, err := replay(relay)
+		if err != nil {
+			return err
+		}
+
+		before := game.Position()
+		move, san, err := game.Play(cmd.Move)
+		if errors.Is(err, chess.ErrIllegalMove) {
+			return fmt.Errorf("%w: %s", domain.ErrIllegalMove, cmd.Move)
+		}
+		if err != nil {
+			return err
+		}
+
+		now := rs.now()
+		clock := &relay.WhiteClock
+		if before.Turn == chess.Black {
+			clock = &relay.BlackClock
+		}
+		if cmd.Clock != nil {
+			*clock = *cmd.Clock
+		} else {
+			*clock = relay.TimeControl.ClockAfterMove(*clock, now.Sub(relay.LastMoveAt), before.FullmoveNumber)
+		}
+
+		relay.FEN = game.Position().FEN()
+		relay.LastMoveAt = now
+		relay.Moves = append(relay.Moves, domain.RelayMove{
+			Ply:        len(relay.Moves) + 1,
+			SAN:        san,
+			UCI:        move.UCI(),
+			FEN:        relay.FEN,
+			WhiteClock: relay.WhiteClock,
+			BlackClock: relay.BlackClock,
+			At:         now,
+		})
+
+		if outcome := game.Outcome(); outcome.Result != chess.Ongoing {
+			if err := rs.finish(ctx, &relay, game, domain.MatchResult(outcome.Result), domain.RelayTermination(outcome.Termination)); err != nil {
+				return err
+			}
+		}
+
+		relay, err = repo.UpdateRelay(relay)
+		recorded = true
+		return err
+	})
+	if err != nil {
+		return domain.Relay{}, err
+	}
+
+	if recorded {
+		last, _ := relay.LastMove()
+		rs.publish(ctx, relay, domain.LiveRelayMove, &last)
+		if relay.Status == domain.RelayFinished {
+			rs.publish(ctx, relay, domain.LiveRelayFinished, nil)
+		}
+	}
+
+	return relay, nil
+}
+
+// FinishRelay ends the game with the result the arbiter reports
+func (rs *RelayServicer) FinishRelay(ctx context.Context, cmd commands.FinishRelayCommand) (domain.Relay, error) {
+	var relay domain.Relay
+	err := rs.relays.WriteTx(func(repo ports.RelayRepository) error {
+		var err error
+		relay, err = repo.FindRelay(cmd.MatchID)
+		if err != nil {
+			return err
+		}
+		if relay.Status == domain.RelayFinished {
+			return domain.ErrRelayFinished
+		}
+
+		game, err := replay(relay)
+		if err != nil {
+			return err
+		}
+		if err := rs.finish(ctx, &relay, game, cmd.Result, cmd.Termination); err != nil {
+			return err
+		}
+
+		relay, err = repo.UpdateRelay(relay)
+		return err
+	})
+	if err != nil {
+		return domain.Relay{}, err
+	}
+
+	rs.publish(ctx, relay, domain.LiveRelayFinished, nil)
+
+	return relay, nil
+}
+
+// finish writes the pgn of the game to the match and records its result, the relay is only
+// finished when both went through so a failure can be retried. A match that already has the
+// same result, e.g. reported by the arbiter before the last move was relayed, still gets the pgn
+func (rs *RelayServicer) finish(ctx context.Context, relay *domain.Relay, game *chess.Game, result domain.MatchResult, termination domain.RelayTermination) error {
+	var match domain.Match
+	err := rs.matches.ReadTx(func(repo ports.MatchRepository) error {
+		var err error
+		match, err = repo.FindMatch(relay.MatchID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// the tags are looked up before taking the match lock
+	pgn := rs.pgn(match, *relay, game, result)
+	err = rs.matches.WriteTx(func(repo ports.MatchRepository) error {
+		match, err := repo.FindMatch(relay.MatchID)
+		if err != nil {
+			return err
+		}
+		if match.Result.Finished() && match.Result != result {
+			return domain.ErrMatchFinished
+		}
+
+		match.PGN = pgn
+		_, err = repo.UpdateMatch(match)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := rs.results.RecordResult(ctx, matchcommands.RecordResultCommand{ID: relay.MatchID, Result: result}); err != nil {
+		return err
+	}
+
+	relay.Status = domain.RelayFinished
+	relay.Result = result
+	relay.Termination = termination
+	relay.FinishedAt = rs.now()
+
+	return nil
+}
+
+func (rs *RelayServicer) pgn(match domain.Match, relay domain.Relay, game *chess.Game, result domain.MatchResult) string {
+	event := "Casual game"
+	if match.TournamentID != uuid.Nil {
+		if err := rs.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+			tournament, err := repo.FindTournament(match.TournamentID)
+			event = tournament.Name
+			return err
+		}); err != nil {
+			event = "?"
+		}
+	}
+
+	site := "?"
+	if location := strings.TrimSpace(strings.Join(nonEmpty(match.Location.Name, match.Location.City), ", ")); location != "" {
+		site = location
+	}
+
+	white, black := "?", "?"
+	_ = rs.players.ReadTx(func(repo ports.PlayerRepository) error {
+		if p, err := repo.FindPlayer(match.WhitePlayer); err == nil {
+			white = pgnName(p)
+		}
+		if p, err := repo.FindPlayer(match.BlackPlayer); err == nil {
+			black = pgnName(p)
+		}
+		return nil
+	})
+
+	pgn := chess.PGN{
+		Tags: [][2]string{
+			{"Event", event},
+			{"Site", site},
+			{"Date", relay.StartedAt.Format("2006.01.02")},
+			{"Round", "-"},
+			{"White", white},
+			{"Black", black},
+			{"Result", string(result)},
+		},
+		Moves:  game.SANs(),
+		Result: chess.Result(result),
+		FEN:    relay.InitialFEN,
+	}
+	if !relay.TimeControl.IsZero() {
+		pgn.Tags = append(pgn.Tags, [2]string{"TimeControl", relay.TimeControl.PGN()})
+		// the clock after each move is the one of the player who made it
+		white := game.Initial().Turn == chess.White
+		for _, m := range relay.Moves {
+			if white {
+				pgn.Clocks = append(pgn.Clocks, m.WhiteClock)
+			} else {
+				pgn.Clocks = append(pgn.Clocks, m.BlackClock)
+			}
+			white = !white
+		}
+	}
+
+	return pgn.String()
+}
+
+// relayUpdate is what the spectators receive about a relayed game, clocks in milliseconds
+type relayUpdate struct {
+	MatchID     uuid.UUID               `json:"match_id"`
+	Status      domain.RelayStatus      `json:"status"`
+	FEN         string                  `json:"fen"`
+	Ply         int                     `json:"ply"`
+	SAN         string                  `json:"san,omitempty"`
+	UCI         string                  `json:"uci,omitempty"`
+	WhiteClock  int64                   `json:"white_clock_ms"`
+	BlackClock  int64                   `json:"black_clock_ms"`
+	Result      domain.MatchResult      `json:"result"`
+	Termination domain.RelayTermination `json:"termination,omitempty"`
+}
+
+// publish sends the update to the live feed of the tournament when it is open to spectators
+func (rs *RelayServicer) publish(ctx context.Context, relay domain.Relay, updateType string, move *domain.RelayMove) {
+	if relay.TournamentID == uuid.Nil || rs.broadcaster == nil {
+		return
+	}
+
+	var open bool
+	_ = rs.tournaments.ReadTx(func(repo ports.TournamentRepository) error {
+		tournament, err := repo.FindTournament(relay.TournamentID)
+		open = err == nil && tournament.OpenToSpectators
+		return err
+	})
+	if !open {
+		return
+	}
+
+	update := relayUpdate{
+		MatchID:     relay.MatchID,
+		Status:      relay.Status,
+		FEN:         relay.FEN,
+		Ply:         len(relay.Moves),
+		WhiteClock:  relay.WhiteClock.Milliseconds(),
+		BlackClock:  relay.BlackClock.Milliseconds(),
+		Result:      relay.Result,
+		Termination: relay.Termination,
+	}
+	if move != nil {
+		update.Ply, update.SAN, update.UCI = move.Ply, move.SAN, move.UCI
+	}
+
+	err := rs.broadcaster.Publish(domain.LiveUpdate{
+		TournamentID: relay.TournamentID,
+		Type:         updateType,
+		Data:         update,
+		At:           relay.UpdatedAt,
+	})
+	if err != nil {
+		rs.logger.Warn(ctx, "relay update not published", ports.String("match", relay.MatchID.String()), ports.Error("error", err))
+	}
+}
+
+// replay rebuilds the game from the moves relayed so far
+func replay(relay domain.Relay) (*chess.Game, error) {
+	initial, err := chess.ParseFEN(relay.InitialFEN)
+	if err != nil {
+		return nil, err
+	}
+
+	game := chess.NewGame(initial)
+	for _, m := range relay.Moves {
+		if _, _, err := game.Play(m.UCI); err != nil {
+			return nil, fmt.Errorf("replaying move %d of the relay: %w", m.Ply, err)
+		}
+	}
+	return game, nil
+}
+
+// sameMove reports whether the text is the relayed move, in either notation
+func sameMove(m domain.RelayMove, text string) bool {
+	text = strings.TrimRight(strings.TrimSpace(text), "+#!?")
+	return text == m.UCI || text == strings.TrimRight(m.SAN, "+#")
+}
+
+// pgnName is the name of the player as written in pgn, "Last, First"
+func pgnName(p domain.Player) string {
+	switch {
+	case p.LastName != "" && p.FirstName != "":
+		return p.LastName + ", " + p.FirstName
+	case p.LastName != "" || p.FirstName != "":
+		return p.LastName + p.FirstName
+	case p.Username != "":
+		return p.Username
+	}
+	return "?"
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	matchcommands "github.com/ctfrancia/maple/internal/application/commands/match"
+	commands "github.com/ctfrancia/maple/internal/application/commands/relay"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+type relayFixture struct {
+	rs          *RelayServicer
+	ms          ports.MatchServicer
+	broadcaster *recordingBroadcaster
+	now         *time.Time
+	match       domain.Match
+}
+
+// newRelayFixture has a blitz match of a tournament open to spectators
+func newRelayFixture(t *testing.T) *relayFixture {
+	t.Helper()
+
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	tournaments := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+
+	ps, err := NewPlayerServicer(lggr, players, matches)
+	if err != nil {
+		t.Fatalf("error creating player service: %v", err)
+	}
+	ms, err := NewMatchServicer(lggr, matches, players, tournaments, nil)
+	if err != nil {
+		t.Fatalf("error creating match service: %v", err)
+	}
+
+	var tournament domain.Tournament
+	err = tournaments.WriteTx(func(repo ports.TournamentRepository) error {
+		tournament, err = repo.CreateTournament(domain.Tournament{Name: "Open de Sants", OpenToSpectators: true})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	white := createTestPlayer(t, ps, "white")
+	black := createTestPlayer(t, ps, "black")
+	match, err := ms.CreateMatch(context.Background(), matchcommands.CreateMatchCommand{
+		TournamentID: tournament.PublicID,
+		WhitePlayer:  white.PublicID,
+		BlackPlayer:  black.PublicID,
+		TimeControl:  "3+2",
+	})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+
+	f := &relayFixture{ms: ms, broadcaster: &recordingBroadcaster{}, match: match}
+	service, err := NewRelayServicer(lggr, inmemory.NewRelayRepositoryProvider(inmemory.NewInMemoryRelayRepository()), matches, players, tournaments, ms, f.broadcaster)
+	if err != nil {
+		t.Fatalf("error creating relay service: %v", err)
+	}
+	f.rs = service.(*RelayServicer)
+
+	now := time.Date(2026, 5, 2, 17, 0, 0, 0, time.UTC)
+	f.now = &now
+	f.rs.now = func() time.Time { return *f.now }
+
+	return f
+}
+
+func (f *relayFixture) move(t *testing.T, move string, after time.Duration) domain.Relay {
+	t.Helper()
+	*f.now = f.now.Add(after)
+	relay, err := f.rs.RecordMove(context.Background(), commands.RecordMoveCommand{MatchID: f.match.UUID, Move: move})
+	if err != nil {
+		t.Fatalf("error recording %s: %v", move, err)
+	}
+	return relay
+}
+
+func TestRelayServicer_Checkmate(t *testing.T) {
+	f := newRelayFixture(t)
+	ctx := context.Background()
+
+	relay, err := f.rs.StartRelay(ctx, commands.StartRelayCommand{MatchID: f.match.UUID})
+	if err != nil {
+		t.Fatalf("error starting relay: %v", err)
+	}
+	if relay.WhiteClock != 3*time.Minute || relay.Status != domain.RelayLive {
+		t.Errorf("unexpected relay %+v", relay)
+	}
+	if _, err := f.rs.StartRelay(ctx, commands.StartRelayCommand{MatchID: f.match.UUID}); !errors.Is(err, domain.ErrRelayAlreadyStarted) {
+		t.Errorf("expected ErrRelayAlreadyStarted, got %v", err)
+	}
+
+	relay = f.move(t, "f3", 10*time.Second)
+	if relay.WhiteClock != 3*time.Minute-8*time.Second {
+		t.Errorf("expected the increment on white's clock, got %s", relay.WhiteClock)
+	}
+
+	if _, err := f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "e4"}); !errors.Is(err, domain.ErrIllegalMove) {
+		t.Errorf("expected ErrIllegalMove for a white move on black's turn, got %v", err)
+	}
+
+	// the board reports what its clock shows
+	clock := 2*time.Minute + 55*time.Second
+	relay, err = f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "e7e5", Ply: 2, Clock: &clock})
+	if err != nil {
+		t.Fatalf("error recording e5: %v", err)
+	}
+	if relay.BlackClock != clock || relay.Moves[1].SAN != "e5" {
+		t.Errorf("unexpected relay %+v", relay)
+	}
+
+	// sent again by the board, nothing changes
+	again, err := f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "e5", Ply: 2})
+	if err != nil || len(again.Moves) != 2 {
+		t.Errorf("expected the repeated move to be ignored, got %d moves, %v", len(again.Moves), err)
+	}
+	if _, err := f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "Nc3", Ply: 4}); !errors.Is(err, domain.ErrRelayOutOfSync) {
+		t.Errorf("expected ErrRelayOutOfSync for a skipped move, got %v", err)
+	}
+
+	f.move(t, "g4", 5*time.Second)
+	relay = f.move(t, "Qh4", 3*time.Second)
+
+	if relay.Status != domain.RelayFinished || relay.Result != domain.MatchResultBlackWins || relay.Termination != domain.RelayCheckmate {
+		t.Fatalf("expected the relay to end on the mate, got %+v", relay)
+	}
+	if _, err := f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "a3"}); !errors.Is(err, domain.ErrRelayFinished) {
+		t.Errorf("expected ErrRelayFinished, got %v", err)
+	}
+
+	match, err := f.ms.FindMatch(ctx, matchcommands.FindMatchCommand{ID: f.match.UUID})
+	if err != nil {
+		t.Fatalf("error finding match: %v", err)
+	}
+	if match.Result != domain.MatchResultBlackWins {
+		t.Errorf("expected the result to be recorded, got %s", match.Result)
+	}
+	for _, want := range []string{
+		`[Event "Open de Sants"]`,
+		`[White "Player, Test"]`,
+		`[Result "0-1"]`,
+		`[TimeControl "180+2"]`,
+		"1. f3 {[%clk 0:02:52]} e5 {[%clk 0:02:55]} 2. g4 {[%clk 0:02:49]} Qh4# {[%clk 0:02:54]} 0-1",
+	} {
+		// the movetext wraps, compared on a single line
+		if !strings.Contains(strings.Join(strings.Fields(match.PGN), " "), want) {
+			t.Errorf("expected the pgn to contain %q, got\n%s", want, match.PGN)
+		}
+	}
+
+	// started, 4 moves and the end
+	var types []string
+	for _, update := range f.broadcaster.updates {
+		types = append(types, update.Type)
+	}
+	if got := strings.Join(types, " "); got != "relay.started relay.move relay.move relay.move relay.move relay.finished" {
+		t.Errorf("unexpected live updates %s", got)
+	}
+}
+
+func TestRelayServicer_FinishRelay(t *testing.T) {
+	f := newRelayFixture(t)
+	ctx := context.Background()
+
+	_, err := f.rs.StartRelay(ctx, commands.StartRelayCommand{MatchID: f.match.UUID, FEN: "4k3/8/8/8/8/8/4P3/4K3 b - - 0 60"})
+	if err != nil {
+		t.Fatalf("error starting relay: %v", err)
+	}
+	f.move(t, "Kd7", time.Second)
+
+	cmd := commands.FinishRelayCommand{MatchID: f.match.UUID, Result: domain.MatchResultDraw, Termination: domain.RelayAgreement}
+	relay, err := f.rs.FinishRelay(ctx, cmd)
+	if err != nil {
+		t.Fatalf("error finishing relay: %v", err)
+	}
+	if relay.Status != domain.RelayFinished || relay.Termination != domain.RelayAgreement || relay.FinishedAt.IsZero() {
+		t.Errorf("unexpected relay %+v", relay)
+	}
+	if _, err := f.rs.FinishRelay(ctx, cmd); !errors.Is(err, domain.ErrRelayFinished) {
+		t.Errorf("expected ErrRelayFinished, got %v", err)
+	}
+
+	match, err := f.ms.FindMatch(ctx, matchcommands.FindMatchCommand{ID: f.match.UUID})
+	if err != nil {
+		t.Fatalf("error finding match: %v", err)
+	}
+	if !strings.Contains(match.PGN, `[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 60"]`) || !strings.Contains(match.PGN, "60... Kd7") {
+		t.Errorf("expected the pgn to start from the position, got\n%s", match.PGN)
+	}
+
+	// the match has its result, it cannot be relayed again
+	if _, err := f.rs.StartRelay(ctx, commands.StartRelayCommand{MatchID: f.match.UUID}); !errors.Is(err, domain.ErrMatchFinished) {
+		t.Errorf("expected ErrMatchFinished, got %v", err)
+	}
+}
+
+func TestRelayServicer_ResultRecordedBeforeTheMate(t *testing.T) {
+	ctx := context.Background()
+
+	mate := func(t *testing.T, f *relayFixture, reported domain.MatchResult) (domain.Relay, error) {
+		t.Helper()
+		if _, err := f.rs.StartRelay(ctx, commands.StartRelayCommand{MatchID: f.match.UUID}); err != nil {
+			t.Fatalf("error starting relay: %v", err)
+		}
+		f.move(t, "f3", time.Second)
+		f.move(t, "e5", time.Second)
+		f.move(t, "g4", time.Second)
+
+		// the arbiter reports the result before the board sends the last move
+		if _, err := f.ms.RecordResult(ctx, matchcommands.RecordResultCommand{ID: f.match.UUID, Result: reported}); err != nil {
+			t.Fatalf("error recording result: %v", err)
+		}
+		return f.rs.RecordMove(ctx, commands.RecordMoveCommand{MatchID: f.match.UUID, Move: "Qh4"})
+	}
+
+	t.Run("same result", func(t *testing.T) {
+		f := newRelayFixture(t)
+		relay, err := mate(t, f, domain.MatchResultBlackWins)
+		if err != nil {
+			t.Fatalf("error recording the mate: %v", err)
+		}
+		if relay.Status != domain.RelayFinished || len(relay.Moves) != 4 {
+			t.Errorf("expected the relay to end on the mate, got %+v", relay)
+		}
+
+		match, err := f.ms.FindMatch(ctx, matchcommands.FindMatchCommand{ID: f.match.UUID})
+		if err != nil {
+			t.Fatalf("error finding match: %v", err)
+		}
+		if !strings.Contains(match.PGN, "Qh4#") {
+			t.Errorf("expected the pgn to be written, got\n%s", match.PGN)
+		}
+	})
+
+	t.Run("different result", func(t *testing.T) {
+		f := newRelayFixture(t)
+		if _, err := mate(t, f, domain.MatchResultDraw); !errors.Is(err, domain.ErrMatchFinished) {
+			t.Fatalf("expected ErrMatchFinished, got %v", err)
+		}
+
+		relay, err := f.rs.FindRelay(ctx, commands.FindRelayCommand{MatchID: f.match.UUID})
+		if err != nil {
+			t.Fatalf("error finding relay: %v", err)
+		}
+		if relay.Status != domain.RelayLive || len(relay.Moves) != 3 {
+			t.Errorf("expected the mate not to be recorded, got %+v", relay)
+		}
+	})
+}
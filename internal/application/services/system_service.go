@@ -2,6 +2,8 @@
 package services
 
 import (
+	"context"
+
 	"github.com/ctfrancia/maple/internal/core/domain"
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
@@ -10,13 +12,16 @@ type SystemHealthServicer struct {
 	sAdapter ports.SystemAdapter
 	repo     ports.SystemRepository
 	security ports.SecurityAdapter
+	health   ports.HealthChecker
 }
 
-func NewSystemHealthServicer(sa ports.SystemAdapter, sr ports.SystemRepository, sec ports.SecurityAdapter) ports.SystemServicer {
+// NewSystemHealthServicer - a nil health reports the process up without checking anything
+func NewSystemHealthServicer(sa ports.SystemAdapter, sr ports.SystemRepository, sec ports.SecurityAdapter, health ports.HealthChecker) ports.SystemServicer {
 	return &SystemHealthServicer{
 		sAdapter: sa,
 		repo:     sr,
 		security: sec,
+		health:   health,
 	}
 }
 
@@ -24,6 +29,20 @@ func (shs *SystemHealthServicer) ProcessSystemHealthRequest() domain.System {
 	return shs.sAdapter.GetSystemInfo()
 }
 
+func (shs *SystemHealthServicer) Liveness(ctx context.Context) domain.HealthReport {
+	if shs.health == nil {
+		return domain.HealthReport{Status: domain.HealthUp, System: shs.sAdapter.GetSystemInfo()}
+	}
+	return shs.health.Liveness(ctx)
+}
+
+func (shs *SystemHealthServicer) Readiness(ctx context.Context) domain.HealthReport {
+	if shs.health == nil {
+		return domain.HealthReport{Status: domain.HealthUp, System: shs.sAdapter.GetSystemInfo()}
+	}
+	return shs.health.Readiness(ctx)
+}
+
 func (shs *SystemHealthServicer) Login(username, password string) (any, error) {
 	return nil, nil
 }
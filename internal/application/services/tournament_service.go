@@ -13,88 +13,133 @@ import (
 type TournamentServicer struct {
 	logger     ports.Logger
 	repository ports.TournamentRepositoryProvider
-	workerPool *TournamentWorkerPool // make this port
+	matches    ports.MatchRepositoryProvider
+	players    ports.PlayerRepositoryProvider
+	locations  ports.LocationRepositoryProvider
+	tasks      tournamentTasks
+	policy     ports.ListingPolicy // nil when every listing is public right away
 }
 
-func NewTournamentServicer(log ports.Logger, tr ports.TournamentRepositoryProvider, wp *TournamentWorkerPool) (ports.TournamentServicer, error) {
-	return &TournamentServicer{
+func NewTournamentServicer(log ports.Logger, tr ports.TournamentRepositoryProvider, mr ports.MatchRepositoryProvider, pr ports.PlayerRepositoryProvider, lr ports.LocationRepositoryProvider, wp *WorkerPool, policy ports.ListingPolicy) (ports.TournamentServicer, error) {
+	ts := &TournamentServicer{
 		logger:     log,
 		repository: tr,
-		workerPool: wp,
-	}, nil
-}
-
-func (ts *TournamentServicer) CreateTournament(ctx context.Context, tournament commands.CreateTournamentCommand) (domain.Tournament, error) {
-	task := TournamentTask{
-		ID:         uuid.New(),
-		Type:       TaskTypeCreateTournament,
-		Data:       CreateTournamentTask{Tournament: tournament},
-		Repository: ts.repository,
-		ResultCh:   make(chan TaskResult, 1),
-		Context:    ctx,
+		matches:    mr,
+		players:    pr,
+		locations:  lr,
+		policy:     policy,
+	}
+	if err := ts.registerTasks(wp); err != nil {
+		return nil, err
 	}
 
-	resultCh := ts.workerPool.SubmitTask(task)
+	return ts, nil
+}
 
-	select {
-	case result := <-resultCh:
-		if result.Error != nil {
-			// error handling here
-			return domain.Tournament{}, result.Error
+func (ts *TournamentServicer) CreateTournament(ctx context.Context, tournament commands.CreateTournamentCommand) (result domain.Tournament, err error) {
+	if tournament.ConsumerID != "" {
+		ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyConsumerID, tournament.ConsumerID))
+	}
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.CreateTournament", ports.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+
+	moderation := domain.ModerationApproved
+	if ts.policy != nil {
+		moderation, err = ts.policy.Admit(ctx, tournament.ConsumerID)
+		if err != nil {
+			return domain.Tournament{}, err
 		}
-		return result.Data.(domain.Tournament), nil
+	}
 
-	case <-ctx.Done():
-		return domain.Tournament{}, ctx.Err()
+	var location domain.Location
+	if tournament.LocationID != "" {
+		// validated by the command
+		locationID, _ := uuid.Parse(tournament.LocationID)
+		location, err = findLocation(ts.locations, locationID)
+		if err != nil {
+			return domain.Tournament{}, err
+		}
 	}
+
+	task := CreateTournamentTask{Tournament: tournament, Moderation: moderation, Location: location}
+
+	return ts.tasks.create.Submit(ctx, task).Await(ctx)
 }
 
-func (ts *TournamentServicer) ListTournaments(ctx context.Context) ([]domain.Tournament, error) {
-	task := TournamentTask{
-		ID:         uuid.New(),
-		Type:       TaskTypeListTournaments,
-		Data:       ListTournamentsTask{},
-		Repository: ts.repository,
-		ResultCh:   make(chan TaskResult, 1),
-		Context:    ctx,
+func (ts *TournamentServicer) ListTournaments(ctx context.Context, cmd commands.ListTournamentsCommand) (result []domain.Tournament, err error) {
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.ListTournaments", ports.SpanKindInternal)
+	defer func() { endSpan(span, err) }()
+
+	filter := domain.TournamentFilter{Category: cmd.Category, Moderation: domain.ModerationApproved}
+	if cmd.TimeControl != "" {
+		tc, err := domain.ParseTimeControl(cmd.TimeControl)
+		if err != nil {
+			return nil, err
+		}
+		filter.TimeControl = tc
 	}
 
-	resultCh := ts.workerPool.SubmitTask(task)
+	return ts.tasks.list.Submit(ctx, ListTournamentsTask{Filter: filter}).Await(ctx)
+}
 
-	select {
-	case result := <-resultCh:
-		if result.Error != nil {
-			// error handling here
-			return nil, result.Error
-		}
-		return result.Data.([]domain.Tournament), nil
+func (ts *TournamentServicer) FindTournament(ctx context.Context, cmd commands.FindTournamentCommand) (result domain.Tournament, err error) {
+	ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyTournamentID, cmd.ID.String()))
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.FindTournament", ports.SpanKindInternal, ports.String("tournament_id", cmd.ID.String()))
+	defer func() { endSpan(span, err) }()
+
+	return ts.tasks.find.Submit(ctx, FindTournamentTask{TournamentID: cmd.ID}).Await(ctx)
+}
 
-	case <-ctx.Done():
-		return nil, ctx.Err()
+// TransitionTournament takes the action on the tournament, the guards of the action are
+// checked against the registered players and the matches of the tournament
+func (ts *TournamentServicer) TransitionTournament(ctx context.Context, cmd commands.TransitionTournamentCommand) (result domain.Tournament, err error) {
+	ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyTournamentID, cmd.ID.String()))
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.TransitionTournament", ports.SpanKindInternal,
+		ports.String("tournament_id", cmd.ID.String()),
+		ports.String("action", string(cmd.Action)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	tournament, err := ts.tasks.transition.Submit(ctx, TransitionTournamentTask{Command: cmd}).Await(ctx)
+	if err != nil {
+		return domain.Tournament{}, err
 	}
+
+	ts.logger.Info(ctx, "tournament status changed",
+		ports.String("action", string(cmd.Action)),
+		ports.String("status", string(tournament.Status)),
+		ports.String("actor", string(cmd.Actor.Role)+":"+cmd.Actor.ID),
+	)
+	return tournament, nil
 }
 
-func (ts *TournamentServicer) FindTournament(ctx context.Context, cmd commands.FindTournamentCommand) (domain.Tournament, error) {
-	task := TournamentTask{
-		ID:         uuid.New(),
-		Type:       TaskTypeFindTournament,
-		Data:       FindTournamentTask{TournamentID: cmd.ID},
-		Repository: ts.repository,
-		ResultCh:   make(chan TaskResult, 1),
-		Context:    ctx,
+// RegisterPlayer registers the player for the tournament, players can register until it starts
+func (ts *TournamentServicer) RegisterPlayer(ctx context.Context, cmd commands.RegisterPlayerCommand) (result domain.Tournament, err error) {
+	ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyTournamentID, cmd.TournamentID.String()))
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.RegisterPlayer", ports.SpanKindInternal,
+		ports.String("tournament_id", cmd.TournamentID.String()),
+		ports.String("player_id", cmd.PlayerID.String()),
+	)
+	defer func() { endSpan(span, err) }()
+
+	err = ts.players.ReadTx(func(repo ports.PlayerRepository) error {
+		_, err := repo.FindPlayer(cmd.PlayerID)
+		return err
+	})
+	if err != nil {
+		return domain.Tournament{}, err
 	}
 
-	resultCh := ts.workerPool.SubmitTask(task)
+	return ts.tasks.register.Submit(ctx, RegisterPlayerTask{Command: cmd}).Await(ctx)
+}
 
-	select {
-	case result := <-resultCh:
-		if result.Error != nil {
-			// error handling here
-			return domain.Tournament{}, result.Error
-		}
-		return result.Data.(domain.Tournament), nil
+// PairRound creates the matches of the next round of an active tournament
+func (ts *TournamentServicer) PairRound(ctx context.Context, cmd commands.PairRoundCommand) (result domain.PairedRound, err error) {
+	ctx = ports.WithLogFields(ctx, ports.String(ports.LogKeyTournamentID, cmd.TournamentID.String()))
+	ctx, span := ports.StartSpan(ctx, "TournamentServicer.PairRound", ports.SpanKindInternal,
+		ports.String("tournament_id", cmd.TournamentID.String()),
+	)
+	defer func() { endSpan(span, err) }()
 
-	case <-ctx.Done():
-		return domain.Tournament{}, ctx.Err()
-	}
+	return ts.tasks.pair.Submit(ctx, PairRoundTask{Command: cmd}).Await(ctx)
 }
@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/ctfrancia/maple/internal/adapters/logger"
 	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
 	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
 )
 
 // TODO: Create table for testing
@@ -17,34 +21,34 @@ var lggr = logger.NewZapLogger("test")
 func TestCreateTournament_ShouldCreateService(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	wp := NewTournamentWorkerPool(ctx, cancel)
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
 	wp.Start()
 	defer wp.Stop()
 
-	repo := inmemory.NewInMemoryTournamentRepository()
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
 
-	_, err := NewTournamentServicer(lggr, repo, wp)
+	_, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
 	if err != nil {
 		t.Errorf("error creating service: %v", err)
 	}
 }
 
 func TestCreateTournament(t *testing.T) {
-	repo := inmemory.NewInMemoryTournamentRepository()
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
 	// ctx, cancel := context.WithCancel(context.Background())
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	wp := NewTournamentWorkerPool(ctx, cancel)
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
 	wp.Start()
 	defer wp.Stop()
 
-	ts, err := NewTournamentServicer(lggr, repo, wp)
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
 	if err != nil {
 		t.Errorf("error creating service: %v", err)
 	}
 
-	result, err := ts.CreateTournament(ctx, domain.Tournament{Name: "Test Tournament"})
+	result, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Test Tournament", TimeControl: "90+30"})
 	if err != nil {
 		t.Errorf("error creating tournament: %v", err)
 	}
@@ -55,26 +59,26 @@ func TestCreateTournament(t *testing.T) {
 }
 
 func TestListTournaments(t *testing.T) {
-	repo := inmemory.NewInMemoryTournamentRepository()
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
 	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Second)
 	defer cancel()
 
-	wp := NewTournamentWorkerPool(ctx, cancel)
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
 	wp.Start()
 	defer wp.Stop()
 
-	ts, err := NewTournamentServicer(lggr, repo, wp)
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
 	if err != nil {
 		t.Errorf("error creating service: %v", err)
 	}
 
 	// Create a tournament
-	_, err = ts.CreateTournament(ctx, domain.Tournament{Name: "Test Tournament"})
+	_, err = ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Test Tournament", TimeControl: "90+30"})
 	if err != nil {
 		t.Errorf("error creating tournament: %v", err)
 	}
 
-	result, err := ts.ListTournaments(ctx)
+	result, err := ts.ListTournaments(ctx, commands.ListTournamentsCommand{})
 	if err != nil {
 		t.Errorf("error listing tournaments: %v", err)
 	}
@@ -85,27 +89,27 @@ func TestListTournaments(t *testing.T) {
 }
 
 func TestFindTournament(t *testing.T) {
-	repo := inmemory.NewInMemoryTournamentRepository()
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
 	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Second)
 	defer cancel()
 
-	wp := NewTournamentWorkerPool(ctx, cancel)
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
 	wp.Start()
 	defer wp.Stop()
 
-	ts, err := NewTournamentServicer(lggr, repo, wp)
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
 	if err != nil {
 		t.Errorf("error creating service: %v", err)
 	}
 
 	// Create a tournament
-	data := domain.Tournament{Name: "Test Tournament"}
-	tournament, err := ts.CreateTournament(ctx, domain.Tournament{Name: "Test Tournament"})
+	data := commands.CreateTournamentCommand{Name: "Test Tournament", TimeControl: "90+30"}
+	tournament, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Test Tournament", TimeControl: "90+30"})
 	if err != nil {
 		t.Errorf("error creating tournament: %v", err)
 	}
 
-	result, err := ts.FindTournament(ctx, tournament.PublicID)
+	result, err := ts.FindTournament(ctx, commands.FindTournamentCommand{ID: tournament.PublicID})
 	if err != nil {
 		t.Errorf("error listing tournaments: %v", err)
 	}
@@ -114,3 +118,256 @@ func TestFindTournament(t *testing.T) {
 		t.Errorf("tournament name is not correct: expected %s, got %s", data.Name, result.Name)
 	}
 }
+
+func TestListTournaments_FilterByTimeControl(t *testing.T) {
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Second)
+	defer cancel()
+
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+
+	for name, tc := range map[string]string{
+		"Classical Open": "90/40+30, 30+30",
+		"Rapid Open":     "15+10",
+		"Blitz Night":    "3+2",
+		"Blitz Sunday":   "5|3",
+	} {
+		if _, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: name, TimeControl: tc}); err != nil {
+			t.Fatalf("error creating tournament %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cmd      commands.ListTournamentsCommand
+		expected int
+	}{
+		{"no filter", commands.ListTournamentsCommand{}, 4},
+		{"blitz", commands.ListTournamentsCommand{Category: domain.TimeControlCategoryBlitz}, 2},
+		{"classical", commands.ListTournamentsCommand{Category: domain.TimeControlCategoryClassical}, 1},
+		{"bullet", commands.ListTournamentsCommand{Category: domain.TimeControlCategoryBullet}, 0},
+		{"exact time control", commands.ListTournamentsCommand{TimeControl: "5+3"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ts.ListTournaments(ctx, tt.cmd)
+			if err != nil {
+				t.Fatalf("error listing tournaments: %v", err)
+			}
+			if len(result) != tt.expected {
+				t.Errorf("expected %d tournaments, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestTransitionTournament(t *testing.T) {
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Second)
+	defer cancel()
+
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	ts, err := NewTournamentServicer(lggr, repo, matches, nil, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+
+	tournament, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Club Championship", TimeControl: "90+30", ConsumerID: "acme"})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+	if tournament.Status != domain.TournamentStatusDraft {
+		t.Fatalf("expected a new tournament to be a draft, got %s", tournament.Status)
+	}
+
+	other := domain.Principal{Role: domain.RoleConsumer, ID: "club-app"}
+	_, err = ts.TransitionTournament(ctx, commands.TransitionTournamentCommand{ID: tournament.PublicID, Action: domain.TournamentActionSubmit, Actor: other})
+	if !errors.Is(err, domain.ErrTournamentNotOwner) {
+		t.Fatalf("expected another consumer not to change the tournament, got %v", err)
+	}
+
+	transition := func(action domain.TournamentAction) (domain.Tournament, error) {
+		return ts.TransitionTournament(ctx, commands.TransitionTournamentCommand{ID: tournament.PublicID, Action: action, Actor: domain.Principal{Role: domain.RoleAdmin, ID: "arbiter"}})
+	}
+
+	if _, err := transition(domain.TournamentActionStart); !errors.Is(err, domain.ErrTournamentTransitionNotAllowed) {
+		t.Fatalf("expected a draft not to start, got %v", err)
+	}
+	if _, err := transition(domain.TournamentActionSubmit); err != nil {
+		t.Fatalf("error submitting tournament: %v", err)
+	}
+	if _, err := transition(domain.TournamentActionStart); !errors.Is(err, domain.ErrTournamentNotEnoughPlayers) {
+		t.Fatalf("expected the tournament not to start without players, got %v", err)
+	}
+
+	white, black := uuid.New(), uuid.New()
+	err = repo.WriteTx(func(r ports.TournamentRepository) error {
+		found, err := r.FindTournament(tournament.PublicID)
+		if err != nil {
+			return err
+		}
+		found.Players = []uuid.UUID{white, black}
+		_, err = r.UpdateTournament(found)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error registering players: %v", err)
+	}
+
+	if _, err := transition(domain.TournamentActionStart); err != nil {
+		t.Fatalf("error starting tournament: %v", err)
+	}
+
+	var match domain.Match
+	err = matches.WriteTx(func(r ports.MatchRepository) error {
+		match, err = r.CreateMatch(domain.Match{TournamentID: tournament.PublicID, WhitePlayer: white, BlackPlayer: black, Result: domain.MatchResultOngoing})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating match: %v", err)
+	}
+
+	if _, err := transition(domain.TournamentActionComplete); !errors.Is(err, domain.ErrTournamentUnreportedResults) {
+		t.Fatalf("expected the tournament not to complete with an ongoing match, got %v", err)
+	}
+
+	err = matches.WriteTx(func(r ports.MatchRepository) error {
+		match.Result = domain.MatchResultDraw
+		_, err := r.UpdateMatch(match)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error recording result: %v", err)
+	}
+
+	result, err := transition(domain.TournamentActionComplete)
+	if err != nil {
+		t.Fatalf("error completing tournament: %v", err)
+	}
+	if result.Status != domain.TournamentStatusCompleted {
+		t.Errorf("expected the tournament to be completed, got %s", result.Status)
+	}
+	if len(result.Transitions) != 3 {
+		t.Fatalf("expected 3 transitions to be recorded, got %d", len(result.Transitions))
+	}
+	last := result.Transitions[2]
+	if last.From != domain.TournamentStatusActive || last.To != domain.TournamentStatusCompleted || last.Actor != "arbiter" || last.At.IsZero() {
+		t.Errorf("unexpected transition recorded: %+v", last)
+	}
+}
+
+func TestRegisterPlayerAndPairRound(t *testing.T) {
+	outbox := inmemory.NewOutboxRepositoryProvider(inmemory.NewInMemoryOutboxRepository())
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), outbox)
+	matches := inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil)
+	players := inmemory.NewPlayerRepositoryProvider(inmemory.NewInMemoryPlayerRepository())
+	ctx, cancel := context.WithTimeout(context.TODO(), 1*time.Second)
+	defer cancel()
+
+	wp := NewWorkerPool(ctx, lggr, nil, DefaultWorkerPoolConfig())
+	wp.Start()
+	defer wp.Stop()
+
+	ts, err := NewTournamentServicer(lggr, repo, matches, players, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+
+	tournament, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Club Championship", TimeControl: "90+30"})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	var white, black domain.Player
+	err = players.WriteTx(func(r ports.PlayerRepository) error {
+		if white, err = r.CreatePlayer(domain.Player{FirstName: "Judit"}); err != nil {
+			return err
+		}
+		black, err = r.CreatePlayer(domain.Player{FirstName: "Magnus"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("error creating players: %v", err)
+	}
+
+	register := func(player uuid.UUID) (domain.Tournament, error) {
+		return ts.RegisterPlayer(ctx, commands.RegisterPlayerCommand{TournamentID: tournament.PublicID, PlayerID: player})
+	}
+	if _, err := register(uuid.New()); !errors.Is(err, domain.ErrPlayerNotFound) {
+		t.Fatalf("expected an unknown player not to register, got %v", err)
+	}
+	if _, err := register(white.PublicID); err != nil {
+		t.Fatalf("error registering player: %v", err)
+	}
+	if _, err := register(white.PublicID); !errors.Is(err, domain.ErrPlayerAlreadyRegistered) {
+		t.Fatalf("expected a player not to register twice, got %v", err)
+	}
+	registered, err := register(black.PublicID)
+	if err != nil {
+		t.Fatalf("error registering player: %v", err)
+	}
+	if registered.NumberOfPlayers != 2 {
+		t.Errorf("expected 2 registered players, got %d", registered.NumberOfPlayers)
+	}
+
+	pairings := []commands.Pairing{{White: white.PublicID, Black: black.PublicID}}
+	pair := func() (domain.PairedRound, error) {
+		return ts.PairRound(ctx, commands.PairRoundCommand{TournamentID: tournament.PublicID, Pairings: pairings})
+	}
+	if _, err := pair(); !errors.Is(err, domain.ErrTournamentNotInPlay) {
+		t.Fatalf("expected a draft not to be paired, got %v", err)
+	}
+
+	for _, action := range []domain.TournamentAction{domain.TournamentActionSubmit, domain.TournamentActionStart} {
+		if _, err := ts.TransitionTournament(ctx, commands.TransitionTournamentCommand{ID: tournament.PublicID, Action: action, Actor: domain.Principal{Role: domain.RoleAdmin, ID: "arbiter"}}); err != nil {
+			t.Fatalf("error taking action %s: %v", action, err)
+		}
+	}
+	if _, err := register(white.PublicID); !errors.Is(err, domain.ErrTournamentRegistrationClosed) {
+		t.Fatalf("expected the registration to close when the tournament starts, got %v", err)
+	}
+
+	round, err := pair()
+	if err != nil {
+		t.Fatalf("error pairing round: %v", err)
+	}
+	if round.Round != 1 || len(round.Matches) != 1 {
+		t.Fatalf("expected round 1 with 1 match, got round %d with %d", round.Round, len(round.Matches))
+	}
+	match := round.Matches[0]
+	if match.TournamentID != tournament.PublicID || match.WhitePlayer != white.PublicID || match.BlackPlayer != black.PublicID || match.TimeControl.String() != "90+30" {
+		t.Errorf("unexpected match of the round: %+v", match)
+	}
+	if second, err := pair(); err != nil || second.Round != 2 {
+		t.Fatalf("expected the next pairing to be round 2, got %d: %v", second.Round, err)
+	}
+
+	var events []domain.Event
+	for _, entry := range pendingEntries(t, outbox) {
+		switch entry.Event.(type) {
+		case domain.PlayerRegistered, domain.RoundPaired:
+			events = append(events, entry.Event)
+		}
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 2 registrations and 2 pairings in the outbox, got %+v", events)
+	}
+	if e, ok := events[0].(domain.PlayerRegistered); !ok || e.PlayerID != white.PublicID || e.TournamentID != tournament.PublicID {
+		t.Errorf("unexpected registration event: %+v", events[0])
+	}
+	if e, ok := events[2].(domain.RoundPaired); !ok || e.Round != 1 || len(e.Matches) != 1 || e.Matches[0] != match.UUID {
+		t.Errorf("unexpected pairing event: %+v", events[2])
+	}
+}
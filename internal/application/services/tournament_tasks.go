@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type CreateTournamentTask struct {
+	Tournament commands.CreateTournamentCommand
+	Moderation domain.ModerationStatus // decided by the listing policy before the task is submitted
+	Location   domain.Location         // read from the registry when the command links one
+}
+
+type FindTournamentTask struct {
+	TournamentID uuid.UUID
+}
+
+type ListTournamentsTask struct {
+	Filter domain.TournamentFilter
+}
+
+type TransitionTournamentTask struct {
+	Command commands.TransitionTournamentCommand
+}
+
+type RegisterPlayerTask struct {
+	Command commands.RegisterPlayerCommand
+}
+
+type PairRoundTask struct {
+	Command commands.PairRoundCommand
+}
+
+// tournamentTasks are the tasks of the tournament service registered in the worker pool
+type tournamentTasks struct {
+	create     *Task[CreateTournamentTask, domain.Tournament]
+	find       *Task[FindTournamentTask, domain.Tournament]
+	list       *Task[ListTournamentsTask, []domain.Tournament]
+	transition *Task[TransitionTournamentTask, domain.Tournament]
+	register   *Task[RegisterPlayerTask, domain.Tournament]
+	pair       *Task[PairRoundTask, domain.PairedRound]
+}
+
+// registerTasks registers the handlers of the tournament tasks, the reads go in the high lane
+func (ts *TournamentServicer) registerTasks(wp *WorkerPool) error {
+	var err error
+	if ts.tasks.create, err = Register(wp, ts.createTournament, TaskOptions{}); err != nil {
+		return err
+	}
+	if ts.tasks.find, err = Register(wp, ts.findTournament, TaskOptions{Priority: TaskPriorityHigh}); err != nil {
+		return err
+	}
+	if ts.tasks.list, err = Register(wp, ts.listTournaments, TaskOptions{Priority: TaskPriorityHigh}); err != nil {
+		return err
+	}
+	if ts.tasks.transition, err = Register(wp, ts.transitionTournament, TaskOptions{}); err != nil {
+		return err
+	}
+	if ts.tasks.register, err = Register(wp, ts.registerPlayer, TaskOptions{}); err != nil {
+		return err
+	}
+	if ts.tasks.pair, err = Register(wp, ts.pairRound, TaskOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ts *TournamentServicer) createTournament(ctx context.Context, t CreateTournamentTask) (domain.Tournament, error) {
+	var result domain.Tournament
+	var err error
+
+	tournament := domain.NewTournament(t.Tournament.Name, t.Tournament.Description)
+	if len(t.Tournament.Descriptions) > 0 {
+		tournament.Descriptions = make(domain.LocalizedText, len(t.Tournament.Descriptions))
+		for locale, description := range t.Tournament.Descriptions {
+			tournament.Descriptions[i18n.Locale(locale)] = description
+		}
+	}
+	tournament.TimeControl, err = domain.ParseTimeControl(t.Tournament.TimeControl)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	tournament.ConsumerID = t.Tournament.ConsumerID
+	tournament.Moderation = t.Moderation
+	tournament.Location = t.Location
+
+	span := txSpan(ctx, "tournaments", "write")
+	err = ts.repository.WriteTx(func(repo ports.TournamentRepository) error {
+		// the lock may have been a long wait
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err = repo.CreateTournament(*tournament)
+		if err != nil {
+			return err
+		}
+
+		return repo.RecordEvents(domain.TournamentCreated{
+			TournamentID: result.PublicID,
+			Name:         result.Name,
+			TimeControl:  result.TimeControl.String(),
+			At:           result.CreatedAt,
+		})
+	})
+	endSpan(span, err)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+
+	return result, nil
+}
+
+func (ts *TournamentServicer) findTournament(ctx context.Context, t FindTournamentTask) (domain.Tournament, error) {
+	var result domain.Tournament
+	var err error
+
+	span := txSpan(ctx, "tournaments", "read")
+	err = ts.repository.ReadTx(func(repo ports.TournamentRepository) error {
+		result, err = repo.FindTournament(t.TournamentID)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	endSpan(span, err)
+	if err != nil {
+		return domain.Tournament{}, fmt.Errorf("error finding tournament: %v", err)
+	}
+
+	return result, nil
+}
+
+func (ts *TournamentServicer) listTournaments(ctx context.Context, t ListTournamentsTask) ([]domain.Tournament, error) {
+	var results []domain.Tournament
+	var err error
+
+	span := txSpan(ctx, "tournaments", "read")
+	err = ts.repository.ReadTx(func(repo ports.TournamentRepository) error {
+		results, err = repo.ListTournaments(t.Filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	endSpan(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tournaments: %v", err)
+	}
+
+	return results, nil
+}
+
+func (ts *TournamentServicer) transitionTournament(ctx context.Context, t TransitionTournamentTask) (domain.Tournament, error) {
+	var result domain.Tournament
+
+	// the tournament and the matches of the guards are read under the tournament lock, so two
+	// actions cannot both pass the guards and no round is paired between the guards and the update
+	span := txSpan(ctx, "tournaments", "write")
+	err := ts.repository.WriteTx(func(repo ports.TournamentRepository) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tournament, err := repo.FindTournament(t.Command.ID)
+		if err != nil {
+			return err
+		}
+		if !tournament.ManagedBy(t.Command.Actor) {
+			return domain.ErrTournamentNotOwner
+		}
+
+		var matches []domain.Match
+		matchesSpan := txSpan(ctx, "matches", "read")
+		err = ts.matches.ReadTx(func(repo ports.MatchRepository) error {
+			var err error
+			matches, err = repo.ListMatches(domain.MatchFilter{TournamentID: t.Command.ID})
+			return err
+		})
+		endSpan(matchesSpan, err)
+		if err != nil {
+			return err
+		}
+
+		if err := tournament.Transition(t.Command.Action, t.Command.Actor.ID, t.Command.Reason, matches, time.Now()); err != nil {
+			return err
+		}
+
+		result, err = repo.UpdateTournament(tournament)
+		if err != nil {
+			return err
+		}
+
+		if result.Status == domain.TournamentStatusCompleted {
+			return repo.RecordEvents(domain.TournamentCompleted{
+				TournamentID: result.PublicID,
+				Actor:        t.Command.Actor.ID,
+				At:           result.UpdatedAt,
+			})
+		}
+		return nil
+	})
+	endSpan(span, err)
+	if err != nil {
+		return domain.Tournament{}, fmt.Errorf("error transitioning tournament: %w", err)
+	}
+
+	return result, nil
+}
+
+func (ts *TournamentServicer) registerPlayer(ctx context.Context, t RegisterPlayerTask) (domain.Tournament, error) {
+	var result domain.Tournament
+
+	span := txSpan(ctx, "tournaments", "write")
+	err := ts.repository.WriteTx(func(repo ports.TournamentRepository) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tournament, err := repo.FindTournament(t.Command.TournamentID)
+		if err != nil {
+			return err
+		}
+
+		if err := tournament.Register(t.Command.PlayerID, time.Now()); err != nil {
+			return err
+		}
+
+		result, err = repo.UpdateTournament(tournament)
+		if err != nil {
+			return err
+		}
+
+		return repo.RecordEvents(domain.PlayerRegistered{
+			TournamentID: result.PublicID,
+			PlayerID:     t.Command.PlayerID,
+			At:           result.UpdatedAt,
+		})
+	})
+	endSpan(span, err)
+	if err != nil {
+		return domain.Tournament{}, fmt.Errorf("error registering player: %w", err)
+	}
+
+	return result, nil
+}
+
+func (ts *TournamentServicer) pairRound(ctx context.Context, t PairRoundTask) (domain.PairedRound, error) {
+	var result domain.PairedRound
+
+	pairings := make([]domain.Pairing, len(t.Command.Pairings))
+	for i, p := range t.Command.Pairings {
+		pairings[i] = domain.Pairing{White: p.White, Black: p.Black}
+	}
+
+	// the matches are created under the tournament lock so the same round cannot be paired twice
+	span := txSpan(ctx, "tournaments", "write")
+	err := ts.repository.WriteTx(func(repo ports.TournamentRepository) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tournament, err := repo.FindTournament(t.Command.TournamentID)
+		if err != nil {
+			return err
+		}
+
+		result.Round, err = tournament.PairRound(pairings, time.Now())
+		if err != nil {
+			return err
+		}
+
+		matchesSpan := txSpan(ctx, "matches", "write")
+		err = ts.matches.WriteTx(func(matches ports.MatchRepository) error {
+			for _, p := range pairings {
+				match, err := matches.CreateMatch(tournamentMatch(tournament, p))
+				if err != nil {
+					return err
+				}
+				result.Matches = append(result.Matches, match)
+			}
+			return nil
+		})
+		endSpan(matchesSpan, err)
+		if err != nil {
+			return err
+		}
+
+		tournament, err = repo.UpdateTournament(tournament)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(result.Matches))
+		for i, match := range result.Matches {
+			ids[i] = match.UUID
+		}
+		return repo.RecordEvents(domain.RoundPaired{
+			TournamentID: tournament.PublicID,
+			Round:        result.Round,
+			Matches:      ids,
+			At:           tournament.UpdatedAt,
+		})
+	})
+	endSpan(span, err)
+	if err != nil {
+		return domain.PairedRound{}, fmt.Errorf("error pairing round: %w", err)
+	}
+
+	return result, nil
+}
+
+// tournamentMatch is the match of a pairing, played at the time control and venue of the tournament
+func tournamentMatch(tournament domain.Tournament, p domain.Pairing) domain.Match {
+	ratingType := domain.RatingTypeStandard
+	if !tournament.TimeControl.IsZero() {
+		ratingType = tournament.TimeControl.Category().RatingType()
+	}
+
+	return domain.Match{
+		TournamentID: tournament.PublicID,
+		Location:     tournament.Location,
+		City:         tournament.Location.City,
+		State:        tournament.Location.State,
+		Country:      tournament.Location.Country,
+		WhitePlayer:  p.White,
+		BlackPlayer:  p.Black,
+		RatingType:   ratingType,
+		TimeControl:  tournament.TimeControl,
+		Result:       domain.MatchResultOngoing,
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+// txSpan starts the span of a transaction on the repository, the repositories are not given
+// the context so the services trace their transactions
+func txSpan(ctx context.Context, repository, mode string) ports.Span {
+	_, span := ports.StartSpan(ctx, repository+" "+mode+" tx", ports.SpanKindInternal,
+		ports.String("db.collection.name", repository),
+		ports.String("db.operation.name", mode),
+	)
+	return span
+}
+
+// endSpan ends the span, failed when err is not nil
+func endSpan(span ports.Span, err error) {
+	span.RecordError(err)
+	span.End()
+}
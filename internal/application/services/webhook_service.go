@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/webhook"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// webhookDefaultDeliveries is how many deliveries of the log are returned when the command does not say
+const webhookDefaultDeliveries = 50
+
+// WebhookConfig - how the deliveries are sent and retried, and when a failing webhook is disabled
+type WebhookConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int // deliveries sent per poll
+	MaxAttempts    int // attempts before a delivery is given up on
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	DisableAfter   int // consecutive failed attempts before the webhook is disabled
+	RequestTimeout time.Duration
+}
+
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		PollInterval:   time.Second,
+		BatchSize:      50,
+		MaxAttempts:    6,
+		BaseBackoff:    10 * time.Second,
+		MaxBackoff:     time.Hour,
+		DisableAfter:   20,
+		RequestTimeout: 10 * time.Second,
+	}
+}
+
+// webhookPayload is the body of every delivery, data is the event itself
+type webhookPayload struct {
+	ID         uuid.UUID        `json:"id"`
+	Type       domain.EventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       domain.Event     `json:"data"`
+}
+
+// WebhookServicer manages the webhooks of the api consumers and delivers the events to them.
+// HandleEvent is subscribed to the event bus and only queues the deliveries, they are sent in
+// the background so a slow or broken endpoint does not hold back the other subscribers
+type WebhookServicer struct {
+	logger   ports.Logger
+	webhooks ports.WebhookRepositoryProvider
+	client   ports.HTTPClient
+	config   WebhookConfig
+	now      func() time.Time
+
+	mu       sync.Mutex
+	inFlight map[uuid.UUID]bool // deliveries being attempted, so a redelivery is not sent twice
+
+	delivering sync.Mutex
+	lifecycle  sync.Mutex
+	started    bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func NewWebhookServicer(log ports.Logger, wr ports.WebhookRepositoryProvider, client ports.HTTPClient, config WebhookConfig) *WebhookServicer {
+	return &WebhookServicer{
+		logger:   log,
+		webhooks: wr,
+		client:   client,
+		config:   config,
+		now:      time.Now,
+		inFlight: make(map[uuid.UUID]bool),
+	}
+}
+
+func (ws *WebhookServicer) CreateWebhook(ctx context.Context, cmd commands.CreateWebhookCommand) (domain.Webhook, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	webhook := domain.Webhook{
+		ConsumerID:    cmd.ConsumerID,
+		URL:           cmd.URL,
+		Secret:        secret,
+		EventTypes:    cmd.EventTypes,
+		TournamentIDs: cmd.TournamentIDs,
+		Status:        domain.WebhookStatusActive,
+	}
+
+	err = ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		webhook, err = repo.CreateWebhook(webhook)
+		return err
+	})
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	ws.logger.Info(ctx, "webhook created", ports.String("webhook_id", webhook.PublicID.String()), ports.String("consumer_id", webhook.ConsumerID))
+
+	return webhook, nil
+}
+
+func (ws *WebhookServicer) ListWebhooks(ctx context.Context, cmd commands.ListWebhooksCommand) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := ws.webhooks.ReadTx(func(repo ports.WebhookRepository) error {
+		var err error
+		webhooks, err = repo.ListWebhooks(cmd.ConsumerID)
+		return err
+	})
+
+	return webhooks, err
+}
+
+func (ws *WebhookServicer) FindWebhook(ctx context.Context, cmd commands.FindWebhookCommand) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := ws.webhooks.ReadTx(func(repo ports.WebhookRepository) error {
+		var err error
+		webhook, err = ownedWebhook(repo, cmd.ID, cmd.ConsumerID)
+		return err
+	})
+
+	return webhook, err
+}
+
+// DeleteWebhook removes the webhook, the deliveries still queued for it are given up on
+func (ws *WebhookServicer) DeleteWebhook(ctx context.Context, cmd commands.FindWebhookCommand) error {
+	return ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		if _, err := ownedWebhook(repo, cmd.ID, cmd.ConsumerID); err != nil {
+			return err
+		}
+		return repo.DeleteWebhook(cmd.ID)
+	})
+}
+
+func (ws *WebhookServicer) EnableWebhook(ctx context.Context, cmd commands.FindWebhookCommand) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		found, err := ownedWebhook(repo, cmd.ID, cmd.ConsumerID)
+		if err != nil {
+			return err
+		}
+
+		found.Status = domain.WebhookStatusActive
+		found.ConsecutiveFailures = 0
+		found.DisabledAt = time.Time{}
+		found.DisabledReason = ""
+
+		webhook, err = repo.UpdateWebhook(found)
+		return err
+	})
+
+	return webhook, err
+}
+
+func (ws *WebhookServicer) ListDeliveries(ctx context.Context, cmd commands.ListDeliveriesCommand) ([]domain.WebhookDelivery, error) {
+	limit := cmd.Limit
+	if limit == 0 {
+		limit = webhookDefaultDeliveries
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err := ws.webhooks.ReadTx(func(repo ports.WebhookRepository) error {
+		if _, err := ownedWebhook(repo, cmd.WebhookID, cmd.ConsumerID); err != nil {
+			return err
+		}
+
+		var err error
+		deliveries, err = repo.ListDeliveries(cmd.WebhookID, limit)
+		return err
+	})
+
+	return deliveries, err
+}
+
+// Redeliver queues the payload of the delivery again and attempts it right away, if the attempt
+// fails the new delivery is retried like any other
+func (ws *WebhookServicer) Redeliver(ctx context.Context, cmd commands.RedeliverCommand) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		original, err := repo.FindDelivery(cmd.DeliveryID)
+		if err != nil {
+			return err
+		}
+
+		webhook, err := ownedWebhook(repo, original.WebhookID, cmd.ConsumerID)
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			return domain.ErrWebhookDeliveryNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if webhook.Status == domain.WebhookStatusDisabled {
+			return domain.ErrWebhookDisabled
+		}
+
+		delivery, err = repo.CreateDelivery(domain.WebhookDelivery{
+			WebhookID:     original.WebhookID,
+			EventID:       original.EventID,
+			EventType:     original.EventType,
+			Payload:       original.Payload,
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: ws.now(),
+			RedeliveryOf:  original.PublicID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// claimed before the transaction ends so the background loop never picks it up
+		ws.claim(delivery.PublicID)
+		return nil
+	})
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	defer ws.release(delivery.PublicID)
+
+	return ws.attempt(ctx, delivery)
+}
+
+// ownedWebhook finds the webhook of the consumer, the webhooks of the other consumers are not
+// found so their ids are not told apart from the ids of no webhook
+func ownedWebhook(repo ports.WebhookRepository, id uuid.UUID, consumerID string) (domain.Webhook, error) {
+	webhook, err := repo.FindWebhook(id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.ConsumerID != consumerID {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// HandleEvent queues a delivery of the event for every active webhook that wants it. It is
+// idempotent: when the event bus delivers the event again no delivery is queued twice
+func (ws *WebhookServicer) HandleEvent(ctx context.Context, event domain.Event) error {
+	eventID, ok := EventIDFromContext(ctx)
+	if !ok {
+		eventID = uuid.New()
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:         eventID,
+		Type:       event.EventType(),
+		OccurredAt: event.OccurredAt(),
+		Data:       event,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	return ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		webhooks, err := repo.ListWebhooks("")
+		if err != nil {
+			return err
+		}
+
+		for _, webhook := range webhooks {
+			if webhook.Status != domain.WebhookStatusActive || !webhook.Wants(event) {
+				continue
+			}
+
+			queued, err := repo.HasDelivery(webhook.PublicID, eventID)
+			if err != nil {
+				return err
+			}
+			if queued {
+				continue
+			}
+
+			_, err = repo.CreateDelivery(domain.WebhookDelivery{
+				WebhookID:     webhook.PublicID,
+				EventID:       eventID,
+				EventType:     event.EventType(),
+				Payload:       payload,
+				Status:        domain.WebhookDeliveryPending,
+				NextAttemptAt: ws.now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Start sends the due deliveries until ctx is done or Stop is called
+func (ws *WebhookServicer) Start(ctx context.Context) {
+	ws.lifecycle.Lock()
+	defer ws.lifecycle.Unlock()
+
+	if ws.started {
+		return
+	}
+	ws.stop = make(chan struct{})
+	ws.done = make(chan struct{})
+	ws.started = true
+
+	go ws.run(ctx, ws.stop, ws.done)
+}
+
+// Stop waits for the deliveries in progress to finish
+func (ws *WebhookServicer) Stop() {
+	ws.lifecycle.Lock()
+	defer ws.lifecycle.Unlock()
+
+	if !ws.started {
+		return
+	}
+	close(ws.stop)
+	<-ws.done
+	ws.started = false
+}
+
+func (ws *WebhookServicer) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ws.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := ws.DeliverDue(ctx); err != nil {
+				ws.logger.Error(ctx, "delivering webhooks failed", ports.Error("error", err))
+			}
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// DeliverDue attempts the deliveries that are due concurrently and returns how many succeeded
+func (ws *WebhookServicer) DeliverDue(ctx context.Context) (int, error) {
+	ws.delivering.Lock()
+	defer ws.delivering.Unlock()
+
+	var due []domain.WebhookDelivery
+	err := ws.webhooks.ReadTx(func(repo ports.WebhookRepository) error {
+		var err error
+		due, err = repo.DueDeliveries(ws.now(), ws.config.BatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for _, delivery := range due {
+		if !ws.claim(delivery.PublicID) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(delivery domain.WebhookDelivery) {
+			defer wg.Done()
+			defer ws.release(delivery.PublicID)
+
+			result, err := ws.attempt(ctx, delivery)
+			if err != nil {
+				ws.logger.Error(ctx, "recording webhook delivery failed", ports.String("delivery_id", delivery.PublicID.String()), ports.Error("error", err))
+				return
+			}
+			if result.Status == domain.WebhookDeliverySucceeded {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return succeeded, nil
+}
+
+// claim marks the delivery as being attempted, false if it already is
+func (ws *WebhookServicer) claim(id uuid.UUID) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.inFlight[id] {
+		return false
+	}
+	ws.inFlight[id] = true
+	return true
+}
+
+func (ws *WebhookServicer) release(id uuid.UUID) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.inFlight, id)
+}
+
+// attempt sends the delivery to the endpoint of its webhook and records the outcome in the
+// delivery log. A failure schedules a retry until MaxAttempts, every failure counts towards
+// disabling the webhook and a success resets the count
+func (ws *WebhookServicer) attempt(ctx context.Context, delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	var webhook domain.Webhook
+	err := ws.webhooks.ReadTx(func(repo ports.WebhookRepository) error {
+		var err error
+		webhook, err = repo.FindWebhook(delivery.WebhookID)
+		return err
+	})
+
+	var attempt domain.WebhookAttempt
+	switch {
+	case err == nil && webhook.Status == domain.WebhookStatusActive:
+		attempt = ws.send(ctx, webhook, delivery)
+	case err == nil:
+		attempt = domain.WebhookAttempt{At: ws.now(), Error: domain.ErrWebhookDisabled.Error()}
+	default:
+		attempt = domain.WebhookAttempt{At: ws.now(), Error: err.Error()}
+	}
+
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	sent := err == nil && webhook.Status == domain.WebhookStatusActive
+	switch {
+	case attempt.Succeeded():
+		delivery.Status = domain.WebhookDeliverySucceeded
+	case !sent || len(delivery.Attempts) >= ws.config.MaxAttempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+	default:
+		delivery.NextAttemptAt = attempt.At.Add(backoff(ws.config.BaseBackoff, ws.config.MaxBackoff, len(delivery.Attempts)))
+	}
+
+	fields := []ports.LogField{
+		ports.String("webhook_id", delivery.WebhookID.String()),
+		ports.String("delivery_id", delivery.PublicID.String()),
+		ports.String("event", string(delivery.EventType)),
+		ports.Int("attempts", len(delivery.Attempts)),
+		ports.Int("status_code", attempt.StatusCode),
+	}
+	if !attempt.Succeeded() {
+		ws.logger.Warn(ctx, "webhook delivery failed", append(fields, ports.String("reason", attempt.Error))...)
+	}
+
+	err = ws.webhooks.WriteTx(func(repo ports.WebhookRepository) error {
+		var err error
+		delivery, err = repo.UpdateDelivery(delivery)
+		if err != nil || !sent {
+			return err
+		}
+
+		// read again, other deliveries of the webhook may have been attempted meanwhile
+		current, err := repo.FindWebhook(webhook.PublicID)
+		if err != nil {
+			return err
+		}
+
+		current.LastDeliveryAt = attempt.At
+		if attempt.Succeeded() {
+			current.ConsecutiveFailures = 0
+		} else {
+			current.ConsecutiveFailures++
+			if current.Status == domain.WebhookStatusActive && current.ConsecutiveFailures >= ws.config.DisableAfter {
+				current.Status = domain.WebhookStatusDisabled
+				current.DisabledAt = attempt.At
+				current.DisabledReason = fmt.Sprintf("%d consecutive failed deliveries", current.ConsecutiveFailures)
+				ws.logger.Warn(ctx, "webhook disabled", append(fields, ports.String("reason", current.DisabledReason))...)
+			}
+		}
+
+		_, err = repo.UpdateWebhook(current)
+		return err
+	})
+
+	return delivery, err
+}
+
+// send posts the payload signed with the secret of the webhook
+func (ws *WebhookServicer) send(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) domain.WebhookAttempt {
+	ctx, cancel := context.WithTimeout(ctx, ws.config.RequestTimeout)
+	defer cancel()
+
+	at := ws.now()
+	timestamp := at.Unix()
+	headers := map[string]string{
+		"Content-Type":                "application/json",
+		"User-Agent":                  "maple-webhooks",
+		domain.WebhookHeaderEvent:     string(delivery.EventType),
+		domain.WebhookHeaderEventID:   delivery.EventID.String(),
+		domain.WebhookHeaderDelivery:  delivery.PublicID.String(),
+		domain.WebhookHeaderTimestamp: strconv.FormatInt(timestamp, 10),
+		domain.WebhookHeaderSignature: domain.SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload),
+	}
+
+	start := time.Now()
+	resp, err := ws.client.Post(ctx, webhook.URL, delivery.Payload, headers)
+	attempt := domain.WebhookAttempt{At: at, Duration: time.Since(start)}
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	// only the status is kept, the body of whatever answered is not the business of the consumer
+	attempt.StatusCode = resp.StatusCode
+	if !attempt.Succeeded() {
+		attempt.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+// newWebhookSecret returns 32 random bytes hex encoded, prefixed so they are recognized if leaked
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
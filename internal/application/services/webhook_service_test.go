@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	commands "github.com/ctfrancia/maple/internal/application/commands/webhook"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+// webhookReceiver is an endpoint that verifies the signatures like a consumer would and
+// answers with the status codes it is told to
+type webhookReceiver struct {
+	t      *testing.T
+	secret string
+
+	mu       sync.Mutex
+	statuses []int // answered in order, 200 once they run out
+	received []webhookPayload
+	headers  []http.Header
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	err := domain.VerifyWebhookSignature(wr.secret, r.Header.Get(domain.WebhookHeaderTimestamp), r.Header.Get(domain.WebhookHeaderSignature), body, 5*time.Minute, time.Now())
+	if err != nil {
+		wr.t.Errorf("signature not valid: %v", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		webhookPayload
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		wr.t.Errorf("payload not valid: %v", err)
+	}
+	wr.received = append(wr.received, payload.webhookPayload)
+	wr.headers = append(wr.headers, r.Header.Clone())
+
+	status := http.StatusOK
+	if len(wr.statuses) > 0 {
+		status, wr.statuses = wr.statuses[0], wr.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (wr *webhookReceiver) count() int {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return len(wr.received)
+}
+
+func newTestWebhookServicer(t *testing.T, config WebhookConfig) (*WebhookServicer, *time.Time) {
+	t.Helper()
+
+	ws := NewWebhookServicer(lggr, inmemory.NewWebhookRepositoryProvider(inmemory.NewInMemoryWebhookRepository()), infrastructure.NewHTTPClientAdapter("", time.Second), config)
+
+	// the signatures are checked against the wall clock, the retries only move forward from it
+	now := time.Now()
+	ws.now = func() time.Time { return now }
+
+	return ws, &now
+}
+
+func testWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		MaxAttempts:    3,
+		BaseBackoff:    time.Second,
+		MaxBackoff:     time.Minute,
+		DisableAfter:   5,
+		RequestTimeout: time.Second,
+	}
+}
+
+func createTestWebhook(t *testing.T, ws *WebhookServicer, receiver *webhookReceiver, url string, types ...domain.EventType) domain.Webhook {
+	t.Helper()
+
+	webhook, err := ws.CreateWebhook(context.Background(), commands.CreateWebhookCommand{ConsumerID: "consumer", URL: url, EventTypes: types})
+	if err != nil {
+		t.Fatalf("error creating webhook: %v", err)
+	}
+	receiver.secret = webhook.Secret
+
+	return webhook
+}
+
+func deliveries(t *testing.T, ws *WebhookServicer, webhookID uuid.UUID) []domain.WebhookDelivery {
+	t.Helper()
+
+	list, err := ws.ListDeliveries(context.Background(), commands.ListDeliveriesCommand{ConsumerID: "consumer", WebhookID: webhookID})
+	if err != nil {
+		t.Fatalf("error listing deliveries: %v", err)
+	}
+	return list
+}
+
+func TestWebhookServicer_Deliver(t *testing.T) {
+	receiver := &webhookReceiver{t: t}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	ws, _ := newTestWebhookServicer(t, testWebhookConfig())
+	webhook := createTestWebhook(t, ws, receiver, srv.URL, domain.EventTournamentCreated)
+
+	eventID := uuid.New()
+	ctx := context.WithValue(context.Background(), eventIDKey{}, eventID)
+	tournamentID := uuid.New()
+
+	// delivered twice by the event bus, not of the type and the one that is wanted
+	for range 2 {
+		if err := ws.HandleEvent(ctx, domain.TournamentCreated{TournamentID: tournamentID, Name: "Open"}); err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+	if err := ws.HandleEvent(context.Background(), domain.TournamentCompleted{TournamentID: tournamentID}); err != nil {
+		t.Fatalf("error handling event: %v", err)
+	}
+
+	succeeded, err := ws.DeliverDue(context.Background())
+	if err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+	if succeeded != 1 || receiver.count() != 1 {
+		t.Fatalf("expected 1 delivery, got %d succeeded and %d received", succeeded, receiver.count())
+	}
+
+	payload, header := receiver.received[0], receiver.headers[0]
+	if payload.ID != eventID || payload.Type != domain.EventTournamentCreated {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if header.Get(domain.WebhookHeaderEventID) != eventID.String() || header.Get(domain.WebhookHeaderEvent) != string(domain.EventTournamentCreated) {
+		t.Errorf("unexpected headers %v", header)
+	}
+
+	log := deliveries(t, ws, webhook.PublicID)
+	if len(log) != 1 || log[0].Status != domain.WebhookDeliverySucceeded {
+		t.Fatalf("expected 1 successful delivery in the log, got %+v", log)
+	}
+	if attempt := log[0].Attempts[0]; attempt.StatusCode != http.StatusOK || attempt.Error != "" {
+		t.Errorf("expected the status of the response to be logged, got %+v", attempt)
+	}
+}
+
+func TestWebhookServicer_Retry(t *testing.T) {
+	receiver := &webhookReceiver{t: t, statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	ws, now := newTestWebhookServicer(t, testWebhookConfig())
+	webhook := createTestWebhook(t, ws, receiver, srv.URL)
+
+	if err := ws.HandleEvent(context.Background(), domain.TournamentCreated{TournamentID: uuid.New()}); err != nil {
+		t.Fatalf("error handling event: %v", err)
+	}
+
+	// 1s then 2s of backoff, nothing is sent before it is due
+	waits := []time.Duration{0, time.Second, 2 * time.Second}
+	for _, wait := range waits {
+		*now = now.Add(wait - time.Millisecond)
+		if _, err := ws.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("error delivering: %v", err)
+		}
+		*now = now.Add(time.Millisecond)
+		if _, err := ws.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("error delivering: %v", err)
+		}
+	}
+
+	if got := receiver.count(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+
+	log := deliveries(t, ws, webhook.PublicID)
+	if len(log) != 1 || log[0].Status != domain.WebhookDeliverySucceeded || len(log[0].Attempts) != 3 {
+		t.Fatalf("expected the delivery to succeed on its third attempt, got %+v", log)
+	}
+	if log[0].Attempts[0].StatusCode != http.StatusInternalServerError || log[0].Attempts[0].Error == "" {
+		t.Errorf("expected the failed attempt to be logged, got %+v", log[0].Attempts[0])
+	}
+
+	found, err := ws.FindWebhook(context.Background(), commands.FindWebhookCommand{ConsumerID: "consumer", ID: webhook.PublicID})
+	if err != nil {
+		t.Fatalf("error finding webhook: %v", err)
+	}
+	if found.ConsecutiveFailures != 0 {
+		t.Errorf("expected the failures to be reset, got %d", found.ConsecutiveFailures)
+	}
+}
+
+func TestWebhookServicer_AutoDisable(t *testing.T) {
+	receiver := &webhookReceiver{t: t, statuses: []int{500, 500, 500, 500, 500}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	config := testWebhookConfig()
+	config.DisableAfter = 2
+	ws, now := newTestWebhookServicer(t, config)
+	webhook := createTestWebhook(t, ws, receiver, srv.URL)
+
+	for range 3 {
+		if err := ws.HandleEvent(context.Background(), domain.TournamentCreated{TournamentID: uuid.New()}); err != nil {
+			t.Fatalf("error handling event: %v", err)
+		}
+	}
+
+	// the batch is sent concurrently, the webhook is disabled after the second failure
+	// and whatever was not sent by then is given up on
+	if _, err := ws.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+	*now = now.Add(time.Hour)
+	if _, err := ws.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+
+	found, err := ws.FindWebhook(context.Background(), commands.FindWebhookCommand{ConsumerID: "consumer", ID: webhook.PublicID})
+	if err != nil {
+		t.Fatalf("error finding webhook: %v", err)
+	}
+	if found.Status != domain.WebhookStatusDisabled || found.DisabledReason == "" {
+		t.Fatalf("expected the webhook to be disabled, got %+v", found)
+	}
+	for _, d := range deliveries(t, ws, webhook.PublicID) {
+		if d.Status != domain.WebhookDeliveryFailed {
+			t.Errorf("expected every delivery to be given up on, got %s", d.Status)
+		}
+	}
+
+	// new events are not queued for a disabled webhook
+	sent := receiver.count()
+	_ = ws.HandleEvent(context.Background(), domain.TournamentCreated{TournamentID: uuid.New()})
+	if got := len(deliveries(t, ws, webhook.PublicID)); got != 3 {
+		t.Errorf("expected no new delivery, got %d deliveries", got)
+	}
+
+	if _, err := ws.Redeliver(context.Background(), commands.RedeliverCommand{ConsumerID: "consumer", DeliveryID: deliveries(t, ws, webhook.PublicID)[0].PublicID}); !errors.Is(err, domain.ErrWebhookDisabled) {
+		t.Errorf("expected ErrWebhookDisabled, got %v", err)
+	}
+
+	enabled, err := ws.EnableWebhook(context.Background(), commands.FindWebhookCommand{ConsumerID: "consumer", ID: webhook.PublicID})
+	if err != nil {
+		t.Fatalf("error enabling webhook: %v", err)
+	}
+	if enabled.Status != domain.WebhookStatusActive || enabled.ConsecutiveFailures != 0 {
+		t.Errorf("expected the webhook to be active again, got %+v", enabled)
+	}
+	if receiver.count() != sent {
+		t.Errorf("expected nothing sent while disabled")
+	}
+}
+
+func TestWebhookServicer_Redeliver(t *testing.T) {
+	receiver := &webhookReceiver{t: t, statuses: []int{http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	config := testWebhookConfig()
+	config.MaxAttempts = 1
+	ws, _ := newTestWebhookServicer(t, config)
+	webhook := createTestWebhook(t, ws, receiver, srv.URL)
+
+	if err := ws.HandleEvent(context.Background(), domain.TournamentCreated{TournamentID: uuid.New()}); err != nil {
+		t.Fatalf("error handling event: %v", err)
+	}
+	if _, err := ws.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+
+	failed := deliveries(t, ws, webhook.PublicID)[0]
+	if failed.Status != domain.WebhookDeliveryFailed {
+		t.Fatalf("expected the delivery to fail, got %s", failed.Status)
+	}
+
+	redelivered, err := ws.Redeliver(context.Background(), commands.RedeliverCommand{ConsumerID: "consumer", DeliveryID: failed.PublicID})
+	if err != nil {
+		t.Fatalf("error redelivering: %v", err)
+	}
+	if redelivered.Status != domain.WebhookDeliverySucceeded || redelivered.RedeliveryOf != failed.PublicID || redelivered.EventID != failed.EventID {
+		t.Errorf("unexpected redelivery %+v", redelivered)
+	}
+	if receiver.received[0].ID != receiver.received[1].ID {
+		t.Errorf("expected the same event to be redelivered")
+	}
+
+	// already attempted, the background loop does not send it again
+	if _, err := ws.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+	if got := receiver.count(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+
+	if _, err := ws.Redeliver(context.Background(), commands.RedeliverCommand{ConsumerID: "consumer", DeliveryID: uuid.New()}); !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestWebhookServicer_OtherConsumer(t *testing.T) {
+	receiver := &webhookReceiver{t: t}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	ws, _ := newTestWebhookServicer(t, testWebhookConfig())
+	webhook := createTestWebhook(t, ws, receiver, srv.URL)
+
+	if err := ws.HandleEvent(context.Background(), domain.TournamentCreated{TournamentID: uuid.New()}); err != nil {
+		t.Fatalf("error handling event: %v", err)
+	}
+	if _, err := ws.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("error delivering: %v", err)
+	}
+	delivery := deliveries(t, ws, webhook.PublicID)[0]
+
+	// the webhooks of another consumer are not found, as if they did not exist
+	ctx := context.Background()
+	find := commands.FindWebhookCommand{ConsumerID: "other", ID: webhook.PublicID}
+	if _, err := ws.FindWebhook(ctx, find); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound finding, got %v", err)
+	}
+	if _, err := ws.EnableWebhook(ctx, find); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound enabling, got %v", err)
+	}
+	if err := ws.DeleteWebhook(ctx, find); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound deleting, got %v", err)
+	}
+	if _, err := ws.ListDeliveries(ctx, commands.ListDeliveriesCommand{ConsumerID: "other", WebhookID: webhook.PublicID}); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound listing deliveries, got %v", err)
+	}
+	if _, err := ws.Redeliver(ctx, commands.RedeliverCommand{ConsumerID: "other", DeliveryID: delivery.PublicID}); !errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected ErrWebhookDeliveryNotFound redelivering, got %v", err)
+	}
+	if list, err := ws.ListWebhooks(ctx, commands.ListWebhooksCommand{ConsumerID: "other"}); err != nil || len(list) != 0 {
+		t.Errorf("expected no webhook of the other consumer, got %v and %v", list, err)
+	}
+
+	if receiver.count() != 1 {
+		t.Errorf("expected nothing sent for the other consumer, got %d requests", receiver.count())
+	}
+	if _, err := ws.FindWebhook(ctx, commands.FindWebhookCommand{ConsumerID: "consumer", ID: webhook.PublicID}); err != nil {
+		t.Errorf("expected the webhook to be left alone, got %v", err)
+	}
+}
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+)
+
+var (
+	ErrTaskRegistered    = errors.New("a handler is already registered for the task")
+	ErrTaskNotRegistered = errors.New("no handler is registered for the task")
+	ErrTaskPanicked      = errors.New("the task handler panicked")
+)
+
+// TaskPriority is the lane a task waits in, the workers take from the high lane first so the
+// reads are not held up behind bulk work
+type TaskPriority int
+
+const (
+	TaskPriorityNormal TaskPriority = iota // the writes, the zero value
+	TaskPriorityHigh                       // the reads the api answers
+	TaskPriorityLow                        // bulk work such as imports
+)
+
+// fairnessEvery is how often a worker looks at the low lane first, a steady stream of reads
+// would never let a bulk import through otherwise
+const fairnessEvery = 8
+
+// WorkerPoolConfig tunes the worker pool
+type WorkerPoolConfig struct {
+	// Workers is how many tasks run at the same time
+	Workers int
+	// QueueSize is how many tasks each priority lane holds before submissions block
+	QueueSize int
+	// SubmitTimeout is how long a submission waits for room in a full lane before failing
+	// with ErrWorkerPoolFull, the caller's context can cut it short
+	SubmitTimeout time.Duration
+	// TaskTimeout is how long a task has from its submission when its handler sets no
+	// timeout of its own
+	TaskTimeout time.Duration
+}
+
+func DefaultWorkerPoolConfig() WorkerPoolConfig {
+	return WorkerPoolConfig{
+		Workers:       runtime.NumCPU() * 2,
+		QueueSize:     256,
+		SubmitTimeout: 2 * time.Second,
+		TaskTimeout:   10 * time.Second,
+	}
+}
+
+// TaskHandler runs the tasks of a request type, the context carries the task's timeout
+type TaskHandler[Req, Resp any] func(ctx context.Context, req Req) (Resp, error)
+
+// TaskOptions are how the tasks of a handler are queued
+type TaskOptions struct {
+	Priority TaskPriority
+	Timeout  time.Duration // from the submission, 0 is the pool's TaskTimeout
+	Name     string        // of the task in the metrics and the spans, the request type by default
+}
+
+// Task is a handler registered in a pool, submitting to it returns the future of the response
+type Task[Req, Resp any] struct {
+	pool    *WorkerPool
+	name    string
+	handler TaskHandler[Req, Resp]
+	options TaskOptions
+}
+
+// taskKey is the request and response types a handler is registered for
+type taskKey struct {
+	request, response reflect.Type
+}
+
+// WorkerPool runs the tasks of the handlers registered in it on a fixed number of workers.
+// Any subsystem can register its handlers, a task is found by its request and response
+// types. Submissions wait for room in their lane rather than failing as soon as the workers
+// are busy, and Stop lets the workers finish what was queued
+type WorkerPool struct {
+	logger  ports.Logger
+	metrics workerPoolMetrics
+	config  WorkerPoolConfig
+	lanes   [3]chan queuedTask // high, normal and low
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped chan struct{} // closed by Stop, unblocks the submissions waiting for room
+	stop    sync.Once
+	started bool
+	closing bool // set by Stop under mu, the pool is not started again
+	mu      sync.RWMutex
+
+	handlersMu sync.RWMutex
+	handlers   map[taskKey]any // *Task[Req, Resp]
+}
+
+// workerPoolMetrics are the instruments the pool reports to
+type workerPoolMetrics struct {
+	queueDepth   ports.Gauge     // per lane
+	busyWorkers  ports.Gauge     // running a task
+	taskDuration ports.Histogram // per task, of the handler
+	rejections   ports.Counter   // per reason, the tasks answered without running
+	panics       ports.Counter   // per task
+}
+
+func newWorkerPoolMetrics(m ports.Metrics) workerPoolMetrics {
+	if m == nil {
+		m = nopMetrics{}
+	}
+	return workerPoolMetrics{
+		queueDepth:   m.Gauge("maple_worker_pool_queue_depth", "Tasks waiting in the lanes of the worker pool.", "lane"),
+		busyWorkers:  m.Gauge("maple_worker_pool_busy_workers", "Workers of the pool running a task."),
+		taskDuration: m.Histogram("maple_worker_pool_task_duration_seconds", "Time the handlers of the tasks took.", nil, "task"),
+		rejections:   m.Counter("maple_worker_pool_rejections_total", "Tasks answered without running.", "reason"),
+		panics:       m.Counter("maple_worker_pool_task_panics_total", "Task handlers that panicked.", "task"),
+	}
+}
+
+// laneNames label the lanes in the metrics
+var laneNames = [3]string{"high", "normal", "low"}
+
+// queuedTask is a task waiting in a lane with its request bound, the context its timeout
+// runs on and the future it answers
+type queuedTask struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wait   ports.Span // of the time in the lane
+	run    func(ctx context.Context) error
+	fail   func(err error)
+}
+
+// NewWorkerPool - a nil metrics reports nowhere
+func NewWorkerPool(ctx context.Context, log ports.Logger, metrics ports.Metrics, config WorkerPoolConfig) *WorkerPool {
+	defaults := DefaultWorkerPoolConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.SubmitTimeout <= 0 {
+		config.SubmitTimeout = defaults.SubmitTimeout
+	}
+	if config.TaskTimeout <= 0 {
+		config.TaskTimeout = defaults.TaskTimeout
+	}
+
+	wp := &WorkerPool{
+		logger:   log,
+		metrics:  newWorkerPoolMetrics(metrics),
+		config:   config,
+		stopped:  make(chan struct{}),
+		handlers: make(map[taskKey]any),
+	}
+	wp.ctx, wp.cancel = context.WithCancel(ctx)
+	for i := range wp.lanes {
+		wp.lanes[i] = make(chan queuedTask, config.QueueSize)
+	}
+
+	return wp
+}
+
+// Register binds the handler to its request and response types, a pair takes a single handler
+func Register[Req, Resp any](wp *WorkerPool, handler TaskHandler[Req, Resp], options TaskOptions) (*Task[Req, Resp], error) {
+	key := taskKey{request: reflect.TypeFor[Req](), response: reflect.TypeFor[Resp]()}
+	task := &Task[Req, Resp]{
+		pool:    wp,
+		name:    key.request.String(),
+		handler: handler,
+		options: options,
+	}
+	if options.Name != "" {
+		task.name = options.Name
+	}
+
+	wp.handlersMu.Lock()
+	defer wp.handlersMu.Unlock()
+
+	if _, ok := wp.handlers[key]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrTaskRegistered, task.name)
+	}
+	wp.handlers[key] = task
+
+	return task, nil
+}
+
+// Submit queues the request for the handler registered for its types, for the callers that
+// do not hold the task. The future fails with ErrTaskNotRegistered when there is none
+func Submit[Req, Resp any](ctx context.Context, wp *WorkerPool, req Req) *Future[Resp] {
+	key := taskKey{request: reflect.TypeFor[Req](), response: reflect.TypeFor[Resp]()}
+
+	wp.handlersMu.RLock()
+	registered, ok := wp.handlers[key]
+	wp.handlersMu.RUnlock()
+	if !ok {
+		return failedFuture[Resp](fmt.Errorf("%w: %s", ErrTaskNotRegistered, key.request))
+	}
+
+	return registered.(*Task[Req, Resp]).Submit(ctx, req)
+}
+
+// Submit queues the request in the lane of the task's priority. When the lane is full it
+// waits for room until the pool's SubmitTimeout, failing with ErrWorkerPoolFull, or until
+// ctx is done. Cancelling ctx also drops the task if it has not started
+func (t *Task[Req, Resp]) Submit(ctx context.Context, req Req) *Future[Resp] {
+	future := newFuture[Resp]()
+	item := queuedTask{
+		name: t.name,
+		run: func(ctx context.Context) error {
+			value, err := t.handler(ctx, req)
+			future.complete(value, err)
+			return err
+		},
+		fail: func(err error) {
+			var zero Resp
+			future.complete(zero, err)
+		},
+	}
+
+	timeout := t.options.Timeout
+	if timeout <= 0 {
+		timeout = t.pool.config.TaskTimeout
+	}
+	t.pool.enqueue(ctx, t.options.Priority, timeout, item)
+
+	return future
+}
+
+func (wp *WorkerPool) enqueue(ctx context.Context, priority TaskPriority, timeout time.Duration, item queuedTask) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	index := priority.lane()
+	_, item.wait = ports.StartSpan(ctx, "queue "+item.name, ports.SpanKindInternal, ports.String("lane", laneNames[index]))
+
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+
+	select {
+	case <-wp.stopped:
+		wp.reject(item, "stopped", domain.ErrWorkerPoolStopped)
+		return
+	case <-wp.ctx.Done():
+		wp.reject(item, "stopped", domain.ErrWorkerPoolStopped)
+		return
+	default:
+	}
+
+	item.ctx, item.cancel = context.WithTimeout(ctx, timeout)
+
+	lane := wp.lanes[index]
+	select {
+	case lane <- item:
+		wp.metrics.queueDepth.Set(float64(len(lane)), laneNames[index])
+		return
+	default:
+	}
+
+	timer := time.NewTimer(wp.config.SubmitTimeout)
+	defer timer.Stop()
+
+	select {
+	case lane <- item:
+		wp.metrics.queueDepth.Set(float64(len(lane)), laneNames[index])
+		return
+	case <-ctx.Done():
+		item.cancel()
+		wp.reject(item, "cancelled", ctx.Err())
+	case <-timer.C:
+		item.cancel()
+		wp.reject(item, "full", domain.ErrWorkerPoolFull)
+	case <-wp.stopped:
+		item.cancel()
+		wp.reject(item, "stopped", domain.ErrWorkerPoolStopped)
+	case <-wp.ctx.Done():
+		item.cancel()
+		wp.reject(item, "stopped", domain.ErrWorkerPoolStopped)
+	}
+}
+
+// reject answers the task without running it
+func (wp *WorkerPool) reject(item queuedTask, reason string, err error) {
+	wp.metrics.rejections.Inc(reason)
+	item.wait.RecordError(err)
+	item.wait.End()
+	item.fail(err)
+}
+
+func (wp *WorkerPool) Start() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.started || wp.closing {
+		return
+	}
+
+	for i := 0; i < wp.config.Workers; i++ {
+		wp.wg.Add(1)
+		go wp.worker()
+	}
+	wp.started = true
+}
+
+func (wp *WorkerPool) worker() {
+	defer wp.wg.Done()
+
+	for n := 1; ; n++ {
+		item, ok := wp.next(n%fairnessEvery == 0)
+		if !ok {
+			return
+		}
+		wp.run(item)
+	}
+}
+
+// next takes a task from the first lane that has one, high to low unless lowFirst. It waits
+// for one when the lanes are empty and reports false once the pool is stopped and drained
+func (wp *WorkerPool) next(lowFirst bool) (queuedTask, bool) {
+	if item, ok := wp.poll(lowFirst); ok {
+		return item, true
+	}
+
+	select {
+	case item := <-wp.lanes[0]:
+		wp.taken(0)
+		return item, true
+	case item := <-wp.lanes[1]:
+		wp.taken(1)
+		return item, true
+	case item := <-wp.lanes[2]:
+		wp.taken(2)
+		return item, true
+	case <-wp.stopped:
+		return wp.poll(lowFirst)
+	case <-wp.ctx.Done():
+		return queuedTask{}, false
+	}
+}
+
+// poll takes a task without waiting
+func (wp *WorkerPool) poll(lowFirst bool) (queuedTask, bool) {
+	for i := range wp.lanes {
+		lane := i
+		if lowFirst {
+			lane = len(wp.lanes) - 1 - i
+		}
+		select {
+		case item := <-wp.lanes[lane]:
+			wp.taken(lane)
+			return item, true
+		default:
+		}
+	}
+	return queuedTask{}, false
+}
+
+// taken updates the depth of the lane a task was taken from
+func (wp *WorkerPool) taken(lane int) {
+	wp.metrics.queueDepth.Set(float64(len(wp.lanes[lane])), laneNames[lane])
+}
+
+// run answers the task, the ones whose caller gave up or whose timeout passed while they
+// were queued are answered without running. A panicking handler fails its task and leaves
+// the worker running
+func (wp *WorkerPool) run(item queuedTask) {
+	defer item.cancel()
+
+	if err := item.ctx.Err(); err != nil {
+		wp.reject(item, "expired", taskError(err))
+		return
+	}
+
+	item.wait.End()
+	ctx, span := ports.StartSpan(item.ctx, "task "+item.name, ports.SpanKindInternal)
+	defer span.End()
+
+	wp.metrics.busyWorkers.Add(1)
+	start := time.Now()
+	defer func() {
+		wp.metrics.busyWorkers.Add(-1)
+		wp.metrics.taskDuration.Observe(time.Since(start).Seconds(), item.name)
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			wp.metrics.panics.Inc(item.name)
+			err := fmt.Errorf("%w: %s: %v", ErrTaskPanicked, item.name, r)
+			span.RecordError(err)
+			wp.logger.Error(ctx, "task handler panicked",
+				ports.String("task", item.name),
+				ports.String("panic", fmt.Sprint(r)),
+				ports.String("stack", string(debug.Stack())),
+			)
+			item.fail(err)
+		}
+	}()
+
+	span.RecordError(item.run(ctx))
+}
+
+// taskError tells a task that ran out of time from one whose caller cancelled it
+func taskError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", domain.ErrTaskTimeout, err)
+	}
+	return err
+}
+
+// Ping reports the pool down once it is stopped or when it was never started
+func (wp *WorkerPool) Ping(ctx context.Context) error {
+	select {
+	case <-wp.stopped:
+		return domain.ErrWorkerPoolStopped
+	default:
+	}
+
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+	if wp.closing {
+		return domain.ErrWorkerPoolStopped
+	}
+	if !wp.started {
+		return errors.New("the worker pool is not started")
+	}
+	return nil
+}
+
+// Stop stops taking tasks and waits for the workers to run the queued ones, the tasks still
+// queued when the context of the pool is cancelled fail with ErrWorkerPoolStopped. The pool
+// is stopped once, the callers racing the first one wait for it to be done
+func (wp *WorkerPool) Stop() {
+	wp.stop.Do(wp.shutdown)
+}
+
+func (wp *WorkerPool) shutdown() {
+	close(wp.stopped)
+
+	// no submission is in flight once the lock is held, the later ones see the pool stopped. It
+	// is released before waiting, the running tasks may submit others and Ping takes it too
+	wp.mu.Lock()
+	wp.closing = true
+	started := wp.started
+	wp.mu.Unlock()
+
+	if started {
+		wp.wg.Wait()
+	}
+	wp.mu.Lock()
+	wp.started = false
+	wp.mu.Unlock()
+	wp.cancel()
+
+	for {
+		item, ok := wp.poll(false)
+		if !ok {
+			return
+		}
+		item.cancel()
+		wp.reject(item, "stopped", domain.ErrWorkerPoolStopped)
+	}
+}
+
+// lane is the index of the lane of the priority
+func (p TaskPriority) lane() int {
+	switch p {
+	case TaskPriorityHigh:
+		return 0
+	case TaskPriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Future is the response of a submitted task
+type Future[T any] struct {
+	done  chan struct{}
+	once  sync.Once
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+func failedFuture[T any](err error) *Future[T] {
+	future := newFuture[T]()
+	var zero T
+	future.complete(zero, err)
+	return future
+}
+
+// complete answers the future, the answers after the first are ignored
+func (f *Future[T]) complete(value T, err error) {
+	f.once.Do(func() {
+		f.value, f.err = value, taskError(err)
+		close(f.done)
+	})
+}
+
+// Done is closed once the response is in
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the response or for ctx, the task keeps going when ctx is done first
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
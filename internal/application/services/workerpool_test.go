@@ -0,0 +1,401 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/adapters/metrics"
+	"github.com/ctfrancia/maple/internal/adapters/persistence/inmemory"
+	"github.com/ctfrancia/maple/internal/adapters/tracing"
+	commands "github.com/ctfrancia/maple/internal/application/commands/tournament"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// gate holds the tasks until it is opened, recording the order they started in
+type gate struct {
+	started chan string
+	open    chan struct{}
+}
+
+func newGate() *gate {
+	return &gate{started: make(chan string, 8), open: make(chan struct{})}
+}
+
+// gatedTask registers a handler of the request type that waits for the gate
+func gatedTask[Req ~string](t *testing.T, wp *WorkerPool, g *gate, options TaskOptions) *Task[Req, string] {
+	t.Helper()
+
+	task, err := Register(wp, func(ctx context.Context, req Req) (string, error) {
+		g.started <- string(req)
+		<-g.open
+		return string(req), nil
+	}, options)
+	if err != nil {
+		t.Fatalf("error registering the task: %v", err)
+	}
+	return task
+}
+
+type (
+	highRequest    string
+	normalRequest  string
+	lowRequest     string
+	timeoutRequest string
+	chainRequest   string
+)
+
+func TestWorkerPool_Burst(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 2, QueueSize: 4})
+	wp.Start()
+	defer wp.Stop()
+
+	repo := inmemory.NewTournamentRepositoryProvider(inmemory.NewInMemoryTournamentRepository(), nil)
+	ts, err := NewTournamentServicer(lggr, repo, inmemory.NewMatchRepositoryProvider(inmemory.NewInMemoryMatchRepository(), nil), nil, nil, wp, nil)
+	if err != nil {
+		t.Fatalf("error creating service: %v", err)
+	}
+	tournament, err := ts.CreateTournament(ctx, commands.CreateTournamentCommand{Name: "Spring Open", TimeControl: "90+30"})
+	if err != nil {
+		t.Fatalf("error creating tournament: %v", err)
+	}
+
+	// many more requests than workers and room in the queue, they wait rather than fail
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ts.FindTournament(ctx, commands.FindTournamentCommand{ID: tournament.PublicID}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("expected every request of the burst to succeed, got %v", err)
+	}
+}
+
+func TestWorkerPool_Priorities(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 1})
+	wp.Start()
+	defer wp.Stop()
+
+	g := newGate()
+	high := gatedTask[highRequest](t, wp, g, TaskOptions{Priority: TaskPriorityHigh})
+	normal := gatedTask[normalRequest](t, wp, g, TaskOptions{Priority: TaskPriorityNormal})
+	low := gatedTask[lowRequest](t, wp, g, TaskOptions{Priority: TaskPriorityLow})
+
+	// the only worker is busy while the other tasks are queued
+	busy := normal.Submit(ctx, "busy")
+	if name := <-g.started; name != "busy" {
+		t.Fatalf("expected the busy task to start, got %s", name)
+	}
+	futures := []*Future[string]{
+		low.Submit(ctx, "low"),
+		normal.Submit(ctx, "normal"),
+		high.Submit(ctx, "high"),
+	}
+	close(g.open)
+
+	var order []string
+	for range futures {
+		order = append(order, <-g.started)
+	}
+	for _, future := range append(futures, busy) {
+		if _, err := future.Await(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{"high", "normal", "low"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected the tasks to run in order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestWorkerPool_Backpressure(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 1, QueueSize: 1, SubmitTimeout: 20 * time.Millisecond})
+	wp.Start()
+
+	g := newGate()
+	normal := gatedTask[normalRequest](t, wp, g, TaskOptions{})
+	timeout := gatedTask[timeoutRequest](t, wp, g, TaskOptions{Priority: TaskPriorityHigh, Timeout: 10 * time.Millisecond})
+
+	busy := normal.Submit(ctx, "busy")
+	<-g.started
+	queued := normal.Submit(ctx, "queued")
+
+	// the normal lane is full
+	if _, err := normal.Submit(ctx, "full").Await(ctx); !errors.Is(err, domain.ErrWorkerPoolFull) {
+		t.Errorf("expected ErrWorkerPoolFull, got %v", err)
+	}
+
+	// the caller gives up before the pool does
+	short, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := normal.Submit(short, "short").Await(ctx); !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, domain.ErrWorkerPoolFull) {
+		t.Errorf("expected the deadline of the caller, got %v", err)
+	}
+
+	// queued in the high lane but out of time before the worker gets to it
+	timedOut := timeout.Submit(ctx, "timeout")
+	time.Sleep(20 * time.Millisecond)
+	close(g.open)
+
+	if _, err := timedOut.Await(ctx); !errors.Is(err, domain.ErrTaskTimeout) {
+		t.Errorf("expected ErrTaskTimeout, got %v", err)
+	}
+	for _, future := range []*Future[string]{busy, queued} {
+		if _, err := future.Await(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	wp.Stop()
+	if _, err := normal.Submit(ctx, "stopped").Await(ctx); !errors.Is(err, domain.ErrWorkerPoolStopped) {
+		t.Errorf("expected ErrWorkerPoolStopped, got %v", err)
+	}
+}
+
+func TestWorkerPool_StopConcurrently(t *testing.T) {
+	wp := NewWorkerPool(context.Background(), lggr, nil, WorkerPoolConfig{Workers: 2, QueueSize: 4})
+	wp.Start()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wp.Stop()
+		}()
+	}
+	wg.Wait()
+
+	if err := wp.Ping(context.Background()); !errors.Is(err, domain.ErrWorkerPoolStopped) {
+		t.Errorf("expected ErrWorkerPoolStopped, got %v", err)
+	}
+}
+
+func TestWorkerPool_StopWhileTasksSubmit(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 1, QueueSize: 4})
+	g := newGate()
+	next := gatedTask[normalRequest](t, wp, g, TaskOptions{})
+	// the running task submits the next step once the pool is stopping
+	chain, err := Register(wp, func(ctx context.Context, req chainRequest) (string, error) {
+		g.started <- string(req)
+		<-g.open
+		return next.Submit(ctx, normalRequest(req)).Await(ctx)
+	}, TaskOptions{})
+	if err != nil {
+		t.Fatalf("error registering the task: %v", err)
+	}
+	wp.Start()
+
+	future := chain.Submit(ctx, "chain")
+	<-g.started
+
+	stopped := make(chan struct{})
+	go func() {
+		wp.Stop()
+		close(stopped)
+	}()
+	for wp.Ping(ctx) == nil {
+		time.Sleep(time.Millisecond)
+	}
+	close(g.open)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return while a task submitted another one")
+	}
+	if _, err := future.Await(ctx); !errors.Is(err, domain.ErrWorkerPoolStopped) {
+		t.Errorf("expected the next step to be rejected with ErrWorkerPoolStopped, got %v", err)
+	}
+}
+
+func TestWorkerPool_Registry(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 1})
+	wp.Start()
+	defer wp.Stop()
+
+	// a subsystem other than the tournaments plugs its own handler in
+	type countMatches struct{ TournamentID uuid.UUID }
+	_, err := Register(wp, func(ctx context.Context, req countMatches) (int, error) {
+		return 3, nil
+	}, TaskOptions{})
+	if err != nil {
+		t.Fatalf("error registering the task: %v", err)
+	}
+
+	count, err := Submit[countMatches, int](ctx, wp, countMatches{TournamentID: uuid.New()}).Await(ctx)
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 matches, got %d, %v", count, err)
+	}
+
+	_, err = Register(wp, func(ctx context.Context, req countMatches) (int, error) {
+		return 0, nil
+	}, TaskOptions{})
+	if !errors.Is(err, ErrTaskRegistered) {
+		t.Errorf("expected ErrTaskRegistered, got %v", err)
+	}
+
+	// the same request with another response is another task
+	if _, err := Submit[countMatches, string](ctx, wp, countMatches{}).Await(ctx); !errors.Is(err, ErrTaskNotRegistered) {
+		t.Errorf("expected ErrTaskNotRegistered, got %v", err)
+	}
+}
+
+func TestWorkerPool_Panic(t *testing.T) {
+	ctx := context.Background()
+	wp := NewWorkerPool(ctx, lggr, nil, WorkerPoolConfig{Workers: 1})
+	wp.Start()
+	defer wp.Stop()
+
+	task, err := Register(wp, func(ctx context.Context, req string) (string, error) {
+		if req == "panic" {
+			panic("handler bug")
+		}
+		return req, nil
+	}, TaskOptions{})
+	if err != nil {
+		t.Fatalf("error registering the task: %v", err)
+	}
+
+	if _, err := task.Submit(ctx, "panic").Await(ctx); !errors.Is(err, ErrTaskPanicked) {
+		t.Errorf("expected ErrTaskPanicked, got %v", err)
+	}
+
+	// the only worker survived the panic
+	result, err := task.Submit(ctx, "ok").Await(ctx)
+	if err != nil || result != "ok" {
+		t.Errorf("expected the worker to keep running, got %q, %v", result, err)
+	}
+}
+
+func TestWorkerPool_Metrics(t *testing.T) {
+	ctx := context.Background()
+	registry := metrics.NewRegistry()
+	wp := NewWorkerPool(ctx, lggr, registry, WorkerPoolConfig{Workers: 1, QueueSize: 1, SubmitTimeout: 10 * time.Millisecond})
+	wp.Start()
+	defer wp.Stop()
+
+	g := newGate()
+	normal := gatedTask[normalRequest](t, wp, g, TaskOptions{})
+
+	busy := normal.Submit(ctx, "busy")
+	<-g.started
+	queued := normal.Submit(ctx, "queued")
+	full := normal.Submit(ctx, "full")
+	if _, err := full.Await(ctx); !errors.Is(err, domain.ErrWorkerPoolFull) {
+		t.Fatalf("expected ErrWorkerPoolFull, got %v", err)
+	}
+
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("error writing the metrics: %v", err)
+	}
+	for _, expected := range []string{
+		"maple_worker_pool_busy_workers 1\n",
+		`maple_worker_pool_queue_depth{lane="normal"} 1` + "\n",
+		`maple_worker_pool_rejections_total{reason="full"} 1` + "\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the metrics to contain %q, got\n%s", expected, out.String())
+		}
+	}
+
+	close(g.open)
+	for _, future := range []*Future[string]{busy, queued} {
+		if _, err := future.Await(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	// the durations are observed once the workers are done with the tasks
+	wp.Stop()
+	out.Reset()
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("error writing the metrics: %v", err)
+	}
+	if expected := `maple_worker_pool_task_duration_seconds_count{task="services.normalRequest"} 2` + "\n"; !strings.Contains(out.String(), expected) {
+		t.Errorf("expected the metrics to contain %q, got\n%s", expected, out.String())
+	}
+}
+
+func TestWorkerPool_Tracing(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := tracing.NewWriterExporter(&out)
+	if err != nil {
+		t.Fatalf("error creating the exporter: %v", err)
+	}
+	tracer := tracing.NewTracer(lggr, exporter, tracing.DefaultConfig())
+
+	wp := NewWorkerPool(context.Background(), lggr, nil, WorkerPoolConfig{Workers: 1})
+	wp.Start()
+	defer wp.Stop()
+
+	task, err := Register(wp, func(ctx context.Context, req string) (string, error) {
+		// the handler runs on a worker but in the trace of the caller
+		_, span := ports.StartSpan(ctx, "handler", ports.SpanKindInternal)
+		span.End()
+		return req, nil
+	}, TaskOptions{})
+	if err != nil {
+		t.Fatalf("error registering the task: %v", err)
+	}
+
+	ctx, caller := ports.StartSpan(ports.WithTracer(context.Background(), tracer), "caller", ports.SpanKindInternal)
+	if _, err := task.Submit(ctx, "traced").Await(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wp.Stop()
+	caller.End()
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("error exporting the spans: %v", err)
+	}
+
+	type writtenSpan struct {
+		Name        string
+		SpanContext struct{ SpanID string }
+		Parent      struct{ SpanID string }
+	}
+	spans := make(map[string]*writtenSpan)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var span writtenSpan
+		if err := json.Unmarshal([]byte(line), &span); err != nil {
+			t.Fatalf("error reading span %q: %v", line, err)
+		}
+		spans[span.Name] = &span
+	}
+
+	queue, run, handler := spans["queue string"], spans["task string"], spans["handler"]
+	if queue == nil || run == nil || handler == nil {
+		t.Fatalf("expected the queue, task and handler spans, got %v", spans)
+	}
+	if queue.Parent.SpanID != caller.SpanID() || run.Parent.SpanID != caller.SpanID() {
+		t.Errorf("expected the queue and task spans to be children of the caller, got %v and %v", queue.Parent.SpanID, run.Parent.SpanID)
+	}
+	if handler.Parent.SpanID != run.SpanContext.SpanID {
+		t.Errorf("expected the handler span to be a child of the task span, got %v", handler.Parent.SpanID)
+	}
+}
@@ -0,0 +1,170 @@
+// Package templates renders the emails sent to the players. Every notification kind has one
+// emails/<locale>/<kind>.tmpl file per language defining a "subject" and a "text" template,
+// footer.tmpl is shared by the emails of the language
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+)
+
+//go:embed emails/*/*.tmpl
+var emailFiles embed.FS
+
+// dateLayouts are how the dates are written in each language
+var dateLayouts = map[i18n.Locale]string{
+	i18n.LocaleEnglish: "Mon Jan 2, 2006 15:04 MST",
+	i18n.LocaleSpanish: "02/01/2006 15:04 MST",
+	i18n.LocaleCatalan: "02/01/2006 15:04 MST",
+}
+
+// EmailData is what the templates are rendered with
+type EmailData struct {
+	Player         domain.Player
+	Tournament     domain.Tournament
+	Challenge      domain.Challenge // when the email is about a casual game
+	Opponent       domain.Player    // in the game the email is about
+	Round          int              // 1 based, 0 when the email is not about a round
+	StartsAt       time.Time        // of the round, or of the window of the challenge
+	UnsubscribeURL string
+	Timezone       *time.Location // the dates are written in, UTC when nil
+}
+
+// Emails holds the parsed templates of every language
+type Emails struct {
+	templates map[i18n.Locale]map[domain.NotificationKind]*template.Template
+	fallback  i18n.Locale
+}
+
+var (
+	defaultEmails     *Emails
+	defaultEmailsOnce sync.Once
+)
+
+// Default returns the built in templates with i18n.DefaultLocale as the fallback
+func Default() *Emails {
+	defaultEmailsOnce.Do(func() {
+		e, err := NewEmails(emailFiles, i18n.DefaultLocale)
+		if err != nil {
+			panic(fmt.Sprintf("templates: parsing the built in emails: %v", err))
+		}
+		defaultEmails = e
+	})
+	return defaultEmails
+}
+
+// NewEmails parses the emails/<locale>/<kind>.tmpl files of fsys, the fallback language must
+// have a template for every notification kind
+func NewEmails(fsys fs.FS, fallback i18n.Locale) (*Emails, error) {
+	dirs, err := fs.ReadDir(fsys, "emails")
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Emails{
+		templates: make(map[i18n.Locale]map[domain.NotificationKind]*template.Template),
+		fallback:  fallback,
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		locale := i18n.Locale(dir.Name())
+		e.templates[locale] = make(map[domain.NotificationKind]*template.Template)
+
+		for _, kind := range domain.NotificationKinds {
+			name := path.Join("emails", dir.Name(), string(kind)+".tmpl")
+			if _, err := fs.Stat(fsys, name); err != nil {
+				continue
+			}
+
+			patterns := []string{name}
+			footer := path.Join("emails", dir.Name(), "footer.tmpl")
+			if _, err := fs.Stat(fsys, footer); err == nil {
+				patterns = append(patterns, footer)
+			}
+
+			tmpl, err := template.New(string(kind)).Funcs(funcs(locale)).ParseFS(fsys, patterns...)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", name, err)
+			}
+			for _, required := range []string{"subject", "text"} {
+				if tmpl.Lookup(required) == nil {
+					return nil, fmt.Errorf("%s does not define %q", name, required)
+				}
+			}
+			e.templates[locale][kind] = tmpl
+		}
+	}
+
+	for _, kind := range domain.NotificationKinds {
+		if e.templates[fallback][kind] == nil {
+			return nil, fmt.Errorf("the fallback locale %q has no %s email", fallback, kind)
+		}
+	}
+
+	return e, nil
+}
+
+// Render returns the subject and the text of the email in the locale, in the fallback
+// language when there is no template for the locale
+func (e *Emails) Render(locale i18n.Locale, kind domain.NotificationKind, data EmailData) (subject, text string, err error) {
+	tmpl, ok := e.templates[locale][kind]
+	if !ok {
+		tmpl, ok = e.templates[e.fallback][kind]
+		if !ok {
+			return "", "", fmt.Errorf("no %s email template", kind)
+		}
+	}
+
+	// the date helper needs the timezone of the data, it is bound on a copy
+	tmpl, err = tmpl.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	tz := data.Timezone
+	if tz == nil {
+		tz = time.UTC
+	}
+	tmpl.Funcs(template.FuncMap{
+		"datetime": func(t time.Time) string { return t.In(tz).Format(dateLayout(locale)) },
+	})
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	// a subject is a single line header
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "text", data); err != nil {
+		return "", "", err
+	}
+
+	return subject, strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+func funcs(locale i18n.Locale) template.FuncMap {
+	return template.FuncMap{
+		"datetime": func(t time.Time) string { return t.UTC().Format(dateLayout(locale)) },
+		"inc":      func(i int) int { return i + 1 },
+	}
+}
+
+func dateLayout(locale i18n.Locale) string {
+	if layout, ok := dateLayouts[locale]; ok {
+		return layout
+	}
+	return dateLayouts[i18n.DefaultLocale]
+}
@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/i18n"
+)
+
+func testData() EmailData {
+	start := time.Date(2026, 11, 7, 16, 30, 0, 0, time.UTC)
+	return EmailData{
+		Player: domain.Player{PublicID: uuid.MustParse("5f0c7a4e-6a39-4d51-9f4b-0d3c2a1b8e11"), FirstName: "Núria"},
+		Tournament: domain.Tournament{
+			Name:        "Open de Sants",
+			Location:    domain.Location{Name: "Casal de Sants", Address: "Carrer de Sants 79", City: "Barcelona"},
+			TimeControl: domain.TimeControl{Periods: []domain.TimeControlPeriod{{Base: 90 * time.Minute, Increment: 30 * time.Second}}},
+			Schedule:    []domain.Schedule{{StartTime: start}, {StartTime: start.Add(24 * time.Hour)}},
+		},
+		Challenge: domain.Challenge{
+			ChallengerID: uuid.MustParse("5f0c7a4e-6a39-4d51-9f4b-0d3c2a1b8e11"),
+			Location:     domain.Location{Name: "Bar Velódromo", City: "Barcelona"},
+			RadiusKm:     2,
+			StartsAt:     start,
+			EndsAt:       start.Add(3 * time.Hour),
+			TimeControl:  domain.TimeControl{Periods: []domain.TimeControlPeriod{{Base: 10 * time.Minute, Increment: 5 * time.Second}}},
+			Rated:        true,
+		},
+		Opponent:       domain.Player{FirstName: "Jordi", LastName: "Puig"},
+		Round:          2,
+		StartsAt:       start.Add(24 * time.Hour),
+		UnsubscribeURL: "https://maple.example/v1/notification/unsubscribe/token",
+	}
+}
+
+func TestEmails_EveryKindInEveryLocale(t *testing.T) {
+	emails := Default()
+
+	for _, locale := range i18n.Default().Locales() {
+		for _, kind := range domain.NotificationKinds {
+			_, ok := emails.templates[locale][kind]
+			assert.True(t, ok, "%s has no %s email", locale, kind)
+
+			subject, text, err := emails.Render(locale, kind, testData())
+			require.NoError(t, err, "%s %s", locale, kind)
+			// the subject says what the email is about
+			about := "Open de Sants"
+			if kind == domain.NotificationChallengeAccepted {
+				about = "Jordi Puig"
+			}
+			assert.Contains(t, subject, about)
+			assert.NotContains(t, subject, "\n")
+			assert.Contains(t, text, "Núria")
+			assert.Contains(t, text, testData().UnsubscribeURL)
+			assert.NotContains(t, text, "<no value>")
+		}
+	}
+}
+
+func TestEmails_Render(t *testing.T) {
+	emails := Default()
+
+	t.Run("should write the schedule and venue in the language", func(t *testing.T) {
+		_, text, err := emails.Render(i18n.LocaleCatalan, domain.NotificationRegistrationConfirmed, testData())
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "Lloc: Casal de Sants, Carrer de Sants 79, Barcelona")
+		assert.Contains(t, text, "Ronda 2: 08/11/2026 16:30 UTC")
+		assert.Contains(t, text, "Ritme de joc: 90+30")
+	})
+
+	t.Run("should write the dates in the timezone", func(t *testing.T) {
+		data := testData()
+		madrid, err := time.LoadLocation("Europe/Madrid")
+		require.NoError(t, err)
+		data.Timezone = madrid
+
+		_, text, err := emails.Render(i18n.LocaleSpanish, domain.NotificationRoundReminder, data)
+		require.NoError(t, err)
+		assert.Contains(t, text, "08/11/2026 17:30 CET")
+	})
+
+	t.Run("should tell each side of a challenge who they play", func(t *testing.T) {
+		subject, text, err := emails.Render(i18n.LocaleCatalan, domain.NotificationChallengeAccepted, testData())
+		require.NoError(t, err)
+		assert.Equal(t, "Tens partida contra Jordi Puig", subject)
+		assert.Contains(t, text, "Jordi Puig ha acceptat el teu desafiament.")
+		assert.Contains(t, text, "Ritme de joc: 10+5, valorada")
+		assert.Contains(t, text, "Quan: entre el 07/11/2026 16:30 UTC i el 07/11/2026 19:30 UTC")
+		assert.Contains(t, text, "On: Bar Velódromo, Barcelona (a menys de 2 km)")
+
+		data := testData()
+		data.Challenge.ChallengerID = uuid.New()
+		_, text, err = emails.Render(i18n.LocaleEnglish, domain.NotificationChallengeAccepted, data)
+		require.NoError(t, err)
+		assert.Contains(t, text, "You accepted the challenge of Jordi Puig.")
+	})
+
+	t.Run("should fall back to the default language", func(t *testing.T) {
+		subject, _, err := emails.Render("pt", domain.NotificationRoundReminder, testData())
+		require.NoError(t, err)
+		assert.Equal(t, "Round 2 of Open de Sants starts soon", subject)
+	})
+}
+
+func TestNewEmails(t *testing.T) {
+	t.Run("should fail when the fallback misses a kind", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"emails/en/round_reminder.tmpl": {Data: []byte(`{{define "subject"}}s{{end}}{{define "text"}}t{{end}}`)},
+		}
+
+		_, err := NewEmails(fsys, i18n.LocaleEnglish)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail when a template has no subject", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"emails/en/round_reminder.tmpl": {Data: []byte(`{{define "text"}}t{{end}}`)},
+		}
+
+		_, err := NewEmails(fsys, i18n.LocaleEnglish)
+		assert.Error(t, err)
+	})
+}
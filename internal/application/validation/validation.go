@@ -0,0 +1,136 @@
+// Package validation holds the field errors returned by the commands. Every error carries
+// a stable code the clients can rely on, the message is looked up in the i18n catalogs
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ctfrancia/maple/internal/core/i18n"
+)
+
+// Codes of the field errors, they are also the keys of the i18n catalogs
+const (
+	CodeRequired               = "required"
+	CodeNotNil                 = "not_nil"
+	CodeNotEmpty               = "not_empty"
+	CodeTooShort               = "too_short"
+	CodeTooLong                = "too_long"
+	CodeOneOf                  = "one_of"
+	CodeBetween                = "between"
+	CodePositive               = "positive"
+	CodeDifferentPlayer        = "different_player"
+	CodeInvalidFormat          = "invalid_format"
+	CodeInvalidTimeControl     = "invalid_time_control"
+	CodeInvalidEmail           = "invalid_email"
+	CodeInvalidClubAffiliation = "invalid_club_affiliation"
+	CodeInvalidURL             = "invalid_url"
+	CodePublicURL              = "public_url"
+	CodeLocalPath              = "local_path"
+	CodeAfter                  = "after"
+)
+
+// FieldError is why a field is not valid
+type FieldError struct {
+	Code   string
+	Params map[string]any
+}
+
+// Message returns the message of the error in the locale
+func (fe FieldError) Message(locale i18n.Locale) string {
+	return i18n.Default().Translate(locale, fe.Code, fe.Params)
+}
+
+// String returns the message in the default locale
+func (fe FieldError) String() string {
+	return fe.Message(i18n.DefaultLocale)
+}
+
+// Errors maps the invalid fields to their error
+type Errors map[string]FieldError
+
+// Error represents multiple field validation errors
+type Error struct {
+	Errors Errors `json:"errors"`
+}
+
+func (ve Error) Error() string {
+	if len(ve.Errors) == 0 {
+		return "validation failed"
+	}
+
+	var messages []string
+	for field, fe := range ve.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, fe))
+	}
+	sort.Strings(messages)
+
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, ", "))
+}
+
+// AsError reports whether err is a validation error, returning it if so
+func AsError(err error) (*Error, bool) {
+	var ve Error
+	if errors.As(err, &ve) {
+		return &ve, true
+	}
+	return nil, false
+}
+
+func Required() FieldError { return FieldError{Code: CodeRequired} }
+
+func NotNil() FieldError { return FieldError{Code: CodeNotNil} }
+
+func NotEmpty() FieldError { return FieldError{Code: CodeNotEmpty} }
+
+func TooShort(min int) FieldError {
+	return FieldError{Code: CodeTooShort, Params: map[string]any{"min": min}}
+}
+
+func TooLong(max int) FieldError {
+	return FieldError{Code: CodeTooLong, Params: map[string]any{"max": max}}
+}
+
+// OneOf is for values outside of an enum, the values are listed as they are sent over the api
+func OneOf(values ...string) FieldError {
+	return FieldError{Code: CodeOneOf, Params: map[string]any{"values": values}}
+}
+
+func Between(min, max int) FieldError {
+	return FieldError{Code: CodeBetween, Params: map[string]any{"min": min, "max": max}}
+}
+
+func Positive() FieldError { return FieldError{Code: CodePositive} }
+
+func DifferentPlayer() FieldError { return FieldError{Code: CodeDifferentPlayer} }
+
+// InvalidFormat is for values that do not follow a layout such as YYYY-MM
+func InvalidFormat(format string) FieldError {
+	return FieldError{Code: CodeInvalidFormat, Params: map[string]any{"format": format}}
+}
+
+func InvalidTimeControl() FieldError {
+	return FieldError{Code: CodeInvalidTimeControl, Params: map[string]any{"examples": []string{"90+30", "90/40+30, 30+30"}}}
+}
+
+func InvalidEmail() FieldError { return FieldError{Code: CodeInvalidEmail} }
+
+func InvalidClubAffiliation() FieldError { return FieldError{Code: CodeInvalidClubAffiliation} }
+
+// InvalidURL is for urls that are not absolute or whose scheme is not one of schemes
+func InvalidURL(schemes ...string) FieldError {
+	return FieldError{Code: CodeInvalidURL, Params: map[string]any{"schemes": schemes}}
+}
+
+// PublicURL is for urls that point at this server or at its private network
+func PublicURL() FieldError { return FieldError{Code: CodePublicURL} }
+
+// LocalPath is for the paths of the imports that are absolute or lead out of the import directory
+func LocalPath() FieldError { return FieldError{Code: CodeLocalPath} }
+
+// After is for times that must come after the one of another field
+func After(field string) FieldError {
+	return FieldError{Code: CodeAfter, Params: map[string]any{"field": field}}
+}
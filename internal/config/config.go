@@ -0,0 +1,364 @@
+// Package config is the configuration of maple. It is loaded from the defaults, the profile of
+// the environment, a yaml or toml file, the environment variables and the flags, each one
+// taking over the ones before it
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/mail"
+	"net/url"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// Config - the yaml and toml tags are the keys of the file and, joined by dots, the names of
+// the flags. The env tags keep the names of the variables maple has always read. The secret
+// ones are masked when the config is printed
+type Config struct {
+	Env         string            `yaml:"env" toml:"env" env:"ENV" usage:"profile of the environment: dev, test or prod"`
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	Workers     WorkersConfig     `yaml:"workers" toml:"workers"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Mail        MailConfig        `yaml:"mail" toml:"mail"`
+	Geocoding   GeocodingConfig   `yaml:"geocoding" toml:"geocoding"`
+	Imports     ImportsConfig     `yaml:"imports" toml:"imports"`
+	Moderation  ModerationConfig  `yaml:"moderation" toml:"moderation"`
+	Rating      RatingConfig      `yaml:"rating" toml:"rating"`
+	Security    SecurityConfig    `yaml:"security" toml:"security"`
+	Admin       AdminConfig       `yaml:"admin" toml:"admin"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+}
+
+type ServerConfig struct {
+	ListenAddress   string        `yaml:"listen_address" toml:"listen_address" env:"LISTEN_ADDRESS" usage:"address the api listens on"`
+	PublicURL       string        `yaml:"public_url" toml:"public_url" env:"PUBLIC_URL" usage:"url the api is reached at, for the links of the emails"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"READ_TIMEOUT" usage:"longest time to read a request"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"WRITE_TIMEOUT" usage:"longest time to write a response, the live streams lift it"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"IDLE_TIMEOUT" usage:"longest time a keep-alive connection waits for the next request"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SIG_SERVICE_TIMEOUT" usage:"time the requests in flight are given to finish on shutdown"`
+}
+
+type LogConfig struct {
+	Level            string `yaml:"level" toml:"level" env:"LOG_LEVEL" usage:"debug, info, warn or error"`
+	SampleInitial    int    `yaml:"sample_initial" toml:"sample_initial" env:"LOG_SAMPLE_INITIAL" usage:"lines of the same message logged every second before sampling, 0 is the default of the profile"`
+	SampleThereafter int    `yaml:"sample_thereafter" toml:"sample_thereafter" env:"LOG_SAMPLE_THEREAFTER" usage:"one of every this many lines is logged once sampling, negative logs every line"`
+}
+
+type WorkersConfig struct {
+	Count         int           `yaml:"count" toml:"count" env:"WORKER_COUNT" usage:"workers of the tournament worker pool"`
+	QueueSize     int           `yaml:"queue_size" toml:"queue_size" env:"WORKER_QUEUE_SIZE" usage:"tasks each priority lane holds"`
+	SubmitTimeout time.Duration `yaml:"submit_timeout" toml:"submit_timeout" env:"WORKER_SUBMIT_TIMEOUT" usage:"longest time a task waits for room in its lane"`
+	TaskTimeout   time.Duration `yaml:"task_timeout" toml:"task_timeout" env:"WORKER_TASK_TIMEOUT" usage:"longest time a task runs"`
+}
+
+type TracingConfig struct {
+	OTLPEndpoint string            `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" usage:"collector the spans are exported to"`
+	OTLPHeaders  map[string]string `yaml:"otlp_headers" toml:"otlp_headers" env:"OTEL_EXPORTER_OTLP_HEADERS" secret:"true" usage:"headers of the exports, key=value pairs separated by commas"`
+	ServiceName  string            `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" usage:"service the spans are reported as"`
+	SampleRatio  float64           `yaml:"sample_ratio" toml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG" usage:"ratio of the traces started by maple that are sampled"`
+	File         string            `yaml:"file" toml:"file" env:"TRACES_FILE" usage:"file the spans are written to as json lines, - for stdout"`
+}
+
+type MailConfig struct {
+	From string     `yaml:"from" toml:"from" env:"MAIL_FROM" usage:"sender of the emails"`
+	Dir  string     `yaml:"dir" toml:"dir" env:"MAIL_DIR" usage:"directory the emails are written to when there is no smtp server"`
+	SMTP SMTPConfig `yaml:"smtp" toml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string        `yaml:"host" toml:"host" env:"SMTP_HOST" usage:"smtp server the emails are relayed through"`
+	Port     int           `yaml:"port" toml:"port" env:"SMTP_PORT" usage:"port of the smtp server"`
+	Username string        `yaml:"username" toml:"username" env:"SMTP_USERNAME" usage:"user of the smtp server, no authentication when empty"`
+	Password string        `yaml:"password" toml:"password" env:"SMTP_PASSWORD" secret:"true" usage:"password of the smtp user"`
+	Timeout  time.Duration `yaml:"timeout" toml:"timeout" env:"SMTP_TIMEOUT" usage:"longest time to send an email"`
+}
+
+type GeocodingConfig struct {
+	GeonamesDir     string `yaml:"geonames_dir" toml:"geonames_dir" env:"GEONAMES_DIR" usage:"directory of the geonames dumps the venues are geocoded with offline"`
+	GeonamesCountry string `yaml:"geonames_country" toml:"geonames_country" env:"GEONAMES_COUNTRY" usage:"country of the geonames dumps"`
+	NominatimURL    string `yaml:"nominatim_url" toml:"nominatim_url" env:"NOMINATIM_URL" usage:"nominatim instance the venues are geocoded with"`
+}
+
+// ImportsConfig - the imports only read the files of Dir, the admin puts them there first
+type ImportsConfig struct {
+	Dir string `yaml:"dir" toml:"dir" env:"IMPORT_DIR" usage:"directory the fide rating lists and the chat exports are imported from, the paths of the imports are relative to it and nothing is imported without one"`
+}
+
+type ModerationConfig struct {
+	Approval domain.ApprovalMode `yaml:"approval" toml:"approval" env:"MODERATION_APPROVAL" usage:"listings held for approval: off, new or all"`
+}
+
+type RatingConfig struct {
+	Algorithm domain.RatingAlgorithm `yaml:"algorithm" toml:"algorithm" env:"RATING_ALGORITHM" usage:"algorithm of the maple rating: elo or glicko2"`
+	Elo       EloConfig              `yaml:"elo" toml:"elo"`
+	Glicko2   Glicko2Config          `yaml:"glicko2" toml:"glicko2"`
+}
+
+type EloConfig struct {
+	InitialRating    float64 `yaml:"initial_rating" toml:"initial_rating" env:"ELO_INITIAL_RATING" usage:"rating of a player before their first game"`
+	ProvisionalGames int     `yaml:"provisional_games" toml:"provisional_games" env:"ELO_PROVISIONAL_GAMES" usage:"games a player is provisional for"`
+	ProvisionalK     float64 `yaml:"provisional_k" toml:"provisional_k" env:"ELO_PROVISIONAL_K" usage:"k-factor of the provisional players"`
+	K                float64 `yaml:"k" toml:"k" env:"ELO_K" usage:"k-factor of the established players"`
+	MasterK          float64 `yaml:"master_k" toml:"master_k" env:"ELO_MASTER_K" usage:"k-factor once a player reached the master threshold"`
+	MasterThreshold  float64 `yaml:"master_threshold" toml:"master_threshold" env:"ELO_MASTER_THRESHOLD" usage:"rating the master k-factor starts at"`
+	Floor            float64 `yaml:"floor" toml:"floor" env:"ELO_FLOOR" usage:"rating no player drops below"`
+}
+
+// Glicko2Config - see the paper of Mark Glickman on Glicko-2 for choosing them
+type Glicko2Config struct {
+	InitialRating        float64 `yaml:"initial_rating" toml:"initial_rating" env:"GLICKO2_INITIAL_RATING" usage:"rating of a player before their first game"`
+	InitialDeviation     float64 `yaml:"initial_deviation" toml:"initial_deviation" env:"GLICKO2_INITIAL_DEVIATION" usage:"rating deviation of a player before their first game"`
+	InitialVolatility    float64 `yaml:"initial_volatility" toml:"initial_volatility" env:"GLICKO2_INITIAL_VOLATILITY" usage:"volatility of a player before their first game"`
+	Tau                  float64 `yaml:"tau" toml:"tau" env:"GLICKO2_TAU" usage:"constraint on the change of the volatility, 0.3 to 1.2"`
+	ProvisionalDeviation float64 `yaml:"provisional_deviation" toml:"provisional_deviation" env:"GLICKO2_PROVISIONAL_DEVIATION" usage:"a player is provisional while their deviation is above it"`
+}
+
+type SecurityConfig struct {
+	Argon2 Argon2Config `yaml:"argon2" toml:"argon2"`
+}
+
+// Argon2Config are the parameters the passwords are hashed with, see RFC 9106 for choosing them
+type Argon2Config struct {
+	Memory      uint32 `yaml:"memory" toml:"memory" env:"ARGON2_MEMORY" usage:"memory of the hash in KiB"`
+	Iterations  uint32 `yaml:"iterations" toml:"iterations" env:"ARGON2_ITERATIONS" usage:"passes over the memory"`
+	Parallelism uint8  `yaml:"parallelism" toml:"parallelism" env:"ARGON2_PARALLELISM" usage:"threads of the hash"`
+	SaltLength  uint32 `yaml:"salt_length" toml:"salt_length" env:"ARGON2_SALT_LENGTH" usage:"bytes of the salt"`
+	KeyLength   uint32 `yaml:"key_length" toml:"key_length" env:"ARGON2_KEY_LENGTH" usage:"bytes of the hash"`
+}
+
+func (ac Argon2Config) Params() domain.A2params {
+	return domain.A2params{
+		Memory:      ac.Memory,
+		Iterations:  ac.Iterations,
+		Parallelism: ac.Parallelism,
+		SaltLength:  ac.SaltLength,
+		KeyLength:   ac.KeyLength,
+	}
+}
+
+type AdminConfig struct {
+	Token string `yaml:"token" toml:"token" env:"ADMIN_TOKEN" secret:"true" usage:"bearer token of the admin routes, they are not mounted without one"`
+}
+
+// AuthConfig are the bearer tokens of the api consumers, the moderators, the players and the
+// arbiters, who they are is never taken from the request
+type AuthConfig struct {
+	ConsumerTokens  map[string]string `yaml:"consumer_tokens" toml:"consumer_tokens" env:"CONSUMER_TOKENS" secret:"true" usage:"bearer tokens of the api consumers, consumer_id=token pairs separated by commas"`
+	ModeratorTokens map[string]string `yaml:"moderator_tokens" toml:"moderator_tokens" env:"MODERATOR_TOKENS" secret:"true" usage:"bearer tokens of the moderators, name=token pairs separated by commas, the admin token moderates too"`
+	PlayerTokens    map[string]string `yaml:"player_tokens" toml:"player_tokens" env:"PLAYER_TOKENS" secret:"true" usage:"bearer tokens of the players, player_id=token pairs separated by commas"`
+	ArbiterTokens   map[string]string `yaml:"arbiter_tokens" toml:"arbiter_tokens" env:"ARBITER_TOKENS" secret:"true" usage:"bearer tokens of the arbiters and the boards relaying the games, name=token pairs separated by commas"`
+}
+
+// IdempotencyConfig - the keys are kept in memory without a database, a retry that reaches
+// another instance runs again
+type IdempotencyConfig struct {
+	TTL       time.Duration `yaml:"ttl" toml:"ttl" env:"IDEMPOTENCY_TTL" usage:"time the response of an Idempotency-Key is replayed to its retries"`
+	SQLDriver string        `yaml:"sql_driver" toml:"sql_driver" env:"IDEMPOTENCY_SQL_DRIVER" usage:"database/sql driver of the database the keys are shared in: pgx"`
+	SQLDSN    string        `yaml:"sql_dsn" toml:"sql_dsn" env:"IDEMPOTENCY_SQL_DSN" secret:"true" usage:"data source name of the database the keys are shared in"`
+}
+
+// Defaults are the values every profile starts from
+func Defaults() Config {
+	return Config{
+		Env: "dev",
+		Server: ServerConfig{
+			ListenAddress:   ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Workers: WorkersConfig{
+			Count:         runtime.NumCPU() * 2,
+			QueueSize:     256,
+			SubmitTimeout: 2 * time.Second,
+			TaskTimeout:   10 * time.Second,
+		},
+		Tracing: TracingConfig{
+			ServiceName: "maple",
+			SampleRatio: 1,
+		},
+		Mail: MailConfig{
+			From: "Maple <no-reply@maple.local>",
+			SMTP: SMTPConfig{
+				Port:    587,
+				Timeout: 30 * time.Second,
+			},
+		},
+		Geocoding: GeocodingConfig{
+			GeonamesCountry: "ES",
+		},
+		Moderation: ModerationConfig{
+			Approval: domain.ApprovalOff,
+		},
+		Rating: RatingConfig{
+			Algorithm: domain.RatingAlgorithmElo,
+			Elo: EloConfig{
+				InitialRating:    1500,
+				ProvisionalGames: 30,
+				ProvisionalK:     40,
+				K:                20,
+				MasterK:          10,
+				MasterThreshold:  2400,
+				Floor:            100,
+			},
+			Glicko2: Glicko2Config{
+				InitialRating:        1500,
+				InitialDeviation:     350,
+				InitialVolatility:    0.06,
+				Tau:                  0.5,
+				ProvisionalDeviation: 110,
+			},
+		},
+		Security: SecurityConfig{
+			Argon2: Argon2Config{
+				Memory:      64 * 1024,
+				Iterations:  3,
+				Parallelism: 2,
+				SaltLength:  16,
+				KeyLength:   32,
+			},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+	}
+}
+
+// profiles adjust the defaults to the environment before the file is read
+var profiles = map[string]func(c *Config){
+	"dev": func(c *Config) {
+		c.Log.Level = "debug"
+	},
+	"test": func(c *Config) {
+		c.Log.Level = "debug"
+		c.Security.Argon2.Memory = 8 * 1024
+		c.Security.Argon2.Iterations = 1
+	},
+	"prod": func(c *Config) {
+		c.Log.SampleInitial = 100
+		c.Log.SampleThereafter = 100
+	},
+}
+
+// Validate reports every invalid value at once, each error names the key of the file
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	checkURL := func(value, key string) {
+		if value == "" {
+			return
+		}
+		u, err := url.Parse(value)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", key, "%q is not an http url", value)
+	}
+
+	_, ok := profiles[c.Env]
+	check(ok, "env", "%q is not one of dev, test or prod", c.Env)
+
+	_, _, err := net.SplitHostPort(c.Server.ListenAddress)
+	check(err == nil, "server.listen_address", "%q is not a host:port address", c.Server.ListenAddress)
+	checkURL(c.Server.PublicURL, "server.public_url")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout", "must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout", "must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout", "must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "log.level", "%q is not one of debug, info, warn or error", c.Log.Level)
+	}
+
+	check(c.Workers.Count > 0, "workers.count", "must be positive")
+	check(c.Workers.QueueSize > 0, "workers.queue_size", "must be positive")
+	check(c.Workers.SubmitTimeout > 0, "workers.submit_timeout", "must be positive")
+	check(c.Workers.TaskTimeout > 0, "workers.task_timeout", "must be positive")
+
+	checkURL(c.Tracing.OTLPEndpoint, "tracing.otlp_endpoint")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+
+	_, err = mail.ParseAddress(c.Mail.From)
+	check(err == nil, "mail.from", "%q is not an email address", c.Mail.From)
+	if c.Mail.SMTP.Host != "" {
+		check(c.Mail.SMTP.Port > 0 && c.Mail.SMTP.Port < 65536, "mail.smtp.port", "%d is not a port", c.Mail.SMTP.Port)
+		check(c.Mail.SMTP.Timeout > 0, "mail.smtp.timeout", "must be positive")
+	}
+	check(c.Mail.SMTP.Password == "" || c.Mail.SMTP.Username != "", "mail.smtp.username", "is required with a password")
+
+	check(len(c.Geocoding.GeonamesCountry) == 2, "geocoding.geonames_country", "%q is not a two letter country code", c.Geocoding.GeonamesCountry)
+	checkURL(c.Geocoding.NominatimURL, "geocoding.nominatim_url")
+
+	check(c.Moderation.Approval.Valid(), "moderation.approval", "%q is not one of off, new or all", c.Moderation.Approval)
+
+	switch c.Rating.Algorithm {
+	case domain.RatingAlgorithmElo, domain.RatingAlgorithmGlicko2:
+	default:
+		check(false, "rating.algorithm", "%q is not one of elo or glicko2", c.Rating.Algorithm)
+	}
+	elo := c.Rating.Elo
+	check(elo.InitialRating >= elo.Floor, "rating.elo.initial_rating", "must be at least the floor")
+	check(elo.ProvisionalGames >= 0, "rating.elo.provisional_games", "must not be negative")
+	check(elo.ProvisionalK > 0, "rating.elo.provisional_k", "must be positive")
+	check(elo.K > 0, "rating.elo.k", "must be positive")
+	check(elo.MasterK > 0, "rating.elo.master_k", "must be positive")
+	check(elo.MasterThreshold > elo.Floor, "rating.elo.master_threshold", "must be above the floor")
+	check(elo.Floor >= 0, "rating.elo.floor", "must not be negative")
+	g2 := c.Rating.Glicko2
+	check(g2.InitialRating > 0, "rating.glicko2.initial_rating", "must be positive")
+	check(g2.InitialDeviation > 0, "rating.glicko2.initial_deviation", "must be positive")
+	check(g2.InitialVolatility > 0, "rating.glicko2.initial_volatility", "must be positive")
+	check(g2.Tau >= 0.3 && g2.Tau <= 1.2, "rating.glicko2.tau", "must be between 0.3 and 1.2")
+	check(g2.ProvisionalDeviation > 0 && g2.ProvisionalDeviation <= g2.InitialDeviation, "rating.glicko2.provisional_deviation", "must be positive and at most the initial deviation")
+
+	a2 := c.Security.Argon2
+	check(a2.Iterations >= 1, "security.argon2.iterations", "must be at least 1")
+	check(a2.Parallelism >= 1, "security.argon2.parallelism", "must be at least 1")
+	// argon2 needs 8 KiB of memory per thread
+	check(a2.Memory >= 8*uint32(a2.Parallelism), "security.argon2.memory", "must be at least 8 KiB per thread")
+	check(a2.SaltLength >= 8, "security.argon2.salt_length", "must be at least 8 bytes")
+	check(a2.KeyLength >= 16, "security.argon2.key_length", "must be at least 16 bytes")
+
+	check(c.Idempotency.TTL > 0, "idempotency.ttl", "must be positive")
+	check(c.Idempotency.SQLDriver == "" || c.Idempotency.SQLDSN != "", "idempotency.sql_dsn", "is required with a driver")
+	check(c.Idempotency.SQLDSN == "" || c.Idempotency.SQLDriver != "", "idempotency.sql_driver", "is required with a data source name")
+
+	check(c.Env != "prod" || c.Admin.Token == "" || len(c.Admin.Token) >= 32, "admin.token", "must be at least 32 characters in prod")
+	tokens := map[string]bool{c.Admin.Token: c.Admin.Token != ""}
+	for _, section := range []struct {
+		key    string
+		tokens map[string]string
+	}{{"consumer_tokens", c.Auth.ConsumerTokens}, {"moderator_tokens", c.Auth.ModeratorTokens}, {"player_tokens", c.Auth.PlayerTokens}, {"arbiter_tokens", c.Auth.ArbiterTokens}} {
+		for _, name := range slices.Sorted(maps.Keys(section.tokens)) {
+			token := section.tokens[name]
+			key := "auth." + section.key + "." + name
+			check(token != "", key, "is empty")
+			check(c.Env != "prod" || len(token) >= 32, key, "must be at least 32 characters in prod")
+			check(token == "" || !tokens[token], key, "is the token of someone else")
+			tokens[token] = true
+		}
+	}
+	for id := range c.Auth.PlayerTokens {
+		playerID, err := uuid.Parse(id)
+		check(err == nil && playerID.String() == id, "auth.player_tokens."+id, "is not the public id of a player")
+	}
+
+	return errors.Join(errs...)
+}
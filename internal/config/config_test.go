@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupOf(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const yamlConfig = `
+env: prod
+server:
+  listen_address: ":9000"
+  read_timeout: 20s
+workers:
+  count: 8
+tracing:
+  otlp_headers:
+    authorization: Bearer abc
+profiles:
+  prod:
+    server:
+      read_timeout: 30s
+    log:
+      level: warn
+  dev:
+    workers:
+      count: 1
+`
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := Load(nil, lookupOf(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "dev", c.Env)
+	assert.Equal(t, ":8080", c.Server.ListenAddress)
+	assert.Equal(t, 15*time.Second, c.Server.WriteTimeout)
+	// of the dev profile
+	assert.Equal(t, "debug", c.Log.Level)
+	assert.Equal(t, domain.RatingAlgorithmElo, c.Rating.Algorithm)
+	assert.Equal(t, uint32(64*1024), c.Security.Argon2.Params().Memory)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "maple.yaml", yamlConfig)
+
+	t.Run("the file and the section of its profile over the defaults", func(t *testing.T) {
+		c, err := Load([]string{"-config", path}, lookupOf(nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, "prod", c.Env)
+		assert.Equal(t, ":9000", c.Server.ListenAddress)
+		assert.Equal(t, 30*time.Second, c.Server.ReadTimeout)
+		assert.Equal(t, "warn", c.Log.Level)
+		assert.Equal(t, 8, c.Workers.Count)
+		assert.Equal(t, map[string]string{"authorization": "Bearer abc"}, c.Tracing.OTLPHeaders)
+		// of the prod profile
+		assert.Equal(t, 100, c.Log.SampleInitial)
+	})
+
+	t.Run("the variables over the file, the profile follows ENV", func(t *testing.T) {
+		c, err := Load(nil, lookupOf(map[string]string{
+			FileEnv:                       path,
+			"ENV":                         "dev",
+			"LISTEN_ADDRESS":              ":9100",
+			"OTEL_EXPORTER_OTLP_HEADERS":  "x-api-key=k1, x-tenant=maple",
+			"MODERATION_APPROVAL":         "all",
+			"WORKER_TASK_TIMEOUT":         "",
+			"OTEL_TRACES_SAMPLER_ARG":     "0.25",
+			"ARGON2_PARALLELISM":          "4",
+			"SIG_SERVICE_TIMEOUT":         "9s",
+			"UNRELATED_VARIABLE_IGNORED":  "x",
+			"SMTP_HOST":                   "smtp.maple.example",
+			"SMTP_PORT":                   "2525",
+			"WORKER_SUBMIT_TIMEOUT":       "1s",
+			"GEONAMES_COUNTRY":            "PT",
+			"RATING_ALGORITHM":            "glicko2",
+			"GLICKO2_TAU":                 "0.75",
+			"ADMIN_TOKEN":                 "s3cret",
+			"CONSUMER_TOKENS":             "club=t0ken",
+			"LOG_SAMPLE_THEREAFTER":       "-1",
+			"OTEL_SERVICE_NAME":           "maple-eu",
+			"TRACES_FILE":                 "-",
+			"MAIL_FROM":                   "Maple <maple@maple.example>",
+			"PUBLIC_URL":                  "https://maple.example",
+			"NOMINATIM_URL":               "https://nominatim.openstreetmap.org",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "dev", c.Env)
+		assert.Equal(t, ":9100", c.Server.ListenAddress)
+		assert.Equal(t, 20*time.Second, c.Server.ReadTimeout)
+		assert.Equal(t, 1, c.Workers.Count)
+		assert.Equal(t, 9*time.Second, c.Server.ShutdownTimeout)
+		assert.Equal(t, 10*time.Second, c.Workers.TaskTimeout)
+		assert.Equal(t, map[string]string{"x-api-key": "k1", "x-tenant": "maple"}, c.Tracing.OTLPHeaders)
+		assert.Equal(t, domain.ApprovalAll, c.Moderation.Approval)
+		assert.Equal(t, 0.25, c.Tracing.SampleRatio)
+		assert.Equal(t, uint8(4), c.Security.Argon2.Parallelism)
+		assert.Equal(t, 2525, c.Mail.SMTP.Port)
+		assert.Equal(t, 0.75, c.Rating.Glicko2.Tau)
+		assert.Equal(t, map[string]string{"club": "t0ken"}, c.Auth.ConsumerTokens)
+	})
+
+	t.Run("the flags over the variables", func(t *testing.T) {
+		c, err := Load([]string{"-config", path, "-env", "test", "-server.listen_address", ":9200", "-workers.count=3"},
+			lookupOf(map[string]string{"ENV": "dev", "LISTEN_ADDRESS": ":9100", "WORKER_COUNT": "6"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "test", c.Env)
+		assert.Equal(t, ":9200", c.Server.ListenAddress)
+		assert.Equal(t, 3, c.Workers.Count)
+		// of the test profile, the file has no section for it
+		assert.Equal(t, uint32(1), c.Security.Argon2.Iterations)
+		assert.Equal(t, 20*time.Second, c.Server.ReadTimeout)
+	})
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "maple.toml", `
+env = "prod"
+
+[server]
+listen_address = ":9000"
+write_timeout = "45s"
+
+[security.argon2]
+memory = 131072
+
+[profiles.prod.log]
+level = "error"
+`)
+
+	c, err := Load([]string{"-config", path}, lookupOf(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9000", c.Server.ListenAddress)
+	assert.Equal(t, 45*time.Second, c.Server.WriteTimeout)
+	assert.Equal(t, uint32(131072), c.Security.Argon2.Memory)
+	assert.Equal(t, "error", c.Log.Level)
+}
+
+func TestLoad_ReportsEveryError(t *testing.T) {
+	path := writeFile(t, "maple.yaml", `
+server:
+  listen_adress: ":9000"
+  idle_timeout: 60
+workers: 4
+profiles:
+  dev:
+    log:
+      level: loud
+`)
+
+	_, err := Load([]string{"-config", path, "-tracing.sample_ratio", "2"}, lookupOf(map[string]string{
+		"WORKER_COUNT":           "four",
+		"LISTEN_ADDRESS":         "8080",
+		"ARGON2_MEMORY":          "-1",
+		"IDEMPOTENCY_SQL_DRIVER": "pgx",
+		"ADMIN_TOKEN":            "s3cret",
+		"CONSUMER_TOKENS":        "club=s3cret,league=",
+		"MODERATOR_TOKENS":       "ana=t0ken,joan=t0ken",
+		"PLAYER_TOKENS":          "magnus=p1ayer",
+		"ELO_K":                  "0",
+		"GLICKO2_TAU":            "2",
+	}))
+	require.Error(t, err)
+
+	for _, want := range []string{
+		`server.idle_timeout: "60" is not a duration`,
+		`server.listen_adress: unknown key`,
+		`workers: is a section, not a value`,
+		`workers.count (WORKER_COUNT): "four" is not a number`,
+		`security.argon2.memory (ARGON2_MEMORY): "-1" is not a positive number`,
+		`server.listen_address: "8080" is not a host:port address`,
+		`tracing.sample_ratio: must be between 0 and 1`,
+		`log.level: "loud" is not one of debug, info, warn or error`,
+		`idempotency.sql_dsn: is required with a driver`,
+		`auth.consumer_tokens.club: is the token of someone else`,
+		`auth.consumer_tokens.league: is empty`,
+		`auth.moderator_tokens.joan: is the token of someone else`,
+		`auth.player_tokens.magnus: is not the public id of a player`,
+		`rating.elo.k: must be positive`,
+		`rating.glicko2.tau: must be between 0.3 and 1.2`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoad_Flags(t *testing.T) {
+	_, err := Load([]string{"-no-such-flag"}, lookupOf(nil))
+	assert.Error(t, err)
+
+	_, err = Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, lookupOf(nil))
+	assert.ErrorContains(t, err, "reading the config file")
+
+	assert.Contains(t, Usage(), "-server.read_timeout")
+	assert.Contains(t, Usage(), "(READ_TIMEOUT)")
+}
+
+func TestPrint_MasksSecrets(t *testing.T) {
+	c, err := Load(nil, lookupOf(map[string]string{
+		"SMTP_HOST":                  "smtp.maple.example",
+		"SMTP_USERNAME":              "maple",
+		"SMTP_PASSWORD":              "hunter2",
+		"ADMIN_TOKEN":                "s3cret",
+		"OTEL_EXPORTER_OTLP_HEADERS": "authorization=Bearer abc",
+	}))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, c))
+
+	printed := out.String()
+	assert.NotContains(t, printed, "hunter2")
+	assert.NotContains(t, printed, "s3cret")
+	assert.NotContains(t, printed, "Bearer abc")
+	assert.Contains(t, printed, "password: '********'")
+	assert.Contains(t, printed, "username: maple")
+	assert.Contains(t, printed, "read_timeout: 15s")
+
+	// the printed config is a config file
+	path := writeFile(t, "printed.yaml", strings.ReplaceAll(printed, "'********'", "secret"))
+	reloaded, err := Load([]string{"-config", path}, lookupOf(nil))
+	require.NoError(t, err)
+	assert.Equal(t, c.Server, reloaded.Server)
+
+	// the original config keeps its secrets
+	assert.Equal(t, "hunter2", c.Mail.SMTP.Password)
+	assert.Equal(t, "Bearer abc", c.Tracing.OTLPHeaders["authorization"])
+}
@@ -0,0 +1,331 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the variable of the config file when the -config flag is not given
+const FileEnv = "MAPLE_CONFIG"
+
+// field is a leaf of the config, key is its yaml path
+type field struct {
+	key    string
+	env    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// fields walks the config, the nested structs are the sections of the file
+func fields(v reflect.Value, prefix string) []field {
+	var out []field
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		key := sf.Tag.Get("yaml")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+			out = append(out, fields(v.Field(i), key)...)
+			continue
+		}
+		out = append(out, field{
+			key:    key,
+			env:    sf.Tag.Get("env"),
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+	return out
+}
+
+// set parses raw into the value of the field, the way it is written in the variables and flags
+func (f field) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a positive number", raw)
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Map:
+		m := make(map[string]string)
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		v.Set(reflect.ValueOf(m))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Load reads the config of the arguments, without the name of the program, and of the
+// variables lookup finds. The config file is the one of -config or MAPLE_CONFIG, it is yaml
+// unless its extension is .toml. Every invalid value is reported in the error
+func Load(args []string, lookup func(string) (string, bool)) (Config, error) {
+	c := Defaults()
+	leaves := fields(reflect.ValueOf(&c).Elem(), "")
+
+	fs := flag.NewFlagSet("maple", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	file := fs.String("config", "", "yaml or toml config file, also "+FileEnv)
+	flags := make(map[string]string)
+	for _, leaf := range leaves {
+		fs.Func(leaf.key, leaf.usage, func(raw string) error {
+			flags[leaf.key] = raw
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+	if *file == "" {
+		*file, _ = lookup(FileEnv)
+	}
+
+	var base, profileSections map[string]any
+	if *file != "" {
+		var err error
+		base, profileSections, err = readFile(*file)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	// the profile is known before the file is applied, the file may have a section for it
+	env := c.Env
+	if value, ok := base["env"].(string); ok {
+		env = value
+	}
+	if value, ok := lookup("ENV"); ok && value != "" {
+		env = value
+	}
+	if value, ok := flags["env"]; ok {
+		env = value
+	}
+	if profile, ok := profiles[env]; ok {
+		profile(&c)
+	}
+
+	var errs []error
+	errs = append(errs, applySection(base, leaves, "")...)
+	if section, ok := profileSections[env]; ok {
+		sectionMap, ok := section.(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("profiles.%s: is not a section", env))
+		} else {
+			errs = append(errs, applySection(sectionMap, leaves, "profiles."+env+".")...)
+		}
+	}
+
+	for _, leaf := range leaves {
+		if leaf.env == "" {
+			continue
+		}
+		raw, ok := lookup(leaf.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := leaf.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", leaf.key, leaf.env, err))
+		}
+	}
+	for _, leaf := range leaves {
+		raw, ok := flags[leaf.key]
+		if !ok {
+			continue
+		}
+		if err := leaf.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s (-%s): %w", leaf.key, leaf.key, err))
+		}
+	}
+	c.Env = env
+
+	if err := c.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	return c, nil
+}
+
+// readFile splits the file in its base keys and its profiles section
+func readFile(path string) (map[string]any, map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the config file: %w", err)
+	}
+
+	base := make(map[string]any)
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &base)
+	} else {
+		err = yaml.Unmarshal(data, &base)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing the config file %s: %w", path, err)
+	}
+
+	var sections map[string]any
+	if raw, ok := base["profiles"]; ok {
+		sections, ok = raw.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("parsing the config file %s: profiles is not a section", path)
+		}
+		delete(base, "profiles")
+	}
+
+	return base, sections, nil
+}
+
+// applySection sets the keys of a section of the file, origin prefixes its errors. The values
+// are parsed as the ones of the variables, a duration is written "30s" in both formats
+func applySection(section map[string]any, leaves []field, origin string) []error {
+	byKey := make(map[string]field, len(leaves))
+	for _, leaf := range leaves {
+		byKey[leaf.key] = leaf
+	}
+	isSection := func(key string) bool {
+		for _, leaf := range leaves {
+			if strings.HasPrefix(leaf.key, key+".") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var errs []error
+	var walk func(m map[string]any, prefix string)
+	walk = func(m map[string]any, prefix string) {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			value := m[key]
+			if leaf, ok := byKey[path]; ok {
+				if err := leaf.setValue(value); err != nil {
+					errs = append(errs, fmt.Errorf("%s%s: %w", origin, path, err))
+				}
+				continue
+			}
+			if !isSection(path) {
+				errs = append(errs, fmt.Errorf("%s%s: unknown key", origin, path))
+				continue
+			}
+			sub, ok := value.(map[string]any)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s%s: is a section, not a value", origin, path))
+				continue
+			}
+			walk(sub, path)
+		}
+	}
+	walk(section, "")
+
+	return errs
+}
+
+// setValue sets a value of the file
+func (f field) setValue(value any) error {
+	if f.value.Kind() == reflect.Map {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return errors.New("is not a section")
+		}
+		pairs := make(map[string]string, len(m))
+		for key, value := range m {
+			pairs[key] = fmt.Sprint(value)
+		}
+		f.value.Set(reflect.ValueOf(pairs))
+		return nil
+	}
+
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return f.set(fmt.Sprint(value))
+	case map[string]any:
+		return errors.New("is a section, not a value")
+	default:
+		return fmt.Errorf("%v is not a value", value)
+	}
+}
+
+// Usage lists the flags and the variables of every key
+func Usage() string {
+	c := Defaults()
+	leaves := fields(reflect.ValueOf(&c).Elem(), "")
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].key < leaves[j].key })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  -config string\n\tyaml or toml config file, also %s\n", FileEnv)
+	for _, leaf := range leaves {
+		fmt.Fprintf(&b, "  -%s\n\t%s", leaf.key, leaf.usage)
+		if leaf.env != "" {
+			fmt.Fprintf(&b, " (%s)", leaf.env)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const masked = "********"
+
+// Masked is a copy of the config with the secrets that are set masked
+func (c Config) Masked() Config {
+	for _, leaf := range fields(reflect.ValueOf(&c).Elem(), "") {
+		if !leaf.secret || leaf.value.IsZero() {
+			continue
+		}
+		switch leaf.value.Kind() {
+		case reflect.String:
+			leaf.value.SetString(masked)
+		case reflect.Map:
+			// the map is shared with the original config
+			pairs := make(map[string]string, leaf.value.Len())
+			for _, key := range leaf.value.MapKeys() {
+				pairs[key.String()] = masked
+			}
+			leaf.value.Set(reflect.ValueOf(pairs))
+		}
+	}
+	return c
+}
+
+// Print writes the config as the yaml of a config file with the secrets masked
+func Print(w io.Writer, c Config) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Masked()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,211 @@
+package chess
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func perft(p Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	moves := p.LegalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+	nodes := 0
+	for _, m := range moves {
+		nodes += perft(p.Play(m), depth-1)
+	}
+	return nodes
+}
+
+// the node counts of the chess programming wiki perft positions
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		depth int
+		nodes int
+	}{
+		{name: "initial", fen: StartingFEN, depth: 3, nodes: 8902},
+		{name: "kiwipete", fen: "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 2, nodes: 2039},
+		{name: "position 3", fen: "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", depth: 4, nodes: 43238},
+		{name: "position 4", fen: "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", depth: 3, nodes: 9467},
+		{name: "position 5", fen: "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", depth: 2, nodes: 1486},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("error parsing fen: %v", err)
+			}
+			if nodes := perft(pos, tt.depth); nodes != tt.nodes {
+				t.Errorf("expected %d nodes at depth %d, got %d", tt.nodes, tt.depth, nodes)
+			}
+		})
+	}
+}
+
+func TestParseFEN(t *testing.T) {
+	for _, fen := range []string{
+		StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+	} {
+		pos, err := ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("error parsing %s: %v", fen, err)
+		}
+		if got := pos.FEN(); got != fen {
+			t.Errorf("expected %s, got %s", fen, got)
+		}
+	}
+
+	for _, fen := range []string{
+		"",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQQBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+		"4k3/8/8/8/8/8/8/4K2R b - - 0 1 extra",
+		"4k2R/8/8/8/8/8/8/4K3 w - - 0 1",
+	} {
+		if _, err := ParseFEN(fen); !errors.Is(err, ErrInvalidFEN) {
+			t.Errorf("expected ErrInvalidFEN for %q, got %v", fen, err)
+		}
+	}
+}
+
+func TestSAN(t *testing.T) {
+	tests := []struct {
+		fen  string
+		move string // uci
+		san  string
+	}{
+		{fen: StartingFEN, move: "g1f3", san: "Nf3"},
+		{fen: "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", move: "e1g1", san: "O-O"},
+		{fen: "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", move: "e8c8", san: "O-O-O"},
+		{fen: "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", move: "e5f6", san: "exf6"},
+		{fen: "4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", move: "b7b8q", san: "b8=Q+"},
+		{fen: "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", move: "a1a8", san: "Ra8#"},
+		{fen: "4k3/8/8/8/8/8/8/R4RK1 w - - 0 1", move: "a1d1", san: "Rad1"},
+		{fen: "4k3/8/8/8/R7/8/8/R3K3 w - - 0 1", move: "a1a2", san: "R1a2"},
+		{fen: "k7/8/8/8/8/2Q1Q3/8/2Q1K3 w - - 0 1", move: "c3d2", san: "Qc3d2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.san, func(t *testing.T) {
+			pos, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("error parsing fen: %v", err)
+			}
+			m, err := pos.ParseMove(tt.move)
+			if err != nil {
+				t.Fatalf("error parsing %s: %v", tt.move, err)
+			}
+			if san := pos.SAN(m); san != tt.san {
+				t.Errorf("expected %s, got %s", tt.san, san)
+			}
+			parsed, err := pos.ParseMove(tt.san)
+			if err != nil || parsed != m {
+				t.Errorf("expected %s to parse back to %s, got %s, %v", tt.san, tt.move, parsed.UCI(), err)
+			}
+		})
+	}
+
+	pos := StartingPosition()
+	for _, text := range []string{"e5", "Ke2", "e2e5", "O-O", "Nf3x", "", "e9"} {
+		if _, err := pos.ParseMove(text); !errors.Is(err, ErrIllegalMove) {
+			t.Errorf("expected ErrIllegalMove for %q, got %v", text, err)
+		}
+	}
+	for _, text := range []string{"Ngf3", "Ng1f3", "Nf3!?", "g1f3"} {
+		if m, err := pos.ParseMove(text); err != nil || m.UCI() != "g1f3" {
+			t.Errorf("expected %q to be g1f3, got %s, %v", text, m.UCI(), err)
+		}
+	}
+}
+
+func TestGame_Outcome(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		moves []string
+		want  Outcome
+	}{
+		{name: "fool's mate", fen: StartingFEN, moves: []string{"f3", "e5", "g4", "Qh4"}, want: Outcome{Result: BlackWins, Termination: Checkmate}},
+		{name: "stalemate", fen: "7k/8/6Q1/8/8/8/8/K7 w - - 0 1", moves: []string{"Qf7"}, want: Outcome{Result: Draw, Termination: Stalemate}},
+		{name: "bare kings", fen: "4k3/8/8/8/8/8/3q4/4K3 w - - 0 1", moves: []string{"Kxd2"}, want: Outcome{Result: Draw, Termination: InsufficientMaterial}},
+		{name: "same coloured bishops", fen: "4k3/8/3b4/8/8/8/8/2B1K3 w - - 0 1", want: Outcome{Result: Draw, Termination: InsufficientMaterial}},
+		{name: "knight and bishop", fen: "4k3/8/8/3n4/8/8/8/2B1K3 w - - 0 1", want: Outcome{Result: Ongoing}},
+		{
+			name:  "fivefold repetition",
+			fen:   StartingFEN,
+			moves: strings.Fields(strings.Repeat("Nf3 Nf6 Ng1 Ng8 ", 4)),
+			want:  Outcome{Result: Draw, Termination: FivefoldRepetition},
+		},
+		{name: "seventy five moves", fen: "4k3/8/8/8/8/8/8/R3K3 w - - 149 100", moves: []string{"Ra2"}, want: Outcome{Result: Draw, Termination: SeventyFiveMoves}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("error parsing fen: %v", err)
+			}
+			g := NewGame(pos)
+			for _, m := range tt.moves {
+				if _, _, err := g.Play(m); err != nil {
+					t.Fatalf("error playing %s: %v", m, err)
+				}
+			}
+			if got := g.Outcome(); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	g := NewGame(StartingPosition())
+	for _, m := range []string{"f3", "e5", "g4", "Qh4#"} {
+		if _, _, err := g.Play(m); err != nil {
+			t.Fatalf("error playing %s: %v", m, err)
+		}
+	}
+	if _, _, err := g.Play("a3"); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("expected no move after the mate, got %v", err)
+	}
+}
+
+func TestPGN(t *testing.T) {
+	pgn := PGN{
+		Tags:   [][2]string{{"Event", `Open "A"`}, {"White", "Doe, Jane"}},
+		Moves:  []string{"e4", "e5", "Nf3"},
+		Clocks: []time.Duration{90 * time.Minute, 89*time.Minute + 58*time.Second, 89 * time.Minute},
+		Result: Ongoing,
+	}
+	want := `[Event "Open \"A\""]
+[White "Doe, Jane"]
+[Result "*"]
+
+1. e4 {[%clk 1:30:00]} e5 {[%clk 1:29:58]} 2. Nf3 {[%clk 1:29:00]} *
+`
+	if got := pgn.String(); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	pgn = PGN{Moves: []string{"Kd2", "Kd7"}, Result: Draw, FEN: "4k3/8/8/8/8/8/8/4K3 w - - 0 40"}
+	if got := pgn.String(); !strings.Contains(got, `[FEN "4k3/8/8/8/8/8/8/4K3 w - - 0 40"]`) || !strings.HasSuffix(got, "40. Kd2 Kd7 1/2-1/2\n") {
+		t.Errorf("unexpected pgn\n%s", got)
+	}
+
+	long := PGN{Moves: strings.Fields(strings.Repeat("Nf3 Nf6 Ng1 Ng8 ", 10)), Result: Draw}
+	for _, line := range strings.Split(long.String(), "\n") {
+		if len(line) > 79 {
+			t.Errorf("line longer than 79 characters: %q", line)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Result is the result of a game as written in pgn
+type Result string
+
+const (
+	WhiteWins Result = "1-0"
+	BlackWins Result = "0-1"
+	Draw      Result = "1/2-1/2"
+	Ongoing   Result = "*"
+)
+
+// Termination is why a game ended by the rules, without the players or the arbiter
+type Termination string
+
+const (
+	Checkmate            Termination = "checkmate"
+	Stalemate            Termination = "stalemate"
+	InsufficientMaterial Termination = "insufficient_material"
+	FivefoldRepetition   Termination = "fivefold_repetition"
+	SeventyFiveMoves     Termination = "seventy_five_moves"
+)
+
+// Outcome is how a game ended, Result is Ongoing while it is played
+type Outcome struct {
+	Result      Result
+	Termination Termination
+}
+
+// Game is a game from its initial position, it knows the positions played so far for the
+// repetition rules
+type Game struct {
+	initial  Position
+	position Position
+	moves    []Move
+	sans     []string
+	seen     map[string]int // times each position occurred
+}
+
+// NewGame starts a game from the position
+func NewGame(initial Position) *Game {
+	g := &Game{
+		initial:  initial,
+		position: initial,
+		seen:     make(map[string]int),
+	}
+	g.seen[initial.key()]++
+	return g
+}
+
+// Position returns the current position of the game
+func (g *Game) Position() Position {
+	return g.position
+}
+
+// Initial returns the position the game started from
+func (g *Game) Initial() Position {
+	return g.initial
+}
+
+// Moves returns the moves played so far
+func (g *Game) Moves() []Move {
+	return append([]Move(nil), g.moves...)
+}
+
+// SANs returns the moves played so far in standard algebraic notation
+func (g *Game) SANs() []string {
+	return append([]string(nil), g.sans...)
+}
+
+// Play parses and plays the move, it returns the move in standard algebraic notation. No move
+// can be played once the game ended by the rules
+func (g *Game) Play(text string) (Move, string, error) {
+	if g.Outcome().Result != Ongoing {
+		return Move{}, "", fmt.Errorf("%w: the game is over", ErrIllegalMove)
+	}
+
+	m, err := g.position.ParseMove(text)
+	if err != nil {
+		return Move{}, "", err
+	}
+
+	san := g.position.SAN(m)
+	g.position = g.position.apply(m)
+	g.moves = append(g.moves, m)
+	g.sans = append(g.sans, san)
+	g.seen[g.position.key()]++
+
+	return m, san, nil
+}
+
+// Outcome returns how the game ended by the rules. The draws a player has to claim, the
+// threefold repetition and the fifty move rule, are left to the arbiter
+func (g *Game) Outcome() Outcome {
+	pos := g.position
+	if len(pos.LegalMoves()) == 0 {
+		if pos.InCheck() {
+			if pos.Turn == White {
+				return Outcome{Result: BlackWins, Termination: Checkmate}
+			}
+			return Outcome{Result: WhiteWins, Termination: Checkmate}
+		}
+		return Outcome{Result: Draw, Termination: Stalemate}
+	}
+	if pos.insufficientMaterial() {
+		return Outcome{Result: Draw, Termination: InsufficientMaterial}
+	}
+	if g.seen[pos.key()] >= 5 {
+		return Outcome{Result: Draw, Termination: FivefoldRepetition}
+	}
+	if pos.HalfmoveClock >= 150 {
+		return Outcome{Result: Draw, Termination: SeventyFiveMoves}
+	}
+	return Outcome{Result: Ongoing}
+}
+
+// Repetitions returns how many times the current position occurred, a player can claim a
+// draw from 3
+func (g *Game) Repetitions() int {
+	return g.seen[g.position.key()]
+}
+
+// insufficientMaterial - neither side can mate: king against king, a single minor piece, or
+// bishops that all stand on squares of the same colour
+func (p Position) insufficientMaterial() bool {
+	var knights, bishops int
+	bishopSquares := [2]bool{}
+	for sq := Square(0); sq < 64; sq++ {
+		switch p.board[sq].Kind {
+		case Pawn, Rook, Queen:
+			return false
+		case Knight:
+			knights++
+		case Bishop:
+			bishops++
+			bishopSquares[(sq.File()+sq.Rank())%2] = true
+		}
+	}
+
+	switch {
+	case knights+bishops <= 1:
+		return true
+	case knights == 0:
+		return !(bishopSquares[0] && bishopSquares[1])
+	default:
+		return false
+	}
+}
+
+// PGN is a game in Portable Game Notation
+type PGN struct {
+	Tags   [][2]string     // name and value, in the order written
+	Moves  []string        // in standard algebraic notation
+	Clocks []time.Duration // left after each move, written as %clk comments when given
+	Result Result
+	// FEN of the initial position when the game did not start from the standard one
+	FEN string
+}
+
+// String writes the game, the seven tag roster first
+func (pgn PGN) String() string {
+	var b strings.Builder
+
+	result := pgn.Result
+	if result == "" {
+		result = Ongoing
+	}
+	tags := pgn.Tags
+	if pgn.FEN != "" && pgn.FEN != StartingFEN {
+		tags = append(tags[:len(tags):len(tags)], [2]string{"SetUp", "1"}, [2]string{"FEN", pgn.FEN})
+	}
+	hasResult := false
+	for _, tag := range tags {
+		if tag[0] == "Result" {
+			hasResult = true
+		}
+		fmt.Fprintf(&b, "[%s \"%s\"]\n", tag[0], escapeTag(tag[1]))
+	}
+	if !hasResult {
+		fmt.Fprintf(&b, "[Result \"%s\"]\n", result)
+	}
+	b.WriteByte('\n')
+
+	// the move numbers continue from the initial position
+	number, black := 1, false
+	if pgn.FEN != "" {
+		if pos, err := ParseFEN(pgn.FEN); err == nil {
+			number, black = pos.FullmoveNumber, pos.Turn == Black
+		}
+	}
+
+	var tokens []string
+	for i, san := range pgn.Moves {
+		switch {
+		case !black:
+			tokens = append(tokens, fmt.Sprintf("%d.", number))
+		case i == 0:
+			tokens = append(tokens, fmt.Sprintf("%d...", number))
+		}
+		tokens = append(tokens, san)
+		if i < len(pgn.Clocks) {
+			tokens = append(tokens, "{[%clk "+formatClock(pgn.Clocks[i])+"]}")
+		}
+		if black {
+			number++
+		}
+		black = !black
+	}
+	tokens = append(tokens, string(result))
+
+	// lines are kept under 80 characters
+	line := 0
+	for i, token := range tokens {
+		if i > 0 {
+			if line+1+len(token) > 79 {
+				b.WriteByte('\n')
+				line = 0
+			} else {
+				b.WriteByte(' ')
+				line++
+			}
+		}
+		b.WriteString(token)
+		line += len(token)
+	}
+	b.WriteByte('\n')
+
+	return b.String()
+}
+
+func escapeTag(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// formatClock writes the time as h:mm:ss
+func formatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	s := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,321 @@
+package chess
+
+// Move is a move of the piece on From to To, Promotion is the piece a pawn reaching the last
+// rank becomes. Castling is the king moving two squares
+type Move struct {
+	From      Square
+	To        Square
+	Promotion PieceKind
+}
+
+// UCI writes the move in the long algebraic notation of the uci protocol, e.g. e2e4 or e7e8q
+func (m Move) UCI() string {
+	s := m.From.String() + m.To.String()
+	if m.Promotion != NoKind {
+		s += string(pieceLetters[m.Promotion])
+	}
+	return s
+}
+
+var (
+	knightJumps = [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingSteps   = [][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	bishopRays  = [][2]int{{1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+	rookRays    = [][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+	promotions = []PieceKind{Queen, Rook, Bishop, Knight}
+)
+
+// offset returns the square df files and dr ranks away, false when it is off the board
+func offset(sq Square, df, dr int) (Square, bool) {
+	f, r := sq.File()+df, sq.Rank()+dr
+	if f < 0 || f > 7 || r < 0 || r > 7 {
+		return NoSquare, false
+	}
+	return NewSquare(f, r), true
+}
+
+// attacked reports whether a piece of the color attacks the square
+func (p Position) attacked(sq Square, by Color) bool {
+	if sq == NoSquare {
+		return false
+	}
+
+	// a pawn of the color attacks the square from one rank behind it
+	pawnRank := -1
+	if by == Black {
+		pawnRank = 1
+	}
+	for _, df := range []int{-1, 1} {
+		if from, ok := offset(sq, df, pawnRank); ok && p.board[from] == (Piece{Kind: Pawn, Color: by}) {
+			return true
+		}
+	}
+	for _, d := range knightJumps {
+		if from, ok := offset(sq, d[0], d[1]); ok && p.board[from] == (Piece{Kind: Knight, Color: by}) {
+			return true
+		}
+	}
+	for _, d := range kingSteps {
+		if from, ok := offset(sq, d[0], d[1]); ok && p.board[from] == (Piece{Kind: King, Color: by}) {
+			return true
+		}
+	}
+
+	slider := func(rays [][2]int, kind PieceKind) bool {
+		for _, d := range rays {
+			for from, ok := offset(sq, d[0], d[1]); ok; from, ok = offset(from, d[0], d[1]) {
+				piece := p.board[from]
+				if piece.Empty() {
+					continue
+				}
+				if piece.Color == by && (piece.Kind == kind || piece.Kind == Queen) {
+					return true
+				}
+				break
+			}
+		}
+		return false
+	}
+
+	return slider(bishopRays, Bishop) || slider(rookRays, Rook)
+}
+
+// LegalMoves returns every legal move of the side to move
+func (p Position) LegalMoves() []Move {
+	var legal []Move
+	for _, m := range p.pseudoLegalMoves() {
+		next := p.apply(m)
+		if !next.attacked(next.king(p.Turn), next.Turn) {
+			legal = append(legal, m)
+		}
+	}
+	return legal
+}
+
+// IsLegal reports whether the move is legal in the position
+func (p Position) IsLegal(m Move) bool {
+	for _, legal := range p.LegalMoves() {
+		if legal == m {
+			return true
+		}
+	}
+	return false
+}
+
+// pseudoLegalMoves are the moves of the pieces, they may leave the king in check
+func (p Position) pseudoLegalMoves() []Move {
+	moves := make([]Move, 0, 48)
+	for from := Square(0); from < 64; from++ {
+		piece := p.board[from]
+		if piece.Empty() || piece.Color != p.Turn {
+			continue
+		}
+
+		switch piece.Kind {
+		case Pawn:
+			moves = p.pawnMoves(moves, from)
+		case Knight:
+			moves = p.stepMoves(moves, from, knightJumps)
+		case Bishop:
+			moves = p.slideMoves(moves, from, bishopRays)
+		case Rook:
+			moves = p.slideMoves(moves, from, rookRays)
+		case Queen:
+			moves = p.slideMoves(moves, from, bishopRays)
+			moves = p.slideMoves(moves, from, rookRays)
+		case King:
+			moves = p.stepMoves(moves, from, kingSteps)
+			moves = p.castlingMoves(moves, from)
+		}
+	}
+	return moves
+}
+
+func (p Position) pawnMoves(moves []Move, from Square) []Move {
+	dir, startRank, lastRank := 1, 1, 7
+	if p.Turn == Black {
+		dir, startRank, lastRank = -1, 6, 0
+	}
+
+	add := func(to Square) {
+		if to.Rank() == lastRank {
+			for _, kind := range promotions {
+				moves = append(moves, Move{From: from, To: to, Promotion: kind})
+			}
+			return
+		}
+		moves = append(moves, Move{From: from, To: to})
+	}
+
+	if to, ok := offset(from, 0, dir); ok && p.board[to].Empty() {
+		add(to)
+		if from.Rank() == startRank {
+			if to2, _ := offset(to, 0, dir); p.board[to2].Empty() {
+				add(to2)
+			}
+		}
+	}
+	for _, df := range []int{-1, 1} {
+		to, ok := offset(from, df, dir)
+		if !ok {
+			continue
+		}
+		target := p.board[to]
+		if (!target.Empty() && target.Color != p.Turn) || to == p.EnPassant {
+			add(to)
+		}
+	}
+
+	return moves
+}
+
+func (p Position) stepMoves(moves []Move, from Square, steps [][2]int) []Move {
+	for _, d := range steps {
+		to, ok := offset(from, d[0], d[1])
+		if !ok {
+			continue
+		}
+		if target := p.board[to]; target.Empty() || target.Color != p.Turn {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+	return moves
+}
+
+func (p Position) slideMoves(moves []Move, from Square, rays [][2]int) []Move {
+	for _, d := range rays {
+		for to, ok := offset(from, d[0], d[1]); ok; to, ok = offset(to, d[0], d[1]) {
+			target := p.board[to]
+			if target.Empty() {
+				moves = append(moves, Move{From: from, To: to})
+				continue
+			}
+			if target.Color != p.Turn {
+				moves = append(moves, Move{From: from, To: to})
+			}
+			break
+		}
+	}
+	return moves
+}
+
+// castlingMoves - the king cannot castle out of, through or into check and the squares
+// between it and the rook have to be empty
+func (p Position) castlingMoves(moves []Move, from Square) []Move {
+	kingside, queenside := WhiteKingside, WhiteQueenside
+	if p.Turn == Black {
+		kingside, queenside = BlackKingside, BlackQueenside
+	}
+	if p.Castling&(kingside|queenside) == 0 || p.attacked(from, p.Turn.Other()) {
+		return moves
+	}
+
+	empty := func(squares ...Square) bool {
+		for _, sq := range squares {
+			if !p.board[sq].Empty() {
+				return false
+			}
+		}
+		return true
+	}
+	safe := func(squares ...Square) bool {
+		for _, sq := range squares {
+			if p.attacked(sq, p.Turn.Other()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if p.Castling&kingside != 0 && empty(from+1, from+2) && safe(from+1) {
+		moves = append(moves, Move{From: from, To: from + 2})
+	}
+	if p.Castling&queenside != 0 && empty(from-1, from-2, from-3) && safe(from-1) {
+		moves = append(moves, Move{From: from, To: from - 2})
+	}
+	return moves
+}
+
+// Play returns the position after the move, it must be legal
+func (p Position) Play(m Move) Position {
+	return p.apply(m)
+}
+
+func (p Position) apply(m Move) Position {
+	next := p
+	piece := p.board[m.From]
+	captured := p.board[m.To]
+
+	next.board[m.From] = Piece{}
+	next.board[m.To] = piece
+	next.EnPassant = NoSquare
+
+	switch piece.Kind {
+	case Pawn:
+		if m.To == p.EnPassant && captured.Empty() {
+			// the captured pawn is beside the pawn, on the square it passed over
+			next.board[NewSquare(m.To.File(), m.From.Rank())] = Piece{}
+		}
+		if m.Promotion != NoKind {
+			next.board[m.To] = Piece{Kind: m.Promotion, Color: piece.Color}
+		}
+		if diff := int(m.To) - int(m.From); diff == 16 || diff == -16 {
+			next.setEnPassant(m.From + Square(diff/2))
+		}
+	case King:
+		if diff := int(m.To) - int(m.From); diff == 2 || diff == -2 {
+			rookFrom, rookTo := m.From+3, m.From+1
+			if diff < 0 {
+				rookFrom, rookTo = m.From-4, m.From-1
+			}
+			next.board[rookTo] = next.board[rookFrom]
+			next.board[rookFrom] = Piece{}
+		}
+	}
+
+	// moving the king or a rook, or a rook being captured, loses the castling right
+	for _, sq := range []Square{m.From, m.To} {
+		switch sq {
+		case 4:
+			next.Castling &^= WhiteKingside | WhiteQueenside
+		case 7:
+			next.Castling &^= WhiteKingside
+		case 0:
+			next.Castling &^= WhiteQueenside
+		case 60:
+			next.Castling &^= BlackKingside | BlackQueenside
+		case 63:
+			next.Castling &^= BlackKingside
+		case 56:
+			next.Castling &^= BlackQueenside
+		}
+	}
+
+	next.HalfmoveClock++
+	if piece.Kind == Pawn || !captured.Empty() {
+		next.HalfmoveClock = 0
+	}
+	if p.Turn == Black {
+		next.FullmoveNumber++
+	}
+	next.Turn = p.Turn.Other()
+
+	return next
+}
+
+// setEnPassant keeps the square only when a pawn of the side to move is beside the pawn
+// that just moved, so the same positions compare equal for the repetition rules
+func (p *Position) setEnPassant(passed Square) {
+	opponent := p.Turn.Other()
+	pawnSquare := passed + 8
+	if opponent == White {
+		pawnSquare = passed - 8
+	}
+	for _, df := range []int{-1, 1} {
+		if sq, ok := offset(pawnSquare, df, 0); ok && p.board[sq] == (Piece{Kind: Pawn, Color: opponent}) {
+			p.EnPassant = passed
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrIllegalMove = errors.New("illegal move")
+
+// SAN writes the legal move in standard algebraic notation, e.g. Nbd7, exd6, O-O or e8=Q#
+func (p Position) SAN(m Move) string {
+	piece := p.board[m.From]
+	var b strings.Builder
+
+	switch {
+	case piece.Kind == King && int(m.To)-int(m.From) == 2:
+		b.WriteString("O-O")
+	case piece.Kind == King && int(m.To)-int(m.From) == -2:
+		b.WriteString("O-O-O")
+	case piece.Kind == Pawn:
+		if m.From.File() != m.To.File() {
+			b.WriteByte(byte('a' + m.From.File()))
+			b.WriteByte('x')
+		}
+		b.WriteString(m.To.String())
+		if m.Promotion != NoKind {
+			b.WriteByte('=')
+			b.WriteByte(Piece{Kind: m.Promotion}.letter())
+		}
+	default:
+		b.WriteByte(Piece{Kind: piece.Kind}.letter())
+		b.WriteString(p.disambiguation(m))
+		if !p.board[m.To].Empty() {
+			b.WriteByte('x')
+		}
+		b.WriteString(m.To.String())
+	}
+
+	next := p.apply(m)
+	if next.InCheck() {
+		if len(next.LegalMoves()) == 0 {
+			b.WriteByte('#')
+		} else {
+			b.WriteByte('+')
+		}
+	}
+
+	return b.String()
+}
+
+// disambiguation is the file, the rank or the square of the piece when another piece of the
+// same kind can move to the same square
+func (p Position) disambiguation(m Move) string {
+	kind := p.board[m.From].Kind
+	var sameFile, sameRank, ambiguous bool
+	for _, other := range p.LegalMoves() {
+		if other.To != m.To || other.From == m.From || p.board[other.From].Kind != kind {
+			continue
+		}
+		ambiguous = true
+		sameFile = sameFile || other.From.File() == m.From.File()
+		sameRank = sameRank || other.From.Rank() == m.From.Rank()
+	}
+
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return string(rune('a' + m.From.File()))
+	case !sameRank:
+		return string(rune('1' + m.From.Rank()))
+	default:
+		return m.From.String()
+	}
+}
+
+// ParseMove reads a move in standard algebraic notation or in the uci notation, the move has
+// to be legal in the position. Check marks, annotations and the = of promotions are optional
+func (p Position) ParseMove(text string) (Move, error) {
+	text = strings.TrimSpace(text)
+	legal := p.LegalMoves()
+
+	if m, ok := parseUCI(text); ok {
+		for _, l := range legal {
+			if l == m {
+				return m, nil
+			}
+		}
+		return Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, text)
+	}
+
+	want := normalizeSAN(text)
+	if want == "" {
+		return Move{}, fmt.Errorf("%w: empty move", ErrIllegalMove)
+	}
+	for _, m := range legal {
+		if normalizeSAN(p.SAN(m)) == want {
+			return m, nil
+		}
+	}
+
+	// an over specified move such as Ngf3 is still the move
+	for _, m := range legal {
+		piece := p.board[m.From]
+		if piece.Kind == Pawn || piece.Kind == King {
+			continue
+		}
+		letter := string(Piece{Kind: piece.Kind}.letter())
+		capture := ""
+		if !p.board[m.To].Empty() {
+			capture = "x"
+		}
+		for _, from := range []string{m.From.String()[:1], m.From.String()[1:], m.From.String()} {
+			if want == letter+from+capture+m.To.String() {
+				return m, nil
+			}
+		}
+	}
+
+	return Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, text)
+}
+
+func parseUCI(text string) (Move, bool) {
+	if len(text) != 4 && len(text) != 5 {
+		return Move{}, false
+	}
+	from, ok1 := ParseSquare(text[:2])
+	to, ok2 := ParseSquare(text[2:4])
+	if !ok1 || !ok2 {
+		return Move{}, false
+	}
+	m := Move{From: from, To: to}
+	if len(text) == 5 {
+		kind := PieceKind(strings.IndexByte(pieceLetters, text[4]|0x20))
+		if kind < Knight || kind > Queen {
+			return Move{}, false
+		}
+		m.Promotion = kind
+	}
+	return m, true
+}
+
+// normalizeSAN drops what a move can be written with or without
+func normalizeSAN(san string) string {
+	san = strings.TrimSuffix(san, "e.p.")
+	san = strings.TrimRight(san, "+#!? ")
+	san = strings.ReplaceAll(san, "0", "O")
+	san = strings.ReplaceAll(san, "=", "")
+	return san
+}
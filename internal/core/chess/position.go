@@ -0,0 +1,309 @@
+// Package chess knows the rules of the game: it reads and writes FEN, generates the legal
+// moves of a position and writes them in standard algebraic notation
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFEN = errors.New("invalid fen")
+
+// StartingFEN is the position every standard game starts from
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+type Color uint8
+
+const (
+	White Color = iota
+	Black
+)
+
+func (c Color) Other() Color {
+	return c ^ 1
+}
+
+func (c Color) String() string {
+	if c == White {
+		return "white"
+	}
+	return "black"
+}
+
+type PieceKind uint8
+
+const (
+	NoKind PieceKind = iota
+	Pawn
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+// Piece is a piece on the board, the zero value is an empty square
+type Piece struct {
+	Kind  PieceKind
+	Color Color
+}
+
+func (p Piece) Empty() bool {
+	return p.Kind == NoKind
+}
+
+const pieceLetters = " pnbrqk"
+
+// letter is the fen letter of the piece, upper case for white
+func (p Piece) letter() byte {
+	l := pieceLetters[p.Kind]
+	if p.Color == White {
+		return l - 'a' + 'A'
+	}
+	return l
+}
+
+// Square is a square of the board from a1 = 0 to h8 = 63
+type Square int8
+
+const NoSquare Square = -1
+
+func NewSquare(file, rank int) Square {
+	return Square(rank*8 + file)
+}
+
+// ParseSquare reads a square such as e4
+func ParseSquare(s string) (Square, bool) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return NoSquare, false
+	}
+	return NewSquare(int(s[0]-'a'), int(s[1]-'1')), true
+}
+
+func (s Square) File() int { return int(s) % 8 }
+func (s Square) Rank() int { return int(s) / 8 }
+
+func (s Square) String() string {
+	if s == NoSquare {
+		return "-"
+	}
+	return string([]byte{byte('a' + s.File()), byte('1' + s.Rank())})
+}
+
+// CastlingRights - which castles are still possible, as far as the king and rooks never moved
+type CastlingRights uint8
+
+const (
+	WhiteKingside CastlingRights = 1 << iota
+	WhiteQueenside
+	BlackKingside
+	BlackQueenside
+)
+
+// Position is the state of a game between two moves
+type Position struct {
+	board          [64]Piece
+	Turn           Color
+	Castling       CastlingRights
+	EnPassant      Square // the square a pawn can be captured en passant on, NoSquare when none
+	HalfmoveClock  int    // plies since the last capture or pawn move, for the fifty move rule
+	FullmoveNumber int
+}
+
+// StartingPosition returns the position every standard game starts from
+func StartingPosition() Position {
+	pos, err := ParseFEN(StartingFEN)
+	if err != nil {
+		panic(err)
+	}
+	return pos
+}
+
+// At returns the piece on the square
+func (p Position) At(sq Square) Piece {
+	return p.board[sq]
+}
+
+// ParseFEN reads a position in Forsyth-Edwards Notation, the move counters are optional
+func ParseFEN(fen string) (Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 6 {
+		return Position{}, fmt.Errorf("%w: expected 6 fields, got %d", ErrInvalidFEN, len(fields))
+	}
+
+	pos := Position{EnPassant: NoSquare, FullmoveNumber: 1}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return Position{}, fmt.Errorf("%w: expected 8 ranks", ErrInvalidFEN)
+	}
+	kings := [2]int{}
+	for i, row := range ranks {
+		rank, file := 7-i, 0
+		for _, c := range []byte(row) {
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+			kind := PieceKind(strings.IndexByte(pieceLetters, c|0x20))
+			if kind == NoKind || kind > King || file > 7 {
+				return Position{}, fmt.Errorf("%w: unexpected %q on rank %d", ErrInvalidFEN, c, rank+1)
+			}
+			color := Black
+			if c < 'a' {
+				color = White
+			}
+			if kind == Pawn && (rank == 0 || rank == 7) {
+				return Position{}, fmt.Errorf("%w: pawn on rank %d", ErrInvalidFEN, rank+1)
+			}
+			if kind == King {
+				kings[color]++
+			}
+			pos.board[NewSquare(file, rank)] = Piece{Kind: kind, Color: color}
+			file++
+		}
+		if file != 8 {
+			return Position{}, fmt.Errorf("%w: rank %d does not have 8 squares", ErrInvalidFEN, rank+1)
+		}
+	}
+	if kings != [2]int{1, 1} {
+		return Position{}, fmt.Errorf("%w: each side needs exactly one king", ErrInvalidFEN)
+	}
+
+	switch fields[1] {
+	case "w":
+		pos.Turn = White
+	case "b":
+		pos.Turn = Black
+	default:
+		return Position{}, fmt.Errorf("%w: unknown side to move %q", ErrInvalidFEN, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			i := strings.IndexRune("KQkq", c)
+			if i < 0 {
+				return Position{}, fmt.Errorf("%w: unknown castling right %q", ErrInvalidFEN, c)
+			}
+			pos.Castling |= 1 << i
+		}
+		pos.Castling &= pos.possibleCastling()
+	}
+
+	if fields[3] != "-" {
+		sq, ok := ParseSquare(fields[3])
+		if !ok || (sq.Rank() != 2 && sq.Rank() != 5) {
+			return Position{}, fmt.Errorf("%w: invalid en passant square %q", ErrInvalidFEN, fields[3])
+		}
+		pos.EnPassant = sq
+	}
+
+	if len(fields) == 6 {
+		var err error
+		if pos.HalfmoveClock, err = strconv.Atoi(fields[4]); err != nil || pos.HalfmoveClock < 0 {
+			return Position{}, fmt.Errorf("%w: invalid halfmove clock %q", ErrInvalidFEN, fields[4])
+		}
+		if pos.FullmoveNumber, err = strconv.Atoi(fields[5]); err != nil || pos.FullmoveNumber < 1 {
+			return Position{}, fmt.Errorf("%w: invalid fullmove number %q", ErrInvalidFEN, fields[5])
+		}
+	}
+
+	if pos.attacked(pos.king(pos.Turn.Other()), pos.Turn) {
+		return Position{}, fmt.Errorf("%w: the side not to move is in check", ErrInvalidFEN)
+	}
+
+	return pos, nil
+}
+
+// possibleCastling drops the rights whose king or rook is not on its square
+func (p Position) possibleCastling() CastlingRights {
+	var rights CastlingRights
+	rook := func(sq Square, c Color) bool { return p.board[sq] == Piece{Kind: Rook, Color: c} }
+	if p.board[4] == (Piece{Kind: King, Color: White}) {
+		if rook(7, White) {
+			rights |= WhiteKingside
+		}
+		if rook(0, White) {
+			rights |= WhiteQueenside
+		}
+	}
+	if p.board[60] == (Piece{Kind: King, Color: Black}) {
+		if rook(63, Black) {
+			rights |= BlackKingside
+		}
+		if rook(56, Black) {
+			rights |= BlackQueenside
+		}
+	}
+	return rights
+}
+
+// FEN writes the position in Forsyth-Edwards Notation
+func (p Position) FEN() string {
+	return fmt.Sprintf("%s %d %d", p.key(), p.HalfmoveClock, p.FullmoveNumber)
+}
+
+// key is the fen without the move counters, positions with the same key are the same
+// position for the repetition rules
+func (p Position) key() string {
+	var b strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := p.board[NewSquare(file, rank)]
+			if piece.Empty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			b.WriteByte(piece.letter())
+		}
+		if empty > 0 {
+			b.WriteByte(byte('0' + empty))
+		}
+		if rank > 0 {
+			b.WriteByte('/')
+		}
+	}
+
+	b.WriteByte(' ')
+	if p.Turn == White {
+		b.WriteByte('w')
+	} else {
+		b.WriteByte('b')
+	}
+
+	b.WriteByte(' ')
+	if p.Castling == 0 {
+		b.WriteByte('-')
+	}
+	for i, c := range "KQkq" {
+		if p.Castling&(1<<i) != 0 {
+			b.WriteRune(c)
+		}
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(p.EnPassant.String())
+
+	return b.String()
+}
+
+func (p Position) king(c Color) Square {
+	for sq := Square(0); sq < 64; sq++ {
+		if p.board[sq] == (Piece{Kind: King, Color: c}) {
+			return sq
+		}
+	}
+	return NoSquare
+}
+
+// InCheck reports whether the side to move is in check
+func (p Position) InCheck() bool {
+	return p.attacked(p.king(p.Turn), p.Turn.Other())
+}
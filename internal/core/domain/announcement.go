@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChatExportFormat       = errors.New("unsupported chat export format")
+	ErrAnnouncementNotFound   = errors.New("announcement not found")
+	ErrAnnouncementNotPending = errors.New("the announcement was already reviewed")
+	ErrAnnouncementIncomplete = errors.New("the announcement needs at least a name and a start date to become a tournament")
+)
+
+// ChatExportFormat - the app a chat export file comes from
+type ChatExportFormat string
+
+const (
+	ChatExportWhatsApp ChatExportFormat = "whatsapp" // the .txt of "export chat"
+	ChatExportTelegram ChatExportFormat = "telegram" // the result.json of telegram desktop
+)
+
+func (cf ChatExportFormat) Valid() bool {
+	return cf == ChatExportWhatsApp || cf == ChatExportTelegram
+}
+
+// ChatExportFile describes a local chat export file, Location is where the chat
+// happened as whatsapp writes the times without a zone
+type ChatExportFile struct {
+	Path     string
+	Format   ChatExportFormat // empty to tell by the extension of the file
+	Location *time.Location
+}
+
+// ChatMessage is one message of an exported chat, system and media messages are left out
+type ChatMessage struct {
+	Author string
+	SentAt time.Time
+	Text   string
+}
+
+// ChatExport is what a chat export file holds, Chat is the name of the group when the
+// export says it, whatsapp only puts it in the file name
+type ChatExport struct {
+	Format   ChatExportFormat
+	Chat     string
+	Messages []ChatMessage
+}
+
+// AnnouncementStatus - where an announcement is in the review queue
+type AnnouncementStatus string
+
+const (
+	AnnouncementPending   AnnouncementStatus = "pending"
+	AnnouncementConfirmed AnnouncementStatus = "confirmed" // a draft tournament was created from it
+	AnnouncementRejected  AnnouncementStatus = "rejected"
+)
+
+func (as AnnouncementStatus) Valid() bool {
+	switch as {
+	case AnnouncementPending, AnnouncementConfirmed, AnnouncementRejected:
+		return true
+	}
+	return false
+}
+
+// Announcement is a chat message that looks like the announcement of a chess event,
+// with the details the rules could read from it. An organizer reviews it before it
+// becomes a draft tournament, every field but the message itself can be wrong or empty
+type Announcement struct {
+	ID           int // private
+	PublicID     uuid.UUID
+	Source       ChatExportFormat
+	Chat         string
+	Author       string
+	PostedAt     time.Time
+	Text         string
+	Fingerprint  string // see AnnouncementFingerprint
+	Language     string // es or ca, empty when it could not tell
+	Title        string
+	Dates        []time.Time // every date mentioned, oldest first
+	StartsAt     time.Time   // the first date, at the time mentioned if any
+	HasTime      bool        // false when only the day of StartsAt is known
+	Venue        string
+	Address      string
+	City         string
+	Timezone     Timezone // the one the dates were read in
+	FeeCents     int64
+	Free         bool
+	Phones       []string // +34 and the nine digits
+	TimeControl  string   // as written, e.g. "90+30"
+	Status       AnnouncementStatus
+	TournamentID uuid.UUID // the draft created on confirmation
+	ReviewNote   string
+	ReviewedAt   time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// AnnouncementFilter narrows a listing of announcements, zero values match everything
+type AnnouncementFilter struct {
+	Status AnnouncementStatus
+}
+
+// Matches reports whether the announcement passes the filter
+func (f AnnouncementFilter) Matches(a Announcement) bool {
+	return f.Status == "" || a.Status == f.Status
+}
+
+// AnnouncementFingerprint identifies the text of an announcement regardless of case,
+// accents, emojis and spacing, the same message forwarded to several groups or
+// imported twice has the same fingerprint
+func AnnouncementFingerprint(text string) string {
+	sum := sha256.Sum256([]byte(NormalizeName(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ChatImportSummary reports what an import of a chat export did
+type ChatImportSummary struct {
+	Format        ChatExportFormat
+	Chat          string
+	Messages      int
+	Announcements int // new ones in the review queue
+	Duplicates    int // already in the queue from an earlier import or message
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Role is what the bearer token of a request lets it do
+type Role string
+
+const (
+	RoleConsumer  Role = "consumer"  // an api consumer, it manages what it owns such as its webhooks
+	RoleModerator Role = "moderator" // decides on the reported and held listings
+	RoleAdmin     Role = "admin"     // the operator of the server
+	RolePlayer    Role = "player"    // a player, acting on their own behalf
+	RoleArbiter   Role = "arbiter"   // an arbiter, or a board, relaying the games
+)
+
+// Principal is who a request is authenticated as, ID is the consumer id of a consumer, the
+// name of a moderator or an arbiter and the public id of a player
+type Principal struct {
+	Role Role
+	ID   string
+}
+
+// ActsFor tells whether the principal may act on behalf of the player, the player themself
+// or the admin
+func (p Principal) ActsFor(playerID uuid.UUID) bool {
+	switch p.Role {
+	case RoleAdmin:
+		return true
+	case RolePlayer:
+		return playerID != uuid.Nil && p.ID == playerID.String()
+	default:
+		return false
+	}
+}
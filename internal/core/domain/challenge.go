@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChallengeNotFound         = errors.New("challenge not found")
+	ErrChallengeNotOpen          = errors.New("the challenge is no longer open")
+	ErrChallengeOwn              = errors.New("a player cannot accept their own challenge")
+	ErrChallengeNotOwner         = errors.New("only the player who posted the challenge can cancel it")
+	ErrChallengeRatingOutOfRange = errors.New("the rating of the player is outside the range of the challenge")
+	ErrChallengeWindowPassed     = errors.New("the time window of the challenge is already over")
+)
+
+type ChallengeStatus string
+
+const (
+	ChallengeStatusOpen      ChallengeStatus = "open"
+	ChallengeStatusAccepted  ChallengeStatus = "accepted"
+	ChallengeStatusCancelled ChallengeStatus = "cancelled"
+	ChallengeStatusExpired   ChallengeStatus = "expired" // nobody accepted it before its window was over
+)
+
+func (cs ChallengeStatus) Valid() bool {
+	switch cs {
+	case ChallengeStatusOpen, ChallengeStatusAccepted, ChallengeStatusCancelled, ChallengeStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Challenge is an open invitation to a casual over the board game. The challenger plays at
+// the location, or anywhere within RadiusKm of it, some time between StartsAt and EndsAt.
+// Only one player can accept it, the match is created when they do
+type Challenge struct {
+	ID           int // private
+	PublicID     uuid.UUID
+	ChallengerID uuid.UUID
+	Location     Location // the coordinates are what the distance is measured from
+	RadiusKm     float64  // 0 when the game is played at the location
+	StartsAt     time.Time
+	EndsAt       time.Time // the challenge expires when nobody accepted it by then
+	TimeControl  TimeControl
+	Rated        bool
+	MinRating    int // of the players that can accept it in the pool of the time control, 0 for no bound
+	MaxRating    int
+	Note         string
+	Status       ChallengeStatus
+	ConsumerID   string // the api consumer that posted it
+	Moderation   ModerationStatus
+	AcceptedBy   uuid.UUID // uuid.Nil until accepted
+	MatchID      uuid.UUID // the match created on acceptance
+	AcceptedAt   time.Time
+	CancelledAt  time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// StatusAt returns the status of the challenge at now, an open challenge whose window is
+// over is expired even before it is stored as such
+func (c Challenge) StatusAt(now time.Time) ChallengeStatus {
+	if c.Status == ChallengeStatusOpen && !now.Before(c.EndsAt) {
+		return ChallengeStatusExpired
+	}
+	return c.Status
+}
+
+// RatingPool is the pool the ratings of the challenge are checked in, and the one the match
+// counts towards when rated
+func (c Challenge) RatingPool() RatingType {
+	return c.TimeControl.Category().RatingType()
+}
+
+// HasRatingRange reports whether only the players of some ratings can accept the challenge
+func (c Challenge) HasRatingRange() bool {
+	return c.MinRating > 0 || c.MaxRating > 0
+}
+
+// AdmitsRating reports whether a player of the rating can accept the challenge
+func (c Challenge) AdmitsRating(rating int) bool {
+	if c.MinRating > 0 && rating < c.MinRating {
+		return false
+	}
+	if c.MaxRating > 0 && rating > c.MaxRating {
+		return false
+	}
+	return true
+}
+
+// CanAccept returns why the player cannot accept the challenge at now, nil when they can
+func (c Challenge) CanAccept(playerID uuid.UUID, rating int, now time.Time) error {
+	if c.StatusAt(now) != ChallengeStatusOpen || !c.Moderation.Public() {
+		return ErrChallengeNotOpen
+	}
+	if c.ChallengerID == playerID {
+		return ErrChallengeOwn
+	}
+	if !c.AdmitsRating(rating) {
+		return ErrChallengeRatingOutOfRange
+	}
+	return nil
+}
+
+// Reaches reports whether a player around the coordinates, willing to travel radiusKm, can
+// meet the challenger
+func (c Challenge) Reaches(latitude, longitude, radiusKm float64) bool {
+	return DistanceKm(c.Location.Latitude, c.Location.Longitude, latitude, longitude) <= c.RadiusKm+radiusKm
+}
+
+// ChallengeFilter narrows a listing of challenges, zero values match everything
+type ChallengeFilter struct {
+	Status       ChallengeStatus
+	ChallengerID uuid.UUID
+	Moderation   ModerationStatus
+}
+
+// Matches reports whether the challenge passes the filter
+func (f ChallengeFilter) Matches(c Challenge) bool {
+	if f.Status != "" && c.Status != f.Status {
+		return false
+	}
+	if f.ChallengerID != uuid.Nil && c.ChallengerID != f.ChallengerID {
+		return false
+	}
+	if f.Moderation != "" && c.Moderation != f.Moderation {
+		return false
+	}
+	return true
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm is the great circle distance between two coordinates
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat2 - lat1)
+	dLng := rad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
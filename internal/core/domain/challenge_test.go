@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name       string
+		lat1, lng1 float64
+		lat2, lng2 float64
+		want       float64
+	}{
+		{name: "same place", lat1: 41.3870, lng1: 2.1701, lat2: 41.3870, lng2: 2.1701, want: 0},
+		{name: "barcelona to madrid", lat1: 41.3870, lng1: 2.1701, lat2: 40.4168, lng2: -3.7038, want: 505},
+		{name: "across the antimeridian", lat1: 0, lng1: 179.5, lat2: 0, lng2: -179.5, want: 111},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistanceKm(tt.lat1, tt.lng1, tt.lat2, tt.lng2); math.Abs(got-tt.want) > 1 {
+				t.Errorf("expected %.0f km, got %.1f", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChallenge_CanAccept(t *testing.T) {
+	now := time.Date(2026, 6, 12, 17, 0, 0, 0, time.UTC)
+	challenger, player := uuid.New(), uuid.New()
+	open := Challenge{ChallengerID: challenger, Status: ChallengeStatusOpen, Moderation: ModerationApproved, EndsAt: now.Add(time.Hour), MinRating: 1400, MaxRating: 1800}
+	pending := open
+	pending.Moderation = ModerationPending
+
+	tests := []struct {
+		name      string
+		challenge Challenge
+		player    uuid.UUID
+		rating    int
+		at        time.Time
+		want      error
+	}{
+		{name: "open", challenge: open, player: player, rating: 1600, at: now},
+		{name: "own", challenge: open, player: challenger, rating: 1600, at: now, want: ErrChallengeOwn},
+		{name: "below the range", challenge: open, player: player, rating: 1399, at: now, want: ErrChallengeRatingOutOfRange},
+		{name: "above the range", challenge: open, player: player, rating: 1801, at: now, want: ErrChallengeRatingOutOfRange},
+		{name: "window over", challenge: open, player: player, rating: 1600, at: now.Add(time.Hour), want: ErrChallengeNotOpen},
+		{name: "waiting for approval", challenge: pending, player: player, rating: 1600, at: now, want: ErrChallengeNotOpen},
+		{name: "cancelled", challenge: Challenge{ChallengerID: challenger, Status: ChallengeStatusCancelled, EndsAt: now.Add(time.Hour)}, player: player, at: now, want: ErrChallengeNotOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.challenge.CanAccept(tt.player, tt.rating, tt.at); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
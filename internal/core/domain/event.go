@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+
+// EventType - what happened, it is also the name subscribers and clients see
+type EventType string
+
+const (
+	EventTournamentCreated   EventType = "tournament.created"
+	EventPlayerRegistered    EventType = "tournament.player_registered"
+	EventRoundPaired         EventType = "tournament.round_paired"
+	EventResultRecorded      EventType = "match.result_recorded"
+	EventTournamentCompleted EventType = "tournament.completed"
+	EventChallengeAccepted   EventType = "challenge.accepted"
+)
+
+// EventTypes are all the event types
+var EventTypes = []EventType{
+	EventTournamentCreated,
+	EventPlayerRegistered,
+	EventRoundPaired,
+	EventResultRecorded,
+	EventTournamentCompleted,
+	EventChallengeAccepted,
+}
+
+func (et EventType) Valid() bool {
+	for _, t := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened to an aggregate. Events of the same aggregate are
+// delivered in the order they were recorded
+type Event interface {
+	EventType() EventType
+	AggregateID() uuid.UUID
+	OccurredAt() time.Time
+}
+
+// TournamentCreated is recorded when a tournament is created
+type TournamentCreated struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Name         string    `json:"name"`
+	TimeControl  string    `json:"time_control"`
+	At           time.Time `json:"at"`
+}
+
+func (e TournamentCreated) EventType() EventType   { return EventTournamentCreated }
+func (e TournamentCreated) AggregateID() uuid.UUID { return e.TournamentID }
+func (e TournamentCreated) OccurredAt() time.Time  { return e.At }
+
+// PlayerRegistered is recorded when a player registers for a tournament
+type PlayerRegistered struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	PlayerID     uuid.UUID `json:"player_id"`
+	At           time.Time `json:"at"`
+}
+
+func (e PlayerRegistered) EventType() EventType   { return EventPlayerRegistered }
+func (e PlayerRegistered) AggregateID() uuid.UUID { return e.TournamentID }
+func (e PlayerRegistered) OccurredAt() time.Time  { return e.At }
+
+// RoundPaired is recorded when the matches of a round of a tournament are paired
+type RoundPaired struct {
+	TournamentID uuid.UUID   `json:"tournament_id"`
+	Round        int         `json:"round"`
+	Matches      []uuid.UUID `json:"matches"`
+	At           time.Time   `json:"at"`
+}
+
+func (e RoundPaired) EventType() EventType   { return EventRoundPaired }
+func (e RoundPaired) AggregateID() uuid.UUID { return e.TournamentID }
+func (e RoundPaired) OccurredAt() time.Time  { return e.At }
+
+// ResultRecorded is recorded when the result of a match is recorded or corrected,
+// Previous is the result it had before
+type ResultRecorded struct {
+	MatchID      uuid.UUID   `json:"match_id"`
+	TournamentID uuid.UUID   `json:"tournament_id,omitempty"` // uuid.Nil for casual games
+	WhitePlayer  uuid.UUID   `json:"white_player"`
+	BlackPlayer  uuid.UUID   `json:"black_player"`
+	Result       MatchResult `json:"result"`
+	Previous     MatchResult `json:"previous,omitempty"`
+	Rated        bool        `json:"rated"`
+	At           time.Time   `json:"at"`
+}
+
+func (e ResultRecorded) EventType() EventType { return EventResultRecorded }
+
+// AggregateID is the tournament of the match so the results of a tournament are delivered
+// in order with its other events, the match itself for casual games
+func (e ResultRecorded) AggregateID() uuid.UUID {
+	if e.TournamentID != uuid.Nil {
+		return e.TournamentID
+	}
+	return e.MatchID
+}
+
+func (e ResultRecorded) OccurredAt() time.Time { return e.At }
+
+// TournamentCompleted is recorded when a tournament is completed
+type TournamentCompleted struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Actor        string    `json:"actor"`
+	At           time.Time `json:"at"`
+}
+
+func (e TournamentCompleted) EventType() EventType   { return EventTournamentCompleted }
+func (e TournamentCompleted) AggregateID() uuid.UUID { return e.TournamentID }
+func (e TournamentCompleted) OccurredAt() time.Time  { return e.At }
+
+// ChallengeAccepted is recorded when a player accepts an open challenge and their match is
+// created
+type ChallengeAccepted struct {
+	ChallengeID  uuid.UUID `json:"challenge_id"`
+	MatchID      uuid.UUID `json:"match_id"`
+	ChallengerID uuid.UUID `json:"challenger_id"`
+	AcceptedBy   uuid.UUID `json:"accepted_by"`
+	At           time.Time `json:"at"`
+}
+
+func (e ChallengeAccepted) EventType() EventType   { return EventChallengeAccepted }
+func (e ChallengeAccepted) AggregateID() uuid.UUID { return e.ChallengeID }
+func (e ChallengeAccepted) OccurredAt() time.Time  { return e.At }
+
+// OutboxEntry is an event waiting in the outbox to be delivered to the subscribers
+type OutboxEntry struct {
+	ID            int64 // sequence, the order the events were recorded in
+	EventID       uuid.UUID
+	Event         Event
+	Attempts      int       // failed deliveries so far
+	NextAttemptAt time.Time // not delivered again before
+	Delivered     []string  // names of the subscribers that handled it, they are not called again on a retry
+	LastError     string
+	CreatedAt     time.Time
+	DispatchedAt  time.Time // every subscriber handled it
+	DeadAt        time.Time // given up on after too many attempts
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFideRecordNotFound    = errors.New("fide record not found")
+	ErrFideListFormat        = errors.New("unsupported fide rating list format")
+	ErrFidePlayerNotLinked   = errors.New("player is not linked to a fide id")
+	ErrFideAlreadyLinked     = errors.New("the fide id is already linked to another player")
+	ErrFidePeriodNotImported = errors.New("no fide rating list imported for the rating period")
+)
+
+// FideProfileURL is where the official profile of a fide id lives
+const FideProfileURL = "https://ratings.fide.com/profile/%d"
+
+// FideListFormat - the format of an official fide rating list file
+type FideListFormat string
+
+const (
+	FideListFormatXML FideListFormat = "xml"
+	FideListFormatTXT FideListFormat = "txt"
+)
+
+// FideListFile describes a local rating list file
+type FideListFile struct {
+	Path   string
+	Format FideListFormat
+	// SingleRatingType is what the rating column means in lists that only carry one
+	// rating, e.g. the rapid only list. Defaults to standard
+	SingleRatingType RatingType
+}
+
+// FideRecord is one player of an official fide rating list, Period is the
+// rating period the list was published for, e.g. "2026-10"
+type FideRecord struct {
+	FideID     int
+	Name       string // "Surname, Firstname" as published by fide
+	Federation string
+	Sex        string
+	Title      string
+	BirthYear  int
+	Ratings    Ratings
+	Period     string
+}
+
+// ProfileURL returns the official fide profile of the record
+func (fr FideRecord) ProfileURL() string {
+	return fmt.Sprintf(FideProfileURL, fr.FideID)
+}
+
+// FideImportSummary reports what an import of a rating list did
+type FideImportSummary struct {
+	Period   string
+	Read     int
+	Inserted int
+	Updated  int
+	Skipped  int
+}
+
+// FideMatchCandidate is a fide record that could be the same person as a maple player,
+// Score goes from 0 (nothing in common) to 1 (identical)
+type FideMatchCandidate struct {
+	Record FideRecord
+	Score  float64
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "errors"
+
+var (
+	ErrGeocodeNotFound     = errors.New("no place matches the address")
+	ErrGeocoderUnavailable = errors.New("geocoding is not configured")
+	ErrGazetteerFormat     = errors.New("not a geonames gazetteer file")
+)
+
+// GeocodePrecision is how much of the address a place was resolved from
+type GeocodePrecision string
+
+const (
+	GeocodePrecisionStreet     GeocodePrecision = "street"
+	GeocodePrecisionPostalCode GeocodePrecision = "postal_code"
+	GeocodePrecisionCity       GeocodePrecision = "city"
+)
+
+// GeocodeQuery is what is known of an address, every field is optional but at least one is needed
+type GeocodeQuery struct {
+	Address    string // free text, e.g. "Carrer de Provençals 40, 08019 Barcelona"
+	PostalCode string
+	City       string
+	Province   string
+	Country    string // ISO 3166-1 alpha-2 code, e.g. ES
+}
+
+// Empty reports whether there is nothing to geocode
+func (q GeocodeQuery) Empty() bool {
+	return q.Address == "" && q.PostalCode == "" && q.City == ""
+}
+
+// Place is an address resolved to coordinates and the canonical names of its regions. In
+// spain the county is the comarca, the province the provincia and the state the comunidad
+type Place struct {
+	Name       string // the street or the populated place that matched
+	PostalCode string
+	City       string // the municipality
+	County     string
+	Province   string
+	State      string
+	Country    string // ISO 3166-1 alpha-2 code
+	Latitude   float64
+	Longitude  float64
+	Timezone   Timezone
+	Precision  GeocodePrecision
+}
+
+// Fill completes the location with the values of the place it is missing
+func (p Place) Fill(l Location) Location {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&l.City, p.City)
+	fill(&l.PostalCode, p.PostalCode)
+	fill(&l.County, p.County)
+	fill(&l.Province, p.Province)
+	fill(&l.State, p.State)
+	fill(&l.Country, p.Country)
+	if l.Timezone == "" {
+		l.Timezone = p.Timezone
+	}
+	if !l.HasCoordinates() {
+		l.Latitude, l.Longitude = p.Latitude, p.Longitude
+	}
+	return l
+}
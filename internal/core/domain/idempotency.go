@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyInFlight = errors.New("a request with the idempotency key is in flight")
+	ErrIdempotencyKeyReused   = errors.New("the idempotency key was used for a different request")
+)
+
+// IdempotentResponse is the response of the first request of a key, it is replayed to its retries
+type IdempotentResponse struct {
+	Status int
+	Header map[string][]string
+	Body   []byte
+}
+
+// IdempotencyRecord is an Idempotency-Key in use. Key is scoped to the consumer and the route of
+// the request, Fingerprint is the hash of its body so a retry can be told from another request
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Response    *IdempotentResponse // nil while the first request is in flight
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed tells whether the first request of the key got its response
+func (ir IdempotencyRecord) Completed() bool {
+	return ir.Response != nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrImportFileNotFound is returned for the files of an import that are not in the import
+	// directory of the configuration, whether they do not exist or lead out of it
+	ErrImportFileNotFound = errors.New("the file is not in the import directory")
+	ErrImportJobNotFound  = errors.New("import job not found")
+)
+
+// ImportJobStatus is how far an import running in the background got
+type ImportJobStatus string
+
+const (
+	ImportJobQueued  ImportJobStatus = "queued"
+	ImportJobRunning ImportJobStatus = "running"
+	ImportJobDone    ImportJobStatus = "done"
+	ImportJobFailed  ImportJobStatus = "failed"
+)
+
+// ImportJob is an import run in the background, Summary is what it did once done and Error
+// why it failed
+type ImportJob[S any] struct {
+	ID         uuid.UUID
+	Status     ImportJobStatus
+	Summary    S
+	Error      string
+	QueuedAt   time.Time
+	StartedAt  time.Time // zero while queued
+	FinishedAt time.Time // zero until done or failed
+}
+
+// Finished tells whether the import is done or failed
+func (j ImportJob[S]) Finished() bool {
+	return j.Status == ImportJobDone || j.Status == ImportJobFailed
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTournamentNotOpenToSpectators = errors.New("the tournament is not open to spectators")
+
+// LiveStandings is the type of the live updates carrying the standings of a tournament,
+// the other updates are typed by the event they relay
+const LiveStandings = "tournament.standings"
+
+// LiveUpdate is what the spectators following a tournament live receive
+type LiveUpdate struct {
+	TournamentID uuid.UUID
+	Type         string
+	Data         any // marshalled to json
+	At           time.Time
+}
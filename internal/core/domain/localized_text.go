@@ -0,0 +1,14 @@
+package domain
+
+import "github.com/ctfrancia/maple/internal/core/i18n"
+
+// LocalizedText holds the translations of a user written text, e.g. a tournament description
+type LocalizedText map[i18n.Locale]string
+
+// In returns the text in the locale, the fallback when there is no translation for it
+func (lt LocalizedText) In(locale i18n.Locale, fallback string) string {
+	if text, ok := lt[locale]; ok && text != "" {
+		return text
+	}
+	return fallback
+}
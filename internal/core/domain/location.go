@@ -1,6 +1,19 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLocationNotFound  = errors.New("location not found")
+	ErrLocationDuplicate = errors.New("a similar location is already registered")
+	ErrLocationInUse     = errors.New("the location is referenced by tournaments or matches")
+	ErrLocationMergeSelf = errors.New("a location cannot be merged into itself")
+)
 
 type Timezone string
 
@@ -11,20 +24,72 @@ const (
 	TimezoneEST Timezone = "EST"
 )
 
-// Location represents a location in the world
+// Location represents a location in the world. The ones in the registry have a PublicID,
+// tournaments and matches keep a copy of the registered location they reference
 type Location struct {
 	ID         int
-	PublicID   uuid.UUID
+	PublicID   uuid.UUID // uuid.Nil for the places that are not in the registry
 	ClubAffil  *Club
 	Name       string
 	Address    string
 	PostalCode string
-	City       string // have a city db
-	State      string // have a state db
-	County     string // have a county db
-	Province   string // have a province db
-	Country    string // have a country db
+	City       string // the registered ones take the canonical name from the geocoder
+	State      string
+	County     string // the comarca in catalonia
+	Province   string
+	Country    string // ISO 3166-1 alpha-2 code when geocoded
 	Latitude   float64
 	Longitude  float64
 	Timezone   Timezone
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// HasCoordinates reports whether the location was given a position, 0,0 is in the ocean
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// Registered reports whether the location is a reference to the registry
+func (l Location) Registered() bool {
+	return l.PublicID != uuid.Nil
+}
+
+// LocationFilter narrows a listing of locations, zero values match everything
+type LocationFilter struct {
+	City    string
+	Country string
+	Query   string // part of the name or the address
+}
+
+// Matches reports whether the location passes the filter, the comparisons ignore case and accents
+func (f LocationFilter) Matches(l Location) bool {
+	if f.City != "" && NormalizeName(l.City) != NormalizeName(f.City) {
+		return false
+	}
+	if f.Country != "" && NormalizeName(l.Country) != NormalizeName(f.Country) {
+		return false
+	}
+	if f.Query != "" {
+		query := NormalizeName(f.Query)
+		if !strings.Contains(NormalizeName(l.Name), query) && !strings.Contains(NormalizeName(l.Address), query) {
+			return false
+		}
+	}
+	return true
+}
+
+// LocationDuplicate is a registered location that looks like the same place as another one
+type LocationDuplicate struct {
+	Location   Location
+	Score      float64 // from 0 to 1
+	DistanceKm float64 // -1 when either location has no coordinates
+}
+
+// LocationMerge is the result of merging a duplicate into the location that is kept
+type LocationMerge struct {
+	Location    Location  // the kept location, with the details only the duplicate had
+	Merged      uuid.UUID // the public ID of the removed duplicate
+	Tournaments int       // re-pointed to the kept location
+	Matches     int
 }
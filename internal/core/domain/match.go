@@ -1,25 +1,105 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// Match represents a match between two players
+var (
+	ErrMatchNotFound      = errors.New("match not found")
+	ErrMatchSamePlayer    = errors.New("a player cannot play against themselves")
+	ErrMatchResultInvalid = errors.New("the match result is not a final result")
+)
+
+// MatchResult - the result of a match in PGN notation
+type MatchResult string
+
+const (
+	MatchResultWhiteWins MatchResult = "1-0"
+	MatchResultBlackWins MatchResult = "0-1"
+	MatchResultDraw      MatchResult = "1/2-1/2"
+	MatchResultOngoing   MatchResult = "*"
+)
+
+// Finished reports whether the result is a final one
+func (mr MatchResult) Finished() bool {
+	switch mr {
+	case MatchResultWhiteWins, MatchResultBlackWins, MatchResultDraw:
+		return true
+	}
+	return false
+}
+
+// Match represents a match between two players, players are referenced by their public ID
+// so the same player is shared across every tournament and match
 type Match struct {
 	ID           int // private
 	UUID         uuid.UUID
-	TournamentID Tournament
-	Winner       Player
+	TournamentID uuid.UUID // uuid.Nil for casual games
 	Location     Location
 	City         string
 	State        string
 	Country      string
 	Rated        bool
-	WhitePlayer  Player
-	BlackPlayer  Player
-	PGN          string // Portable Game Notation
+	RatingType   RatingType // the maple rating pool a rated match counts towards
+	TimeControl  TimeControl
+	WhitePlayer  uuid.UUID
+	BlackPlayer  uuid.UUID
+	Result       MatchResult
+	PGN          string    // Portable Game Notation
+	CompletedAt  time.Time // zero until a final result is recorded
+	Moderation   ModerationStatus
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+// Between reports whether the match was played between a and b, regardless of colour
+func (m Match) Between(a, b uuid.UUID) bool {
+	return (m.WhitePlayer == a && m.BlackPlayer == b) || (m.WhitePlayer == b && m.BlackPlayer == a)
+}
+
+// WinnerID returns the public ID of the winner, uuid.Nil for draws and unfinished matches
+func (m Match) WinnerID() uuid.UUID {
+	switch m.Result {
+	case MatchResultWhiteWins:
+		return m.WhitePlayer
+	case MatchResultBlackWins:
+		return m.BlackPlayer
+	}
+	return uuid.Nil
+}
+
+// MatchFilter narrows a listing of matches, zero values match everything
+type MatchFilter struct {
+	PlayerID     uuid.UUID
+	TournamentID uuid.UUID
+	Category     TimeControlCategory
+	TimeControl  TimeControl // only matches played at exactly this time control
+	Moderation   ModerationStatus
+	LocationID   uuid.UUID // only matches played at the registered location
+}
+
+// Matches reports whether the match passes the filter
+func (f MatchFilter) Matches(m Match) bool {
+	if f.PlayerID != uuid.Nil && m.WhitePlayer != f.PlayerID && m.BlackPlayer != f.PlayerID {
+		return false
+	}
+	if f.TournamentID != uuid.Nil && m.TournamentID != f.TournamentID {
+		return false
+	}
+	if f.Category != "" && m.TimeControl.Category() != f.Category {
+		return false
+	}
+	if !f.TimeControl.IsZero() && !m.TimeControl.Equal(f.TimeControl) {
+		return false
+	}
+	if f.Moderation != "" && m.Moderation != f.Moderation {
+		return false
+	}
+	if f.LocationID != uuid.Nil && m.Location.PublicID != f.LocationID {
+		return false
+	}
+	return true
+}
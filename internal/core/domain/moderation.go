@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportDuplicate      = errors.New("the reporter already has an open report of the subject")
+	ErrConsumerSuspended    = errors.New("the consumer is suspended and cannot post listings")
+	ErrModerationSubject    = errors.New("unknown moderation subject")
+	ErrConsumerNotSuspended = errors.New("the consumer is not suspended")
+	ErrConsumerRequired     = errors.New("a listing needs the consumer posting it")
+)
+
+// ModerationStatus - whether content is shown in the public listings
+type ModerationStatus string
+
+const (
+	ModerationApproved ModerationStatus = "approved"
+	ModerationPending  ModerationStatus = "pending" // waiting for a moderator before going public
+	ModerationRejected ModerationStatus = "rejected"
+	ModerationHidden   ModerationStatus = "hidden" // was public until a moderator took it down
+)
+
+// Public reports whether the content can be listed
+func (ms ModerationStatus) Public() bool {
+	return ms == ModerationApproved
+}
+
+// ApprovalMode - which new listings wait for a moderator before going public
+type ApprovalMode string
+
+const (
+	ApprovalOff ApprovalMode = "off" // every listing is public right away
+	ApprovalNew ApprovalMode = "new" // until a moderator approved enough listings of the consumer
+	ApprovalAll ApprovalMode = "all"
+)
+
+func (am ApprovalMode) Valid() bool {
+	switch am {
+	case ApprovalOff, ApprovalNew, ApprovalAll:
+		return true
+	}
+	return false
+}
+
+// ModerationAction - what a moderator does with a subject
+type ModerationAction string
+
+const (
+	ModerationApprove ModerationAction = "approve"
+	ModerationReject  ModerationAction = "reject"
+	ModerationHide    ModerationAction = "hide"
+)
+
+func (ma ModerationAction) Valid() bool {
+	switch ma {
+	case ModerationApprove, ModerationReject, ModerationHide:
+		return true
+	}
+	return false
+}
+
+// Status is the moderation status the action leaves the subject in
+func (ma ModerationAction) Status() ModerationStatus {
+	switch ma {
+	case ModerationApprove:
+		return ModerationApproved
+	case ModerationReject:
+		return ModerationRejected
+	case ModerationHide:
+		return ModerationHidden
+	}
+	return ""
+}
+
+// SubjectType - the kinds of content that can be reported and moderated
+type SubjectType string
+
+const (
+	SubjectTournament SubjectType = "tournament"
+	SubjectMatch      SubjectType = "match"
+	SubjectPlayer     SubjectType = "player"
+	SubjectChallenge  SubjectType = "challenge"
+)
+
+func (st SubjectType) Valid() bool {
+	switch st {
+	case SubjectTournament, SubjectMatch, SubjectPlayer, SubjectChallenge:
+		return true
+	}
+	return false
+}
+
+// ModerationSubject identifies a piece of content
+type ModerationSubject struct {
+	Type SubjectType
+	ID   uuid.UUID
+}
+
+// ReportReason - why something was reported
+type ReportReason string
+
+const (
+	ReportSpam      ReportReason = "spam"
+	ReportOffensive ReportReason = "offensive"
+	ReportIncorrect ReportReason = "incorrect" // wrong dates, venue, results...
+	ReportDuplicate ReportReason = "duplicate"
+	ReportOther     ReportReason = "other"
+)
+
+func (rr ReportReason) Valid() bool {
+	switch rr {
+	case ReportSpam, ReportOffensive, ReportIncorrect, ReportDuplicate, ReportOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus - where a report is in the moderation queue
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportUpheld    ReportStatus = "upheld"    // the subject was rejected or hidden
+	ReportDismissed ReportStatus = "dismissed" // the subject was approved
+)
+
+// Report is someone flagging a subject to the moderators
+type Report struct {
+	ID         int // private
+	PublicID   uuid.UUID
+	Subject    ModerationSubject
+	ConsumerID string // who posted the subject, empty when it is not a listing of a consumer
+	ReporterID string // optional, the consumer or player that reported it
+	Reason     ReportReason
+	Details    string
+	Status     ReportStatus
+	ResolvedAt time.Time
+	CreatedAt  time.Time
+}
+
+// ReportFilter narrows a listing of reports, zero values match everything
+type ReportFilter struct {
+	Subject *ModerationSubject
+	Status  ReportStatus
+}
+
+// Matches reports whether the report passes the filter
+func (f ReportFilter) Matches(r Report) bool {
+	if f.Subject != nil && r.Subject != *f.Subject {
+		return false
+	}
+	if f.Status != "" && r.Status != f.Status {
+		return false
+	}
+	return true
+}
+
+// ModerationDecision is the record of a moderator acting on a subject
+type ModerationDecision struct {
+	ID        int // private
+	PublicID  uuid.UUID
+	Subject   ModerationSubject
+	Action    ModerationAction
+	From      ModerationStatus
+	To        ModerationStatus
+	Moderator string
+	Note      string
+	Reports   int // the open reports the decision resolved
+	At        time.Time
+}
+
+// ModerationItem is an entry of the moderators' queue: a listing waiting for approval,
+// a subject with open reports or both
+type ModerationItem struct {
+	Subject    ModerationSubject
+	Title      string
+	ConsumerID string
+	Status     ModerationStatus
+	Reports    []Report // the open ones, oldest first
+	Since      time.Time
+}
+
+// ConsumerStanding is how the moderation of an api consumer's listings has gone, it decides
+// whether their new listings need approval and whether they can post at all
+type ConsumerStanding struct {
+	ConsumerID       string
+	Status           ConsumerStatus // active or suspended
+	ApprovedListings int            // listings a moderator approved
+	UpheldReports    int            // listings hidden or rejected after being reported, however many reports they had
+	SuspendedAt      time.Time
+	SuspendedReason  string
+	UpdatedAt        time.Time
+}
+
+// NewConsumerStanding is the standing of a consumer nothing was moderated for yet
+func NewConsumerStanding(consumerID string) ConsumerStanding {
+	return ConsumerStanding{ConsumerID: consumerID, Status: ConsumerStatusActive}
+}
+
+// ModerationHistory is everything that happened to a subject in the moderation, oldest first
+type ModerationHistory struct {
+	Subject   ModerationSubject
+	Status    ModerationStatus
+	Reports   []Report
+	Decisions []ModerationDecision
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldings replaces the accented letters used in spanish, catalan and the
+// rest of europe with their plain ascii version
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ą': "a",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ś': "s", 'š': "s", 'ş': "s",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'ł': "l", 'ľ': "l",
+	'ř': "r", 'ť': "t", 'ď': "d", 'ğ': "g",
+	'ß': "ss", 'æ': "ae", 'œ': "oe",
+}
+
+// NormalizeName lowercases s, removes accents and replaces punctuation with
+// single spaces so "Club d'Escacs Sant Martí" and "club d escacs sant marti" are equal
+func NormalizeName(s string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if f, ok := foldings[r]; ok {
+			b.WriteString(f)
+			space = false
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// FideNameKey is the blocking key used to look up fide records by name, the first
+// three letters of the normalized surname. surname can be a full "Surname, Firstname"
+func FideNameKey(surname string) string {
+	if i := strings.Index(surname, ","); i >= 0 {
+		surname = surname[:i]
+	}
+
+	key := strings.ReplaceAll(NormalizeName(surname), " ", "")
+	if len(key) > 3 {
+		key = key[:3]
+	}
+
+	return key
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/i18n"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotificationNotFound         = errors.New("notification not found")
+	ErrNotificationPrefsNotFound    = errors.New("notification preferences not found")
+	ErrUnsubscribeTokenInvalid      = errors.New("the unsubscribe link is not valid")
+	ErrNotificationRecipientMissing = errors.New("the player has no email address")
+)
+
+// NotificationKind - why a player is told something, players can opt out of each kind
+type NotificationKind string
+
+const (
+	NotificationRegistrationConfirmed NotificationKind = "registration_confirmed"
+	NotificationRoundReminder         NotificationKind = "round_reminder"
+	NotificationPairingsPublished     NotificationKind = "pairings_published"
+	NotificationChallengeAccepted     NotificationKind = "challenge_accepted"
+)
+
+// NotificationKinds are all the notification kinds
+var NotificationKinds = []NotificationKind{
+	NotificationRegistrationConfirmed,
+	NotificationRoundReminder,
+	NotificationPairingsPublished,
+	NotificationChallengeAccepted,
+}
+
+func (nk NotificationKind) Valid() bool {
+	for _, k := range NotificationKinds {
+		if nk == k {
+			return true
+		}
+	}
+	return false
+}
+
+type NotificationStatus string
+
+const (
+	NotificationPending   NotificationStatus = "pending"
+	NotificationSent      NotificationStatus = "sent"
+	NotificationFailed    NotificationStatus = "failed"    // given up on after too many attempts
+	NotificationCancelled NotificationStatus = "cancelled" // the player opted out before it was sent
+)
+
+// Email is a rendered message ready to be handed to a Notifier
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	Headers map[string]string // e.g. List-Unsubscribe
+}
+
+// Notification is an email waiting in the queue, it is rendered when queued so every retry
+// sends the same message
+type Notification struct {
+	ID            int // private
+	PublicID      uuid.UUID
+	Key           string // identifies what it is about so the same notification is not queued twice
+	Kind          NotificationKind
+	PlayerID      uuid.UUID
+	Locale        i18n.Locale
+	Email         Email
+	Status        NotificationStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time // not sent before, reminders are queued ahead of time
+	SentAt        time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NotificationPreferences are what a player wants to be told about
+type NotificationPreferences struct {
+	PlayerID         uuid.UUID
+	Locale           i18n.Locale        // empty for i18n.DefaultLocale
+	Muted            []NotificationKind // kinds the player opted out of
+	Unsubscribed     bool               // opted out of every email
+	UnsubscribeToken string             // identifies the player in the unsubscribe links
+	UpdatedAt        time.Time
+}
+
+// Wants reports whether the player wants notifications of the kind
+func (np NotificationPreferences) Wants(kind NotificationKind) bool {
+	if np.Unsubscribed {
+		return false
+	}
+	for _, k := range np.Muted {
+		if k == kind {
+			return false
+		}
+	}
+	return true
+}
+
+// EmailLocale is the locale the emails of the player are written in
+func (np NotificationPreferences) EmailLocale() i18n.Locale {
+	if np.Locale == "" {
+		return i18n.DefaultLocale
+	}
+	return np.Locale
+}
@@ -1,6 +1,52 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPlayerNotFound = errors.New("player not found")
+
+// RatingType - the rating list a rating belongs to
+type RatingType string
+
+const (
+	RatingTypeStandard RatingType = "standard"
+	RatingTypeRapid    RatingType = "rapid"
+	RatingTypeBlitz    RatingType = "blitz"
+)
+
+// RatingTypes is every supported rating type, in display order
+var RatingTypes = []RatingType{RatingTypeStandard, RatingTypeRapid, RatingTypeBlitz}
+
+// Valid reports whether rt is one of the supported rating types
+func (rt RatingType) Valid() bool {
+	switch rt {
+	case RatingTypeStandard, RatingTypeRapid, RatingTypeBlitz:
+		return true
+	}
+	return false
+}
+
+// RatingSource - who issued a rating
+type RatingSource string
+
+const (
+	RatingSourceFIDE     RatingSource = "fide"
+	RatingSourceRegional RatingSource = "regional"
+	RatingSourceMaple    RatingSource = "maple"
+)
+
+// Valid reports whether rs is one of the supported rating sources
+func (rs RatingSource) Valid() bool {
+	switch rs {
+	case RatingSourceFIDE, RatingSourceRegional, RatingSourceMaple:
+		return true
+	}
+	return false
+}
 
 // Player represents a player in a tournament/match (human or computer)
 // if they have a public ID, they are a human
@@ -18,17 +64,113 @@ type Player struct {
 	ClubAffiliation Club
 	FIDE            Fide
 	Regional        Regional
+	Moderation      ModerationStatus // a hidden player is left out of the public listings
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Ratings holds one numeric rating per rating type, 0 means unrated
+type Ratings struct {
+	Standard int
+	Rapid    int
+	Blitz    int
+}
+
+// Get returns the rating for the given type
+func (r Ratings) Get(rt RatingType) int {
+	switch rt {
+	case RatingTypeStandard:
+		return r.Standard
+	case RatingTypeRapid:
+		return r.Rapid
+	case RatingTypeBlitz:
+		return r.Blitz
+	}
+	return 0
+}
+
+// Set updates the rating for the given type
+func (r *Ratings) Set(rt RatingType, rating int) {
+	switch rt {
+	case RatingTypeStandard:
+		r.Standard = rating
+	case RatingTypeRapid:
+		r.Rapid = rating
+	case RatingTypeBlitz:
+		r.Blitz = rating
+	}
 }
 
 type Fide struct {
-	Rating string
-	URL    string
-	Title  string
+	ID         int // 0 until the player is linked to a fide record
+	Federation string
+	BirthYear  int
+	Ratings    Ratings
+	URL        string
+	Title      string
 }
 
 type Regional struct {
 	Country string
 	City    string
-	Rating  string
+	Ratings Ratings
 	Title   string
 }
+
+// RatingChange is a single point in a player's rating history,
+// EventID is the tournament or match that caused the change
+type RatingChange struct {
+	ID         int
+	PlayerID   uuid.UUID
+	Source     RatingSource
+	Type       RatingType
+	EventID    uuid.UUID
+	EventName  string
+	Previous   int
+	Rating     int
+	RecordedAt time.Time
+}
+
+// Delta is how many points the player gained (or lost) with this change
+func (rc RatingChange) Delta() int {
+	return rc.Rating - rc.Previous
+}
+
+// HeadToHead is the record of a player against an opponent, computed from
+// their finished matches. Wins/Losses/Draws are from PlayerID's point of view
+type HeadToHead struct {
+	PlayerID   uuid.UUID
+	OpponentID uuid.UUID
+	Wins       int
+	Losses     int
+	Draws      int
+	Matches    []Match
+}
+
+// NewHeadToHead computes the record of player against opponent from the given
+// matches, unfinished matches and matches not between the two are ignored
+func NewHeadToHead(player, opponent uuid.UUID, matches []Match) HeadToHead {
+	h2h := HeadToHead{
+		PlayerID:   player,
+		OpponentID: opponent,
+		Matches:    make([]Match, 0, len(matches)),
+	}
+
+	for _, m := range matches {
+		if !m.Between(player, opponent) || !m.Result.Finished() {
+			continue
+		}
+
+		switch m.WinnerID() {
+		case player:
+			h2h.Wins++
+		case opponent:
+			h2h.Losses++
+		default:
+			h2h.Draws++
+		}
+		h2h.Matches = append(h2h.Matches, m)
+	}
+
+	return h2h
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrRatingNotFound = errors.New("rating not found")
+
+// RatingAlgorithm - the algorithm used by the maple rating engine
+type RatingAlgorithm string
+
+const (
+	RatingAlgorithmElo     RatingAlgorithm = "elo"
+	RatingAlgorithmGlicko2 RatingAlgorithm = "glicko2"
+)
+
+// MapleRating is a player's internal rating in one pool, pools are split per
+// rating type so a blitz game never moves a standard rating
+type MapleRating struct {
+	PlayerID    uuid.UUID
+	Pool        RatingType
+	Rating      float64
+	Deviation   float64 // glicko-2 rating deviation, unused by elo
+	Volatility  float64 // glicko-2 volatility, unused by elo
+	Games       int
+	Provisional bool
+	UpdatedAt   time.Time
+}
+
+// Rounded returns the rating as it is shown to players
+func (mr MapleRating) Rounded() int {
+	return int(math.Round(mr.Rating))
+}
+
+// Score returns white's score for a finished result: 1, 0.5 or 0
+func (mr MatchResult) Score() (float64, bool) {
+	switch mr {
+	case MatchResultWhiteWins:
+		return 1, true
+	case MatchResultBlackWins:
+		return 0, true
+	case MatchResultDraw:
+		return 0.5, true
+	}
+	return 0, false
+}
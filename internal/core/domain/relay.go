@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRelayNotFound       = errors.New("relay not found")
+	ErrRelayAlreadyStarted = errors.New("the match is already relayed")
+	ErrRelayFinished       = errors.New("the relay is finished")
+	ErrRelayOutOfSync      = errors.New("the move does not follow the moves relayed so far")
+	ErrIllegalMove         = errors.New("the move is not legal in the position")
+	ErrMatchFinished       = errors.New("the match already has a result")
+)
+
+// The live updates of the relayed games, published to the feed of their tournament
+const (
+	LiveRelayStarted  = "relay.started"
+	LiveRelayMove     = "relay.move"
+	LiveRelayFinished = "relay.finished"
+)
+
+type RelayStatus string
+
+const (
+	RelayLive     RelayStatus = "live"
+	RelayFinished RelayStatus = "finished"
+)
+
+// RelayTermination is why a relayed game ended, the first ones end it by the rules and the
+// others are reported by the arbiter
+type RelayTermination string
+
+const (
+	RelayCheckmate            RelayTermination = "checkmate"
+	RelayStalemate            RelayTermination = "stalemate"
+	RelayInsufficientMaterial RelayTermination = "insufficient_material"
+	RelayFivefoldRepetition   RelayTermination = "fivefold_repetition"
+	RelaySeventyFiveMoves     RelayTermination = "seventy_five_moves"
+
+	RelayResignation RelayTermination = "resignation"
+	RelayAgreement   RelayTermination = "agreement"
+	RelayTimeout     RelayTermination = "timeout"
+	RelayRepetition  RelayTermination = "threefold_repetition"
+	RelayFiftyMoves  RelayTermination = "fifty_moves"
+	RelayArbiter     RelayTermination = "arbiter_decision"
+)
+
+// RelayReportedTerminations are the terminations an arbiter can finish a relay with
+var RelayReportedTerminations = []RelayTermination{
+	RelayResignation,
+	RelayAgreement,
+	RelayTimeout,
+	RelayRepetition,
+	RelayFiftyMoves,
+	RelayArbiter,
+}
+
+// RelayMove is a move of a relayed game
+type RelayMove struct {
+	Ply        int    // 1 based
+	SAN        string // standard algebraic notation
+	UCI        string
+	FEN        string // of the position after the move
+	WhiteClock time.Duration
+	BlackClock time.Duration
+	At         time.Time
+}
+
+// Relay is a match relayed move by move, e.g. from a dgt board. The clocks are the time
+// left to each player, 0 when the match has no time control
+type Relay struct {
+	MatchID      uuid.UUID
+	TournamentID uuid.UUID // uuid.Nil for casual games
+	TimeControl  TimeControl
+	InitialFEN   string
+	FEN          string // of the current position
+	Moves        []RelayMove
+	WhiteClock   time.Duration
+	BlackClock   time.Duration
+	Status       RelayStatus
+	Result       MatchResult
+	Termination  RelayTermination
+	StartedAt    time.Time
+	LastMoveAt   time.Time // the clock of the side to move runs from here
+	FinishedAt   time.Time
+	UpdatedAt    time.Time
+}
+
+// LastMove returns the last move played, false before the first one
+func (r Relay) LastMove() (RelayMove, bool) {
+	if len(r.Moves) == 0 {
+		return RelayMove{}, false
+	}
+	return r.Moves[len(r.Moves)-1], true
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Standing is the score of a player in a tournament, a win is worth 1 point and a draw 1/2
+type Standing struct {
+	Rank     int       `json:"rank"` // players on the same points share the rank
+	PlayerID uuid.UUID `json:"player_id"`
+	Points   float64   `json:"points"`
+	Played   int       `json:"played"`
+	Wins     int       `json:"wins"`
+	Draws    int       `json:"draws"`
+	Losses   int       `json:"losses"`
+}
+
+// ComputeStandings scores the finished matches, the registered players that did not play yet
+// are listed with no points. Ties are broken by wins and then by fewer games played
+func ComputeStandings(players []uuid.UUID, matches []Match) []Standing {
+	byPlayer := make(map[uuid.UUID]*Standing, len(players))
+	var order []uuid.UUID
+	standing := func(id uuid.UUID) *Standing {
+		s, ok := byPlayer[id]
+		if !ok {
+			s = &Standing{PlayerID: id}
+			byPlayer[id] = s
+			order = append(order, id)
+		}
+		return s
+	}
+
+	for _, id := range players {
+		standing(id)
+	}
+	for _, m := range matches {
+		if !m.Result.Finished() {
+			continue
+		}
+		white, black := standing(m.WhitePlayer), standing(m.BlackPlayer)
+		white.Played++
+		black.Played++
+
+		switch m.Result {
+		case MatchResultWhiteWins:
+			white.Points++
+			white.Wins++
+			black.Losses++
+		case MatchResultBlackWins:
+			black.Points++
+			black.Wins++
+			white.Losses++
+		case MatchResultDraw:
+			white.Points += 0.5
+			black.Points += 0.5
+			white.Draws++
+			black.Draws++
+		}
+	}
+
+	standings := make([]Standing, len(order))
+	for i, id := range order {
+		standings[i] = *byPlayer[id]
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Played < b.Played
+	})
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Points == standings[i-1].Points {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+
+	return standings
+}
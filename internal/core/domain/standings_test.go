@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestComputeStandings(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	matches := []Match{
+		{WhitePlayer: a, BlackPlayer: b, Result: MatchResultWhiteWins},
+		{WhitePlayer: c, BlackPlayer: a, Result: MatchResultDraw},
+		{WhitePlayer: b, BlackPlayer: c, Result: MatchResultBlackWins},
+		{WhitePlayer: b, BlackPlayer: a, Result: MatchResultOngoing},
+	}
+
+	// a and c are on 1.5 with a win each, d has not played
+	standings := ComputeStandings([]uuid.UUID{d, c, b, a}, matches)
+	want := []Standing{
+		{Rank: 1, PlayerID: c, Points: 1.5, Played: 2, Wins: 1, Draws: 1},
+		{Rank: 1, PlayerID: a, Points: 1.5, Played: 2, Wins: 1, Draws: 1},
+		{Rank: 3, PlayerID: d},
+		{Rank: 3, PlayerID: b, Played: 2, Losses: 2},
+	}
+
+	if len(standings) != len(want) {
+		t.Fatalf("expected %d standings, got %+v", len(want), standings)
+	}
+	for i := range want {
+		if standings[i] != want[i] {
+			t.Errorf("standing %d: expected %+v, got %+v", i, want[i], standings[i])
+		}
+	}
+}
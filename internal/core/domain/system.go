@@ -1,6 +1,44 @@
 // Package domain represents the domain objects
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrWorkerPoolFull    = errors.New("the worker pool queue is full")
+	ErrWorkerPoolStopped = errors.New("the worker pool is stopped")
+	ErrTaskTimeout       = errors.New("the task took longer than its timeout")
+)
+
+// System is the build of the running binary, the version, commit and build time are set
+// with -ldflags when it is built
 type System struct {
-	Version string
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"
+	HealthDown HealthStatus = "down"
+)
+
+// HealthCheck is the outcome of a probe of a dependency
+type HealthCheck struct {
+	Name    string
+	Status  HealthStatus
+	Latency time.Duration
+	Error   string
+}
+
+// HealthReport is up when every check is
+type HealthReport struct {
+	Status HealthStatus
+	Checks []HealthCheck
+	System System
 }
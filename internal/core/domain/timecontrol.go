@@ -0,0 +1,378 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTimeControlInvalid     = errors.New("invalid time control")
+	ErrTimeControlUnknown     = errors.New("the time control is unknown")
+	ErrTimeControlUnsupported = errors.New("the time control is not supported")
+)
+
+// TimeControlCategory - how fast a game is played, derived from the time control
+type TimeControlCategory string
+
+const (
+	TimeControlCategoryBullet    TimeControlCategory = "bullet"
+	TimeControlCategoryBlitz     TimeControlCategory = "blitz"
+	TimeControlCategoryRapid     TimeControlCategory = "rapid"
+	TimeControlCategoryClassical TimeControlCategory = "classical"
+)
+
+// TimeControlCategories are all the categories from fastest to slowest
+var TimeControlCategories = []TimeControlCategory{
+	TimeControlCategoryBullet,
+	TimeControlCategoryBlitz,
+	TimeControlCategoryRapid,
+	TimeControlCategoryClassical,
+}
+
+func (c TimeControlCategory) Valid() bool {
+	for _, category := range TimeControlCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// RatingType returns the rating pool games of the category count towards,
+// there is no bullet rating so bullet games count as blitz
+func (c TimeControlCategory) RatingType() RatingType {
+	switch c {
+	case TimeControlCategoryBullet, TimeControlCategoryBlitz:
+		return RatingTypeBlitz
+	case TimeControlCategoryRapid:
+		return RatingTypeRapid
+	}
+	return RatingTypeStandard
+}
+
+// TimeControlPeriod is one stage of a time control. Moves is how many moves have to be
+// played within the period, 0 means the rest of the game. Increment (fischer) is added
+// after every move, Delay (bronstein) is given back up to the time used on the move
+type TimeControlPeriod struct {
+	Moves     int
+	Base      time.Duration
+	Increment time.Duration
+	Delay     time.Duration
+}
+
+// TimeControl is the time each player gets, e.g. 90 minutes for 40 moves then 30 minutes,
+// with 30 seconds added per move from the first move is two periods: 90/40+30, 30+30
+type TimeControl struct {
+	Periods []TimeControlPeriod
+}
+
+const (
+	// categoryMoves is how many moves a game is expected to last when turning the
+	// increment into time, the same figure fide uses for its blitz and rapid definitions
+	categoryMoves = 60
+
+	bulletLimit = 3 * time.Minute
+	blitzLimit  = 10 * time.Minute
+	rapidLimit  = 60 * time.Minute
+)
+
+// IsZero reports whether no time control was set
+func (tc TimeControl) IsZero() bool {
+	return len(tc.Periods) == 0
+}
+
+// EstimatedDuration is the time a player gets for a game of 60 moves
+func (tc TimeControl) EstimatedDuration() time.Duration {
+	var total time.Duration
+	moves := 0
+	for i, p := range tc.Periods {
+		total += p.Base
+
+		// the last period lasts the rest of the game
+		inPeriod := p.Moves
+		if p.Moves == 0 || i == len(tc.Periods)-1 {
+			inPeriod = max(categoryMoves-moves, 0)
+		}
+		inPeriod = min(inPeriod, max(categoryMoves-moves, 0))
+
+		total += time.Duration(inPeriod) * (p.Increment + p.Delay)
+		moves += inPeriod
+	}
+	return total
+}
+
+// Category derives the category from the estimated duration: under 3 minutes is bullet,
+// up to 10 minutes blitz, under 60 minutes rapid and anything longer classical
+func (tc TimeControl) Category() TimeControlCategory {
+	d := tc.EstimatedDuration()
+	switch {
+	case d < bulletLimit:
+		return TimeControlCategoryBullet
+	case d <= blitzLimit:
+		return TimeControlCategoryBlitz
+	case d < rapidLimit:
+		return TimeControlCategoryRapid
+	}
+	return TimeControlCategoryClassical
+}
+
+// Validate checks the periods make a playable time control
+func (tc TimeControl) Validate() error {
+	if tc.IsZero() {
+		return fmt.Errorf("%w: no periods", ErrTimeControlInvalid)
+	}
+	for i, p := range tc.Periods {
+		switch {
+		case p.Base < 0 || p.Increment < 0 || p.Delay < 0 || p.Moves < 0:
+			return fmt.Errorf("%w: negative values in period %d", ErrTimeControlInvalid, i+1)
+		case p.Base == 0 && p.Increment == 0 && p.Delay == 0:
+			return fmt.Errorf("%w: period %d has no time", ErrTimeControlInvalid, i+1)
+		case p.Increment > 0 && p.Delay > 0:
+			return fmt.Errorf("%w: period %d has both increment and delay", ErrTimeControlInvalid, i+1)
+		case p.Moves == 0 && i != len(tc.Periods)-1:
+			return fmt.Errorf("%w: only the last period can be for the rest of the game", ErrTimeControlInvalid)
+		}
+	}
+	return nil
+}
+
+// Equal reports whether both time controls give the same time
+func (tc TimeControl) Equal(other TimeControl) bool {
+	if len(tc.Periods) != len(other.Periods) {
+		return false
+	}
+	for i := range tc.Periods {
+		if tc.Periods[i] != other.Periods[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// InitialClock is the time each player starts the game with
+func (tc TimeControl) InitialClock() time.Duration {
+	if tc.IsZero() {
+		return 0
+	}
+	return tc.Periods[0].Base
+}
+
+// ClockAfterMove returns the time a player has left after their move number n (1 based)
+// that took used out of clock. The increment or delay of the period is applied and once
+// the moves of a period are played the base of the next one is added. A flag fall is 0
+func (tc TimeControl) ClockAfterMove(clock, used time.Duration, n int) time.Duration {
+	if tc.IsZero() {
+		return clock
+	}
+
+	// the last period repeats when it is for a number of moves, e.g. 40/120 for every 40 moves
+	index, played := len(tc.Periods)-1, 0
+	for i, p := range tc.Periods {
+		if p.Moves == 0 || n <= played+p.Moves {
+			index = i
+			break
+		}
+		played += p.Moves
+	}
+	period := tc.Periods[index]
+
+	used = max(used-period.Delay, 0)
+	left := clock - used
+	if left <= 0 {
+		return 0
+	}
+	left += period.Increment
+
+	if period.Moves > 0 && (n-played)%period.Moves == 0 {
+		next := period
+		if index+1 < len(tc.Periods) {
+			next = tc.Periods[index+1]
+		}
+		left += next.Base
+	}
+
+	return left
+}
+
+// String formats the time control in the notation read by ParseTimeControl, e.g. "90/40+30, 30+30"
+func (tc TimeControl) String() string {
+	periods := make([]string, len(tc.Periods))
+	for i, p := range tc.Periods {
+		var b strings.Builder
+		b.WriteString(formatMinutes(p.Base))
+		if p.Moves > 0 {
+			fmt.Fprintf(&b, "/%d", p.Moves)
+		}
+		if p.Increment > 0 {
+			b.WriteString("+" + formatSeconds(p.Increment))
+		}
+		if p.Delay > 0 {
+			b.WriteString("d" + formatSeconds(p.Delay))
+		}
+		periods[i] = b.String()
+	}
+	return strings.Join(periods, ", ")
+}
+
+// PGN formats the time control as the value of the PGN TimeControl tag, e.g. "40/5400+30:1800+30"
+func (tc TimeControl) PGN() string {
+	periods := make([]string, len(tc.Periods))
+	for i, p := range tc.Periods {
+		var b strings.Builder
+		if p.Moves > 0 {
+			fmt.Fprintf(&b, "%d/", p.Moves)
+		}
+		b.WriteString(formatSeconds(p.Base))
+		// the tag has no way to write a delay, it is the closest thing
+		if bonus := p.Increment + p.Delay; bonus > 0 {
+			b.WriteString("+" + formatSeconds(bonus))
+		}
+		periods[i] = b.String()
+	}
+	return strings.Join(periods, ":")
+}
+
+// ParseTimeControl reads the notation organisers use, base minutes per period with
+// optional moves, increment seconds and delay seconds:
+//
+//	"90+30"            90 minutes, 30 seconds increment
+//	"5|3", "5 + 3"     5 minutes, 3 seconds increment
+//	"25d5"             25 minutes, 5 seconds delay
+//	"90/40+30, 30+30"  90 minutes for 40 moves, then 30 minutes, 30 seconds increment
+//
+// periods can also be separated by ";" or "then"
+func ParseTimeControl(notation string) (TimeControl, error) {
+	notation = strings.ToLower(strings.TrimSpace(notation))
+	if notation == "" {
+		return TimeControl{}, fmt.Errorf("%w: empty", ErrTimeControlInvalid)
+	}
+
+	notation = strings.NewReplacer(" then ", ",", ";", ",", "|", "+").Replace(notation)
+
+	var tc TimeControl
+	for _, field := range strings.Split(notation, ",") {
+		field = strings.ReplaceAll(field, " ", "")
+		base, rest := cutAny(field, "+d")
+
+		period := TimeControlPeriod{}
+		minutes, moves, hasMoves := strings.Cut(base, "/")
+		if hasMoves {
+			n, err := strconv.Atoi(moves)
+			if err != nil || n <= 0 {
+				return TimeControl{}, fmt.Errorf("%w: moves %q", ErrTimeControlInvalid, moves)
+			}
+			period.Moves = n
+		}
+
+		d, err := parseAmount(minutes, time.Minute)
+		if err != nil {
+			return TimeControl{}, err
+		}
+		period.Base = d
+
+		if err := parseBonus(rest, &period); err != nil {
+			return TimeControl{}, err
+		}
+		tc.Periods = append(tc.Periods, period)
+	}
+
+	if err := tc.Validate(); err != nil {
+		return TimeControl{}, err
+	}
+	return tc, nil
+}
+
+// ParsePGNTimeControl reads the value of the PGN TimeControl tag, the periods are
+// separated by ":" and every value is in seconds, e.g. "40/5400+30:1800+30" or "300+3".
+// "?" returns ErrTimeControlUnknown and games without a clock ("-") or with a
+// sandclock ("*60") return ErrTimeControlUnsupported
+func ParsePGNTimeControl(tag string) (TimeControl, error) {
+	tag = strings.TrimSpace(tag)
+	switch {
+	case tag == "" || tag == "?":
+		return TimeControl{}, ErrTimeControlUnknown
+	case tag == "-" || strings.HasPrefix(tag, "*"):
+		return TimeControl{}, fmt.Errorf("%w: %q", ErrTimeControlUnsupported, tag)
+	}
+
+	var tc TimeControl
+	for _, field := range strings.Split(tag, ":") {
+		period := TimeControlPeriod{}
+
+		if moves, rest, ok := strings.Cut(field, "/"); ok {
+			n, err := strconv.Atoi(moves)
+			if err != nil || n <= 0 {
+				return TimeControl{}, fmt.Errorf("%w: moves %q", ErrTimeControlInvalid, moves)
+			}
+			period.Moves = n
+			field = rest
+		}
+
+		base, increment, _ := strings.Cut(field, "+")
+		d, err := parseAmount(base, time.Second)
+		if err != nil {
+			return TimeControl{}, err
+		}
+		period.Base = d
+
+		if increment != "" {
+			if period.Increment, err = parseAmount(increment, time.Second); err != nil {
+				return TimeControl{}, err
+			}
+		}
+		tc.Periods = append(tc.Periods, period)
+	}
+
+	if err := tc.Validate(); err != nil {
+		return TimeControl{}, err
+	}
+	return tc, nil
+}
+
+// parseBonus reads the "+30" and "d5" suffixes of a period
+func parseBonus(s string, period *TimeControlPeriod) error {
+	for s != "" {
+		kind := s[0]
+		value, rest := cutAny(s[1:], "+d")
+
+		d, err := parseAmount(value, time.Second)
+		if err != nil {
+			return err
+		}
+		if kind == '+' {
+			period.Increment = d
+		} else {
+			period.Delay = d
+		}
+		s = rest
+	}
+	return nil
+}
+
+// parseAmount reads a non negative, possibly fractional, number of units
+func parseAmount(s string, unit time.Duration) (time.Duration, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("%w: %q is not a number", ErrTimeControlInvalid, s)
+	}
+	return time.Duration(f * float64(unit)).Round(time.Second), nil
+}
+
+// cutAny splits s before the first byte found in chars
+func cutAny(s, chars string) (string, string) {
+	if i := strings.IndexAny(s, chars); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
+
+func formatMinutes(d time.Duration) string {
+	return strconv.FormatFloat(d.Minutes(), 'f', -1, 64)
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	tests := []struct {
+		notation string
+		periods  []TimeControlPeriod
+		category TimeControlCategory
+		str      string
+		pgn      string
+	}{
+		{
+			notation: "90+30",
+			periods:  []TimeControlPeriod{{Base: 90 * time.Minute, Increment: 30 * time.Second}},
+			category: TimeControlCategoryClassical,
+			str:      "90+30",
+			pgn:      "5400+30",
+		},
+		{
+			notation: "5 | 3",
+			periods:  []TimeControlPeriod{{Base: 5 * time.Minute, Increment: 3 * time.Second}},
+			category: TimeControlCategoryBlitz,
+			str:      "5+3",
+			pgn:      "300+3",
+		},
+		{
+			notation: "1+0",
+			periods:  []TimeControlPeriod{{Base: time.Minute}},
+			category: TimeControlCategoryBullet,
+			str:      "1",
+			pgn:      "60",
+		},
+		{
+			notation: "0.5+0",
+			periods:  []TimeControlPeriod{{Base: 30 * time.Second}},
+			category: TimeControlCategoryBullet,
+			str:      "0.5",
+			pgn:      "30",
+		},
+		{
+			notation: "10+5",
+			periods:  []TimeControlPeriod{{Base: 10 * time.Minute, Increment: 5 * time.Second}},
+			category: TimeControlCategoryRapid,
+			str:      "10+5",
+			pgn:      "600+5",
+		},
+		{
+			notation: "25d5",
+			periods:  []TimeControlPeriod{{Base: 25 * time.Minute, Delay: 5 * time.Second}},
+			category: TimeControlCategoryRapid,
+			str:      "25d5",
+			pgn:      "1500+5",
+		},
+		{
+			notation: "90/40+30, 30+30",
+			periods: []TimeControlPeriod{
+				{Moves: 40, Base: 90 * time.Minute, Increment: 30 * time.Second},
+				{Base: 30 * time.Minute, Increment: 30 * time.Second},
+			},
+			category: TimeControlCategoryClassical,
+			str:      "90/40+30, 30+30",
+			pgn:      "40/5400+30:1800+30",
+		},
+		{
+			notation: "120/40 then 60",
+			periods: []TimeControlPeriod{
+				{Moves: 40, Base: 120 * time.Minute},
+				{Base: 60 * time.Minute},
+			},
+			category: TimeControlCategoryClassical,
+			str:      "120/40, 60",
+			pgn:      "40/7200:3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			tc, err := ParseTimeControl(tt.notation)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.Equal(TimeControl{Periods: tt.periods}) {
+				t.Errorf("expected periods %+v, got %+v", tt.periods, tc.Periods)
+			}
+			if tc.Category() != tt.category {
+				t.Errorf("expected category %s, got %s (%s)", tt.category, tc.Category(), tc.EstimatedDuration())
+			}
+			if tc.String() != tt.str {
+				t.Errorf("expected notation %q, got %q", tt.str, tc.String())
+			}
+			if tc.PGN() != tt.pgn {
+				t.Errorf("expected pgn %q, got %q", tt.pgn, tc.PGN())
+			}
+
+			// the notation and the pgn tag read back to the same time control, delays aside
+			again, err := ParseTimeControl(tc.String())
+			if err != nil || !again.Equal(tc) {
+				t.Errorf("%q does not read back: %+v, %v", tc.String(), again, err)
+			}
+		})
+	}
+}
+
+func TestParseTimeControl_Errors(t *testing.T) {
+	for _, notation := range []string{"", "abc", "90+", "90/0+30", "-5+3", "0+0", "5+3d2", "30, 90/40"} {
+		t.Run(notation, func(t *testing.T) {
+			_, err := ParseTimeControl(notation)
+			if !errors.Is(err, ErrTimeControlInvalid) {
+				t.Errorf("expected ErrTimeControlInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParsePGNTimeControl(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected TimeControl
+		err      error
+	}{
+		{tag: "300+3", expected: TimeControl{Periods: []TimeControlPeriod{{Base: 5 * time.Minute, Increment: 3 * time.Second}}}},
+		{tag: "40/9000:1800+30", expected: TimeControl{Periods: []TimeControlPeriod{
+			{Moves: 40, Base: 150 * time.Minute},
+			{Base: 30 * time.Minute, Increment: 30 * time.Second},
+		}}},
+		{tag: "?", err: ErrTimeControlUnknown},
+		{tag: "-", err: ErrTimeControlUnsupported},
+		{tag: "*180", err: ErrTimeControlUnsupported},
+		{tag: "40/abc", err: ErrTimeControlInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			tc, err := ParsePGNTimeControl(tt.tag)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.Equal(tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, tc)
+			}
+		})
+	}
+}
+
+func TestTimeControlCategory_RatingType(t *testing.T) {
+	tests := map[TimeControlCategory]RatingType{
+		TimeControlCategoryBullet:    RatingTypeBlitz,
+		TimeControlCategoryBlitz:     RatingTypeBlitz,
+		TimeControlCategoryRapid:     RatingTypeRapid,
+		TimeControlCategoryClassical: RatingTypeStandard,
+	}
+
+	for category, expected := range tests {
+		if got := category.RatingType(); got != expected {
+			t.Errorf("%s: expected %s, got %s", category, expected, got)
+		}
+	}
+}
+
+func TestTimeControl_ClockAfterMove(t *testing.T) {
+	classical := mustParseTimeControl(t, "90/40+30, 30+30")
+	delay := mustParseTimeControl(t, "25d5")
+	repeating := TimeControl{Periods: []TimeControlPeriod{{Moves: 40, Base: 2 * time.Hour}}}
+
+	tests := []struct {
+		name     string
+		tc       TimeControl
+		clock    time.Duration
+		used     time.Duration
+		move     int
+		expected time.Duration
+	}{
+		{name: "increment", tc: classical, clock: 90 * time.Minute, used: time.Minute, move: 1, expected: 89*time.Minute + 30*time.Second},
+		{name: "end of the first period", tc: classical, clock: 10 * time.Minute, used: time.Minute, move: 40, expected: 39*time.Minute + 30*time.Second},
+		{name: "second period", tc: classical, clock: 10 * time.Minute, used: time.Minute, move: 41, expected: 9*time.Minute + 30*time.Second},
+		{name: "flag fall", tc: classical, clock: time.Minute, used: 2 * time.Minute, move: 12, expected: 0},
+		{name: "delay used up", tc: delay, clock: 10 * time.Minute, used: 8 * time.Second, move: 3, expected: 10*time.Minute - 3*time.Second},
+		{name: "within the delay", tc: delay, clock: 10 * time.Minute, used: 4 * time.Second, move: 3, expected: 10 * time.Minute},
+		{name: "repeating period", tc: repeating, clock: time.Minute, used: 0, move: 80, expected: 2*time.Hour + time.Minute},
+		{name: "no time control", clock: time.Minute, used: time.Second, move: 1, expected: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tc.ClockAfterMove(tt.clock, tt.used, tt.move); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func mustParseTimeControl(t *testing.T, notation string) TimeControl {
+	t.Helper()
+	tc, err := ParseTimeControl(notation)
+	if err != nil {
+		t.Fatalf("error parsing %q: %v", notation, err)
+	}
+	return tc
+}
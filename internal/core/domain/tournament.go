@@ -45,24 +45,55 @@ type Tournament struct {
 	Creator            Player   // REVISIT: this is actually going to be the website owner, not a player
 	Contact            Contact
 	Description        string
+	Descriptions       LocalizedText // translations of Description
 	OpenToPublic       bool
 	OpenToSpectators   bool
 	OpenToRegistration bool
 	Registration       Registration
 	Arbitrator         string
 	PairingMethod      PairingMethod
+	TimeControl        TimeControl
 	Matches            []Match
-	Players            []Player // no more no less than 2 white/black
-	NumberOfPlayers    int      // how many are participating
+	Players            []uuid.UUID // public IDs of the registered players
+	NumberOfPlayers    int         // how many are participating
+	Rounds             int         // how many rounds have been paired
 	Schedule           []Schedule
 	Results            []Result
 	Status             TournamentStatus
+	Transitions        []TournamentTransition // every change of status, oldest first
+	ConsumerID         string                 // the api consumer that posted it, empty for the ones created here
+	Moderation         ModerationStatus
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 	SoftDeletedAt      time.Time
 	DeletedAt          time.Time
 }
 
+// TournamentFilter narrows a listing of tournaments, zero values match everything
+type TournamentFilter struct {
+	Category    TimeControlCategory
+	TimeControl TimeControl // only tournaments played at exactly this time control
+	Moderation  ModerationStatus
+	LocationID  uuid.UUID // only tournaments held at the registered location
+}
+
+// Matches reports whether the tournament passes the filter
+func (f TournamentFilter) Matches(t Tournament) bool {
+	if f.Category != "" && t.TimeControl.Category() != f.Category {
+		return false
+	}
+	if !f.TimeControl.IsZero() && !t.TimeControl.Equal(f.TimeControl) {
+		return false
+	}
+	if f.Moderation != "" && t.Moderation != f.Moderation {
+		return false
+	}
+	if f.LocationID != uuid.Nil && t.Location.PublicID != f.LocationID {
+		return false
+	}
+	return true
+}
+
 type Schedule struct {
 	StartTime time.Time
 	EndTime   time.Time
@@ -94,7 +125,7 @@ type Payment struct {
 }
 
 type Result struct {
-	Player Player
+	Player uuid.UUID // public ID
 	Prize  int64
 }
 
@@ -108,5 +139,6 @@ func NewTournament(name, desc string) *Tournament {
 	return &Tournament{
 		Name:        name,
 		Description: desc,
+		Status:      TournamentStatusDraft,
 	}
 }
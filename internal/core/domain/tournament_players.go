@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTournamentRegistrationClosed = errors.New("the tournament no longer takes registrations")
+	ErrPlayerAlreadyRegistered      = errors.New("the player is already registered for the tournament")
+	ErrTournamentNotInPlay          = errors.New("the tournament is not being played")
+	ErrInvalidPairings              = errors.New("invalid pairings")
+)
+
+// Pairing is a game of a round, the players by their public ID
+type Pairing struct {
+	White uuid.UUID
+	Black uuid.UUID
+}
+
+// PairedRound is a round of a tournament and the matches it was paired into
+type PairedRound struct {
+	Round   int
+	Matches []Match
+}
+
+// Register adds the player to the tournament, players can register until it starts
+func (t *Tournament) Register(player uuid.UUID, at time.Time) error {
+	switch status := t.CurrentStatus(); status {
+	case TournamentStatusDraft, TournamentStatusPending:
+	default:
+		return fmt.Errorf("%w: the tournament is %s", ErrTournamentRegistrationClosed, status)
+	}
+	if slices.Contains(t.Players, player) {
+		return ErrPlayerAlreadyRegistered
+	}
+
+	t.Players = append(t.Players, player)
+	t.NumberOfPlayers = len(t.Players)
+	t.UpdatedAt = at
+
+	return nil
+}
+
+// PairRound checks the pairings of the next round of an active tournament and counts the round,
+// every player of the pairings has to be registered and can only play once in the round
+func (t *Tournament) PairRound(pairings []Pairing, at time.Time) (int, error) {
+	if status := t.CurrentStatus(); status != TournamentStatusActive {
+		return 0, fmt.Errorf("%w: the tournament is %s", ErrTournamentNotInPlay, status)
+	}
+	if len(pairings) == 0 {
+		return 0, fmt.Errorf("%w: the round has no games", ErrInvalidPairings)
+	}
+
+	paired := make(map[uuid.UUID]bool, 2*len(pairings))
+	for _, p := range pairings {
+		if p.White == p.Black {
+			return 0, fmt.Errorf("%w: %s cannot play against themselves", ErrInvalidPairings, p.White)
+		}
+		for _, player := range []uuid.UUID{p.White, p.Black} {
+			if !slices.Contains(t.Players, player) {
+				return 0, fmt.Errorf("%w: %s is not registered", ErrInvalidPairings, player)
+			}
+			if paired[player] {
+				return 0, fmt.Errorf("%w: %s is paired twice", ErrInvalidPairings, player)
+			}
+			paired[player] = true
+		}
+	}
+
+	t.Rounds++
+	t.UpdatedAt = at
+
+	return t.Rounds, nil
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTournament_Register(t *testing.T) {
+	player := uuid.New()
+
+	tests := []struct {
+		name    string
+		status  TournamentStatus
+		players []uuid.UUID
+		wantErr error
+	}{
+		{name: "register for a draft", status: TournamentStatusDraft},
+		{name: "register for a pending", status: TournamentStatusPending},
+		{name: "register for an active", status: TournamentStatusActive, wantErr: ErrTournamentRegistrationClosed},
+		{name: "register twice", status: TournamentStatusPending, players: []uuid.UUID{player}, wantErr: ErrPlayerAlreadyRegistered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournament := Tournament{Status: tt.status, Players: tt.players}
+
+			err := tournament.Register(player, time.Now())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(tournament.Players) != len(tt.players) {
+					t.Errorf("Register() changed the players on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Register() unexpected error: %v", err)
+			}
+			if len(tournament.Players) != 1 || tournament.Players[0] != player || tournament.NumberOfPlayers != 1 {
+				t.Errorf("Register() players = %v (%d), want [%s]", tournament.Players, tournament.NumberOfPlayers, player)
+			}
+		})
+	}
+}
+
+func TestTournament_PairRound(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	players := []uuid.UUID{a, b, c, d}
+
+	tests := []struct {
+		name     string
+		status   TournamentStatus
+		pairings []Pairing
+		wantErr  error
+	}{
+		{name: "pair a round", status: TournamentStatusActive, pairings: []Pairing{{a, b}, {c, d}}},
+		{name: "pair a pending", status: TournamentStatusPending, pairings: []Pairing{{a, b}}, wantErr: ErrTournamentNotInPlay},
+		{name: "no games", status: TournamentStatusActive, wantErr: ErrInvalidPairings},
+		{name: "against themselves", status: TournamentStatusActive, pairings: []Pairing{{a, a}}, wantErr: ErrInvalidPairings},
+		{name: "not registered", status: TournamentStatusActive, pairings: []Pairing{{a, uuid.New()}}, wantErr: ErrInvalidPairings},
+		{name: "paired twice", status: TournamentStatusActive, pairings: []Pairing{{a, b}, {c, a}}, wantErr: ErrInvalidPairings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournament := Tournament{Status: tt.status, Players: players, Rounds: 1}
+
+			round, err := tournament.PairRound(tt.pairings, time.Now())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("PairRound() error = %v, want %v", err, tt.wantErr)
+				}
+				if tournament.Rounds != 1 {
+					t.Errorf("PairRound() counted the round on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PairRound() unexpected error: %v", err)
+			}
+			if round != 2 || tournament.Rounds != 2 {
+				t.Errorf("PairRound() round = %d, rounds = %d, want 2", round, tournament.Rounds)
+			}
+		})
+	}
+}
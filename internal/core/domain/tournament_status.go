@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTournamentTransitionNotAllowed = errors.New("the tournament cannot take this action in its current status")
+	ErrTournamentActionUnknown        = errors.New("unknown tournament action")
+	ErrTournamentNotEnoughPlayers     = errors.New("not enough players to start the tournament")
+	ErrTournamentUnreportedResults    = errors.New("the tournament has matches without a result")
+	ErrTournamentNotOwner             = errors.New("only the consumer that posted the tournament can change its status")
+)
+
+// TournamentMinimumPlayers is how many registered players a tournament needs to start
+const TournamentMinimumPlayers = 2
+
+// TournamentAction - what moves a tournament from one status to another
+type TournamentAction string
+
+const (
+	TournamentActionSubmit     TournamentAction = "submit"     // draft to pending, the details are final
+	TournamentActionStart      TournamentAction = "start"      // pending to active, the first round can be played
+	TournamentActionSuspend    TournamentAction = "suspend"    // active to suspended, e.g. the venue is not available
+	TournamentActionResume     TournamentAction = "resume"     // suspended to active
+	TournamentActionComplete   TournamentAction = "complete"   // active to completed, every result is in
+	TournamentActionDeactivate TournamentAction = "deactivate" // draft, pending or suspended to inactive, it will not be played
+	TournamentActionReopen     TournamentAction = "reopen"     // inactive back to draft
+)
+
+// tournamentTransitions are the statuses an action can be taken from and the status it leads to
+var tournamentTransitions = map[TournamentAction]struct {
+	from []TournamentStatus
+	to   TournamentStatus
+}{
+	TournamentActionSubmit:     {from: []TournamentStatus{TournamentStatusDraft}, to: TournamentStatusPending},
+	TournamentActionStart:      {from: []TournamentStatus{TournamentStatusPending}, to: TournamentStatusActive},
+	TournamentActionSuspend:    {from: []TournamentStatus{TournamentStatusActive}, to: TournamentStatusSuspended},
+	TournamentActionResume:     {from: []TournamentStatus{TournamentStatusSuspended}, to: TournamentStatusActive},
+	TournamentActionComplete:   {from: []TournamentStatus{TournamentStatusActive}, to: TournamentStatusCompleted},
+	TournamentActionDeactivate: {from: []TournamentStatus{TournamentStatusDraft, TournamentStatusPending, TournamentStatusSuspended}, to: TournamentStatusInactive},
+	TournamentActionReopen:     {from: []TournamentStatus{TournamentStatusInactive}, to: TournamentStatusDraft},
+}
+
+// TournamentActions are all the actions in the order of the life of a tournament
+var TournamentActions = []TournamentAction{
+	TournamentActionSubmit,
+	TournamentActionStart,
+	TournamentActionSuspend,
+	TournamentActionResume,
+	TournamentActionComplete,
+	TournamentActionDeactivate,
+	TournamentActionReopen,
+}
+
+func (a TournamentAction) Valid() bool {
+	_, ok := tournamentTransitions[a]
+	return ok
+}
+
+// TournamentTransition records a change of status, who made it and when
+type TournamentTransition struct {
+	Action TournamentAction
+	From   TournamentStatus
+	To     TournamentStatus
+	Actor  string
+	Reason string // optional, e.g. why it was suspended
+	At     time.Time
+}
+
+// CurrentStatus returns the status of the tournament, tournaments saved before the status
+// was tracked have none and are drafts
+func (t Tournament) CurrentStatus() TournamentStatus {
+	if t.Status == "" {
+		return TournamentStatusDraft
+	}
+	return t.Status
+}
+
+// AvailableActions returns the actions that can be taken from the current status,
+// the guards are not checked
+func (t Tournament) AvailableActions() []TournamentAction {
+	var actions []TournamentAction
+	for _, action := range TournamentActions {
+		if canTransitionFrom(action, t.CurrentStatus()) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// ManagedBy tells whether the principal can take the actions on the tournament, the admin
+// manages every tournament and a consumer the ones it posted
+func (t Tournament) ManagedBy(principal Principal) bool {
+	switch principal.Role {
+	case RoleAdmin:
+		return true
+	case RoleConsumer:
+		return principal.ID != "" && principal.ID == t.ConsumerID
+	default:
+		return false
+	}
+}
+
+// Transition takes the action on the tournament and records it. matches are the matches
+// of the tournament, completing it requires every one of them to have a final result
+func (t *Tournament) Transition(action TournamentAction, actor, reason string, matches []Match, at time.Time) error {
+	transition, ok := tournamentTransitions[action]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrTournamentActionUnknown, action)
+	}
+
+	from := t.CurrentStatus()
+	if !canTransitionFrom(action, from) {
+		return fmt.Errorf("%w: cannot %s a %s tournament", ErrTournamentTransitionNotAllowed, action, from)
+	}
+
+	if err := t.checkGuards(action, matches); err != nil {
+		return err
+	}
+
+	t.Status = transition.to
+	t.Transitions = append(t.Transitions, TournamentTransition{
+		Action: action,
+		From:   from,
+		To:     transition.to,
+		Actor:  actor,
+		Reason: reason,
+		At:     at,
+	})
+	t.UpdatedAt = at
+
+	return nil
+}
+
+func (t Tournament) checkGuards(action TournamentAction, matches []Match) error {
+	switch action {
+	case TournamentActionStart:
+		if len(t.Players) < TournamentMinimumPlayers {
+			return fmt.Errorf("%w: %d registered, %d needed", ErrTournamentNotEnoughPlayers, len(t.Players), TournamentMinimumPlayers)
+		}
+
+	case TournamentActionComplete:
+		unreported := 0
+		for _, m := range matches {
+			if !m.Result.Finished() {
+				unreported++
+			}
+		}
+		if unreported > 0 {
+			return fmt.Errorf("%w: %d without a result", ErrTournamentUnreportedResults, unreported)
+		}
+	}
+	return nil
+}
+
+func canTransitionFrom(action TournamentAction, status TournamentStatus) bool {
+	for _, from := range tournamentTransitions[action].from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTournament_Transition(t *testing.T) {
+	players := []uuid.UUID{uuid.New(), uuid.New()}
+	finished := []Match{{Result: MatchResultWhiteWins}, {Result: MatchResultDraw}}
+	unfinished := []Match{{Result: MatchResultWhiteWins}, {Result: MatchResultOngoing}}
+
+	tests := []struct {
+		name    string
+		status  TournamentStatus
+		players []uuid.UUID
+		matches []Match
+		action  TournamentAction
+		want    TournamentStatus
+		wantErr error
+	}{
+		{name: "submit a draft", status: TournamentStatusDraft, action: TournamentActionSubmit, want: TournamentStatusPending},
+		{name: "no status is a draft", status: "", action: TournamentActionSubmit, want: TournamentStatusPending},
+		{name: "start", status: TournamentStatusPending, players: players, action: TournamentActionStart, want: TournamentStatusActive},
+		{name: "start without players", status: TournamentStatusPending, players: players[:1], action: TournamentActionStart, wantErr: ErrTournamentNotEnoughPlayers},
+		{name: "start a draft", status: TournamentStatusDraft, players: players, action: TournamentActionStart, wantErr: ErrTournamentTransitionNotAllowed},
+		{name: "suspend", status: TournamentStatusActive, action: TournamentActionSuspend, want: TournamentStatusSuspended},
+		{name: "resume", status: TournamentStatusSuspended, action: TournamentActionResume, want: TournamentStatusActive},
+		{name: "suspend a pending", status: TournamentStatusPending, action: TournamentActionSuspend, wantErr: ErrTournamentTransitionNotAllowed},
+		{name: "complete", status: TournamentStatusActive, matches: finished, action: TournamentActionComplete, want: TournamentStatusCompleted},
+		{name: "complete without matches", status: TournamentStatusActive, action: TournamentActionComplete, want: TournamentStatusCompleted},
+		{name: "complete with unreported results", status: TournamentStatusActive, matches: unfinished, action: TournamentActionComplete, wantErr: ErrTournamentUnreportedResults},
+		{name: "complete a suspended", status: TournamentStatusSuspended, matches: finished, action: TournamentActionComplete, wantErr: ErrTournamentTransitionNotAllowed},
+		{name: "deactivate a suspended", status: TournamentStatusSuspended, action: TournamentActionDeactivate, want: TournamentStatusInactive},
+		{name: "deactivate an active", status: TournamentStatusActive, action: TournamentActionDeactivate, wantErr: ErrTournamentTransitionNotAllowed},
+		{name: "reopen", status: TournamentStatusInactive, action: TournamentActionReopen, want: TournamentStatusDraft},
+		{name: "nothing after completed", status: TournamentStatusCompleted, action: TournamentActionReopen, wantErr: ErrTournamentTransitionNotAllowed},
+		{name: "unknown action", status: TournamentStatusDraft, action: "publish", wantErr: ErrTournamentActionUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournament := Tournament{Status: tt.status, Players: tt.players}
+			at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			err := tournament.Transition(tt.action, "arbiter", "", tt.matches, at)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transition() error = %v, want %v", err, tt.wantErr)
+				}
+				if tournament.Status != tt.status || len(tournament.Transitions) != 0 {
+					t.Errorf("Transition() changed the tournament on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transition() unexpected error: %v", err)
+			}
+
+			if tournament.Status != tt.want {
+				t.Errorf("Transition() status = %s, want %s", tournament.Status, tt.want)
+			}
+			want := TournamentTransition{Action: tt.action, From: Tournament{Status: tt.status}.CurrentStatus(), To: tt.want, Actor: "arbiter", At: at}
+			if len(tournament.Transitions) != 1 || tournament.Transitions[0] != want {
+				t.Errorf("Transition() recorded %+v, want %+v", tournament.Transitions, want)
+			}
+		})
+	}
+}
+
+func TestTournament_AvailableActions(t *testing.T) {
+	tests := []struct {
+		status TournamentStatus
+		want   []TournamentAction
+	}{
+		{TournamentStatusDraft, []TournamentAction{TournamentActionSubmit, TournamentActionDeactivate}},
+		{TournamentStatusPending, []TournamentAction{TournamentActionStart, TournamentActionDeactivate}},
+		{TournamentStatusActive, []TournamentAction{TournamentActionSuspend, TournamentActionComplete}},
+		{TournamentStatusSuspended, []TournamentAction{TournamentActionResume, TournamentActionDeactivate}},
+		{TournamentStatusInactive, []TournamentAction{TournamentActionReopen}},
+		{TournamentStatusCompleted, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got := Tournament{Status: tt.status}.AvailableActions()
+			if len(got) != len(tt.want) {
+				t.Fatalf("AvailableActions() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("AvailableActions() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTournament_ManagedBy(t *testing.T) {
+	posted := Tournament{ConsumerID: "acme"}
+
+	tests := []struct {
+		name       string
+		tournament Tournament
+		principal  Principal
+		want       bool
+	}{
+		{name: "admin", tournament: posted, principal: Principal{Role: RoleAdmin, ID: "admin"}, want: true},
+		{name: "owner", tournament: posted, principal: Principal{Role: RoleConsumer, ID: "acme"}, want: true},
+		{name: "another consumer", tournament: posted, principal: Principal{Role: RoleConsumer, ID: "club-app"}, want: false},
+		{name: "consumer of a maple tournament", tournament: Tournament{}, principal: Principal{Role: RoleConsumer}, want: false},
+		{name: "moderator", tournament: posted, principal: Principal{Role: RoleModerator, ID: "acme"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tournament.ManagedBy(tt.principal); got != tt.want {
+				t.Errorf("ManagedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDisabled         = errors.New("the webhook is disabled")
+	ErrWebhookSignatureInvalid = errors.New("the webhook signature is not valid")
+	ErrWebhookSignatureExpired = errors.New("the webhook timestamp is outside the tolerance")
+	ErrWebhookSignatureMissing = errors.New("the webhook signature is missing")
+	ErrWebhookTimestampInvalid = errors.New("the webhook timestamp is not valid")
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookHeaderEvent     = "X-Maple-Event"
+	WebhookHeaderEventID   = "X-Maple-Event-ID"
+	WebhookHeaderDelivery  = "X-Maple-Delivery"
+	WebhookHeaderTimestamp = "X-Maple-Timestamp"
+	WebhookHeaderSignature = "X-Maple-Signature"
+
+	// webhookSignaturePrefix names the algorithm so it can change without breaking receivers
+	webhookSignaturePrefix = "sha256="
+)
+
+type WebhookStatus string
+
+const (
+	WebhookStatusActive   WebhookStatus = "active"
+	WebhookStatusDisabled WebhookStatus = "disabled"
+)
+
+// Webhook is an endpoint of an api consumer that is told about the events it subscribed to
+type Webhook struct {
+	ID                  int // private
+	PublicID            uuid.UUID
+	ConsumerID          string
+	URL                 string
+	Secret              string      // signs the deliveries, only shown when the webhook is created
+	EventTypes          []EventType // every type when empty
+	TournamentIDs       []uuid.UUID // every tournament when empty
+	Status              WebhookStatus
+	ConsecutiveFailures int // failed attempts since the last successful one
+	DisabledAt          time.Time
+	DisabledReason      string
+	LastDeliveryAt      time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Wants reports whether the event passes the filters of the webhook
+func (w Webhook) Wants(event Event) bool {
+	if len(w.EventTypes) > 0 && !containsEventType(w.EventTypes, event.EventType()) {
+		return false
+	}
+	if len(w.TournamentIDs) > 0 {
+		tournamentID := EventTournamentID(event)
+		for _, id := range w.TournamentIDs {
+			if id == tournamentID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// EventTournamentID returns the tournament the event is about, uuid.Nil when there is none
+func EventTournamentID(event Event) uuid.UUID {
+	switch e := event.(type) {
+	case ResultRecorded:
+		return e.TournamentID
+	case ChallengeAccepted:
+		return uuid.Nil
+	}
+	return event.AggregateID()
+}
+
+func containsEventType(types []EventType, t EventType) bool {
+	for _, et := range types {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // given up on
+)
+
+// WebhookDelivery is one event sent to one webhook, Attempts is its delivery log
+type WebhookDelivery struct {
+	ID            int // private
+	PublicID      uuid.UUID
+	WebhookID     uuid.UUID
+	EventID       uuid.UUID
+	EventType     EventType
+	Payload       []byte
+	Status        WebhookDeliveryStatus
+	Attempts      []WebhookAttempt
+	NextAttemptAt time.Time
+	RedeliveryOf  uuid.UUID // the delivery it was manually redelivered from, uuid.Nil otherwise
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// WebhookAttempt is one request made to the endpoint of the webhook
+type WebhookAttempt struct {
+	At         time.Time
+	StatusCode int // 0 when no response was received
+	Error      string
+	Duration   time.Duration
+}
+
+// Succeeded reports whether the endpoint acknowledged the delivery with a 2xx response
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// SignWebhookPayload returns the value of the X-Maple-Signature header: the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret of the webhook. The timestamp
+// is part of the signature so a captured delivery cannot be replayed later
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature is what receivers do with a delivery: check the signature header,
+// which can hold several comma separated signatures while a secret is rotated, and that the
+// timestamp is within tolerance of now
+func VerifyWebhookSignature(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	if signature == "" {
+		return ErrWebhookSignatureMissing
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrWebhookTimestampInvalid, timestamp)
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrWebhookSignatureExpired
+	}
+
+	expected := SignWebhookPayload(secret, ts, body)
+	for _, candidate := range strings.Split(signature, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(candidate)), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrWebhookSignatureInvalid
+}
+
+// nonPublicPrefixes are the ranges not reachable from the internet that netip has no method for
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // this network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade nat
+	netip.MustParsePrefix("192.0.0.0/24"),   // ietf protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // nat64, it reaches the ipv4 addresses of the network
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local nat64
+	netip.MustParsePrefix("2001::/32"),      // teredo, it embeds any ipv4 address
+	netip.MustParsePrefix("2002::/16"),      // 6to4, same
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+	netip.MustParsePrefix("100::/64"),       // discard
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// PublicAddress reports whether the address is one of the internet, the webhooks are not sent
+// to the server itself or to its network
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// PublicHost reports whether the host of a url may be public: its address when it is one, and
+// any name but localhost, which only resolves to the server. The address a name resolves to is
+// checked when connecting
+func PublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"tournament.created"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	signature := SignWebhookPayload("secret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   error
+	}{
+		{name: "valid", secret: "secret", timestamp: ts, signature: signature, body: body},
+		{name: "one of several", secret: "secret", timestamp: ts, signature: "sha256=00, " + signature, body: body},
+		{name: "other secret", secret: "other", timestamp: ts, signature: signature, body: body, wantErr: ErrWebhookSignatureInvalid},
+		{name: "tampered body", secret: "secret", timestamp: ts, signature: signature, body: []byte(`{}`), wantErr: ErrWebhookSignatureInvalid},
+		{name: "replayed", secret: "secret", timestamp: strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), signature: signature, body: body, wantErr: ErrWebhookSignatureExpired},
+		{name: "missing", secret: "secret", timestamp: ts, body: body, wantErr: ErrWebhookSignatureMissing},
+		{name: "bad timestamp", secret: "secret", timestamp: "yesterday", signature: signature, body: body, wantErr: ErrWebhookTimestampInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(tt.secret, tt.timestamp, tt.signature, tt.body, 5*time.Minute, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWebhook_Wants(t *testing.T) {
+	tournament := uuid.New()
+	created := TournamentCreated{TournamentID: tournament}
+	result := ResultRecorded{MatchID: uuid.New(), TournamentID: tournament}
+
+	tests := []struct {
+		name    string
+		webhook Webhook
+		event   Event
+		want    bool
+	}{
+		{name: "no filters", webhook: Webhook{}, event: created, want: true},
+		{name: "event type", webhook: Webhook{EventTypes: []EventType{EventResultRecorded}}, event: created, want: false},
+		{name: "tournament", webhook: Webhook{TournamentIDs: []uuid.UUID{tournament}}, event: result, want: true},
+		{name: "other tournament", webhook: Webhook{TournamentIDs: []uuid.UUID{uuid.New()}}, event: created, want: false},
+		{name: "casual game", webhook: Webhook{TournamentIDs: []uuid.UUID{tournament}}, event: ResultRecorded{MatchID: uuid.New()}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.webhook.Wants(tt.event); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPublicHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "hooks.example.com", want: true},
+		{host: "93.184.215.14", want: true},
+		{host: "[2606:2800:21f:cb07:6820:80da:af6b:8b2c]", want: true},
+		{host: "localhost", want: false},
+		{host: "api.localhost.", want: false},
+		{host: "127.0.0.1", want: false},
+		{host: "10.1.2.3", want: false},
+		{host: "172.16.0.1", want: false},
+		{host: "192.168.1.1", want: false},
+		{host: "169.254.169.254", want: false},
+		{host: "100.64.0.1", want: false},
+		{host: "0.0.0.0", want: false},
+		{host: "[::1]", want: false},
+		{host: "[::ffff:127.0.0.1]", want: false},
+		{host: "[fd00::1]", want: false},
+		{host: "[fe80::1]", want: false},
+		{host: "[64:ff9b::a00:1]", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := PublicHost(tt.host); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// localeFiles are the built in catalogs, one <locale>.json file per language
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// listConjunction is the catalog key of the word joining the last two items of a list
+const listConjunction = "list.or"
+
+// Catalog maps message codes to the message of one language, placeholders such as {min}
+// are replaced by the params given to Translate
+type Catalog map[string]string
+
+// Bundle holds the catalogs of every supported language
+type Bundle struct {
+	catalogs map[Locale]Catalog
+	locales  []Locale // the fallback first
+	fallback Locale
+}
+
+var (
+	defaultBundle     *Bundle
+	defaultBundleOnce sync.Once
+)
+
+// Default returns the bundle of the built in catalogs with DefaultLocale as the fallback
+func Default() *Bundle {
+	defaultBundleOnce.Do(func() {
+		b, err := NewBundle(localeFiles, DefaultLocale)
+		if err != nil {
+			panic(fmt.Sprintf("i18n: loading the built in catalogs: %v", err))
+		}
+		defaultBundle = b
+	})
+	return defaultBundle
+}
+
+// NewBundle loads every locales/<locale>.json catalog of fsys. The fallback catalog
+// is required and is used for the codes missing from the other catalogs
+func NewBundle(fsys fs.FS, fallback Locale) (*Bundle, error) {
+	files, err := fs.Glob(fsys, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		catalogs: make(map[Locale]Catalog),
+		fallback: fallback,
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		locale := Locale(strings.TrimSuffix(path.Base(file), ".json"))
+		b.catalogs[locale] = catalog
+	}
+
+	if _, ok := b.catalogs[fallback]; !ok {
+		return nil, fmt.Errorf("no catalog for the fallback locale %q", fallback)
+	}
+
+	for locale := range b.catalogs {
+		if locale != fallback {
+			b.locales = append(b.locales, locale)
+		}
+	}
+	sort.Slice(b.locales, func(i, j int) bool { return b.locales[i] < b.locales[j] })
+	b.locales = append([]Locale{fallback}, b.locales...)
+
+	return b, nil
+}
+
+// Locales returns the supported locales, the fallback first
+func (b *Bundle) Locales() []Locale {
+	return append([]Locale(nil), b.locales...)
+}
+
+// Supports reports whether there is a catalog for the locale
+func (b *Bundle) Supports(locale Locale) bool {
+	_, ok := b.catalogs[locale]
+	return ok
+}
+
+// Negotiate picks the supported locale the client prefers from an Accept-Language header
+func (b *Bundle) Negotiate(header string) Locale {
+	return Negotiate(header, b.locales)
+}
+
+// Translate returns the message of the code in the locale, falling back to the fallback
+// catalog and then to the code itself. Slices of strings in params are written as a list,
+// e.g. "standard, rapid or blitz"
+func (b *Bundle) Translate(locale Locale, code string, params map[string]any) string {
+	catalog, ok := b.catalogs[locale]
+	if !ok {
+		locale, catalog = b.fallback, b.catalogs[b.fallback]
+	}
+
+	message, ok := catalog[code]
+	if !ok {
+		message, ok = b.catalogs[b.fallback][code]
+		if !ok {
+			return code
+		}
+	}
+
+	if len(params) == 0 {
+		return message
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", b.format(locale, value))
+	}
+
+	return strings.NewReplacer(replacements...).Replace(message)
+}
+
+func (b *Bundle) format(locale Locale, value any) string {
+	items, ok := value.([]string)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+
+	conjunction := b.Translate(locale, listConjunction, nil)
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
@@ -0,0 +1,90 @@
+// Package i18n holds the message catalogs of the user facing messages and the locale negotiation.
+// Messages are looked up by a stable code, adding a language is adding a catalog to locales/
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a BCP 47 primary language tag such as "ca"
+type Locale string
+
+const (
+	LocaleCatalan Locale = "ca"
+	LocaleSpanish Locale = "es"
+	LocaleEnglish Locale = "en"
+
+	// DefaultLocale is used when the client does not ask for a supported locale
+	DefaultLocale = LocaleEnglish
+)
+
+type localeKey struct{}
+
+// WithLocale stores the negotiated locale of the request in the context
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale of the request, DefaultLocale if none was negotiated
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Negotiate picks the supported locale the client prefers from an Accept-Language header,
+// e.g. "ca-ES,ca;q=0.9,es;q=0.8,en;q=0.5". Region subtags are ignored so "es-MX" gets
+// "es", and when nothing matches the first supported locale is returned
+func Negotiate(header string, supported []Locale) Locale {
+	type weighted struct {
+		locale Locale
+		q      float64
+	}
+
+	var prefs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(tag, "-")
+		prefs = append(prefs, weighted{locale: Locale(strings.ToLower(primary)), q: q})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	for _, pref := range prefs {
+		if pref.locale == "*" && len(supported) > 0 {
+			return supported[0]
+		}
+		for _, locale := range supported {
+			if pref.locale == locale {
+				return locale
+			}
+		}
+	}
+
+	if len(supported) > 0 {
+		return supported[0]
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []Locale{LocaleEnglish, LocaleCatalan, LocaleSpanish}
+
+	tests := []struct {
+		name   string
+		header string
+		want   Locale
+	}{
+		{name: "empty header", header: "", want: LocaleEnglish},
+		{name: "exact match", header: "ca", want: LocaleCatalan},
+		{name: "region is ignored", header: "es-MX", want: LocaleSpanish},
+		{name: "highest q wins", header: "en;q=0.5, ca-ES;q=0.9, es;q=0.8", want: LocaleCatalan},
+		{name: "order breaks ties", header: "es, ca", want: LocaleSpanish},
+		{name: "unsupported are skipped", header: "fr-FR, de;q=0.9, es;q=0.1", want: LocaleSpanish},
+		{name: "q of zero is refused", header: "ca;q=0, es;q=0.2", want: LocaleSpanish},
+		{name: "wildcard gets the first supported", header: "fr, *;q=0.5", want: LocaleEnglish},
+		{name: "nothing supported", header: "fr, de", want: LocaleEnglish},
+		{name: "case insensitive", header: "CA-es", want: LocaleCatalan},
+		{name: "malformed q is skipped", header: "ca;q=abc, es", want: LocaleSpanish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header, supported))
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("should default when no locale was negotiated", func(t *testing.T) {
+		assert.Equal(t, DefaultLocale, FromContext(context.Background()))
+	})
+
+	t.Run("should return the stored locale", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), LocaleCatalan)
+		assert.Equal(t, LocaleCatalan, FromContext(ctx))
+	})
+}
+
+func TestBundle_Translate(t *testing.T) {
+	bundle := Default()
+
+	tests := []struct {
+		name   string
+		locale Locale
+		code   string
+		params map[string]any
+		want   string
+	}{
+		{name: "english", locale: LocaleEnglish, code: "required", want: "is required"},
+		{name: "catalan", locale: LocaleCatalan, code: "required", want: "és obligatori"},
+		{name: "spanish", locale: LocaleSpanish, code: "required", want: "es obligatorio"},
+		{name: "params", locale: LocaleEnglish, code: "between", params: map[string]any{"min": 1, "max": 10}, want: "must be between 1 and 10"},
+		{name: "list", locale: LocaleEnglish, code: "one_of", params: map[string]any{"values": []string{"standard", "rapid", "blitz"}}, want: "must be one of standard, rapid or blitz"},
+		{name: "localized list", locale: LocaleCatalan, code: "one_of", params: map[string]any{"values": []string{"a", "b"}}, want: "ha de ser un de a o b"},
+		{name: "unsupported locale falls back", locale: Locale("fr"), code: "required", want: "is required"},
+		{name: "unknown code is returned as is", locale: LocaleEnglish, code: "no_such_code", want: "no_such_code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, bundle.Translate(tt.locale, tt.code, tt.params))
+		})
+	}
+}
+
+func TestBundle_CatalogsAreComplete(t *testing.T) {
+	bundle := Default()
+	fallback := bundle.catalogs[bundle.fallback]
+
+	for _, locale := range bundle.Locales() {
+		catalog := bundle.catalogs[locale]
+		for code := range fallback {
+			assert.NotEmpty(t, catalog[code], "%s is missing %q", locale, code)
+		}
+		for code := range catalog {
+			_, ok := fallback[code]
+			assert.True(t, ok, "%s has %q which is not in the %s catalog", locale, code, bundle.fallback)
+		}
+	}
+}
+
+func TestNewBundle(t *testing.T) {
+	t.Run("should load the catalogs with the fallback first", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/en.json": {Data: []byte(`{"hello": "hello"}`)},
+			"locales/pt.json": {Data: []byte(`{"hello": "olá"}`)},
+			"locales/ca.json": {Data: []byte(`{}`)},
+		}
+
+		bundle, err := NewBundle(fsys, LocaleEnglish)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Locale{"en", "ca", "pt"}, bundle.Locales())
+		assert.True(t, bundle.Supports("pt"))
+		assert.Equal(t, "olá", bundle.Translate("pt", "hello", nil))
+		assert.Equal(t, "hello", bundle.Translate(LocaleCatalan, "hello", nil))
+	})
+
+	t.Run("should fail without a fallback catalog", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/ca.json": {Data: []byte(`{}`)},
+		}
+
+		_, err := NewBundle(fsys, LocaleEnglish)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on a malformed catalog", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/en.json": {Data: []byte(`{"hello":`)},
+		}
+
+		_, err := NewBundle(fsys, LocaleEnglish)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "net/http"
+
+// AdminHandler is for the operators of the api, its routes are only mounted when an admin
+// token is configured
+type AdminHandler interface {
+	LogLevelHandler(w http.ResponseWriter, r *http.Request)
+	SetLogLevelHandler(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,57 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/announcement"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// AnnouncementHandler is for our incomming http requests
+type AnnouncementHandler interface {
+	ImportChatHandler(w http.ResponseWriter, r *http.Request)
+	FindImportHandler(w http.ResponseWriter, r *http.Request)
+	ListAnnouncementsHandler(w http.ResponseWriter, r *http.Request)
+	FindAnnouncementHandler(w http.ResponseWriter, r *http.Request)
+	ConfirmAnnouncementHandler(w http.ResponseWriter, r *http.Request)
+	RejectAnnouncementHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// AnnouncementServicer is for our application layer
+type AnnouncementServicer interface {
+	// ImportChat queues the import of a chat export in the background, the messages that look
+	// like the announcement of a chess event are queued for review, the ones already in the
+	// queue are skipped
+	ImportChat(ctx context.Context, cmd commands.ImportChatCommand) (domain.ImportJob[domain.ChatImportSummary], error)
+	// FindImport returns how an import of a chat export is going
+	FindImport(ctx context.Context, cmd commands.FindImportCommand) (domain.ImportJob[domain.ChatImportSummary], error)
+	ListAnnouncements(ctx context.Context, cmd commands.ListAnnouncementsCommand) ([]domain.Announcement, error)
+	FindAnnouncement(ctx context.Context, cmd commands.FindAnnouncementCommand) (domain.Announcement, error)
+	// ConfirmAnnouncement creates a draft tournament with the details of the announcement
+	ConfirmAnnouncement(ctx context.Context, cmd commands.ConfirmAnnouncementCommand) (domain.Announcement, error)
+	RejectAnnouncement(ctx context.Context, cmd commands.RejectAnnouncementCommand) (domain.Announcement, error)
+}
+
+// ChatExportReader reads an exported chat from a local file
+type ChatExportReader interface {
+	Read(ctx context.Context, file domain.ChatExportFile) (domain.ChatExport, error)
+}
+
+// AnnouncementRepository is for our persistence layer
+type AnnouncementRepository interface {
+	CreateAnnouncement(announcement domain.Announcement) (domain.Announcement, error)
+	UpdateAnnouncement(announcement domain.Announcement) (domain.Announcement, error)
+	FindAnnouncement(id uuid.UUID) (domain.Announcement, error)
+	// HasFingerprint reports whether an announcement with the fingerprint is stored, whatever its status
+	HasFingerprint(fingerprint string) (bool, error)
+	// ListAnnouncements returns the announcements that pass the filter ordered by PostedAt then ID
+	ListAnnouncements(filter domain.AnnouncementFilter) ([]domain.Announcement, error)
+}
+
+// AnnouncementRepositoryProvider is an interface for providing thread safe access to the announcement repository
+type AnnouncementRepositoryProvider interface {
+	WriteTx(func(AnnouncementRepository) error) error
+	ReadTx(func(AnnouncementRepository) error) error
+}
@@ -1,11 +1,30 @@
 package ports
 
 import (
-// "context"
+	"context"
 
-// "github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/ctfrancia/maple/internal/core/domain"
 )
 
+// Authenticator tells who the bearer token of a request belongs to
+type Authenticator interface {
+	// Authenticate returns domain.ErrInvalidCredentials when the token is nobody's
+	Authenticate(ctx context.Context, token string) (domain.Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal stores who the request is authenticated as in the context
+func WithPrincipal(ctx context.Context, principal domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext is who the request is authenticated as, false when it is anonymous
+func PrincipalFromContext(ctx context.Context) (domain.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(domain.Principal)
+	return principal, ok
+}
+
 /*
 // AuthenticationServicer defines the authentication use case
 type AuthenticationServicer interface {
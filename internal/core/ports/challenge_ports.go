@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/challenge"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// ChallengeHandler is for our incomming http requests
+type ChallengeHandler interface {
+	CreateChallengeHandler(w http.ResponseWriter, r *http.Request)
+	FindChallengeHandler(w http.ResponseWriter, r *http.Request)
+	ListChallengesHandler(w http.ResponseWriter, r *http.Request)
+	AcceptChallengeHandler(w http.ResponseWriter, r *http.Request)
+	CancelChallengeHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// ChallengeServicer is for our application layer
+type ChallengeServicer interface {
+	CreateChallenge(ctx context.Context, cmd commands.CreateChallengeCommand) (domain.Challenge, error)
+	FindChallenge(ctx context.Context, cmd commands.FindChallengeCommand) (domain.Challenge, error)
+	// ListChallenges returns the open challenges that pass the filters of the command
+	ListChallenges(ctx context.Context, cmd commands.ListChallengesCommand) ([]domain.Challenge, error)
+	// AcceptChallenge creates the match of the challenge, only the first player to accept it
+	// gets it: the others get ErrChallengeNotOpen
+	AcceptChallenge(ctx context.Context, cmd commands.AcceptChallengeCommand) (domain.Challenge, error)
+	CancelChallenge(ctx context.Context, cmd commands.CancelChallengeCommand) (domain.Challenge, error)
+}
+
+// ChallengeRepository is for our persistence layer
+type ChallengeRepository interface {
+	EventRecorder
+	CreateChallenge(challenge domain.Challenge) (domain.Challenge, error)
+	// UpdateChallenge only changes a challenge that is still open, every change closes it. It
+	// fails with ErrChallengeNotOpen when the stored challenge was already closed
+	UpdateChallenge(challenge domain.Challenge) (domain.Challenge, error)
+	// ModerateChallenge sets the moderation status whatever the status of the challenge is
+	ModerateChallenge(id uuid.UUID, status domain.ModerationStatus) (domain.Challenge, error)
+	FindChallenge(id uuid.UUID) (domain.Challenge, error)
+	// ListChallenges returns the challenges that pass the filter ordered by StartsAt then ID
+	ListChallenges(filter domain.ChallengeFilter) ([]domain.Challenge, error)
+}
+
+// ChallengeRepositoryProvider is an interface for providing thread safe access to the challenge repository
+type ChallengeRepositoryProvider interface {
+	WriteTx(func(ChallengeRepository) error) error
+	ReadTx(func(ChallengeRepository) error) error
+}
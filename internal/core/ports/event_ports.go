@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// EventRecorder is implemented by the repositories whose changes raise events. The events are
+// recorded in the transaction of the change and reach the outbox only if the transaction succeeds
+type EventRecorder interface {
+	RecordEvents(events ...domain.Event) error
+}
+
+// OutboxRepository stores the events until every subscriber handled them
+type OutboxRepository interface {
+	Append(events ...domain.Event) error
+	// Pending returns the entries neither dispatched nor dead ordered by ID, limit <= 0 returns all
+	Pending(limit int) ([]domain.OutboxEntry, error)
+	// Due returns the pending entries whose next attempt is not after now ordered by ID, leaving
+	// out the aggregates whose oldest pending entry is not due yet. limit <= 0 returns all
+	Due(now time.Time, limit int) ([]domain.OutboxEntry, error)
+	MarkDelivered(id int64, subscriber string) error
+	MarkDispatched(id int64, at time.Time) error
+	MarkFailed(id int64, reason string, nextAttempt time.Time) error
+	MarkDead(id int64, reason string, at time.Time) error
+	// Prune removes the entries dispatched or dead before the time and returns how many
+	Prune(before time.Time) (int, error)
+}
+
+// OutboxRepositoryProvider is an interface for providing thread safe access to the outbox
+type OutboxRepositoryProvider interface {
+	WriteTx(func(OutboxRepository) error) error
+	ReadTx(func(OutboxRepository) error) error
+}
+
+// EventHandler handles an event, returning an error has it delivered again later so handlers
+// must be idempotent
+type EventHandler func(ctx context.Context, event domain.Event) error
+
+// EventBus delivers the events of the outbox to the subscribers
+type EventBus interface {
+	// Subscribe registers the handler under a unique name for the event types, every type when none is given
+	Subscribe(name string, handler EventHandler, types ...domain.EventType) error
+}
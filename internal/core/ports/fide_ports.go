@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/fide"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// FideHandler is for our incomming http requests
+type FideHandler interface {
+	ImportRatingListHandler(w http.ResponseWriter, r *http.Request)
+	FindImportHandler(w http.ResponseWriter, r *http.Request)
+	FindRecordHandler(w http.ResponseWriter, r *http.Request)
+	MatchCandidatesHandler(w http.ResponseWriter, r *http.Request)
+	ConfirmMatchHandler(w http.ResponseWriter, r *http.Request)
+	RefreshTournamentRatingsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// FideServicer is for our application layer
+type FideServicer interface {
+	// ImportRatingList queues the import of a rating list in the background
+	ImportRatingList(ctx context.Context, cmd commands.ImportRatingListCommand) (domain.ImportJob[domain.FideImportSummary], error)
+	// FindImport returns how an import of a rating list is going
+	FindImport(ctx context.Context, cmd commands.FindImportCommand) (domain.ImportJob[domain.FideImportSummary], error)
+	FindRecord(ctx context.Context, cmd commands.FindRecordCommand) (domain.FideRecord, error)
+	// MatchCandidates returns the fide records that look like the player, best first
+	MatchCandidates(ctx context.Context, cmd commands.MatchCandidatesCommand) ([]domain.FideMatchCandidate, error)
+	// ConfirmMatch links a player to a fide id once someone confirmed they are the same person
+	ConfirmMatch(ctx context.Context, cmd commands.ConfirmMatchCommand) (domain.Player, error)
+	// RefreshTournamentRatings sets the fide ratings of every linked entrant to the ones of the rating period
+	RefreshTournamentRatings(ctx context.Context, cmd commands.RefreshTournamentRatingsCommand) (int, error)
+}
+
+// FideListReader reads an official fide rating list from a local file, calling fn for every record
+type FideListReader interface {
+	Read(ctx context.Context, file domain.FideListFile, fn func(domain.FideRecord) error) error
+}
+
+// FideRepository is for our persistence layer
+type FideRepository interface {
+	// UpsertRecord inserts the record or replaces the one with the same fide id and period, reporting which one happened
+	UpsertRecord(record domain.FideRecord) (inserted bool, err error)
+	// FindRecord returns the record of the period, an empty period returns the latest one
+	FindRecord(fideID int, period string) (domain.FideRecord, error)
+	// ListRecordsByNameKey returns the records of the period whose surname starts like key, see FideNameKey
+	ListRecordsByNameKey(key string, period string) ([]domain.FideRecord, error)
+	// LatestPeriod returns the newest rating period imported, empty if none
+	LatestPeriod() (string, error)
+	HasPeriod(period string) (bool, error)
+}
+
+// FideRepositoryProvider is an interface for providing thread safe access to the fide repository
+type FideRepositoryProvider interface {
+	WriteTx(func(FideRepository) error) error
+	ReadTx(func(FideRepository) error) error
+}
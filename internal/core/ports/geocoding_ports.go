@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// Geocoder resolves addresses to places and places to addresses, both fail with
+// ErrGeocodeNotFound when nothing matches
+type Geocoder interface {
+	Geocode(ctx context.Context, query domain.GeocodeQuery) (domain.Place, error)
+	// Reverse returns the place closest to the coordinates
+	Reverse(ctx context.Context, latitude, longitude float64) (domain.Place, error)
+}
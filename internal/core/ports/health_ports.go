@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// HealthProbe checks a dependency, an error marks it down
+type HealthProbe func(ctx context.Context) error
+
+// Pinger is implemented by the adapters that can tell whether what they depend on answers
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthCheckConfig registers a probe. The liveness ones tell the process is stuck and has
+// to be restarted, every probe counts for the readiness
+type HealthCheckConfig struct {
+	Name     string
+	Probe    HealthProbe
+	Timeout  time.Duration // zero is the default of the registry
+	Liveness bool
+}
+
+type HealthChecker interface {
+	Register(check HealthCheckConfig)
+	Liveness(ctx context.Context) domain.HealthReport
+	Readiness(ctx context.Context) domain.HealthReport
+}
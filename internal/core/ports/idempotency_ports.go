@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// IdempotencyStore keeps the responses of the requests sent with an Idempotency-Key, the
+// expired records are as good as missing
+type IdempotencyStore interface {
+	// Begin claims the key for a first request and returns nil, or returns the record of the key
+	// when its first request completed with the same fingerprint. It fails with
+	// ErrIdempotencyKeyInFlight while the first request has no response yet and with
+	// ErrIdempotencyKeyReused when the fingerprint is another one
+	Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error)
+	// Complete stores the response of the request that claimed the key
+	Complete(ctx context.Context, key string, response domain.IdempotentResponse) error
+	// Release forgets a key whose request did not complete, its retries run as a first request
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"net/http"
+
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// LiveHandler is for the spectators following a tournament live
+type LiveHandler interface {
+	// StreamHandler streams the live updates as server sent events
+	StreamHandler(w http.ResponseWriter, r *http.Request)
+	// WebSocketHandler streams the live updates over a websocket
+	WebSocketHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// LiveBroadcaster fans the live updates of a tournament out to its spectators
+type LiveBroadcaster interface {
+	Publish(update domain.LiveUpdate) error
+}
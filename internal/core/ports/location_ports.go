@@ -0,0 +1,63 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/location"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// LocationHandler is for our incomming http requests
+type LocationHandler interface {
+	CreateLocationHandler(w http.ResponseWriter, r *http.Request)
+	UpdateLocationHandler(w http.ResponseWriter, r *http.Request)
+	FindLocationHandler(w http.ResponseWriter, r *http.Request)
+	ListLocationsHandler(w http.ResponseWriter, r *http.Request)
+	DeleteLocationHandler(w http.ResponseWriter, r *http.Request)
+	CheckLocationHandler(w http.ResponseWriter, r *http.Request)
+	FindDuplicatesHandler(w http.ResponseWriter, r *http.Request)
+	MergeLocationsHandler(w http.ResponseWriter, r *http.Request)
+	GeocodeHandler(w http.ResponseWriter, r *http.Request)
+	ReverseGeocodeHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// LocationServicer is for our application layer
+type LocationServicer interface {
+	// CreateLocation registers a venue, what it is missing is filled with the geocoder. It fails with ErrLocationDuplicate when a similar one
+	// is already registered unless the command forces it
+	CreateLocation(ctx context.Context, cmd commands.CreateLocationCommand) (domain.Location, error)
+	// UpdateLocation also updates the copy kept by the tournaments and matches held there
+	UpdateLocation(ctx context.Context, cmd commands.UpdateLocationCommand) (domain.Location, error)
+	FindLocation(ctx context.Context, cmd commands.FindLocationCommand) (domain.Location, error)
+	ListLocations(ctx context.Context, cmd commands.ListLocationsCommand) ([]domain.Location, error)
+	// DeleteLocation fails with ErrLocationInUse while tournaments or matches reference it
+	DeleteLocation(ctx context.Context, cmd commands.DeleteLocationCommand) error
+	// CheckLocation returns the registered locations that look like the one about to be created, best first
+	CheckLocation(ctx context.Context, cmd commands.CreateLocationCommand) ([]domain.LocationDuplicate, error)
+	// FindDuplicates returns the other registered locations that look like the location, best first
+	FindDuplicates(ctx context.Context, cmd commands.FindLocationCommand) ([]domain.LocationDuplicate, error)
+	// MergeLocations re-points every tournament and match of the duplicate to the kept
+	// location and removes the duplicate
+	MergeLocations(ctx context.Context, cmd commands.MergeLocationsCommand) (domain.LocationMerge, error)
+	// Geocode fails with ErrGeocoderUnavailable when no geocoder is configured
+	Geocode(ctx context.Context, cmd commands.GeocodeCommand) (domain.Place, error)
+	ReverseGeocode(ctx context.Context, cmd commands.ReverseGeocodeCommand) (domain.Place, error)
+}
+
+// LocationRepository is for our persistence layer
+type LocationRepository interface {
+	CreateLocation(location domain.Location) (domain.Location, error)
+	UpdateLocation(location domain.Location) (domain.Location, error)
+	FindLocation(id uuid.UUID) (domain.Location, error)
+	DeleteLocation(id uuid.UUID) error
+	// ListLocations returns the locations that pass the filter ordered by name then ID
+	ListLocations(filter domain.LocationFilter) ([]domain.Location, error)
+}
+
+// LocationRepositoryProvider is an interface for providing thread safe access to the location repository
+type LocationRepositoryProvider interface {
+	WriteTx(func(LocationRepository) error) error
+	ReadTx(func(LocationRepository) error) error
+}
@@ -34,3 +34,49 @@ func Error(key string, err error) LogField {
 func Any(key string, value any) LogField {
 	return LogField{Key: key, Value: value}
 }
+
+// LogLeveler changes the level of the logger while it runs
+type LogLeveler interface {
+	Level() string
+	// SetLevel takes debug, info, warn or error
+	SetLevel(level string) error
+}
+
+// the keys of the ids every line logged for a request carries
+const (
+	LogKeyRequestID    = "request_id"
+	LogKeyConsumerID   = "consumer_id"
+	LogKeyTournamentID = "tournament_id"
+	LogKeyTraceID      = "trace_id"
+	LogKeySpanID       = "span_id"
+)
+
+type logFieldsKey struct{}
+
+// WithLogFields adds fields to every line logged with the context, a field replaces the one
+// of the same key the context already had
+func WithLogFields(ctx context.Context, fields ...LogField) context.Context {
+	existing := LogFieldsFromContext(ctx)
+	merged := make([]LogField, 0, len(existing)+len(fields))
+	for _, field := range existing {
+		replaced := false
+		for _, f := range fields {
+			if f.Key == field.Key {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, field)
+		}
+	}
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, logFieldsKey{}, merged)
+}
+
+// LogFieldsFromContext are the fields added to the context with WithLogFields
+func LogFieldsFromContext(ctx context.Context) []LogField {
+	fields, _ := ctx.Value(logFieldsKey{}).([]LogField)
+	return fields
+}
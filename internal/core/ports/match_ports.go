@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/match"
+	commands "github.com/ctfrancia/maple/internal/application/commands/match"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// MatchHandler is for our incomming http requests
+type MatchHandler interface {
+	CreateMatchHandler(w http.ResponseWriter, r *http.Request)
+	FindMatchHandler(w http.ResponseWriter, r *http.Request)
+	ListMatchesHandler(w http.ResponseWriter, r *http.Request)
+	RecordResultHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// MatchServicer is for our application layer
+type MatchServicer interface {
+	CreateMatch(ctx context.Context, cmd commands.CreateMatchCommand) (domain.Match, error)
+	FindMatch(ctx context.Context, cmd commands.FindMatchCommand) (domain.Match, error)
+	ListMatches(ctx context.Context, cmd commands.ListMatchesCommand) ([]domain.Match, error)
+	RecordResult(ctx context.Context, cmd commands.RecordResultCommand) (domain.Match, error)
+}
+
+// MatchRepository is for our persistence layer
+type MatchRepository interface {
+	EventRecorder
+	CreateMatch(match domain.Match) (domain.Match, error)
+	UpdateMatch(match domain.Match) (domain.Match, error)
+	DeleteMatch(id uuid.UUID) error
+	FindMatch(id uuid.UUID) (domain.Match, error)
+	ListMatchesByPlayer(playerID uuid.UUID) ([]domain.Match, error)
+	// ListMatches returns the matches that pass the filter ordered by ID
+	ListMatches(filter domain.MatchFilter) ([]domain.Match, error)
+	// ListCompletedMatches returns every match with a final result ordered by CompletedAt then ID
+	ListCompletedMatches() ([]domain.Match, error)
+}
+
+// MatchRepositoryProvider is an interface for providing thread safe access to the match repository
+type MatchRepositoryProvider interface {
+	WriteTx(func(MatchRepository) error) error
+	ReadTx(func(MatchRepository) error) error
+}
+
+type MatchMapper interface {
+	MapToCreateCommand(dto dto.CreateMatchRequest) commands.CreateMatchCommand
+	MapToFindCommand(ID uuid.UUID) commands.FindMatchCommand
+	MapToRecordResultCommand(ID uuid.UUID, dto dto.RecordResultRequest) commands.RecordResultCommand
+}
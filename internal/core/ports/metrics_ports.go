@@ -0,0 +1,27 @@
+package ports
+
+// Metrics registers the instruments the services and adapters report to, the exporter behind
+// it is an adapter. The label values are given in the order of the label names registered
+type Metrics interface {
+	Counter(name, help string, labels ...string) Counter
+	Gauge(name, help string, labels ...string) Gauge
+	// GaugeFunc reads the value when the metrics are collected
+	GaugeFunc(name, help string, fn func() float64)
+	// Histogram with the upper bounds of the buckets, nil is the default latency buckets
+	Histogram(name, help string, buckets []float64, labels ...string) Histogram
+}
+
+// Counter only goes up
+type Counter interface {
+	Inc(labelValues ...string)
+	Add(delta float64, labelValues ...string)
+}
+
+type Gauge interface {
+	Set(value float64, labelValues ...string)
+	Add(delta float64, labelValues ...string)
+}
+
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
@@ -0,0 +1,62 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/moderation"
+	"github.com/ctfrancia/maple/internal/core/domain"
+)
+
+// ModerationHandler is for our incomming http requests
+type ModerationHandler interface {
+	ReportHandler(w http.ResponseWriter, r *http.Request)
+	ModerationQueueHandler(w http.ResponseWriter, r *http.Request)
+	ModerateHandler(w http.ResponseWriter, r *http.Request)
+	ModerationHistoryHandler(w http.ResponseWriter, r *http.Request)
+	FindStandingHandler(w http.ResponseWriter, r *http.Request)
+	ReinstateConsumerHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// ModerationServicer is for our application layer
+type ModerationServicer interface {
+	ListingPolicy
+	// Report flags a subject to the moderators, a reporter can only have one open report of it
+	Report(ctx context.Context, cmd commands.ReportCommand) (domain.Report, error)
+	// Queue returns the listings waiting for approval and the subjects with open reports, oldest first
+	Queue(ctx context.Context, cmd commands.ModerationQueueCommand) ([]domain.ModerationItem, error)
+	// Moderate sets the moderation status of the subject and resolves its open reports: approving
+	// dismisses them, rejecting or hiding upholds them and counts against the consumer that posted it
+	Moderate(ctx context.Context, cmd commands.ModerateCommand) (domain.ModerationDecision, error)
+	History(ctx context.Context, cmd commands.ModerationHistoryCommand) (domain.ModerationHistory, error)
+	FindStanding(ctx context.Context, cmd commands.FindStandingCommand) (domain.ConsumerStanding, error)
+	// ReinstateConsumer lifts a suspension and clears the upheld reports that led to it
+	ReinstateConsumer(ctx context.Context, cmd commands.ReinstateConsumerCommand) (domain.ConsumerStanding, error)
+}
+
+// ListingPolicy decides how the new listings of a consumer start in the moderation
+type ListingPolicy interface {
+	// Admit returns the moderation status a new listing of the consumer starts with, it fails
+	// with ErrConsumerSuspended when the consumer cannot post and ErrConsumerRequired without one
+	Admit(ctx context.Context, consumerID string) (domain.ModerationStatus, error)
+}
+
+// ModerationRepository is for our persistence layer
+type ModerationRepository interface {
+	CreateReport(report domain.Report) (domain.Report, error)
+	UpdateReport(report domain.Report) (domain.Report, error)
+	// ListReports returns the reports that pass the filter ordered by CreatedAt then ID
+	ListReports(filter domain.ReportFilter) ([]domain.Report, error)
+	CreateDecision(decision domain.ModerationDecision) (domain.ModerationDecision, error)
+	// ListDecisions returns the decisions on the subject oldest first
+	ListDecisions(subject domain.ModerationSubject) ([]domain.ModerationDecision, error)
+	// FindStanding never fails for a consumer nothing was moderated for, it returns a new standing
+	FindStanding(consumerID string) (domain.ConsumerStanding, error)
+	SaveStanding(standing domain.ConsumerStanding) (domain.ConsumerStanding, error)
+}
+
+// ModerationRepositoryProvider is an interface for providing thread safe access to the moderation repository
+type ModerationRepositoryProvider interface {
+	WriteTx(func(ModerationRepository) error) error
+	ReadTx(func(ModerationRepository) error) error
+}
@@ -0,0 +1,54 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/notification"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// Notifier delivers an email, e.g. through an smtp server
+type Notifier interface {
+	Send(ctx context.Context, email domain.Email) error
+}
+
+// NotificationHandler is for our incomming http requests
+type NotificationHandler interface {
+	FindPreferencesHandler(w http.ResponseWriter, r *http.Request)
+	UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request)
+	UnsubscribeHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// NotificationServicer is for our application layer
+type NotificationServicer interface {
+	FindPreferences(ctx context.Context, cmd commands.FindPreferencesCommand) (domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, cmd commands.UpdatePreferencesCommand) (domain.NotificationPreferences, error)
+	// Unsubscribe opts the player of the token out of every email, the notifications still queued are cancelled
+	Unsubscribe(ctx context.Context, cmd commands.UnsubscribeCommand) (domain.NotificationPreferences, error)
+}
+
+// NotificationRepository is for our persistence layer, it is the queue of the notifications
+// and the preferences of the players
+type NotificationRepository interface {
+	EnqueueNotification(notification domain.Notification) (domain.Notification, error)
+	UpdateNotification(notification domain.Notification) (domain.Notification, error)
+	// HasNotification reports whether a notification with the key was already queued
+	HasNotification(key string) (bool, error)
+	// DueNotifications returns the pending notifications whose next attempt is not after now, oldest first
+	DueNotifications(now time.Time, limit int) ([]domain.Notification, error)
+	// ListNotifications returns the notifications of the player newest first, limit <= 0 returns all
+	ListNotifications(playerID uuid.UUID, limit int) ([]domain.Notification, error)
+
+	FindPreferences(playerID uuid.UUID) (domain.NotificationPreferences, error)
+	FindPreferencesByToken(token string) (domain.NotificationPreferences, error)
+	SavePreferences(prefs domain.NotificationPreferences) (domain.NotificationPreferences, error)
+}
+
+// NotificationRepositoryProvider is an interface for providing thread safe access to the notification repository
+type NotificationRepositoryProvider interface {
+	WriteTx(func(NotificationRepository) error) error
+	ReadTx(func(NotificationRepository) error) error
+}
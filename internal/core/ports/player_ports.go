@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	dto "github.com/ctfrancia/maple/internal/adapters/http/handlers/dto/player"
+	commands "github.com/ctfrancia/maple/internal/application/commands/player"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// PlayerHandler is for our incomming http requests
+type PlayerHandler interface {
+	CreatePlayerHandler(w http.ResponseWriter, r *http.Request)
+	FindPlayerHandler(w http.ResponseWriter, r *http.Request)
+	UpdatePlayerHandler(w http.ResponseWriter, r *http.Request)
+	RecordRatingChangeHandler(w http.ResponseWriter, r *http.Request)
+	RatingHistoryHandler(w http.ResponseWriter, r *http.Request)
+	HeadToHeadHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// PlayerServicer is for our application layer
+type PlayerServicer interface {
+	CreatePlayer(ctx context.Context, cmd commands.CreatePlayerCommand) (domain.Player, error)
+	UpdatePlayer(ctx context.Context, cmd commands.UpdatePlayerCommand) (domain.Player, error)
+	FindPlayer(ctx context.Context, cmd commands.FindPlayerCommand) (domain.Player, error)
+	RecordRatingChange(ctx context.Context, cmd commands.RecordRatingChangeCommand) (domain.RatingChange, error)
+	RatingHistory(ctx context.Context, cmd commands.RatingHistoryCommand) ([]domain.RatingChange, error)
+	HeadToHead(ctx context.Context, cmd commands.HeadToHeadCommand) (domain.HeadToHead, error)
+}
+
+// PlayerRepository is for our persistence layer
+type PlayerRepository interface {
+	CreatePlayer(player domain.Player) (domain.Player, error)
+	UpdatePlayer(player domain.Player) (domain.Player, error)
+	FindPlayer(id uuid.UUID) (domain.Player, error)
+	FindPlayerByFideID(fideID int) (domain.Player, error)
+	AddRatingChange(change domain.RatingChange) (domain.RatingChange, error)
+	// ListRatingHistory returns the changes oldest first, an empty source or type matches all
+	ListRatingHistory(playerID uuid.UUID, source domain.RatingSource, rt domain.RatingType) ([]domain.RatingChange, error)
+	DeleteRatingHistory(source domain.RatingSource) error
+}
+
+// PlayerRepositoryProvider is an interface for providing thread safe access to the player repository
+type PlayerRepositoryProvider interface {
+	WriteTx(func(PlayerRepository) error) error
+	ReadTx(func(PlayerRepository) error) error
+}
+
+type PlayerMapper interface {
+	MapToCreateCommand(dto dto.CreatePlayerRequest) commands.CreatePlayerCommand
+	MapToUpdateCommand(ID uuid.UUID, dto dto.UpdatePlayerRequest) commands.UpdatePlayerCommand
+	MapToFindCommand(ID uuid.UUID) commands.FindPlayerCommand
+	MapToRecordRatingChangeCommand(ID uuid.UUID, dto dto.RecordRatingChangeRequest) commands.RecordRatingChangeCommand
+}
@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/rating"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// RatingHandler is for our incomming http requests
+type RatingHandler interface {
+	ListRatingsHandler(w http.ResponseWriter, r *http.Request)
+	FindRatingHandler(w http.ResponseWriter, r *http.Request)
+	RecomputeRatingsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// RatingServicer is the maple rating engine
+type RatingServicer interface {
+	// ApplyMatch updates the ratings of both players after a rated match is completed
+	ApplyMatch(ctx context.Context, match domain.Match) error
+	// HandleEvent updates the ratings after the result of a rated match is recorded, see domain.ResultRecorded
+	HandleEvent(ctx context.Context, event domain.Event) error
+	// Recompute rebuilds every maple rating from the match history and returns how many matches were replayed
+	Recompute(ctx context.Context) (int, error)
+	FindRating(ctx context.Context, cmd commands.FindRatingCommand) (domain.MapleRating, error)
+	ListRatings(ctx context.Context, cmd commands.ListRatingsCommand) ([]domain.MapleRating, error)
+}
+
+// RatingCalculator is a rating algorithm that can be plugged into the rating engine
+type RatingCalculator interface {
+	Algorithm() domain.RatingAlgorithm
+	// NewRating is the rating of a player that has not played in the pool yet
+	NewRating(playerID uuid.UUID, pool domain.RatingType) domain.MapleRating
+	// Rate returns the new ratings of white and black after a game where white scored score (1, 0.5 or 0)
+	Rate(white, black domain.MapleRating, score float64) (domain.MapleRating, domain.MapleRating)
+}
+
+// RatingRepository is for our persistence layer
+type RatingRepository interface {
+	FindRating(playerID uuid.UUID, pool domain.RatingType) (domain.MapleRating, error)
+	SaveRating(rating domain.MapleRating) (domain.MapleRating, error)
+	// ListRatings returns the ratings of the pool, highest first
+	ListRatings(pool domain.RatingType) ([]domain.MapleRating, error)
+	DeleteRatings() error
+}
+
+// RatingRepositoryProvider is an interface for providing thread safe access to the rating repository
+type RatingRepositoryProvider interface {
+	WriteTx(func(RatingRepository) error) error
+	ReadTx(func(RatingRepository) error) error
+}
@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/relay"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// RelayHandler is for our incomming http requests
+type RelayHandler interface {
+	StartRelayHandler(w http.ResponseWriter, r *http.Request)
+	FindRelayHandler(w http.ResponseWriter, r *http.Request)
+	RecordMoveHandler(w http.ResponseWriter, r *http.Request)
+	FinishRelayHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// RelayServicer is for our application layer
+type RelayServicer interface {
+	StartRelay(ctx context.Context, cmd commands.StartRelayCommand) (domain.Relay, error)
+	FindRelay(ctx context.Context, cmd commands.FindRelayCommand) (domain.Relay, error)
+	// RecordMove validates the move against the position, a move that ends the game by the
+	// rules finishes the relay and records the result of the match with its pgn
+	RecordMove(ctx context.Context, cmd commands.RecordMoveCommand) (domain.Relay, error)
+	FinishRelay(ctx context.Context, cmd commands.FinishRelayCommand) (domain.Relay, error)
+}
+
+// RelayRepository is for our persistence layer
+type RelayRepository interface {
+	// CreateRelay fails with ErrRelayAlreadyStarted when the match already has a relay
+	CreateRelay(relay domain.Relay) (domain.Relay, error)
+	UpdateRelay(relay domain.Relay) (domain.Relay, error)
+	FindRelay(matchID uuid.UUID) (domain.Relay, error)
+}
+
+// RelayRepositoryProvider is an interface for providing thread safe access to the relay repository
+type RelayRepositoryProvider interface {
+	WriteTx(func(RelayRepository) error) error
+	ReadTx(func(RelayRepository) error) error
+}
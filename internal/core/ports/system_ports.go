@@ -2,19 +2,25 @@
 package ports
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/ctfrancia/maple/internal/application/validation"
 	"github.com/ctfrancia/maple/internal/core/domain"
 )
 
 type SystemHandler interface {
 	HealthHandler(w http.ResponseWriter, r *http.Request)
+	LivezHandler(w http.ResponseWriter, r *http.Request)
+	ReadyzHandler(w http.ResponseWriter, r *http.Request)
 	LoginHandler(w http.ResponseWriter, r *http.Request)
 	NewConsumerHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type SystemServicer interface {
 	ProcessSystemHealthRequest() domain.System
+	Liveness(ctx context.Context) domain.HealthReport
+	Readiness(ctx context.Context) domain.HealthReport
 	Login(username, password string) (any, error)
 	CreateNewConsumer(consumer domain.NewAPIConsumer) (domain.NewAPIConsumer, error)
 	NewAPIConsumer(consumer domain.NewAPIConsumer) (domain.NewAPIConsumer, error)
@@ -27,9 +33,11 @@ type SystemAdapter interface {
 type SystemResponder interface {
 	WriteJSON(w http.ResponseWriter, status int, data any, headers http.Header) error
 	ErrorResponse(w http.ResponseWriter, r *http.Request, status int, message any)
-	FailedValidationResponse(w http.ResponseWriter, r *http.Request, errs map[string]string)
+	ErrorCodeResponse(w http.ResponseWriter, r *http.Request, status int, code string)
+	FailedValidationResponse(w http.ResponseWriter, r *http.Request, errs validation.Errors)
 	BadRequestResponse(w http.ResponseWriter, r *http.Request, err error)
 	ServerErrorResponse(w http.ResponseWriter, r *http.Request, err error)
+	NotFoundResponse(w http.ResponseWriter, r *http.Request)
 	InvalidCredentialsResponse(w http.ResponseWriter, r *http.Request)
 	ConflictResponse(w http.ResponseWriter, r *http.Request)
 }
@@ -17,23 +17,36 @@ type TournamentHandler interface {
 	ListTournamentsHandler(w http.ResponseWriter, r *http.Request)
 	UpdateTournamentHandler(w http.ResponseWriter, r *http.Request)
 	DeleteTournamentHandler(w http.ResponseWriter, r *http.Request)
+	// TransitionTournamentHandler takes the {action} of the route, e.g. POST /{id}/start
+	TransitionTournamentHandler(w http.ResponseWriter, r *http.Request)
+	RegisterPlayerHandler(w http.ResponseWriter, r *http.Request)
+	PairRoundHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // TournamentServicer is for our application layer
 type TournamentServicer interface {
 	CreateTournament(ctx context.Context, tournament commands.CreateTournamentCommand) (domain.Tournament, error)
-	ListTournaments(ctx context.Context) ([]domain.Tournament, error)
+	ListTournaments(ctx context.Context, cmd commands.ListTournamentsCommand) ([]domain.Tournament, error)
 	FindTournament(ctx context.Context, cmd commands.FindTournamentCommand) (domain.Tournament, error)
+	TransitionTournament(ctx context.Context, cmd commands.TransitionTournamentCommand) (domain.Tournament, error)
+	RegisterPlayer(ctx context.Context, cmd commands.RegisterPlayerCommand) (domain.Tournament, error)
+	PairRound(ctx context.Context, cmd commands.PairRoundCommand) (domain.PairedRound, error)
 }
 
 // TournamentRepository  is for our persistence layer
 type TournamentRepository interface {
+	EventRecorder
 	CreateTournament(tournament domain.Tournament) (domain.Tournament, error)
 	FindTournament(id uuid.UUID) (domain.Tournament, error)
-	ListTournaments(params any) ([]domain.Tournament, error)
+	UpdateTournament(tournament domain.Tournament) (domain.Tournament, error)
+	// ListTournaments returns the tournaments that pass the filter, newest first
+	ListTournaments(filter domain.TournamentFilter) ([]domain.Tournament, error)
 }
 
 type TournamentMapper interface {
-	MapToCommand(dto dto.CreateTournamentRequest) commands.CreateTournamentCommand
+	MapToCommand(consumerID string, dto dto.CreateTournamentRequest) commands.CreateTournamentCommand
 	MapToFindCommand(ID uuid.UUID) commands.FindTournamentCommand
+	MapToTransitionCommand(ID uuid.UUID, action domain.TournamentAction, actor domain.Principal, req dto.TransitionTournamentRequest) commands.TransitionTournamentCommand
+	MapToRegisterPlayerCommand(ID uuid.UUID, req dto.RegisterPlayerRequest) commands.RegisterPlayerCommand
+	MapToPairRoundCommand(ID uuid.UUID, req dto.PairRoundRequest) commands.PairRoundCommand
 }
@@ -0,0 +1,92 @@
+package ports
+
+import "context"
+
+// SpanKind is the role of the span in the trace, as the OpenTelemetry kinds
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota + 1
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// Tracer starts the spans of a trace, the exporter behind it is an adapter. It travels in the
+// context so every layer a request goes through can add its spans, see WithTracer
+type Tracer interface {
+	// Start a span, a child of the span of ctx when there is one
+	Start(ctx context.Context, name string, kind SpanKind, fields ...LogField) (context.Context, Span)
+	// Inject writes the trace context of ctx to the carrier, the headers of an outgoing request
+	Inject(ctx context.Context, carrier map[string]string)
+	// Extract reads the trace context of the carrier, the spans started from the context
+	// returned continue that trace
+	Extract(ctx context.Context, carrier map[string]string) context.Context
+}
+
+type Span interface {
+	SetName(name string)
+	SetAttributes(fields ...LogField)
+	// RecordError marks the span as failed, a nil error is ignored
+	RecordError(err error)
+	End()
+	// TraceID and SpanID are in hex, empty when the context is not traced
+	TraceID() string
+	SpanID() string
+}
+
+type tracerKey struct{}
+
+type spanKey struct{}
+
+// WithTracer stores the tracer in the context, the spans started from it are traced
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// TracerFromContext is the tracer of the context, nil when the context is not traced
+func TracerFromContext(ctx context.Context) Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(Tracer)
+	return tracer
+}
+
+// WithSpan stores the span as the current one of the context, for the tracers
+func WithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext is the current span of the context, a span that records nothing when
+// there is none
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return nopSpan{}
+}
+
+// StartSpan starts a span with the tracer of the context, it records nothing when the context
+// is not traced
+func StartSpan(ctx context.Context, name string, kind SpanKind, fields ...LogField) (context.Context, Span) {
+	tracer := TracerFromContext(ctx)
+	if tracer == nil {
+		return ctx, nopSpan{}
+	}
+	return tracer.Start(ctx, name, kind, fields...)
+}
+
+// InjectTrace writes the trace context of ctx to the headers of an outgoing request
+func InjectTrace(ctx context.Context, headers map[string]string) {
+	if tracer := TracerFromContext(ctx); tracer != nil {
+		tracer.Inject(ctx, headers)
+	}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetName(name string)              {}
+func (nopSpan) SetAttributes(fields ...LogField) {}
+func (nopSpan) RecordError(err error)            {}
+func (nopSpan) End()                             {}
+func (nopSpan) TraceID() string                  { return "" }
+func (nopSpan) SpanID() string                   { return "" }
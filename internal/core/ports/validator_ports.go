@@ -1,9 +1,11 @@
 package ports
 
+import "github.com/ctfrancia/maple/internal/application/validation"
+
 type ValidatorServicer interface {
 	Valid() bool
-	AddError(key, message string)
-	Check(ok bool, key, message string)
+	AddError(key string, fe validation.FieldError)
+	Check(ok bool, key string, fe validation.FieldError)
 	In(key string, permittedValues ...string) bool
-	ReturnErrors() map[string]string
+	ReturnErrors() validation.Errors
 }
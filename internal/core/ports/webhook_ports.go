@@ -0,0 +1,63 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	commands "github.com/ctfrancia/maple/internal/application/commands/webhook"
+	"github.com/ctfrancia/maple/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler is for our incomming http requests
+type WebhookHandler interface {
+	CreateWebhookHandler(w http.ResponseWriter, r *http.Request)
+	ListWebhooksHandler(w http.ResponseWriter, r *http.Request)
+	FindWebhookHandler(w http.ResponseWriter, r *http.Request)
+	DeleteWebhookHandler(w http.ResponseWriter, r *http.Request)
+	EnableWebhookHandler(w http.ResponseWriter, r *http.Request)
+	ListDeliveriesHandler(w http.ResponseWriter, r *http.Request)
+	RedeliverHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// WebhookServicer is for our application layer
+type WebhookServicer interface {
+	// CreateWebhook registers the endpoint, the secret is only ever returned here
+	CreateWebhook(ctx context.Context, cmd commands.CreateWebhookCommand) (domain.Webhook, error)
+	ListWebhooks(ctx context.Context, cmd commands.ListWebhooksCommand) ([]domain.Webhook, error)
+	FindWebhook(ctx context.Context, cmd commands.FindWebhookCommand) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, cmd commands.FindWebhookCommand) error
+	// EnableWebhook enables a webhook that was disabled after failing too many times
+	EnableWebhook(ctx context.Context, cmd commands.FindWebhookCommand) (domain.Webhook, error)
+	// ListDeliveries is the delivery log of the webhook, newest first
+	ListDeliveries(ctx context.Context, cmd commands.ListDeliveriesCommand) ([]domain.WebhookDelivery, error)
+	// Redeliver sends the payload of a delivery again as a new delivery and waits for the attempt
+	Redeliver(ctx context.Context, cmd commands.RedeliverCommand) (domain.WebhookDelivery, error)
+}
+
+// WebhookRepository is for our persistence layer
+type WebhookRepository interface {
+	CreateWebhook(webhook domain.Webhook) (domain.Webhook, error)
+	UpdateWebhook(webhook domain.Webhook) (domain.Webhook, error)
+	FindWebhook(id uuid.UUID) (domain.Webhook, error)
+	// ListWebhooks returns the webhooks of the consumer, every webhook when consumerID is empty
+	ListWebhooks(consumerID string) ([]domain.Webhook, error)
+	DeleteWebhook(id uuid.UUID) error
+
+	CreateDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	UpdateDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	FindDelivery(id uuid.UUID) (domain.WebhookDelivery, error)
+	// HasDelivery reports whether the event was already queued for the webhook, redeliveries aside
+	HasDelivery(webhookID, eventID uuid.UUID) (bool, error)
+	// ListDeliveries returns the deliveries of the webhook newest first, limit <= 0 returns all
+	ListDeliveries(webhookID uuid.UUID, limit int) ([]domain.WebhookDelivery, error)
+	// DueDeliveries returns the pending deliveries whose next attempt is not after now, oldest first
+	DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookRepositoryProvider is an interface for providing thread safe access to the webhook repository
+type WebhookRepositoryProvider interface {
+	WriteTx(func(WebhookRepository) error) error
+	ReadTx(func(WebhookRepository) error) error
+}
@@ -5,18 +5,32 @@ package infrastructure
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
+	"syscall"
 	"time"
 
+	"github.com/ctfrancia/maple/internal/core/domain"
 	"github.com/ctfrancia/maple/internal/core/ports"
 )
 
+// ErrNonPublicAddress is returned by the public clients for the hosts that resolve to an address
+// of the server or of its network
+var ErrNonPublicAddress = errors.New("the address is not public")
+
+// publicResponseBytes is as much of a response as the public clients read, the endpoints are
+// not trusted to send a small one
+const publicResponseBytes = 1 << 20
+
 // HTTPClientAdapter is an adapter for the http.Client
 type HTTPClientAdapter struct {
 	client  *http.Client
 	baseURL string
+	maxBody int64 // bytes of the response read, the rest is dropped, 0 reads it all
 }
 
 // NewHTTPClientAdapter creates a new HTTPClientAdapter
@@ -29,6 +43,48 @@ func NewHTTPClientAdapter(baseURL string, timeout time.Duration) *HTTPClientAdap
 	}
 }
 
+// NewPublicHTTPClientAdapter creates an HTTPClientAdapter for the urls given by the api
+// consumers, it only connects to public addresses. The address is checked once resolved, right
+// before connecting, so a name that resolves elsewhere the next time cannot get round it, and
+// neither can a redirect. There is no proxy, it would connect for the client
+func NewPublicHTTPClientAdapter(timeout time.Duration) *HTTPClientAdapter {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}
+
+	return &HTTPClientAdapter{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+		},
+		maxBody: publicResponseBytes,
+	}
+}
+
+// publicOnly is the control of the dialer of the public clients, address is the resolved one
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !domain.PublicAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, addr)
+	}
+	return nil
+}
+
 // Get makes a GET request to the given URL
 func (h *HTTPClientAdapter) Get(ctx context.Context, url string, headers map[string]string) (*ports.HTTPResponse, error) {
 	return h.makeRequest(ctx, http.MethodGet, url, nil, headers)
@@ -49,9 +105,26 @@ func (h *HTTPClientAdapter) Delete(ctx context.Context, url string, headers map[
 	return h.makeRequest(ctx, http.MethodDelete, url, nil, headers)
 }
 
-func (h *HTTPClientAdapter) makeRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) (*ports.HTTPResponse, error) {
+// makeRequest makes the request in a client span, the trace context goes with the request
+// so the server continues the trace
+func (h *HTTPClientAdapter) makeRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) (result *ports.HTTPResponse, err error) {
 	fullURL := h.baseURL + url
 
+	ctx, span := ports.StartSpan(ctx, method, ports.SpanKindClient,
+		ports.String("http.request.method", method),
+		ports.String("url.full", fullURL),
+	)
+	defer func() {
+		if result != nil {
+			span.SetAttributes(ports.Int("http.response.status_code", result.StatusCode))
+			if result.StatusCode >= http.StatusBadRequest {
+				span.RecordError(fmt.Errorf("%d %s", result.StatusCode, http.StatusText(result.StatusCode)))
+			}
+		}
+		span.RecordError(err)
+		span.End()
+	}()
+
 	var bodyReader io.Reader
 	if body != nil {
 		bodyReader = bytes.NewBuffer(body)
@@ -66,6 +139,11 @@ func (h *HTTPClientAdapter) makeRequest(ctx context.Context, method, url string,
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	trace := make(map[string]string, 2)
+	ports.InjectTrace(ctx, trace)
+	for key, value := range trace {
+		req.Header.Set(key, value)
+	}
 
 	resp, err := h.client.Do(req)
 	if err != nil {
@@ -73,7 +151,11 @@ func (h *HTTPClientAdapter) makeRequest(ctx context.Context, method, url string,
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	var respReader io.Reader = resp.Body
+	if h.maxBody > 0 {
+		respReader = io.LimitReader(resp.Body, h.maxBody)
+	}
+	respBody, err := io.ReadAll(respReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
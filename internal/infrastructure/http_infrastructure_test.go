@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -439,3 +440,19 @@ func TestHTTPClientAdapter_Integration(t *testing.T) {
 		assert.Contains(t, string(resp.Body), "test")
 	})
 }
+
+func TestPublicHTTPClientAdapter(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	// the url is checked once resolved, a name of the loopback is refused like its address
+	adapter := NewPublicHTTPClientAdapter(time.Second)
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := adapter.Post(context.Background(), url, []byte(`{}`), nil)
+		assert.ErrorIs(t, err, ErrNonPublicAddress, url)
+	}
+	assert.False(t, reached)
+}